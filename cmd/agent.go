@@ -1,9 +1,28 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
+// Output formats of the queue and history list commands
+const (
+	listFormatCompact = "compact" // One table row per entry
+	listFormatWide    = "wide"    // Every detail of each entry
+)
+
+// listCellWidth caps table cells of compact lists, e.g. long error messages
+const listCellWidth = 48
+
+// validateListFormat checks a --format value of a list command
+func validateListFormat(format string) error {
+	if format != listFormatCompact && format != listFormatWide {
+		return fmt.Errorf("unknown format '%s' (expected %s or %s)", format, listFormatCompact, listFormatWide)
+	}
+	return nil
+}
+
 var agentCmd = &cobra.Command{
 	Use:   "agent",
 	Short: "Manage scheduled agent tasks",
@@ -17,6 +36,7 @@ Examples:
   worktree agent run npm-audit        # Run npm audit task
   worktree agent list                  # List all agent tasks
   worktree agent validate npm-audit    # Validate task definition
+  worktree agent show npm-audit        # Show resolved task definition
   worktree agent status npm-audit      # Show last run time`,
 }
 
@@ -25,5 +45,6 @@ func init() {
 	// - agentRunCmd (agent_run.go)
 	// - agentListCmd (agent_list.go)
 	// - agentValidateCmd (agent_validate.go)
+	// - agentShowCmd (agent_show.go)
 	// - agentScheduleCmd (agent_schedule.go)
 }
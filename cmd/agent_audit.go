@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/agent"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/queue"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// catchUpWorktree is the queue worktree label used for catch-up runs
+const catchUpWorktree = "catch-up"
+
+var (
+	auditDays    int
+	auditGrace   time.Duration
+	auditEnqueue bool
+)
+
+var agentAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report scheduled runs that did not happen",
+	Long: `Compare each task's cron schedule with the execution history and list
+scheduled runs that never happened (machine asleep, daemon not running, ...).
+
+A scheduled run counts as done when the task was executed (by the daemon,
+cron/launchd, the queue or manually) between its scheduled time and the
+next scheduled time. Runs scheduled within --grace of now are not reported,
+since they may still be in progress.
+
+With --enqueue, tasks that set 'catch_up: true' and missed at least one run
+get a single catch-up run added to the queue (unless one is already pending).
+Process it with 'worktree agent queue start'.
+
+Examples:
+  worktree agent audit                  # Missed runs in the last 7 days
+  worktree agent audit --days 30
+  worktree agent audit --enqueue        # Queue catch-up runs for catch_up tasks`,
+	Args: cobra.NoArgs,
+	Run:  runAgentAudit,
+}
+
+func init() {
+	agentAuditCmd.Flags().IntVar(&auditDays, "days", 7, "number of days to look back")
+	agentAuditCmd.Flags().DurationVar(&auditGrace, "grace", time.Hour, "ignore runs scheduled this recently")
+	agentAuditCmd.Flags().BoolVar(&auditEnqueue, "enqueue", false, "queue a catch-up run for tasks with catch_up: true")
+
+	agentCmd.AddCommand(agentAuditCmd)
+}
+
+func runAgentAudit(cmd *cobra.Command, args []string) {
+	if auditDays < 1 {
+		checkError(fmt.Errorf("--days must be at least 1"))
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	if len(workCfg.ScheduledAgents) == 0 {
+		ui.Warning("No scheduled agents configured in .worktree.yml")
+		return
+	}
+
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -auditDays)
+	audits := agent.AuditSchedule(workCfg.ScheduledAgents, h.Query("", "", 0), since, now, auditGrace)
+
+	ui.Section(fmt.Sprintf("Schedule Audit (last %d days)", auditDays))
+
+	totalMissed := 0
+	var catchUp []string
+	for _, audit := range audits {
+		if audit.Err != nil {
+			ui.Error(fmt.Sprintf("%s: %v", audit.TaskName, audit.Err))
+			continue
+		}
+
+		lastRun := "never"
+		if audit.LastRun != nil {
+			lastRun = audit.LastRun.Format("2006-01-02 15:04")
+		}
+
+		if len(audit.Missed) == 0 {
+			ui.CheckMark(fmt.Sprintf("%s: %d/%d scheduled runs done (last run: %s)",
+				audit.TaskName, audit.Expected, audit.Expected, lastRun))
+			continue
+		}
+
+		totalMissed += len(audit.Missed)
+		ui.Warning(fmt.Sprintf("%s: missed %d of %d scheduled runs (%s, last run: %s)",
+			audit.TaskName, len(audit.Missed), audit.Expected, audit.Schedule, lastRun))
+		for _, missed := range audit.Missed {
+			fmt.Printf("    - %s\n", missed.Format("2006-01-02 15:04 Mon"))
+		}
+		if audit.CatchUp {
+			catchUp = append(catchUp, audit.TaskName)
+		}
+	}
+
+	ui.NewLine()
+	if totalMissed == 0 {
+		ui.Success("No missed runs")
+		return
+	}
+
+	if !auditEnqueue {
+		if len(catchUp) > 0 {
+			ui.Info("Run with --enqueue to queue catch-up runs for tasks with catch_up: true")
+		}
+		return
+	}
+
+	if len(catchUp) == 0 {
+		ui.Info("No missed task has catch_up: true; nothing queued")
+		return
+	}
+
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	for _, taskName := range catchUp {
+		if hasPendingTask(q, taskName) {
+			ui.Info(fmt.Sprintf("%s: catch-up run already pending", taskName))
+			continue
+		}
+		task, err := q.Add(taskName, catchUpWorktree)
+		checkError(err)
+		ui.CheckMark(fmt.Sprintf("%s: catch-up run queued (%s)", taskName, task.ID[:8]))
+	}
+	ui.Info("Run 'worktree agent queue start --continuous' to process the queue")
+}
+
+// hasPendingTask reports whether agentName already has a pending task in the queue
+func hasPendingTask(q *queue.Queue, agentName string) bool {
+	for _, task := range q.List(queue.StatusPending) {
+		if task.AgentName == agentName {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/braunmar/worktree/pkg/agent"
 	"github.com/braunmar/worktree/pkg/config"
@@ -11,7 +12,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var daemonForeground bool
+var (
+	daemonForeground bool
+	daemonQueue      bool
+)
 
 var agentDaemonCmd = &cobra.Command{
 	Use:   "daemon",
@@ -19,12 +23,26 @@ var agentDaemonCmd = &cobra.Command{
 	Long: `Start the agent scheduler daemon to run scheduled tasks automatically.
 
 The daemon reads all scheduled_agents from .worktree.yml and runs them
-according to their cron schedules. The daemon runs in the foreground by
+according to their cron schedules (standard 5-field cron expressions or
+descriptors like @daily and @every 2h). The daemon runs in the foreground by
 default, but can be registered as a system service.
 
+Runs of a task never overlap: when a task's schedule fires while its previous
+run is still in progress, the new run is skipped and recorded as skipped in
+the execution history. The same happens when a concurrency limit holds the
+run back: another process running the task (max_concurrent), the
+max_concurrent_agents limit, or another run outside an isolated worktree
+using the project root. Every run is recorded there with trigger "schedule".
+
+With --queue, scheduled runs are added to the task queue instead and the
+daemon works the queue off one task at a time, so no two tasks run at once.
+Queued tasks wait for runs started elsewhere instead of being skipped.
+Tasks added with 'worktree agent queue add' are picked up as well.
+
 Examples:
   worktree agent daemon                 # Run in foreground
   worktree agent daemon --foreground    # Same as above
+  worktree agent daemon --queue         # Serialize all runs through the queue
 
 Logs are written to ~/logs/worktree-scheduler.log
 
@@ -53,18 +71,29 @@ func runAgentDaemon(cmd *cobra.Command, args []string) {
 	// Create scheduler
 	scheduler, err := agent.NewScheduler(cfg, workCfg)
 	checkError(err)
+	scheduler.SetQueue(daemonQueue)
 
 	// Show startup message
 	ui.Section("Starting Agent Scheduler Daemon")
 	fmt.Printf("  Project: %s\n", cfg.ProjectRoot)
 	fmt.Printf("  Agents: %d configured\n", len(workCfg.ScheduledAgents))
 	fmt.Printf("  Logs: ~/logs/worktree-scheduler.log\n")
+	if daemonQueue {
+		fmt.Printf("  Mode: queue (runs are enqueued and run one at a time)\n")
+	}
 	fmt.Println()
 
 	// List scheduled agents
-	for taskName, task := range workCfg.ScheduledAgents {
+	now := time.Now()
+	for _, taskName := range sortedKeys(workCfg.ScheduledAgents) {
+		task := workCfg.ScheduledAgents[taskName]
 		fmt.Printf("  • %s (%s)\n", task.Name, task.Schedule)
 		fmt.Printf("    Key: %s\n", taskName)
+		if next, err := agent.NextRun(task.Schedule, now); err != nil {
+			ui.Warning(fmt.Sprintf("    Not scheduled: %v", err))
+		} else {
+			fmt.Printf("    Next run: %s\n", next.Format("2006-01-02 15:04"))
+		}
 	}
 
 	fmt.Println()
@@ -80,5 +109,6 @@ func runAgentDaemon(cmd *cobra.Command, args []string) {
 
 func init() {
 	agentDaemonCmd.Flags().BoolVar(&daemonForeground, "foreground", true, "Run in foreground (default)")
+	agentDaemonCmd.Flags().BoolVar(&daemonQueue, "queue", false, "Enqueue scheduled runs and work the queue off one task at a time")
 	agentCmd.AddCommand(agentDaemonCmd)
 }
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/braunmar/worktree/pkg/agent"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+var gsdResetWorktree string
+
+var agentGSDCmd = &cobra.Command{
+	Use:   "gsd",
+	Short: "Inspect GSD milestone progress",
+	Long: `Inspect and reset the progress of agent tasks that use the GSD workflow
+(gsd.enabled: true).
+
+A GSD run executes the milestone's phases one by one and records each
+phase's outcome under worktrees/.gsd. When a phase or its gates fail, the
+next run of the task resumes at that phase instead of starting over.`,
+}
+
+var gsdStatusCmd = &cobra.Command{
+	Use:   "status [task-name]",
+	Short: "Show GSD milestone progress",
+	Long: `Show the milestone progress of GSD agent tasks: each phase's status,
+start and finish times, and the error or failed gates of a failed phase.
+
+Without a task name, all recorded milestones are shown.
+
+Examples:
+  worktree agent gsd status
+  worktree agent gsd status feature-planner`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runGSDStatus,
+}
+
+var gsdResetCmd = &cobra.Command{
+	Use:   "reset <task-name>",
+	Short: "Discard GSD milestone progress so the next run starts over",
+	Long: `Discard the recorded milestone progress of a GSD agent task; its next
+run starts with the first phase and a new milestone.
+
+Examples:
+  worktree agent gsd reset feature-planner
+  worktree agent gsd reset feature-planner --worktree feature-auth`,
+	Args: cobra.ExactArgs(1),
+	Run:  runGSDReset,
+}
+
+func init() {
+	gsdResetCmd.Flags().StringVar(&gsdResetWorktree, "worktree", "", "Reset only the progress of runs queued for this feature")
+
+	agentGSDCmd.AddCommand(gsdStatusCmd)
+	agentGSDCmd.AddCommand(gsdResetCmd)
+	agentCmd.AddCommand(agentGSDCmd)
+}
+
+func runGSDStatus(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	states, err := agent.ListGSDStates(cfg.WorktreeDir)
+	checkError(err)
+
+	if len(args) == 1 {
+		states = filterGSDStates(states, args[0], "")
+		if len(states) == 0 {
+			ui.Info(fmt.Sprintf("No GSD progress recorded for '%s'", args[0]))
+			return
+		}
+	}
+	if len(states) == 0 {
+		ui.Info("No GSD progress recorded")
+		return
+	}
+
+	for i, state := range states {
+		if i > 0 {
+			fmt.Println()
+		}
+		printGSDState(state)
+	}
+}
+
+func printGSDState(state *agent.GSDState) {
+	fmt.Println(ui.Bold("GSD: " + state.Label()))
+	fmt.Printf("  Milestone: %s\n", orNone(state.Milestone))
+	fmt.Printf("  Status: %s (%d/%d phases completed)\n", state.Status(), state.Completed(), len(state.Phases))
+	fmt.Printf("  Updated: %s\n", state.UpdatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Println()
+
+	table := ui.NewTable("#", "PHASE", "STATUS", "STARTED", "FINISHED")
+	for i, phase := range state.Phases {
+		table.AddRow(fmt.Sprintf("%d/%d", i+1, len(state.Phases)), phase.Name, phase.Status,
+			formatGSDTime(phase.StartedAt), formatGSDTime(phase.CompletedAt))
+	}
+	table.Print()
+
+	for _, phase := range state.Phases {
+		if phase.Status != agent.GSDPhaseFailed {
+			continue
+		}
+		fmt.Println()
+		ui.Error(fmt.Sprintf("Phase %s failed: %s", phase.Name, phase.Error))
+		if len(phase.FailedGates) > 0 {
+			fmt.Printf("  Failed gates: %s\n", strings.Join(phase.FailedGates, ", "))
+		}
+		fmt.Printf("  The next run of %s resumes at this phase\n", state.Agent)
+	}
+}
+
+func formatGSDTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func runGSDReset(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	states, err := agent.ListGSDStates(cfg.WorktreeDir)
+	checkError(err)
+
+	states = filterGSDStates(states, args[0], gsdResetWorktree)
+	if len(states) == 0 {
+		ui.Info(fmt.Sprintf("No GSD progress recorded for '%s'", args[0]))
+		return
+	}
+
+	for _, state := range states {
+		if err := os.Remove(state.Path()); err != nil && !os.IsNotExist(err) {
+			checkError(fmt.Errorf("failed to remove GSD state: %w", err))
+		}
+		ui.CheckMark(fmt.Sprintf("Reset GSD progress of %s (%d/%d phases were completed)",
+			state.Label(), state.Completed(), len(state.Phases)))
+	}
+}
+
+// filterGSDStates keeps the states of an agent, optionally of one worktree only
+func filterGSDStates(states []*agent.GSDState, agentName, worktree string) []*agent.GSDState {
+	var filtered []*agent.GSDState
+	for _, state := range states {
+		if state.Agent != agentName {
+			continue
+		}
+		if worktree != "" && state.Worktree != worktree {
+			continue
+		}
+		filtered = append(filtered, state)
+	}
+	return filtered
+}
@@ -1,13 +1,19 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/braunmar/worktree/pkg/agent"
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/queue"
 	"github.com/braunmar/worktree/pkg/ui"
 )
 
@@ -15,6 +21,13 @@ var (
 	historyAgent  string
 	historyStatus string
 	historyLimit  int
+	historyFormat string
+	rerunNow      bool
+	rerunCurrent  bool
+	historyJSON   bool
+	exportFormat  string
+	exportSince   string
+	exportOutput  string
 )
 
 var agentHistoryCmd = &cobra.Command{
@@ -30,11 +43,85 @@ var historyListCmd = &cobra.Command{
 
 Filter by agent name or status. By default, shows last 20 executions.
 
+Formats:
+  compact  one table row per execution (default)
+  wide     every detail of each execution
+
 Example:
   worktree agent history list
   worktree agent history list --agent npm-audit
-  worktree agent history list --status failed --limit 10`,
-	Run: runHistoryList,
+  worktree agent history list --status failed --limit 10 --format wide`,
+	Args: cobra.NoArgs,
+	Run:  runHistoryList,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show <execution-id>",
+	Short: "Show one execution and the task definition it ran",
+	Long: `Show the details of one execution together with the task definition
+exactly as it was executed, even if .worktree.yml has changed since.
+
+Each step is listed with its status, duration, attempts, error and the tail
+of its output (the last 2000 bytes; skill steps run interactively and keep
+none).
+
+Each execution also writes a structured JSON log with timestamped events
+for its steps, safety gates and git operations (commit, push, pull/merge
+request, commit status) to worktrees/.agent-logs/<id>.json. It is shown as a
+timeline; --json prints the log itself, e.g. for jq.
+
+The execution ID may be abbreviated to any unique prefix (as printed by
+'history list'). Runs recorded before snapshots were introduced have no
+stored definition.
+
+Example:
+  worktree agent history show 3f2a9c1b
+  worktree agent history show 3f2a9c1b --json | jq '.events[] | select(.status == "failed")'`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHistoryShow,
+}
+
+var historyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export executions as CSV or JSON",
+	Long: `Export agent executions oldest first, e.g. to feed dashboards.
+
+Formats:
+  csv   one row per execution: id, agent, status, trigger, worktree, start and
+        end time, duration, steps executed and failed, error, commits, PR/MR
+        and task snapshot (default)
+  json  an array of the full execution records, steps included
+
+--since keeps executions started within the given age (e.g. 30d, 2w, 36h).
+The history keeps what the history: section of .worktree.yml retains
+(max_records, default 1000; max_age, default no limit).
+
+Example:
+  worktree agent history export --since 30d > executions.csv
+  worktree agent history export --format json --agent npm-audit --output audit.json`,
+	Args: cobra.NoArgs,
+	Run:  runHistoryExport,
+}
+
+var historyRerunCmd = &cobra.Command{
+	Use:   "rerun <execution-id>",
+	Short: "Run an execution again with the same agent, worktree and definition",
+	Long: `Queue an execution again: the same agent task on the same worktree,
+with the task definition exactly as it was executed (its snapshot), so a
+transient failure can be retried without rebuilding the invocation.
+
+--now runs it immediately instead of queueing it. --current uses the task
+definition currently in .worktree.yml; runs recorded before snapshots were
+introduced always do.
+
+The execution ID may be abbreviated to any unique prefix.
+
+Example:
+  worktree agent history rerun 3f2a9c1b          # Queue it
+  worktree agent history rerun 3f2a9c1b --now    # Run it right away
+  worktree agent history rerun 3f2a9c1b --current`,
+	Args: cobra.ExactArgs(1),
+	Run:  runHistoryRerun,
 }
 
 var historyStatsCmd = &cobra.Command{
@@ -64,9 +151,21 @@ func init() {
 	historyListCmd.Flags().StringVar(&historyAgent, "agent", "", "Filter by agent name")
 	historyListCmd.Flags().StringVar(&historyStatus, "status", "", "Filter by status (completed, failed)")
 	historyListCmd.Flags().IntVar(&historyLimit, "limit", 20, "Limit number of results")
+	historyListCmd.Flags().StringVar(&historyFormat, "format", listFormatCompact, "Output format: compact or wide")
+	historyShowCmd.Flags().BoolVar(&historyJSON, "json", false, "Print the execution's structured JSON log")
+	historyExportCmd.Flags().StringVar(&exportFormat, "format", history.ExportCSV, "Output format: csv or json")
+	historyExportCmd.Flags().StringVar(&exportSince, "since", "", "Only executions started within this age (e.g. 30d, 2w, 36h)")
+	historyExportCmd.Flags().StringVar(&historyAgent, "agent", "", "Filter by agent name")
+	historyExportCmd.Flags().StringVar(&historyStatus, "status", "", "Filter by status (completed, failed, skipped)")
+	historyExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write to this file instead of stdout")
+	historyRerunCmd.Flags().BoolVar(&rerunNow, "now", false, "Run immediately instead of adding to the queue")
+	historyRerunCmd.Flags().BoolVar(&rerunCurrent, "current", false, "Use the current task definition instead of the recorded snapshot")
 
 	// Register subcommands
 	agentHistoryCmd.AddCommand(historyListCmd)
+	agentHistoryCmd.AddCommand(historyShowCmd)
+	agentHistoryCmd.AddCommand(historyExportCmd)
+	agentHistoryCmd.AddCommand(historyRerunCmd)
 	agentHistoryCmd.AddCommand(historyStatsCmd)
 	agentHistoryCmd.AddCommand(historyClearCmd)
 
@@ -75,12 +174,17 @@ func init() {
 }
 
 func runHistoryList(cmd *cobra.Command, args []string) {
+	checkError(validateListFormat(historyFormat))
+
 	// Load config
 	cfg, err := config.New()
 	checkError(err)
 
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
 	// Load history
-	h, err := history.Load(cfg.WorktreeDir)
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Query history
@@ -92,20 +196,29 @@ func runHistoryList(cmd *cobra.Command, args []string) {
 	}
 
 	ui.Section(fmt.Sprintf("Execution History (%d records)", len(records)))
+	if historyFormat == listFormatCompact {
+		printHistoryCompact(records)
+		return
+	}
 	fmt.Println()
 
 	for _, record := range records {
 		// Status emoji
 		var emoji string
-		if record.Status == "completed" {
+		switch record.Status {
+		case "completed":
 			emoji = "✅"
-		} else {
+		case history.RunSkipped:
+			emoji = "⏭️"
+		default:
 			emoji = "❌"
 		}
 
 		fmt.Printf("%s %s\n", emoji, record.AgentName)
 		fmt.Printf("   ID: %s\n", record.ID[:8]+"...")
-		fmt.Printf("   Worktree: %s\n", record.Worktree)
+		if record.Worktree != "" {
+			fmt.Printf("   Worktree: %s\n", record.Worktree)
+		}
 		fmt.Printf("   Started: %s\n", record.StartTime.Format("2006-01-02 15:04:05"))
 		fmt.Printf("   Duration: %s\n", time.Duration(record.Duration)*time.Millisecond)
 		fmt.Printf("   Status: %s\n", record.Status)
@@ -119,20 +232,254 @@ func runHistoryList(cmd *cobra.Command, args []string) {
 		}
 
 		if record.PRUrl != "" {
-			fmt.Printf("   PR: %s\n", record.PRUrl)
+			fmt.Printf("   PR/MR: %s\n", record.PRUrl)
 		}
 
 		fmt.Println()
 	}
 }
 
+// printHistoryCompact prints one table row per execution
+func printHistoryCompact(records []history.ExecutionRecord) {
+	table := ui.NewTable("ID", "STATUS", "AGENT", "WORKTREE", "STARTED", "DURATION", "ERROR").Truncate(listCellWidth)
+	for _, record := range records {
+		worktree := record.Worktree
+		if worktree == "" {
+			worktree = "-"
+		}
+		table.AddRow(queue.ShortID(record.ID), record.Status, record.AgentName, worktree,
+			record.StartTime.Format("2006-01-02 15:04"), (time.Duration(record.Duration) * time.Millisecond).String(), record.Error)
+	}
+	table.Print()
+}
+
+func runHistoryShow(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	record, err := h.Find(args[0])
+	checkError(err)
+
+	if historyJSON {
+		log, err := h.LoadLog(record)
+		checkError(err)
+		data, err := json.MarshalIndent(log, "", "  ")
+		checkError(err)
+		fmt.Println(string(data))
+		return
+	}
+
+	ui.Section(fmt.Sprintf("Execution %s", record.ID))
+	fmt.Printf("   Agent: %s\n", record.AgentName)
+	if record.Worktree != "" {
+		fmt.Printf("   Worktree: %s\n", record.Worktree)
+	}
+	fmt.Printf("   Started: %s\n", record.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Duration: %s\n", time.Duration(record.Duration)*time.Millisecond)
+	fmt.Printf("   Status: %s\n", record.Status)
+	if record.Trigger != "" {
+		fmt.Printf("   Trigger: %s\n", record.Trigger)
+	}
+	if record.StepsExecuted > 0 {
+		fmt.Printf("   Steps executed: %d\n", record.StepsExecuted)
+	}
+	if record.Error != "" {
+		fmt.Printf("   Error: %s\n", record.Error)
+	}
+	for _, commit := range record.Commits {
+		fmt.Printf("   Commit: %s\n", commit)
+	}
+	if record.PRUrl != "" {
+		fmt.Printf("   PR/MR: %s\n", record.PRUrl)
+	}
+	if record.Log != "" {
+		fmt.Printf("   Log: %s\n", h.LogPath(record))
+	}
+	fmt.Println()
+
+	if len(record.Steps) > 0 {
+		showExecutionSteps(record)
+	}
+	if record.Log != "" {
+		showExecutionLog(h, record)
+	}
+
+	if record.TaskHash == "" {
+		ui.Info("No task definition recorded for this execution (recorded before snapshots were kept)")
+		return
+	}
+
+	definition, err := h.TaskSnapshot(record.TaskHash)
+	if err != nil {
+		ui.Warning(err.Error())
+		return
+	}
+
+	ui.Section(fmt.Sprintf("Task definition (snapshot %.12s)", record.TaskHash))
+	for _, line := range strings.Split(strings.TrimRight(string(definition), "\n"), "\n") {
+		fmt.Printf("   %s\n", line)
+	}
+	fmt.Println()
+
+	// Point out drift from the current configuration, which agent run and rerun --current use
+	current, ok := workCfg.ScheduledAgents[record.AgentName]
+	if !ok {
+		ui.Info(fmt.Sprintf("Task '%s' no longer exists in .worktree.yml", record.AgentName))
+		return
+	}
+	if currentDef, err := agent.TaskDefinition(current); err == nil && !bytes.Equal(currentDef, definition) {
+		ui.Warning("The task definition in .worktree.yml has changed since this execution")
+	}
+}
+
+// showExecutionSteps prints the outcome of each step with the tail of its output
+func showExecutionSteps(record *history.ExecutionRecord) {
+	ui.Section("Steps")
+	for i, step := range record.Steps {
+		line := fmt.Sprintf("   %d. %s (%s): %s, %s", i+1, step.Name, step.Type, step.Status, time.Duration(step.DurationMS)*time.Millisecond)
+		if step.Attempts > 1 {
+			line += fmt.Sprintf(", %d attempts", step.Attempts)
+		}
+		if step.Continued {
+			line += ", continued"
+		}
+		fmt.Println(line)
+		if step.Error != "" {
+			fmt.Printf("      Error: %s\n", step.Error)
+		}
+		if output := strings.TrimRight(step.Output, "\n"); output != "" {
+			fmt.Println("      Output:")
+			for _, outputLine := range strings.Split(output, "\n") {
+				fmt.Printf("        %s\n", outputLine)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// showExecutionLog prints the events of an execution's structured log as a timeline
+func showExecutionLog(h *history.History, record *history.ExecutionRecord) {
+	log, err := h.LoadLog(record)
+	if err != nil {
+		ui.Warning(err.Error())
+		return
+	}
+
+	ui.Section("Timeline")
+	for _, event := range log.Events {
+		line := fmt.Sprintf("   %s  %-4s  %s: %s", event.Time.Format("15:04:05"), event.Phase, event.Name, event.Status)
+		if event.DurationMS > 0 {
+			line += fmt.Sprintf(" (%s)", time.Duration(event.DurationMS)*time.Millisecond)
+		}
+		if url := event.Details["url"]; url != "" {
+			line += " " + url
+		}
+		fmt.Println(line)
+		if event.Error != "" && event.Phase != history.PhaseRun {
+			fmt.Printf("             %s\n", event.Error)
+		}
+	}
+	fmt.Println()
+}
+
+func runHistoryExport(cmd *cobra.Command, args []string) {
+	if exportFormat != history.ExportCSV && exportFormat != history.ExportJSON {
+		checkError(fmt.Errorf("unknown format '%s' (expected csv or json)", exportFormat))
+	}
+	var since time.Time
+	if exportSince != "" {
+		age, err := config.ParseAge(exportSince)
+		checkError(err)
+		since = time.Now().Add(-age)
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	records := h.Since(historyAgent, historyStatus, since)
+	if exportOutput == "" {
+		checkError(history.Export(os.Stdout, exportFormat, records))
+		return
+	}
+
+	f, err := os.Create(exportOutput)
+	checkError(err)
+	err = history.Export(f, exportFormat, records)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	checkError(err)
+	ui.Success(fmt.Sprintf("Exported %d executions to %s", len(records), exportOutput))
+}
+
+func runHistoryRerun(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	record, err := h.Find(args[0])
+	checkError(err)
+
+	task, taskHash, err := agent.RerunTask(h, workCfg, record, rerunCurrent)
+	checkError(err)
+
+	definition := "current definition"
+	if taskHash != "" {
+		definition = fmt.Sprintf("snapshot %.12s", taskHash)
+	}
+
+	if !rerunNow {
+		q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
+		checkError(err)
+
+		queued, err := q.AddSnapshot(record.AgentName, record.Worktree, taskHash)
+		checkError(err)
+
+		ui.Success(fmt.Sprintf("Rerun of %s queued", queue.ShortID(record.ID)))
+		fmt.Printf("  ID: %s\n", queued.ID)
+		fmt.Printf("  Agent: %s\n", queued.AgentName)
+		fmt.Printf("  Worktree: %s\n", queued.Worktree)
+		fmt.Printf("  Definition: %s\n", definition)
+		fmt.Println()
+		ui.Info("Process it with: worktree agent queue start")
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Rerunning %s (%s)", queue.ShortID(record.ID), definition))
+	executor := agent.NewExecutor(cfg, workCfg, task, record.AgentName)
+	executor.SetWorktree(record.Worktree)
+	if err := executor.Run(); err != nil {
+		checkError(fmt.Errorf("agent task failed: %w", err))
+	}
+}
+
 func runHistoryStats(cmd *cobra.Command, args []string) {
 	// Load config
 	cfg, err := config.New()
 	checkError(err)
 
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
 	// Load history
-	h, err := history.Load(cfg.WorktreeDir)
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Get statistics
@@ -147,7 +494,7 @@ func runHistoryStats(cmd *cobra.Command, args []string) {
 	fmt.Println()
 
 	// Overall stats
-	fmt.Println("📊 Overall")
+	ui.Println("📊 Overall")
 	fmt.Printf("   Total executions: %d\n", stats.TotalExecutions)
 	fmt.Printf("   Success rate: %.1f%%\n", stats.SuccessRate)
 	fmt.Printf("   Average duration: %s\n", stats.AverageDuration)
@@ -155,7 +502,7 @@ func runHistoryStats(cmd *cobra.Command, args []string) {
 
 	// Per-agent stats
 	if len(stats.ByAgent) > 0 {
-		fmt.Println("🤖 By Agent")
+		ui.Println("🤖 By Agent")
 		fmt.Println()
 
 		for agentName, agentStats := range stats.ByAgent {
@@ -174,8 +521,11 @@ func runHistoryClear(cmd *cobra.Command, args []string) {
 	cfg, err := config.New()
 	checkError(err)
 
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
 	// Load history
-	h, err := history.Load(cfg.WorktreeDir)
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Count before clear
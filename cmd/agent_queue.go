@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -14,8 +15,17 @@ import (
 
 var (
 	queueContinuous bool
+	queueAgent      string
+	queueWorktree   string
+	queueStatus     string
+	queueFormat     string
+	queueLimit      int
+	queueNoFollow   bool
 )
 
+// queueFollowInterval is how often "queue show" checks a running task for new output
+const queueFollowInterval = 500 * time.Millisecond
+
 var agentQueueCmd = &cobra.Command{
 	Use:   "queue",
 	Short: "Manage agent task queue",
@@ -40,14 +50,25 @@ Example:
 var queueListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List queued tasks",
-	Long: `List all tasks in the queue with their status.
+	Long: `List all tasks in the queue with their status, running tasks first,
+then pending, completed and failed ones.
 
 Status values:
   - pending: Waiting to be executed
   - running: Currently executing
   - completed: Successfully finished
-  - failed: Execution failed`,
-	Run: runQueueList,
+  - failed: Execution failed
+
+Formats:
+  compact  one table row per task (default)
+  wide     every detail of each task, grouped by status
+
+Example:
+  worktree agent queue list
+  worktree agent queue list --status failed --format wide
+  worktree agent queue list --status pending --limit 5`,
+	Args: cobra.NoArgs,
+	Run:  runQueueList,
 }
 
 var queueStartCmd = &cobra.Command{
@@ -65,18 +86,41 @@ Example:
 }
 
 var queueRemoveCmd = &cobra.Command{
-	Use:   "remove <task-id>",
+	Use:   "remove [task-id]",
 	Short: "Remove task from queue",
-	Long: `Remove a specific task from the queue by its ID.
+	Long: `Remove a task from the queue by its ID, or every task of an agent on a
+worktree.
 
-Get the task ID from 'worktree agent queue list'.
+The ID may be shortened to any unambiguous prefix, such as the 8 characters
+shown by 'worktree agent queue list'. A prefix matching several tasks is an
+error.
 
 Example:
-  worktree agent queue remove abc123-def456-...`,
-	Args: cobra.ExactArgs(1),
+  worktree agent queue remove 3f2a9c1e
+  worktree agent queue remove --agent npm-audit --worktree feature-x --status pending`,
+	Args: cobra.MaximumNArgs(1),
 	Run:  runQueueRemove,
 }
 
+var queueShowCmd = &cobra.Command{
+	Use:   "show <task-id>",
+	Short: "Show a queued task",
+	Long: `Show all details of a task, including its full ID.
+
+The ID may be shortened to any unambiguous prefix.
+
+Queued runs write their output to worktrees/.queue-logs/<id>.log. While the
+task is running, its output so far is printed and then followed live until
+the task completes (Ctrl+C stops following, the task keeps running). Use
+--no-follow to print the output so far and return.
+
+Example:
+  worktree agent queue show 3f2a9c1e
+  worktree agent queue show 3f2a9c1e --no-follow`,
+	Args: cobra.ExactArgs(1),
+	Run:  runQueueShow,
+}
+
 var queueClearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear completed and failed tasks",
@@ -91,12 +135,20 @@ Example:
 func init() {
 	// Add flags
 	queueStartCmd.Flags().BoolVar(&queueContinuous, "continuous", false, "Process all pending tasks sequentially")
+	queueRemoveCmd.Flags().StringVar(&queueAgent, "agent", "", "Remove the tasks of this agent (requires --worktree)")
+	queueRemoveCmd.Flags().StringVar(&queueWorktree, "worktree", "", "Remove the tasks on this worktree (requires --agent)")
+	queueRemoveCmd.Flags().StringVar(&queueStatus, "status", "", "Only remove tasks with this status (pending, running, completed, failed)")
+	queueListCmd.Flags().StringVar(&queueFormat, "format", listFormatCompact, "Output format: compact or wide")
+	queueListCmd.Flags().IntVar(&queueLimit, "limit", 0, "Show at most this many tasks (0 = all)")
+	queueListCmd.Flags().StringVar(&queueStatus, "status", "", "Only list tasks with this status (pending, running, completed, failed)")
+	queueShowCmd.Flags().BoolVar(&queueNoFollow, "no-follow", false, "Print the output of a running task so far without following it")
 
 	// Register subcommands
 	agentQueueCmd.AddCommand(queueAddCmd)
 	agentQueueCmd.AddCommand(queueListCmd)
 	agentQueueCmd.AddCommand(queueStartCmd)
 	agentQueueCmd.AddCommand(queueRemoveCmd)
+	agentQueueCmd.AddCommand(queueShowCmd)
 	agentQueueCmd.AddCommand(queueClearCmd)
 
 	// Register queue command under agent
@@ -120,7 +172,7 @@ func runQueueAdd(cmd *cobra.Command, args []string) {
 	}
 
 	// Load queue
-	q, err := queue.Load(cfg.WorktreeDir)
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Add task
@@ -140,90 +192,134 @@ func runQueueAdd(cmd *cobra.Command, args []string) {
 }
 
 func runQueueList(cmd *cobra.Command, args []string) {
+	checkError(validateListFormat(queueFormat))
+	status := queue.TaskStatus(queueStatus)
+	switch status {
+	case "", queue.StatusPending, queue.StatusRunning, queue.StatusCompleted, queue.StatusFailed:
+	default:
+		checkError(fmt.Errorf("unknown status '%s' (expected pending, running, completed or failed)", queueStatus))
+	}
+
 	// Load config
 	cfg, err := config.New()
 	checkError(err)
 
-	// Load queue
-	q, err := queue.Load(cfg.WorktreeDir)
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
 
-	tasks := q.List("")
+	// Load queue
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
 
-	if len(tasks) == 0 {
+	all := q.List("")
+	if len(all) == 0 {
 		ui.Info("Queue is empty")
 		return
 	}
 
-	ui.Section("Task Queue")
-	fmt.Println()
-
-	// Group by status
-	statusGroups := map[queue.TaskStatus][]queue.QueuedTask{
-		queue.StatusPending:   {},
-		queue.StatusRunning:   {},
-		queue.StatusCompleted: {},
-		queue.StatusFailed:    {},
+	// Running tasks first, then pending, completed and failed, each in queue order
+	var tasks []queue.QueuedTask
+	for _, s := range []queue.TaskStatus{queue.StatusRunning, queue.StatusPending, queue.StatusCompleted, queue.StatusFailed} {
+		if status == "" || status == s {
+			tasks = append(tasks, q.List(s)...)
+		}
 	}
-
-	for _, task := range tasks {
-		statusGroups[task.Status] = append(statusGroups[task.Status], task)
+	matching := len(tasks)
+	if queueLimit > 0 && len(tasks) > queueLimit {
+		tasks = tasks[:queueLimit]
 	}
 
-	// Display each group
-	for _, status := range []queue.TaskStatus{queue.StatusRunning, queue.StatusPending, queue.StatusCompleted, queue.StatusFailed} {
-		groupTasks := statusGroups[status]
-		if len(groupTasks) == 0 {
-			continue
-		}
-
-		// Status header with emoji
-		var emoji string
-		switch status {
-		case queue.StatusPending:
-			emoji = "⏸️"
-		case queue.StatusRunning:
-			emoji = "▶️"
-		case queue.StatusCompleted:
-			emoji = "✅"
-		case queue.StatusFailed:
-			emoji = "❌"
+	if len(tasks) == 0 {
+		ui.Info(fmt.Sprintf("No %s tasks in queue", status))
+	} else {
+		ui.Section("Task Queue")
+		if queueFormat == listFormatWide {
+			fmt.Println()
+			printQueueWide(tasks)
+		} else {
+			printQueueCompact(tasks)
+			fmt.Println()
 		}
+	}
+	if len(tasks) < matching {
+		ui.Info(fmt.Sprintf("Showing %d of %d tasks (--limit)", len(tasks), matching))
+	}
 
-		fmt.Printf("%s %s (%d)\n", emoji, status, len(groupTasks))
-		fmt.Println()
+	// Summary
+	counts := make(map[queue.TaskStatus]int)
+	for _, task := range all {
+		counts[task.Status]++
+	}
+	ui.Separator(53)
+	fmt.Printf("Total: %d tasks\n", len(all))
+	fmt.Printf("  Pending: %d\n", counts[queue.StatusPending])
+	fmt.Printf("  Running: %d\n", counts[queue.StatusRunning])
+	fmt.Printf("  Completed: %d\n", counts[queue.StatusCompleted])
+	fmt.Printf("  Failed: %d\n", counts[queue.StatusFailed])
+	ui.Separator(53)
+}
 
-		for _, task := range groupTasks {
-			fmt.Printf("  ID: %s\n", task.ID[:8]+"...")
-			fmt.Printf("  Agent: %s\n", task.AgentName)
-			fmt.Printf("  Worktree: %s\n", task.Worktree)
-			fmt.Printf("  Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
+// printQueueCompact prints one table row per task
+func printQueueCompact(tasks []queue.QueuedTask) {
+	table := ui.NewTable("ID", "STATUS", "AGENT", "WORKTREE", "CREATED", "DURATION", "ERROR").Truncate(listCellWidth)
+	for _, task := range tasks {
+		duration := "-"
+		if task.CompletedAt != nil {
+			duration = (time.Duration(task.Duration) * time.Millisecond).String()
+		}
+		table.AddRow(queue.ShortID(task.ID), string(task.Status), task.AgentName, task.Worktree,
+			task.CreatedAt.Format("2006-01-02 15:04"), duration, task.Error)
+	}
+	table.Print()
+}
 
-			if task.StartedAt != nil {
-				fmt.Printf("  Started: %s\n", task.StartedAt.Format("2006-01-02 15:04:05"))
+// printQueueWide prints every detail of each task, grouped by status
+func printQueueWide(tasks []queue.QueuedTask) {
+	for i, task := range tasks {
+		if i == 0 || tasks[i-1].Status != task.Status {
+			// Status header with emoji
+			var emoji string
+			switch task.Status {
+			case queue.StatusPending:
+				emoji = "⏸️"
+			case queue.StatusRunning:
+				emoji = "▶️"
+			case queue.StatusCompleted:
+				emoji = "✅"
+			case queue.StatusFailed:
+				emoji = "❌"
 			}
 
-			if task.CompletedAt != nil {
-				fmt.Printf("  Completed: %s\n", task.CompletedAt.Format("2006-01-02 15:04:05"))
-				fmt.Printf("  Duration: %s\n", time.Duration(task.Duration)*time.Millisecond)
+			count := 0
+			for _, t := range tasks {
+				if t.Status == task.Status {
+					count++
+				}
 			}
+			fmt.Printf("%s %s (%d)\n", emoji, task.Status, count)
+			fmt.Println()
+		}
 
-			if task.Error != "" {
-				fmt.Printf("  Error: %s\n", task.Error)
-			}
+		fmt.Printf("  ID: %s\n", queue.ShortID(task.ID)+"...")
+		fmt.Printf("  Agent: %s\n", task.AgentName)
+		fmt.Printf("  Worktree: %s\n", task.Worktree)
+		fmt.Printf("  Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
 
-			fmt.Println()
+		if task.StartedAt != nil {
+			fmt.Printf("  Started: %s\n", task.StartedAt.Format("2006-01-02 15:04:05"))
 		}
-	}
 
-	// Summary
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("Total: %d tasks\n", len(tasks))
-	fmt.Printf("  Pending: %d\n", len(statusGroups[queue.StatusPending]))
-	fmt.Printf("  Running: %d\n", len(statusGroups[queue.StatusRunning]))
-	fmt.Printf("  Completed: %d\n", len(statusGroups[queue.StatusCompleted]))
-	fmt.Printf("  Failed: %d\n", len(statusGroups[queue.StatusFailed]))
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		if task.CompletedAt != nil {
+			fmt.Printf("  Completed: %s\n", task.CompletedAt.Format("2006-01-02 15:04:05"))
+			fmt.Printf("  Duration: %s\n", time.Duration(task.Duration)*time.Millisecond)
+		}
+
+		if task.Error != "" {
+			fmt.Printf("  Error: %s\n", task.Error)
+		}
+
+		fmt.Println()
+	}
 }
 
 func runQueueStart(cmd *cobra.Command, args []string) {
@@ -235,7 +331,7 @@ func runQueueStart(cmd *cobra.Command, args []string) {
 	checkError(err)
 
 	// Load queue
-	q, err := queue.Load(cfg.WorktreeDir)
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Check for pending tasks
@@ -264,21 +360,122 @@ func runQueueStart(cmd *cobra.Command, args []string) {
 }
 
 func runQueueRemove(cmd *cobra.Command, args []string) {
-	taskID := args[0]
+	bySelector := queueAgent != "" || queueWorktree != "" || queueStatus != ""
+	switch {
+	case len(args) == 1 && bySelector:
+		checkError(fmt.Errorf("pass either a task ID or --agent/--worktree, not both"))
+	case len(args) == 0 && (queueAgent == "" || queueWorktree == ""):
+		checkError(fmt.Errorf("pass a task ID, or both --agent and --worktree"))
+	}
+
+	status := queue.TaskStatus(queueStatus)
+	switch status {
+	case "", queue.StatusPending, queue.StatusRunning, queue.StatusCompleted, queue.StatusFailed:
+	default:
+		checkError(fmt.Errorf("unknown status '%s' (expected pending, running, completed or failed)", queueStatus))
+	}
 
 	// Load config
 	cfg, err := config.New()
 	checkError(err)
 
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	// Load queue
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
+	checkError(err)
+
+	var tasks []queue.QueuedTask
+	if len(args) == 1 {
+		task, err := q.Find(args[0])
+		checkError(err)
+		tasks = append(tasks, *task)
+	} else {
+		tasks = q.Select(queueAgent, queueWorktree, status)
+		if len(tasks) == 0 {
+			checkError(fmt.Errorf("no matching tasks for agent %s on %s", queueAgent, queueWorktree))
+		}
+	}
+
+	// Remove tasks
+	for _, task := range tasks {
+		checkError(q.Remove(task.ID))
+		removeQueueLog(cfg.WorktreeDir, task.ID)
+		ui.Success(fmt.Sprintf("Task removed from queue: %s (%s on %s, %s)", task.ID, task.AgentName, task.Worktree, task.Status))
+	}
+}
+
+func runQueueShow(cmd *cobra.Command, args []string) {
+	// Load config
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
 	// Load queue
-	q, err := queue.Load(cfg.WorktreeDir)
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
-	// Remove task
-	err = q.Remove(taskID)
+	task, err := q.Find(args[0])
 	checkError(err)
 
-	ui.Success(fmt.Sprintf("Task removed from queue: %s", taskID))
+	fmt.Printf("ID: %s\n", task.ID)
+	fmt.Printf("Agent: %s\n", task.AgentName)
+	fmt.Printf("Worktree: %s\n", task.Worktree)
+	fmt.Printf("Status: %s\n", task.Status)
+	fmt.Printf("Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
+	if task.StartedAt != nil {
+		fmt.Printf("Started: %s\n", task.StartedAt.Format("2006-01-02 15:04:05"))
+	}
+	if task.CompletedAt != nil {
+		fmt.Printf("Completed: %s\n", task.CompletedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Duration: %s\n", time.Duration(task.Duration)*time.Millisecond)
+	}
+	if task.Error != "" {
+		fmt.Printf("Error: %s\n", task.Error)
+	}
+
+	logPath := queue.LogPath(cfg.WorktreeDir, task.ID)
+	if _, err := os.Stat(logPath); err == nil {
+		fmt.Printf("Log: %s\n", logPath)
+	}
+	if task.Status != queue.StatusRunning {
+		return
+	}
+
+	fmt.Println()
+	if queueNoFollow {
+		ui.Section("Output so far")
+		checkError(queue.FollowLog(logPath, os.Stdout, 0, func() bool { return false }))
+		return
+	}
+	ui.Section("Live output (Ctrl+C to stop following)")
+	id := task.ID
+	running := func() bool {
+		current, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
+		if err != nil {
+			return false
+		}
+		t, err := current.Find(id)
+		if err != nil {
+			return false
+		}
+		task = t
+		return t.Status == queue.StatusRunning
+	}
+	checkError(queue.FollowLog(logPath, os.Stdout, queueFollowInterval, running))
+
+	fmt.Println()
+	switch task.Status {
+	case queue.StatusRunning:
+		ui.Warning("Task no longer found in the queue")
+	case queue.StatusFailed:
+		ui.Error(fmt.Sprintf("Task failed after %s: %s", time.Duration(task.Duration)*time.Millisecond, task.Error))
+	default:
+		ui.Success(fmt.Sprintf("Task %s in %s", task.Status, time.Duration(task.Duration)*time.Millisecond))
+	}
 }
 
 func runQueueClear(cmd *cobra.Command, args []string) {
@@ -286,18 +483,25 @@ func runQueueClear(cmd *cobra.Command, args []string) {
 	cfg, err := config.New()
 	checkError(err)
 
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
 	// Load queue
-	q, err := queue.Load(cfg.WorktreeDir)
+	q, err := queue.Load(cfg.WorktreeDir, workCfg.Storage)
 	checkError(err)
 
 	// Count before clear
 	before := q.Count("")
 	completedCount := q.Count(queue.StatusCompleted)
 	failedCount := q.Count(queue.StatusFailed)
+	finished := append(q.List(queue.StatusCompleted), q.List(queue.StatusFailed)...)
 
 	// Clear
 	err = q.Clear()
 	checkError(err)
+	for _, task := range finished {
+		removeQueueLog(cfg.WorktreeDir, task.ID)
+	}
 
 	// Count after clear
 	after := q.Count("")
@@ -308,3 +512,10 @@ func runQueueClear(cmd *cobra.Command, args []string) {
 	fmt.Printf("  Failed: %d\n", failedCount)
 	fmt.Printf("  Remaining: %d\n", after)
 }
+
+// removeQueueLog deletes the run log of a task removed from the queue
+func removeQueueLog(worktreeDir, taskID string) {
+	if err := os.Remove(queue.LogPath(worktreeDir, taskID)); err != nil && !os.IsNotExist(err) {
+		ui.Warning(fmt.Sprintf("Failed to remove run log of %s: %v", taskID, err))
+	}
+}
@@ -5,10 +5,16 @@ import (
 
 	"github.com/braunmar/worktree/pkg/agent"
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	agentRunWorktree string
+	agentRunWait     bool
+)
+
 var agentRunCmd = &cobra.Command{
 	Use:   "run <task-name>",
 	Short: "Run a scheduled agent task",
@@ -18,10 +24,20 @@ The task is loaded from .worktree.yml, a temporary worktree is created,
 steps are executed, safety gates are run, and changes are committed/pushed
 if all gates pass.
 
+--worktree runs the task for a feature, as a queued run would: docker steps
+exec into that feature's containers.
+
+A run fails with "already running since ..." when another run of the task
+is in progress (up to max_concurrent runs may be), max_concurrent_agents
+runs are, or another run outside an isolated worktree uses the project
+root. --wait waits for them to finish instead.
+
 Examples:
   worktree agent run npm-audit         # Run npm audit task
   worktree agent run backend-deps      # Run backend dependency update
-  worktree agent run go-version-update # Run Go version update`,
+  worktree agent run go-version-update # Run Go version update
+  worktree agent run db-migrate --worktree feature-auth
+  worktree agent run npm-audit --wait  # Start once other runs are done`,
 	Args: cobra.ExactArgs(1),
 	Run:  runAgentTask,
 }
@@ -59,6 +75,10 @@ func runAgentTask(cmd *cobra.Command, args []string) {
 
 	// Create and run agent executor
 	executor := agent.NewExecutor(cfg, workCfg, task, taskName)
+	if agentRunWorktree != "" {
+		executor.SetWorktree(registry.NormalizeBranchName(agentRunWorktree))
+	}
+	executor.SetWaitForLock(agentRunWait)
 	err = executor.Run()
 	if err != nil {
 		checkError(fmt.Errorf("agent task failed: %w", err))
@@ -66,5 +86,7 @@ func runAgentTask(cmd *cobra.Command, args []string) {
 }
 
 func init() {
+	agentRunCmd.Flags().StringVar(&agentRunWorktree, "worktree", "", "Feature to run the task for (recorded in history; docker steps use its containers)")
+	agentRunCmd.Flags().BoolVar(&agentRunWait, "wait", false, "Wait for runs holding the task's locks to finish instead of failing")
 	agentCmd.AddCommand(agentRunCmd)
 }
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/braunmar/worktree/pkg/agent"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleAll     bool
+	scheduleDryRun  bool
+	scheduleBackend string
+)
+
+var agentScheduleCmd = &cobra.Command{
+	Use:   "schedule [task-name]",
+	Short: "Register agent tasks with cron or launchd",
+	Long: `Generate OS scheduler entries that run agent tasks on their cron schedule.
+
+On macOS a launchd agent is written to ~/Library/LaunchAgents and loaded.
+Elsewhere a block is added to your user crontab. Each task gets one entry
+labelled com.worktree.<project>.<task>; running the command again updates
+that entry in place instead of adding a duplicate, and does nothing when
+the entry is already up to date.
+
+Output of scheduled runs is appended to ~/logs/worktree-agent-<task>.log.
+
+As an alternative, 'worktree agent daemon' runs all tasks in one process.
+
+Examples:
+  worktree agent schedule npm-audit              # Schedule one task
+  worktree agent schedule --all                  # Schedule every task
+  worktree agent schedule --all --dry-run        # Print generated entries only
+  worktree agent schedule npm-audit --backend cron
+  worktree agent schedule remove npm-audit       # Delete the generated entry`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAgentSchedule,
+}
+
+var agentScheduleRemoveCmd = &cobra.Command{
+	Use:   "remove <task-name>",
+	Short: "Remove the scheduler entry generated for a task",
+	Long: `Remove the cron block or launchd agent generated by 'worktree agent schedule'.
+
+The task does not need to exist in .worktree.yml anymore, so entries for
+deleted tasks can be cleaned up too.
+
+Examples:
+  worktree agent schedule remove npm-audit
+  worktree agent schedule remove npm-audit --dry-run`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAgentScheduleRemove,
+}
+
+func init() {
+	agentScheduleCmd.Flags().BoolVar(&scheduleAll, "all", false, "schedule every task in scheduled_agents")
+	agentScheduleCmd.PersistentFlags().BoolVar(&scheduleDryRun, "dry-run", false, "print what would change without writing anything")
+	agentScheduleCmd.PersistentFlags().StringVar(&scheduleBackend, "backend", agent.DefaultScheduleBackend(), "scheduler backend: cron or launchd")
+
+	agentScheduleCmd.AddCommand(agentScheduleRemoveCmd)
+	agentCmd.AddCommand(agentScheduleCmd)
+}
+
+func runAgentSchedule(cmd *cobra.Command, args []string) {
+	if scheduleAll == (len(args) == 1) {
+		checkError(fmt.Errorf("specify a task name or --all"))
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	if len(workCfg.ScheduledAgents) == 0 {
+		checkError(fmt.Errorf("no scheduled_agents defined in .worktree.yml"))
+	}
+
+	var taskNames []string
+	if scheduleAll {
+		for name := range workCfg.ScheduledAgents {
+			taskNames = append(taskNames, name)
+		}
+		sort.Strings(taskNames)
+	} else {
+		if _, exists := workCfg.ScheduledAgents[args[0]]; !exists {
+			checkError(fmt.Errorf("agent task '%s' not found in .worktree.yml", args[0]))
+		}
+		taskNames = []string{args[0]}
+	}
+
+	binary, err := os.Executable()
+	checkError(err)
+	if resolved, err := filepath.EvalSymlinks(binary); err == nil {
+		binary = resolved
+	}
+
+	if scheduleDryRun {
+		ui.Section(fmt.Sprintf("Agent schedule (%s, dry run)", scheduleBackend))
+	} else {
+		ui.Section(fmt.Sprintf("Agent schedule (%s)", scheduleBackend))
+	}
+
+	failed := 0
+	for _, taskName := range taskNames {
+		task := workCfg.ScheduledAgents[taskName]
+
+		entry, err := agent.BuildScheduleEntry(scheduleBackend, workCfg.ProjectName, cfg.ProjectRoot, binary, taskName, task)
+		if err != nil {
+			ui.Error(err.Error())
+			failed++
+			continue
+		}
+
+		result, err := agent.ApplyScheduleEntry(entry, scheduleDryRun)
+		if err != nil {
+			ui.Error(fmt.Sprintf("%s: %v", taskName, err))
+			failed++
+			continue
+		}
+
+		printScheduleResult(taskName, entry, result)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func runAgentScheduleRemove(cmd *cobra.Command, args []string) {
+	taskName := args[0]
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	result, err := agent.RemoveScheduleEntry(scheduleBackend, workCfg.ProjectName, taskName, scheduleDryRun)
+	checkError(err)
+
+	label := agent.ScheduleLabel(workCfg.ProjectName, taskName)
+	switch {
+	case result == agent.ScheduleNotFound:
+		ui.Info(fmt.Sprintf("No %s entry found for '%s' (%s)", scheduleBackend, taskName, label))
+	case scheduleDryRun:
+		ui.Info(fmt.Sprintf("Would remove %s entry %s", scheduleBackend, label))
+	default:
+		ui.Success(fmt.Sprintf("Removed %s entry %s", scheduleBackend, label))
+	}
+}
+
+// printScheduleResult reports the outcome for one task; dry runs also print the generated entry
+func printScheduleResult(taskName string, entry *agent.ScheduleEntry, result agent.ScheduleResult) {
+	location := "crontab"
+	if entry.Path != "" {
+		location = entry.Path
+	}
+
+	if scheduleDryRun {
+		if result == agent.ScheduleUnchanged {
+			ui.Info(fmt.Sprintf("%s: up to date in %s", taskName, location))
+		} else {
+			ui.Info(fmt.Sprintf("%s: would be %s in %s", taskName, result, location))
+		}
+		ui.NewLine()
+		fmt.Print(entry.Content)
+		ui.NewLine()
+		return
+	}
+
+	switch result {
+	case agent.ScheduleUnchanged:
+		ui.Info(fmt.Sprintf("%s: already up to date (%s)", taskName, location))
+	default:
+		ui.CheckMark(fmt.Sprintf("%s: %s in %s", taskName, result, location))
+	}
+}
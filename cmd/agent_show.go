@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/agent"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var agentShowCmd = &cobra.Command{
+	Use:   "show <task-name>",
+	Short: "Show the resolved definition of an agent task",
+	Long: `Print the full definition of a scheduled agent task as it will run.
+
+Shows the context, every step with its type and command, safety gates,
+git and PR settings, rollback, notifications, GSD settings and the step
+environment. Values that are not set in .worktree.yml are shown with the
+default the task runs with, e.g. the host vars kept in isolated mode.
+
+Examples:
+  worktree agent show npm-audit`,
+	Args: cobra.ExactArgs(1),
+	Run:  runAgentShow,
+}
+
+func runAgentShow(cmd *cobra.Command, args []string) {
+	taskName := args[0]
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	task, exists := workCfg.ScheduledAgents[taskName]
+	if !exists {
+		checkError(fmt.Errorf("agent task '%s' not found in .worktree.yml", taskName))
+	}
+
+	ui.Section(fmt.Sprintf("Agent Task: %s", task.Name))
+	fmt.Printf("  Key: %s\n", taskName)
+	fmt.Printf("  Description: %s\n", orNone(task.Description))
+	fmt.Printf("  Schedule: %s (%s)\n", task.Schedule, parseCronSchedule(task.Schedule))
+	fmt.Printf("  Catch-up: %s\n", onOff(task.CatchUp))
+	fmt.Printf("  Max concurrent runs: %d\n", task.GetMaxConcurrent())
+	fmt.Println()
+
+	fmt.Println(ui.Bold("Context"))
+	fmt.Printf("  Preset: %s\n", task.Context.Preset)
+	fmt.Printf("  Branch: %s\n", task.Context.Branch)
+	fmt.Printf("  Instance: %d\n", task.Context.Instance)
+	fmt.Printf("  YOLO: %s\n", onOff(task.Context.Yolo))
+	fmt.Printf("  Isolated worktree: %s\n", onOff(task.Context.IsolatedWorktree))
+	fmt.Println()
+
+	if task.GSD != nil && task.GSD.Enabled {
+		ui.Info("GSD is enabled: the GSD workflow runs instead of the steps, gates and git operations below")
+		fmt.Println()
+	}
+
+	showAgentSteps(cfg.ProjectRoot, task)
+	showAgentGates(task)
+	showAgentGit(task)
+	showAgentNotifications(task)
+	showAgentGSD(task)
+	showAgentEnvironment(cfg.ProjectRoot, task)
+
+	ui.Info(fmt.Sprintf("Run 'worktree agent validate %s' to check this definition", taskName))
+}
+
+func showAgentSteps(projectRoot string, task *config.AgentTask) {
+	fmt.Printf("%s (%d)\n", ui.Bold("Steps"), len(task.Steps))
+	if len(task.Steps) == 0 {
+		fmt.Println("  none")
+	}
+	for i, step := range task.Steps {
+		fmt.Printf("  [%d/%d] %s (%s)\n", i+1, len(task.Steps), step.Name, step.Type)
+		if step.Template != "" {
+			fmt.Printf("        From template: %s\n", step.Template)
+		}
+		if policy := stepPolicy(step); policy != "" {
+			fmt.Printf("        Policy: %s\n", policy)
+		}
+		switch step.Type {
+		case "skill":
+			fmt.Printf("        Skill: %s\n", step.Skill)
+			if step.Args != "" {
+				fmt.Printf("        Args: %s\n", step.Args)
+			}
+			claudeArgs := "claude -c"
+			if task.Context.Yolo {
+				claudeArgs = "claude --dangerously-skip-permissions -c"
+			}
+			fmt.Printf("        Runs: %s %q\n", claudeArgs, step.Skill)
+			fmt.Printf("        Working dir: %s\n", orDefault(step.WorkingDir, "project root"))
+		case "pipeline":
+			script := filepath.Join(agent.PipelineDir, step.Pipeline+".sh")
+			if _, err := agent.PipelineScript(projectRoot, step.Pipeline); err != nil {
+				script += " (missing)"
+			}
+			fmt.Printf("        Runs: bash %s", script)
+			for _, arg := range step.PipelineArgs {
+				fmt.Printf(" %q", arg)
+			}
+			fmt.Println()
+			fmt.Printf("        Working dir: %s\n", orDefault(step.WorkingDir, "project root"))
+		case "docker":
+			args := agent.DockerStepArgs("<feature compose project>", step)
+			fmt.Printf("        Runs: %s %s %q\n", docker.Current().ComposeString(), strings.Join(args[:len(args)-1], " "), step.Command)
+			fmt.Printf("        Container: service %s of the %s project in the run's feature\n", step.Service, step.Project)
+		case "http":
+			fmt.Printf("        Request: %s %s\n", agent.HTTPStepMethod(step), step.URL)
+			for _, key := range sortedKeys(step.Headers) {
+				fmt.Printf("        Header: %s: %s\n", key, step.Headers[key])
+			}
+			if step.Body != "" {
+				fmt.Printf("        Body: %s\n", step.Body)
+			}
+			if step.ExpectStatus != 0 {
+				fmt.Printf("        Expects: status %d\n", step.ExpectStatus)
+			} else {
+				fmt.Println("        Expects: any 2xx status")
+			}
+			if step.Capture != "" {
+				capture := "response body"
+				if step.CaptureJSON != "" {
+					capture = "JSON field " + step.CaptureJSON
+				}
+				fmt.Printf("        Captures: %s into $%s for later steps and gates\n", capture, step.Capture)
+			}
+		default:
+			fmt.Printf("        Command: bash -c %q\n", step.Command)
+			fmt.Printf("        Working dir: %s\n", orDefault(step.WorkingDir, "current directory"))
+		}
+	}
+	fmt.Println()
+}
+
+// stepPolicy describes a step's timeout, retries and continue_on_error
+func stepPolicy(step config.AgentStep) string {
+	var parts []string
+	if step.Timeout != "" {
+		parts = append(parts, "timeout "+step.Timeout)
+	}
+	if step.Retries > 0 {
+		parts = append(parts, fmt.Sprintf("%d retries (first after %s, doubling)", step.Retries, step.GetRetryDelay()))
+	}
+	if step.ContinueOnError {
+		parts = append(parts, "continue on error")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func showAgentGates(task *config.AgentTask) {
+	fmt.Printf("%s (%d)\n", ui.Bold("Safety Gates"), len(task.Safety.Gates))
+	if len(task.Safety.Gates) == 0 {
+		fmt.Println("  none")
+	}
+	for i, gate := range task.Safety.Gates {
+		required := "optional"
+		if gate.Required {
+			required = "required"
+		}
+		fmt.Printf("  [%d/%d] %s (%s)\n", i+1, len(task.Safety.Gates), gate.Name, required)
+		fmt.Printf("        Command: %s\n", gate.Command)
+	}
+	if len(task.Safety.Gates) > 0 {
+		fmt.Println("  Gates run in the project root")
+	}
+	fmt.Println()
+}
+
+func showAgentGit(task *config.AgentTask) {
+	git := task.Safety.Git
+	fmt.Println(ui.Bold("Git"))
+	fmt.Printf("  Branch: %s\n", orNone(git.Branch))
+	fmt.Printf("  Commit message: %s\n", orNone(git.CommitMessage))
+	fmt.Printf("  Push: %s\n", onOff(git.Push.Enabled))
+	if git.Push.Enabled {
+		fmt.Printf("  Create PR: %s\n", onOff(git.Push.CreatePR))
+		if git.Push.CreatePR {
+			fmt.Printf("  Forge: %s\n", orDefault(git.Forge, "auto"))
+			fmt.Printf("  Target branch: %s\n", orDefault(git.Push.TargetBranch, task.Context.Branch))
+			fmt.Printf("  PR title: %s\n", orNone(git.Push.PRTitle))
+			fmt.Printf("  PR body: %s\n", orNone(strings.TrimSpace(git.Push.PRBody)))
+			fmt.Printf("  Auto-merge: %s\n", onOff(git.Push.AutoMerge))
+		}
+		fmt.Printf("  Commit status: %s\n", orDefault(git.Push.CommitStatus, "off"))
+	}
+	if strings.Contains(git.Branch+git.Push.PRTitle+git.Push.PRBody, "{date}") {
+		fmt.Println("  {date} is replaced with the run date (YYYY-MM-DD)")
+	}
+
+	rollback := task.Safety.Rollback
+	fmt.Printf("  Rollback: %s", onOff(rollback.Enabled))
+	if rollback.Enabled {
+		fmt.Printf(" (strategy: %s)", orDefault(rollback.Strategy, "cleanup-worktree"))
+	}
+	fmt.Println()
+	fmt.Println()
+}
+
+func showAgentNotifications(task *config.AgentTask) {
+	fmt.Println(ui.Bold("Notifications"))
+	showNotificationList("On success", task.Notifications.OnSuccess)
+	showNotificationList("On failure", task.Notifications.OnFailure)
+	fmt.Println()
+}
+
+func showNotificationList(label string, notifications []config.Notification) {
+	if len(notifications) == 0 {
+		fmt.Printf("  %s: none\n", label)
+		return
+	}
+	fmt.Printf("  %s:\n", label)
+	for _, n := range notifications {
+		details := []string{}
+		if n.Project != "" {
+			details = append(details, "project "+n.Project)
+		}
+		if n.Title != "" {
+			details = append(details, "title "+n.Title)
+		}
+		if len(n.Labels) > 0 {
+			details = append(details, "labels "+strings.Join(n.Labels, ", "))
+		}
+		if len(n.Recipients) > 0 {
+			details = append(details, "recipients "+strings.Join(n.Recipients, ", "))
+		}
+		if len(details) > 0 {
+			fmt.Printf("    - %s (%s)\n", n.Type, strings.Join(details, "; "))
+		} else {
+			fmt.Printf("    - %s\n", n.Type)
+		}
+	}
+}
+
+func showAgentGSD(task *config.AgentTask) {
+	fmt.Println(ui.Bold("GSD"))
+	if task.GSD == nil {
+		fmt.Println("  not configured")
+		fmt.Println()
+		return
+	}
+	fmt.Printf("  Enabled: %s\n", onOff(task.GSD.Enabled))
+	fmt.Printf("  Milestone: %s\n", orNone(task.GSD.Milestone))
+	fmt.Printf("  Read task file: %s\n", onOff(task.GSD.ReadTaskFile))
+	fmt.Printf("  Auto-execute: %s\n", onOff(task.GSD.AutoExecute))
+	if len(task.GSD.Phases) == 0 {
+		fmt.Println("  Phases: one phase planned from .task.md")
+	} else {
+		fmt.Printf("  Phases (%d):\n", len(task.GSD.Phases))
+	}
+	for i, phase := range task.GSD.Phases {
+		fmt.Printf("    [%d/%d] %s\n", i+1, len(task.GSD.Phases), phase.Name)
+		if phase.Prompt != "" {
+			fmt.Printf("          Prompt: %s\n", strings.TrimSpace(phase.Prompt))
+		}
+		for _, gate := range phase.Gates {
+			required := "optional"
+			if gate.Required {
+				required = "required"
+			}
+			fmt.Printf("          Gate: %s (%s): %s\n", gate.Name, required, gate.Command)
+		}
+	}
+	fmt.Println("  Progress: worktree agent gsd status (failed runs resume at the failed phase)")
+	fmt.Println()
+}
+
+func showAgentEnvironment(projectRoot string, task *config.AgentTask) {
+	envCfg := task.Environment
+	fmt.Println(ui.Bold("Environment"))
+	if envCfg.Isolated {
+		fmt.Println("  Isolated: on (task env vars are added on top)")
+		fmt.Printf("  Pass-through: %s\n", strings.Join(agent.PassThroughEnv(task), ", "))
+	} else {
+		fmt.Println("  Isolated: off (steps inherit the caller's environment)")
+	}
+	if envCfg.CacheDir != "" {
+		cacheDir := envCfg.CacheDir
+		if !filepath.IsAbs(cacheDir) {
+			cacheDir = filepath.Join(projectRoot, cacheDir)
+		}
+		fmt.Printf("  Cache dir: %s (XDG_CACHE_HOME, GOCACHE, GOMODCACHE)\n", cacheDir)
+	} else {
+		fmt.Println("  Cache dir: none (default caches)")
+	}
+	showEnvMap("  ", "Env", task.Env)
+	for _, step := range task.Steps {
+		showEnvMap("  ", fmt.Sprintf("Env of step '%s'", step.Name), step.Env)
+	}
+	fmt.Println()
+}
+
+// showEnvMap lists an agent env map as written; secret references are shown,
+// never their values
+func showEnvMap(indent, title string, env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+	fmt.Printf("%s%s:\n", indent, title)
+	for _, key := range sortedKeys(env) {
+		value := env[key]
+		if config.IsSecretRef(value) {
+			value += " (secret)"
+		}
+		fmt.Printf("%s  %s=%s\n", indent, key, value)
+	}
+}
+
+// onOff renders a boolean setting
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// orNone renders an optional value
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+// orDefault renders a value, or the default it falls back to when unset
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback + " (default)"
+	}
+	return value
+}
+
+func init() {
+	agentCmd.AddCommand(agentShowCmd)
+}
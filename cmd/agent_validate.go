@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/braunmar/worktree/pkg/agent"
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/ui"
 
@@ -18,7 +19,12 @@ Checks:
 - Task exists in .worktree.yml
 - Preset exists and is valid
 - Branch is specified
-- Steps are configured correctly
+- Steps are configured correctly (pipeline steps: the script exists in
+  .worktree/steps; docker steps: a service of a docker project; http
+  steps: a URL, a known method and a valid capture variable)
+- GSD tasks have a milestone and uniquely named phases (and no isolated_worktree)
+- Task and step env maps use valid names and secret references
+- Step timeouts, retries and retry delays are valid durations and counts
 - Safety gates are configured
 - Git configuration is valid
 
@@ -107,8 +113,48 @@ func runAgentValidate(cmd *cobra.Command, args []string) {
 
 	ui.CheckMark(fmt.Sprintf("YOLO mode: %v", task.Context.Yolo))
 
+	if task.Context.IsolatedWorktree {
+		if task.GSD != nil && task.GSD.Enabled {
+			ui.Error("✗ isolated_worktree is not supported with GSD (the GSD workflow runs in the project root)")
+			errors++
+		} else {
+			ui.CheckMark("Isolated worktree: steps, gates and git operations run in an ephemeral worktree")
+		}
+	}
+
+	if task.GetMaxConcurrent() > 1 && !task.Context.IsolatedWorktree {
+		ui.Warning(fmt.Sprintf("⚠ max_concurrent %d has no effect without isolated_worktree (runs in the project root never overlap)", task.MaxConcurrent))
+	} else if task.GetMaxConcurrent() > 1 {
+		ui.CheckMark(fmt.Sprintf("Max concurrent runs: %d", task.MaxConcurrent))
+	}
+
+	// Validate env maps
+	if err := config.ValidateAgentEnv("env", task.Env); err != nil {
+		ui.Error(fmt.Sprintf("✗ %v", err))
+		errors++
+	} else if len(task.Env) > 0 {
+		ui.CheckMark(fmt.Sprintf("Env: %d vars", len(task.Env)))
+	}
+	for i, step := range task.Steps {
+		if err := config.ValidateAgentEnv(fmt.Sprintf("step %d (%s): env", i+1, step.Name), step.Env); err != nil {
+			ui.Error(fmt.Sprintf("✗ %v", err))
+			errors++
+		}
+		if err := config.ValidateStepPolicy(step); err != nil {
+			ui.Error(fmt.Sprintf("✗ step %d (%s): %v", i+1, step.Name, err))
+			errors++
+		}
+	}
+
+	// Validate GSD phases
+	if task.GSD != nil && task.GSD.Enabled {
+		errors += validateGSDPhases(task.GSD)
+	}
+
 	// Validate steps
-	if len(task.Steps) == 0 {
+	if task.GSD != nil && task.GSD.Enabled && len(task.Steps) == 0 {
+		ui.Info("No steps configured (the GSD workflow runs instead)")
+	} else if len(task.Steps) == 0 {
 		ui.Error("✗ No steps configured")
 		errors++
 	} else {
@@ -120,8 +166,8 @@ func runAgentValidate(cmd *cobra.Command, args []string) {
 				errors++
 			}
 
-			if step.Type != "shell" && step.Type != "skill" {
-				ui.Error(fmt.Sprintf("  ✗ Step %d (%s): invalid type '%s' (must be 'shell' or 'skill')", i+1, step.Name, step.Type))
+			if step.Type != "shell" && step.Type != "skill" && step.Type != "pipeline" && step.Type != "docker" && step.Type != "http" {
+				ui.Error(fmt.Sprintf("  ✗ Step %d (%s): invalid type '%s' (must be 'shell', 'skill', 'pipeline', 'docker' or 'http')", i+1, step.Name, step.Type))
 				errors++
 			} else if step.Type == "shell" {
 				if step.Command == "" {
@@ -137,6 +183,27 @@ func runAgentValidate(cmd *cobra.Command, args []string) {
 				} else {
 					ui.CheckMark(fmt.Sprintf("  Step %d: %s (skill: %s)", i+1, step.Name, step.Skill))
 				}
+			} else if step.Type == "pipeline" {
+				if _, err := agent.PipelineScript(cfg.ProjectRoot, step.Pipeline); err != nil {
+					ui.Error(fmt.Sprintf("  ✗ Step %d (%s): %v", i+1, step.Name, err))
+					errors++
+				} else {
+					ui.CheckMark(fmt.Sprintf("  Step %d: %s (pipeline: %s)", i+1, step.Name, step.Pipeline))
+				}
+			} else if step.Type == "docker" {
+				if err := agent.ValidateDockerStep(workCfg, step); err != nil {
+					ui.Error(fmt.Sprintf("  ✗ Step %d (%s): %v", i+1, step.Name, err))
+					errors++
+				} else {
+					ui.CheckMark(fmt.Sprintf("  Step %d: %s (docker: %s/%s)", i+1, step.Name, step.Project, step.Service))
+				}
+			} else if step.Type == "http" {
+				if err := agent.ValidateHTTPStep(step); err != nil {
+					ui.Error(fmt.Sprintf("  ✗ Step %d (%s): %v", i+1, step.Name, err))
+					errors++
+				} else {
+					ui.CheckMark(fmt.Sprintf("  Step %d: %s (http: %s %s)", i+1, step.Name, agent.HTTPStepMethod(step), step.URL))
+				}
 			}
 		}
 	}
@@ -202,11 +269,40 @@ func runAgentValidate(cmd *cobra.Command, args []string) {
 			if task.Safety.Git.Push.AutoMerge {
 				ui.Warning("⚠ Auto-merge is enabled (use with caution)")
 			}
+
+			switch forge := task.Safety.Git.Forge; forge {
+			case "", agent.ForgeAuto:
+				ui.CheckMark("Forge: auto (detected from the origin remote)")
+			case agent.ForgeGitHub, agent.ForgeGitLab:
+				ui.CheckMark(fmt.Sprintf("Forge: %s", forge))
+			default:
+				ui.Error(fmt.Sprintf("✗ Unknown forge '%s' (expected %s, %s or %s)", forge, agent.ForgeGitHub, agent.ForgeGitLab, agent.ForgeAuto))
+				errors++
+			}
+		}
+
+		switch task.Safety.Git.Push.CommitStatus {
+		case "":
+		case agent.CommitStatusGitHub, agent.CommitStatusGitLab:
+			ui.CheckMark(fmt.Sprintf("Commit status: %s (context worktree-agent/%s)", task.Safety.Git.Push.CommitStatus, taskName))
+		default:
+			ui.Error(fmt.Sprintf("✗ Unknown commit_status '%s' (expected %s or %s)", task.Safety.Git.Push.CommitStatus, agent.CommitStatusGitHub, agent.CommitStatusGitLab))
+			errors++
 		}
 	} else {
 		ui.Info("Push disabled")
 	}
 
+	// Validate environment configuration
+	if task.Environment.Isolated {
+		ui.CheckMark(fmt.Sprintf("Environment: isolated (%d extra pass-through vars)", len(task.Environment.PassThrough)))
+	} else if len(task.Environment.PassThrough) > 0 {
+		ui.Warning("⚠ environment.pass_through has no effect unless environment.isolated is true")
+	}
+	if task.Environment.CacheDir != "" {
+		ui.CheckMark(fmt.Sprintf("Cache dir: %s", task.Environment.CacheDir))
+	}
+
 	// Validate rollback configuration
 	if task.Safety.Rollback.Enabled {
 		ui.CheckMark(fmt.Sprintf("Rollback enabled (strategy: %s)", task.Safety.Rollback.Strategy))
@@ -226,6 +322,50 @@ func runAgentValidate(cmd *cobra.Command, args []string) {
 	}
 }
 
+// validateGSDPhases checks the GSD milestone and its phases, returning the number of errors
+func validateGSDPhases(gsd *config.GSDConfig) int {
+	errors := 0
+	if gsd.Milestone == "" {
+		ui.Error("✗ GSD milestone is empty")
+		errors++
+	} else {
+		ui.CheckMark(fmt.Sprintf("GSD milestone: %s", gsd.Milestone))
+	}
+
+	if len(gsd.Phases) == 0 {
+		ui.CheckMark("GSD phases: one phase planned from .task.md")
+		return errors
+	}
+	ui.CheckMark(fmt.Sprintf("GSD phases: %d configured", len(gsd.Phases)))
+
+	seen := map[string]bool{}
+	for i, phase := range gsd.Phases {
+		switch {
+		case phase.Name == "":
+			ui.Error(fmt.Sprintf("  ✗ Phase %d: name is empty", i+1))
+			errors++
+			continue
+		case seen[phase.Name]:
+			ui.Error(fmt.Sprintf("  ✗ Phase %d: duplicate name '%s'", i+1, phase.Name))
+			errors++
+			continue
+		}
+		seen[phase.Name] = true
+
+		if phase.Prompt == "" && !gsd.ReadTaskFile {
+			ui.Warning(fmt.Sprintf("⚠ Phase %d (%s): prompt is empty (recommended to add)", i+1, phase.Name))
+		}
+		for j, gate := range phase.Gates {
+			if gate.Name == "" || gate.Command == "" {
+				ui.Error(fmt.Sprintf("  ✗ Phase %d (%s): gate %d needs a name and a command", i+1, phase.Name, j+1))
+				errors++
+			}
+		}
+		ui.CheckMark(fmt.Sprintf("  Phase %d: %s (%d gates)", i+1, phase.Name, len(phase.Gates)))
+	}
+	return errors
+}
+
 func init() {
 	agentCmd.AddCommand(agentValidateCmd)
 }
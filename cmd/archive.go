@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/archive"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	archiveOutput  string
+	archiveVolumes bool
+	archiveKeep    bool
+	archiveForce   bool
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive <feature-name-or-branch>",
+	Short: "Archive a feature environment into a tarball",
+	Long: `Stop a feature and pack its environment into a tarball that
+'worktree restore' can recreate it from.
+
+The archive contains:
+- The registry entry (branch, projects, ports, compose project names)
+- The instance marker, .worktree-env.json and .worktree-overrides.yml
+- The generated files of each project
+- The HEAD commit of each project, so the branch can be recreated if deleted
+- With --volumes, the contents of the feature's container volumes (databases etc.)
+
+Code is not archived: commits stay on the feature branch in each project's
+repository. Features with uncommitted changes are refused unless --force.
+
+By default the feature is removed after archiving (worktrees, feature
+directory, registry entry), freeing its ports. Use --keep to leave it in place.
+
+Examples:
+  worktree archive feature-user-auth                   # Writes ./feature-user-auth-<date>.tar.gz
+  worktree archive feature-user-auth --volumes         # Include database volumes
+  worktree archive feature/reports -o reports.tar.gz --keep`,
+	Args: cobra.ExactArgs(1),
+	Run:  runArchive,
+}
+
+func init() {
+	archiveCmd.Flags().StringVarP(&archiveOutput, "output", "o", "", "archive file to write (default ./<feature>-<timestamp>.tar.gz)")
+	archiveCmd.Flags().BoolVar(&archiveVolumes, "volumes", false, "include the feature's container volumes")
+	archiveCmd.Flags().BoolVar(&archiveKeep, "keep", false, "keep the feature after archiving")
+	archiveCmd.Flags().BoolVarP(&archiveForce, "force", "f", false, "archive and remove even with uncommitted changes")
+}
+
+func runArchive(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+	configureContainerRuntime(workCfg)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature '%s' not found in registry", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		checkError(fmt.Errorf("feature directory not found: worktrees/%s", featureName))
+	}
+
+	guardCrossFeature("archive", featureName, archiveForce)
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+
+	// Record HEAD commits and refuse to drop uncommitted work
+	commits := make(map[string]string)
+	var dirty []string
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		worktreePath := filepath.Join(featureDir, project.Dir)
+		if commit, err := git.HeadCommit(worktreePath); err == nil {
+			commits[projectName] = commit
+		}
+		if changes, _ := git.HasUncommittedChanges(worktreePath); changes {
+			dirty = append(dirty, projectName)
+		}
+	}
+	if len(dirty) > 0 && !archiveKeep && !archiveForce {
+		ui.Error(fmt.Sprintf("Uncommitted changes in: %v", dirty))
+		ui.Info("Commit them first (they are not part of the archive), use --keep, or --force to discard them")
+		os.Exit(1)
+	}
+
+	output := archiveOutput
+	if output == "" {
+		output = fmt.Sprintf("%s-%s.tar.gz", featureName, time.Now().Format("20060102-150405"))
+	}
+	if _, err := os.Stat(output); err == nil {
+		checkError(fmt.Errorf("%s already exists", output))
+	}
+
+	ui.Section(fmt.Sprintf("Archiving %s", featureName))
+
+	stopFeatureServices(cfg, workCfg, wt)
+
+	manifest := &archive.Manifest{
+		Version:     archive.FormatVersion,
+		ProjectName: workCfg.ProjectName,
+		ArchivedAt:  time.Now(),
+		Worktree:    wt,
+		Commits:     commits,
+		Files:       archiveFiles(workCfg, wt, featureDir),
+	}
+
+	volumeDir, err := os.MkdirTemp("", "worktree-archive-*")
+	checkError(err)
+	defer os.RemoveAll(volumeDir)
+
+	if archiveVolumes {
+		manifest.Volumes = exportFeatureVolumes(wt, volumeDir)
+	}
+
+	if err := archive.Create(output, manifest, featureDir, volumeDir); err != nil {
+		checkError(err)
+	}
+	ui.CheckMark(fmt.Sprintf("Wrote %s (%d files, %d volumes)", output, len(manifest.Files), len(manifest.Volumes)))
+
+	if !archiveKeep {
+		removeFeatureFiles(cfg, workCfg, reg, wt)
+		if err := reg.Save(); err != nil {
+			checkError(fmt.Errorf("failed to save registry: %w", err))
+		}
+	}
+
+	ui.NewLine()
+	ui.Success(fmt.Sprintf("Feature '%s' archived", featureName))
+	ui.Info(fmt.Sprintf("Restore it with: worktree restore %s", output))
+}
+
+// archiveFiles lists the feature-relative files worth archiving that exist:
+// worktree's own state files and each project's generated files
+func archiveFiles(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureDir string) []string {
+	candidates := config.FeatureStateFiles()
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		for _, file := range workCfg.GeneratedFiles[projectName] {
+			candidates = append(candidates, filepath.Join(project.Dir, file.Path))
+		}
+	}
+
+	var files []string
+	for _, rel := range candidates {
+		if info, err := os.Lstat(filepath.Join(featureDir, rel)); err == nil && info.Mode().IsRegular() {
+			files = append(files, filepath.ToSlash(rel))
+		}
+	}
+	return files
+}
+
+// exportFeatureVolumes dumps every compose volume of the feature into volumeDir.
+// Failures are reported and the volume left out of the archive.
+func exportFeatureVolumes(wt *registry.Worktree, volumeDir string) []archive.Volume {
+	var volumes []archive.Volume
+	seen := make(map[string]bool) // Projects may share one compose project
+	for _, projectName := range wt.Projects {
+		composeProject := wt.GetComposeProject(projectName)
+		if composeProject == "" || seen[composeProject] {
+			continue
+		}
+		seen[composeProject] = true
+
+		found, err := docker.ListComposeVolumes(composeProject)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("%s: %v", projectName, err))
+			continue
+		}
+
+		for _, vol := range found {
+			if err := docker.ExportVolume(vol.Name, filepath.Join(volumeDir, archive.VolumeDumpName(vol.Name))); err != nil {
+				ui.Warning(fmt.Sprintf("Skipping volume %s: %v", vol.Name, err))
+				continue
+			}
+			volumes = append(volumes, archive.Volume{
+				Name:           vol.Name,
+				ComposeProject: composeProject,
+				ComposeVolume:  vol.ComposeVolume,
+			})
+			ui.CheckMark(fmt.Sprintf("Exported volume %s", vol.Name))
+		}
+	}
+	return volumes
+}
@@ -135,12 +135,12 @@ func runBatchCreate(cmd *cobra.Command, args []string) {
 	}
 
 	// Summary
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Separator(53)
 	fmt.Printf("Batch Create Summary\n")
 	fmt.Printf("  Total: %d\n", len(batchTasks.Tasks))
 	fmt.Printf("  Success: %d\n", successCount)
 	fmt.Printf("  Failed: %d\n", failedCount)
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Separator(53)
 
 	if failedCount > 0 {
 		fmt.Println()
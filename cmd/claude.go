@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	claudeYolo      bool
+	claudePrompt    string
+	claudeNoContext bool
+)
+
+var claudeCmd = &cobra.Command{
+	Use:   "claude [feature-name] [-- claude-args...]",
+	Short: "Launch Claude in a feature worktree with the feature's env",
+	Long: `Launch the claude CLI in the feature's Claude working directory (the
+project with claude_working_dir: true, else the feature's first project).
+
+Claude gets the feature's resolved env vars (allocated ports, derived URLs,
+INSTANCE, FEATURE_NAME, overrides), so the commands it runs talk to this
+feature's services. Secret env vars are not passed.
+
+A context prompt summarizing the feature (branches, worktree paths, allocated
+ports and service URLs) is appended to Claude's system prompt; skip it with
+--no-context. --prompt starts the session with that message.
+
+YOLO mode (claude --dangerously-skip-permissions) is used when the feature
+has it enabled ('worktree yolo') or with --yolo for this session only.
+
+Arguments after -- are passed to claude unchanged.
+
+If no feature name is provided and you're in a worktree directory,
+the feature will be auto-detected from .worktree-instance.
+
+Examples:
+  worktree claude feature-user-auth
+  worktree claude feature-user-auth --yolo --prompt "Fix the failing login test"
+  worktree claude -- --model opus   # Auto-detect feature, pass flags to claude`,
+	Args: cobra.ArbitraryArgs,
+	Run:  runClaude,
+}
+
+func init() {
+	claudeCmd.Flags().BoolVar(&claudeYolo, "yolo", false, "skip Claude's permission prompts for this session (--dangerously-skip-permissions)")
+	claudeCmd.Flags().StringVar(&claudePrompt, "prompt", "", "initial message of the session")
+	claudeCmd.Flags().BoolVar(&claudeNoContext, "no-context", false, "don't append the feature context to Claude's system prompt")
+}
+
+func runClaude(cmd *cobra.Command, args []string) {
+	// Arguments after -- belong to claude
+	var claudeArgs []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		claudeArgs = args[dash:]
+		args = args[:dash]
+	}
+	if len(args) > 1 {
+		checkError(fmt.Errorf("accepts at most 1 feature name, received %d", len(args)))
+	}
+
+	var featureName string
+	if len(args) == 0 {
+		instance, err := config.DetectInstance()
+		if err != nil {
+			ui.Error("Not in a worktree directory and no feature name provided")
+			ui.Info("Usage: worktree claude <feature-name>")
+			os.Exit(1)
+		}
+		featureName = instance.Feature
+	} else {
+		featureName = registry.NormalizeBranchName(args[0])
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	claudeProject := getClaudeWorkingProject(workCfg, wt.Projects)
+	if claudeProject == "" {
+		checkError(fmt.Errorf("feature '%s' has no projects", featureName))
+	}
+	workDir := filepath.Join(featureDir, workCfg.Projects[claudeProject].Dir)
+	if _, err := os.Stat(workDir); err != nil {
+		checkError(fmt.Errorf("claude working directory %s is missing: %w", workDir, err))
+	}
+
+	envVars, _ := featureEnvVars(workCfg, wt, featureName, featureDir)
+	env := os.Environ()
+	for _, key := range sortedKeys(envVars) {
+		if strings.Contains(envVars[key], "{") {
+			continue // Unresolved per-service placeholders like {service}
+		}
+		env = append(env, fmt.Sprintf("%s=%s", key, envVars[key]))
+	}
+
+	yolo := claudeYolo || wt.YoloMode
+	var launchArgs []string
+	if yolo {
+		launchArgs = append(launchArgs, "--dangerously-skip-permissions")
+	}
+	if !claudeNoContext {
+		launchArgs = append(launchArgs, "--append-system-prompt", claudeContext(workCfg, wt, featureDir, claudeProject))
+	}
+	launchArgs = append(launchArgs, claudeArgs...)
+	if claudePrompt != "" {
+		launchArgs = append(launchArgs, claudePrompt)
+	}
+
+	ui.Rocket(fmt.Sprintf("Launching Claude for Feature: %s", featureName))
+	ui.PrintStatusLine("Working directory", relToRoot(cfg.ProjectRoot, workDir))
+	if yolo {
+		ui.PrintStatusLine("YOLO Mode", "🚀 Enabled (autonomous mode)")
+	}
+	ui.NewLine()
+
+	claude := exec.Command("claude", launchArgs...)
+	claude.Dir = workDir
+	claude.Env = env
+	claude.Stdin = os.Stdin
+	claude.Stdout = os.Stdout
+	claude.Stderr = os.Stderr
+	if err := claude.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+		checkError(fmt.Errorf("failed to launch claude: %w", err))
+	}
+}
+
+// claudeContext summarizes a feature for Claude: its worktrees and branches,
+// allocated ports and service URLs
+func claudeContext(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureDir, claudeProject string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are working in the worktree feature '%s' of %s, an isolated copy of the projects with its own services.\n",
+		wt.Normalized, workCfg.ProjectName)
+	fmt.Fprintf(&b, "Your working directory is the %s project.\n", claudeProject)
+	if wt.YoloMode {
+		b.WriteString("YOLO mode is enabled: work autonomously when the solution is clear.\n")
+	}
+
+	b.WriteString("\nProjects (worktree path, branch):\n")
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: %s (branch %s)\n", projectName, filepath.Join(featureDir, project.Dir), wt.BranchFor(projectName))
+	}
+
+	if len(wt.Ports) > 0 {
+		b.WriteString("\nAllocated ports (also set as env vars):\n")
+		for _, name := range sortedKeys(wt.Ports) {
+			fmt.Fprintf(&b, "- %s=%d\n", name, wt.Ports[name])
+		}
+	}
+
+	services := workCfg.GetDisplayableServices(wt.Ports)
+	if len(services) > 0 {
+		b.WriteString("\nService URLs:\n")
+		for _, name := range sortedKeys(services) {
+			fmt.Fprintf(&b, "- %s: %s\n", name, services[name])
+		}
+	}
+
+	fmt.Fprintf(&b, "\nUse 'worktree status %s' for service health and 'worktree logs %s' for logs.\n", wt.Normalized, wt.Normalized)
+	fmt.Fprintf(&b, "A machine-readable version of this context is in %s in your working directory.\n", contextFile)
+	return b.String()
+}
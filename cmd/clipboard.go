@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// copyDefault is the --copy value when the flag is given without a target
+const copyDefault = "default"
+
+// copyToClipboard puts text on the system clipboard and reports what was copied
+func copyToClipboard(what, text string) {
+	if err := ui.CopyToClipboard(text); err != nil {
+		checkError(fmt.Errorf("failed to copy %s to the clipboard: %w", what, err))
+	}
+	ui.CheckMark(fmt.Sprintf("Copied %s to the clipboard: %s", what, text))
+}
+
+// featureServiceURL returns the URL of a feature's service, looked up by its
+// display name (case-insensitive) or its port variable. Without a name it is
+// the service of the instance port.
+func featureServiceURL(workCfg *config.WorktreeConfig, wt *registry.Worktree, name string) (string, string, error) {
+	if name == "" {
+		portName, err := workCfg.GetInstancePortName()
+		if err != nil {
+			return "", "", err
+		}
+		name = portName
+	}
+
+	services := workCfg.GetDisplayableServices(wt.Ports)
+	for envName, envCfg := range workCfg.EnvVariables {
+		if envCfg.Name == "" {
+			continue
+		}
+		if url, ok := services[envCfg.Name]; ok && (strings.EqualFold(envCfg.Name, name) || envName == name) {
+			return envCfg.Name, url, nil
+		}
+	}
+
+	names := make([]string, 0, len(services))
+	for serviceName := range services {
+		names = append(names, serviceName)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return "", "", fmt.Errorf("feature '%s' has no services with a URL", wt.Normalized)
+	}
+	return "", "", fmt.Errorf("no service '%s' with a URL (available: %s)", name, strings.Join(names, ", "))
+}
+
+// featureCopyTarget resolves a status --copy target: the feature directory by
+// default, a project's worktree path, or a service URL
+func featureCopyTarget(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, target string) (string, string, error) {
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+	if target == copyDefault {
+		return "feature path", featureDir, nil
+	}
+	if project, ok := workCfg.Projects[target]; ok {
+		return target + " path", filepath.Join(featureDir, project.Dir), nil
+	}
+	name, url, err := featureServiceURL(workCfg, wt, target)
+	if err != nil {
+		return "", "", fmt.Errorf("'%s' is neither a project nor a service: %w", target, err)
+	}
+	return name + " URL", url, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/doctor"
+
+	"github.com/spf13/cobra"
+)
+
+// completionRequestTimeout bounds the test completion request sent to the binary on PATH
+const completionRequestTimeout = 10 * time.Second
+
+var completionDoctorShell string
+
+var completionDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that shell completion and the prompt helper are installed",
+	Long: `Verify the shell integration of worktree for your shell and print the exact
+commands that fix what is missing:
+
+- The shell, detected from $SHELL (bash, zsh or fish; override with --shell)
+- worktree is on PATH, and it is this binary rather than an older copy
+- The binary on PATH answers completion requests
+- The completion script is sourced from your rc file or installed where the
+  shell loads completions, and matches this version
+- bash: the bash-completion package; zsh: compinit runs in .zshrc
+- The 'worktree prompt' segment in your prompt (optional)
+
+Exit codes are those of 'worktree doctor': 0 healthy, 1 warnings, 2 errors.
+
+Examples:
+  worktree completion doctor
+  worktree completion doctor --shell fish`,
+	Args: cobra.NoArgs,
+	Run:  runCompletionDoctor,
+}
+
+func init() {
+	completionDoctorCmd.Flags().StringVar(&completionDoctorShell, "shell", "", "shell to check: "+strings.Join(doctor.Shells, ", ")+" (default: from $SHELL)")
+}
+
+// addCompletionDoctor adds the doctor subcommand to cobra's generated
+// completion command, which exists only once it is initialized
+func addCompletionDoctor(root *cobra.Command) {
+	root.InitDefaultCompletionCmd()
+	for _, c := range root.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(completionDoctorCmd)
+			return
+		}
+	}
+}
+
+func runCompletionDoctor(cmd *cobra.Command, args []string) {
+	home, err := os.UserHomeDir()
+	checkError(err)
+	executable, err := os.Executable()
+	checkError(err)
+
+	report := doctor.CheckShell(doctor.ShellOptions{
+		Shell:      completionDoctorShell,
+		Program:    cmd.Root().Name(),
+		Executable: executable,
+		Home:       home,
+		Getenv:     os.Getenv,
+		LookPath:   exec.LookPath,
+		Complete:   sendCompletionRequest,
+		Scripts:    completionScripts(cmd.Root()),
+	})
+	report.Print()
+	os.Exit(report.ExitCode())
+}
+
+// sendCompletionRequest asks the binary at path to complete its subcommands,
+// as the shell does on <TAB>
+func sendCompletionRequest(path string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), completionRequestTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	request := exec.CommandContext(ctx, path, cobra.ShellCompNoDescRequestCmd, "")
+	request.Stderr = &stderr
+	out, err := request.Output()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// The last line of a completion response is the ":<directive>" line
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if !strings.HasPrefix(lines[len(lines)-1], ":") {
+		return fmt.Errorf("unexpected response %q", lines[len(lines)-1])
+	}
+	return nil
+}
+
+// completionScripts generates the completion scripts of each shell, with and
+// without descriptions, to tell whether an installed script is current
+func completionScripts(root *cobra.Command) map[string][][]byte {
+	scripts := map[string][][]byte{}
+	for _, desc := range []bool{true, false} {
+		var bash, zsh, fish bytes.Buffer
+		root.GenBashCompletionV2(&bash, desc)
+		if desc {
+			root.GenZshCompletion(&zsh)
+		} else {
+			root.GenZshCompletionNoDesc(&zsh)
+		}
+		root.GenFishCompletion(&fish, desc)
+		scripts["bash"] = append(scripts["bash"], bash.Bytes())
+		scripts["zsh"] = append(scripts["zsh"], zsh.Bytes())
+		scripts["fish"] = append(scripts["fish"], fish.Bytes())
+	}
+	return scripts
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and maintain the .worktree.yml configuration",
+	Long: `Commands for inspecting and maintaining the .worktree.yml configuration.
+
+Available subcommands:
+  show           - Print the fully-resolved configuration
+  rename-project - Rename a project key everywhere it is referenced`,
+}
+
+var (
+	configShowFeature   string
+	configShowEffective bool
+)
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the fully-resolved configuration",
+	Long: `Print the configuration as the tool sees it after loading.
+
+Shows the merged config layers (.worktree.yml, include: files and
+.worktree.local.yml) with defaults applied, and the allocation range of every
+port variable, including ranges derived from port expressions and pools.
+
+With --feature, also prints that feature's instance, allocated ports and the
+env vars computed for it (registry values plus current overrides). This is
+useful for debugging why a service got a specific port or URL.
+
+With --effective, prints the merged YAML instead, each key annotated with the
+layer it came from. Layers merge in this order, later ones winning:
+.worktree.yml, the include: entries in listed order (URL includes are pinned
+by sha256 and cached), then .worktree.local.yml.
+
+Examples:
+  worktree config show
+  worktree config show --feature feature-user-auth
+  worktree config show --effective`,
+	Args: cobra.NoArgs,
+	Run:  runConfigShow,
+}
+
+var configRenameProjectCmd = &cobra.Command{
+	Use:   "rename-project <old-name> <new-name>",
+	Short: "Rename a project key in config, registry and instance markers",
+	Long: `Rename a project key consistently across the whole tool.
+
+This command:
+1. Renames the key under 'projects' in .worktree.yml, its include: files and
+   .worktree.local.yml, wherever it is defined
+2. Updates every preset and generated_files entry referencing it, in any of
+   those files
+3. Updates Projects and ComposeProjects of every registry entry
+4. Updates the project list in each feature's .worktree-instance marker
+5. Renames process executor PID files (<project>.pid)
+
+Comments and key order in the config files are preserved. A URL include that
+mentions the project cannot be rewritten, so the rename is refused. Compose project names
+are left unchanged so running containers can still be stopped. The project's
+'dir' is not touched, so no worktree directories move.
+
+Examples:
+  worktree config rename-project api backend
+  worktree config rename-project web frontend`,
+	Args: cobra.ExactArgs(2),
+	Run:  runConfigRenameProject,
+}
+
+func init() {
+	configShowCmd.Flags().StringVar(&configShowFeature, "feature", "", "also show computed values for this feature")
+	configShowCmd.Flags().BoolVar(&configShowEffective, "effective", false, "print the merged YAML with the layer each key came from")
+	configShowCmd.MarkFlagsMutuallyExclusive("feature", "effective")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configRenameProjectCmd)
+}
+
+func runConfigShow(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	if configShowEffective {
+		data, err := config.EffectiveConfig(cfg.ProjectRoot)
+		checkError(err)
+		fmt.Print(string(data))
+		return
+	}
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	// Resolve the feature first so an unknown name fails before any output
+	var reg *registry.Registry
+	var wt *registry.Worktree
+	featureName := ""
+	if configShowFeature != "" {
+		featureName = registry.NormalizeBranchName(configShowFeature)
+		reg, err = registry.Load(cfg.WorktreeDir, workCfg)
+		checkError(err)
+
+		var exists bool
+		wt, exists = reg.Find(featureName)
+		if !exists {
+			ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
+			fmt.Println("\nAvailable features:")
+			for _, w := range reg.List() {
+				fmt.Printf("  - %s\n", w.Normalized)
+			}
+			os.Exit(1)
+		}
+		featureName = wt.Normalized
+	}
+
+	ui.Section("Configuration")
+	for _, layer := range workCfg.Layers {
+		if rel, err := filepath.Rel(cfg.ProjectRoot, layer); err == nil {
+			layer = rel
+		}
+		ui.PrintStatusLine("Layer", layer)
+	}
+	ui.PrintStatusLine("project_name", workCfg.ProjectName)
+	ui.PrintStatusLine("hostname", workCfg.Hostname)
+	ui.PrintStatusLine("container_host", workCfg.GetContainerHost())
+	ui.PrintStatusLine("instance_env", strings.Join(workCfg.GetInstanceEnvNames(), ", "))
+	runtime := workCfg.ContainerRuntime
+	if runtime == "" {
+		runtime = "auto"
+	}
+	ui.PrintStatusLine("container_runtime", runtime)
+	ui.PrintStatusLine("default_preset", valueOrNone(workCfg.DefaultPreset))
+	if workCfg.MaxInstances > 0 {
+		ui.PrintStatusLine("max_instances", fmt.Sprintf("%d", workCfg.MaxInstances))
+	}
+	ui.PrintStatusLine("auto_fixtures", fmt.Sprintf("%t", workCfg.AutoFixtures))
+
+	ui.Section("Projects")
+	for _, name := range sortedKeys(workCfg.Projects) {
+		project := workCfg.Projects[name]
+		mainBranch := project.MainBranch
+		if mainBranch == "" {
+			mainBranch = "main"
+		}
+		fmt.Printf("  %-20s dir=%s main_branch=%s executor=%s\n", name, project.Dir, mainBranch, project.GetExecutor())
+	}
+
+	ui.Section("Presets")
+	for _, name := range sortedKeys(workCfg.Presets) {
+		marker := ""
+		if name == workCfg.DefaultPreset {
+			marker = " (default)"
+		}
+		fmt.Printf("  %-20s [%s]%s\n", name, strings.Join(workCfg.Presets[name].Projects, ", "), marker)
+	}
+
+	if len(workCfg.PortPools) > 0 {
+		ui.Section("Port pools")
+		for _, name := range sortedKeys(workCfg.PortPools) {
+			pool := workCfg.PortPools[name]
+			fmt.Printf("  %-20s %d-%d used by [%s]\n", name, pool.Range[0], pool.Range[1],
+				strings.Join(workCfg.GetPoolServices(name), ", "))
+		}
+	}
+
+	if len(workCfg.EnvVariables) > 0 {
+		ui.Section("Env variables")
+		for _, name := range sortedKeys(workCfg.EnvVariables) {
+			fmt.Printf("  %-20s %s\n", name, describeEnvVar(workCfg, name))
+		}
+	}
+
+	if wt != nil {
+		printFeatureResolved(cfg, workCfg, wt, featureName)
+	}
+	ui.NewLine()
+}
+
+// describeEnvVar summarises how an env variable gets its value and port range
+func describeEnvVar(workCfg *config.WorktreeConfig, name string) string {
+	envCfg := workCfg.EnvVariables[name]
+
+	var parts []string
+	if envCfg.Env != "" {
+		parts = append(parts, "env="+envCfg.Env)
+	} else {
+		parts = append(parts, "display-only")
+	}
+	if envCfg.Port != "" {
+		parts = append(parts, fmt.Sprintf("port=%q", envCfg.Port))
+	}
+	if envCfg.Value != "" {
+		parts = append(parts, fmt.Sprintf("value=%q", envCfg.Value))
+	}
+	if envCfg.Host != "" {
+		parts = append(parts, fmt.Sprintf("host=%s", workCfg.HostFor(name)))
+	}
+
+	if portRange := workCfg.GetPortRangeFor(name); portRange != nil {
+		source := "explicit"
+		switch {
+		case envCfg.Pool != "":
+			source = "pool " + envCfg.Pool
+		case envCfg.Range == nil:
+			source = "from port expression"
+		}
+		parts = append(parts, fmt.Sprintf("range=%d-%d (%s)", portRange[0], portRange[1], source))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// printFeatureResolved prints the instance, ports and computed env vars of one feature
+func printFeatureResolved(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName string) {
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+
+	ui.Section(fmt.Sprintf("Feature: %s", featureName))
+	ui.PrintStatusLine("Branch", wt.Branch)
+	ui.PrintStatusLine("Projects", strings.Join(wt.Projects, ", "))
+	instance := featureInstance(workCfg, wt)
+	ui.PrintStatusLine("Instance", fmt.Sprintf("%d", instance))
+
+	// Registry values are written by new-feature/start; recompute for older entries
+	vars := make(map[string]string, len(wt.ComputedVars))
+	for key, value := range wt.ComputedVars {
+		vars[key] = value
+	}
+	var overrides map[string]string
+	if len(vars) == 0 {
+		vars = workCfg.ExportEnvVars(instance)
+		vars["FEATURE_NAME"] = featureName
+		for service, port := range wt.Ports {
+			vars[service] = fmt.Sprintf("%d", port)
+		}
+		config.AddPathVars(vars, featureDir)
+		overrides = resolveWithOverrides(workCfg, instance, featureDir, vars)
+	} else {
+		// Vars read from the host environment are never stored; resolve them now
+		workCfg.ResolveHostEnvVars(instance, vars)
+		overrides = applyFeatureOverrides(featureDir, vars)
+	}
+
+	if len(wt.Ports) > 0 {
+		ui.NewLine()
+		fmt.Println("  Ports:")
+		for _, service := range sortedKeys(wt.Ports) {
+			fmt.Printf("    %-22s %d\n", service, wt.Ports[service])
+		}
+	}
+
+	ui.NewLine()
+	fmt.Println("  Environment:")
+	for _, key := range sortedKeys(vars) {
+		suffix := ""
+		if _, ok := overrides[key]; ok {
+			suffix = " (override)"
+		}
+		fmt.Printf("    %-22s %s%s\n", key, vars[key], suffix)
+	}
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func runConfigRenameProject(cmd *cobra.Command, args []string) {
+	oldName, newName := args[0], args[1]
+	if oldName == newName {
+		checkError(fmt.Errorf("old and new project names are the same"))
+	}
+	if newName == "" {
+		checkError(fmt.Errorf("new project name cannot be empty"))
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	if _, exists := workCfg.Projects[oldName]; !exists {
+		checkError(fmt.Errorf("project '%s' not found in the config", oldName))
+	}
+	if _, exists := workCfg.Projects[newName]; exists {
+		checkError(fmt.Errorf("project '%s' already exists in the config", newName))
+	}
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	ui.Section(fmt.Sprintf("Renaming project '%s' → '%s'", oldName, newName))
+
+	// 1. Config files (projects, presets, generated_files) in every layer
+	changed, err := config.RenameProjectInConfig(cfg.ProjectRoot, oldName, newName)
+	checkError(err)
+	for _, path := range changed {
+		ui.CheckMark(fmt.Sprintf("Updated %s", relToRoot(cfg.ProjectRoot, path)))
+	}
+
+	// 2. Registry entries
+	updated := reg.RenameProject(oldName, newName)
+	if err := reg.Save(); err != nil {
+		checkError(fmt.Errorf("config was updated but saving the registry failed: %w", err))
+	}
+	ui.CheckMark(fmt.Sprintf("Updated registry entries: %d", len(updated)))
+
+	// 3. Per-feature markers and PID files
+	for _, featureName := range updated {
+		featureDir := cfg.WorktreeFeaturePath(featureName)
+
+		if err := config.RenameInstanceProject(featureDir, oldName, newName); err != nil {
+			ui.Warning(fmt.Sprintf("%s: failed to update instance marker: %v", featureName, err))
+		} else {
+			ui.CheckMark(fmt.Sprintf("%s: updated instance marker", featureName))
+		}
+
+		oldPid := filepath.Join(featureDir, oldName+".pid")
+		if _, err := os.Stat(oldPid); err == nil {
+			if err := os.Rename(oldPid, filepath.Join(featureDir, newName+".pid")); err != nil {
+				ui.Warning(fmt.Sprintf("%s: failed to rename PID file: %v", featureName, err))
+			}
+		}
+	}
+
+	ui.NewLine()
+	ui.Success(fmt.Sprintf("Project '%s' renamed to '%s'", oldName, newName))
+}
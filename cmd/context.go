@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+// contextFile is the machine-readable feature summary written into the
+// Claude working directory
+const contextFile = ".worktree-context.json"
+
+// contextExcludeMarker marks the info/exclude block that keeps contextFile
+// out of git status
+const contextExcludeMarker = "worktree context"
+
+var contextRefresh bool
+
+var contextCmd = &cobra.Command{
+	Use:   "context [feature-name]",
+	Short: "Print the feature's .worktree-context.json",
+	Long: `Print the machine-readable context of a feature: its branch, instance,
+projects (worktree path, branch, start and test commands), allocated ports,
+service URLs and resolved env vars.
+
+The same JSON is kept in .worktree-context.json in the feature's Claude working
+directory (the project with claude_working_dir: true, else the first project),
+so AI assistants and scripts inside the worktree can discover the environment
+without guessing. new-feature and start write it, sync refreshes it; --refresh
+rewrites it now, e.g. after editing .worktree.yml. The file is listed in the
+repository's .git/info/exclude, so it never shows up in git status.
+
+Secret env vars are not included.
+
+If no feature name is provided and you're in a worktree directory,
+the feature will be auto-detected from .worktree-instance.
+
+Examples:
+  worktree context feature-user-auth
+  worktree context --refresh
+  jq -r '.urls.Backend' .worktree-context.json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runContext,
+}
+
+func init() {
+	contextCmd.Flags().BoolVar(&contextRefresh, "refresh", false, "rewrite .worktree-context.json instead of printing it")
+}
+
+// featureContext is the content of .worktree-context.json
+type featureContext struct {
+	Feature     string            `json:"feature"`
+	Branch      string            `json:"branch"`
+	Instance    int               `json:"instance"`
+	ProjectName string            `json:"project_name,omitempty"`
+	ProjectRoot string            `json:"project_root"`
+	FeatureDir  string            `json:"feature_dir"`
+	WorkingDir  string            `json:"working_dir"` // The Claude working directory holding this file
+	YoloMode    bool              `json:"yolo_mode"`
+	Projects    []contextProject  `json:"projects"`
+	Ports       map[string]int    `json:"ports"`
+	URLs        map[string]string `json:"urls"`
+	Env         map[string]string `json:"env"`
+	Commands    map[string]string `json:"commands"` // worktree commands for this feature, by purpose
+	GeneratedAt string            `json:"generated_at"`
+}
+
+// contextProject is one project of a feature in .worktree-context.json
+type contextProject struct {
+	Name         string `json:"name"`
+	Path         string `json:"path"`
+	Branch       string `json:"branch"`
+	Executor     string `json:"executor"`
+	StartCommand string `json:"start_command,omitempty"`
+	TestCommand  string `json:"test_command,omitempty"`
+}
+
+func runContext(cmd *cobra.Command, args []string) {
+	var featureName string
+	if len(args) == 0 {
+		instance, err := config.DetectInstance()
+		if err != nil {
+			ui.Error("Not in a worktree directory and no feature name provided")
+			ui.Info("Usage: worktree context <feature-name>")
+			os.Exit(1)
+		}
+		featureName = instance.Feature
+	} else {
+		featureName = registry.NormalizeBranchName(args[0])
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+
+	if contextRefresh {
+		path, err := writeFeatureContext(cfg, workCfg, wt)
+		checkError(err)
+		ui.CheckMark(fmt.Sprintf("Feature context written (%s)", relToRoot(cfg.ProjectRoot, path)))
+		return
+	}
+
+	data, err := json.MarshalIndent(buildFeatureContext(cfg, workCfg, wt), "", "  ")
+	checkError(err)
+	fmt.Println(string(data))
+}
+
+// buildFeatureContext collects what a tool inside the worktree needs to know
+// about its feature
+func buildFeatureContext(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) *featureContext {
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+	ctx := &featureContext{
+		Feature:     wt.Normalized,
+		Branch:      wt.Branch,
+		Instance:    featureInstance(workCfg, wt),
+		ProjectName: workCfg.ProjectName,
+		ProjectRoot: cfg.ProjectRoot,
+		FeatureDir:  featureDir,
+		YoloMode:    wt.YoloMode,
+		Projects:    []contextProject{},
+		Ports:       wt.Ports,
+		URLs:        workCfg.GetDisplayableServices(wt.Ports),
+		Commands: map[string]string{
+			"status":  "worktree status " + wt.Normalized,
+			"logs":    "worktree logs " + wt.Normalized,
+			"start":   "worktree start " + wt.Normalized,
+			"stop":    "worktree stop " + wt.Normalized,
+			"restart": "worktree restart " + wt.Normalized,
+			"env":     "worktree env " + wt.Normalized,
+			"sync":    "worktree sync " + wt.Normalized,
+		},
+		GeneratedAt: time.Now().Format(time.RFC3339),
+	}
+	if ctx.Ports == nil {
+		ctx.Ports = map[string]int{}
+	}
+	if claudeProject := getClaudeWorkingProject(workCfg, wt.Projects); claudeProject != "" {
+		ctx.WorkingDir = filepath.Join(featureDir, workCfg.Projects[claudeProject].Dir)
+	}
+
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		ctx.Projects = append(ctx.Projects, contextProject{
+			Name:         projectName,
+			Path:         filepath.Join(featureDir, project.Dir),
+			Branch:       wt.BranchFor(projectName),
+			Executor:     project.GetExecutor(),
+			StartCommand: project.StartCommand,
+			TestCommand:  project.TestCommand,
+		})
+	}
+
+	ctx.Env, _ = featureEnvVars(workCfg, wt, wt.Normalized, featureDir)
+	for key, value := range ctx.Env {
+		if strings.Contains(value, "{") {
+			delete(ctx.Env, key) // Unresolved per-service placeholders like {service}
+		}
+	}
+	return ctx
+}
+
+// writeFeatureContext writes .worktree-context.json into the feature's Claude
+// working directory and returns its path
+func writeFeatureContext(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) (string, error) {
+	ctx := buildFeatureContext(cfg, workCfg, wt)
+	if ctx.WorkingDir == "" {
+		return "", fmt.Errorf("feature '%s' has no projects", wt.Normalized)
+	}
+	if _, err := os.Stat(ctx.WorkingDir); err != nil {
+		return "", fmt.Errorf("claude working directory %s is missing: %w", ctx.WorkingDir, err)
+	}
+
+	data, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal feature context: %w", err)
+	}
+	path := filepath.Join(ctx.WorkingDir, contextFile)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", contextFile, err)
+	}
+
+	// Keep the file out of git status, so it is neither committed nor blocks removing the worktree
+	if err := git.SetExcludes(ctx.WorkingDir, contextExcludeMarker, []string{contextFile}); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to exclude %s from git: %v", contextFile, err))
+	}
+	return path, nil
+}
+
+// refreshFeatureContext rewrites .worktree-context.json, warning on failure
+func refreshFeatureContext(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) {
+	if _, err := writeFeatureContext(cfg, workCfg, wt); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write %s: %v", contextFile, err))
+	}
+}
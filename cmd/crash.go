@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/crash"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// crashDumpsDisabled reports whether WORKTREE_CRASH_DUMPS=0 asks for the raw
+// Go panic instead of a crash dump
+func crashDumpsDisabled() bool {
+	env := os.Getenv("WORKTREE_CRASH_DUMPS")
+	return env == "0" || env == "false"
+}
+
+// reportCrash writes a crash dump of a panic to worktrees/.crash, or the
+// temp directory outside a project, and tells the user how to file a bug.
+// The dump stays on this machine.
+func reportCrash(value interface{}, stack []byte) error {
+	dir := filepath.Join(os.TempDir(), "worktree-crash")
+	projectRoot := ""
+	if cfg, err := config.New(); err == nil {
+		dir = filepath.Join(cfg.WorktreeDir, crash.Dir)
+		projectRoot = cfg.ProjectRoot
+	}
+
+	report := crash.Report{
+		Time:    time.Now(),
+		Version: rootCmd.Version,
+		Args:    os.Args[1:],
+		Panic:   value,
+		Stack:   stack,
+		Recent:  ui.RecentLines(),
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintf(os.Stderr, "worktree crashed unexpectedly: %v\n", value)
+	path, err := crash.Write(dir, report, crash.DefaultAnonymizer(projectRoot))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write crash dump: %v\n\n%s", err, stack)
+		return fmt.Errorf("panic: %v", value)
+	}
+	fmt.Fprintf(os.Stderr, "\nA crash dump was saved to:\n  %s\n\n", path)
+	fmt.Fprintln(os.Stderr, "It was stored on this machine only; nothing was sent anywhere.")
+	fmt.Fprintln(os.Stderr, "To report the bug, review the dump and attach it to a new issue at:")
+	fmt.Fprintf(os.Stderr, "  %s\n", crash.IssuesURL)
+	return fmt.Errorf("panic: %v", value)
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	describeAsCommand bool
+	describeAsYAML    bool
+)
+
+// featureSpec is the shareable description of how a feature was created
+type featureSpec struct {
+	Branch     string            `yaml:"branch"`
+	Preset     string            `yaml:"preset,omitempty"`
+	Projects   []string          `yaml:"projects"`
+	BranchMap  map[string]string `yaml:"branch_map,omitempty"`
+	From       string            `yaml:"from,omitempty"`
+	NoFixtures bool              `yaml:"no_fixtures,omitempty"`
+	Yolo       bool              `yaml:"yolo,omitempty"`
+	Overrides  map[string]string `yaml:"overrides,omitempty"`
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <feature-name-or-branch>",
+	Short: "Show how to recreate a feature environment",
+	Long: `Show the parameters a feature was created with and the commands that
+recreate an identical environment: the new-feature invocation (branch,
+preset, per-project branches, --from, --no-fixtures, --yolo) followed by its
+env variable overrides, if any.
+
+Share the output with teammates so they can spin up the same environment,
+e.g. to review a feature. Ports are not part of it; each checkout allocates
+its own.
+
+Examples:
+  worktree describe feature-user-auth
+  worktree describe feature/user-auth --as-command   # Commands only, for scripts
+  worktree describe feature/user-auth --as-yaml      # Shareable YAML snippet`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDescribe,
+}
+
+func init() {
+	describeCmd.Flags().BoolVar(&describeAsCommand, "as-command", false, "print only the commands that recreate the feature")
+	describeCmd.Flags().BoolVar(&describeAsYAML, "as-yaml", false, "print the creation parameters as YAML")
+	describeCmd.MarkFlagsMutuallyExclusive("as-command", "as-yaml")
+}
+
+func runDescribe(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	overrides, err := config.ReadOverrides(cfg.WorktreeFeaturePath(featureName))
+	checkError(err)
+
+	spec, warnings := describeFeature(workCfg, wt, overrides)
+
+	switch {
+	case describeAsYAML:
+		data, err := yaml.Marshal(spec)
+		checkError(err)
+		fmt.Print(string(data))
+	case describeAsCommand:
+		for _, line := range spec.commands(featureName) {
+			fmt.Println(line)
+		}
+	default:
+		ui.PrintHeader(fmt.Sprintf("Feature: %s", featureName))
+		ui.NewLine()
+		ui.PrintStatusLine("Branch", spec.Branch)
+		for _, projectName := range sortedKeys(spec.BranchMap) {
+			ui.PrintStatusLine("  "+projectName, spec.BranchMap[projectName])
+		}
+		ui.PrintStatusLine("Preset", spec.Preset)
+		ui.PrintStatusLine("Projects", strings.Join(spec.Projects, ", "))
+		if spec.From != "" {
+			ui.PrintStatusLine("From", spec.From)
+		}
+		ui.PrintStatusLine("Created", wt.Created.Format("2006-01-02 15:04"))
+		ui.NewLine()
+
+		for _, warning := range warnings {
+			ui.Warning(warning)
+		}
+
+		fmt.Println("Recreate with:")
+		for _, line := range spec.commands(featureName) {
+			ui.PrintCommand("  " + line)
+		}
+		ui.NewLine()
+	}
+}
+
+// describeFeature builds the spec of a feature from its registry entry. The
+// warnings explain where the spec cannot reproduce the feature exactly.
+func describeFeature(workCfg *config.WorktreeConfig, wt *registry.Worktree, overrides map[string]string) (*featureSpec, []string) {
+	spec := &featureSpec{
+		Branch:     wt.Branch,
+		Preset:     wt.Preset,
+		Projects:   wt.Projects,
+		BranchMap:  wt.Branches,
+		From:       wt.BaseRef,
+		NoFixtures: wt.NoFixtures,
+		Yolo:       wt.YoloMode,
+		Overrides:  overrides,
+	}
+
+	var warnings []string
+	if spec.Preset == "" {
+		// Features created before presets were recorded: find one with the same projects
+		for _, name := range sortedKeys(workCfg.Presets) {
+			if slices.Equal(workCfg.Presets[name].Projects, wt.Projects) {
+				spec.Preset = name
+				break
+			}
+		}
+		if spec.Preset == "" {
+			warnings = append(warnings, fmt.Sprintf("No preset has exactly the projects %v; the command uses the default preset", wt.Projects))
+		}
+	} else if preset, ok := workCfg.Presets[spec.Preset]; !ok {
+		warnings = append(warnings, fmt.Sprintf("Preset '%s' no longer exists in .worktree.yml", spec.Preset))
+	} else if !slices.Equal(preset.Projects, wt.Projects) {
+		warnings = append(warnings, fmt.Sprintf("Preset '%s' now has projects %v, the feature has %v", spec.Preset, preset.Projects, wt.Projects))
+	}
+
+	return spec, warnings
+}
+
+// commands returns the new-feature invocation recreating the spec, followed
+// by an override command when the feature has overrides
+func (s *featureSpec) commands(featureName string) []string {
+	args := []string{"worktree", "new-feature", shellArg(s.Branch)}
+	if s.Preset != "" {
+		args = append(args, shellArg(s.Preset))
+	}
+	if len(s.BranchMap) > 0 {
+		var pairs []string
+		for _, projectName := range sortedKeys(s.BranchMap) {
+			pairs = append(pairs, projectName+"="+s.BranchMap[projectName])
+		}
+		args = append(args, "--branch-map", shellArg(strings.Join(pairs, ",")))
+	}
+	if s.From != "" {
+		args = append(args, "--from", shellArg(s.From))
+	}
+	if s.NoFixtures {
+		args = append(args, "--no-fixtures")
+	}
+	if s.Yolo {
+		args = append(args, "--yolo")
+	}
+	lines := []string{strings.Join(args, " ")}
+
+	if len(s.Overrides) > 0 {
+		args = []string{"worktree", "override", featureName}
+		for _, key := range sortedKeys(s.Overrides) {
+			args = append(args, shellArg(key+"="+s.Overrides[key]))
+		}
+		lines = append(lines, strings.Join(args, " "))
+	}
+	return lines
+}
+
+var shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+=,-]+$`)
+
+// shellArg quotes s for /bin/sh unless it consists of characters that need no quoting
+func shellArg(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/parallel"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 
@@ -20,7 +20,8 @@ var diffCmd = &cobra.Command{
 
 This command:
 1. Runs git diff <main>...<branch> in each project worktree
-2. Shows the combined diff across all projects
+2. Shows the combined diff across all projects, in project order (projects
+   are diffed up to git_parallelism at a time, default 4)
 
 The feature name is automatically normalized, so you can use either:
 - The normalized feature name: feature-user-auth
@@ -45,7 +46,7 @@ func runDiff(cmd *cobra.Command, args []string) {
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
 		fmt.Println("\nAvailable features:")
@@ -54,6 +55,7 @@ func runDiff(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	if !cfg.WorktreeExists(featureName) {
 		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
@@ -73,6 +75,12 @@ func runDiff(cmd *cobra.Command, args []string) {
 
 	featureDir := cfg.WorktreeFeaturePath(featureName)
 
+	type projectDiff struct {
+		projectName  string
+		worktreePath string
+		mainBranch   string
+	}
+	var diffs []projectDiff
 	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
@@ -91,27 +99,33 @@ func runDiff(cmd *cobra.Command, args []string) {
 		if project.MainBranch != "" {
 			mainBranch = project.MainBranch
 		}
+		diffs = append(diffs, projectDiff{projectName, worktreePath, mainBranch})
+	}
 
-		ui.Section(fmt.Sprintf("%s", projectName))
-
-		// Check if there's any diff first
-		checkCmd := exec.Command("git", "diff", mainBranch+"..."+wt.Branch, "--name-only")
-		checkCmd.Dir = worktreePath
-		var checkOut bytes.Buffer
-		checkCmd.Stdout = &checkOut
-		checkCmd.Run()
-
-		if checkOut.Len() == 0 {
-			ui.Info("No changes relative to " + mainBranch)
+	// Diffs are computed concurrently and printed in project order
+	parallel.Run(len(diffs), workCfg.GetGitParallelism(), func(i int) *projectOutput {
+		d := diffs[i]
+		result := &projectOutput{}
+		diffArgs := []string{"diff", d.mainBranch + "..." + wt.BranchFor(d.projectName)}
+		if !ui.IsPlain() {
+			// Captured output is not a terminal; keep git's colors for one
+			diffArgs = append(diffArgs, "--color")
+		}
+		diffExec := git.Command(d.worktreePath, diffArgs...)
+		diffExec.Stdout = &result.output
+		diffExec.Stderr = &result.output
+		result.err = diffExec.Run()
+		return result
+	}, func(i int, result *projectOutput) bool {
+		d := diffs[i]
+		ui.Section(d.projectName)
+		if result.output.Len() == 0 {
+			ui.Info("No changes relative to " + d.mainBranch)
 			ui.NewLine()
-			continue
+			return true
 		}
-
-		diffExec := exec.Command("git", "diff", mainBranch+"..."+wt.Branch)
-		diffExec.Dir = worktreePath
-		diffExec.Stdout = os.Stdout
-		diffExec.Stderr = os.Stderr
-		diffExec.Run()
+		result.print()
 		ui.NewLine()
-	}
+		return true
+	})
 }
@@ -5,7 +5,9 @@ import (
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/doctor"
 	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -14,6 +16,7 @@ var (
 	featureFilter string
 	noFetch       bool
 	autoFix       bool
+	fixDryRun     bool
 	jsonOutput    bool
 )
 
@@ -23,28 +26,52 @@ var doctorCmd = &cobra.Command{
 	Long: `Diagnose and report issues with worktree setup:
 
 - Docker availability and status
+- Git version and the worktree features it supports
 - Orphaned containers, directories, or registry entries
 - Git status and branch tracking
 - Stale worktrees (old, merged, or unused)
-- Port allocation conflicts
+- Port allocations: out-of-range ports, ports held by unrelated processes,
+  and ports left unbound by running features
+- Symlinks that are missing, point elsewhere or dangle
+- Registry and instance marker files that fail their checksum (edited by
+  hand or partially written)
+
+With --feature, only that feature is checked (its registry entry, symlinks,
+git status, staleness and port bindings), which is much faster with many
+worktrees. The report and exit codes are the same: 0 healthy, 1 warnings,
+2 errors (an unknown feature is an error too).
 
 The doctor command helps maintain a healthy worktree environment and
 identifies issues before they cause problems.
 
+With --fix, doctor then cleans up after itself:
+
+- Accepts the content of state files failing their checksum when it still
+  parses, and restores the others from their last good backup (asks first)
+- Stops and removes containers of features that are not in the registry
+- Removes registry entries whose directory is gone
+- Deletes directories in worktrees/ that are not in the registry (asks first)
+- Prunes stale git worktree metadata in each project repository
+
+Use --dry-run to preview every action without changing anything.
+
 Examples:
   worktree doctor                      # Check all worktrees
-  worktree doctor --feature user-auth  # Check specific feature
+  worktree doctor --feature feature-user-auth  # Check one feature only
   worktree doctor --no-fetch           # Skip git fetch (faster)
-  worktree doctor --fix                # Auto-fix safe issues
+  worktree doctor --fix                # Auto-fix issues
+  worktree doctor --fix --dry-run      # Preview fixes
+  worktree doctor --fix --yes          # Fix without confirmation prompts
   worktree doctor --json               # JSON output for scripting`,
 	Args: cobra.NoArgs,
 	Run:  runDoctor,
 }
 
 func init() {
-	doctorCmd.Flags().StringVar(&featureFilter, "feature", "", "check specific feature only")
+	doctorCmd.Flags().StringVar(&featureFilter, "feature", "", "check only this feature (name or branch)")
 	doctorCmd.Flags().BoolVar(&noFetch, "no-fetch", false, "skip git fetch before comparing")
-	doctorCmd.Flags().BoolVar(&autoFix, "fix", false, "auto-fix safe issues (orphaned registry entries)")
+	doctorCmd.Flags().BoolVar(&autoFix, "fix", false, "fix orphaned registry entries, directories, containers and git metadata")
+	doctorCmd.Flags().BoolVar(&fixDryRun, "dry-run", false, "with --fix: show what would be fixed without changing anything")
 	doctorCmd.Flags().BoolVar(&jsonOutput, "json", false, "output results as JSON")
 }
 
@@ -55,25 +82,81 @@ func runDoctor(cmd *cobra.Command, args []string) {
 
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
+
+	if featureFilter != "" {
+		featureFilter = registry.NormalizeBranchName(featureFilter)
+	}
 
-	// Load registry
-	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	// Repair state files failing their checksum first, since a corrupted
+	// registry must not be used for the other fixes
+	var integrityFixes []doctor.FixAction
+	if autoFix || fixDryRun {
+		integrityFixes = doctor.PlanIntegrityFixes(doctor.CheckIntegrity(cfg, featureFilter))
+		if !fixDryRun {
+			doctor.ApplyFixes(cfg, workCfg, nil, integrityFixes, confirmFix)
+		}
+	}
+
+	// Load registry; checksum mismatches are reported below instead of failing here
+	reg, err := registry.LoadUnverified(cfg.WorktreeDir, workCfg)
+	if err != nil && len(integrityFixes) > 0 && !jsonOutput {
+		// A registry that does not parse stays unreadable until the planned repair runs
+		doctor.PrintFixes(integrityFixes, fixDryRun)
+		ui.NewLine()
+	}
 	checkError(err)
 
+	// A scoped run only makes sense for a known feature
+	if featureFilter != "" {
+		wt, exists := reg.Find(featureFilter)
+		if !exists {
+			ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureFilter))
+			fmt.Println("\nAvailable features:")
+			for _, w := range reg.List() {
+				fmt.Printf("  - %s\n", w.Normalized)
+			}
+			os.Exit(2)
+		}
+		featureFilter = wt.Normalized
+	}
+
 	// Run health checks
 	report := doctor.RunHealthCheck(cfg, workCfg, reg, doctor.Options{
 		FeatureFilter: featureFilter,
 		NoFetch:       noFetch,
-		AutoFix:       autoFix,
 	})
 
-	// Output report
+	if !jsonOutput {
+		report.Print()
+	}
+
+	if autoFix || fixDryRun {
+		fixes := doctor.PlanFixes(cfg, workCfg, report)
+		if !fixDryRun {
+			doctor.ApplyFixes(cfg, workCfg, reg, fixes, confirmFix)
+		}
+		report.Fixes = append(integrityFixes, fixes...)
+		if !jsonOutput {
+			doctor.PrintFixes(report.Fixes, fixDryRun)
+			ui.NewLine()
+		}
+	}
+
 	if jsonOutput {
 		fmt.Println(report.ToJSON())
-	} else {
-		report.Print()
 	}
 
 	// Exit with appropriate code
 	os.Exit(report.ExitCode())
 }
+
+// confirmFix asks before a destructive fix. With --json the prompt cannot be
+// shown, so such fixes are skipped unless --yes is given.
+func confirmFix(action doctor.FixAction) bool {
+	if jsonOutput {
+		return ui.AssumingYes()
+	}
+
+	return ui.Confirm(strings.ToUpper(action.Description[:1])+action.Description[1:]+"?", false)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// dryRunPreview prints what start, stop, restart or remove would do under
+// --dry-run: the commands per project with their directory and compose
+// project, the env vars they get and the files that would change
+type dryRunPreview struct {
+	root string // Project root; paths are shown relative to it
+}
+
+// newDryRunPreview prints the preview header
+func newDryRunPreview(projectRoot string) *dryRunPreview {
+	ui.Section("🔍 Dry Run - Preview Mode")
+	return &dryRunPreview{root: projectRoot}
+}
+
+// env lists the env vars commands would run with. Secrets show their
+// reference, since resolving them is part of what a dry run skips.
+func (p *dryRunPreview) env(workCfg *config.WorktreeConfig, envVars map[string]string) {
+	vars := make(map[string]string, len(envVars))
+	for key, value := range envVars {
+		vars[key] = value
+	}
+	for _, envCfg := range workCfg.EnvVariables {
+		if envCfg.Secret != "" {
+			vars[envCfg.Env] = fmt.Sprintf("<secret %s>", envCfg.Secret)
+		}
+	}
+
+	fmt.Println("Environment:")
+	for _, key := range sortedKeys(vars) {
+		ui.CheckMark(fmt.Sprintf("%s=%s", key, vars[key]))
+	}
+	ui.NewLine()
+}
+
+// project starts the commands of one project
+func (p *dryRunPreview) project(projectName, worktreePath, composeProject string) {
+	fmt.Printf("%s (in %s, COMPOSE_PROJECT_NAME=%s):\n", projectName, p.rel(worktreePath), composeProject)
+}
+
+// command lists a command of the current project; empty commands are skipped
+func (p *dryRunPreview) command(label, command string) {
+	if command != "" {
+		ui.CheckMark(fmt.Sprintf("%s: %s", label, command))
+	}
+}
+
+// composeDown lists the compose command that stops a docker project
+func (p *dryRunPreview) composeDown(composeProject string) {
+	p.command("stop", docker.Current().ComposeString()+" -p "+composeProject+" down --remove-orphans")
+}
+
+// files lists files or directories that would be written or removed
+func (p *dryRunPreview) files(title string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Println(title)
+	for _, path := range paths {
+		ui.CheckMark(p.rel(path))
+	}
+	ui.NewLine()
+}
+
+// done closes the preview
+func (p *dryRunPreview) done(action string) {
+	ui.Info("This is a dry run - no changes were made")
+	ui.Println("💡 Run without --dry-run to " + action)
+}
+
+// rel shows a path relative to the project root when it is inside it
+func (p *dryRunPreview) rel(path string) string {
+	if rel, err := filepath.Rel(p.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// composeProjectFor returns the compose project a project of the feature runs under
+func composeProjectFor(workCfg *config.WorktreeConfig, wt *registry.Worktree, projectName string) string {
+	if composeProject := wt.GetComposeProject(projectName); composeProject != "" {
+		return composeProject
+	}
+	return fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, wt.Normalized, projectName)
+}
+
+// stopProject lists how a project's services would be stopped
+func (p *dryRunPreview) stopProject(workCfg *config.WorktreeConfig, projectName, composeProject, featureDir string) {
+	project := workCfg.Projects[projectName]
+	if project.GetExecutor() == "process" {
+		p.command("stop", "SIGTERM to the process group in "+p.rel(filepath.Join(featureDir, projectName+".pid")))
+		return
+	}
+	p.composeDown(composeProject)
+}
+
+// startProject lists how a project's services would be started
+func (p *dryRunPreview) startProject(workCfg *config.WorktreeConfig, projectName, featureDir string) {
+	project := workCfg.Projects[projectName]
+	if project.GetExecutor() == "process" {
+		p.command("start_command (background, pid file "+p.rel(filepath.Join(featureDir, projectName+".pid"))+")", project.StartCommand)
+	} else {
+		p.command("start_command", project.StartCommand)
+	}
+}
+
+// healthChecks lists the readiness probes run after a project starts
+func (p *dryRunPreview) healthChecks(workCfg *config.WorktreeConfig, projectName string) {
+	for _, check := range workCfg.Projects[projectName].HealthChecks {
+		p.command("health check", check.Label())
+	}
+}
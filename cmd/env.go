@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var envFormat string
+
+var envCmd = &cobra.Command{
+	Use:   "env [feature-name]",
+	Short: "Print all resolved environment variables of a feature",
+	Long: `Print every resolved environment variable of a feature (allocated ports,
+derived URLs, INSTANCE, FEATURE_NAME, overrides) for external tools and IDE
+run configurations.
+
+Formats:
+  dotenv  KEY=value lines for .env files (default)
+  shell   export KEY='value' lines, for eval/source
+  json    a single JSON object
+
+Variables that only resolve per service (e.g. COMPOSE_PROJECT_NAME templates)
+and secret: variables are not included.
+
+If no feature name is provided and you're in a worktree directory,
+the feature will be auto-detected from .worktree-instance.
+
+Examples:
+  worktree env feature-user-auth > .env.worktree
+  eval "$(worktree env feature-user-auth --format shell)"
+  worktree env --format json | jq -r .APP_PORT`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runEnv,
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envFormat, "format", "dotenv", "output format: dotenv, shell or json")
+}
+
+func runEnv(cmd *cobra.Command, args []string) {
+	switch envFormat {
+	case "dotenv", "shell", "json":
+	default:
+		checkError(fmt.Errorf("unknown format '%s' (expected dotenv, shell or json)", envFormat))
+	}
+
+	var featureName string
+	if len(args) == 0 {
+		instance, err := config.DetectInstance()
+		if err != nil {
+			ui.Error("Not in a worktree directory and no feature name provided")
+			ui.Info("Usage: worktree env <feature-name>")
+			os.Exit(1)
+		}
+		featureName = instance.Feature
+	} else {
+		featureName = registry.NormalizeBranchName(args[0])
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	envVars, _ := featureEnvVars(workCfg, wt, featureName, cfg.WorktreeFeaturePath(featureName))
+	for key, value := range envVars {
+		if strings.Contains(value, "{") {
+			delete(envVars, key) // Unresolved per-service placeholders like {service}
+		}
+	}
+
+	switch envFormat {
+	case "json":
+		data, err := json.MarshalIndent(envVars, "", "  ")
+		checkError(err)
+		fmt.Println(string(data))
+	case "shell":
+		for _, key := range sortedKeys(envVars) {
+			fmt.Printf("export %s=%s\n", key, shellQuote(envVars[key]))
+		}
+	default:
+		for _, key := range sortedKeys(envVars) {
+			fmt.Printf("%s=%s\n", key, dotenvQuote(envVars[key]))
+		}
+	}
+}
+
+// dotenvPlainRe matches values that need no quoting in a .env file
+var dotenvPlainRe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// dotenvQuote double-quotes a value when it contains characters .env parsers
+// treat specially
+func dotenvQuote(value string) string {
+	if dotenvPlainRe.MatchString(value) {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "$", `\$`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// shellQuote single-quotes a value for POSIX shells
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var flagsNoReload bool
+
+var flagsCmd = &cobra.Command{
+	Use:   "flags <feature-name> [set KEY=VALUE... | unset KEY...]",
+	Short: "Show or change a feature's runtime feature flags",
+	Long: `Show or change the runtime feature flags of a feature.
+
+feature_flags in .worktree.yml renders a flags file (JSON or YAML) into the
+project worktrees of every feature, with placeholders resolved. Services read
+it at runtime, so flags change without env vars or restarts.
+
+'set' and 'unset' change the feature's values (kept in
+worktrees/<feature>/.worktree-flags.yml, on top of the configured defaults),
+rewrite the flags files and run feature_flags.reload_command in each project
+worktree. Values true/false and numbers are written as booleans and numbers.
+
+Examples:
+  worktree flags feature-x                          # List flags and values
+  worktree flags feature-x set new_checkout=true
+  worktree flags feature-x set rate_limit=50 --no-reload
+  worktree flags feature-x unset new_checkout       # Back to the default`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runFlags,
+}
+
+func init() {
+	flagsCmd.Flags().BoolVar(&flagsNoReload, "no-reload", false, "do not run feature_flags.reload_command")
+}
+
+func runFlags(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	if !workCfg.FeatureFlags.Enabled() {
+		ui.Error("No feature_flags configured in .worktree.yml")
+		os.Exit(1)
+	}
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	envVars, _ := featureEnvVars(workCfg, wt, featureName, featureDir)
+
+	if len(args) == 1 {
+		printFlags(workCfg, featureName, featureDir, envVars)
+		return
+	}
+
+	flags, err := config.ReadFlags(featureDir)
+	checkError(err)
+
+	action, operands := args[1], args[2:]
+	if len(operands) == 0 {
+		checkError(fmt.Errorf("'%s' needs at least one flag", action))
+	}
+	switch action {
+	case "set":
+		for _, assignment := range operands {
+			key, value, ok := strings.Cut(assignment, "=")
+			if !ok || key == "" || strings.ContainsAny(key, " \t") {
+				checkError(fmt.Errorf("invalid flag '%s': expected KEY=VALUE", assignment))
+			}
+			if _, known := workCfg.FeatureFlags.Flags[key]; !known {
+				ui.Warning(fmt.Sprintf("'%s' is not in feature_flags.flags", key))
+			}
+			flags[key] = value
+		}
+	case "unset":
+		for _, key := range operands {
+			delete(flags, key)
+		}
+	default:
+		checkError(fmt.Errorf("unknown action '%s' (expected set or unset)", action))
+	}
+
+	checkError(config.WriteFlags(featureDir, flags))
+	writeFeatureFlags(workCfg, featureDir, wt.Projects, envVars)
+	ui.Success(fmt.Sprintf("Updated flags for '%s'", featureName))
+
+	if workCfg.FeatureFlags.ReloadCommand == "" || flagsNoReload {
+		return
+	}
+	for _, projectName := range workCfg.FeatureFlags.FlagProjects(wt.Projects) {
+		worktreePath := featureDir + "/" + workCfg.Projects[projectName].Dir
+
+		envList := os.Environ()
+		for key, value := range envVars {
+			envList = append(envList, fmt.Sprintf("%s=%s", key, value))
+		}
+		envList = append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", wt.GetComposeProject(projectName)))
+		envList = append(envList, composeFileEnv(workCfg, featureDir, projectName)...)
+
+		reload := exec.Command("sh", "-c", workCfg.FeatureFlags.ReloadCommand)
+		reload.Dir = worktreePath
+		reload.Env = envList
+		reload.Stdout = os.Stdout
+		reload.Stderr = os.Stderr
+		if err := reload.Run(); err != nil {
+			ui.Warning(fmt.Sprintf("Reload command failed for %s: %v", projectName, err))
+		} else {
+			ui.CheckMark(fmt.Sprintf("Reloaded %s", projectName))
+		}
+	}
+}
+
+// printFlags lists a feature's resolved flags, marking values set for the feature
+func printFlags(workCfg *config.WorktreeConfig, featureName, featureDir string, envVars map[string]string) {
+	flags, err := workCfg.ResolveFlags(featureDir, envVars)
+	checkError(err)
+	runtime, err := config.ReadFlags(featureDir)
+	checkError(err)
+
+	if len(flags) == 0 {
+		ui.Info(fmt.Sprintf("No flags defined for '%s'", featureName))
+		return
+	}
+
+	keys := make([]string, 0, len(flags))
+	for key := range flags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ui.Section(fmt.Sprintf("Feature flags for %s", featureName))
+	for _, key := range keys {
+		line := fmt.Sprintf("  %s=%v", key, flags[key])
+		if _, set := runtime[key]; set {
+			line += " (set)"
+		}
+		fmt.Println(line)
+	}
+	ui.NewLine()
+}
+
+// writeFeatureFlags renders the feature_flags file into a feature's project
+// worktrees. Errors are warnings so flags never block starting a feature.
+func writeFeatureFlags(workCfg *config.WorktreeConfig, featureDir string, projects []string, envVars map[string]string) {
+	if _, err := workCfg.WriteFlagsFiles(featureDir, projects, envVars); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write feature flags: %v", err))
+	}
+}
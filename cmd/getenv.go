@@ -22,6 +22,9 @@ With explicit feature name (reads from registry, works from anywhere):
 Auto-detected from within a feature directory (reads from .worktree-env):
   cd worktrees/feature-x && worktree get-env FE_PORT
 
+Values read from the host environment ({env:VAR}) are never stored, so they
+are resolved from the current environment when asked for.
+
 Output is the raw value only, suitable for scripting:
   PORT=$(worktree get-env feature-x FE_PORT)`,
 	Args: cobra.RangeArgs(1, 2),
@@ -43,13 +46,17 @@ func runGetEnv(cmd *cobra.Command, args []string) {
 		reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 		checkError(err)
 
-		wt, exists := reg.Get(featureName)
+		wt, exists := reg.Find(featureName)
 		if !exists {
 			ui.Error(fmt.Sprintf("Feature '%s' not found in registry", featureName))
 			os.Exit(1)
 		}
+		featureName = wt.Normalized
 
 		val, ok := wt.ComputedVars[varName]
+		if !ok {
+			val, ok = hostEnvValue(cfg, workCfg, wt, varName)
+		}
 		if !ok {
 			ui.Error(fmt.Sprintf("Variable '%s' not found for feature '%s'", varName, featureName))
 			os.Exit(1)
@@ -76,6 +83,9 @@ func runGetEnv(cmd *cobra.Command, args []string) {
 		}
 
 		val, ok := vars[varName]
+		if !ok {
+			val, ok = featureHostEnvValue(instance.Feature, varName)
+		}
 		if !ok {
 			ui.Error(fmt.Sprintf("Variable '%s' not found in .worktree-env", varName))
 			os.Exit(1)
@@ -84,3 +94,36 @@ func runGetEnv(cmd *cobra.Command, args []string) {
 		fmt.Println(val)
 	}
 }
+
+// hostEnvValue resolves a var read from the host environment ({env:VAR}),
+// which the registry and .worktree-env never hold
+func hostEnvValue(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, varName string) (string, bool) {
+	if !workCfg.HostEnvVarNames()[varName] {
+		return "", false
+	}
+	envVars, _ := featureEnvVars(workCfg, wt, wt.Normalized, cfg.WorktreeFeaturePath(wt.Normalized))
+	val, ok := envVars[varName]
+	return val, ok
+}
+
+// featureHostEnvValue is hostEnvValue for a feature detected from the current
+// directory, loading the config and registry only when needed
+func featureHostEnvValue(featureName, varName string) (string, bool) {
+	cfg, err := config.New()
+	if err != nil {
+		return "", false
+	}
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	if err != nil || !workCfg.HostEnvVarNames()[varName] {
+		return "", false
+	}
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	if err != nil {
+		return "", false
+	}
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		return "", false
+	}
+	return hostEnvValue(cfg, workCfg, wt, varName)
+}
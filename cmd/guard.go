@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// guardCrossFeature protects destructive commands from targeting the wrong feature.
+// When the current directory belongs to a feature worktree (ambient feature from
+// .worktree-instance) and featureName names a different feature, the user must
+// confirm before continuing. Declining, or a non-interactive stdin, exits with 1.
+// force skips the check.
+func guardCrossFeature(action, featureName string, force bool) {
+	if force {
+		return
+	}
+
+	instance, err := config.DetectInstance()
+	if err != nil || instance.Feature == "" || instance.Feature == featureName {
+		return
+	}
+
+	ui.Warning(fmt.Sprintf("You are inside feature '%s' but about to %s '%s'", instance.Feature, action, featureName))
+	if !ui.Confirm(fmt.Sprintf("Continue with '%s'?", featureName), false) {
+		ui.NewLine()
+		ui.Info("Cancelled (use --force to skip this check)")
+		os.Exit(1)
+	}
+}
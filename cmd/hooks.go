@@ -3,35 +3,128 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"time"
 
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/exec"
+	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 )
 
-// runHookCommand executes a lifecycle hook command (pre/post for start, stop, restart).
-// Hook failures are non-fatal: they print a warning but do not exit.
-// Returns true if the command succeeded or was skipped (empty).
-func runHookCommand(label, command, workDir string, envList []string) bool {
+// hookRunner executes a feature's start commands and lifecycle hooks (pre/post
+// for start, stop, restart) through pkg/exec, which captures their output in
+// the feature's .logs directory. Hooks follow their project's hooks: policy.
+// finish records every run in the registry for worktree logs --runs.
+type hookRunner struct {
+	runner  *exec.Runner
+	reg     *registry.Registry
+	wt      *registry.Worktree
+	results []exec.Result
+}
+
+// newHookRunner returns a runner for the feature wt of reg
+func newHookRunner(featureDir string, reg *registry.Registry, wt *registry.Worktree) *hookRunner {
+	return &hookRunner{runner: exec.New(featureDir), reg: reg, wt: wt}
+}
+
+// start runs a project's start_command; what a failure means is up to the caller
+func (r *hookRunner) start(projectName, command, workDir string, envList []string) error {
+	if command == "" {
+		return nil
+	}
+	result, err := r.runner.Run(projectName+".start", command, workDir, envList, 0)
+	r.results = append(r.results, result)
+	return err
+}
+
+// run executes a project's hook, e.g. start_post, retrying it when the policy
+// says so. A failure or timeout prints a warning, unless the policy is abort,
+// which records the runs so far and exits. Returns true if the command
+// succeeded or was skipped (empty).
+func (r *hookRunner) run(projectName, hook, command string, policy config.HookPolicy, workDir string, envList []string) bool {
 	if command == "" {
 		return true
 	}
 
-	ui.Loading(fmt.Sprintf("Running %s...", label))
-	ui.NewLine()
+	label := fmt.Sprintf("%s: %s_command", projectName, hook)
+	start := time.Now()
+	var result exec.Result
+	var err error
+	attempts := policy.Attempts()
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt == 1 {
+			ui.Loading(fmt.Sprintf("Running %s...", label))
+		} else {
+			ui.Loading(fmt.Sprintf("Retrying %s (attempt %d of %d)...", label, attempt, attempts))
+		}
+		ui.NewLine()
 
-	hookCmd := exec.Command("sh", "-c", command)
-	hookCmd.Dir = workDir
-	hookCmd.Env = envList
-	hookCmd.Stdout = os.Stdout
-	hookCmd.Stderr = os.Stderr
+		result, err = r.runner.Run(projectName+"."+hook, command, workDir, envList, policy.GetTimeout())
+		if attempt > 1 {
+			result.Attempts = attempt
+		}
+		if err == nil {
+			break
+		}
+		if attempt < attempts {
+			ui.Warning(fmt.Sprintf("%s failed: %v", label, err))
+		}
+	}
+	result.Started = start
+	result.DurationMS = time.Since(start).Milliseconds()
+	r.results = append(r.results, result)
 
-	if err := hookCmd.Run(); err != nil {
-		ui.Warning(fmt.Sprintf("%s failed: %v", label, err))
+	if err == nil {
+		ui.Success(fmt.Sprintf("%s completed in %s", label, formatRunDuration(result.Duration())))
+		ui.NewLine()
+		return true
+	}
+
+	if policy.GetOnFailure() == config.HookOnFailureAbort {
+		ui.Error(fmt.Sprintf("%s failed: %v", label, err))
 		ui.Info(fmt.Sprintf("You can run manually: %s", command))
-		return false
+		ui.NewLine()
+		r.finish()
+		os.Exit(1)
+	}
+	ui.Warning(fmt.Sprintf("%s failed: %v", label, err))
+	ui.Info(fmt.Sprintf("You can run manually: %s", command))
+	return false
+}
+
+// finish records the runs in the registry and lists each with its duration
+// and outcome
+func (r *hookRunner) finish() {
+	if len(r.results) == 0 {
+		return
 	}
 
-	ui.Success(fmt.Sprintf("%s completed", label))
+	for _, result := range r.results {
+		r.wt.RecordRun(result)
+	}
+	if err := r.reg.Save(); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to record command runs in registry: %v", err))
+	}
+
+	ui.Section("Commands:")
+	for _, result := range r.results {
+		status := formatRunDuration(result.Duration())
+		if result.Attempts > 1 {
+			status += fmt.Sprintf(", %d attempts", result.Attempts)
+		}
+		if result.Failed() {
+			status += fmt.Sprintf(", failed: %s", result.Error)
+		}
+		ui.PrintStatusLine(result.Name, status)
+	}
 	ui.NewLine()
-	return true
+	r.results = nil
+}
+
+// formatRunDuration rounds a command's duration for display
+func formatRunDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
 }
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var hostsPrint bool
+
+var hostsCmd = &cobra.Command{
+	Use:   "hosts",
+	Short: "Sync the hosts file entries of all features",
+	Long: `Write a marked block per registered feature into the hosts file configured
+under hosts: (default /etc/hosts), mapping its stable hostname, e.g.
+myproject-feature-user-auth.local, to 127.0.0.1. Blocks of features that no
+longer exist are removed; lines outside the blocks are left alone.
+
+new-feature and remove update the block of their feature automatically. Run
+this command after changing the hosts: or proxy: config, or when those updates
+failed because the hosts file is not writable.
+
+Value templates reference a feature's hostname as {feature_host}, e.g.
+  OAUTH_REDIRECT_URI:
+    value: "http://{feature_host}/auth/callback"
+    env: "OAUTH_REDIRECT_URI"
+
+Examples:
+  sudo worktree hosts          # Sync /etc/hosts
+  worktree hosts --print       # Print the entries instead of writing them`,
+	Args: cobra.NoArgs,
+	Run:  runHosts,
+}
+
+func init() {
+	hostsCmd.Flags().BoolVar(&hostsPrint, "print", false, "print the entries instead of writing the hosts file")
+}
+
+func runHosts(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	if !workCfg.Hosts.Enabled {
+		ui.Error("Hosts entries are not enabled")
+		ui.Info("Set hosts: {enabled: true} in .worktree.yml")
+		os.Exit(1)
+	}
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	features := make(map[string][]string)
+	for _, wt := range reg.List() {
+		features[wt.Normalized] = workCfg.FeatureHostnames(wt.Normalized)
+	}
+
+	if hostsPrint {
+		names := make([]string, 0, len(features))
+		for name := range features {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s %s\n", workCfg.Hosts.GetAddress(), strings.Join(features[name], " "))
+		}
+		return
+	}
+
+	changed, err := workCfg.UpdateHostsFile(features, true)
+	if err != nil {
+		reportHostsError(workCfg, err)
+		os.Exit(1)
+	}
+	if changed {
+		ui.Success(fmt.Sprintf("Updated %s (%d features)", workCfg.Hosts.GetFile(), len(features)))
+	} else {
+		ui.Info(fmt.Sprintf("%s is up to date", workCfg.Hosts.GetFile()))
+	}
+}
+
+// updateFeatureHosts writes (hostnames set) or removes (nil) a feature's
+// hosts block when hosts entries are enabled
+func updateFeatureHosts(workCfg *config.WorktreeConfig, featureName string, hostnames []string) {
+	if !workCfg.Hosts.Enabled {
+		return
+	}
+	changed, err := workCfg.UpdateHostsFile(map[string][]string{featureName: hostnames}, false)
+	if err != nil {
+		reportHostsError(workCfg, err)
+		return
+	}
+	if !changed {
+		return
+	}
+	if hostnames == nil {
+		ui.CheckMark(fmt.Sprintf("Removed hosts entries from %s", workCfg.Hosts.GetFile()))
+	} else {
+		ui.CheckMark(fmt.Sprintf("Hosts entries added to %s (%s)", workCfg.Hosts.GetFile(), strings.Join(hostnames, ", ")))
+	}
+}
+
+// reportHostsError warns about a failed hosts file update, with a hint when
+// the file needs root
+func reportHostsError(workCfg *config.WorktreeConfig, err error) {
+	ui.Warning(fmt.Sprintf("Failed to update hosts entries: %v", err))
+	if errors.Is(err, os.ErrPermission) {
+		ui.Info("💡 Run 'sudo worktree hosts' to sync them, or make " + workCfg.Hosts.GetFile() + " writable")
+	}
+}
+
+// writeComposeOverrides renders the extra_hosts of each project into its
+// compose override (.worktree-compose.yml)
+func writeComposeOverrides(workCfg *config.WorktreeConfig, featureDir, featureName string, projects []string, envVars map[string]string) {
+	for _, projectName := range projects {
+		if _, err := workCfg.WriteComposeOverride(featureDir, projectName, featureName, envVars); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to write compose override for %s: %v", projectName, err))
+		}
+	}
+}
+
+// composeFileEnv returns COMPOSE_FILE for a project's commands when it has a
+// compose override, so compose loads the extra_hosts on top of its own files
+func composeFileEnv(workCfg *config.WorktreeConfig, featureDir, projectName string) []string {
+	files, err := workCfg.ComposeFiles(featureDir, projectName)
+	if err != nil {
+		ui.Warning(err.Error())
+		return nil
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	return []string{"COMPOSE_FILE=" + strings.Join(files, string(os.PathListSeparator))}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show the worktree version and the tools it detected",
+	Long: `Show the worktree version, the installed git version with the git
+features it supports, and the container runtime in use.
+
+Include this output when reporting a bug. Works outside a project too.
+
+Example:
+  worktree info`,
+	Args: cobra.NoArgs,
+	Run:  runInfo,
+}
+
+func runInfo(cmd *cobra.Command, args []string) {
+	ui.Section("Worktree")
+	fmt.Printf("  Version: %s\n", rootCmd.Version)
+	fmt.Printf("  Platform: %s/%s (%s)\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+
+	if cfg, err := config.New(); err == nil {
+		fmt.Printf("  Project root: %s\n", cfg.ProjectRoot)
+		if workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot); err == nil {
+			configureContainerRuntime(workCfg)
+		}
+	}
+
+	ui.Section("Git")
+	v, err := git.DetectVersion()
+	if err != nil {
+		ui.Error(err.Error())
+	} else {
+		fmt.Printf("  Version: %s (minimum %d.%d)\n", v, git.MinVersion.Major, git.MinVersion.Minor)
+		for _, c := range git.Capabilities {
+			state := "yes"
+			if !v.AtLeast(c.Since) {
+				state = fmt.Sprintf("no, needs git %d.%d", c.Since.Major, c.Since.Minor)
+			}
+			fmt.Printf("  %s: %s\n", c.Name, state)
+		}
+	}
+
+	ui.Section("Container runtime")
+	fmt.Printf("  Runtime: %s\n", docker.Current().Name)
+	if out, err := docker.Current().Command("--version").Output(); err == nil {
+		fmt.Printf("  Version: %s", out)
+	} else {
+		fmt.Printf("  Version: not installed\n")
+	}
+}
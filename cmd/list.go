@@ -24,7 +24,7 @@ Shows:
 - Feature name
 - Branch name
 - Project status for each configured project (clean/modified)
-- Running status
+- Running status and cumulative runtime
 - Port mapping
 
 Example:
@@ -39,7 +39,7 @@ func runList(cmd *cobra.Command, args []string) {
 	checkError(err)
 
 	// Display header
-	fmt.Printf("%s Worktree Features:\n\n", "📋")
+	ui.Printf("%s Worktree Features:\n\n", "📋")
 
 	// Check if worktrees directory exists
 	if _, err := os.Stat(cfg.WorktreeDir); os.IsNotExist(err) {
@@ -52,6 +52,7 @@ func runList(cmd *cobra.Command, args []string) {
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
@@ -78,7 +79,7 @@ func runList(cmd *cobra.Command, args []string) {
 
 		// Check if worktree directory still exists
 		if !cfg.WorktreeExists(featureName) {
-			fmt.Printf("⚠️  %s: directory not found (orphaned registry entry)\n\n", featureName)
+			ui.Printf("⚠️  %s: directory not found (orphaned registry entry)\n\n", featureName)
 			continue
 		}
 
@@ -114,7 +115,7 @@ func runList(cmd *cobra.Command, args []string) {
 
 			// Check if worktree exists
 			if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
-				fmt.Printf("  %s: ⚠️  worktree not found\n", projectName)
+				ui.Printf("  %s: ⚠️  worktree not found\n", projectName)
 				continue
 			}
 
@@ -122,17 +123,20 @@ func runList(cmd *cobra.Command, args []string) {
 			count, _ := git.GetUncommittedChangesCount(worktreePath)
 
 			if changes {
-				fmt.Printf("  %s: ⚠️  modified (%d uncommitted changes)\n", projectName, count)
+				ui.Printf("  %s: ⚠️  modified (%d uncommitted changes)\n", projectName, count)
 			} else {
-				fmt.Printf("  %s: ✅ clean\n", projectName)
+				ui.Printf("  %s: ✅ clean\n", projectName)
 			}
 		}
 
 		// Running status
 		if running {
-			fmt.Printf("  Status:   🟢 Running\n")
+			ui.Printf("  Status:   🟢 Running\n")
 		} else {
-			fmt.Printf("  Status:   ⚪ Stopped\n")
+			ui.Printf("  Status:   ⚪ Stopped\n")
+		}
+		if runtime := featureRuntime(featureDir); runtime != "" {
+			fmt.Printf("  Runtime:  %s\n", runtime)
 		}
 
 		// Show allocated port numbers sorted alphabetically
@@ -4,34 +4,64 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	logsFollow bool
+	logsTail   int
+	logsRuns   bool
+	logsRun    string
+)
+
 var logsCmd = &cobra.Command{
 	Use:   "logs <feature-name> [project-name]",
-	Short: "Show logs for a feature",
-	Long: `Show logs for a specific feature worktree.
+	Short: "Show container logs for a feature",
+	Long: `Show container logs for a specific feature worktree.
 
 This command:
 1. Validates the feature exists
-2. Executes 'make app-logs' in the specified project directory (defaults to first project)
-3. Follows logs in real-time (Ctrl+C to exit)
+2. Looks up each project's compose project name from the registry
+3. Runs 'docker compose logs' (or the podman equivalent) in the project directory
+
+Without a project name, logs for every docker project in the feature are shown.
+Projects using the process executor have no container logs and are skipped.
+
+Start commands and lifecycle hooks run by start, stop, restart and new-feature
+have their output captured in worktrees/<feature>/.logs/. --runs lists the
+last run of each (exit code, duration, log file); --run prints the captured
+output of one, e.g. backend.start_post.
 
 Examples:
-  worktree logs feature-user-auth              # Use first project
-  worktree logs feature-user-auth backend      # Specify project
-  worktree logs feature-reports frontend`,
+  worktree logs feature-user-auth                    # All projects
+  worktree logs feature-user-auth backend            # Single project
+  worktree logs feature-user-auth backend --follow   # Follow (Ctrl+C to exit)
+  worktree logs feature-reports --tail 100           # Last 100 lines per service
+  worktree logs feature-user-auth --runs             # Recorded command runs
+  worktree logs feature-user-auth --run backend.start_post`,
 	Args: cobra.RangeArgs(1, 2),
 	Run:  runLogs,
 }
 
+func init() {
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "follow log output")
+	logsCmd.Flags().IntVar(&logsTail, "tail", -1, "number of lines to show from the end of the logs per service (default: all)")
+	logsCmd.Flags().BoolVar(&logsRuns, "runs", false, "list the recorded start command and hook runs")
+	logsCmd.Flags().StringVar(&logsRun, "run", "", "print the captured output of a command run, e.g. backend.start_post")
+}
+
 func runLogs(cmd *cobra.Command, args []string) {
-	featureName := args[0]
+	featureName := registry.NormalizeBranchName(args[0])
 	projectName := ""
 	if len(args) > 1 {
 		projectName = args[1]
@@ -44,13 +74,14 @@ func runLogs(cmd *cobra.Command, args []string) {
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		fmt.Println("\nAvailable features:")
@@ -59,6 +90,7 @@ func runLogs(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Check if worktree exists
 	if !cfg.WorktreeExists(featureName) {
@@ -66,64 +98,162 @@ func runLogs(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Determine which project to use
-	if projectName == "" {
-		// Default to first project in the worktree
-		if len(wt.Projects) == 0 {
-			ui.Error("No projects found in worktree")
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+
+	if logsRuns || logsRun != "" {
+		showCommandRuns(wt, featureDir, projectName)
+		return
+	}
+
+	// Determine which projects to show
+	projects := wt.Projects
+	if projectName != "" {
+		if _, exists := workCfg.Projects[projectName]; !exists || !slices.Contains(wt.Projects, projectName) {
+			ui.Error(fmt.Sprintf("Project '%s' not found in feature '%s'", projectName, featureName))
+			fmt.Println("\nAvailable projects:")
+			for _, p := range wt.Projects {
+				fmt.Printf("  - %s\n", p)
+			}
 			os.Exit(1)
 		}
-		projectName = wt.Projects[0]
+		projects = []string{projectName}
 	}
 
-	// Validate project exists in configuration
-	project, exists := workCfg.Projects[projectName]
-	if !exists {
-		ui.Error(fmt.Sprintf("Project '%s' not found in configuration", projectName))
-		fmt.Println("\nAvailable projects:")
-		for _, p := range wt.Projects {
-			fmt.Printf("  - %s\n", p)
-		}
-		os.Exit(1)
+	// Compose needs the feature's env to interpolate compose files (ports, INSTANCE, ...)
+	envList := buildStopEnvList(workCfg, wt, featureName, featureDir)
+
+	logCmds := composeLogCommands(workCfg, wt, featureName, featureDir, projects, envList, buildComposeLogsArgs(logsFollow, logsTail, ""))
+	if len(logCmds) == 0 {
+		ui.Warning("No docker projects to show logs for")
+		return
 	}
 
 	// Display header
-	ui.Info(fmt.Sprintf("Showing logs for Feature: %s (Project: %s) - Ctrl+C to exit...", featureName, projectName))
+	hint := ""
+	if logsFollow {
+		hint = " - Ctrl+C to exit..."
+	}
+	ui.Info(fmt.Sprintf("Showing logs for Feature: %s%s", featureName, hint))
 	ui.Info(fmt.Sprintf("Branch: %s", wt.Branch))
 	ui.NewLine()
 
-	// Get project directory
-	featureDir := cfg.WorktreeFeaturePath(featureName)
-	projectDir := featureDir + "/" + project.Dir
+	if !streamLogs(logCmds, logsFollow) {
+		os.Exit(1)
+	}
+}
+
+// composeLogCommands builds a compose logs command with args for every docker
+// project of the feature, skipping projects of other executors
+func composeLogCommands(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName, featureDir string, projects, envList, args []string) []*exec.Cmd {
+	var logCmds []*exec.Cmd
+	for _, name := range projects {
+		project, ok := workCfg.Projects[name]
+		if !ok {
+			continue
+		}
+		if project.GetExecutor() != "docker" {
+			ui.Info(fmt.Sprintf("Skipping %s (%s executor has no container logs)", name, project.GetExecutor()))
+			continue
+		}
+
+		composeProject := wt.GetComposeProject(name)
+		if composeProject == "" {
+			composeProject = fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, featureName, name)
+		}
 
-	// Export environment variables for compose project
-	envVars := map[string]string{
-		"COMPOSE_PROJECT_NAME": wt.GetComposeProject(projectName),
+		logCmd := docker.Current().ComposeCommand(append([]string{"-p", composeProject}, args...)...)
+		logCmd.Dir = featureDir + "/" + project.Dir
+		logCmd.Env = append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
+		logCmd.Stdout = os.Stdout
+		logCmd.Stderr = os.Stderr
+		logCmds = append(logCmds, logCmd)
 	}
-	for service, port := range wt.Ports {
-		envVars[service] = fmt.Sprintf("%d", port)
+	return logCmds
+}
+
+// streamLogs runs compose logs commands and reports whether all succeeded.
+// Sequential output keeps static logs readable; following needs all streams at once.
+func streamLogs(logCmds []*exec.Cmd, follow bool) bool {
+	failed := false
+	if follow && len(logCmds) > 1 {
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for _, c := range logCmds {
+			wg.Add(1)
+			go func(c *exec.Cmd) {
+				defer wg.Done()
+				if err := runLogCommand(c); err != nil {
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}(c)
+		}
+		wg.Wait()
+	} else {
+		for _, c := range logCmds {
+			if err := runLogCommand(c); err != nil {
+				failed = true
+			}
+		}
 	}
 
-	envList := os.Environ()
-	for key, value := range envVars {
-		envList = append(envList, fmt.Sprintf("%s=%s", key, value))
+	return !failed
+}
+
+// showCommandRuns lists the feature's recorded command runs (of one project
+// when projectName is set), or prints the captured output of --run
+func showCommandRuns(wt *registry.Worktree, featureDir, projectName string) {
+	if logsRun != "" {
+		run, ok := wt.Runs[logsRun]
+		if !ok || run.Log == "" {
+			ui.Error(fmt.Sprintf("No captured output for '%s'", logsRun))
+			ui.Info("List recorded runs with: worktree logs " + wt.Normalized + " --runs")
+			os.Exit(1)
+		}
+		data, err := os.ReadFile(filepath.Join(featureDir, run.Log))
+		checkError(err)
+		os.Stdout.Write(data)
+		return
 	}
 
-	// Execute make app-logs in project directory
-	makeCmd := exec.Command("make", "app-logs")
-	makeCmd.Dir = projectDir
-	makeCmd.Env = envList
-	makeCmd.Stdout = os.Stdout
-	makeCmd.Stderr = os.Stderr
-	makeCmd.Stdin = os.Stdin
+	runs := wt.ListRuns(projectName)
+	if len(runs) == 0 {
+		ui.Info("No recorded command runs")
+		return
+	}
+
+	fmt.Printf("%-24s %-5s %-10s %-20s %s\n", "COMMAND", "EXIT", "DURATION", "STARTED", "LOG")
+	for _, run := range runs {
+		fmt.Printf("%-24s %-5d %-10s %-20s %s\n", run.Name, run.ExitCode, formatRunDuration(run.Duration()),
+			run.Started.Format("2006-01-02 15:04:05"), run.Log)
+	}
+}
+
+// buildComposeLogsArgs returns the compose arguments for showing a project's
+// logs; since limits them to entries after a timestamp when set
+func buildComposeLogsArgs(follow bool, tail int, since string) []string {
+	args := []string{"logs"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	if tail >= 0 {
+		args = append(args, "--tail", strconv.Itoa(tail))
+	}
+	if since != "" {
+		args = append(args, "--since", since)
+	}
+	return args
+}
 
-	if err := makeCmd.Run(); err != nil {
-		// Don't treat Ctrl+C as an error
+// runLogCommand runs a compose logs command, treating Ctrl+C as a normal exit
+func runLogCommand(c *exec.Cmd) error {
+	if err := c.Run(); err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 130 {
-			ui.NewLine()
-			return
+			return nil
 		}
-		ui.Error(fmt.Sprintf("Failed to show logs: %v", err))
-		os.Exit(1)
+		ui.Error(fmt.Sprintf("Failed to show logs in %s: %v", c.Dir, err))
+		return err
 	}
+	return nil
 }
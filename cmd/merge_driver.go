@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <project> <current> <path>",
+	Short: "Resolve a merge conflict in a generated file (run by git)",
+	Long: `Git merge driver for generated_files, registered when merge_driver is
+'regenerate'. Renders the generated file at <path> for the feature the
+worktree belongs to and writes it over <current> (git's %A), so rebases never
+stop on files worktree regenerates anyway. Outside a feature worktree the
+current version is kept.`,
+	Args:   cobra.ExactArgs(3),
+	Hidden: true,
+	Run:    runMergeDriver,
+}
+
+func runMergeDriver(cmd *cobra.Command, args []string) {
+	projectName, current, path := args[0], args[1], args[2]
+
+	content, err := regeneratedContent(projectName, path)
+	if err != nil {
+		// Keeping the current version never blocks the merge; the next start or regen rewrites the file
+		fmt.Fprintf(os.Stderr, "worktree merge-driver: keeping current %s: %v\n", path, err)
+		return
+	}
+	if err := os.WriteFile(current, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "worktree merge-driver: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// regeneratedContent renders a project's generated file for the feature of
+// the current directory
+func regeneratedContent(projectName, path string) (string, error) {
+	instance, err := config.DetectInstance()
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return "", err
+	}
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	if err != nil {
+		return "", err
+	}
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	if err != nil {
+		return "", err
+	}
+	wt, exists := reg.Get(instance.Feature)
+	if !exists {
+		return "", fmt.Errorf("feature '%s' not found in registry", instance.Feature)
+	}
+
+	envVars, _ := featureEnvVars(workCfg, wt, instance.Feature, cfg.WorktreeFeaturePath(instance.Feature))
+	content, ok, err := workCfg.RenderGeneratedFile(projectName, path, envVars)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("not a generated file of %s", projectName)
+	}
+	return content, nil
+}
+
+// registerMergeDriver registers the merge_driver for a project's generated
+// files in its repository. Does nothing unless merge_driver is set.
+func registerMergeDriver(workCfg *config.WorktreeConfig, projectName, worktreePath string) {
+	if workCfg.MergeDriver == "" || len(workCfg.GeneratedFiles[projectName]) == 0 {
+		return
+	}
+
+	executable, err := os.Executable()
+	if err == nil {
+		executable, err = filepath.EvalSymlinks(executable)
+	}
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to register merge driver for %s: %v", projectName, err))
+		return
+	}
+
+	description := fmt.Sprintf("worktree generated files (%s)", workCfg.MergeDriver)
+	if err := git.SetMergeDriver(worktreePath, config.MergeDriverName, description, workCfg.MergeDriverCommand(executable, projectName)); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to register merge driver for %s: %v", projectName, err))
+		return
+	}
+	if err := git.SetAttributes(worktreePath, config.MergeDriverName, workCfg.MergeDriverAttributes(projectName)); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to register merge driver for %s: %v", projectName, err))
+	}
+}
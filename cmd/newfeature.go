@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,10 +19,15 @@ import (
 )
 
 var (
-	preset       string
-	noFixturesNF bool
-	dryRun       bool
-	yoloModeNF   bool
+	preset          string
+	noFixturesNF    bool
+	dryRun          bool
+	yoloModeNF      bool
+	branchMapNF     map[string]string
+	fromNF          string
+	noStartNF       bool
+	takeoverNF      bool
+	skipPreflightNF bool
 )
 
 var newFeatureCmd = &cobra.Command{
@@ -37,12 +44,43 @@ This is a one-command setup for feature development that:
 6. Runs post-startup commands (if configured)
 7. Navigates Claude to the backend worktree
 
+Every project checks out <branch> unless its config sets branch_template
+(e.g. "{branch}-api") or --branch-map names a branch for it. The feature is
+still named and tracked after <branch>.
+
+Branches that do not exist yet are created from the project's main_branch,
+or from --from <ref> (a branch, tag or commit) when given.
+
+With --no-start, steps 5 and 6 are skipped: worktrees, ports, generated files
+and the registry entry are set up, and 'worktree start' launches the
+services later.
+
+With --takeover, an existing feature of the same name is recreated on its
+previous ports, so bookmarks, OAuth redirect URIs and client configs keep
+working. Each port must be free or published by the feature's own containers;
+those are stopped and the old worktrees removed (branches are kept). Refused
+when a worktree has uncommitted changes. Ports of newly configured services
+are allocated as usual.
+
+Before anything is created, a pre-flight check verifies that the container
+runtime's daemon and compose are available (when services will be started)
+and that the disk has min_free_disk_mb free (default 1024). --skip-preflight
+or WORKTREE_SKIP_PREFLIGHT=1 skips it.
+
+Start command output (image pulls, builds) is hidden behind a spinner and
+written to worktrees/<feature>/.worktree-start.log; the end of the log is
+shown if a start command fails. --verbose streams it to the terminal as well.
+
 Examples:
   worktree new-feature feature/user-auth              # Use default preset
   worktree new-feature feature/reports fullstack      # Use fullstack preset
   worktree new-feature feature/api backend            # Backend only
   worktree new-feature feature/ui --no-fixtures       # Skip fixtures
-  worktree new-feature feature/coverage --yolo        # Enable YOLO mode`,
+  worktree new-feature docs/readme --no-start         # Don't start services
+  worktree new-feature feature/coverage --yolo        # Enable YOLO mode
+  worktree new-feature feature/x --branch-map backend=feature/x-api,frontend=feature/x-ui
+  worktree new-feature hotfix/login --from release/2.4 # Branch off a release branch
+  worktree new-feature feature/user-auth --takeover   # Recreate on the same ports`,
 	Args: cobra.RangeArgs(1, 2),
 	Run:  runNewFeature,
 }
@@ -51,6 +89,11 @@ func init() {
 	newFeatureCmd.Flags().BoolVar(&noFixturesNF, "no-fixtures", false, "skip running fixtures")
 	newFeatureCmd.Flags().BoolVar(&dryRun, "dry-run", false, "preview changes without creating anything")
 	newFeatureCmd.Flags().BoolVar(&yoloModeNF, "yolo", false, "enable YOLO mode (Claude works autonomously)")
+	newFeatureCmd.Flags().StringToStringVar(&branchMapNF, "branch-map", nil, "per-project branches, e.g. backend=feature/x-api,frontend=feature/x-ui")
+	newFeatureCmd.Flags().BoolVar(&noStartNF, "no-start", false, "create the environment without starting services or running fixtures")
+	newFeatureCmd.Flags().StringVar(&fromNF, "from", "", "ref to create missing branches from (default: each project's main_branch)")
+	newFeatureCmd.Flags().BoolVar(&takeoverNF, "takeover", false, "replace an existing feature of the same name, keeping its ports")
+	newFeatureCmd.Flags().BoolVar(&skipPreflightNF, "skip-preflight", false, "skip the container runtime and disk space checks")
 }
 
 func runNewFeature(cmd *cobra.Command, args []string) {
@@ -60,31 +103,36 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 		presetName = args[1]
 	}
 
-	verbose, _ := cmd.Flags().GetBool("verbose")
-
-	// Normalize branch name to feature name
-	featureName := registry.NormalizeBranchName(branch)
-	if verbose {
-		ui.Info(fmt.Sprintf("Normalized branch '%s' to feature name '%s'", branch, featureName))
-	}
-
 	// Get configuration
 	cfg, err := config.New()
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
-	}
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
 
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
-	}
+	configureContainerRuntime(workCfg)
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
+
+	// Normalize branch name to feature name
+	featureName := registry.FeatureName(workCfg, branch)
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Normalized branch '%s' to feature name '%s'", branch, featureName))
 
 	// Get preset
 	presetCfg, err := workCfg.GetPreset(presetName)
 	checkError(err)
+	if presetName == "" {
+		presetName = workCfg.DefaultPreset
+	}
+
+	projectBranches, err := resolveProjectBranches(workCfg, presetCfg.Projects, branch, branchMapNF)
+	checkError(err)
+	branchBases, err := resolveBranchBases(cfg.ProjectRoot, workCfg, presetCfg.Projects, projectBranches, fromNF)
+	checkError(err)
+
+	if !dryRun {
+		runPreflight(cfg, workCfg, presetCfg.Projects, !noStartNF, skipPreflightNF)
+	}
 
 	// Display header
 	ui.Rocket(fmt.Sprintf("Setting up feature environment: %s", branch))
@@ -92,33 +140,47 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 	ui.Info(fmt.Sprintf("Preset: %s - %s", presetName, presetCfg.Description))
 	ui.NewLine()
 
+	// Load registry
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Loaded registry from: %s", cfg.WorktreeDir))
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Found %d existing worktrees", len(reg.Worktrees)))
+
 	// Check if worktree already exists
-	if cfg.WorktreeExists(featureName) {
+	var replaced *registry.Worktree
+	if takeoverNF {
+		replaced = planTakeover(cfg, workCfg, reg, featureName)
+	} else if cfg.WorktreeExists(featureName) {
 		ui.Error(fmt.Sprintf("Worktree '%s' already exists", featureName))
 		fmt.Println("\nRemove it first with:")
 		ui.PrintCommand(fmt.Sprintf("  worktree remove %s", featureName))
+		ui.Info("Or recreate it on the same ports with --takeover")
 		os.Exit(1)
 	}
 
-	// Load registry
-	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
-	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded registry from: %s", cfg.WorktreeDir))
-		ui.Info(fmt.Sprintf("Found %d existing worktrees", len(reg.Worktrees)))
-	}
-
 	// Allocate ports for all services
 	ui.Section("Allocating ports...")
 	services := workCfg.GetPortServiceNames()
-	if verbose {
-		ui.Info(fmt.Sprintf("Services requiring ports: %v", services))
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Services requiring ports: %v", services))
+	ports := make(map[string]int)
+	missing := services
+	if replaced != nil {
+		missing = nil
+		for _, service := range services {
+			if port, ok := replaced.Ports[service]; ok {
+				ports[service] = port
+			} else {
+				missing = append(missing, service)
+			}
+		}
 	}
-	ports, err := reg.AllocatePorts(services)
+	// The replaced feature is still registered, so new ports avoid its ports
+	allocated, err := allocatePorts(cfg, workCfg, reg, missing, featureName)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Allocated ports: %v", ports))
+	for service, port := range allocated {
+		ports[service] = port
 	}
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Allocated ports: %v", ports))
 
 	// Calculate INSTANCE from the first allocated ranged port
 	instancePortName, err := workCfg.GetInstancePortName()
@@ -129,16 +191,28 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 	instance := ports[instancePortName] - basePort
 
 	// Display allocated ports
-	ui.CheckMark("Ports allocated")
+	if replaced != nil {
+		ui.CheckMark(fmt.Sprintf("Ports of the previous %s reused", featureName))
+	} else {
+		ui.CheckMark("Ports allocated")
+	}
 	ui.Info(fmt.Sprintf("Instance: %d", instance))
 	ui.NewLine()
 
 	// If dry-run, display preview and exit
 	if dryRun {
-		displayDryRunPreview(featureName, instance, ports, workCfg, presetCfg, cfg)
+		displayDryRunPreview(featureName, instance, ports, workCfg, presetCfg, cfg, projectBranches, branchBases)
 		os.Exit(0)
 	}
 
+	if replaced != nil {
+		ui.Section(fmt.Sprintf("Replacing previous %s...", featureName))
+		stopFeatureServices(cfg, workCfg, replaced)
+		removeFeatureFiles(cfg, workCfg, reg, replaced)
+		checkError(reg.Save())
+		ui.NewLine()
+	}
+
 	// Create feature directory
 	featureDir := cfg.WorktreeFeaturePath(featureName)
 	if err := os.MkdirAll(featureDir, 0755); err != nil {
@@ -153,18 +227,32 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 
 		projectDir := cfg.ProjectRoot + "/" + project.Dir
 		worktreePath := featureDir + "/" + project.Dir
+		projectBranch := projectBranches[projectName]
 
-		if verbose {
-			ui.Info(fmt.Sprintf("Git worktree command: git worktree add %s %s", worktreePath, branch))
-		}
+		ui.Verbose(ui.ScopeGit, fmt.Sprintf("Git worktree command: git worktree add %s %s", worktreePath, projectBranch))
 
-		if err := git.CreateWorktree(projectDir, worktreePath, branch); err != nil {
+		base, newBranch := branchBases[projectName]
+		if err := git.CreateWorktree(projectDir, worktreePath, projectBranch, base); err != nil {
 			checkError(fmt.Errorf("failed to create %s worktree: %w", projectName, err))
 		}
-		ui.CheckMark(fmt.Sprintf("Created %s worktree", projectName))
+		created := fmt.Sprintf("Created %s worktree", projectName)
+		if projectBranch != branch {
+			created += fmt.Sprintf(" (branch %s)", projectBranch)
+		}
+		if newBranch {
+			created += " " + describeBranchBase(base)
+		}
+		ui.CheckMark(created)
+
+		if project.Submodules {
+			initSubmodules(projectName, worktreePath)
+		}
 	}
 	ui.NewLine()
 
+	// Existing files in the way of symlinks are moved to a snapshot under .backup/
+	backup := config.NewBackup(featureDir)
+
 	// Create symlinks from configuration
 	if len(workCfg.Symlinks) > 0 {
 		ui.Section("Creating symlinks...")
@@ -186,12 +274,11 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 					}
 				} else {
 					// If it's a directory or file, back it up and remove
-					backupPath := targetPath + ".backup." + time.Now().Format("20060102-150405")
-					if err := os.Rename(targetPath, backupPath); err != nil {
+					if err := backup.Move(link.Target); err != nil {
 						ui.Warning(fmt.Sprintf("Failed to backup existing %s: %v", link.Target, err))
 						continue
 					} else {
-						ui.Info(fmt.Sprintf("Backed up existing %s", link.Target))
+						ui.Info(fmt.Sprintf("Backed up existing %s to %s", link.Target, backup.Dir()))
 					}
 				}
 			}
@@ -274,12 +361,11 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 					if info.Mode()&os.ModeSymlink != 0 {
 						os.Remove(targetPath)
 					} else {
-						backupPath := targetPath + ".backup." + time.Now().Format("20060102-150405")
-						if err := os.Rename(targetPath, backupPath); err != nil {
+						if err := backup.Move(project.Dir + "/" + link.Target); err != nil {
 							ui.Warning(fmt.Sprintf("[%s] Failed to backup %s: %v", projectName, link.Target, err))
 							continue
 						}
-						ui.Info(fmt.Sprintf("[%s] Backed up existing %s", projectName, link.Target))
+						ui.Info(fmt.Sprintf("[%s] Backed up existing %s to %s", projectName, link.Target, backup.Dir()))
 					}
 				}
 
@@ -331,15 +417,30 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 		composeProjects[projectName] = workCfg.ReplaceComposeProjectPlaceholders(template, featureName, projectName)
 	}
 
+	// Record only the project branches that differ from the feature branch
+	var branches map[string]string
+	for projectName, projectBranch := range projectBranches {
+		if projectBranch != branch {
+			if branches == nil {
+				branches = make(map[string]string)
+			}
+			branches[projectName] = projectBranch
+		}
+	}
+
 	// Add to registry
 	wt := &registry.Worktree{
 		Branch:          branch,
+		Branches:        branches,
 		Normalized:      featureName,
 		Created:         time.Now(),
 		Projects:        presetCfg.Projects,
 		Ports:           ports,
 		ComposeProjects: composeProjects,
 		YoloMode:        yoloModeNF,
+		Preset:          presetName,
+		BaseRef:         fromNF,
+		NoFixtures:      noFixturesNF,
 	}
 	if err := reg.Add(wt); err != nil {
 		checkError(err)
@@ -355,16 +456,23 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 	} else {
 		ui.CheckMark("Instance marker created")
 	}
+	if len(workCfg.InstanceEnv) > 0 {
+		if err := config.UpdateInstanceEnv(featureDir, workCfg.GetInstanceEnvNames()); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to record instance_env in marker: %v", err))
+		}
+	}
 	ui.NewLine()
 
 	// Export all environment variables (includes allocated ports + calculated values like INSTANCE, LOCALSTACK_EXT_*)
 	baseEnvVars := workCfg.ExportEnvVars(instance)
 	baseEnvVars["FEATURE_NAME"] = featureName
+	baseEnvVars["YOLO"] = strconv.FormatBool(wt.YoloMode)
 
 	// Override with actually allocated ports (in case of conflicts)
 	for service, port := range ports {
 		baseEnvVars[service] = fmt.Sprintf("%d", port)
 	}
+	config.AddPathVars(baseEnvVars, featureDir)
 
 	// Recompute value-template vars (e.g., GOOGLE_OAUTH_REDIRECT_URI) now that actual
 	// allocated ports are in baseEnvVars. Without this, they resolve against base port
@@ -389,11 +497,97 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 		if err := workCfg.GenerateFiles(projectName, featureDir, baseEnvVars); err != nil {
 			ui.Warning(fmt.Sprintf("Failed to generate files for %s: %v", projectName, err))
 		}
+		registerMergeDriver(workCfg, projectName, featureDir+"/"+workCfg.Projects[projectName].Dir)
+	}
+	writeFeatureFlags(workCfg, featureDir, presetCfg.Projects, baseEnvVars)
+	writeComposeOverrides(workCfg, featureDir, featureName, presetCfg.Projects, baseEnvVars)
+	for _, path := range writeWorkspace(workCfg, featureDir, featureName, presetCfg.Projects, workCfg.GetExportedVars(baseEnvVars)) {
+		ui.CheckMark(fmt.Sprintf("IDE configuration created (%s)", path))
+	}
+	if path, err := writeFeatureContext(cfg, workCfg, wt); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write %s: %v", contextFile, err))
+	} else {
+		ui.CheckMark(fmt.Sprintf("Feature context created (%s)", relToRoot(cfg.ProjectRoot, path)))
+	}
+
+	writeProxyConfig(cfg, workCfg, featureName, baseEnvVars)
+	updateFeatureHosts(workCfg, featureName, workCfg.FeatureHostnames(featureName))
+	sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventCreated, wt.Projects)
+
+	if noStartNF {
+		ui.Info("Skipping service startup (--no-start)")
+		ui.NewLine()
+	} else {
+		startNewFeatureServices(workCfg, presetCfg.Projects, reg, wt, featureName, featureDir, withSecretEnvVars(cfg.ProjectRoot, workCfg, baseEnvVars))
+		sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventStarted, wt.Projects)
+	}
+
+	// Get Claude working directory (from preset projects, not all projects)
+	claudeProject := getClaudeWorkingProject(workCfg, presetCfg.Projects)
+	claudePath := fmt.Sprintf("worktrees/%s/%s", featureName, workCfg.Projects[claudeProject].Dir)
+
+	// Success message
+	ui.Success("Feature environment ready!")
+	ui.NewLine()
+
+	// Navigate Claude
+	ui.PrintHeader("Claude is ready to work:")
+	ui.PrintStatusLine("  Working directory", claudePath)
+	ui.PrintStatusLine("  Feature name", featureName)
+
+	// Show YOLO mode status
+	if wt.YoloMode {
+		ui.PrintStatusLine("  YOLO Mode", "🚀 Enabled (autonomous mode)")
+	}
+	ui.NewLine()
+
+	// Show access URLs dynamically from config
+	displayServices := workCfg.GetDisplayableServices(ports)
+	if len(displayServices) > 0 {
+		for name, url := range displayServices {
+			ui.PrintStatusLine("  "+name, url)
+		}
+		ui.NewLine()
+	}
+
+	// Change to working directory
+	if err := os.Chdir(claudePath); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to change directory: %v", err))
+		ui.Info(fmt.Sprintf("Please manually cd to: %s", claudePath))
+	} else {
+		ui.Success(fmt.Sprintf("Navigated to %s", claudePath))
+		if !noStartNF {
+			// Give a moment for services to stabilize
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	if noStartNF {
+		ui.NewLine()
+		ui.Info(fmt.Sprintf("💡 Start services with: worktree start %s", featureName))
+	}
+	ui.Info(fmt.Sprintf("💡 Launch Claude with the feature's env: worktree claude %s", featureName))
+
+	ui.NewLine()
+}
+
+// startNewFeatureServices runs the start command of each project, then the
+// post-startup commands (fixtures) unless disabled
+func startNewFeatureServices(workCfg *config.WorktreeConfig, projects []string, reg *registry.Registry, wt *registry.Worktree, featureName, featureDir string, baseEnvVars map[string]string) {
+	// Start command output (compose pull/build) always goes to the start log;
+	// it is only streamed to the terminal in verbose mode
+	verbose := ui.IsVerboseFor(ui.ScopeDocker)
+	logFile, err := config.CreateStartLog(featureDir)
+	if err != nil {
+		ui.Warning(err.Error())
+	} else {
+		defer logFile.Close()
 	}
 
 	// Start services for each project
 	ui.Section("Starting services...")
-	for _, projectName := range presetCfg.Projects {
+	unhealthy := make(map[string]bool)
+	for _, projectName := range projects {
 		project := workCfg.Projects[projectName]
 
 		if project.StartCommand == "" {
@@ -401,8 +595,6 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 			continue
 		}
 
-		ui.Loading(fmt.Sprintf("Starting '%s' services...", projectName))
-
 		worktreePath := featureDir + "/" + project.Dir
 
 		// Build environment list with per-service COMPOSE_PROJECT_NAME
@@ -413,11 +605,10 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 		// Add service-specific compose project name
 		composeProject := wt.GetComposeProject(projectName)
 		envList = append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
+		envList = append(envList, composeFileEnv(workCfg, featureDir, projectName)...)
 
-		if verbose {
-			ui.Info(fmt.Sprintf("Starting %s with COMPOSE_PROJECT_NAME=%s", projectName, composeProject))
-			ui.Info(fmt.Sprintf("Start command: %s", project.StartCommand))
-		}
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("Starting %s with COMPOSE_PROJECT_NAME=%s", projectName, composeProject))
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("Start command: %s", project.StartCommand))
 
 		// Replace placeholders in start command
 		startCmd := project.StartCommand
@@ -428,11 +619,31 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 		makeCmd := exec.Command("sh", "-c", startCmd)
 		makeCmd.Dir = worktreePath
 		makeCmd.Env = envList
-		makeCmd.Stdout = os.Stdout
-		makeCmd.Stderr = os.Stderr
 
-		if err := makeCmd.Run(); err != nil {
+		var output io.Writer = io.Discard
+		if logFile != nil {
+			fmt.Fprintf(logFile, "==> %s: %s\n", projectName, startCmd)
+			output = logFile
+		}
+		var spinner *ui.Spinner
+		if verbose {
+			ui.Loading(fmt.Sprintf("Starting '%s' services...", projectName))
+			output = io.MultiWriter(os.Stdout, output)
+		} else {
+			spinner = ui.StartSpinner(fmt.Sprintf("Starting '%s' services...", projectName))
+		}
+		makeCmd.Stdout = output
+		makeCmd.Stderr = output
+
+		err := makeCmd.Run()
+		if spinner != nil {
+			spinner.Stop()
+		}
+		if err != nil {
 			ui.Warning(fmt.Sprintf("Failed to start %s: %v", projectName, err))
+			if !verbose && logFile != nil {
+				printLogTail(logFile.Name(), startLogTailLines)
+			}
 		} else {
 			// Verify containers are actually running (wait for startup)
 			time.Sleep(3 * time.Second)
@@ -441,13 +652,15 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 			if err != nil {
 				ui.Warning(fmt.Sprintf("Could not verify %s container status: %v", projectName, err))
 			} else {
-				// Check if any containers exited
+				// Check if any containers exited; oneshot services may exit cleanly
 				hasFailures := false
 				for service, status := range containerStatus {
-					if strings.Contains(strings.ToLower(status), "exited") {
-						ui.Warning(fmt.Sprintf("%s service '%s' exited: %s", projectName, service, status))
-						hasFailures = true
+					code, exited := docker.ExitCode(status)
+					if !exited || (code == 0 && workCfg.IsOneshotService(service)) {
+						continue
 					}
+					ui.Warning(fmt.Sprintf("%s service '%s' exited: %s", projectName, service, status))
+					hasFailures = true
 				}
 
 				if !hasFailures && len(containerStatus) > 0 {
@@ -456,24 +669,30 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 					ui.Warning(fmt.Sprintf("No containers found for %s", projectName))
 				}
 			}
+
+			if !waitForHealthy(workCfg, projectName, worktreePath, baseEnvVars, envList) {
+				ui.Warning(fmt.Sprintf("%s did not become healthy, skipping its post-command", projectName))
+				unhealthy[projectName] = true
+			}
 		}
 	}
+	if logFile != nil {
+		ui.Info(fmt.Sprintf("Full start output: %s", logFile.Name()))
+	}
 	ui.NewLine()
 
 	// Run post-commands (fixtures, seed data, etc.)
 	if workCfg.AutoFixtures && !noFixturesNF {
 		ui.Section("Running post-startup commands...")
-		for _, projectName := range presetCfg.Projects {
+		hooks := newHookRunner(featureDir, reg, wt)
+		for _, projectName := range projects {
 			project := workCfg.Projects[projectName]
 
-			if project.StartPostCommand == "" {
+			if project.StartPostCommand == "" || unhealthy[projectName] {
 				continue
 			}
 
-			ui.Loading(fmt.Sprintf("Running %s post-command...", projectName))
-
 			worktreePath := featureDir + "/" + project.Dir
-			postCmd := project.StartPostCommand
 
 			// Build environment list with per-service COMPOSE_PROJECT_NAME
 			envList := os.Environ()
@@ -482,65 +701,16 @@ func runNewFeature(cmd *cobra.Command, args []string) {
 			}
 			// Add service-specific compose project name
 			envList = append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", wt.GetComposeProject(projectName)))
+			envList = append(envList, composeFileEnv(workCfg, featureDir, projectName)...)
 
-			cmd := exec.Command("sh", "-c", postCmd)
-			cmd.Dir = worktreePath
-			cmd.Env = envList
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-
-			if err := cmd.Run(); err != nil {
-				ui.Warning(fmt.Sprintf("Failed to run post-command: %v", err))
-			} else {
-				ui.CheckMark(fmt.Sprintf("Post-command completed for %s", projectName))
-			}
+			hooks.run(projectName, "start_post", project.StartPostCommand, project.Hook("start_post"), worktreePath, envList)
 		}
-		ui.NewLine()
-	}
-
-	// Get Claude working directory (from preset projects, not all projects)
-	claudeProject := getClaudeWorkingProject(workCfg, presetCfg.Projects)
-	claudePath := fmt.Sprintf("worktrees/%s/%s", featureName, workCfg.Projects[claudeProject].Dir)
-
-	// Success message
-	ui.Success("Feature environment ready!")
-	ui.NewLine()
-
-	// Navigate Claude
-	ui.PrintHeader("Claude is ready to work:")
-	ui.PrintStatusLine("  Working directory", claudePath)
-	ui.PrintStatusLine("  Feature name", featureName)
-
-	// Show YOLO mode status
-	if wt.YoloMode {
-		ui.PrintStatusLine("  YOLO Mode", "🚀 Enabled (autonomous mode)")
-	}
-	ui.NewLine()
-
-	// Show access URLs dynamically from config
-	displayServices := workCfg.GetDisplayableServices(ports)
-	if len(displayServices) > 0 {
-		for name, url := range displayServices {
-			ui.PrintStatusLine("  "+name, url)
-		}
-		ui.NewLine()
+		hooks.finish()
 	}
-
-	// Change to working directory
-	if err := os.Chdir(claudePath); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to change directory: %v", err))
-		ui.Info(fmt.Sprintf("Please manually cd to: %s", claudePath))
-	} else {
-		ui.Success(fmt.Sprintf("Navigated to %s", claudePath))
-		// Give a moment for services to stabilize
-		time.Sleep(2 * time.Second)
-	}
-
-	ui.NewLine()
 }
 
 // displayDryRunPreview shows what would be created without actually creating it
-func displayDryRunPreview(featureName string, instance int, ports map[string]int, workCfg *config.WorktreeConfig, presetCfg *config.PresetConfig, cfg *config.Config) {
+func displayDryRunPreview(featureName string, instance int, ports map[string]int, workCfg *config.WorktreeConfig, presetCfg *config.PresetConfig, cfg *config.Config, projectBranches, branchBases map[string]string) {
 	ui.Section("🔍 Dry Run - Preview Mode")
 
 	// Port allocation preview
@@ -564,7 +734,11 @@ func displayDryRunPreview(featureName string, instance int, ports map[string]int
 	for _, projectName := range presetCfg.Projects {
 		project := workCfg.Projects[projectName]
 		worktreePath := featureDir + "/" + project.Dir
-		ui.CheckMark(worktreePath)
+		line := fmt.Sprintf("%s (branch %s)", worktreePath, projectBranches[projectName])
+		if base, newBranch := branchBases[projectName]; newBranch {
+			line += " " + describeBranchBase(base)
+		}
+		ui.CheckMark(line)
 	}
 	ui.NewLine()
 
@@ -606,17 +780,22 @@ func displayDryRunPreview(featureName string, instance int, ports map[string]int
 	}
 
 	// Services to start
-	fmt.Println("Services to start:")
-	for _, projectName := range presetCfg.Projects {
-		project := workCfg.Projects[projectName]
-		if project.StartCommand != "" {
-			ui.CheckMark(fmt.Sprintf("%s: %s", projectName, project.StartCommand))
+	if noStartNF {
+		fmt.Println("Services to start: none (--no-start)")
+		ui.NewLine()
+	} else {
+		fmt.Println("Services to start:")
+		for _, projectName := range presetCfg.Projects {
+			project := workCfg.Projects[projectName]
+			if project.StartCommand != "" {
+				ui.CheckMark(fmt.Sprintf("%s: %s", projectName, project.StartCommand))
+			}
 		}
+		ui.NewLine()
 	}
-	ui.NewLine()
 
 	// Post commands
-	if workCfg.AutoFixtures && !noFixturesNF {
+	if workCfg.AutoFixtures && !noFixturesNF && !noStartNF {
 		fmt.Println("Post-startup commands:")
 		for _, projectName := range presetCfg.Projects {
 			project := workCfg.Projects[projectName]
@@ -628,5 +807,93 @@ func displayDryRunPreview(featureName string, instance int, ports map[string]int
 	}
 
 	ui.Info("This is a dry run - no changes were made")
-	fmt.Println("💡 Run without --dry-run to create the feature")
+	ui.Println("💡 Run without --dry-run to create the feature")
+}
+
+// initSubmodules checks out the submodules of a new worktree, showing git's
+// progress. Failures are warnings: the worktree itself is usable.
+func initSubmodules(projectName, worktreePath string) {
+	ui.Loading(fmt.Sprintf("Initializing %s submodules...", projectName))
+	if err := git.UpdateSubmodules(worktreePath, os.Stdout); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to initialize %s submodules: %v", projectName, err))
+		ui.Info(fmt.Sprintf("💡 Retry with: git -C %s submodule update --init --recursive", worktreePath))
+		return
+	}
+	ui.CheckMark(fmt.Sprintf("Initialized %s submodules", projectName))
+}
+
+// planTakeover checks that a registered feature can be replaced by a new one
+// on the same ports and returns it. Nothing is changed yet; the caller stops
+// and removes it once the new feature is about to be created.
+func planTakeover(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, featureName string) *registry.Worktree {
+	old, exists := reg.Get(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature '%s' is not registered, nothing to take over", featureName))
+		ui.Info("Run without --takeover to create it")
+		os.Exit(1)
+	}
+
+	// Removing the worktrees would lose uncommitted work
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	for _, projectName := range old.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		if changes, _ := git.HasUncommittedChanges(featureDir + "/" + project.Dir); changes {
+			ui.Error(fmt.Sprintf("%s has uncommitted changes in worktrees/%s/%s", featureName, featureName, project.Dir))
+			ui.Info("Commit or stash them before --takeover")
+			os.Exit(1)
+		}
+	}
+
+	// Ports still published by the old containers are freed when they stop;
+	// without a container runtime nothing is held
+	held, _ := docker.GetFeaturePublishedPorts(workCfg.ProjectName, featureName)
+
+	ports := make(map[string]int)
+	for _, service := range workCfg.GetPortServiceNames() {
+		if port, ok := old.Ports[service]; ok {
+			ports[service] = port
+		}
+	}
+	if err := reg.CheckTakeoverPorts(ports, featureName, held); err != nil {
+		ui.Error(fmt.Sprintf("Cannot take over the ports of %s: %v", featureName, err))
+		os.Exit(1)
+	}
+	return old
+}
+
+// writeProxyConfig writes the feature's reverse-proxy rules when proxy: is configured
+func writeProxyConfig(cfg *config.Config, workCfg *config.WorktreeConfig, featureName string, envVars map[string]string) {
+	path, rules, err := workCfg.WriteProxyConfig(cfg.ProjectRoot, featureName, envVars)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write proxy config: %v", err))
+		return
+	}
+	if path == "" {
+		return
+	}
+	ui.CheckMark(fmt.Sprintf("Proxy config written (%s)", path))
+	for _, rule := range rules {
+		ui.PrintStatusLine("  "+rule.Host, rule.Upstream)
+	}
+}
+
+// startLogTailLines is how much of the start log is shown when a start command fails
+const startLogTailLines = 20
+
+// printLogTail prints the last n lines of a log file, indented
+func printLogTail(path string, n int) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for _, line := range lines {
+		fmt.Printf("    %s\n", line)
+	}
 }
@@ -1,6 +1,13 @@
 package cmd
 
-import "github.com/braunmar/worktree/pkg/config"
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/ui"
+)
 
 // getClaudeWorkingProject returns the project configured as Claude's working directory
 // from the given preset projects (not all projects in config)
@@ -19,3 +26,80 @@ func getClaudeWorkingProject(workCfg *config.WorktreeConfig, presetProjects []st
 
 	return ""
 }
+
+// resolveProjectBranches returns the branch each preset project checks out:
+// the --branch-map entry, else the project's branch_template, else the feature branch
+func resolveProjectBranches(workCfg *config.WorktreeConfig, presetProjects []string, branch string, branchMap map[string]string) (map[string]string, error) {
+	inPreset := make(map[string]bool, len(presetProjects))
+	for _, projectName := range presetProjects {
+		inPreset[projectName] = true
+	}
+	for projectName, projectBranch := range branchMap {
+		if !inPreset[projectName] {
+			return nil, fmt.Errorf("--branch-map: project '%s' is not part of the preset %v", projectName, presetProjects)
+		}
+		if projectBranch == "" {
+			return nil, fmt.Errorf("--branch-map: empty branch for project '%s'", projectName)
+		}
+	}
+
+	branches := make(map[string]string, len(presetProjects))
+	for _, projectName := range presetProjects {
+		project := workCfg.Projects[projectName]
+		if mapped, ok := branchMap[projectName]; ok {
+			branches[projectName] = mapped
+		} else {
+			branches[projectName] = project.FeatureBranch(branch)
+		}
+	}
+	return branches, nil
+}
+
+// resolveBranchBases returns, for each preset project whose branch does not exist
+// yet, the ref the branch is created from: from (--from) if set, else the
+// project's main_branch. An empty base means the repository's HEAD, used when
+// main_branch is unset or cannot be resolved.
+func resolveBranchBases(projectRoot string, workCfg *config.WorktreeConfig, presetProjects []string, projectBranches map[string]string, from string) (map[string]string, error) {
+	bases := make(map[string]string)
+	for _, projectName := range presetProjects {
+		project := workCfg.Projects[projectName]
+		projectDir := filepath.Join(projectRoot, project.Dir)
+		if git.RefExists(projectDir, projectBranches[projectName]) {
+			continue
+		}
+
+		switch {
+		case from != "":
+			if !git.RefExists(projectDir, from) {
+				return nil, fmt.Errorf("--from: ref '%s' not found in %s", from, projectName)
+			}
+			bases[projectName] = from
+		case project.MainBranch != "" && git.RefExists(projectDir, project.MainBranch):
+			bases[projectName] = project.MainBranch
+		default:
+			if project.MainBranch != "" {
+				ui.Warning(fmt.Sprintf("%s: main_branch '%s' not found, branching from HEAD", projectName, project.MainBranch))
+			}
+			bases[projectName] = ""
+		}
+	}
+	return bases, nil
+}
+
+// describeBranchBase describes where a newly created branch starts
+func describeBranchBase(base string) string {
+	if base == "" {
+		return "- new branch from HEAD"
+	}
+	return "- new branch from " + base
+}
+
+// writeWorkspace generates the feature's IDE configuration (workspace:) and
+// returns the written paths, relative to the feature dir
+func writeWorkspace(workCfg *config.WorktreeConfig, featureDir, featureName string, projects []string, envVars map[string]string) []string {
+	written, err := workCfg.WriteWorkspace(featureDir, featureName, projects, envVars)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write IDE workspace: %v", err))
+	}
+	return written
+}
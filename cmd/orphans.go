@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/doctor"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// serviceOrphanNames returns the names of a compose project's containers whose
+// service is no longer in the compose file of any project sharing it;
+// detection errors yield none
+func serviceOrphanNames(workCfg *config.WorktreeConfig, wt *registry.Worktree, featurePath, projectName, composeProject string) map[string]bool {
+	projects := doctor.ComposeSiblings(workCfg, wt, composeProject)
+	orphans, err := doctor.ServiceOrphans(workCfg, wt, featurePath, projects, composeProject)
+	if err != nil {
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("%s: orphan detection skipped: %v", projectName, err))
+		return nil
+	}
+	names := make(map[string]bool, len(orphans))
+	for _, c := range orphans {
+		names[c.Name] = true
+	}
+	return names
+}
+
+// settleServiceOrphans deals with the orphaned containers of a project that
+// survived stopping it: with remove they are removed, otherwise the running
+// ones are stopped with the feature and kept
+func settleServiceOrphans(featureName, projectName, composeProject string, orphans map[string]bool, remove bool) {
+	if len(orphans) == 0 {
+		return
+	}
+	containers, err := docker.ComposeContainers(composeProject)
+	if err != nil {
+		return
+	}
+
+	var left, running []string
+	for _, c := range containers {
+		if !orphans[c.Name] {
+			continue
+		}
+		left = append(left, c.Name)
+		if c.Running() {
+			running = append(running, c.Name)
+		}
+	}
+	if len(left) == 0 {
+		return
+	}
+
+	if remove {
+		if err := docker.RemoveContainers(left); err != nil {
+			ui.Warning(fmt.Sprintf("%s: %v", projectName, err))
+			return
+		}
+		ui.CheckMark(fmt.Sprintf("%s: removed %d orphaned containers (%s)", projectName, len(left), strings.Join(left, ", ")))
+		return
+	}
+
+	if err := docker.StopContainers(running); err != nil {
+		ui.Warning(fmt.Sprintf("%s: %v", projectName, err))
+	}
+	ui.Warning(fmt.Sprintf("%s: %d orphaned containers kept (service no longer in the compose file): %s", projectName, len(left), strings.Join(left, ", ")))
+	ui.Info(fmt.Sprintf("💡 Remove them with: worktree stop %s --remove-orphans", featureName))
+}
+
+// printServiceOrphans lists the containers of a feature whose service is no
+// longer in the compose file, with how to adopt or clean them up
+func printServiceOrphans(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) {
+	reports := doctor.CheckServiceOrphans(cfg, workCfg, []*registry.Worktree{wt})
+	if len(reports) == 0 {
+		return
+	}
+
+	ui.PrintHeader("Orphaned Containers")
+	for _, report := range reports {
+		for i, name := range report.Containers {
+			ui.Warning(fmt.Sprintf("%s: %s (service '%s' is no longer in the compose file)", report.Project, name, report.Services[i]))
+		}
+	}
+	ui.Info("💡 Adopt: add the service back to the project's compose file")
+	ui.Info(fmt.Sprintf("💡 Clean up: worktree stop %s --remove-orphans", wt.Normalized))
+	ui.NewLine()
+}
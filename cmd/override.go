@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	overrideUnset []string
+	overrideClear bool
+)
+
+var overrideCmd = &cobra.Command{
+	Use:   "override <feature-name> [KEY=VALUE...]",
+	Short: "Set per-feature env variable overrides",
+	Long: `Set, remove, or list env variable overrides for a single feature.
+
+Overrides live in worktrees/<feature>/.worktree-overrides.yml. They replace
+configured env variable values (or add extra variables) for that feature only,
+and are applied by start, restart and stop. The file is never touched when
+files are regenerated, so overrides survive restarts.
+
+Run 'worktree start <feature>' afterwards to apply the new values.
+
+Examples:
+  worktree override feature-x API_URL=https://staging.example.com
+  worktree override feature-x DEBUG=1 LOG_LEVEL=debug
+  worktree override feature-x --unset API_URL
+  worktree override feature-x --clear
+  worktree override feature-x                 # List current overrides`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runOverride,
+}
+
+func init() {
+	overrideCmd.Flags().StringSliceVar(&overrideUnset, "unset", nil, "remove override for the given variable (repeatable)")
+	overrideCmd.Flags().BoolVar(&overrideClear, "clear", false, "remove all overrides for the feature")
+}
+
+func runOverride(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+
+	overrides, err := config.ReadOverrides(featureDir)
+	checkError(err)
+
+	assignments := args[1:]
+	if len(assignments) == 0 && len(overrideUnset) == 0 && !overrideClear {
+		printOverrides(featureName, overrides)
+		return
+	}
+
+	if overrideClear {
+		overrides = make(map[string]string)
+	}
+
+	for _, key := range overrideUnset {
+		delete(overrides, key)
+	}
+
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		if !ok {
+			checkError(fmt.Errorf("invalid override '%s': expected KEY=VALUE", assignment))
+		}
+		if !config.ValidEnvName(key) {
+			checkError(fmt.Errorf("invalid override '%s': '%s' is not a valid environment variable name", assignment, key))
+		}
+		overrides[key] = value
+	}
+
+	checkError(config.WriteOverrides(featureDir, overrides))
+
+	ui.Success(fmt.Sprintf("Updated overrides for '%s'", featureName))
+	printOverrides(featureName, overrides)
+	ui.Info(fmt.Sprintf("Run 'worktree start %s' to apply", featureName))
+}
+
+// printOverrides lists a feature's overrides in sorted order
+func printOverrides(featureName string, overrides map[string]string) {
+	if len(overrides) == 0 {
+		ui.Info(fmt.Sprintf("No overrides set for '%s'", featureName))
+		return
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ui.Section(fmt.Sprintf("Overrides for %s", featureName))
+	for _, key := range keys {
+		fmt.Printf("  %s=%s\n", key, overrides[key])
+	}
+	ui.NewLine()
+}
+
+// resolveWithOverrides applies the feature's overrides before resolving value
+// templates, so an overridden port reaches the values built from it (a *_URL
+// using {APP_PORT}). Overrides of value vars themselves are applied again
+// afterwards so they win over their templates. Returns the overrides.
+func resolveWithOverrides(workCfg *config.WorktreeConfig, instance int, featureDir string, envVars map[string]string) map[string]string {
+	overrides := applyFeatureOverrides(featureDir, envVars)
+	workCfg.ResolveValueVars(instance, envVars)
+	maps.Copy(envVars, overrides)
+	return overrides
+}
+
+// applyFeatureOverrides merges the feature's .worktree-overrides.yml into envVars.
+// Read errors are reported as warnings so a broken overrides file never blocks start/stop.
+func applyFeatureOverrides(featureDir string, envVars map[string]string) map[string]string {
+	overrides, err := config.ApplyOverrides(featureDir, envVars)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Ignoring feature overrides: %v", err))
+		return nil
+	}
+	return overrides
+}
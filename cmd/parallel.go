@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+)
+
+// projectOutput is the result of one project's job run by parallel.Run: the
+// output of its git commands is captured and printed when the project's turn
+// comes, so concurrent projects do not interleave
+type projectOutput struct {
+	output bytes.Buffer
+	err    error
+}
+
+// print writes the captured output to stdout
+func (r *projectOutput) print() {
+	os.Stdout.Write(r.output.Bytes())
+}
@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/registry"
@@ -27,13 +30,27 @@ Displays:
 If no feature name is provided and you're in a worktree directory,
 the feature will be auto-detected from .worktree-instance.
 
+With --copy, the URL of the instance port's service is put on the system
+clipboard; --copy=<service> copies another service's URL (by display name or
+port variable). The clipboard tool is pbcopy, clip, wl-copy, xclip, xsel or
+clip.exe, or the command in WORKTREE_CLIPBOARD.
+
 Examples:
   worktree ports feature-user-auth    # Explicit feature name
-  worktree ports                      # Auto-detect from current directory`,
+  worktree ports                      # Auto-detect from current directory
+  worktree ports --copy               # Copy the main service URL
+  worktree ports --copy=Frontend      # Copy the Frontend URL`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runPorts,
 }
 
+var portsCopy string
+
+func init() {
+	portsCmd.Flags().StringVar(&portsCopy, "copy", "", "Copy a service URL to the clipboard (default: the instance port's service)")
+	portsCmd.Flags().Lookup("copy").NoOptDefVal = copyDefault
+}
+
 func runPorts(cmd *cobra.Command, args []string) {
 	var featureName string
 	autoDetected := false
@@ -66,7 +83,7 @@ func runPorts(cmd *cobra.Command, args []string) {
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		fmt.Println("\nAvailable features:")
@@ -75,6 +92,7 @@ func runPorts(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Display header
 	ui.PrintHeader(fmt.Sprintf("Ports for Feature: %s", featureName))
@@ -94,4 +112,97 @@ func runPorts(cmd *cobra.Command, args []string) {
 		ui.PrintStatusLine("Mailpit SMTP", fmt.Sprintf("%s:%d", workCfg.Hostname, port))
 	}
 	ui.NewLine()
+
+	if portsCopy != "" {
+		service := portsCopy
+		if service == copyDefault {
+			service = ""
+		}
+		name, url, err := featureServiceURL(workCfg, wt, service)
+		checkError(err)
+		copyToClipboard(name+" URL", url)
+	}
+}
+
+// allocatePorts allocates ports for services like reg.AllocatePorts. When a
+// service with on_exhausted: steal-stopped has no free port, the port of a
+// stopped feature (other than exclude) is reclaimed after confirmation, the
+// one stopped longest first, and allocation is retried.
+func allocatePorts(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, services []string, exclude string) (map[string]int, error) {
+	for {
+		ports, err := reg.AllocatePorts(services)
+		var exhausted *registry.PortExhaustedError
+		if err == nil || !errors.As(err, &exhausted) || workCfg.GetOnExhausted(exhausted.Service).Action != config.OnExhaustedSteal {
+			return ports, err
+		}
+		if !reclaimStoppedPort(cfg, workCfg, reg, exhausted, exclude) {
+			return nil, err
+		}
+	}
+}
+
+// reclaimStoppedPort takes an exhausted service's port away from a stopped
+// feature, which gets a new one on its next start. It reports whether a port
+// was reclaimed.
+func reclaimStoppedPort(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, exhausted *registry.PortExhaustedError, exclude string) bool {
+	type candidate struct {
+		holder       registry.PortHolder
+		wt           *registry.Worktree
+		stoppedSince time.Time
+	}
+	var candidates []candidate
+	for _, holder := range exhausted.Holders {
+		wt, ok := reg.Get(holder.Feature)
+		if !ok || holder.Feature == exclude || featureRunning(cfg, workCfg, wt) {
+			continue
+		}
+		stoppedSince := wt.Created
+		if cache, err := config.ReadStatusCache(cfg.WorktreeFeaturePath(wt.Normalized)); err == nil && cache != nil {
+			stoppedSince = cache.UpdatedAt
+		}
+		candidates = append(candidates, candidate{holder: holder, wt: wt, stoppedSince: stoppedSince})
+	}
+	if len(candidates) == 0 {
+		ui.Warning(fmt.Sprintf("%s: every port is taken and no feature holding one is stopped (on_exhausted: steal-stopped)", exhausted.Service))
+		return false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].stoppedSince.Before(candidates[j].stoppedSince) })
+
+	victim := candidates[0]
+	question := fmt.Sprintf("Port %d of %s is held by stopped feature '%s' (idle since %s). Reclaim it? '%s' gets a new port on its next start",
+		victim.holder.Port, victim.holder.Service, victim.holder.Feature, victim.stoppedSince.Format("2006-01-02 15:04"), victim.holder.Feature)
+	if !ui.Confirm(question, false) {
+		return false
+	}
+	delete(victim.wt.Ports, victim.holder.Service)
+	ui.CheckMark(fmt.Sprintf("Reclaimed port %d from %s (%s)", victim.holder.Port, victim.holder.Feature, victim.holder.Service))
+	return true
+}
+
+// ensureFeaturePorts allocates ports for the services a feature has none
+// for: ports reclaimed by on_exhausted: steal-stopped, or variables added to
+// .worktree.yml since it was created
+func ensureFeaturePorts(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, wt *registry.Worktree) error {
+	var missing []string
+	for _, service := range workCfg.GetPortServiceNames() {
+		if _, ok := wt.Ports[service]; !ok {
+			missing = append(missing, service)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	allocated, err := allocatePorts(cfg, workCfg, reg, missing, wt.Normalized)
+	if err != nil {
+		return err
+	}
+	if wt.Ports == nil {
+		wt.Ports = make(map[string]int)
+	}
+	for _, service := range missing {
+		wt.Ports[service] = allocated[service]
+		ui.CheckMark(fmt.Sprintf("Allocated %s=%d (the feature had no port for it)", service, allocated[service]))
+	}
+	return reg.Save()
 }
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/doctor"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// skipPreflightEnv disables the pre-flight checks like --skip-preflight when
+// set to anything but "", "0" or "false"
+const skipPreflightEnv = "WORKTREE_SKIP_PREFLIGHT"
+
+// runPreflight exits before new-feature or start change anything when the
+// container runtime or the free disk space would make them fail midway.
+// startsServices is false when no start_command will run (e.g. --no-start).
+func runPreflight(cfg *config.Config, workCfg *config.WorktreeConfig, projects []string, startsServices, skip bool) {
+	if env := os.Getenv(skipPreflightEnv); skip || (env != "" && env != "0" && env != "false") {
+		ui.Verbose(ui.ScopeConfig, "Skipping pre-flight checks")
+		return
+	}
+
+	dir := cfg.WorktreeDir
+	if _, err := os.Stat(dir); err != nil {
+		dir = cfg.ProjectRoot
+	}
+	opts := doctor.PreflightOptions{
+		Dir:           dir,
+		Docker:        startsServices && usesContainerRuntime(workCfg, projects),
+		MinFreeDiskMB: workCfg.GetMinFreeDiskMB(),
+	}
+	problems := doctor.Preflight(opts)
+	if len(problems) == 0 {
+		ui.Verbose(ui.ScopeConfig, "Pre-flight checks passed")
+		return
+	}
+
+	ui.Error("Pre-flight checks failed, nothing was changed:")
+	for _, problem := range problems {
+		ui.CrossMark(problem.Problem)
+		ui.Info("💡 " + problem.Hint)
+	}
+	ui.NewLine()
+	ui.Info("Run 'worktree doctor' for details, or skip these checks with --skip-preflight")
+	os.Exit(1)
+}
+
+// usesContainerRuntime reports whether any of projects starts services with
+// the docker executor
+func usesContainerRuntime(workCfg *config.WorktreeConfig, projects []string) bool {
+	for _, name := range projects {
+		project := workCfg.Projects[name]
+		if project.GetExecutor() == "docker" && project.StartCommand != "" {
+			return true
+		}
+	}
+	return false
+}
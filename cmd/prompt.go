@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultPromptFormat = "{feature}:{instance} {dot}"
+
+var promptFormat string
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a compact worktree segment for the shell prompt",
+	Long: `Print the current feature, instance and running state as a short segment
+for PS1, starship or similar prompts.
+
+Only the .worktree-instance marker and a cached status file are read, so the
+command is fast enough to run on every prompt. The cached status is refreshed
+by start, stop, restart and status. Outside a worktree nothing is printed.
+
+Format placeholders:
+  {feature}   Feature name
+  {instance}  Instance number
+  {dot}       ● running, ○ stopped, ? unknown
+  {status}    running, stopped or unknown
+
+Examples:
+  worktree prompt                              # feature-x:2 ●
+  worktree prompt --format '[{feature} {status}]'
+  PS1='$(worktree prompt --no-color) \$ '      # bash
+  # starship: [custom.worktree] command = "worktree prompt", when = true`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{skipGitCheckAnnotation: "true"},
+	Run:         runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", defaultPromptFormat, "output format ({feature}, {instance}, {dot}, {status})")
+}
+
+func runPrompt(cmd *cobra.Command, args []string) {
+	ctx, err := config.DetectInstance()
+	if err != nil {
+		return
+	}
+
+	status := "unknown"
+	if cache, err := config.ReadStatusCache(ctx.WorktreeRoot); err == nil && cache != nil {
+		status = "stopped"
+		if cache.Running {
+			status = "running"
+		}
+	}
+
+	// The prompt's stdout is never a terminal, so only an explicit --no-color
+	// or NO_COLOR turns its colors off
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	dot := promptDot(status, !noColor && !ui.IsAccessible() && !ui.NoColorRequested())
+	if ui.IsAccessible() {
+		dot = status
+	}
+
+	fmt.Println(strings.NewReplacer(
+		"{feature}", ctx.Feature,
+		"{instance}", strconv.Itoa(ctx.Instance),
+		"{dot}", dot,
+		"{status}", status,
+	).Replace(promptFormat))
+}
+
+// promptDot returns the status symbol, colored with raw ANSI codes since the
+// prompt's stdout is never a terminal
+func promptDot(status string, colored bool) string {
+	symbol, code := "?", "33"
+	switch status {
+	case "running":
+		symbol, code = "●", "32"
+	case "stopped":
+		symbol, code = "○", "90"
+	}
+	if !colored {
+		return symbol
+	}
+	return "\033[" + code + "m" + symbol + "\033[0m"
+}
+
+// cacheFeatureStatus records the running state for 'worktree prompt' and the
+// runtime shown by list/status. Best effort: failures are ignored.
+func cacheFeatureStatus(featureDir string, running bool) {
+	_ = config.WriteStatusCache(featureDir, running)
+}
+
+// featureRuntime returns the cumulative running time recorded for a feature,
+// or "" when it has never been started
+func featureRuntime(featureDir string) string {
+	cache, err := config.ReadStatusCache(featureDir)
+	if err != nil || cache == nil {
+		return ""
+	}
+	total := cache.TotalRunningTime(time.Now())
+	if total == 0 && !cache.Running {
+		return ""
+	}
+	return total.Round(time.Second).String()
+}
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOlderThan string
+	pruneMerged    bool
+	pruneDryRun    bool
+	pruneForce     bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove stale features in batch",
+	Long: `Find features that are done or abandoned and remove them: services are
+stopped, git worktrees removed and registry entries dropped, exactly as
+'worktree remove' does.
+
+A feature is stale when
+  --merged        its branch was merged into the project's main branch
+                  (origin/<main_branch> if present) in every project it touched
+  --older-than    it has had no commit (and was created) longer ago than the
+                  given age, e.g. 14d, 2w or 36h
+
+Without either flag both criteria apply, with --older-than 14d.
+Merges are detected by ancestry, so squash- or rebase-merged branches are
+only found through --older-than.
+
+Features with uncommitted changes are never pruned; remove them explicitly
+with 'worktree remove'. A summary is shown and confirmed before anything
+is removed.
+
+Examples:
+  worktree prune --dry-run             # Show what would be removed
+  worktree prune --merged              # Only merged branches
+  worktree prune --older-than 30d
+  worktree prune --force               # Skip the confirmation`,
+	Args: cobra.NoArgs,
+	Run:  runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "14d", "prune features without commits for this long (e.g. 14d, 2w, 36h)")
+	pruneCmd.Flags().BoolVar(&pruneMerged, "merged", false, "prune features whose branches are merged into main")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "list stale features without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "skip confirmation prompt")
+}
+
+// pruneCandidate is a feature evaluated by prune
+type pruneCandidate struct {
+	wt      *registry.Worktree
+	reasons []string
+	dirty   []string // Projects with uncommitted changes
+}
+
+func runPrune(cmd *cobra.Command, args []string) {
+	byAge := cmd.Flags().Changed("older-than") || !pruneMerged
+	byMerge := pruneMerged || !cmd.Flags().Changed("older-than")
+
+	maxAge, err := config.ParseAge(pruneOlderThan)
+	checkError(err)
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+	configureContainerRuntime(workCfg)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	now := time.Now()
+	var stale, skipped []pruneCandidate
+	for _, wt := range reg.List() {
+		if !cfg.WorktreeExists(wt.Normalized) {
+			continue // Orphaned registry entry: 'worktree doctor --fix' cleans those up
+		}
+
+		candidate := evaluatePrune(cfg, workCfg, wt, now, maxAge, byAge, byMerge)
+		switch {
+		case len(candidate.reasons) == 0:
+		case len(candidate.dirty) > 0:
+			skipped = append(skipped, candidate)
+		default:
+			stale = append(stale, candidate)
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].wt.Normalized < stale[j].wt.Normalized })
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].wt.Normalized < skipped[j].wt.Normalized })
+
+	if len(skipped) > 0 {
+		ui.Warning("Stale but with uncommitted changes (not pruned):")
+		for _, c := range skipped {
+			fmt.Printf("  - %s: %s (dirty: %s)\n", c.wt.Normalized, strings.Join(c.reasons, ", "), strings.Join(c.dirty, ", "))
+		}
+		ui.NewLine()
+	}
+
+	if len(stale) == 0 {
+		ui.Success("No stale features to prune")
+		return
+	}
+
+	ui.Section(fmt.Sprintf("%d stale features:", len(stale)))
+	for _, c := range stale {
+		fmt.Printf("  - %s (%s): %s\n", c.wt.Normalized, c.wt.Branch, strings.Join(c.reasons, ", "))
+	}
+	ui.NewLine()
+
+	if pruneDryRun {
+		ui.Info("Dry run: nothing removed")
+		return
+	}
+
+	if !pruneForce {
+		if !ui.Confirm(fmt.Sprintf("Remove these %d features (services, worktrees and registry entries)?", len(stale)), false) {
+			ui.Info("Prune cancelled")
+			return
+		}
+		ui.NewLine()
+	}
+
+	for _, c := range stale {
+		ui.Section(fmt.Sprintf("Removing %s", c.wt.Normalized))
+		stopFeatureServices(cfg, workCfg, c.wt)
+		removeFeatureFiles(cfg, workCfg, reg, c.wt)
+		ui.NewLine()
+	}
+
+	if err := reg.Save(); err != nil {
+		checkError(fmt.Errorf("failed to save registry: %w", err))
+	}
+
+	ui.Success(fmt.Sprintf("Pruned %d features", len(stale)))
+}
+
+// evaluatePrune collects the reasons a feature is stale and the projects that
+// have uncommitted changes
+func evaluatePrune(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, now time.Time, maxAge time.Duration, byAge, byMerge bool) pruneCandidate {
+	candidate := pruneCandidate{wt: wt}
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+
+	lastActivity := wt.Created
+	mergedAll, mergedAny := true, false
+	var mainRef string
+
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); err != nil {
+			continue
+		}
+
+		if dirty, _ := git.HasUncommittedChanges(worktreePath); dirty {
+			candidate.dirty = append(candidate.dirty, projectName)
+		}
+
+		commitTime, err := git.LastCommitTime(worktreePath)
+		if err != nil {
+			mergedAll = false
+			continue
+		}
+		if commitTime.After(lastActivity) {
+			lastActivity = commitTime
+		}
+
+		mainBranch := project.MainBranch
+		if mainBranch == "" {
+			mainBranch = "main"
+		}
+		ref := git.MainRef(worktreePath, mainBranch)
+		merged, err := git.IsHeadMergedInto(worktreePath, ref)
+		switch {
+		case err != nil || !merged:
+			mergedAll = false
+		case commitTime.After(wt.Created):
+			// HEAD is in main and was committed after the feature was created,
+			// so it is the feature's own work (an untouched branch is also in main)
+			mergedAny = true
+			mainRef = ref
+		}
+	}
+
+	if byMerge && mergedAll && mergedAny {
+		candidate.reasons = append(candidate.reasons, "merged into "+mainRef)
+	}
+	if idle := now.Sub(lastActivity); byAge && idle > maxAge {
+		candidate.reasons = append(candidate.reasons, fmt.Sprintf("no commits for %d days", int(idle.Hours()/24)))
+	}
+
+	return candidate
+}
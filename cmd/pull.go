@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/git"
@@ -45,7 +44,7 @@ func runPull(cmd *cobra.Command, args []string) {
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
 		fmt.Println("\nAvailable features:")
@@ -54,6 +53,7 @@ func runPull(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	if !cfg.WorktreeExists(featureName) {
 		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
@@ -119,8 +119,7 @@ func runPull(cmd *cobra.Command, args []string) {
 		}
 
 		ui.Info(fmt.Sprintf("📥 Pulling %s...", projectName))
-		pullExec := exec.Command("git", "pull", "origin", wt.Branch)
-		pullExec.Dir = worktreePath
+		pullExec := git.Command(worktreePath, "pull", "origin", wt.BranchFor(projectName))
 		pullExec.Stdout = os.Stdout
 		pullExec.Stderr = os.Stderr
 		if err := pullExec.Run(); err != nil {
@@ -3,9 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/parallel"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 
@@ -18,8 +19,9 @@ var pushCmd = &cobra.Command{
 	Long: `Push the feature branch to origin for all projects in the worktree.
 
 This command:
-1. Pushes the feature branch to origin in all project worktrees
-2. Reports success or failure per project
+1. Pushes the feature branch to origin in all project worktrees, up to
+   git_parallelism at a time (default 4)
+2. Reports success or failure per project, in project order
 
 The feature name is automatically normalized, so you can use either:
 - The normalized feature name: feature-user-auth
@@ -44,7 +46,7 @@ func runPush(cmd *cobra.Command, args []string) {
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
 		fmt.Println("\nAvailable features:")
@@ -53,6 +55,7 @@ func runPush(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	if !cfg.WorktreeExists(featureName) {
 		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
@@ -73,7 +76,11 @@ func runPush(cmd *cobra.Command, args []string) {
 	featureDir := cfg.WorktreeFeaturePath(featureName)
 
 	ui.Section("Pushing branches...")
-	allOk := true
+	type projectPush struct {
+		projectName  string
+		worktreePath string
+	}
+	var pushes []projectPush
 	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
@@ -87,29 +94,46 @@ func runPush(cmd *cobra.Command, args []string) {
 			ui.Warning(fmt.Sprintf("Worktree for %s does not exist, skipping", projectName))
 			continue
 		}
+		pushes = append(pushes, projectPush{projectName, worktreePath})
+	}
 
-		ui.Info(fmt.Sprintf("📤 Pushing %s...", projectName))
-		pushCmd := exec.Command("git", "push", "origin", wt.Branch)
-		pushCmd.Dir = worktreePath
-		pushCmd.Stdout = os.Stdout
-		pushCmd.Stderr = os.Stderr
-		if err := pushCmd.Run(); err != nil {
-			ui.CrossMark(fmt.Sprintf("%s push failed", projectName))
+	allOk := true
+	parallel.Run(len(pushes), workCfg.GetGitParallelism(), func(i int) *projectOutput {
+		p := pushes[i]
+		result := &projectOutput{}
+		pushCmd := git.Command(p.worktreePath, "push", "origin", wt.BranchFor(p.projectName))
+		pushCmd.Stdout = &result.output
+		pushCmd.Stderr = &result.output
+		result.err = pushCmd.Run()
+		return result
+	}, func(i int, result *projectOutput) bool {
+		p := pushes[i]
+		ui.Info(fmt.Sprintf("📤 Pushing %s...", p.projectName))
+		result.print()
+		if result.err != nil {
+			ui.CrossMark(fmt.Sprintf("%s push failed", p.projectName))
 			allOk = false
 			ui.NewLine()
 			ui.Info("💡 If the remote is ahead, try: worktree pull " + featureName)
 			ui.Info("💡 Or rebase first: worktree rebase " + featureName)
-			continue
+			return true
 		}
-		ui.CheckMark(fmt.Sprintf("%s pushed", projectName))
-	}
+		ui.CheckMark(fmt.Sprintf("%s pushed", p.projectName))
+		return true
+	})
 
 	ui.NewLine()
 	if allOk {
 		ui.Success("✨ Push completed successfully!")
 		ui.NewLine()
 		ui.Info("Next steps:")
-		ui.Info("  • Open a pull request from branch: " + wt.Branch)
+		if len(wt.Branches) == 0 {
+			ui.Info("  • Open a pull request from branch: " + wt.Branch)
+		} else {
+			for _, projectName := range projects {
+				ui.Info(fmt.Sprintf("  • Open a %s pull request from branch: %s", projectName, wt.BranchFor(projectName)))
+			}
+		}
 	} else {
 		ui.Error("Push completed with errors (see above)")
 		os.Exit(1)
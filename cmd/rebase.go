@@ -2,17 +2,26 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"strings"
+	"sync"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/parallel"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	rebaseContinue  bool
+	rebaseAbort     bool
+	rebaseAutostash bool
+)
+
 var rebaseCmd = &cobra.Command{
 	Use:   "rebase <feature-name>",
 	Short: "Update main and rebase feature branch",
@@ -24,6 +33,19 @@ This command:
 3. Rebases the feature worktree branches on top of updated main
 4. Shows status and any conflicts that need resolution
 
+Projects are updated and rebased concurrently, up to git_parallelism at a
+time (default 4), with output shown per project in order. When a project
+hits conflicts no further projects are started. Resolve the conflicts
+and stage the files, then run 'worktree rebase <feature> --continue': it
+continues every project that is mid-rebase and rebases the projects that were
+not reached yet. '--abort' aborts the rebases in progress; projects that
+already finished keep their rebased branch.
+
+With --autostash, uncommitted changes are stashed first ('worktree stash')
+and restored once every project is rebased. If the rebase stops on
+conflicts they stay stashed; '--continue --autostash' restores them at the
+end, or run 'worktree unstash <feature>' yourself.
+
 Works with any projects defined in your .worktree.yml configuration.
 
 The feature name is automatically normalized, so you can use either:
@@ -32,11 +54,22 @@ The feature name is automatically normalized, so you can use either:
 
 Examples:
   worktree rebase feature-user-auth
-  worktree rebase feature/user-auth`,
+  worktree rebase feature/user-auth
+  worktree rebase feature-user-auth --autostash  # Stash and restore local changes
+  worktree rebase feature-user-auth --continue   # After resolving conflicts
+  worktree rebase feature-user-auth --abort`,
 	Args: cobra.ExactArgs(1),
 	Run:  runRebase,
 }
 
+func init() {
+	rebaseCmd.Flags().BoolVar(&rebaseContinue, "continue", false, "continue in-progress rebases after resolving conflicts")
+	rebaseCmd.Flags().BoolVar(&rebaseAbort, "abort", false, "abort in-progress rebases")
+	rebaseCmd.Flags().BoolVar(&rebaseAutostash, "autostash", false, "stash uncommitted changes before rebasing and restore them afterwards")
+	rebaseCmd.MarkFlagsMutuallyExclusive("continue", "abort")
+	rebaseCmd.MarkFlagsMutuallyExclusive("autostash", "abort")
+}
+
 func runRebase(cmd *cobra.Command, args []string) {
 	input := args[0]
 
@@ -56,7 +89,7 @@ func runRebase(cmd *cobra.Command, args []string) {
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
 		fmt.Println("\nAvailable features:")
@@ -65,6 +98,7 @@ func runRebase(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Check if worktree directory exists
 	if !cfg.WorktreeExists(featureName) {
@@ -87,9 +121,54 @@ func runRebase(cmd *cobra.Command, args []string) {
 
 	featureDir := cfg.WorktreeFeaturePath(featureName)
 
-	// Check for uncommitted changes in all projects
+	// Projects stopped mid-rebase by an earlier run
+	rebasing := make(map[string]bool)
+	for _, projectName := range projects {
+		if project, exists := workCfg.Projects[projectName]; exists {
+			if git.RebaseInProgress(featureDir + "/" + project.Dir) {
+				rebasing[projectName] = true
+			}
+		}
+	}
+
+	if rebaseAbort {
+		abortRebases(featureDir, workCfg, projects, rebasing)
+		return
+	}
+
+	if rebaseContinue && len(rebasing) == 0 {
+		ui.Error("No rebase in progress")
+		ui.Info(fmt.Sprintf("💡 Start one with: worktree rebase %s", featureName))
+		os.Exit(1)
+	}
+	if !rebaseContinue && len(rebasing) > 0 {
+		ui.Error(fmt.Sprintf("Rebase already in progress in: %s", strings.Join(sortedKeys(rebasing), ", ")))
+		ui.NewLine()
+		ui.Info(fmt.Sprintf("💡 Resolve the conflicts, then run: worktree rebase %s --continue", featureName))
+		ui.Info(fmt.Sprintf("💡 Or give up with: worktree rebase %s --abort", featureName))
+		os.Exit(1)
+	}
+
+	// Stash local changes (not the resolved conflicts of projects being continued)
+	if rebaseAutostash {
+		var toStash []string
+		for _, projectName := range projects {
+			if !rebasing[projectName] {
+				toStash = append(toStash, projectName)
+			}
+		}
+		if stashed := stashFeature(featureName, featureDir, workCfg, toStash); len(stashed) > 0 {
+			ui.NewLine()
+		}
+	}
+
+	// Check for uncommitted changes in all projects (resolved conflicts in
+	// projects being continued are expected)
 	hasUncommittedChanges := false
 	for _, projectName := range projects {
+		if rebasing[projectName] {
+			continue
+		}
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
 			ui.Warning(fmt.Sprintf("Project '%s' not found in configuration, skipping", projectName))
@@ -120,33 +199,20 @@ func runRebase(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Step 1: Update main branch in all project repositories
-	ui.Section("Updating main branches...")
-	for _, projectName := range projects {
-		project, exists := workCfg.Projects[projectName]
-		if !exists {
-			continue
-		}
-
-		// Get main branch name from config (default to "main")
-		mainBranch := "main"
-		if project.MainBranch != "" {
-			mainBranch = project.MainBranch
-		}
-
-		projectDir := cfg.ProjectRoot + "/" + project.Dir
-
-		ui.Info(fmt.Sprintf("📥 Updating %s %s branch...", projectName, mainBranch))
-		if err := updateMainBranch(projectDir, mainBranch); err != nil {
-			ui.Error(fmt.Sprintf("Failed to update %s %s: %v", projectName, mainBranch, err))
-			os.Exit(1)
-		}
-		ui.CheckMark(fmt.Sprintf("%s %s updated", projectName, mainBranch))
+	// Step 1: Update main branch in all project repositories (already done
+	// by the run being continued)
+	if !rebaseContinue {
+		updateMainBranches(cfg, workCfg, projects)
 	}
-	ui.NewLine()
 
 	// Step 2: Rebase all project worktrees
 	ui.Section("Rebasing worktrees...")
+	type projectRebase struct {
+		projectName  string
+		worktreePath string
+		mainBranch   string
+	}
+	var rebases []projectRebase
 	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
@@ -166,17 +232,60 @@ func runRebase(cmd *cobra.Command, args []string) {
 			ui.Warning(fmt.Sprintf("Worktree for %s does not exist, skipping", projectName))
 			continue
 		}
+		rebases = append(rebases, projectRebase{projectName, worktreePath, mainBranch})
+	}
+
+	// Projects rebase concurrently; a conflict stops new projects from starting
+	var failed []projectRebase
+	parallel.Run(len(rebases), workCfg.GetGitParallelism(), func(i int) *projectOutput {
+		r := rebases[i]
+		result := &projectOutput{}
+		if rebasing[r.projectName] {
+			result.err = continueRebase(&result.output, r.worktreePath)
+		} else {
+			result.err = rebaseBranch(&result.output, r.worktreePath, wt.BranchFor(r.projectName), r.mainBranch)
+		}
+		return result
+	}, func(i int, result *projectOutput) bool {
+		r := rebases[i]
+		if rebasing[r.projectName] {
+			ui.Info(fmt.Sprintf("🔄 Continuing %s rebase...", r.projectName))
+		} else {
+			ui.Info(fmt.Sprintf("🔄 Rebasing %s branch...", r.projectName))
+		}
+		result.print()
+		if result.err != nil {
+			ui.Error(fmt.Sprintf("%s rebase failed: %v", r.projectName, result.err))
+			failed = append(failed, r)
+			return false
+		}
+		ui.CheckMark(fmt.Sprintf("%s rebased successfully", r.projectName))
+		return true
+	})
 
-		ui.Info(fmt.Sprintf("🔄 Rebasing %s branch...", projectName))
-		if err := rebaseBranch(worktreePath, wt.Branch, mainBranch); err != nil {
-			ui.Error(fmt.Sprintf("%s rebase failed: %v", projectName, err))
+	if len(failed) > 0 {
+		ui.NewLine()
+		ui.Info("💡 Resolve conflicts in:")
+		for _, r := range failed {
+			ui.Info(fmt.Sprintf("   %s", r.worktreePath))
+		}
+		ui.Info(fmt.Sprintf("💡 Stage the resolved files, then run: worktree rebase %s --continue", featureName))
+		ui.Info(fmt.Sprintf("💡 Or give up with: worktree rebase %s --abort", featureName))
+		if rebaseAutostash {
+			ui.Info(fmt.Sprintf("💡 Your local changes stay stashed; restore them with: worktree unstash %s", featureName))
+		}
+		os.Exit(1)
+	}
+
+	if rebaseAutostash {
+		ui.NewLine()
+		ui.Section("Restoring stashed changes...")
+		if !unstashFeature(featureName, featureDir, workCfg, projects) {
 			ui.NewLine()
-			ui.Info("💡 Resolve conflicts in:")
-			ui.Info(fmt.Sprintf("   %s", worktreePath))
-			ui.Info("💡 Then run: git -C " + worktreePath + " rebase --continue")
+			ui.Error("Rebase succeeded, but some stashed changes could not be restored")
+			ui.Info("💡 Resolve the conflicts, then run 'git stash drop' in those worktrees")
 			os.Exit(1)
 		}
-		ui.CheckMark(fmt.Sprintf("%s rebased successfully", projectName))
 	}
 
 	ui.NewLine()
@@ -188,18 +297,76 @@ func runRebase(cmd *cobra.Command, args []string) {
 	ui.NewLine()
 }
 
-// updateMainBranch pulls latest changes from origin/main
-func updateMainBranch(repoDir string, mainBranch string) error {
+// updateMainBranches updates the main branch of every project repository,
+// exiting on the first failure. Repositories are updated concurrently
+// (git_parallelism); projects sharing a repository directory take turns.
+func updateMainBranches(cfg *config.Config, workCfg *config.WorktreeConfig, projects []string) {
+	ui.Section("Updating main branches...")
+	type mainUpdate struct {
+		projectName string
+		projectDir  string
+		mainBranch  string
+	}
+	var updates []mainUpdate
+	locks := make(map[string]*sync.Mutex)
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+
+		// Get main branch name from config (default to "main")
+		mainBranch := "main"
+		if project.MainBranch != "" {
+			mainBranch = project.MainBranch
+		}
+
+		projectDir := cfg.ProjectRoot + "/" + project.Dir
+		if locks[projectDir] == nil {
+			locks[projectDir] = &sync.Mutex{}
+		}
+		updates = append(updates, mainUpdate{projectName, projectDir, mainBranch})
+	}
+
+	fetcher := git.NewFetchCoordinator(workCfg.GetFetchTTL())
+	failed := false
+	parallel.Run(len(updates), workCfg.GetGitParallelism(), func(i int) *projectOutput {
+		u := updates[i]
+		lock := locks[u.projectDir]
+		lock.Lock()
+		defer lock.Unlock()
+
+		result := &projectOutput{}
+		result.err = updateMainBranch(&result.output, fetcher, u.projectDir, u.mainBranch)
+		return result
+	}, func(i int, result *projectOutput) bool {
+		u := updates[i]
+		ui.Info(fmt.Sprintf("📥 Updating %s %s branch...", u.projectName, u.mainBranch))
+		result.print()
+		if result.err != nil {
+			ui.Error(fmt.Sprintf("Failed to update %s %s: %v", u.projectName, u.mainBranch, result.err))
+			failed = true
+			return false
+		}
+		ui.CheckMark(fmt.Sprintf("%s %s updated", u.projectName, u.mainBranch))
+		return true
+	})
+	if failed {
+		os.Exit(1)
+	}
+	ui.NewLine()
+}
+
+// updateMainBranch merges the latest origin/main into main. Projects sharing
+// a repository are fetched once (see git.FetchCoordinator).
+func updateMainBranch(out io.Writer, fetcher *git.FetchCoordinator, repoDir string, mainBranch string) error {
 	// Fetch latest from origin
-	fetchCmd := exec.Command("git", "fetch", "origin", mainBranch)
-	fetchCmd.Dir = repoDir
-	if err := fetchCmd.Run(); err != nil {
-		return fmt.Errorf("git fetch failed: %w", err)
+	if _, err := fetcher.Fetch(repoDir, "origin"); err != nil {
+		return err
 	}
 
 	// Get current branch
-	currentBranchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	currentBranchCmd.Dir = repoDir
+	currentBranchCmd := git.Command(repoDir, "rev-parse", "--abbrev-ref", "HEAD")
 	currentBranchOutput, err := currentBranchCmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to get current branch: %w", err)
@@ -209,27 +376,23 @@ func updateMainBranch(repoDir string, mainBranch string) error {
 
 	// If not on main, checkout main
 	if currentBranch != mainBranch {
-		checkoutCmd := exec.Command("git", "checkout", mainBranch)
-		checkoutCmd.Dir = repoDir
+		checkoutCmd := git.Command(repoDir, "checkout", mainBranch)
 		if err := checkoutCmd.Run(); err != nil {
 			return fmt.Errorf("git checkout %s failed: %w", mainBranch, err)
 		}
 	}
 
-	// Pull latest changes
-	pullCmd := exec.Command("git", "pull", "origin", mainBranch)
-	pullCmd.Dir = repoDir
-	if err := pullCmd.Run(); err != nil {
-		return fmt.Errorf("git pull failed: %w", err)
+	// Merge the fetched changes (like 'git pull', without fetching again)
+	if err := git.Merge(repoDir, "origin/"+mainBranch); err != nil {
+		return err
 	}
 
 	// Checkout back to original branch if needed
 	if currentBranch != mainBranch {
-		checkoutBackCmd := exec.Command("git", "checkout", currentBranch)
-		checkoutBackCmd.Dir = repoDir
+		checkoutBackCmd := git.Command(repoDir, "checkout", currentBranch)
 		if err := checkoutBackCmd.Run(); err != nil {
 			// Don't fail here, just warn
-			fmt.Printf("Warning: Could not checkout back to %s\n", currentBranch)
+			fmt.Fprintf(out, "Warning: Could not checkout back to %s\n", currentBranch)
 		}
 	}
 
@@ -237,22 +400,64 @@ func updateMainBranch(repoDir string, mainBranch string) error {
 }
 
 // rebaseBranch rebases the current branch on top of main
-func rebaseBranch(worktreePath string, branchName string, mainBranch string) error {
+func rebaseBranch(out io.Writer, worktreePath string, branchName string, mainBranch string) error {
 	// Ensure we're on the right branch
-	checkoutCmd := exec.Command("git", "checkout", branchName)
-	checkoutCmd.Dir = worktreePath
+	checkoutCmd := git.Command(worktreePath, "checkout", branchName)
 	if err := checkoutCmd.Run(); err != nil {
 		return fmt.Errorf("git checkout failed: %w", err)
 	}
 
 	// Rebase on main
-	rebaseCmd := exec.Command("git", "rebase", mainBranch)
-	rebaseCmd.Dir = worktreePath
-	rebaseCmd.Stdout = os.Stdout
-	rebaseCmd.Stderr = os.Stderr
+	rebaseCmd := git.Command(worktreePath, "rebase", mainBranch)
+	rebaseCmd.Stdout = out
+	rebaseCmd.Stderr = out
 	if err := rebaseCmd.Run(); err != nil {
 		return fmt.Errorf("git rebase failed (conflicts or other issues)")
 	}
 
 	return nil
 }
+
+// continueRebase continues a stopped rebase, keeping the existing commit messages
+func continueRebase(out io.Writer, worktreePath string) error {
+	continueCmd := git.Command(worktreePath, "rebase", "--continue")
+	continueCmd.Env = append(os.Environ(), "GIT_EDITOR=true")
+	continueCmd.Stdout = out
+	continueCmd.Stderr = out
+	if err := continueCmd.Run(); err != nil {
+		return fmt.Errorf("git rebase --continue failed (unresolved conflicts?)")
+	}
+
+	return nil
+}
+
+// abortRebases aborts the rebase in every project that is mid-rebase
+func abortRebases(featureDir string, workCfg *config.WorktreeConfig, projects []string, rebasing map[string]bool) {
+	if len(rebasing) == 0 {
+		ui.Info("No rebase in progress")
+		return
+	}
+
+	ui.Section("Aborting rebases...")
+	failed := false
+	for _, projectName := range projects {
+		if !rebasing[projectName] {
+			continue
+		}
+
+		abortCmd := git.Command(featureDir+"/"+workCfg.Projects[projectName].Dir, "rebase", "--abort")
+		if out, err := abortCmd.CombinedOutput(); err != nil {
+			ui.CrossMark(fmt.Sprintf("%s: git rebase --abort failed: %s", projectName, strings.TrimSpace(string(out))))
+			failed = true
+			continue
+		}
+		ui.CheckMark(fmt.Sprintf("%s rebase aborted", projectName))
+	}
+
+	ui.NewLine()
+	if failed {
+		os.Exit(1)
+	}
+	ui.Success("Rebase aborted")
+	ui.NewLine()
+}
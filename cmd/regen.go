@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var regenCmd = &cobra.Command{
+	Use:   "regen [feature-name]",
+	Short: "Regenerate a feature's files from the current configuration",
+	Long: `Re-run symlink creation, copies, generated_files and the IDE workspace for an existing
+feature, using the ports stored in the registry. Use this after editing
+templates in .worktree.yml instead of recreating the feature.
+
+Only files whose content changed are written; the versions they replace are
+snapshotted (undo with 'worktree sync <feature> --undo'). .worktree-env and
+the registry's computed vars are updated as well. Running services are not
+restarted.
+
+If no feature name is provided and you're in a worktree directory,
+the feature will be auto-detected from .worktree-instance.
+
+Examples:
+  worktree regen feature-user-auth
+  worktree regen                       # Auto-detect from current directory`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runRegen,
+}
+
+func runRegen(cmd *cobra.Command, args []string) {
+	var featureName string
+	if len(args) == 0 {
+		instance, err := config.DetectInstance()
+		if err != nil {
+			ui.Error("Not in a worktree directory and no feature name provided")
+			ui.Info("Usage: worktree regen <feature-name>")
+			os.Exit(1)
+		}
+		featureName = instance.Feature
+	} else {
+		featureName = registry.NormalizeBranchName(args[0])
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		os.Exit(1)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Regenerating Files: %s", featureName))
+	ui.NewLine()
+
+	wt.ComputedVars = syncFeature(cfg, workCfg, wt, featureName)
+	if err := reg.Save(); err != nil {
+		checkError(fmt.Errorf("failed to save registry: %w", err))
+	}
+
+	ui.NewLine()
+	ui.Success("Regeneration completed")
+	ui.NewLine()
+}
@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/docker"
@@ -16,7 +15,9 @@ import (
 )
 
 var (
-	forceRemove bool
+	forceRemove     bool
+	discardUnpushed bool
+	removeDryRun    bool
 )
 
 var removeCmd = &cobra.Command{
@@ -31,55 +32,58 @@ The feature name/branch is automatically normalized, so you can use either:
 This command performs safety checks:
 - Warns if feature is still running
 - Warns if there are uncommitted changes
-- Prompts for confirmation (unless --force is used)
+- Lists branches not merged into the main branch
+- Refuses to remove features with commits that are neither pushed nor merged
+  into the main branch, unless --force or --discard-unpushed is used
+- Prompts for confirmation (unless --force or --yes is used)
+- Asks for extra confirmation when run from inside a different feature's worktree
 - Removes from registry
 
+--dry-run prints the compose and git commands each project would run and the
+files and registry entry that would be removed, without removing anything.
+
 Examples:
   worktree remove feature-user-auth           # Using normalized name
   worktree remove feature/user-auth           # Using branch name
-  worktree remove feature/reports --force`,
+  worktree remove feature/reports --force
+  worktree remove feature/spike --discard-unpushed  # Drop local-only commits, still confirm
+  worktree remove feature/spike --dry-run           # Preview without removing`,
 	Args: cobra.ExactArgs(1),
 	Run:  runRemove,
 }
 
 func init() {
 	removeCmd.Flags().BoolVarP(&forceRemove, "force", "f", false, "skip confirmation prompts")
+	removeCmd.Flags().BoolVar(&discardUnpushed, "discard-unpushed", false, "remove even if the feature has unpushed, unmerged commits")
+	removeCmd.Flags().BoolVar(&removeDryRun, "dry-run", false, "preview commands and files without removing anything")
 }
 
 func runRemove(cmd *cobra.Command, args []string) {
 	input := args[0]
-	verbose, _ := cmd.Flags().GetBool("verbose")
 
 	// Normalize the input to match behavior of new-feature command
 	// This allows users to use either the normalized name or the original branch name
 	featureName := registry.NormalizeBranchName(input)
-	if verbose {
-		ui.Info(fmt.Sprintf("Normalized input '%s' to feature name '%s'", input, featureName))
-	}
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Normalized input '%s' to feature name '%s'", input, featureName))
 
 	// Get configuration
 	cfg, err := config.New()
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
-	}
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
 
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
-	}
+	configureContainerRuntime(workCfg)
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded registry with %d worktrees", len(reg.Worktrees)))
-	}
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Loaded registry with %d worktrees", len(reg.Worktrees)))
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
 		fmt.Println("\nAvailable features:")
@@ -88,11 +92,22 @@ func runRemove(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
+
+	if !removeDryRun {
+		guardCrossFeature("remove", featureName, forceRemove)
+	}
 
 	// Check if worktree directory exists
 	if !cfg.WorktreeExists(featureName) {
 		ui.Warning(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		if removeDryRun {
+			previewRemove(cfg, workCfg, wt, false)
+			return
+		}
 		ui.Info("Removing from registry only...")
+		removeProxyConfig(cfg, workCfg, featureName)
+		updateFeatureHosts(workCfg, featureName, nil)
 
 		if err := reg.Remove(featureName); err != nil {
 			ui.Error(fmt.Sprintf("Failed to remove from registry: %v", err))
@@ -103,6 +118,7 @@ func runRemove(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 
+		sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventRemoved, wt.Projects)
 		ui.Success("Removed from registry")
 		os.Exit(0)
 	}
@@ -144,30 +160,41 @@ func runRemove(cmd *cobra.Command, args []string) {
 	}
 	ui.NewLine()
 
-	// Always stop services before removing (prevents stale containers)
-	ui.Info("Stopping services (if running)...")
-	featurePath := cfg.WorktreeFeaturePath(featureName)
-
-	// Build map of project directory to compose project name
-	projectInfo := make(map[string]string)
-	for _, projectName := range projects {
-		if projectCfg, exists := workCfg.Projects[projectName]; exists {
-			// Get the compose project name for this project from registry
-			composeName := wt.GetComposeProject(projectName)
-			if composeName == "" {
-				// Fallback to default naming if not in registry
-				composeName = fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, featureName, projectName)
-			}
-			projectInfo[projectCfg.Dir] = composeName
+	// Committed work that exists only on the local branches is easy to lose once
+	// the worktrees are gone, so it needs explicit consent
+	unpushed, unmerged := unsavedCommits(featureDir, workCfg, projects)
+	if len(unmerged) > 0 {
+		ui.Warning("Not merged into the main branch:")
+		for _, msg := range unmerged {
+			ui.PrintStatusLine("", msg)
+		}
+		ui.NewLine()
+	}
+	if len(unpushed) > 0 {
+		ui.Warning("Commits not pushed to a remote or merged into the main branch:")
+		for _, msg := range unpushed {
+			ui.PrintStatusLine("", msg)
+		}
+		ui.NewLine()
+		if !forceRemove && !discardUnpushed && !removeDryRun {
+			ui.Error("Refusing to remove a feature with unpushed commits")
+			ui.Info("Push them first (worktree push " + featureName + "), or use --discard-unpushed or --force")
+			os.Exit(1)
 		}
 	}
 
-	if err := docker.StopFeature(workCfg.ProjectName, featureName, featurePath, projectInfo); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to stop services: %v", err))
-		ui.Info("Continuing with removal...")
-	} else {
-		ui.CheckMark("Services stopped")
+	if removeDryRun {
+		if len(unpushed) > 0 && !forceRemove && !discardUnpushed {
+			ui.Warning("Remove would refuse because of the unpushed commits (use --discard-unpushed or --force)")
+			ui.NewLine()
+		}
+		previewRemove(cfg, workCfg, wt, true)
+		return
 	}
+
+	// Always stop services before removing (prevents stale containers)
+	ui.Info("Stopping services (if running)...")
+	stopFeatureServices(cfg, workCfg, wt)
 	ui.NewLine()
 
 	// Check for uncommitted changes in all projects
@@ -204,21 +231,130 @@ func runRemove(cmd *cobra.Command, args []string) {
 	}
 
 	// Confirm removal
-	if !forceRemove {
-		fmt.Print("Are you sure you want to remove this worktree? [y/N]: ")
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-
-		if response != "y" && response != "yes" {
-			ui.Info("Removal cancelled")
-			os.Exit(0)
-		}
+	if !forceRemove && !ui.Confirm("Are you sure you want to remove this worktree?", false) {
+		ui.Info("Removal cancelled")
+		os.Exit(0)
 	}
 
 	// Remove worktrees for all projects
 	ui.NewLine()
 	ui.Loading("Removing worktrees...")
+	removeFeatureFiles(cfg, workCfg, reg, wt)
+
+	// Save registry
+	if err := reg.Save(); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to save registry: %v", err))
+	}
+
+	ui.Success("Cleanup complete")
+	ui.NewLine()
+}
+
+// previewRemove prints what remove would run and delete, for --dry-run.
+// Without the feature directory, only the registry entry, proxy config and
+// hosts entries would be removed.
+func previewRemove(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, dirExists bool) {
+	preview := newDryRunPreview(cfg.ProjectRoot)
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+
+	var files []string
+	if dirExists {
+		for _, projectName := range wt.Projects {
+			project, exists := workCfg.Projects[projectName]
+			if !exists {
+				continue
+			}
+			projectDir, _ := filepath.Abs(filepath.Join(cfg.ProjectRoot, project.Dir))
+			worktreePath, _ := filepath.Abs(filepath.Join(featureDir, project.Dir))
+			composeProject := composeProjectFor(workCfg, wt, projectName)
+			preview.project(projectName, worktreePath, composeProject)
+			preview.composeDown(composeProject)
+			preview.command("remove worktree", fmt.Sprintf("git -C %s worktree remove %s", projectDir, worktreePath))
+			preview.command("prune", fmt.Sprintf("git -C %s worktree prune", projectDir))
+			ui.NewLine()
+		}
+		files = append(files, featureDir)
+	}
+	if workCfg.Proxy.Enabled() {
+		if path, err := workCfg.ProxyFilePath(cfg.ProjectRoot, wt.Normalized); err == nil {
+			files = append(files, path)
+		}
+	}
+	if workCfg.Hosts.Enabled {
+		files = append(files, workCfg.Hosts.GetFile()+" (entries of this feature)")
+	}
+	files = append(files, registry.FilePath(cfg.WorktreeDir)+" (entry '"+wt.Normalized+"')")
+	preview.files("To remove:", files)
+	preview.done("remove the feature")
+}
+
+// unsavedCommits reports, per project, commits not merged into the main branch
+// and the subset of those that is not on any remote either
+func unsavedCommits(featureDir string, workCfg *config.WorktreeConfig, projects []string) (unpushed, unmerged []string) {
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			continue
+		}
+
+		mainBranch := project.MainBranch
+		if mainBranch == "" {
+			mainBranch = "main"
+		}
+		mainRef := git.MainRef(worktreePath, mainBranch)
+
+		if count, err := git.UnmergedCommits(worktreePath, mainRef); err == nil && count > 0 {
+			unmerged = append(unmerged, fmt.Sprintf("%s: %d commits not in %s", projectName, count, mainRef))
+		}
+		if count, err := git.UnpushedCommits(worktreePath, mainRef); err == nil && count > 0 {
+			unpushed = append(unpushed, fmt.Sprintf("%s: %d unpushed commits", projectName, count))
+		}
+	}
+	return unpushed, unmerged
+}
+
+// stopFeatureServices stops a feature's containers ahead of removal, reporting
+// failures as warnings so removal can continue
+func stopFeatureServices(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) {
+	featureName := wt.Normalized
+	featurePath := cfg.WorktreeFeaturePath(featureName)
+
+	// Build map of project directory to compose project name
+	projectInfo := make(map[string]string)
+	for _, projectName := range wt.Projects {
+		if projectCfg, exists := workCfg.Projects[projectName]; exists {
+			// Get the compose project name for this project from registry
+			composeName := wt.GetComposeProject(projectName)
+			if composeName == "" {
+				// Fallback to default naming if not in registry
+				composeName = fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, featureName, projectName)
+			}
+			projectInfo[projectCfg.Dir] = composeName
+		}
+	}
+
+	if err := docker.StopFeature(workCfg.ProjectName, featureName, featurePath, projectInfo); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to stop services: %v", err))
+		ui.Info("Continuing with removal...")
+	} else {
+		ui.CheckMark("Services stopped")
+	}
+}
+
+// removeFeatureFiles removes a feature's git worktrees, prunes worktree metadata,
+// deletes the feature directory and drops the registry entry. The caller saves the registry.
+func removeFeatureFiles(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, wt *registry.Worktree) {
+	featureName := wt.Normalized
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	projects := wt.Projects
+
+	removeProxyConfig(cfg, workCfg, featureName)
+	updateFeatureHosts(workCfg, featureName, nil)
 
 	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
@@ -266,12 +402,17 @@ func runRemove(cmd *cobra.Command, args []string) {
 	} else {
 		ui.CheckMark("Removed from registry")
 	}
+	sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventRemoved, projects)
+}
 
-	// Save registry
-	if err := reg.Save(); err != nil {
-		ui.Warning(fmt.Sprintf("Failed to save registry: %v", err))
+// removeProxyConfig deletes the feature's reverse-proxy rules, if any
+func removeProxyConfig(cfg *config.Config, workCfg *config.WorktreeConfig, featureName string) {
+	if !workCfg.Proxy.Enabled() {
+		return
+	}
+	if err := workCfg.RemoveProxyConfig(cfg.ProjectRoot, featureName); err != nil {
+		ui.Warning(err.Error())
+	} else {
+		ui.CheckMark("Removed proxy config")
 	}
-
-	ui.Success("Cleanup complete")
-	ui.NewLine()
 }
@@ -3,11 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/exec"
 	"github.com/braunmar/worktree/pkg/process"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
@@ -15,6 +15,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	restartProjects []string
+	restartDryRun   bool
+)
+
 var restartCmd = &cobra.Command{
 	Use:   "restart [feature-name]",
 	Short: "Restart services for a feature worktree",
@@ -30,6 +35,12 @@ Use restart_pre/post_command for restart-specific operations:
 - restart_pre_command: backup state, drain connections, etc.
 - restart_post_command: verify health, warm caches, etc.
 
+--project restarts only the named projects (repeatable), leaving other
+services of the feature untouched.
+
+--dry-run prints the hooks, stop and start commands of each project, the env
+vars they get and the files restart would change, without running anything.
+
 This is useful when:
 - Configuration has changed
 - You need to pick up new environment variables
@@ -37,7 +48,9 @@ This is useful when:
 
 Examples:
   worktree restart feature-user-auth
-  worktree restart                    # Auto-detect from current directory`,
+  worktree restart                    # Auto-detect from current directory
+  worktree restart feature-x --project backend
+  worktree restart feature-x --dry-run  # Preview without restarting`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runRestart,
 }
@@ -68,14 +81,19 @@ func runRestart(cmd *cobra.Command, args []string) {
 	checkError(err)
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
+
+	projects, err := selectProjects(wt.Projects, restartProjects)
+	checkError(err)
 
 	featureDir := cfg.WorktreeFeaturePath(featureName)
 
@@ -96,6 +114,13 @@ func runRestart(cmd *cobra.Command, args []string) {
 	for service, port := range wt.Ports {
 		baseEnvVars[service] = fmt.Sprintf("%d", port)
 	}
+	applyFeatureOverrides(featureDir, baseEnvVars)
+
+	if restartDryRun {
+		previewRestart(cfg, workCfg, wt, featureDir, projects, baseEnvVars)
+		return
+	}
+	baseEnvVars = withSecretEnvVars(cfg.ProjectRoot, workCfg, baseEnvVars)
 
 	envList := os.Environ()
 	for key, value := range baseEnvVars {
@@ -103,18 +128,20 @@ func runRestart(cmd *cobra.Command, args []string) {
 	}
 
 	// Phase 1: restart_pre_command for each project
-	for _, projectName := range wt.Projects {
+	hooks := newHookRunner(featureDir, reg, wt)
+	for _, projectName := range projects {
 		if project, ok := workCfg.Projects[projectName]; ok {
 			worktreePath := featureDir + "/" + project.Dir
 			composeProject := wt.GetComposeProject(projectName)
 			projectEnv := append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
-			runHookCommand(fmt.Sprintf("%s: restart_pre_command", projectName), project.RestartPreCommand, worktreePath, projectEnv)
+			projectEnv = append(projectEnv, composeFileEnv(workCfg, featureDir, projectName)...)
+			hooks.run(projectName, "restart_pre", project.RestartPreCommand, project.Hook("restart_pre"), worktreePath, projectEnv)
 		}
 	}
 
 	// Phase 2: Stop services (NO stop_pre/post hooks)
 	ui.Loading("Stopping services...")
-	for _, projectName := range wt.Projects {
+	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
 			continue
@@ -146,7 +173,7 @@ func runRestart(cmd *cobra.Command, args []string) {
 
 	// Phase 3: Start services (NO start_pre/post hooks)
 	ui.Loading("Starting services...")
-	for _, projectName := range wt.Projects {
+	for _, projectName := range projects {
 		project := workCfg.Projects[projectName]
 		worktreePath := featureDir + "/" + project.Dir
 
@@ -157,6 +184,7 @@ func runRestart(cmd *cobra.Command, args []string) {
 		}
 		composeProject := wt.GetComposeProject(projectName)
 		projectEnvList = append(projectEnvList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
+		projectEnvList = append(projectEnvList, composeFileEnv(workCfg, featureDir, projectName)...)
 
 		// Start service
 		var startErr error
@@ -165,34 +193,65 @@ func runRestart(cmd *cobra.Command, args []string) {
 			pidFile := filepath.Join(featureDir, projectName+".pid")
 			startErr = process.StartBackground(projectName, project.StartCommand, worktreePath, projectEnvList, pidFile)
 		default: // "docker"
-			shellCmd := exec.Command("sh", "-c", project.StartCommand)
-			shellCmd.Dir = worktreePath
-			shellCmd.Env = projectEnvList
-			shellCmd.Stdout = os.Stdout
-			shellCmd.Stderr = os.Stderr
-			startErr = shellCmd.Run()
+			startErr = hooks.start(projectName, project.StartCommand, worktreePath, projectEnvList)
 		}
 		if startErr != nil {
 			ui.Error(fmt.Sprintf("Failed to start %s: %v", projectName, startErr))
+			hooks.finish()
 			os.Exit(1)
 		}
 	}
 	ui.NewLine()
 
 	// Phase 4: restart_post_command for each project
-	for _, projectName := range wt.Projects {
+	for _, projectName := range projects {
 		if project, ok := workCfg.Projects[projectName]; ok {
 			worktreePath := featureDir + "/" + project.Dir
 			composeProject := wt.GetComposeProject(projectName)
 			projectEnv := append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
-			runHookCommand(fmt.Sprintf("%s: restart_post_command", projectName), project.RestartPostCommand, worktreePath, projectEnv)
+			projectEnv = append(projectEnv, composeFileEnv(workCfg, featureDir, projectName)...)
+			hooks.run(projectName, "restart_post", project.RestartPostCommand, project.Hook("restart_post"), worktreePath, projectEnv)
 		}
 	}
 
+	cacheFeatureStatus(featureDir, true)
+	sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventStarted, projects)
+
 	ui.Success(fmt.Sprintf("Feature '%s' restarted", featureName))
 	ui.NewLine()
+	hooks.finish()
+}
+
+// previewRestart prints what restart would run and write, for --dry-run
+func previewRestart(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, featureDir string, projects []string, envVars map[string]string) {
+	preview := newDryRunPreview(cfg.ProjectRoot)
+	preview.env(workCfg, envVars)
+
+	files := []string{registry.FilePath(cfg.WorktreeDir), exec.LogDir(featureDir)}
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+		composeProject := composeProjectFor(workCfg, wt, projectName)
+		preview.project(projectName, featureDir+"/"+project.Dir, composeProject)
+		preview.command("restart_pre_command", project.RestartPreCommand)
+		preview.stopProject(workCfg, projectName, composeProject, featureDir)
+		preview.startProject(workCfg, projectName, featureDir)
+		preview.command("restart_post_command", project.RestartPostCommand)
+		ui.NewLine()
+
+		if project.GetExecutor() == "process" {
+			files = append(files, filepath.Join(featureDir, projectName+".pid"))
+		}
+	}
+	files = append(files, config.StatusCachePath(featureDir))
+	preview.files("Files to change:", files)
+	preview.done("restart the feature")
 }
 
 func init() {
+	restartCmd.Flags().StringSliceVar(&restartProjects, "project", nil, "restart only this project of the feature (repeatable)")
+	restartCmd.Flags().BoolVar(&restartDryRun, "dry-run", false, "preview commands, env vars and files without restarting anything")
 	rootCmd.AddCommand(restartCmd)
 }
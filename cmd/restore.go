@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/archive"
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	restoreNewPorts  bool
+	restoreNoVolumes bool
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <archive-file>",
+	Short: "Recreate a feature environment from an archive",
+	Long: `Recreate a feature archived with 'worktree archive'.
+
+This command:
+1. Recreates the git worktrees on the feature branch (if the branch was
+   deleted, it is recreated at the archived commit)
+2. Re-registers the feature with its original ports, or newly allocated
+   ones if those are taken (always new ones with --new-ports)
+3. Restores the instance marker, overrides and generated files, re-rendering
+   generated files and .worktree-env.json when the ports changed
+4. Recreates archived container volumes (skip with --no-volumes)
+
+Services are not started; run 'worktree start <feature>' afterwards.
+
+Examples:
+  worktree restore feature-user-auth-20260301-101500.tar.gz
+  worktree restore reports.tar.gz --new-ports`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRestore,
+}
+
+func init() {
+	restoreCmd.Flags().BoolVar(&restoreNewPorts, "new-ports", false, "allocate new ports instead of reusing the archived ones")
+	restoreCmd.Flags().BoolVar(&restoreNoVolumes, "no-volumes", false, "do not recreate archived volumes")
+}
+
+func runRestore(cmd *cobra.Command, args []string) {
+	archivePath := args[0]
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+	configureContainerRuntime(workCfg)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	extractDir, err := os.MkdirTemp("", "worktree-restore-*")
+	checkError(err)
+	defer os.RemoveAll(extractDir)
+
+	manifest, err := archive.Extract(archivePath, extractDir)
+	checkError(err)
+
+	if manifest.ProjectName != workCfg.ProjectName {
+		checkError(fmt.Errorf("archive belongs to project '%s', not '%s'", manifest.ProjectName, workCfg.ProjectName))
+	}
+
+	archived := manifest.Worktree
+	featureName := archived.Normalized
+	if _, exists := reg.Get(featureName); exists || cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature '%s' already exists", featureName))
+		fmt.Println("\nRemove it first with:")
+		ui.PrintCommand(fmt.Sprintf("  worktree remove %s", featureName))
+		os.Exit(1)
+	}
+
+	for _, projectName := range archived.Projects {
+		if _, ok := workCfg.Projects[projectName]; !ok {
+			checkError(fmt.Errorf("project '%s' from the archive is not configured in .worktree.yml", projectName))
+		}
+	}
+
+	ui.Section(fmt.Sprintf("Restoring %s (archived %s)", featureName, manifest.ArchivedAt.Format("2006-01-02 15:04")))
+
+	// Ports: reuse the archived ones when possible; services added to the config
+	// since archiving get new ports, services removed from it are dropped
+	services := workCfg.GetPortServiceNames()
+	ports := make(map[string]int)
+	var missing []string
+	for _, service := range services {
+		if port, ok := archived.Ports[service]; ok && !restoreNewPorts {
+			ports[service] = port
+		} else {
+			missing = append(missing, service)
+		}
+	}
+	if err := reg.CheckPorts(ports); err != nil {
+		ui.Warning(fmt.Sprintf("Cannot reuse archived ports: %v", err))
+		ports = make(map[string]int)
+		missing = services
+	}
+
+	wt := &registry.Worktree{
+		Branch:          archived.Branch,
+		Branches:        archived.Branches,
+		Normalized:      featureName,
+		Created:         archived.Created,
+		Projects:        archived.Projects,
+		Ports:           ports,
+		ComposeProjects: archived.ComposeProjects,
+		ComposeProject:  archived.ComposeProject,
+		YoloMode:        archived.YoloMode,
+		Preset:          archived.Preset,
+		BaseRef:         archived.BaseRef,
+		NoFixtures:      archived.NoFixtures,
+	}
+	// Register first so newly allocated ports do not collide with the reused ones
+	checkError(reg.Add(wt))
+	if len(missing) > 0 {
+		allocated, err := allocatePorts(cfg, workCfg, reg, missing, featureName)
+		checkError(err)
+		for service, port := range allocated {
+			ports[service] = port
+		}
+	}
+	portsChanged := false
+	for service, port := range ports {
+		if archived.Ports[service] != port {
+			portsChanged = true
+		}
+	}
+	if portsChanged {
+		ui.CheckMark("Ports allocated (changed since archiving)")
+	} else {
+		ui.CheckMark("Archived ports reused")
+	}
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	if err := os.MkdirAll(featureDir, 0755); err != nil {
+		checkError(fmt.Errorf("failed to create feature directory: %w", err))
+	}
+
+	for _, projectName := range wt.Projects {
+		project := workCfg.Projects[projectName]
+		projectDir := filepath.Join(cfg.ProjectRoot, project.Dir)
+		branch := wt.BranchFor(projectName)
+
+		if !git.BranchExists(projectDir, branch) {
+			if commit := manifest.Commits[projectName]; commit != "" {
+				if err := git.CreateBranch(projectDir, branch, commit); err != nil {
+					ui.Warning(fmt.Sprintf("%s: %v", projectName, err))
+				} else {
+					ui.Info(fmt.Sprintf("%s: recreated branch %s at %.8s", projectName, branch, commit))
+				}
+			}
+		}
+
+		if err := git.CreateWorktree(projectDir, filepath.Join(featureDir, project.Dir), branch, ""); err != nil {
+			checkError(fmt.Errorf("failed to create %s worktree: %w", projectName, err))
+		}
+		ui.CheckMark(fmt.Sprintf("Created %s worktree", projectName))
+
+		if project.Submodules {
+			initSubmodules(projectName, filepath.Join(featureDir, project.Dir))
+		}
+	}
+
+	for _, rel := range manifest.Files {
+		data, err := os.ReadFile(archive.ExtractedFile(extractDir, rel))
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Failed to read archived %s: %v", rel, err))
+			continue
+		}
+		target := filepath.Join(featureDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to restore %s: %v", rel, err))
+			continue
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to restore %s: %v", rel, err))
+		}
+	}
+	ui.CheckMark(fmt.Sprintf("Restored %d files", len(manifest.Files)))
+
+	// The archived marker records the old project root and ports
+	instance := featureInstance(workCfg, wt)
+	if err := config.WriteInstanceMarker(featureDir, featureName, instance, cfg.ProjectRoot, wt.Projects, wt.Ports, wt.YoloMode); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to write instance marker: %v", err))
+	}
+	if len(workCfg.InstanceEnv) > 0 {
+		if err := config.UpdateInstanceEnv(featureDir, workCfg.GetInstanceEnvNames()); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to record instance_env in marker: %v", err))
+		}
+	}
+
+	// Symlinks and copies are not archived; generated files only change when the
+	// ports (or the config) did
+	wt.ComputedVars = syncFeature(cfg, workCfg, wt, featureName)
+
+	if err := reg.Save(); err != nil {
+		checkError(fmt.Errorf("failed to save registry: %w", err))
+	}
+	ui.CheckMark("Registry updated")
+
+	if len(manifest.Volumes) > 0 {
+		if restoreNoVolumes {
+			ui.Info(fmt.Sprintf("Skipping %d archived volumes (--no-volumes)", len(manifest.Volumes)))
+		} else {
+			importFeatureVolumes(manifest.Volumes, extractDir)
+		}
+	}
+
+	ui.NewLine()
+	ui.Success(fmt.Sprintf("Feature '%s' restored", featureName))
+	for _, service := range sortedKeys(wt.Ports) {
+		ui.PrintStatusLine("  "+service, fmt.Sprintf("%d", wt.Ports[service]))
+	}
+	ui.Info(fmt.Sprintf("Start it with: worktree start %s", featureName))
+}
+
+// importFeatureVolumes recreates archived volumes, leaving existing volumes untouched
+func importFeatureVolumes(volumes []archive.Volume, extractDir string) {
+	for _, vol := range volumes {
+		if docker.VolumeExists(vol.Name) {
+			ui.Warning(fmt.Sprintf("Volume %s already exists, not overwriting it", vol.Name))
+			continue
+		}
+
+		dockerVol := docker.Volume{Name: vol.Name, ComposeVolume: vol.ComposeVolume}
+		if err := docker.ImportVolume(dockerVol, vol.ComposeProject, archive.ExtractedVolume(extractDir, vol.Name)); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to restore volume %s: %v", vol.Name, err))
+			continue
+		}
+		ui.CheckMark(fmt.Sprintf("Restored volume %s", vol.Name))
+	}
+}
@@ -4,6 +4,12 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/ui"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +19,10 @@ var (
 	commit  = "none" // overridden by -ldflags at build time
 )
 
+// skipGitCheckAnnotation marks commands that must not spend time checking
+// the git version at startup (Annotations: {skipGitCheckAnnotation: "true"})
+const skipGitCheckAnnotation = "skip-git-check"
+
 var rootCmd = &cobra.Command{
 	Use:   "worktree",
 	Short: "Manage git worktrees for multi-instance development",
@@ -22,8 +32,16 @@ This tool helps you create, manage, and remove coordinated git worktrees for mul
 projects, integrated with multi-instance Docker setups.`,
 }
 
-// Execute runs the root command
-func Execute() error {
+// Execute runs the root command. A panic is turned into a local crash dump
+// and an error, unless WORKTREE_CRASH_DUMPS=0.
+func Execute() (err error) {
+	if !crashDumpsDisabled() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = reportCrash(r, debug.Stack())
+			}
+		}()
+	}
 	return rootCmd.Execute()
 }
 
@@ -46,7 +64,58 @@ func init() {
 	rootCmd.Version = version + " (" + commit + ")"
 
 	// Add global flags
-	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringP("verbose", "v", "", "verbose output: details and the git/docker commands being run; --verbose=git,registry limits it to those scopes ("+strings.Join(ui.Scopes, ", ")+") (or set WORKTREE_LOG_LEVEL=verbose or WORKTREE_DEBUG=<scopes>)")
+	rootCmd.PersistentFlags().Lookup("verbose").NoOptDefVal = "all"
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "only print errors, warnings and requested data (or set WORKTREE_LOG_LEVEL=quiet)")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+	rootCmd.PersistentFlags().Bool("accessible", false, "screen-reader friendly output: no emoji or colors, OK/WARN/ERROR words (or set WORKTREE_ACCESSIBLE=1)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "plain ASCII output without colors or emoji, as used when stdout is not a terminal (or set NO_COLOR)")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "answer yes to every confirmation prompt")
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "never wait for input: confirmation prompts take their default answer (no)")
+
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		accessible, _ := cmd.Flags().GetBool("accessible")
+		if env := os.Getenv("WORKTREE_ACCESSIBLE"); env != "" && env != "0" && env != "false" {
+			accessible = true
+		}
+		if accessible {
+			ui.SetAccessible(true)
+		}
+		// CI logs and pipes get ASCII markers instead of emoji and ANSI colors
+		if noColor, _ := cmd.Flags().GetBool("no-color"); noColor || ui.NoColorRequested() || !ui.StdoutIsTerminal() {
+			ui.SetPlain(true)
+		}
+
+		level, err := ui.ParseLevel(os.Getenv("WORKTREE_LOG_LEVEL"))
+		checkError(err)
+		// WORKTREE_DEBUG=git,registry traces some scopes, the flag overrides it
+		scopes := os.Getenv("WORKTREE_DEBUG")
+		verbose := scopes != "" && scopes != "0" && scopes != "false"
+		if cmd.Flags().Changed("verbose") {
+			scopes, _ = cmd.Flags().GetString("verbose")
+			verbose = true
+		}
+		if verbose {
+			selected, err := ui.ParseScopes(scopes)
+			checkError(err)
+			ui.SetVerboseScopes(selected)
+			level = ui.LevelVerbose
+		}
+		if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+			level = ui.LevelQuiet
+		}
+		ui.SetLevel(level)
+
+		yes, _ := cmd.Flags().GetBool("yes")
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+		ui.SetAssumeYes(yes)
+		ui.SetNonInteractive(nonInteractive)
+
+		// Refuse to run against a git too old for worktree instead of failing halfway
+		if gitCheckNeeded(cmd) {
+			checkError(git.CheckMinVersion())
+		}
+	}
 
 	// Add subcommands
 	rootCmd.AddCommand(removeCmd)
@@ -64,8 +133,30 @@ func init() {
 	rootCmd.AddCommand(newFeatureCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(yoloCmd)
+	rootCmd.AddCommand(claudeCmd)
 	rootCmd.AddCommand(agentCmd)
 	rootCmd.AddCommand(getEnvCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(hostsCmd)
+	rootCmd.AddCommand(overrideCmd)
+	rootCmd.AddCommand(flagsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(regenCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(serveStatusCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(describeCmd)
+	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(stashCmd)
+	rootCmd.AddCommand(unstashCmd)
+	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(contextCmd)
+	addCompletionDoctor(rootCmd)
 
 	// Customize help template
 	rootCmd.SetHelpTemplate(`{{.Long}}
@@ -83,9 +174,27 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.
 `)
 }
 
+// gitCheckNeeded reports whether cmd checks the git version before it runs;
+// help, shell completion and annotated commands skip the check
+func gitCheckNeeded(cmd *cobra.Command) bool {
+	if cmd.Annotations[skipGitCheckAnnotation] == "true" {
+		return false
+	}
+	switch cmd.Name() {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	return cmd.Parent() == nil || cmd.Parent().Name() != "completion"
+}
+
 func checkError(err error) {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// configureContainerRuntime selects docker or podman for pkg/docker based on container_runtime
+func configureContainerRuntime(workCfg *config.WorktreeConfig) {
+	checkError(docker.Configure(workCfg.ContainerRuntime))
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/process"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveListen    string
+	serveReadOnly  bool
+	serveActionMux sync.Mutex // start/stop run one at a time
+)
+
+var serveStatusCmd = &cobra.Command{
+	Use:   "serve-status",
+	Short: "Serve an HTML status page listing features and their URLs",
+	Long: `Serve a small status page for this machine: every feature with its branch,
+running state and service URLs, plus start/stop buttons.
+
+Meant for teammates pairing on your machine, so they can find the right URLs
+without asking. The page is plain HTML (no JavaScript) and re-reads the
+registry on every request.
+
+Endpoints:
+  GET  /                              HTML status page
+  GET  /api/features                  Features as JSON
+  POST /api/features/{name}/start     Run 'worktree start <name>'
+  POST /api/features/{name}/stop      Run 'worktree stop <name>'
+
+Start/stop are rejected when --read-only is set and for cross-site requests.
+Anyone who can reach the listen address can use them, so bind to a LAN
+address only on trusted networks.
+
+Examples:
+  worktree serve-status                        # http://127.0.0.1:7788
+  worktree serve-status --listen :7788         # Reachable from the LAN
+  worktree serve-status --listen :7788 --read-only`,
+	Args: cobra.NoArgs,
+	Run:  runServeStatus,
+}
+
+func init() {
+	serveStatusCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:7788", "address to listen on")
+	serveStatusCmd.Flags().BoolVar(&serveReadOnly, "read-only", false, "hide start/stop buttons and reject start/stop requests")
+}
+
+// featureStatus is one feature as shown on the status page and returned by the API
+type featureStatus struct {
+	Name     string            `json:"name"`
+	Branch   string            `json:"branch"`
+	Projects []string          `json:"projects"`
+	Running  bool              `json:"running"`
+	Missing  bool              `json:"missing,omitempty"` // Registry entry without directory
+	Services map[string]string `json:"services"`          // Display name -> URL
+}
+
+// actionResult is the API response of a start/stop request
+type actionResult struct {
+	Feature string `json:"feature"`
+	Action  string `json:"action"`
+	Success bool   `json:"success"`
+	Output  string `json:"output"`
+}
+
+func runServeStatus(cmd *cobra.Command, args []string) {
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		features, err := loadFeatureStatuses(cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		statusPage.Execute(w, map[string]any{
+			"Project":  workCfg.ProjectName,
+			"Features": features,
+			"ReadOnly": serveReadOnly,
+			"Message":  r.URL.Query().Get("msg"),
+			"Updated":  time.Now().Format("15:04:05"),
+		})
+	})
+	mux.HandleFunc("GET /api/features", func(w http.ResponseWriter, r *http.Request) {
+		features, err := loadFeatureStatuses(cfg)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, features)
+	})
+	mux.HandleFunc("POST /api/features/{name}/{action}", func(w http.ResponseWriter, r *http.Request) {
+		handleFeatureAction(cfg, w, r)
+	})
+
+	ln, err := net.Listen("tcp", serveListen)
+	checkError(err)
+
+	ui.Success(fmt.Sprintf("Serving status page on http://%s", displayAddr(ln.Addr())))
+	if serveReadOnly {
+		ui.Info("Read-only: start/stop disabled")
+	}
+	ui.Info("Press Ctrl+C to stop")
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	checkError(server.Serve(ln))
+}
+
+// handleFeatureAction runs start or stop for a feature by invoking this binary,
+// so the exact CLI behavior (hooks, generated files, status cache) applies
+func handleFeatureAction(cfg *config.Config, w http.ResponseWriter, r *http.Request) {
+	name, action := r.PathValue("name"), r.PathValue("action")
+	fromForm := r.Header.Get("Content-Type") == "application/x-www-form-urlencoded"
+
+	reply := func(status int, result actionResult) {
+		if fromForm {
+			http.Redirect(w, r, "/?msg="+url.QueryEscape(actionMessage(result)), http.StatusSeeOther)
+			return
+		}
+		writeJSON(w, status, result)
+	}
+	result := actionResult{Feature: name, Action: action}
+
+	switch {
+	case action != "start" && action != "stop":
+		result.Output = "unknown action"
+		reply(http.StatusNotFound, result)
+		return
+	case serveReadOnly:
+		result.Output = "server is read-only"
+		reply(http.StatusForbidden, result)
+		return
+	case !sameOrigin(r):
+		result.Output = "cross-origin request rejected"
+		writeJSON(w, http.StatusForbidden, result)
+		return
+	}
+
+	reg, err := registry.Load(cfg.WorktreeDir, nil)
+	if err != nil {
+		result.Output = err.Error()
+		reply(http.StatusInternalServerError, result)
+		return
+	}
+	if _, exists := reg.Get(name); !exists {
+		result.Output = fmt.Sprintf("feature '%s' not found", name)
+		reply(http.StatusNotFound, result)
+		return
+	}
+
+	serveActionMux.Lock()
+	defer serveActionMux.Unlock()
+
+	output, err := runSelf(cfg.ProjectRoot, action, name)
+	result.Output = output
+	result.Success = err == nil
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusInternalServerError
+	}
+	reply(status, result)
+}
+
+// runSelf runs this binary with args from dir and returns its combined output.
+// Output goes through a temp file rather than a pipe: services started in the
+// background inherit it and would otherwise keep the request open.
+func runSelf(dir string, args ...string) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate worktree binary: %w", err)
+	}
+
+	out, err := os.CreateTemp("", "worktree-serve-*.log")
+	if err != nil {
+		return "", fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.Env = append(os.Environ(), "WORKTREE_ACCESSIBLE=1") // Plain words instead of emoji in the output
+	runErr := cmd.Run()
+
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	data, _ := io.ReadAll(out)
+	return string(data), runErr
+}
+
+// loadFeatureStatuses reads config and registry fresh and describes every feature
+func loadFeatureStatuses(cfg *config.Config) ([]featureStatus, error) {
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	if err != nil {
+		return nil, err
+	}
+	configureContainerRuntime(workCfg)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	features := []featureStatus{}
+	for _, wt := range reg.List() {
+		features = append(features, featureStatus{
+			Name:     wt.Normalized,
+			Branch:   wt.Branch,
+			Projects: wt.Projects,
+			Running:  featureRunning(cfg, workCfg, wt),
+			Missing:  !cfg.WorktreeExists(wt.Normalized),
+			Services: workCfg.GetDisplayableServices(wt.Ports),
+		})
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return features, nil
+}
+
+// featureRunning reports whether any of the feature's containers or
+// process-executor services is running
+func featureRunning(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) bool {
+	for _, projectName := range wt.Projects {
+		if project, ok := workCfg.Projects[projectName]; ok && project.GetExecutor() == "process" {
+			if process.IsRunning(filepath.Join(cfg.WorktreeFeaturePath(wt.Normalized), projectName+".pid")) {
+				return true
+			}
+		}
+	}
+	return docker.IsFeatureRunning(workCfg.ProjectName, wt.Normalized)
+}
+
+// sameOrigin rejects browser requests posted from another site. Requests without
+// an Origin header (curl, scripts) are allowed.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// actionMessage summarizes an action result for the HTML page
+func actionMessage(result actionResult) string {
+	if result.Success {
+		return fmt.Sprintf("%s: %s done", result.Feature, result.Action)
+	}
+	return fmt.Sprintf("%s: %s failed: %s", result.Feature, result.Action, lastLine(result.Output))
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// displayAddr turns a wildcard listen address into something clickable
+func displayAddr(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil || (host != "::" && host != "0.0.0.0" && host != "") {
+		return addr.String()
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		return net.JoinHostPort(hostname, port)
+	}
+	return net.JoinHostPort("localhost", port)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Project}} worktrees</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+  table { border-collapse: collapse; width: 100%; }
+  th, td { text-align: left; padding: .5rem .75rem; border-bottom: 1px solid #ddd; vertical-align: top; }
+  .running { color: #1a7f37; font-weight: 600; }
+  .stopped { color: #777; }
+  .missing { color: #b35900; }
+  .msg { background: #f3f3f3; padding: .5rem .75rem; margin-bottom: 1rem; }
+  form { display: inline; }
+  small { color: #777; }
+</style>
+</head>
+<body>
+<h1>{{.Project}} worktrees</h1>
+{{if .Message}}<p class="msg">{{.Message}}</p>{{end}}
+{{if .Features}}
+<table>
+  <tr><th>Feature</th><th>Branch</th><th>Status</th><th>Services</th>{{if not .ReadOnly}}<th></th>{{end}}</tr>
+  {{range .Features}}
+  <tr>
+    <td><strong>{{.Name}}</strong><br><small>{{range $i, $p := .Projects}}{{if $i}}, {{end}}{{$p}}{{end}}</small></td>
+    <td>{{.Branch}}</td>
+    <td>{{if .Missing}}<span class="missing">directory missing</span>{{else if .Running}}<span class="running">running</span>{{else}}<span class="stopped">stopped</span>{{end}}</td>
+    <td>{{range $name, $url := .Services}}{{$name}}: <a href="{{$url}}">{{$url}}</a><br>{{end}}</td>
+    {{if not $.ReadOnly}}<td>{{if not .Missing}}
+      <form method="post" action="/api/features/{{.Name}}/start"><button>Start</button></form>
+      <form method="post" action="/api/features/{{.Name}}/stop"><button>Stop</button></form>
+    {{end}}</td>{{end}}
+  </tr>
+  {{end}}
+</table>
+{{else}}
+<p>No features yet. Create one with <code>worktree new-feature &lt;branch&gt;</code>.</p>
+{{end}}
+<p><small>Updated {{.Updated}} &middot; reload to refresh</small></p>
+</body>
+</html>
+`))
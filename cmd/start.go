@@ -3,11 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/exec"
+	"github.com/braunmar/worktree/pkg/health"
 	"github.com/braunmar/worktree/pkg/process"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
@@ -16,8 +21,14 @@ import (
 )
 
 var (
-	noFixtures bool
-	presetName string
+	noFixtures    bool
+	presetName    string
+	startProjects []string
+	startAttach   bool
+	startDryRun   bool
+	skipPreflight bool
+
+	startRemoveOrphans bool
 )
 
 var startCmd = &cobra.Command{
@@ -28,6 +39,33 @@ var startCmd = &cobra.Command{
 Starts ALL projects defined in the preset sequentially. Works with detached Docker
 services that return immediately.
 
+--project starts only the named projects (repeatable): their hooks and
+compose projects run, other services of the feature are left untouched.
+
+Services the feature has no port for (reclaimed by another feature through
+on_exhausted: steal-stopped, or added to .worktree.yml since) get one
+allocated first.
+
+Projects with health_checks are probed after their start_command until every
+check passes; start fails when one does not within its timeout, before
+running start_post_command.
+
+--dry-run prints the commands each project would run (with their directory
+and compose project), the resolved env vars and the files start would write,
+without running or writing anything.
+
+Before anything is written, a pre-flight check verifies that the container
+runtime's daemon and compose are available and that the disk has
+min_free_disk_mb free (default 1024). --skip-preflight or
+WORKTREE_SKIP_PREFLIGHT=1 skips it.
+
+--remove-orphans sets COMPOSE_REMOVE_ORPHANS=1 for the start commands, so
+'docker compose up' removes containers of services no longer in the compose
+file ('worktree status' lists them).
+
+--attach follows the logs of the started docker projects once they are up
+and healthy, until Ctrl+C; detaching leaves the services running.
+
 If no feature name is provided and you're in a worktree directory,
 the feature will be auto-detected from .worktree-instance.
 
@@ -35,7 +73,10 @@ Examples:
   worktree start feature-user-auth                  # Explicit feature name
   worktree start                                    # Auto-detect from current directory
   worktree start feature-reports --preset backend   # Use specific preset
-  worktree start feature-api --no-fixtures          # Skip post-startup tasks`,
+  worktree start feature-api --no-fixtures          # Skip post-startup tasks
+  worktree start feature-api --project backend      # Start only the backend
+  worktree start feature-api --attach               # Start, then follow logs
+  worktree start feature-api --dry-run              # Preview commands, env and files`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runStart,
 }
@@ -43,12 +84,16 @@ Examples:
 func init() {
 	startCmd.Flags().BoolVar(&noFixtures, "no-fixtures", false, "skip post-startup tasks")
 	startCmd.Flags().StringVar(&presetName, "preset", "", "preset to use (defaults to default_preset from config)")
+	startCmd.Flags().StringSliceVar(&startProjects, "project", nil, "start only this project of the feature (repeatable)")
+	startCmd.Flags().BoolVar(&startAttach, "attach", false, "follow the started projects' logs until Ctrl+C (services keep running)")
+	startCmd.Flags().BoolVar(&startDryRun, "dry-run", false, "preview commands, env vars and files without starting anything")
+	startCmd.Flags().BoolVar(&skipPreflight, "skip-preflight", false, "skip the container runtime and disk space checks")
+	startCmd.Flags().BoolVar(&startRemoveOrphans, "remove-orphans", false, "let compose remove containers of services no longer in the compose file (COMPOSE_REMOVE_ORPHANS)")
 }
 
 func runStart(cmd *cobra.Command, args []string) {
 	var featureName string
 	autoDetected := false
-	verbose, _ := cmd.Flags().GetBool("verbose")
 
 	// Auto-detect feature name if not provided
 	if len(args) == 0 {
@@ -68,27 +113,21 @@ func runStart(cmd *cobra.Command, args []string) {
 	// Get configuration
 	cfg, err := config.New()
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
-	}
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded configuration from: %s", cfg.ProjectRoot))
 
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
-	}
+	ui.Verbose(ui.ScopeConfig, fmt.Sprintf("Loaded worktree configuration with %d projects", len(workCfg.Projects)))
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
-	if verbose {
-		ui.Info(fmt.Sprintf("Loaded registry from: %s", cfg.WorktreeDir))
-		ui.Info(fmt.Sprintf("Found %d existing worktrees", len(reg.Worktrees)))
-	}
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Loaded registry from: %s", cfg.WorktreeDir))
+	ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("Found %d existing worktrees", len(reg.Worktrees)))
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 
@@ -109,6 +148,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Check if worktree directory exists
 	if !cfg.WorktreeExists(featureName) {
@@ -116,6 +156,11 @@ func runStart(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	// Ports reclaimed by on_exhausted: steal-stopped are allocated again
+	if !startDryRun {
+		checkError(ensureFeaturePorts(cfg, workCfg, reg, wt))
+	}
+
 	// Get preset (from flag or use projects from registry)
 	var projects []string
 	if presetName != "" {
@@ -126,6 +171,9 @@ func runStart(cmd *cobra.Command, args []string) {
 		projects = wt.Projects
 	}
 
+	projects, err = selectProjects(projects, startProjects)
+	checkError(err)
+
 	if len(projects) == 0 {
 		ui.Error("No projects found")
 		os.Exit(1)
@@ -158,25 +206,39 @@ func runStart(cmd *cobra.Command, args []string) {
 	// Export all environment variables (includes allocated ports + calculated values like INSTANCE, LOCALSTACK_EXT_*)
 	baseEnvVars := workCfg.ExportEnvVars(instance)
 	baseEnvVars["FEATURE_NAME"] = featureName
+	baseEnvVars["YOLO"] = strconv.FormatBool(wt.YoloMode)
 
 	// Override with allocated ports from registry
 	for service, port := range wt.Ports {
 		baseEnvVars[service] = fmt.Sprintf("%d", port)
 	}
 
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	config.AddPathVars(baseEnvVars, featureDir)
+
 	// Recompute value-template vars (e.g., GOOGLE_OAUTH_REDIRECT_URI) now that actual
 	// allocated ports are in baseEnvVars. Without this, they resolve against base port
 	// expressions (always 3000, 8080, etc.) instead of the real allocated ports.
-	workCfg.ResolveValueVars(instance, baseEnvVars)
+	// Per-feature overrides (.worktree-overrides.yml) go in first so an overridden
+	// port reaches them too.
+	overrides := resolveWithOverrides(workCfg, instance, featureDir, baseEnvVars)
+
+	if startDryRun {
+		previewStart(cfg, workCfg, wt, featureDir, projects, baseEnvVars)
+		return
+	}
+	configureContainerRuntime(workCfg)
+	runPreflight(cfg, workCfg, projects, true, skipPreflight)
 
 	// Persist all resolved env vars to registry for visibility and debugging
 	wt.ComputedVars = workCfg.GetComputedVars(baseEnvVars)
+	for key, value := range overrides {
+		wt.ComputedVars[key] = value
+	}
 	if err := reg.Save(); err != nil {
 		ui.Warning(fmt.Sprintf("Failed to update registry computed vars: %v", err))
 	}
 
-	featureDir := cfg.WorktreeFeaturePath(featureName)
-
 	// Keep .worktree-env in sync with recomputed vars
 	if err := config.WriteEnvFile(featureDir, wt.ComputedVars); err != nil {
 		ui.Warning(fmt.Sprintf("Failed to update .worktree-env: %v", err))
@@ -188,8 +250,16 @@ func runStart(cmd *cobra.Command, args []string) {
 			ui.Warning(fmt.Sprintf("Failed to generate files for %s: %v", projectName, err))
 		}
 	}
+	writeFeatureFlags(workCfg, featureDir, projects, baseEnvVars)
+	writeComposeOverrides(workCfg, featureDir, featureName, projects, baseEnvVars)
+	refreshFeatureContext(cfg, workCfg, wt)
+
+	// Secrets only reach the started processes; everything persisted above excludes them
+	baseEnvVars = withSecretEnvVars(cfg.ProjectRoot, workCfg, baseEnvVars)
 
 	// Start ALL projects sequentially
+	startedAt := time.Now()
+	hooks := newHookRunner(featureDir, reg, wt)
 	for _, projectName := range projects {
 		project := workCfg.Projects[projectName]
 		worktreePath := featureDir + "/" + project.Dir
@@ -197,6 +267,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		// Check if worktree exists
 		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
 			ui.Error(fmt.Sprintf("Worktree for %s does not exist: %s", projectName, worktreePath))
+			hooks.finish()
 			os.Exit(1)
 		}
 
@@ -208,15 +279,17 @@ func runStart(cmd *cobra.Command, args []string) {
 		// Add service-specific compose project name
 		composeProject := wt.GetComposeProject(projectName)
 		envList = append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
-
-		if verbose {
-			ui.Info(fmt.Sprintf("Starting %s with COMPOSE_PROJECT_NAME=%s", projectName, composeProject))
-			ui.Info(fmt.Sprintf("Start command: %s", project.StartCommand))
-			ui.Info(fmt.Sprintf("Working directory: %s", worktreePath))
+		envList = append(envList, composeFileEnv(workCfg, featureDir, projectName)...)
+		if startRemoveOrphans {
+			envList = append(envList, "COMPOSE_REMOVE_ORPHANS=1")
 		}
 
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("Starting %s with COMPOSE_PROJECT_NAME=%s", projectName, composeProject))
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("Start command: %s", project.StartCommand))
+		ui.Verbose(ui.ScopeDocker, fmt.Sprintf("Working directory: %s", worktreePath))
+
 		// Pre-start hook
-		runHookCommand(fmt.Sprintf("%s: start_pre_command", projectName), project.StartPreCommand, worktreePath, envList)
+		hooks.run(projectName, "start_pre", project.StartPreCommand, project.Hook("start_pre"), worktreePath, envList)
 
 		// Start services
 		ui.Loading(fmt.Sprintf("Starting %s...", projectName))
@@ -229,36 +302,178 @@ func runStart(cmd *cobra.Command, args []string) {
 			pidFile := filepath.Join(featureDir, projectName+".pid")
 			startErr = process.StartBackground(projectName, project.StartCommand, worktreePath, envList, pidFile)
 		default: // "docker"
-			shellCmd := exec.Command("sh", "-c", project.StartCommand)
-			shellCmd.Dir = worktreePath
-			shellCmd.Env = envList
-			shellCmd.Stdout = os.Stdout
-			shellCmd.Stderr = os.Stderr
-			startErr = shellCmd.Run()
+			startErr = hooks.start(projectName, project.StartCommand, worktreePath, envList)
 		}
 		if startErr != nil {
 			ui.Error(fmt.Sprintf("Failed to start %s: %v", projectName, startErr))
+			hooks.finish()
 			os.Exit(1)
 		}
 
 		ui.NewLine()
 		ui.Success(fmt.Sprintf("%s started!", projectName))
+		if !waitForHealthy(workCfg, projectName, worktreePath, baseEnvVars, envList) {
+			ui.Error(fmt.Sprintf("%s did not become healthy", projectName))
+			ui.Info(fmt.Sprintf("Check its logs with: worktree logs %s", featureName))
+			hooks.finish()
+			os.Exit(1)
+		}
 		ui.NewLine()
 
 		// Post-start hook (unless --no-fixtures)
 		if !noFixtures {
-			runHookCommand(fmt.Sprintf("%s: start_post_command", projectName), project.StartPostCommand, worktreePath, envList)
+			hooks.run(projectName, "start_post", project.StartPostCommand, project.Hook("start_post"), worktreePath, envList)
 		}
 	}
 
+	cacheFeatureStatus(featureDir, true)
+	sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventStarted, projects)
+
 	// Show final summary
-	ui.Success("All services started!")
+	if len(startProjects) > 0 {
+		ui.Success(fmt.Sprintf("Started %s", strings.Join(projects, ", ")))
+	} else {
+		ui.Success("All services started!")
+	}
 	ui.NewLine()
 	displayServices = workCfg.GetDisplayableServices(wt.Ports)
 	for name, url := range displayServices {
 		ui.PrintStatusLine(name, url)
 	}
 	ui.NewLine()
+	hooks.finish()
+
+	if startAttach {
+		attachLogs(workCfg, wt, featureName, featureDir, projects, startedAt)
+	}
+}
+
+// previewStart prints what start would run and write, for --dry-run
+func previewStart(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, featureDir string, projects []string, envVars map[string]string) {
+	preview := newDryRunPreview(cfg.ProjectRoot)
+	preview.env(workCfg, envVars)
+
+	files := []string{registry.FilePath(cfg.WorktreeDir), config.EnvFilePath(featureDir)}
+	for _, projectName := range projects {
+		project := workCfg.Projects[projectName]
+		worktreePath := featureDir + "/" + project.Dir
+		preview.project(projectName, worktreePath, composeProjectFor(workCfg, wt, projectName))
+		preview.command("start_pre_command", project.StartPreCommand)
+		preview.startProject(workCfg, projectName, featureDir)
+		preview.healthChecks(workCfg, projectName)
+		if !noFixtures {
+			preview.command("start_post_command", project.StartPostCommand)
+		}
+		ui.NewLine()
+
+		for _, file := range workCfg.GeneratedFiles[projectName] {
+			files = append(files, filepath.Join(worktreePath, file.Path))
+		}
+		if project.ExtraHosts.Enabled() {
+			files = append(files, workCfg.ComposeOverridePath(featureDir, projectName))
+		}
+	}
+	if workCfg.FeatureFlags.Enabled() {
+		for _, projectName := range workCfg.FeatureFlags.FlagProjects(projects) {
+			files = append(files, filepath.Join(featureDir, workCfg.Projects[projectName].Dir, workCfg.FeatureFlags.Path))
+		}
+	}
+	if claudeProject := getClaudeWorkingProject(workCfg, wt.Projects); claudeProject != "" {
+		files = append(files, filepath.Join(featureDir, workCfg.Projects[claudeProject].Dir, contextFile))
+	}
+	files = append(files, config.StatusCachePath(featureDir), exec.LogDir(featureDir))
+	preview.files("Files to write:", files)
+	preview.done("start the feature")
+}
+
+// attachLogs follows the logs the started projects wrote since startedAt
+// until Ctrl+C, which detaches without stopping anything
+func attachLogs(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName, featureDir string, projects []string, startedAt time.Time) {
+	configureContainerRuntime(workCfg)
+	envList := buildStopEnvList(workCfg, wt, featureName, featureDir)
+	args := buildComposeLogsArgs(true, -1, startedAt.Format(time.RFC3339))
+	logCmds := composeLogCommands(workCfg, wt, featureName, featureDir, projects, envList, args)
+	if len(logCmds) == 0 {
+		ui.Warning("No docker projects to attach to")
+		return
+	}
+
+	ui.Info(fmt.Sprintf("Attached to logs of feature %s - Ctrl+C to detach", featureName))
+	ui.NewLine()
+
+	// Ctrl+C also reaches compose, which exits; catching it here keeps start
+	// alive to report the detach
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	if !streamLogs(logCmds, true) {
+		ui.Warning("Could not follow all logs")
+	}
+	ui.NewLine()
+	ui.Success("Detached - services are still running")
+	ui.Info(fmt.Sprintf("Stop them with: worktree stop %s", featureName))
+}
+
+// withSecretEnvVars returns envVars plus the env variables read from secrets
+// backends. Exits when a secret cannot be resolved, since services would
+// otherwise start without their credentials.
+func withSecretEnvVars(projectRoot string, workCfg *config.WorktreeConfig, envVars map[string]string) map[string]string {
+	if !workCfg.HasSecrets() {
+		return envVars
+	}
+
+	secrets, err := workCfg.ResolveSecrets(projectRoot)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to resolve secrets: %v", err))
+		os.Exit(1)
+	}
+	merged := make(map[string]string, len(envVars)+len(secrets))
+	for key, value := range envVars {
+		merged[key] = value
+	}
+	for key, value := range secrets {
+		merged[key] = value
+	}
+	ui.CheckMark(fmt.Sprintf("Resolved %d secrets", len(secrets)))
+	return merged
+}
+
+// waitForHealthy runs a project's health_checks until each passes, stopping at
+// the first one that never becomes healthy. Returns whether all passed.
+func waitForHealthy(workCfg *config.WorktreeConfig, projectName, worktreePath string, envVars map[string]string, envList []string) bool {
+	for _, check := range workCfg.Projects[projectName].HealthChecks {
+		check = workCfg.ResolveHealthCheck(check, envVars)
+		spinner := ui.StartSpinner(fmt.Sprintf("Waiting for %s: %s...", projectName, check.Label()))
+		err := health.Wait(check, worktreePath, envList)
+		spinner.Stop()
+		if err != nil {
+			ui.CrossMark(fmt.Sprintf("%s: %s %v", projectName, check.Label(), err))
+			return false
+		}
+		ui.CheckMark(fmt.Sprintf("%s: %s is healthy", projectName, check.Label()))
+	}
+	return true
+}
+
+// selectProjects narrows a feature's projects to the ones named with
+// --project, in the feature's order. No names selects every project.
+func selectProjects(projects, only []string) ([]string, error) {
+	if len(only) == 0 {
+		return projects, nil
+	}
+	for _, name := range only {
+		if !slices.Contains(projects, name) {
+			return nil, fmt.Errorf("project '%s' is not part of this feature (projects: %s)", name, strings.Join(projects, ", "))
+		}
+	}
+	var selected []string
+	for _, project := range projects {
+		if slices.Contains(only, project) {
+			selected = append(selected, project)
+		}
+	}
+	return selected, nil
 }
 
 // findSimilarFeatures finds feature names similar to the input using simple string matching
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash <feature-name-or-branch>",
+	Short: "Stash uncommitted changes in all project worktrees",
+	Long: `Stash the uncommitted changes (including untracked files) of every project
+worktree of a feature.
+
+Each stash entry is tagged with the feature name, so 'worktree unstash'
+restores the right entry even though all worktrees of a repository share one
+stash list. Projects without changes are skipped.
+
+Examples:
+  worktree stash feature-user-auth
+  worktree rebase feature-user-auth
+  worktree unstash feature-user-auth`,
+	Args: cobra.ExactArgs(1),
+	Run:  runStash,
+}
+
+var unstashCmd = &cobra.Command{
+	Use:   "unstash <feature-name-or-branch>",
+	Short: "Restore changes stashed with 'worktree stash'",
+	Long: `Pop the most recent stash entry tagged with the feature name in every
+project worktree of the feature.
+
+When a pop conflicts, git keeps the stash entry: resolve the conflicts and
+drop it with 'git stash drop' in that worktree.
+
+Examples:
+  worktree unstash feature-user-auth
+  worktree unstash feature/user-auth`,
+	Args: cobra.ExactArgs(1),
+	Run:  runUnstash,
+}
+
+func runStash(cmd *cobra.Command, args []string) {
+	featureName, featureDir, workCfg, wt := loadStashFeature(args[0])
+
+	ui.PrintHeader(fmt.Sprintf("Stashing Feature: %s", featureName))
+	ui.NewLine()
+
+	stashed := stashFeature(featureName, featureDir, workCfg, wt.Projects)
+	ui.NewLine()
+	if len(stashed) == 0 {
+		ui.Info("No uncommitted changes to stash")
+		return
+	}
+	ui.Success(fmt.Sprintf("Stashed changes in %d project(s)", len(stashed)))
+	ui.Info(fmt.Sprintf("💡 Restore them with: worktree unstash %s", featureName))
+	ui.NewLine()
+}
+
+func runUnstash(cmd *cobra.Command, args []string) {
+	featureName, featureDir, workCfg, wt := loadStashFeature(args[0])
+
+	ui.PrintHeader(fmt.Sprintf("Unstashing Feature: %s", featureName))
+	ui.NewLine()
+
+	if !unstashFeature(featureName, featureDir, workCfg, wt.Projects) {
+		ui.NewLine()
+		ui.Error("Some stashes could not be restored")
+		ui.Info("💡 Resolve the conflicts, then run 'git stash drop' in those worktrees")
+		os.Exit(1)
+	}
+	ui.NewLine()
+}
+
+// loadStashFeature resolves the feature for stash/unstash, exiting when it
+// does not exist
+func loadStashFeature(input string) (string, string, *config.WorktreeConfig, *registry.Worktree) {
+	featureName := registry.NormalizeBranchName(input)
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		os.Exit(1)
+	}
+
+	return featureName, cfg.WorktreeFeaturePath(featureName), workCfg, wt
+}
+
+// stashMessage is the message tagging a feature's stash entries
+func stashMessage(featureName string) string {
+	return "worktree:" + featureName
+}
+
+// stashFeature stashes the changes of every project worktree that has any and
+// returns those projects. It exits on the first failure.
+func stashFeature(featureName, featureDir string, workCfg *config.WorktreeConfig, projects []string) []string {
+	var stashed []string
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			continue
+		}
+
+		ok, err := git.Stash(worktreePath, stashMessage(featureName))
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to stash %s: %v", projectName, err))
+			os.Exit(1)
+		}
+		if ok {
+			stashed = append(stashed, projectName)
+			ui.CheckMark(fmt.Sprintf("%s changes stashed", projectName))
+		}
+	}
+	return stashed
+}
+
+// unstashFeature pops the feature's stash entry in every project worktree
+// that has one. It returns false when a pop failed.
+func unstashFeature(featureName, featureDir string, workCfg *config.WorktreeConfig, projects []string) bool {
+	allOk := true
+	found := false
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			continue
+		}
+
+		ref, err := git.FindStash(worktreePath, stashMessage(featureName))
+		if err != nil {
+			ui.CrossMark(fmt.Sprintf("%s: %v", projectName, err))
+			allOk = false
+			continue
+		}
+		if ref == "" {
+			continue
+		}
+		found = true
+
+		if err := git.StashPop(worktreePath, ref); err != nil {
+			ui.CrossMark(fmt.Sprintf("%s: %v", projectName, err))
+			allOk = false
+			continue
+		}
+		ui.CheckMark(fmt.Sprintf("%s changes restored", projectName))
+	}
+
+	if !found {
+		ui.Info(fmt.Sprintf("No stashed changes for %s", featureName))
+	}
+	return allOk
+}
@@ -3,10 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/health"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
 
@@ -19,21 +19,36 @@ var statusCmd = &cobra.Command{
 	Long: `Show detailed status for a specific feature worktree.
 
 This command shows:
-- Running status
+- Running status and cumulative runtime
 - Port mapping
 - Container health
+- Readiness of projects with health_checks (probed once)
 - Worktree location
 
 If no feature name is provided and you're in a worktree directory,
 the feature will be auto-detected from .worktree-instance.
 
+With --copy, the feature's worktree path is put on the system clipboard;
+--copy=<project> copies a project's worktree path and --copy=<service> a
+service URL. The clipboard tool is pbcopy, clip, wl-copy, xclip, xsel or
+clip.exe, or the command in WORKTREE_CLIPBOARD.
+
 Examples:
   worktree status feature-user-auth    # Explicit feature name
-  worktree status                      # Auto-detect from current directory`,
+  worktree status                      # Auto-detect from current directory
+  worktree status --copy               # Copy the feature path
+  worktree status --copy=backend       # Copy the backend worktree path`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runStatus,
 }
 
+var statusCopy string
+
+func init() {
+	statusCmd.Flags().StringVar(&statusCopy, "copy", "", "Copy the feature path, a project path or a service URL to the clipboard")
+	statusCmd.Flags().Lookup("copy").NoOptDefVal = copyDefault
+}
+
 func runStatus(cmd *cobra.Command, args []string) {
 	var featureName string
 	autoDetected := false
@@ -60,13 +75,14 @@ func runStatus(cmd *cobra.Command, args []string) {
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		fmt.Println("\nAvailable features:")
@@ -75,16 +91,25 @@ func runStatus(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Display header
 	ui.PrintHeader(fmt.Sprintf("Status for Feature: %s", featureName))
 	if autoDetected {
 		ui.Info("✨ Auto-detected from current directory")
 	}
+	if statusCopy != "" {
+		what, text, err := featureCopyTarget(cfg, workCfg, wt, statusCopy)
+		checkError(err)
+		copyToClipboard(what, text)
+	}
 	ui.NewLine()
 
 	// Show basic info
 	ui.PrintStatusLine("Branch", wt.Branch)
+	for _, projectName := range sortedKeys(wt.Branches) {
+		ui.PrintStatusLine("  "+projectName, wt.Branches[projectName])
+	}
 	ui.PrintStatusLine("Created", wt.Created.Format("2006-01-02 15:04"))
 
 	// Show YOLO mode status
@@ -118,9 +143,14 @@ func runStatus(cmd *cobra.Command, args []string) {
 
 	// Check if feature is running
 	running := docker.IsFeatureRunning(workCfg.ProjectName, featureName)
+	cacheFeatureStatus(cfg.WorktreeFeaturePath(featureName), running)
+	runtime := featureRuntime(cfg.WorktreeFeaturePath(featureName))
 
 	if running {
 		ui.PrintStatusLine("Status", "🟢 Running")
+		if runtime != "" {
+			ui.PrintStatusLine("Runtime", runtime)
+		}
 		ui.NewLine()
 
 		// Show port mapping from registry
@@ -133,8 +163,8 @@ func runStatus(cmd *cobra.Command, args []string) {
 
 		// Show container health
 		ui.PrintHeader("Container Health")
-		dockerCmd := exec.Command(
-			"docker", "ps",
+		dockerCmd := docker.Current().Command(
+			"ps",
 			"--filter", fmt.Sprintf("name=%s-%s-", workCfg.ProjectName, featureName),
 			"--format", "table {{.Names}}\t{{.Status}}",
 		)
@@ -143,10 +173,49 @@ func runStatus(cmd *cobra.Command, args []string) {
 		if err == nil {
 			fmt.Println(string(output))
 		}
+
+		printReadiness(cfg, workCfg, wt)
+		printServiceOrphans(cfg, workCfg, wt)
 	} else {
 		ui.PrintStatusLine("Status", "⚪ Not running")
+		if runtime != "" {
+			ui.PrintStatusLine("Runtime", runtime)
+		}
 		ui.NewLine()
 		ui.Info(fmt.Sprintf("Start with: worktree start %s", featureName))
 		ui.NewLine()
 	}
 }
+
+// printReadiness probes the health_checks of every project of a feature once
+func printReadiness(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) {
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+	var envList []string
+	var envVars map[string]string
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok || len(project.HealthChecks) == 0 {
+			continue
+		}
+		if envVars == nil {
+			ui.PrintHeader("Readiness")
+			envVars, _ = featureEnvVars(workCfg, wt, wt.Normalized, featureDir)
+			envList = os.Environ()
+			for key, value := range envVars {
+				envList = append(envList, fmt.Sprintf("%s=%s", key, value))
+			}
+		}
+
+		for _, check := range project.HealthChecks {
+			check = workCfg.ResolveHealthCheck(check, envVars)
+			if err := health.Probe(check, featureDir+"/"+project.Dir, envList); err != nil {
+				ui.CrossMark(fmt.Sprintf("%s: %s %v", projectName, check.Label(), err))
+			} else {
+				ui.CheckMark(fmt.Sprintf("%s: %s is healthy", projectName, check.Label()))
+			}
+		}
+	}
+	if envVars != nil {
+		ui.NewLine()
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/exec"
 	"github.com/braunmar/worktree/pkg/process"
 	"github.com/braunmar/worktree/pkg/registry"
 	"github.com/braunmar/worktree/pkg/ui"
@@ -14,25 +16,28 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// buildStopEnvList builds the environment variable list needed for stop hooks.
+// featureInstance derives a feature's instance number from its allocated ports.
 // Falls back to instance=0 if no ranged port is configured.
-func buildStopEnvList(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName string) []string {
-	instance := 0
-	if instancePortName, err := workCfg.GetInstancePortName(); err == nil {
-		if instancePortCfg, ok := workCfg.EnvVariables[instancePortName]; ok && instancePortCfg.Port != "" {
-			if basePort, err := config.ExtractBasePort(instancePortCfg.Port); err == nil {
-				if allocatedPort, ok := wt.Ports[instancePortName]; ok {
-					instance = allocatedPort - basePort
-				}
-			}
-		}
-	}
+func featureInstance(workCfg *config.WorktreeConfig, wt *registry.Worktree) int {
+	return workCfg.InstanceFromPorts(wt.Ports)
+}
+
+// buildStopEnvVars resolves the feature's env vars from its registry entry
+func buildStopEnvVars(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName, featureDir string) map[string]string {
+	instance := featureInstance(workCfg, wt)
 
 	baseEnvVars := workCfg.ExportEnvVars(instance)
 	baseEnvVars["FEATURE_NAME"] = featureName
 	for service, port := range wt.Ports {
 		baseEnvVars[service] = fmt.Sprintf("%d", port)
 	}
+	applyFeatureOverrides(featureDir, baseEnvVars)
+	return baseEnvVars
+}
+
+// buildStopEnvList builds the environment variable list needed for stop hooks.
+func buildStopEnvList(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName, featureDir string) []string {
+	baseEnvVars := buildStopEnvVars(workCfg, wt, featureName, featureDir)
 
 	envList := os.Environ()
 	for key, value := range baseEnvVars {
@@ -41,6 +46,13 @@ func buildStopEnvList(workCfg *config.WorktreeConfig, wt *registry.Worktree, fea
 	return envList
 }
 
+var (
+	forceStop         bool
+	stopProjects      []string
+	stopDryRun        bool
+	stopRemoveOrphans bool
+)
+
 var stopCmd = &cobra.Command{
 	Use:   "stop [feature-name]",
 	Short: "Stop services for a feature worktree",
@@ -54,13 +66,36 @@ This command:
 If no feature name is provided and you're in a worktree directory,
 the feature will be auto-detected from .worktree-instance.
 
+--project stops only the named projects (repeatable), running just their
+stop hooks and leaving other services of the feature untouched.
+
+When run from inside one feature's worktree with another feature's name,
+you are asked to confirm first (skip with --force).
+
+Containers of services that are no longer in a project's compose file
+(orphans, left over after editing it) are stopped along with the feature
+and kept; --remove-orphans removes them.
+
+--dry-run prints the hooks and compose commands each project would run, the
+env vars they get and the files stop would change, without stopping anything.
+
 Examples:
   worktree stop feature-user-auth    # Explicit feature name
-  worktree stop                      # Auto-detect from current directory`,
+  worktree stop                      # Auto-detect from current directory
+  worktree stop feature-x --project frontend
+  worktree stop feature-x --remove-orphans  # Also remove orphaned service containers
+  worktree stop feature-x --dry-run  # Preview without stopping`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runStop,
 }
 
+func init() {
+	stopCmd.Flags().BoolVarP(&forceStop, "force", "f", false, "skip the confirmation when targeting a feature other than the current one")
+	stopCmd.Flags().StringSliceVar(&stopProjects, "project", nil, "stop only this project of the feature (repeatable)")
+	stopCmd.Flags().BoolVar(&stopDryRun, "dry-run", false, "preview commands, env vars and files without stopping anything")
+	stopCmd.Flags().BoolVar(&stopRemoveOrphans, "remove-orphans", false, "remove containers of services no longer in the compose file")
+}
+
 func runStop(cmd *cobra.Command, args []string) {
 	var featureName string
 	autoDetected := false
@@ -87,13 +122,14 @@ func runStop(cmd *cobra.Command, args []string) {
 	// Load worktree configuration
 	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
 	checkError(err)
+	configureContainerRuntime(workCfg)
 
 	// Load registry
 	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		fmt.Println("\nAvailable features:")
@@ -102,6 +138,14 @@ func runStop(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
+
+	projects, err := selectProjects(wt.Projects, stopProjects)
+	checkError(err)
+
+	if !autoDetected && !stopDryRun {
+		guardCrossFeature("stop", featureName, forceStop)
+	}
 
 	// Display header
 	ui.Warning(fmt.Sprintf("Stopping Feature: %s", featureName))
@@ -114,12 +158,19 @@ func runStop(cmd *cobra.Command, args []string) {
 	// Get worktree path
 	featurePath := cfg.WorktreeFeaturePath(featureName)
 
+	if stopDryRun {
+		previewStop(cfg, workCfg, wt, featurePath, projects)
+		return
+	}
+
 	// Build env for hooks
-	envList := buildStopEnvList(workCfg, wt, featureName)
+	envList := buildStopEnvList(workCfg, wt, featureName, featurePath)
 
 	// Stop each project according to its executor
 	ui.Loading("Stopping services...")
-	for _, projectName := range wt.Projects {
+	hooks := newHookRunner(featurePath, reg, wt)
+	orphansSettled := make(map[string]bool)
+	for _, projectName := range projects {
 		project, exists := workCfg.Projects[projectName]
 		if !exists {
 			continue
@@ -131,8 +182,9 @@ func runStop(cmd *cobra.Command, args []string) {
 			composeProject = fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, featureName, projectName)
 		}
 		projectEnv := append(envList, fmt.Sprintf("COMPOSE_PROJECT_NAME=%s", composeProject))
+		projectEnv = append(projectEnv, composeFileEnv(workCfg, featurePath, projectName)...)
 
-		runHookCommand(fmt.Sprintf("%s: stop_pre_command", projectName), project.StopPreCommand, worktreePath, projectEnv)
+		hooks.run(projectName, "stop_pre", project.StopPreCommand, project.Hook("stop_pre"), worktreePath, projectEnv)
 
 		switch project.GetExecutor() {
 		case "process":
@@ -143,6 +195,11 @@ func runStop(cmd *cobra.Command, args []string) {
 				ui.Warning(fmt.Sprintf("Failed to stop %s: %v", projectName, err))
 			}
 		default: // "docker"
+			var orphans map[string]bool
+			if !orphansSettled[composeProject] {
+				orphansSettled[composeProject] = true
+				orphans = serviceOrphanNames(workCfg, wt, featurePath, projectName, composeProject)
+			}
 			if !docker.IsFeatureRunning(workCfg.ProjectName, featureName) {
 				ui.Info(fmt.Sprintf("%s is not running", projectName))
 			} else {
@@ -151,12 +208,56 @@ func runStop(cmd *cobra.Command, args []string) {
 					ui.Warning(fmt.Sprintf("Failed to stop %s: %v", projectName, err))
 				}
 			}
+			settleServiceOrphans(featureName, projectName, composeProject, orphans, stopRemoveOrphans)
 		}
 
-		runHookCommand(fmt.Sprintf("%s: stop_post_command", projectName), project.StopPostCommand, worktreePath, projectEnv)
+		hooks.run(projectName, "stop_post", project.StopPostCommand, project.Hook("stop_post"), worktreePath, projectEnv)
 	}
 
 	ui.NewLine()
-	ui.Success(fmt.Sprintf("Feature '%s' stopped", featureName))
+	if len(stopProjects) > 0 {
+		// Other projects may still be running, so the cached status is left as is
+		ui.Success(fmt.Sprintf("Stopped %s of feature '%s'", strings.Join(projects, ", "), featureName))
+	} else {
+		cacheFeatureStatus(featurePath, false)
+		ui.Success(fmt.Sprintf("Feature '%s' stopped", featureName))
+	}
 	ui.NewLine()
+	hooks.finish()
+	sendFeatureEvent(cfg, workCfg, wt, config.WebhookEventStopped, projects)
+}
+
+// previewStop prints what stop would run and write, for --dry-run
+func previewStop(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, featurePath string, projects []string) {
+	preview := newDryRunPreview(cfg.ProjectRoot)
+	preview.env(workCfg, buildStopEnvVars(workCfg, wt, wt.Normalized, featurePath))
+
+	var files []string
+	hooks := false
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+		hooks = hooks || project.StopPreCommand != "" || project.StopPostCommand != ""
+		composeProject := composeProjectFor(workCfg, wt, projectName)
+		preview.project(projectName, featurePath+"/"+project.Dir, composeProject)
+		preview.command("stop_pre_command", project.StopPreCommand)
+		preview.stopProject(workCfg, projectName, composeProject, featurePath)
+		preview.command("stop_post_command", project.StopPostCommand)
+		ui.NewLine()
+
+		if project.GetExecutor() == "process" {
+			files = append(files, filepath.Join(featurePath, projectName+".pid"))
+		}
+	}
+	if hooks {
+		// Hook runs are captured and recorded
+		files = append(files, registry.FilePath(cfg.WorktreeDir), exec.LogDir(featurePath))
+	}
+	if len(stopProjects) == 0 {
+		files = append(files, config.StatusCachePath(featurePath))
+	}
+	preview.files("Files to change:", files)
+	preview.done("stop the feature")
 }
@@ -62,6 +62,7 @@ func runStopAll(cmd *cobra.Command, args []string) {
 	// Execute make down-all in project directory
 	makeCmd := exec.Command("make", "down-all")
 	makeCmd.Dir = projectDir
+	ui.Trace(ui.ScopeDocker, makeCmd)
 	makeCmd.Stdout = os.Stdout
 	makeCmd.Stderr = os.Stderr
 
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncPush bool
+	syncUndo bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <feature-name-or-branch>",
+	Short: "Fetch and fast-forward all project worktrees of a feature",
+	Long: `Bring every project worktree of a feature up to date with origin.
+
+For each project this command:
+1. Runs git fetch origin
+2. Reports how many commits the feature branch is ahead of / behind
+   origin/<branch>, and how far it is behind the main branch
+3. Fast-forwards the branch when it is only behind (worktrees with
+   uncommitted changes are left alone)
+4. With --push, pushes branches that are ahead or not on origin yet
+
+It then refreshes the feature's .worktree-context.json (see 'worktree context').
+
+Diverged branches are never merged or rebased; use 'worktree pull' or
+'worktree rebase' for those. A failing project does not stop the others.
+
+Projects sharing a repository are fetched once. With fetch_ttl in
+.worktree.yml, repositories fetched within that duration are not fetched again.
+
+--undo reverts the last regeneration of the feature's generated files,
+symlinks and copies (by 'worktree watch', 'worktree restore' or the
+symlinks 'worktree new-feature' moved files out of the way for). Each
+regeneration snapshots the versions it replaces under
+worktrees/<feature>/.backup/<timestamp>/; --undo restores the newest
+snapshot and deletes it, so repeating it steps further back.
+
+Examples:
+  worktree sync feature-user-auth
+  worktree sync feature/user-auth --push
+  worktree sync feature-user-auth --undo   # Revert the last file regeneration`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSync,
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncPush, "push", false, "push branches that are ahead of origin")
+	syncCmd.Flags().BoolVar(&syncUndo, "undo", false, "restore the files replaced by the last regeneration instead of syncing")
+	syncCmd.MarkFlagsMutuallyExclusive("push", "undo")
+}
+
+func runSync(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		os.Exit(1)
+	}
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	if syncUndo {
+		undoFileSync(featureDir, featureName)
+		return
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Syncing Feature: %s", featureName))
+	ui.NewLine()
+
+	allOk := true
+	fetcher := git.NewFetchCoordinator(workCfg.GetFetchTTL())
+	for _, projectName := range wt.Projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			ui.Warning(fmt.Sprintf("Project '%s' not found in configuration, skipping", projectName))
+			continue
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			ui.Warning(fmt.Sprintf("Worktree for %s does not exist, skipping", projectName))
+			continue
+		}
+
+		ui.Section(projectName)
+		if !syncProject(fetcher, worktreePath, wt.BranchFor(projectName), project.MainBranch) {
+			allOk = false
+		}
+	}
+
+	refreshFeatureContext(cfg, workCfg, wt)
+
+	ui.NewLine()
+	if !allOk {
+		ui.Error("Sync finished with errors")
+		os.Exit(1)
+	}
+	ui.Success("Sync completed")
+	ui.NewLine()
+}
+
+// syncProject fetches, reports and fast-forwards (or pushes) one project
+// worktree. It returns false when a git operation failed.
+func syncProject(fetcher *git.FetchCoordinator, worktreePath, branch, mainBranch string) bool {
+	if !git.HasRemote(worktreePath, "origin") {
+		ui.Warning("No 'origin' remote, skipping")
+		return true
+	}
+
+	fetched, err := fetcher.Fetch(worktreePath, "origin")
+	if err != nil {
+		ui.CrossMark(err.Error())
+		return false
+	}
+	if !fetched {
+		ui.Info("origin was fetched recently, not fetching again")
+	}
+
+	if mainBranch == "" {
+		mainBranch = "main"
+	}
+	mainRef := git.MainRef(worktreePath, mainBranch)
+	if _, behind, err := git.AheadBehind(worktreePath, mainRef); err == nil && behind > 0 {
+		ui.Info(fmt.Sprintf("%d commits behind %s", behind, mainRef))
+	}
+
+	remoteRef := "origin/" + branch
+	if !git.RefExists(worktreePath, remoteRef) {
+		if !syncPush {
+			ui.Info(fmt.Sprintf("%s is not on origin yet (push with --push)", branch))
+			return true
+		}
+		return syncPushBranch(worktreePath, branch)
+	}
+
+	ahead, behind, err := git.AheadBehind(worktreePath, remoteRef)
+	if err != nil {
+		ui.CrossMark(err.Error())
+		return false
+	}
+
+	switch {
+	case ahead == 0 && behind == 0:
+		ui.CheckMark(fmt.Sprintf("Up to date with %s", remoteRef))
+	case ahead > 0 && behind > 0:
+		ui.Warning(fmt.Sprintf("Diverged from %s: %d ahead, %d behind", remoteRef, ahead, behind))
+		ui.Info("💡 Resolve with 'worktree pull' or 'worktree rebase'")
+	case behind > 0:
+		if changes, _ := git.HasUncommittedChanges(worktreePath); changes {
+			ui.Warning(fmt.Sprintf("%d commits behind %s; not fast-forwarding over uncommitted changes", behind, remoteRef))
+			return true
+		}
+		if err := git.FastForward(worktreePath, remoteRef); err != nil {
+			ui.CrossMark(err.Error())
+			return false
+		}
+		ui.CheckMark(fmt.Sprintf("Fast-forwarded %d commits from %s", behind, remoteRef))
+	default:
+		if !syncPush {
+			ui.Info(fmt.Sprintf("%d commits ahead of %s (push with --push)", ahead, remoteRef))
+			return true
+		}
+		return syncPushBranch(worktreePath, branch)
+	}
+	return true
+}
+
+func syncPushBranch(worktreePath, branch string) bool {
+	if err := git.Push(worktreePath, "origin", branch); err != nil {
+		ui.CrossMark(err.Error())
+		return false
+	}
+	ui.CheckMark(fmt.Sprintf("Pushed %s to origin", branch))
+	return true
+}
+
+// undoFileSync restores the newest file snapshot of a feature
+func undoFileSync(featureDir, featureName string) {
+	snapshots, err := config.Backups(featureDir)
+	checkError(err)
+	if len(snapshots) == 0 {
+		ui.Info(fmt.Sprintf("No file snapshots for %s; nothing to undo", featureName))
+		return
+	}
+
+	snapshot := snapshots[len(snapshots)-1]
+	ui.PrintHeader(fmt.Sprintf("Undoing File Sync: %s", featureName))
+	ui.NewLine()
+	ui.PrintStatusLine("Snapshot", filepath.Base(snapshot))
+	ui.NewLine()
+
+	restored, err := config.RestoreBackup(featureDir, snapshot)
+	for _, path := range restored {
+		ui.CheckMark(fmt.Sprintf("Reverted %s", path))
+	}
+	if err != nil {
+		ui.Error(err.Error())
+		os.Exit(1)
+	}
+
+	ui.NewLine()
+	if remaining := len(snapshots) - 1; remaining > 0 {
+		ui.Info(fmt.Sprintf("%d older snapshots left", remaining))
+	}
+	ui.Success("Undo completed")
+	ui.NewLine()
+}
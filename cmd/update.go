@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var updateStrategy string
+
+var updateCmd = &cobra.Command{
+	Use:   "update <feature-name>",
+	Short: "Bring feature branches up to date with main (rebase or merge)",
+	Long: `Update the main branch from origin and bring the feature branches up to date
+with it, by rebasing or by merging.
+
+Strategies:
+  rebase  Rebase each feature branch onto main, exactly like 'worktree rebase'
+  merge   Merge origin/<main_branch> into each feature branch; history is not
+          rewritten, so no force push is needed
+
+The strategy comes from --strategy, else update_strategy in .worktree.yml,
+else rebase.
+
+With the merge strategy every project is merged even if an earlier one
+conflicts; conflicts are reported per project. Resolve them and commit
+('git commit --no-edit'), or give up with 'git merge --abort', in each
+listed worktree.
+
+Examples:
+  worktree update feature-user-auth                   # Configured strategy
+  worktree update feature/user-auth --strategy merge`,
+	Args: cobra.ExactArgs(1),
+	Run:  runUpdate,
+}
+
+func init() {
+	updateCmd.Flags().StringVar(&updateStrategy, "strategy", "", "rebase or merge (default: update_strategy from config, else rebase)")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) {
+	featureName := registry.NormalizeBranchName(args[0])
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	strategy := updateStrategy
+	if strategy == "" {
+		strategy = workCfg.UpdateStrategy
+	}
+	switch strategy {
+	case "", "rebase":
+		runRebase(cmd, args)
+		return
+	case "merge":
+	default:
+		checkError(fmt.Errorf("unknown strategy '%s' (expected rebase or merge)", strategy))
+	}
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found in registry", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		os.Exit(1)
+	}
+
+	ui.PrintHeader(fmt.Sprintf("Updating Feature: %s (merge)", featureName))
+	ui.NewLine()
+	ui.PrintStatusLine("Branch", wt.Branch)
+	ui.NewLine()
+
+	projects := wt.Projects
+	if len(projects) == 0 {
+		ui.Error("No projects found in worktree")
+		os.Exit(1)
+	}
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+
+	// Check for unfinished merges and uncommitted changes in all projects
+	blocked := false
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			ui.Warning(fmt.Sprintf("Project '%s' not found in configuration, skipping", projectName))
+			continue
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			ui.Warning(fmt.Sprintf("Worktree for %s does not exist: %s", projectName, worktreePath))
+			continue
+		}
+
+		if git.MergeInProgress(worktreePath) {
+			blocked = true
+			ui.PrintStatusLine(projectName, "merge in progress")
+			continue
+		}
+		if changes, _ := git.HasUncommittedChanges(worktreePath); changes {
+			blocked = true
+			count, _ := git.GetUncommittedChangesCount(worktreePath)
+			ui.PrintStatusLine(projectName, fmt.Sprintf("%d uncommitted changes", count))
+		}
+	}
+
+	if blocked {
+		ui.NewLine()
+		ui.Error("Cannot update with uncommitted changes or unfinished merges")
+		ui.NewLine()
+		ui.Info("💡 Commit or stash your changes, and finish ('git commit') or abort ('git merge --abort') merges first")
+		os.Exit(1)
+	}
+
+	// Step 1: Update main branch in all project repositories
+	updateMainBranches(cfg, workCfg, projects)
+
+	// Step 2: Merge main into every project worktree
+	ui.Section("Merging main into worktrees...")
+	conflicts := make(map[string][]string)
+	var conflicted []string
+	for _, projectName := range projects {
+		project, exists := workCfg.Projects[projectName]
+		if !exists {
+			continue
+		}
+
+		mainBranch := "main"
+		if project.MainBranch != "" {
+			mainBranch = project.MainBranch
+		}
+
+		worktreePath := featureDir + "/" + project.Dir
+		if _, err := os.Stat(worktreePath); os.IsNotExist(err) {
+			ui.Warning(fmt.Sprintf("Worktree for %s does not exist, skipping", projectName))
+			continue
+		}
+
+		mainRef := git.MainRef(worktreePath, mainBranch)
+		ui.Info(fmt.Sprintf("🔀 Merging %s into %s...", mainRef, projectName))
+		if err := git.Merge(worktreePath, mainRef); err != nil {
+			if !git.MergeInProgress(worktreePath) {
+				ui.Error(fmt.Sprintf("%s merge failed: %v", projectName, err))
+				os.Exit(1)
+			}
+			files, _ := git.ConflictedFiles(worktreePath)
+			conflicts[projectName] = files
+			conflicted = append(conflicted, projectName)
+			ui.CrossMark(fmt.Sprintf("%s has conflicts", projectName))
+			continue
+		}
+		ui.CheckMark(fmt.Sprintf("%s merged successfully", projectName))
+	}
+
+	ui.NewLine()
+	if len(conflicted) > 0 {
+		ui.Error(fmt.Sprintf("Merge conflicts in: %s", strings.Join(conflicted, ", ")))
+		for _, projectName := range conflicted {
+			worktreePath := featureDir + "/" + workCfg.Projects[projectName].Dir
+			ui.NewLine()
+			ui.Info(fmt.Sprintf("%s (%s):", projectName, worktreePath))
+			for _, file := range conflicts[projectName] {
+				fmt.Printf("    - %s\n", file)
+			}
+		}
+		ui.NewLine()
+		ui.Info("💡 Resolve the conflicts, then run: git -C <worktree> commit --no-edit")
+		ui.Info("💡 Or give up with: git -C <worktree> merge --abort")
+		os.Exit(1)
+	}
+
+	ui.Success("✨ Update completed successfully!")
+	ui.NewLine()
+	ui.Info("Next steps:")
+	ui.Info(fmt.Sprintf("  • Test your changes: worktree start %s", featureName))
+	ui.Info(fmt.Sprintf("  • Push to remote: worktree push %s", featureName))
+	ui.NewLine()
+}
@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval time.Duration
+	watchOnce     bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [feature-name]",
+	Short: "Regenerate a feature's files when the configuration changes",
+	Long: `Watch .worktree.yml (including include: files and .worktree.local.yml), the
+feature's .worktree-overrides.yml and copy sources, and bring the feature
+worktree up to date whenever one of them changes:
+
+  - generated_files are re-rendered
+  - missing or outdated symlinks are recreated
+  - copied files are refreshed from their source
+
+Only files whose content actually changed are written, and each update is
+printed. Files are polled, so the watcher works on any filesystem.
+
+If no feature name is provided and you're in a worktree directory,
+the feature will be auto-detected from .worktree-instance.
+
+Examples:
+  worktree watch feature-user-auth     # Watch until Ctrl+C
+  worktree watch                       # Auto-detect from current directory
+  worktree watch --once                # Sync once and exit
+  worktree watch --interval 250ms`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runWatch,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "how often to check for changes")
+	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "sync once and exit instead of watching")
+}
+
+func runWatch(cmd *cobra.Command, args []string) {
+	var featureName string
+	if len(args) == 0 {
+		instance, err := config.DetectInstance()
+		if err != nil {
+			ui.Error("Not in a worktree directory and no feature name provided")
+			ui.Info("Usage: worktree watch <feature-name>")
+			os.Exit(1)
+		}
+		featureName = instance.Feature
+	} else {
+		featureName = args[0]
+	}
+
+	if watchInterval <= 0 {
+		checkError(fmt.Errorf("--interval must be positive"))
+	}
+
+	cfg, err := config.New()
+	checkError(err)
+
+	workCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+	checkError(err)
+
+	reg, err := registry.Load(cfg.WorktreeDir, workCfg)
+	checkError(err)
+
+	wt, exists := reg.Find(featureName)
+	if !exists {
+		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
+		fmt.Println("\nAvailable features:")
+		for _, w := range reg.List() {
+			fmt.Printf("  - %s\n", w.Normalized)
+		}
+		os.Exit(1)
+	}
+	featureName = wt.Normalized
+
+	if !cfg.WorktreeExists(featureName) {
+		ui.Error(fmt.Sprintf("Feature directory not found: worktrees/%s", featureName))
+		os.Exit(1)
+	}
+
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	syncFeature(cfg, workCfg, wt, featureName)
+	if watchOnce {
+		return
+	}
+
+	watched := workCfg.WatchPaths(cfg.ProjectRoot, featureDir, wt.Projects)
+	snapshot := fileSnapshot(watched)
+
+	ui.NewLine()
+	ui.Info(fmt.Sprintf("Watching %d files for changes (Ctrl+C to stop)", len(watched)))
+	for _, path := range watched {
+		fmt.Printf("  - %s\n", relToRoot(cfg.ProjectRoot, path))
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sigChan:
+			ui.NewLine()
+			ui.Info("Stopped watching")
+			return
+		case <-ticker.C:
+		}
+
+		current := fileSnapshot(watched)
+		changed := changedFiles(snapshot, current)
+		if len(changed) == 0 {
+			continue
+		}
+		snapshot = current
+
+		ui.NewLine()
+		for _, path := range changed {
+			ui.Info(fmt.Sprintf("Changed: %s", relToRoot(cfg.ProjectRoot, path)))
+		}
+
+		// Reload everything: the change may add projects, files, links or ports
+		newCfg, err := config.LoadWorktreeConfig(cfg.ProjectRoot)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Config not reloaded: %v", err))
+			continue
+		}
+		newReg, err := registry.Load(cfg.WorktreeDir, newCfg)
+		if err != nil {
+			ui.Warning(fmt.Sprintf("Registry not reloaded: %v", err))
+			continue
+		}
+		newWt, exists := newReg.Get(featureName)
+		if !exists {
+			ui.Warning(fmt.Sprintf("Feature '%s' is no longer in the registry; stopped watching", featureName))
+			return
+		}
+		workCfg, wt = newCfg, newWt
+
+		syncFeature(cfg, workCfg, wt, featureName)
+
+		watched = workCfg.WatchPaths(cfg.ProjectRoot, featureDir, wt.Projects)
+		snapshot = fileSnapshot(watched)
+	}
+}
+
+// syncFeature resolves the feature's env vars and updates its generated files,
+// symlinks, copies and .worktree-env, printing what changed. Returns the
+// resolved vars written to .worktree-env.
+func syncFeature(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName string) map[string]string {
+	featureDir := cfg.WorktreeFeaturePath(featureName)
+	envVars, overrides := featureEnvVars(workCfg, wt, featureName, featureDir)
+
+	computed := workCfg.GetComputedVars(envVars)
+	exported := workCfg.GetExportedVars(envVars)
+	maps.Copy(computed, overrides)
+	maps.Copy(exported, overrides)
+	if err := config.WriteEnvFile(featureDir, computed); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to update .worktree-env: %v", err))
+	}
+
+	if workCfg.Proxy.Enabled() {
+		if _, _, err := workCfg.WriteProxyConfig(cfg.ProjectRoot, featureName, envVars); err != nil {
+			ui.Warning(fmt.Sprintf("Failed to update proxy config: %v", err))
+		}
+	}
+
+	result := workCfg.SyncFeatureFiles(cfg.ProjectRoot, featureDir, wt.Projects, envVars)
+	for _, projectName := range wt.Projects {
+		registerMergeDriver(workCfg, projectName, filepath.Join(featureDir, workCfg.Projects[projectName].Dir))
+	}
+	writeFeatureFlags(workCfg, featureDir, wt.Projects, envVars)
+	writeComposeOverrides(workCfg, featureDir, featureName, wt.Projects, envVars)
+	writeWorkspace(workCfg, featureDir, featureName, wt.Projects, exported)
+	for _, warning := range result.Warnings {
+		ui.Warning(warning)
+	}
+	for _, path := range result.Updated {
+		ui.CheckMark(fmt.Sprintf("Updated %s", path))
+	}
+	if len(result.Updated) == 0 {
+		ui.Info(fmt.Sprintf("%s is up to date", featureName))
+	}
+	if result.Backup != "" {
+		ui.Info(fmt.Sprintf("Previous versions saved to %s (undo with: worktree sync %s --undo)", result.Backup, featureName))
+	}
+	return computed
+}
+
+// featureEnvVars resolves all env vars of a feature the way start does:
+// instance, FEATURE_NAME, YOLO, the registry ports, path vars, value templates
+// and the feature's overrides. Returns the vars and the overrides that were
+// applied.
+func featureEnvVars(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureName, featureDir string) (map[string]string, map[string]string) {
+	instance := featureInstance(workCfg, wt)
+
+	envVars := workCfg.ExportEnvVars(instance)
+	envVars["FEATURE_NAME"] = featureName
+	envVars["YOLO"] = strconv.FormatBool(wt.YoloMode)
+	for service, port := range wt.Ports {
+		envVars[service] = fmt.Sprintf("%d", port)
+	}
+	config.AddPathVars(envVars, featureDir)
+	overrides := resolveWithOverrides(workCfg, instance, featureDir, envVars)
+	return envVars, overrides
+}
+
+// fileSnapshot records modification time and size of each path ("" if missing)
+func fileSnapshot(paths []string) map[string]string {
+	snapshot := make(map[string]string, len(paths))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			snapshot[path] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		} else {
+			snapshot[path] = ""
+		}
+	}
+	return snapshot
+}
+
+// changedFiles returns the paths whose snapshot entry differs, in sorted order
+func changedFiles(before, after map[string]string) []string {
+	var changed []string
+	for _, path := range sortedKeys(after) {
+		if before[path] != after[path] {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// relToRoot shortens path for display when it lies under the project root
+func relToRoot(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+	"github.com/braunmar/worktree/pkg/webhook"
+)
+
+// sendFeatureEvent notifies the configured webhooks of a feature lifecycle
+// event for the given projects of the feature. Failed deliveries are reported
+// but never fail the command.
+func sendFeatureEvent(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree, event string, projects []string) {
+	if len(workCfg.Webhooks) == 0 {
+		return
+	}
+
+	e := webhook.NewEvent(event, workCfg.ProjectName)
+	e.Feature = wt.Normalized
+	e.Branch = wt.Branch
+	e.Projects = projects
+	e.Ports = wt.Ports
+	e.URLs = workCfg.GetDisplayableServices(wt.Ports)
+
+	for _, err := range webhook.Send(cfg.ProjectRoot, workCfg.Webhooks, e) {
+		ui.Warning(fmt.Sprintf("Failed to deliver %s event: %v", event, err))
+	}
+}
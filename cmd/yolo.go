@@ -53,7 +53,7 @@ func runYolo(cmd *cobra.Command, args []string) {
 	checkError(err)
 
 	// Get worktree from registry
-	wt, exists := reg.Get(featureName)
+	wt, exists := reg.Find(featureName)
 	if !exists {
 		ui.Error(fmt.Sprintf("Feature worktree '%s' not found", featureName))
 		fmt.Println("\nAvailable features:")
@@ -62,6 +62,7 @@ func runYolo(cmd *cobra.Command, args []string) {
 		}
 		os.Exit(1)
 	}
+	featureName = wt.Normalized
 
 	// Toggle YOLO mode
 	newState := !yoloDisable
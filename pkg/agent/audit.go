@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+
+	"github.com/robfig/cron/v3"
+)
+
+// TaskAudit compares one task's schedule with its recorded executions
+type TaskAudit struct {
+	TaskName string
+	Schedule string
+	CatchUp  bool
+	Expected int         // Scheduled runs in the audit window
+	Missed   []time.Time // Scheduled times with no execution before the next scheduled time
+	LastRun  *time.Time  // Most recent recorded execution start, if any
+	Err      error       // Schedule could not be parsed
+}
+
+// AuditSchedule reports, per task, the scheduled runs between since and now that
+// have no matching execution record. A scheduled run counts as done when any
+// execution of the task started between its scheduled time and the next one.
+// Runs scheduled within grace of now are not reported yet, as they may still be
+// running. Results are sorted by task name.
+func AuditSchedule(tasks config.ScheduledAgents, records []history.ExecutionRecord, since, now time.Time, grace time.Duration) []TaskAudit {
+	starts := make(map[string][]time.Time)
+	for _, record := range records {
+		starts[record.AgentName] = append(starts[record.AgentName], record.StartTime)
+	}
+
+	var audits []TaskAudit
+	for name, task := range tasks {
+		audit := TaskAudit{TaskName: name, Schedule: task.Schedule, CatchUp: task.CatchUp}
+
+		runs := starts[name]
+		sort.Slice(runs, func(i, j int) bool { return runs[i].Before(runs[j]) })
+		if len(runs) > 0 {
+			last := runs[len(runs)-1]
+			audit.LastRun = &last
+		}
+
+		sched, err := cron.ParseStandard(task.Schedule)
+		if err != nil {
+			audit.Err = fmt.Errorf("invalid schedule '%s': %w", task.Schedule, err)
+			audits = append(audits, audit)
+			continue
+		}
+
+		cutoff := now.Add(-grace)
+		for at := sched.Next(since); !at.After(cutoff); {
+			next := sched.Next(at)
+			audit.Expected++
+			if !ranBetween(runs, at, next) {
+				audit.Missed = append(audit.Missed, at)
+			}
+			at = next
+		}
+
+		audits = append(audits, audit)
+	}
+
+	sort.Slice(audits, func(i, j int) bool { return audits[i].TaskName < audits[j].TaskName })
+	return audits
+}
+
+// ranBetween reports whether any start time (sorted) falls in [from, to)
+func ranBetween(starts []time.Time, from, to time.Time) bool {
+	i := sort.Search(len(starts), func(i int) bool { return !starts[i].Before(from) })
+	return i < len(starts) && starts[i].Before(to)
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+)
+
+func TestAuditSchedule(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.Local)
+	since := now.Add(-24 * time.Hour)
+
+	tasks := config.ScheduledAgents{
+		"daily":   {Schedule: "0 9 * * *", CatchUp: true},
+		"hourly":  {Schedule: "0 * * * *"},
+		"broken":  {Schedule: "not a cron"},
+		"yearly":  {Schedule: "0 0 1 1 *"},
+		"covered": {Schedule: "0 9 * * *"},
+	}
+
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 3, day, hour, min, 0, 0, time.Local)
+	}
+	records := []history.ExecutionRecord{
+		{AgentName: "covered", StartTime: at(10, 9, 0)},
+		// Hourly runs for every hour except 03:00
+		{AgentName: "hourly", StartTime: at(9, 13, 0)},
+	}
+	for hour := 0; hour <= 11; hour++ {
+		if hour != 3 {
+			records = append(records, history.ExecutionRecord{AgentName: "hourly", StartTime: at(10, hour, 1)})
+		}
+	}
+	for hour := 14; hour <= 23; hour++ {
+		records = append(records, history.ExecutionRecord{AgentName: "hourly", StartTime: at(9, hour, 0)})
+	}
+
+	audits := AuditSchedule(tasks, records, since, now, time.Hour)
+	byName := make(map[string]TaskAudit)
+	for _, a := range audits {
+		byName[a.TaskName] = a
+	}
+	if audits[0].TaskName != "broken" {
+		t.Errorf("audits not sorted by name: first = %s", audits[0].TaskName)
+	}
+
+	if a := byName["daily"]; a.Expected != 1 || len(a.Missed) != 1 || !a.Missed[0].Equal(at(10, 9, 0)) || !a.CatchUp {
+		t.Errorf("daily = %+v, want 1 missed run at 09:00 with catch-up", a)
+	}
+	if a := byName["covered"]; a.Expected != 1 || len(a.Missed) != 0 || a.LastRun == nil {
+		t.Errorf("covered = %+v, want run recorded and nothing missed", a)
+	}
+	// 13:00 yesterday .. 11:00 today (12:00 is within the grace period)
+	if a := byName["hourly"]; a.Expected != 23 || len(a.Missed) != 1 || !a.Missed[0].Equal(at(10, 3, 0)) {
+		t.Errorf("hourly = expected %d missed %v, want 23 expected and 03:00 missed", a.Expected, a.Missed)
+	}
+	if a := byName["broken"]; a.Err == nil {
+		t.Error("broken schedule should report an error")
+	}
+	if a := byName["yearly"]; a.Expected != 0 || len(a.Missed) != 0 {
+		t.Errorf("yearly = %+v, want no expected runs", a)
+	}
+}
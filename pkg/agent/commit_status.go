@@ -0,0 +1,209 @@
+package agent
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Commit status providers for safety.git.push.commit_status
+const (
+	CommitStatusGitHub = "github" // Posted with 'gh api'
+	CommitStatusGitLab = "gitlab" // Posted to the GitLab API with GITLAB_TOKEN
+)
+
+// maxStatusDescription is GitHub's limit for commit status descriptions
+const maxStatusDescription = 140
+
+// commitStatus is the verification state posted on a pushed commit
+type commitStatus struct {
+	Context     string // "worktree-agent/<task>"
+	Success     bool
+	Description string
+}
+
+// newCommitStatus builds the status for a task from its gate results. Any
+// failed gate (only optional ones can fail when a push happens) marks the
+// commit as failed so reviewers look at it.
+func newCommitStatus(agentName string, totalGates int, failedGates []string) commitStatus {
+	status := commitStatus{Context: "worktree-agent/" + agentName, Success: len(failedGates) == 0}
+	switch {
+	case totalGates == 0:
+		status.Description = "No safety gates configured"
+	case len(failedGates) == 0:
+		status.Description = fmt.Sprintf("All %d safety gates passed", totalGates)
+	default:
+		status.Description = fmt.Sprintf("%d of %d safety gates passed (failed: %s)",
+			totalGates-len(failedGates), totalGates, strings.Join(failedGates, ", "))
+	}
+	if len(status.Description) > maxStatusDescription {
+		status.Description = status.Description[:maxStatusDescription-3] + "..."
+	}
+	return status
+}
+
+// postCommitStatus posts the gate results on HEAD of the repository in dir.
+// Failing to post never fails the task itself.
+func (e *Executor) postCommitStatus(dir string) {
+	provider := e.task.Safety.Git.Push.CommitStatus
+	status := newCommitStatus(e.agentName, len(e.task.Safety.Gates), e.failedGates)
+
+	fmt.Printf("  Posting commit status (%s)...\n", provider)
+	start := time.Now()
+	details := map[string]string{"dir": dir, "provider": provider, "context": status.Context}
+	sha, err := gitOutput(dir, "rev-parse", "HEAD")
+	if err == nil {
+		switch provider {
+		case CommitStatusGitHub:
+			err = postGitHubStatus(dir, sha, status)
+		case CommitStatusGitLab:
+			err = postGitLabStatus(dir, sha, status)
+		default:
+			err = fmt.Errorf("unknown commit_status provider '%s' (expected %s or %s)", provider, CommitStatusGitHub, CommitStatusGitLab)
+		}
+	}
+	if err != nil {
+		e.logEvent(history.PhaseGit, "commit status", "failed", start, err, details)
+		ui.Printf("  ⚠️  Failed to post commit status: %v\n", err)
+		return
+	}
+	e.logEvent(history.PhaseGit, "commit status", "completed", start, nil, details)
+	ui.Printf("  ✅ Commit status %s: %s\n", status.Context, status.Description)
+}
+
+// postGitHubStatus creates a commit status through the GitHub CLI, which
+// resolves {owner}/{repo} from the repository in dir
+func postGitHubStatus(dir, sha string, status commitStatus) error {
+	state := "failure"
+	if status.Success {
+		state = "success"
+	}
+	cmd := exec.Command("gh", "api", "--method", "POST", "repos/{owner}/{repo}/statuses/"+sha,
+		"-f", "state="+state,
+		"-f", "context="+status.Context,
+		"-f", "description="+status.Description)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("gh api: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// postGitLabStatus creates a commit status through the GitLab API (see newGitLabAPI)
+func postGitLabStatus(dir, sha string, status commitStatus) error {
+	api, err := newGitLabAPI(dir)
+	if err != nil {
+		return err
+	}
+
+	state := "failed"
+	if status.Success {
+		state = "success"
+	}
+	form := url.Values{
+		"state":       {state},
+		"name":        {status.Context},
+		"description": {status.Description},
+	}
+	_, err = api.post("statuses/"+sha, form)
+	return err
+}
+
+// gitLabAPI posts to the API of the GitLab project of a repository
+type gitLabAPI struct {
+	url     string // API root, e.g. https://gitlab.com/api/v4
+	project string // Project path, e.g. group/repo
+	token   string
+}
+
+// newGitLabAPI returns the GitLab API of the repository in dir. The API URL
+// is taken from GITLAB_API_URL (or CI_API_V4_URL in GitLab CI) and otherwise
+// derived from the origin remote; the token comes from GITLAB_TOKEN.
+func newGitLabAPI(dir string) (*gitLabAPI, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITLAB_TOKEN is not set")
+	}
+
+	remote, err := gitOutput(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return nil, err
+	}
+	host, project, err := parseRemoteURL(remote)
+	if err != nil {
+		return nil, err
+	}
+	apiURL := os.Getenv("GITLAB_API_URL")
+	if apiURL == "" {
+		apiURL = os.Getenv("CI_API_V4_URL")
+	}
+	if apiURL == "" {
+		apiURL = "https://" + host + "/api/v4"
+	}
+	return &gitLabAPI{url: strings.TrimSuffix(apiURL, "/"), project: project, token: token}, nil
+}
+
+// post sends a form to an endpoint of the project (projects/<id>/<path>) and
+// returns the response body
+func (a *gitLabAPI) post(path string, form url.Values) ([]byte, error) {
+	endpoint := fmt.Sprintf("%s/projects/%s/%s", a.url, url.PathEscape(a.project), path)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("PRIVATE-TOKEN", a.token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		if len(body) > 500 {
+			body = body[:500]
+		}
+		return nil, fmt.Errorf("GitLab returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// parseRemoteURL splits an SSH, scp-style or HTTP(S) remote URL into host and
+// project path: git@gitlab.com:group/repo.git -> gitlab.com, group/repo
+func parseRemoteURL(remote string) (host, project string, err error) {
+	if strings.Contains(remote, "://") {
+		u, parseErr := url.Parse(remote)
+		if parseErr != nil {
+			return "", "", fmt.Errorf("invalid remote URL %q: %w", remote, parseErr)
+		}
+		host, project = u.Hostname(), u.Path
+	} else if at, rest, ok := strings.Cut(remote, ":"); ok {
+		host, project = at[strings.LastIndex(at, "@")+1:], rest
+	}
+
+	project = strings.TrimSuffix(strings.Trim(project, "/"), ".git")
+	if host == "" || project == "" {
+		return "", "", fmt.Errorf("cannot determine project from remote URL %q", remote)
+	}
+	return host, project, nil
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
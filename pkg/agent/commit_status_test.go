@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestNewCommitStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		total       int
+		failed      []string
+		wantSuccess bool
+		wantDesc    string
+	}{
+		{name: "all passed", total: 3, wantSuccess: true, wantDesc: "All 3 safety gates passed"},
+		{name: "optional failed", total: 3, failed: []string{"lint"}, wantDesc: "2 of 3 safety gates passed (failed: lint)"},
+		{name: "no gates", wantSuccess: true, wantDesc: "No safety gates configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := newCommitStatus("npm-audit", tt.total, tt.failed)
+			if status.Context != "worktree-agent/npm-audit" {
+				t.Errorf("Context = %q", status.Context)
+			}
+			if status.Success != tt.wantSuccess || status.Description != tt.wantDesc {
+				t.Errorf("got %v %q, want %v %q", status.Success, status.Description, tt.wantSuccess, tt.wantDesc)
+			}
+		})
+	}
+
+	long := newCommitStatus("x", 2, []string{strings.Repeat("a", 100), strings.Repeat("b", 100)})
+	if len(long.Description) != maxStatusDescription || !strings.HasSuffix(long.Description, "...") {
+		t.Errorf("long description not truncated: %q", long.Description)
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote      string
+		wantHost    string
+		wantProject string
+	}{
+		{"git@gitlab.com:group/sub/repo.git", "gitlab.com", "group/sub/repo"},
+		{"https://gitlab.example.com/group/repo.git", "gitlab.example.com", "group/repo"},
+		{"ssh://git@gitlab.example.com:2222/group/repo", "gitlab.example.com", "group/repo"},
+	}
+	for _, tt := range tests {
+		host, project, err := parseRemoteURL(tt.remote)
+		if err != nil || host != tt.wantHost || project != tt.wantProject {
+			t.Errorf("parseRemoteURL(%q) = %q, %q, %v", tt.remote, host, project, err)
+		}
+	}
+
+	if _, _, err := parseRemoteURL("/srv/git/repo.git"); err == nil {
+		t.Error("expected error for a local path remote")
+	}
+}
+
+func TestPostGitLabStatus(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@gitlab.example.com:group/repo.git"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	var gotPath, gotToken string
+	var gotForm map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		gotToken = r.Header.Get("PRIVATE-TOKEN")
+		_ = r.ParseForm()
+		gotForm = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITLAB_API_URL", server.URL+"/api/v4")
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	status := newCommitStatus("npm-audit", 2, []string{"tests"})
+	if err := postGitLabStatus(dir, "abc123", status); err != nil {
+		t.Fatal(err)
+	}
+	if gotPath != "/api/v4/projects/group%2Frepo/statuses/abc123" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotToken != "secret" {
+		t.Errorf("token = %q", gotToken)
+	}
+	if gotForm["state"][0] != "failed" || gotForm["name"][0] != "worktree-agent/npm-audit" {
+		t.Errorf("form = %v", gotForm)
+	}
+
+	t.Setenv("GITLAB_TOKEN", "")
+	if err := postGitLabStatus(dir, "abc123", status); err == nil || !strings.Contains(err.Error(), "GITLAB_TOKEN") {
+		t.Errorf("expected missing token error, got %v", err)
+	}
+}
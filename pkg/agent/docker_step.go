@@ -0,0 +1,106 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/doctor"
+	"github.com/braunmar/worktree/pkg/process"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// DockerStepArgs returns the compose arguments that run a docker step's
+// command in its service container of the given compose project
+func DockerStepArgs(composeProject string, step config.AgentStep) []string {
+	args := []string{"-p", composeProject, "exec", "-T"}
+	if step.WorkingDir != "" {
+		args = append(args, "--workdir", step.WorkingDir)
+	}
+	return append(args, step.Service, "sh", "-c", step.Command)
+}
+
+// ValidateDockerStep checks that a docker step names a command, a service and
+// a docker project of the configuration
+func ValidateDockerStep(workCfg *config.WorktreeConfig, step config.AgentStep) error {
+	switch {
+	case step.Command == "":
+		return fmt.Errorf("command is empty")
+	case step.Service == "":
+		return fmt.Errorf("service is empty")
+	case step.Project == "":
+		return fmt.Errorf("project is empty")
+	}
+	project, ok := workCfg.Projects[step.Project]
+	if !ok {
+		return fmt.Errorf("project '%s' not found in .worktree.yml", step.Project)
+	}
+	if project.GetExecutor() != "docker" {
+		return fmt.Errorf("project '%s' uses the %s executor, docker steps need a docker project", step.Project, project.GetExecutor())
+	}
+	return nil
+}
+
+// executeDockerStep runs the step's command in a running container of the
+// feature the run is for (docker compose exec), using the compose project
+// the feature's project was started under
+func (e *Executor) executeDockerStep(step config.AgentStep, env []string) error {
+	if err := ValidateDockerStep(e.workCfg, step); err != nil {
+		return err
+	}
+	if e.worktree == "" {
+		return fmt.Errorf("docker steps run in a feature's containers: queue the task for a feature or use 'worktree agent run %s --worktree <feature>'", e.agentName)
+	}
+
+	reg, err := registry.Load(e.cfg.WorktreeDir, e.workCfg)
+	if err != nil {
+		return err
+	}
+	wt, ok := reg.Find(e.worktree)
+	if !ok {
+		return fmt.Errorf("feature worktree '%s' not found", e.worktree)
+	}
+	if !slices.Contains(wt.Projects, step.Project) {
+		return fmt.Errorf("feature '%s' has no project '%s'", wt.Normalized, step.Project)
+	}
+
+	composeProject := doctor.ComposeProjectName(e.workCfg, wt, step.Project)
+	fmt.Printf("      Container: %s (compose project %s)\n", step.Service, composeProject)
+
+	cmd := docker.Current().ComposeCommand(DockerStepArgs(composeProject, step)...)
+	cmd.Dir = filepath.Join(e.cfg.WorktreeFeaturePath(wt.Normalized), e.workCfg.Projects[step.Project].Dir)
+	cmd.Env = dockerStepEnv(env, e.workCfg, wt, composeProject)
+	e.connectOutput(cmd)
+
+	// A timeout stops the compose client; the command may go on in the container
+	return process.RunCommand(cmd, step.GetTimeout())
+}
+
+// dockerStepEnv adds the feature's resolved vars to the step env, so compose
+// interpolates the compose files as they were when the feature started. Vars
+// read from the host environment are not stored and are resolved again.
+func dockerStepEnv(base []string, workCfg *config.WorktreeConfig, wt *registry.Worktree, composeProject string) []string {
+	if base == nil {
+		base = os.Environ()
+	}
+	env := append([]string{}, base...)
+	vars := maps.Clone(wt.ComputedVars)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	workCfg.ResolveHostEnvVars(workCfg.InstanceFromPorts(wt.Ports), vars)
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, fmt.Sprintf("%s=%s", key, vars[key]))
+	}
+	return append(env, "COMPOSE_PROJECT_NAME="+composeProject)
+}
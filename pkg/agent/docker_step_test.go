@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+func TestDockerStepArgs(t *testing.T) {
+	step := config.AgentStep{Type: "docker", Service: "api", Command: "make migrate"}
+	got := strings.Join(DockerStepArgs("app-feature-x-backend", step), " ")
+	if want := "-p app-feature-x-backend exec -T api sh -c make migrate"; got != want {
+		t.Errorf("DockerStepArgs = %q, want %q", got, want)
+	}
+
+	step.WorkingDir = "/app/db"
+	got = strings.Join(DockerStepArgs("p", step), " ")
+	if want := "-p p exec -T --workdir /app/db api sh -c make migrate"; got != want {
+		t.Errorf("DockerStepArgs with working_dir = %q, want %q", got, want)
+	}
+}
+
+func TestValidateDockerStep(t *testing.T) {
+	workCfg := &config.WorktreeConfig{Projects: map[string]config.ProjectConfig{
+		"backend": {Dir: "backend"},
+		"worker":  {Dir: "worker", Executor: "process"},
+	}}
+	valid := config.AgentStep{Type: "docker", Project: "backend", Service: "api", Command: "make migrate"}
+	if err := ValidateDockerStep(workCfg, valid); err != nil {
+		t.Errorf("valid step: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*config.AgentStep)
+		wantErr string
+	}{
+		{"no command", func(s *config.AgentStep) { s.Command = "" }, "command is empty"},
+		{"no service", func(s *config.AgentStep) { s.Service = "" }, "service is empty"},
+		{"no project", func(s *config.AgentStep) { s.Project = "" }, "project is empty"},
+		{"unknown project", func(s *config.AgentStep) { s.Project = "nope" }, "project 'nope' not found"},
+		{"process project", func(s *config.AgentStep) { s.Project = "worker" }, "uses the process executor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := valid
+			tt.modify(&step)
+			err := ValidateDockerStep(workCfg, step)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateDockerStep = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDockerStepEnv(t *testing.T) {
+	t.Setenv("TEST_CLIENT_SECRET", "s3cret")
+	workCfg := &config.WorktreeConfig{EnvVariables: map[string]config.EnvVarConfig{
+		"CLIENT_SECRET": {Value: "{env:TEST_CLIENT_SECRET}", Env: "CLIENT_SECRET"},
+	}}
+	wt := &registry.Worktree{ComputedVars: map[string]string{"APP_PORT": "9090", "INSTANCE": "1"}}
+	env := dockerStepEnv([]string{"PATH=/bin"}, workCfg, wt, "app-feature-x-backend")
+	got := strings.Join(env, " ")
+	if want := "PATH=/bin APP_PORT=9090 CLIENT_SECRET=s3cret INSTANCE=1 COMPOSE_PROJECT_NAME=app-feature-x-backend"; got != want {
+		t.Errorf("dockerStepEnv = %q, want %q", got, want)
+	}
+}
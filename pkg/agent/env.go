@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+// defaultPassThroughEnv lists host variables kept in an isolated agent environment.
+// They are needed for basic tooling (shell, locale, git over ssh) but do not carry
+// directory-local state like .envrc exports.
+var defaultPassThroughEnv = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "TMPDIR",
+	"LANG", "LC_*", "TZ", "SSH_AUTH_SOCK",
+}
+
+// PassThroughEnv returns the host vars kept in the task's isolated environment:
+// the defaults followed by the task's environment.pass_through entries
+func PassThroughEnv(task *config.AgentTask) []string {
+	return append(append([]string{}, defaultPassThroughEnv...), task.Environment.PassThrough...)
+}
+
+// buildStepEnv returns the environment for step and safety gate commands.
+// Without environment.isolated the caller's full environment is inherited.
+// In isolated mode only allowlisted host vars are kept, and the task's computed
+// vars (INSTANCE, ports, value templates) are added on top. The task's env map
+// is added last.
+func buildStepEnv(host []string, projectRoot string, workCfg *config.WorktreeConfig, task *config.AgentTask) ([]string, error) {
+	envCfg := task.Environment
+	computed := taskVars(workCfg, task)
+
+	env := host
+	if envCfg.Isolated {
+		env = filterEnv(host, PassThroughEnv(task))
+
+		keys := make([]string, 0, len(computed))
+		for key := range computed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			env = append(env, fmt.Sprintf("%s=%s", key, computed[key]))
+		}
+	}
+
+	if envCfg.CacheDir != "" {
+		cacheDir := envCfg.CacheDir
+		if !filepath.IsAbs(cacheDir) {
+			cacheDir = filepath.Join(projectRoot, cacheDir)
+		}
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create agent cache dir: %w", err)
+		}
+		env = append(env,
+			"XDG_CACHE_HOME="+cacheDir,
+			"GOCACHE="+filepath.Join(cacheDir, "go-build"),
+			"GOMODCACHE="+filepath.Join(cacheDir, "go-mod"),
+		)
+	}
+
+	taskEnv, err := workCfg.ResolveAgentEnv(projectRoot, task.Context.Instance, task.Env, computed)
+	if err != nil {
+		return nil, err
+	}
+	return append(env, taskEnv...), nil
+}
+
+// taskVars returns the computed env vars of the task's instance (INSTANCE,
+// ports, value templates), which env map placeholders resolve against
+func taskVars(workCfg *config.WorktreeConfig, task *config.AgentTask) map[string]string {
+	computed := workCfg.ExportEnvVars(task.Context.Instance)
+	workCfg.ResolveValueVars(task.Context.Instance, computed)
+	return computed
+}
+
+// stepEnv returns the environment of a step: the task's env with the step's
+// env map added on top
+func (e *Executor) stepEnv(step config.AgentStep) ([]string, error) {
+	if len(step.Env) == 0 {
+		return e.env, nil
+	}
+	stepEnv, err := e.workCfg.ResolveAgentEnv(e.cfg.ProjectRoot, e.task.Context.Instance, step.Env, taskVars(e.workCfg, e.task))
+	if err != nil {
+		return nil, err
+	}
+	base := e.env
+	if base == nil {
+		base = os.Environ()
+	}
+	return append(append([]string{}, base...), stepEnv...), nil
+}
+
+// filterEnv keeps only KEY=VALUE entries whose key matches one of the allow patterns.
+// A pattern ending in * matches any key with that prefix.
+func filterEnv(env []string, allow []string) []string {
+	var result []string
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		for _, pattern := range allow {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+				if strings.HasPrefix(key, prefix) {
+					result = append(result, kv)
+					break
+				}
+			} else if key == pattern {
+				result = append(result, kv)
+				break
+			}
+		}
+	}
+	return result
+}
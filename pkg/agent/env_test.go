@@ -0,0 +1,64 @@
+package agent
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestPassThroughEnv(t *testing.T) {
+	task := &config.AgentTask{Environment: config.AgentEnvConfig{PassThrough: []string{"NPM_TOKEN", "AWS_*"}}}
+
+	got := PassThroughEnv(task)
+	if !slices.Equal(got[:len(defaultPassThroughEnv)], defaultPassThroughEnv) {
+		t.Errorf("expected defaults first, got %v", got)
+	}
+	if !slices.Equal(got[len(defaultPassThroughEnv):], []string{"NPM_TOKEN", "AWS_*"}) {
+		t.Errorf("expected task entries after defaults, got %v", got)
+	}
+}
+
+func TestFilterEnv(t *testing.T) {
+	host := []string{"PATH=/bin", "LC_ALL=C", "SECRET=x", "AWS_REGION=eu"}
+
+	got := filterEnv(host, []string{"PATH", "LC_*", "AWS_*"})
+	want := []string{"PATH=/bin", "LC_ALL=C", "AWS_REGION=eu"}
+	if !slices.Equal(got, want) {
+		t.Errorf("filterEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildStepEnvTaskEnv(t *testing.T) {
+	workCfg := &config.WorktreeConfig{
+		Hostname: "localhost",
+		EnvVariables: map[string]config.EnvVarConfig{
+			"APP_PORT": {Port: "8080 + {instance}", Env: "APP_PORT"},
+		},
+	}
+	task := &config.AgentTask{
+		Context: config.AgentContext{Instance: 2},
+		Env:     map[string]string{"API_URL": "http://{host}:{APP_PORT}", "LOG_LEVEL": "debug"},
+	}
+
+	env, err := buildStepEnv([]string{"PATH=/bin", "LOG_LEVEL=info"}, t.TempDir(), workCfg, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"PATH=/bin", "LOG_LEVEL=info", "API_URL=http://localhost:8082", "LOG_LEVEL=debug"}
+	if !slices.Equal(env, want) {
+		t.Errorf("buildStepEnv() = %v, want %v (task env last, so it wins)", env, want)
+	}
+
+	e := &Executor{cfg: &config.Config{ProjectRoot: t.TempDir()}, workCfg: workCfg, task: task, env: env}
+	stepEnv, err := e.stepEnv(config.AgentStep{Env: map[string]string{"STEP_PORT": "{APP_PORT+1}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stepEnv[len(stepEnv)-1]; got != "STEP_PORT=8083" {
+		t.Errorf("step env last entry = %q, want STEP_PORT=8083", got)
+	}
+	if len(e.env) != len(want) {
+		t.Errorf("step env must not modify the task env, got %v", e.env)
+	}
+}
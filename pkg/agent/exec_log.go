@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/process"
+)
+
+// maxLogOutput bounds the command output kept in a log event
+const maxLogOutput = 2000
+
+// logEvent adds an event to the run's structured log (see history.ExecutionLog).
+// start is when the operation began; a zero start logs an instant event.
+func (e *Executor) logEvent(phase, name, status string, start time.Time, err error, details map[string]string) {
+	event := history.LogEvent{Time: start, Phase: phase, Name: name, Status: status, Details: details}
+	if start.IsZero() {
+		event.Time = time.Now()
+	} else {
+		event.DurationMS = time.Since(start).Milliseconds()
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	e.events = append(e.events, event)
+}
+
+// saveLog writes the run's structured log and returns its path relative to
+// the worktrees directory
+func (e *Executor) saveLog(h *history.History, record *history.ExecutionRecord) (string, error) {
+	events := append(e.events, history.LogEvent{
+		Time:   record.EndTime,
+		Phase:  history.PhaseRun,
+		Name:   e.task.Name,
+		Status: record.Status,
+		Error:  record.Error,
+	})
+	return h.SaveLog(&history.ExecutionLog{
+		ID:        record.ID,
+		AgentName: record.AgentName,
+		Trigger:   record.Trigger,
+		Worktree:  record.Worktree,
+		StartTime: record.StartTime,
+		EndTime:   record.EndTime,
+		Status:    record.Status,
+		Error:     record.Error,
+		Events:    events,
+	})
+}
+
+// truncateOutput shortens command output for a log event
+func truncateOutput(output []byte) string {
+	if len(output) > maxLogOutput {
+		return string(output[:maxLogOutput]) + "..."
+	}
+	return string(output)
+}
+
+// outputTail keeps the last maxLogOutput bytes written to it
+type outputTail struct {
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > maxLogOutput {
+		t.buf = append([]byte{}, t.buf[len(t.buf)-maxLogOutput:]...)
+		t.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, marked when its beginning was dropped
+func (t *outputTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.truncated {
+		return "..." + string(t.buf)
+	}
+	return string(t.buf)
+}
+
+// connectOutput sends a step command's output to the terminal, keeping its
+// tail for the step's history record
+func (e *Executor) connectOutput(cmd *exec.Cmd) {
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if e.stepOutput == nil {
+		return
+	}
+	cmd.Stdout = io.MultiWriter(os.Stdout, e.stepOutput)
+	cmd.Stderr = io.MultiWriter(os.Stderr, e.stepOutput)
+	// A background process the step left running must not hold the step open
+	cmd.WaitDelay = process.OutputWaitDelay
+}
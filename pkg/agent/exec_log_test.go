@@ -0,0 +1,21 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutputTail(t *testing.T) {
+	var tail outputTail
+	tail.Write([]byte("short\n"))
+	if got := tail.String(); got != "short\n" {
+		t.Errorf("String() = %q, want the whole output", got)
+	}
+
+	tail.Write([]byte(strings.Repeat("x", maxLogOutput)))
+	tail.Write([]byte("end"))
+	got := tail.String()
+	if !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "xend") || len(got) != maxLogOutput+3 {
+		t.Errorf("String() = %.20q... (%d bytes), want the last %d bytes after \"...\"", got, len(got), maxLogOutput)
+	}
+}
@@ -3,14 +3,22 @@ package agent
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/process"
+	"github.com/braunmar/worktree/pkg/ui"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
 )
 
 // Executor manages the execution of a scheduled agent task
@@ -20,6 +28,19 @@ type Executor struct {
 	task      *config.AgentTask
 	cleanup   bool
 	agentName string
+	env       []string     // Environment for steps and safety gates (see buildStepEnv)
+	runWT     *runWorktree // Worktree of an isolated run (context.isolated_worktree), nil otherwise
+
+	worktree      string // Feature the run was queued for, recorded in history (empty for direct runs)
+	trigger       string // What started the run, recorded in history (history.Trigger*)
+	waitForLock   bool   // Wait for runs holding the locks the run needs instead of failing
+	stepsExecuted int
+	stepResults   []history.StepResult // Outcome of each step, recorded in history
+	stepOutput    *outputTail          // Output of the current step attempt, kept in its StepResult
+	failedGates   []string             // Gates that failed in this run, reported in the commit status
+	requestURLs   []string             // Pull/merge requests the run opened, recorded in history
+	commits       []string             // Commits the run pushed, recorded in history
+	events        []history.LogEvent   // Structured log of the run (see logEvent)
 }
 
 // NewExecutor creates a new agent executor
@@ -30,12 +51,140 @@ func NewExecutor(cfg *config.Config, workCfg *config.WorktreeConfig, task *confi
 		task:      task,
 		cleanup:   true,
 		agentName: agentName,
+		trigger:   history.TriggerManual,
 	}
 }
 
-// Run executes the agent task
+// SetWorktree sets the feature the run is for, recorded in its history entry
+func (e *Executor) SetWorktree(worktree string) {
+	e.worktree = worktree
+}
+
+// SetTrigger sets what started the run, recorded in its history entry
+func (e *Executor) SetTrigger(trigger string) {
+	e.trigger = trigger
+}
+
+// SetWaitForLock makes the run wait while other runs hold the locks it needs
+// (the task's max_concurrent, max_concurrent_agents, the project root)
+// instead of failing with a *LockError
+func (e *Executor) SetWaitForLock(wait bool) {
+	e.waitForLock = wait
+}
+
+// Run executes the agent task and records the outcome in the execution history.
+// A run that cannot take its locks is recorded as skipped.
 func (e *Executor) Run() error {
-	fmt.Printf("🤖 Running agent task: %s\n", e.task.Name)
+	release, err := e.acquireLocks(e.waitForLock)
+	if err != nil {
+		e.recordSkipped(err)
+		return err
+	}
+	defer release()
+
+	start := time.Now()
+	e.logEvent(history.PhaseRun, e.task.Name, "started", time.Time{}, nil, map[string]string{"trigger": e.trigger})
+	err = e.run()
+	e.recordHistory(start, err)
+	return err
+}
+
+// loadHistory loads the execution history with the retention policy of the
+// history: section, which Record enforces
+func loadHistory(cfg *config.Config, workCfg *config.WorktreeConfig) (*history.History, error) {
+	h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	h.SetRetention(history.Retention{MaxRecords: workCfg.History.GetMaxRecords(), MaxAge: workCfg.History.GetMaxAge()})
+	return h, nil
+}
+
+// recordSkipped records a run that did not start
+func (e *Executor) recordSkipped(reason error) {
+	h, err := loadHistory(e.cfg, e.workCfg)
+	if err == nil {
+		now := time.Now()
+		err = h.Record(history.ExecutionRecord{
+			ID:        uuid.New().String(),
+			AgentName: e.agentName,
+			Worktree:  e.worktree,
+			Status:    history.RunSkipped,
+			StartTime: now,
+			EndTime:   now,
+			Error:     reason.Error(),
+			Trigger:   e.trigger,
+		})
+	}
+	if err != nil {
+		ui.Printf("⚠️  Failed to record execution history: %v\n", err)
+	}
+}
+
+// recordHistory appends an execution record to the execution history, with
+// the run's structured log in worktrees/.agent-logs/<id>.json. Failing to
+// record never fails the task itself.
+func (e *Executor) recordHistory(start time.Time, runErr error) {
+	h, err := loadHistory(e.cfg, e.workCfg)
+	if err == nil {
+		end := time.Now()
+		record := history.ExecutionRecord{
+			ID:            uuid.New().String(),
+			AgentName:     e.agentName,
+			Worktree:      e.worktree,
+			Status:        "completed",
+			StartTime:     start,
+			EndTime:       end,
+			Duration:      end.Sub(start).Milliseconds(),
+			StepsExecuted: e.stepsExecuted,
+			Steps:         e.stepResults,
+			Trigger:       e.trigger,
+			PRUrl:         strings.Join(e.requestURLs, ", "),
+			Commits:       e.commits,
+		}
+		if runErr != nil {
+			record.Status = "failed"
+			record.Error = runErr.Error()
+		}
+		if path, logErr := e.saveLog(h, &record); logErr != nil {
+			ui.Printf("⚠️  Failed to write execution log: %v\n", logErr)
+		} else {
+			record.Log = path
+		}
+		// Keep the definition as executed; the YAML may change before anyone reads the record
+		if hash, snapErr := snapshotTask(h, e.task); snapErr != nil {
+			ui.Printf("⚠️  Failed to store task definition snapshot: %v\n", snapErr)
+		} else {
+			record.TaskHash = hash
+		}
+		err = h.Record(record)
+	}
+	if err != nil {
+		ui.Printf("⚠️  Failed to record execution history: %v\n", err)
+	}
+}
+
+// snapshotTask stores the task definition in the history's snapshot store
+func snapshotTask(h *history.History, task *config.AgentTask) (string, error) {
+	definition, err := TaskDefinition(task)
+	if err != nil {
+		return "", err
+	}
+	return h.SaveTaskSnapshot(definition)
+}
+
+// TaskDefinition renders a task as the YAML stored in history snapshots
+func TaskDefinition(task *config.AgentTask) ([]byte, error) {
+	definition, err := yaml.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task definition: %w", err)
+	}
+	return definition, nil
+}
+
+// run executes the agent task
+func (e *Executor) run() error {
+	ui.Printf("🤖 Running agent task: %s\n", e.task.Name)
 	fmt.Printf("   %s\n", e.task.Description)
 	fmt.Println()
 
@@ -44,6 +193,26 @@ func (e *Executor) Run() error {
 		return e.runGSDWorkflow()
 	}
 
+	env, err := buildStepEnv(os.Environ(), e.cfg.ProjectRoot, e.workCfg, e.task)
+	if err != nil {
+		return err
+	}
+	e.env = env
+	if e.task.Environment.Isolated {
+		fmt.Println("   Environment: isolated")
+		fmt.Println()
+	}
+	if e.task.Context.IsolatedWorktree {
+		defer func() {
+			if e.runWT != nil {
+				e.removeWorktree()
+			}
+		}()
+		if err := e.createWorktree(); err != nil {
+			return fmt.Errorf("failed to create agent worktree: %w", err)
+		}
+	}
+
 	// Phase 1: Execute steps
 	if err := e.executeSteps(); err != nil {
 		return fmt.Errorf("step execution failed: %w", err)
@@ -56,7 +225,7 @@ func (e *Executor) Run() error {
 			// Rollback if enabled
 			if e.task.Safety.Rollback.Enabled {
 				fmt.Println()
-				fmt.Printf("⚠️  Rolling back due to safety gate failures...\n")
+				ui.Printf("⚠️  Rolling back due to safety gate failures...\n")
 				e.cleanupWorktree()
 			}
 			return fmt.Errorf("safety gates failed: %w", err)
@@ -73,7 +242,7 @@ func (e *Executor) Run() error {
 			// Rollback if enabled
 			if e.task.Safety.Rollback.Enabled {
 				fmt.Println()
-				fmt.Printf("⚠️  Rolling back due to git operation failure...\n")
+				ui.Printf("⚠️  Rolling back due to git operation failure...\n")
 				e.cleanupWorktree()
 			}
 			return fmt.Errorf("git operations failed: %w", err)
@@ -84,29 +253,29 @@ func (e *Executor) Run() error {
 	}
 
 	fmt.Println()
-	fmt.Printf("✅ Agent task '%s' completed successfully\n", e.task.Name)
+	ui.Printf("✅ Agent task '%s' completed successfully\n", e.task.Name)
 	return nil
 }
 
 // executeSteps runs all configured steps
 func (e *Executor) executeSteps() error {
-	fmt.Println("📋 Executing steps...")
+	ui.Println("📋 Executing steps...")
 	fmt.Println()
 
 	for i, step := range e.task.Steps {
 		fmt.Printf("  [%d/%d] %s\n", i+1, len(e.task.Steps), step.Name)
 
-		switch step.Type {
-		case "shell":
-			if err := e.executeShellStep(step); err != nil {
-				return fmt.Errorf("step '%s' failed: %w", step.Name, err)
-			}
-		case "skill":
-			if err := e.executeSkillStep(step); err != nil {
-				return fmt.Errorf("step '%s' failed: %w", step.Name, err)
-			}
-		default:
-			return fmt.Errorf("unknown step type: %s", step.Type)
+		result, err := e.runStep(step)
+		if err != nil && step.ContinueOnError {
+			result.Continued = true
+			ui.Warning(fmt.Sprintf("⚠️  Step '%s' failed, continuing (continue_on_error): %v", step.Name, err))
+		}
+		e.stepResults = append(e.stepResults, result)
+		if err != nil && !step.ContinueOnError {
+			return fmt.Errorf("step '%s' failed: %w", step.Name, err)
+		}
+		if err == nil {
+			e.stepsExecuted++
 		}
 
 		fmt.Println()
@@ -115,45 +284,105 @@ func (e *Executor) executeSteps() error {
 	return nil
 }
 
+// runStep runs a step, retrying it with a doubling delay until it succeeds or
+// its attempts are used up, and returns its outcome for the history
+func (e *Executor) runStep(step config.AgentStep) (history.StepResult, error) {
+	result := history.StepResult{Name: step.Name, Type: step.Type, Status: history.StepCompleted}
+	start := time.Now()
+
+	err := config.ValidateStepPolicy(step)
+	if err == nil {
+		attempts := step.Attempts()
+		delay := step.GetRetryDelay()
+		for attempt := 1; ; attempt++ {
+			result.Attempts = attempt
+			e.stepOutput = &outputTail{}
+			err = e.executeStep(step)
+			if err == nil || attempt >= attempts {
+				break
+			}
+			ui.Warning(fmt.Sprintf("⚠️  Attempt %d of %d failed: %v; retrying in %s", attempt, attempts, err, delay))
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	result.DurationMS = time.Since(start).Milliseconds()
+	if e.stepOutput != nil {
+		result.Output = e.stepOutput.String()
+		e.stepOutput = nil
+	}
+	if err != nil {
+		result.Status = history.StepFailed
+		if errors.Is(err, process.ErrTimeout) {
+			result.Status = history.StepTimedOut
+		}
+		result.Error = err.Error()
+	}
+	e.logEvent(history.PhaseStep, step.Name, result.Status, start, err, map[string]string{
+		"type":     step.Type,
+		"attempts": strconv.Itoa(result.Attempts),
+	})
+	return result, err
+}
+
+// executeStep runs one attempt of a step
+func (e *Executor) executeStep(step config.AgentStep) error {
+	env, err := e.stepEnv(step)
+	if err != nil {
+		return err
+	}
+
+	switch step.Type {
+	case "shell":
+		return e.executeShellStep(step, env)
+	case "skill":
+		return e.executeSkillStep(step, env)
+	case "pipeline":
+		return e.executePipelineStep(step, env)
+	case "docker":
+		return e.executeDockerStep(step, env)
+	case "http":
+		return e.executeHTTPStep(step, env)
+	default:
+		return fmt.Errorf("unknown step type: %s", step.Type)
+	}
+}
+
 // executeShellStep executes a shell command step
-func (e *Executor) executeShellStep(step config.AgentStep) error {
+func (e *Executor) executeShellStep(step config.AgentStep, env []string) error {
 	cmd := exec.Command("bash", "-c", step.Command)
 
-	// Set working directory if specified
-	if step.WorkingDir != "" {
+	// Run in the step's working directory, or the run worktree of an isolated run
+	if e.runWT != nil {
+		cmd.Dir = e.stepDir(step, e.runWT.Dir)
+	} else {
 		cmd.Dir = step.WorkingDir
 	}
 
 	// Connect stdout and stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	// Run the command
-	return cmd.Run()
-}
-
-// createWorktree creates a temporary agent worktree (placeholder for Phase 1)
-func (e *Executor) createWorktree() error {
-	fmt.Println("🔨 Creating agent worktree...")
-	fmt.Printf("   Instance: %d\n", e.task.Context.Instance)
-	fmt.Printf("   Preset: %s\n", e.task.Context.Preset)
-	fmt.Printf("   YOLO mode: %v\n", e.task.Context.Yolo)
-	fmt.Println()
+	e.connectOutput(cmd)
+	cmd.Env = env
 
-	// TODO: Phase 1 - placeholder only
-	// Actual worktree creation will be implemented later
-	return nil
+	// Run the command, killing it after the step's timeout
+	return process.RunCommand(cmd, step.GetTimeout())
 }
 
 // runSafetyGates executes all configured safety gates
 func (e *Executor) runSafetyGates() error {
-	fmt.Println("🛡️  Running safety gates...")
+	return e.runGates(e.task.Safety.Gates)
+}
+
+// runGates executes safety gates in the project root (the run worktree of an
+// isolated run), failing if a required one fails
+func (e *Executor) runGates(gates []config.SafetyGate) error {
+	ui.Println("🛡️  Running safety gates...")
 	fmt.Println()
 
 	var failedGates []string
 	var warnings []string
 
-	for i, gate := range e.task.Safety.Gates {
+	for i, gate := range gates {
 		requiredLabel := ""
 		if gate.Required {
 			requiredLabel = " (required)"
@@ -161,23 +390,32 @@ func (e *Executor) runSafetyGates() error {
 			requiredLabel = " (optional)"
 		}
 
-		fmt.Printf("  [%d/%d] %s%s\n", i+1, len(e.task.Safety.Gates), gate.Name, requiredLabel)
+		fmt.Printf("  [%d/%d] %s%s\n", i+1, len(gates), gate.Name, requiredLabel)
 		fmt.Printf("        Command: %s\n", gate.Command)
 
 		// Execute the gate command
+		gateStart := time.Now()
 		cmd := exec.Command("bash", "-c", gate.Command)
-		cmd.Dir = e.cfg.ProjectRoot
+		cmd.Dir = e.workDir()
+		cmd.Env = e.env
 
 		// Capture output
 		output, err := cmd.CombinedOutput()
+		details := map[string]string{"command": gate.Command, "required": strconv.FormatBool(gate.Required)}
+		if err != nil {
+			details["output"] = truncateOutput(output)
+			e.logEvent(history.PhaseGate, gate.Name, "failed", gateStart, err, details)
+		} else {
+			e.logEvent(history.PhaseGate, gate.Name, "passed", gateStart, nil, details)
+		}
 
 		if err != nil {
 			// Gate failed
 			if gate.Required {
-				fmt.Printf("        ❌ Failed (required)\n")
+				ui.Printf("        ❌ Failed (required)\n")
 				failedGates = append(failedGates, gate.Name)
 			} else {
-				fmt.Printf("        ⚠️  Failed (optional - continuing)\n")
+				ui.Printf("        ⚠️  Failed (optional - continuing)\n")
 				warnings = append(warnings, gate.Name)
 			}
 
@@ -187,25 +425,27 @@ func (e *Executor) runSafetyGates() error {
 			}
 		} else {
 			// Gate passed
-			fmt.Printf("        ✅ Passed\n")
+			ui.Printf("        ✅ Passed\n")
 		}
 
 		fmt.Println()
 	}
 
 	// Summary
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Separator(53)
 	fmt.Println("Safety Gates Summary:")
-	fmt.Printf("  Total: %d\n", len(e.task.Safety.Gates))
-	fmt.Printf("  Passed: %d\n", len(e.task.Safety.Gates)-len(failedGates)-len(warnings))
+	fmt.Printf("  Total: %d\n", len(gates))
+	fmt.Printf("  Passed: %d\n", len(gates)-len(failedGates)-len(warnings))
 	fmt.Printf("  Failed (required): %d\n", len(failedGates))
 	fmt.Printf("  Failed (optional): %d\n", len(warnings))
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Separator(53)
+
+	e.failedGates = append(append([]string{}, failedGates...), warnings...)
 
 	// If any required gates failed, return error
 	if len(failedGates) > 0 {
 		fmt.Println()
-		fmt.Printf("❌ Required safety gates failed:\n")
+		ui.Printf("❌ Required safety gates failed:\n")
 		for _, gate := range failedGates {
 			fmt.Printf("   - %s\n", gate)
 		}
@@ -215,7 +455,7 @@ func (e *Executor) runSafetyGates() error {
 	// Show warnings for optional gates
 	if len(warnings) > 0 {
 		fmt.Println()
-		fmt.Printf("⚠️  Optional safety gates failed (continuing anyway):\n")
+		ui.Printf("⚠️  Optional safety gates failed (continuing anyway):\n")
 		for _, gate := range warnings {
 			fmt.Printf("   - %s\n", gate)
 		}
@@ -224,137 +464,171 @@ func (e *Executor) runSafetyGates() error {
 	return nil
 }
 
-// commitAndPush performs git operations
+// commitAndPush performs git operations: in the project root, or in each
+// project worktree of an isolated run
 func (e *Executor) commitAndPush() error {
-	fmt.Println("📝 Git Operations...")
+	ui.Println("📝 Git Operations...")
 	fmt.Println()
 
-	// Replace {date} placeholder in branch name and messages
-	dateStr := time.Now().Format("2006-01-02")
-	branch := strings.ReplaceAll(e.task.Safety.Git.Branch, "{date}", dateStr)
-	prTitle := strings.ReplaceAll(e.task.Safety.Git.Push.PRTitle, "{date}", dateStr)
-	prBody := strings.ReplaceAll(e.task.Safety.Git.Push.PRBody, "{date}", dateStr)
+	// Replace {date} placeholder in branch name
+	branch := strings.ReplaceAll(e.task.Safety.Git.Branch, "{date}", time.Now().Format("2006-01-02"))
+
+	if e.runWT == nil {
+		if _, err := e.commitAndPushDir(e.cfg.ProjectRoot, branch, true); err != nil {
+			return err
+		}
+	} else {
+		for i := range e.runWT.Projects {
+			p := &e.runWT.Projects[i]
+			fmt.Printf("  %s:\n", p.Name)
+			pushed, err := e.commitAndPushDir(p.Dir, e.runWT.Branch, false)
+			p.Pushed = pushed
+			if err != nil {
+				return fmt.Errorf("%s: %w", p.Name, err)
+			}
+		}
+	}
 
+	fmt.Println()
+	ui.Printf("✅ Git operations completed successfully\n")
+	return nil
+}
+
+// commitAndPushDir commits the changes in the repository at dir to branch,
+// pushes them and opens a pull/merge request, reporting whether anything was
+// pushed. The branch is checked out first when checkout is set.
+func (e *Executor) commitAndPushDir(dir, branch string, checkout bool) (bool, error) {
 	// Check if there are changes to commit
 	fmt.Printf("  Checking for changes...\n")
 	statusCmd := exec.Command("git", "status", "--porcelain")
-	statusCmd.Dir = e.cfg.ProjectRoot
+	statusCmd.Dir = dir
 	output, err := statusCmd.Output()
 	if err != nil {
-		return fmt.Errorf("failed to check git status: %w", err)
+		return false, fmt.Errorf("failed to check git status: %w", err)
 	}
 
 	if len(output) == 0 {
-		fmt.Printf("  ℹ️  No changes to commit\n")
-		return nil
+		ui.Printf("  ℹ️  No changes to commit\n")
+		e.logEvent(history.PhaseGit, "commit", "skipped", time.Time{}, nil, map[string]string{"dir": dir, "reason": "no changes"})
+		return false, nil
 	}
 
-	fmt.Printf("  ✅ Changes detected\n")
+	ui.Printf("  ✅ Changes detected\n")
 	fmt.Println()
 
 	// Create and checkout branch
-	fmt.Printf("  Creating branch: %s\n", branch)
-	checkoutCmd := exec.Command("git", "checkout", "-b", branch)
-	checkoutCmd.Dir = e.cfg.ProjectRoot
-	if branchOutput, err := checkoutCmd.CombinedOutput(); err != nil {
-		// Branch might already exist, try to checkout
-		checkoutCmd = exec.Command("git", "checkout", branch)
-		checkoutCmd.Dir = e.cfg.ProjectRoot
-		if checkoutOutput, err := checkoutCmd.CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to checkout branch: %w\nOutput: %s", err, string(checkoutOutput))
+	if checkout {
+		fmt.Printf("  Creating branch: %s\n", branch)
+		checkoutCmd := exec.Command("git", "checkout", "-b", branch)
+		checkoutCmd.Dir = dir
+		if branchOutput, err := checkoutCmd.CombinedOutput(); err != nil {
+			// Branch might already exist, try to checkout
+			checkoutCmd = exec.Command("git", "checkout", branch)
+			checkoutCmd.Dir = dir
+			if checkoutOutput, err := checkoutCmd.CombinedOutput(); err != nil {
+				return false, fmt.Errorf("failed to checkout branch: %w\nOutput: %s", err, string(checkoutOutput))
+			}
+			_ = branchOutput // Ignore unused
 		}
-		_ = branchOutput // Ignore unused
+		ui.Printf("  ✅ Branch created/checked out\n")
+		fmt.Println()
 	}
-	fmt.Printf("  ✅ Branch created/checked out\n")
-	fmt.Println()
 
 	// Stage all changes
 	fmt.Printf("  Staging changes...\n")
 	addCmd := exec.Command("git", "add", ".")
-	addCmd.Dir = e.cfg.ProjectRoot
+	addCmd.Dir = dir
 	if output, err := addCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to stage changes: %w\nOutput: %s", err, string(output))
+		return false, fmt.Errorf("failed to stage changes: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Printf("  ✅ Changes staged\n")
+	ui.Printf("  ✅ Changes staged\n")
 	fmt.Println()
 
 	// Commit
 	fmt.Printf("  Creating commit...\n")
+	commitStart := time.Now()
 	commitMsg := e.task.Safety.Git.CommitMessage
 	commitCmd := exec.Command("git", "commit", "-m", commitMsg)
-	commitCmd.Dir = e.cfg.ProjectRoot
+	commitCmd.Dir = dir
 	if output, err := commitCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to commit: %w\nOutput: %s", err, string(output))
+		e.logEvent(history.PhaseGit, "commit", "failed", commitStart, err, map[string]string{"dir": dir, "output": truncateOutput(output)})
+		return false, fmt.Errorf("failed to commit: %w\nOutput: %s", err, string(output))
 	}
-	fmt.Printf("  ✅ Commit created\n")
+	sha, _ := gitOutput(dir, "rev-parse", "HEAD")
+	e.logEvent(history.PhaseGit, "commit", "completed", commitStart, nil, map[string]string{"dir": dir, "branch": branch, "sha": sha})
+	ui.Printf("  ✅ Commit created\n")
 	fmt.Println()
 
 	// Push to remote
 	fmt.Printf("  Pushing to remote...\n")
+	pushStart := time.Now()
 	pushCmd := exec.Command("git", "push", "-u", "origin", branch)
-	pushCmd.Dir = e.cfg.ProjectRoot
+	pushCmd.Dir = dir
 	if output, err := pushCmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to push: %w\nOutput: %s", err, string(output))
+		e.logEvent(history.PhaseGit, "push", "failed", pushStart, err, map[string]string{"dir": dir, "branch": branch, "output": truncateOutput(output)})
+		return false, fmt.Errorf("failed to push: %w\nOutput: %s", err, string(output))
+	}
+	if sha != "" {
+		e.commits = append(e.commits, sha)
 	}
-	fmt.Printf("  ✅ Pushed to origin/%s\n", branch)
+	e.logEvent(history.PhaseGit, "push", "completed", pushStart, nil, map[string]string{"dir": dir, "branch": branch})
+	ui.Printf("  ✅ Pushed to origin/%s\n", branch)
 	fmt.Println()
 
-	// Create PR if requested
+	// Open a pull/merge request if requested
 	if e.task.Safety.Git.Push.CreatePR {
-		fmt.Printf("  Creating pull request...\n")
-
-		prCmd := exec.Command("gh", "pr", "create",
-			"--title", prTitle,
-			"--body", prBody,
-			"--head", branch)
-		prCmd.Dir = e.cfg.ProjectRoot
-
-		output, err := prCmd.CombinedOutput()
-		if err != nil {
-			// Check if gh is installed
-			if strings.Contains(err.Error(), "executable file not found") {
-				fmt.Printf("  ⚠️  GitHub CLI (gh) not installed - skipping PR creation\n")
-				fmt.Printf("      Install: brew install gh (macOS) or see https://cli.github.com\n")
-			} else {
-				return fmt.Errorf("failed to create PR: %w\nOutput: %s", err, string(output))
-			}
-		} else {
-			prURL := strings.TrimSpace(string(output))
-			fmt.Printf("  ✅ Pull request created: %s\n", prURL)
+		target := e.targetBranch()
+		req := changeRequest{
+			Title:  e.expandRequestTemplate(e.task.Safety.Git.Push.PRTitle, branch, target),
+			Body:   e.expandRequestTemplate(e.task.Safety.Git.Push.PRBody, branch, target),
+			Source: branch,
+			Target: target,
+		}
+		if err := e.openChangeRequest(dir, req); err != nil {
+			return true, err
 		}
 	}
 
-	fmt.Println()
-	fmt.Printf("✅ Git operations completed successfully\n")
-	return nil
+	// Post gate results on the pushed commit if requested
+	if e.task.Safety.Git.Push.CommitStatus != "" {
+		e.postCommitStatus(dir)
+	}
+
+	return true, nil
 }
 
 // cleanupWorktree removes the agent worktree
 func (e *Executor) cleanupWorktree() {
-	fmt.Println("🧹 Cleaning up...")
+	ui.Println("🧹 Cleaning up...")
+
+	// An isolated run never touched the project root; its worktree is removed when the run ends
+	if e.runWT != nil {
+		ui.Printf("  ✅ Changes are discarded with the agent worktree\n")
+		return
+	}
 
 	// Reset to main branch
 	checkoutCmd := exec.Command("git", "checkout", e.task.Context.Branch)
 	checkoutCmd.Dir = e.cfg.ProjectRoot
 	if err := checkoutCmd.Run(); err != nil {
-		fmt.Printf("  ⚠️  Failed to checkout %s: %v\n", e.task.Context.Branch, err)
+		ui.Printf("  ⚠️  Failed to checkout %s: %v\n", e.task.Context.Branch, err)
 	}
 
 	// Discard all changes
 	resetCmd := exec.Command("git", "reset", "--hard", "HEAD")
 	resetCmd.Dir = e.cfg.ProjectRoot
 	if err := resetCmd.Run(); err != nil {
-		fmt.Printf("  ⚠️  Failed to reset: %v\n", err)
+		ui.Printf("  ⚠️  Failed to reset: %v\n", err)
 	}
 
 	// Clean untracked files
 	cleanCmd := exec.Command("git", "clean", "-fd")
 	cleanCmd.Dir = e.cfg.ProjectRoot
 	if err := cleanCmd.Run(); err != nil {
-		fmt.Printf("  ⚠️  Failed to clean: %v\n", err)
+		ui.Printf("  ⚠️  Failed to clean: %v\n", err)
 	}
 
-	fmt.Printf("  ✅ Cleanup completed\n")
+	ui.Printf("  ✅ Cleanup completed\n")
 }
 
 // sendNotifications sends configured notifications
@@ -372,7 +646,7 @@ func (e *Executor) sendNotifications(success bool, err error) {
 	}
 
 	fmt.Println()
-	fmt.Println("📢 Sending notifications...")
+	ui.Println("📢 Sending notifications...")
 	fmt.Println()
 
 	for _, notification := range notifications {
@@ -380,11 +654,11 @@ func (e *Executor) sendNotifications(success bool, err error) {
 		case "slack":
 			e.sendSlackNotification(notification, success, err)
 		case "gitlab_issue":
-			fmt.Printf("  ⚠️  GitLab issue notifications not yet implemented\n")
+			ui.Printf("  ⚠️  GitLab issue notifications not yet implemented\n")
 		case "email":
-			fmt.Printf("  ⚠️  Email notifications not yet implemented\n")
+			ui.Printf("  ⚠️  Email notifications not yet implemented\n")
 		default:
-			fmt.Printf("  ⚠️  Unknown notification type: %s\n", notification.Type)
+			ui.Printf("  ⚠️  Unknown notification type: %s\n", notification.Type)
 		}
 	}
 }
@@ -394,7 +668,7 @@ func (e *Executor) sendSlackNotification(notification config.Notification, succe
 	// Get webhook URL from notification or environment
 	webhookURL := notification.Recipients[0] // Webhook URL stored in recipients[0]
 	if webhookURL == "" {
-		fmt.Printf("  ⚠️  Slack webhook URL not configured\n")
+		ui.Printf("  ⚠️  Slack webhook URL not configured\n")
 		return
 	}
 
@@ -445,24 +719,24 @@ func (e *Executor) sendSlackNotification(notification config.Notification, succe
 	// Marshal to JSON
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Printf("  ❌ Failed to create Slack payload: %v\n", err)
+		ui.Printf("  ❌ Failed to create Slack payload: %v\n", err)
 		return
 	}
 
 	// Send HTTP POST request
 	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonPayload))
 	if err != nil {
-		fmt.Printf("  ❌ Failed to send Slack notification: %v\n", err)
+		ui.Printf("  ❌ Failed to send Slack notification: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("  ❌ Slack returned error: %s\n", resp.Status)
+		ui.Printf("  ❌ Slack returned error: %s\n", resp.Status)
 		return
 	}
 
-	fmt.Printf("  ✅ Slack notification sent\n")
+	ui.Printf("  ✅ Slack notification sent\n")
 }
 
 // updateRegistry updates the last run time in the registry (placeholder)
@@ -471,57 +745,155 @@ func (e *Executor) updateRegistry() {
 	_ = time.Now() // Placeholder to avoid unused import warning
 }
 
-// runGSDWorkflow executes the agent task using GSD framework
+// runGSDWorkflow executes the agent task using GSD framework. Phases run
+// one claude session each; progress is persisted (see GSDState) so a failed
+// or interrupted run resumes at the first phase that has not completed.
 func (e *Executor) runGSDWorkflow() error {
+	env, err := buildStepEnv(os.Environ(), e.cfg.ProjectRoot, e.workCfg, e.task)
+	if err != nil {
+		return err
+	}
+	e.env = env
+
+	phases, err := e.gsdPhases()
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(phases))
+	for i, phase := range phases {
+		names[i] = phase.Name
+	}
+
+	statePath := GSDStatePath(e.cfg.WorktreeDir, e.agentName, e.worktree)
+	state, err := LoadGSDState(statePath)
+	if err != nil {
+		return err
+	}
+	switch {
+	case state == nil || state.Done():
+		state = NewGSDState(statePath, e.agentName, e.worktree, e.task.GSD.Milestone, names)
+	case !state.Matches(e.task.GSD.Milestone, names):
+		ui.Printf("⚠️  GSD milestone or phases changed since the last run, starting over\n")
+		fmt.Println()
+		state = NewGSDState(statePath, e.agentName, e.worktree, e.task.GSD.Milestone, names)
+	case state.Completed() > 0:
+		ui.Printf("⏩ Resuming GSD milestone '%s' at phase %d/%d (%s)\n",
+			state.Milestone, state.NextPhase()+1, len(phases), phases[state.NextPhase()].Name)
+		fmt.Println()
+	}
+
+	for i := state.NextPhase(); i >= 0 && i < len(phases); i++ {
+		if err := e.runGSDPhase(state, i, phases[i]); err != nil {
+			// Send failure notification
+			e.sendNotifications(false, err)
+
+			// Rollback if enabled
+			if e.task.Safety.Rollback.Enabled {
+				fmt.Println()
+				ui.Printf("⚠️  Rolling back due to GSD workflow failure...\n")
+				e.cleanupWorktree()
+			}
+
+			return fmt.Errorf("GSD workflow failed: %w", err)
+		}
+	}
+
+	// Send success notification
+	if len(e.task.Notifications.OnSuccess) > 0 {
+		e.sendNotifications(true, nil)
+	}
+
+	fmt.Println()
+	ui.Printf("✅ GSD workflow completed successfully\n")
+	return nil
+}
+
+// gsdPhases returns the configured phases with {task} replaced by .task.md,
+// or a single phase planned from .task.md when none are configured
+func (e *Executor) gsdPhases() ([]config.GSDPhase, error) {
 	var taskContent string
-	var err error
 
 	// Read .task.md if configured
 	if e.task.GSD.ReadTaskFile {
+		var err error
 		taskContent, err = ReadTaskFile(e.cfg.ProjectRoot)
 		if err != nil {
-			return fmt.Errorf("failed to read task file: %w", err)
+			return nil, fmt.Errorf("failed to read task file: %w", err)
 		}
 
 		if taskContent == "" {
-			return fmt.Errorf(".task.md not found, but read_task_file is enabled")
+			return nil, fmt.Errorf(".task.md not found, but read_task_file is enabled")
 		}
 
-		fmt.Printf("📄 Task file loaded: .task.md\n")
+		ui.Printf("📄 Task file loaded: .task.md\n")
 		fmt.Printf("   Length: %d characters\n", len(taskContent))
 		fmt.Println()
 	}
 
-	// Build GSD workflow
-	workflow := GSDWorkflow{
-		Milestone:   e.task.GSD.Milestone,
-		Phase:       taskContent,
-		AutoExecute: e.task.GSD.AutoExecute,
-		YoloMode:    e.task.Context.Yolo,
+	if len(e.task.GSD.Phases) == 0 {
+		return []config.GSDPhase{{Name: "task", Prompt: taskContent}}, nil
 	}
 
-	// Launch GSD workflow
-	err = LaunchGSDWorkflow(e.cfg, workflow)
-	if err != nil {
-		// Send failure notification
-		e.sendNotifications(false, err)
+	phases := make([]config.GSDPhase, len(e.task.GSD.Phases))
+	for i, phase := range e.task.GSD.Phases {
+		if phase.Name == "" {
+			return nil, fmt.Errorf("GSD phase %d has no name", i+1)
+		}
+		if e.task.GSD.ReadTaskFile {
+			phase.Prompt = InjectTaskIntoSkill(phase.Prompt, taskContent)
+		}
+		phases[i] = phase
+	}
+	return phases, nil
+}
 
-		// Rollback if enabled
-		if e.task.Safety.Rollback.Enabled {
-			fmt.Println()
-			fmt.Printf("⚠️  Rolling back due to GSD workflow failure...\n")
-			e.cleanupWorktree()
+// runGSDPhase plans (and with auto_execute executes) one phase, then runs its
+// gates, recording the outcome in the milestone state
+func (e *Executor) runGSDPhase(state *GSDState, i int, phase config.GSDPhase) error {
+	ui.Printf("📍 GSD phase %d/%d: %s\n", i+1, len(state.Phases), phase.Name)
+	fmt.Println()
+
+	state.StartPhase(i)
+	if err := state.Save(); err != nil {
+		return err
+	}
+
+	fail := func(err error, failedGates []string) error {
+		state.FailPhase(i, err, failedGates)
+		if saveErr := state.Save(); saveErr != nil {
+			ui.Printf("⚠️  Failed to save GSD state: %v\n", saveErr)
 		}
+		return fmt.Errorf("phase '%s' failed: %w", phase.Name, err)
+	}
 
-		return fmt.Errorf("GSD workflow failed: %w", err)
+	workflow := GSDWorkflow{
+		Milestone:    state.Milestone,
+		NewMilestone: !state.MilestoneStarted,
+		Phase:        phase.Prompt,
+		AutoExecute:  e.task.GSD.AutoExecute,
+		YoloMode:     e.task.Context.Yolo,
+		Env:          e.env,
+	}
+	if err := LaunchGSDWorkflow(e.cfg, workflow); err != nil {
+		return fail(err, nil)
 	}
+	state.MilestoneStarted = true
 
-	// Send success notification
-	if len(e.task.Notifications.OnSuccess) > 0 {
-		e.sendNotifications(true, nil)
+	if len(phase.Gates) > 0 {
+		fmt.Println()
+		if err := e.runGates(phase.Gates); err != nil {
+			return fail(err, e.failedGates)
+		}
 	}
 
+	state.CompletePhase(i)
+	if err := state.Save(); err != nil {
+		return err
+	}
+	e.stepsExecuted++
+
+	fmt.Println()
+	ui.Printf("✅ Phase %s completed (%d/%d)\n", phase.Name, state.Completed(), len(state.Phases))
 	fmt.Println()
-	fmt.Printf("✅ GSD workflow completed successfully\n")
 	return nil
 }
@@ -0,0 +1,188 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Forges for safety.git.forge: where pull/merge requests are opened
+const (
+	ForgeAuto   = "auto"   // Detected from the origin remote (default)
+	ForgeGitHub = "github" // Pull requests with gh
+	ForgeGitLab = "gitlab" // Merge requests with glab, or the GitLab API with GITLAB_TOKEN
+)
+
+// changeRequest is a pull request (GitHub) or merge request (GitLab) to open
+type changeRequest struct {
+	Title  string
+	Body   string
+	Source string // Branch with the changes
+	Target string // Branch to merge into
+}
+
+// detectForge guesses the forge of a remote URL: GitLab when its host
+// mentions gitlab, GitHub otherwise
+func detectForge(remote string) string {
+	host, _, err := parseRemoteURL(remote)
+	if err == nil && strings.Contains(strings.ToLower(host), "gitlab") {
+		return ForgeGitLab
+	}
+	return ForgeGitHub
+}
+
+// forge returns the forge the task opens requests on for the repository in dir
+func (e *Executor) forge(dir string) string {
+	forge := e.task.Safety.Git.Forge
+	if forge != "" && forge != ForgeAuto {
+		return forge
+	}
+	remote, err := gitOutput(dir, "remote", "get-url", "origin")
+	if err != nil {
+		return ForgeGitHub
+	}
+	return detectForge(remote)
+}
+
+// targetBranch returns the branch requests merge into: push.target_branch,
+// or the branch the run started from
+func (e *Executor) targetBranch() string {
+	if target := e.task.Safety.Git.Push.TargetBranch; target != "" {
+		return target
+	}
+	return e.task.Context.Branch
+}
+
+// expandRequestTemplate replaces the placeholders of pr_title and pr_body:
+// {date}, {task}, {branch}, {target} and {gates} (the safety gate summary)
+func (e *Executor) expandRequestTemplate(text, branch, target string) string {
+	status := newCommitStatus(e.agentName, len(e.task.Safety.Gates), e.failedGates)
+	return strings.NewReplacer(
+		"{date}", time.Now().Format("2006-01-02"),
+		"{task}", e.agentName,
+		"{branch}", branch,
+		"{target}", target,
+		"{gates}", status.Description,
+	).Replace(text)
+}
+
+// openChangeRequest opens a pull or merge request for the pushed branch of
+// the repository in dir and records its URL for the history
+func (e *Executor) openChangeRequest(dir string, req changeRequest) error {
+	forge := e.forge(dir)
+	kind := "pull request"
+	if forge == ForgeGitLab {
+		kind = "merge request"
+	}
+	fmt.Printf("  Creating %s (%s → %s)...\n", kind, req.Source, req.Target)
+	start := time.Now()
+	details := map[string]string{"dir": dir, "forge": forge, "source": req.Source, "target": req.Target}
+
+	var requestURL string
+	var err error
+	switch forge {
+	case ForgeGitHub:
+		requestURL, err = createGitHubPR(dir, req)
+	case ForgeGitLab:
+		requestURL, err = createGitLabMR(dir, req)
+	default:
+		err = fmt.Errorf("unknown forge '%s' (expected %s, %s or %s)", forge, ForgeGitHub, ForgeGitLab, ForgeAuto)
+	}
+	if err != nil {
+		e.logEvent(history.PhaseGit, kind, "failed", start, err, details)
+		return fmt.Errorf("failed to create %s: %w", kind, err)
+	}
+	if requestURL == "" {
+		e.logEvent(history.PhaseGit, kind, "skipped", start, nil, details)
+		return nil
+	}
+	details["url"] = requestURL
+	e.logEvent(history.PhaseGit, kind, "completed", start, nil, details)
+	e.requestURLs = append(e.requestURLs, requestURL)
+	ui.Printf("  ✅ %s created: %s\n", strings.ToUpper(kind[:1])+kind[1:], requestURL)
+	return nil
+}
+
+// createGitHubPR opens a pull request with the GitHub CLI. Without gh
+// installed the request is skipped with a warning.
+func createGitHubPR(dir string, req changeRequest) (string, error) {
+	args := []string{"pr", "create", "--title", req.Title, "--body", req.Body, "--head", req.Source}
+	if req.Target != "" {
+		args = append(args, "--base", req.Target)
+	}
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if strings.Contains(err.Error(), "executable file not found") {
+			ui.Printf("  ⚠️  GitHub CLI (gh) not installed - skipping PR creation\n")
+			fmt.Printf("      Install: brew install gh (macOS) or see https://cli.github.com\n")
+			return "", nil
+		}
+		return "", fmt.Errorf("%w\nOutput: %s", err, string(output))
+	}
+	return lastURL(string(output)), nil
+}
+
+// createGitLabMR opens a merge request with the GitLab CLI (glab) when it is
+// installed, or else through the GitLab API with GITLAB_TOKEN. With neither
+// the request is skipped with a warning.
+func createGitLabMR(dir string, req changeRequest) (string, error) {
+	if _, err := exec.LookPath("glab"); err == nil {
+		args := []string{"mr", "create", "--yes", "--title", req.Title, "--description", req.Body, "--source-branch", req.Source}
+		if req.Target != "" {
+			args = append(args, "--target-branch", req.Target)
+		}
+		cmd := exec.Command("glab", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("glab: %w\nOutput: %s", err, string(output))
+		}
+		return lastURL(string(output)), nil
+	}
+
+	api, err := newGitLabAPI(dir)
+	if err != nil {
+		ui.Printf("  ⚠️  GitLab CLI (glab) not installed and %v - skipping MR creation\n", err)
+		fmt.Printf("      Install glab (see https://gitlab.com/gitlab-org/cli) or set GITLAB_TOKEN\n")
+		return "", nil
+	}
+	form := url.Values{
+		"source_branch": {req.Source},
+		"title":         {req.Title},
+		"description":   {req.Body},
+	}
+	if req.Target != "" {
+		form.Set("target_branch", req.Target)
+	}
+	body, err := api.post("merge_requests", form)
+	if err != nil {
+		return "", err
+	}
+	var mr struct {
+		WebURL string `json:"web_url"`
+	}
+	if err := json.Unmarshal(body, &mr); err != nil {
+		return "", fmt.Errorf("unexpected GitLab response: %w", err)
+	}
+	return mr.WebURL, nil
+}
+
+// lastURL returns the last line of CLI output that is a URL, which gh and
+// glab print for the request they created
+func lastURL(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); strings.HasPrefix(line, "https://") || strings.HasPrefix(line, "http://") {
+			return line
+		}
+	}
+	return strings.TrimSpace(output)
+}
@@ -0,0 +1,117 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestDetectForge(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@github.com:acme/app.git", ForgeGitHub},
+		{"https://gitlab.com/group/app.git", ForgeGitLab},
+		{"ssh://git@gitlab.internal.example:2222/group/app.git", ForgeGitLab},
+		{"/srv/git/app.git", ForgeGitHub},
+	}
+	for _, tt := range tests {
+		if got := detectForge(tt.remote); got != tt.want {
+			t.Errorf("detectForge(%q) = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestExpandRequestTemplate(t *testing.T) {
+	task := &config.AgentTask{
+		Context: config.AgentContext{Branch: "main"},
+		Safety:  config.SafetyConfig{Gates: []config.SafetyGate{{Name: "lint"}, {Name: "tests"}}},
+	}
+	e := NewExecutor(&config.Config{}, &config.WorktreeConfig{}, task, "deps")
+	e.failedGates = []string{"tests"}
+
+	got := e.expandRequestTemplate("{task}: {branch} into {target} ({gates})", "automated/deps", e.targetBranch())
+	want := "deps: automated/deps into main (1 of 2 safety gates passed (failed: tests))"
+	if got != want {
+		t.Errorf("expandRequestTemplate() = %q, want %q", got, want)
+	}
+
+	task.Safety.Git.Push.TargetBranch = "develop"
+	if got := e.targetBranch(); got != "develop" {
+		t.Errorf("targetBranch() = %q, want develop", got)
+	}
+}
+
+func TestCreateGitLabMRWithAPI(t *testing.T) {
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"remote", "add", "origin", "git@gitlab.example.com:group/repo.git"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// A PATH with git but without glab
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	if err := os.Symlink(gitPath, filepath.Join(bin, "git")); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+
+	var gotPath string
+	var gotForm map[string][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		_ = r.ParseForm()
+		gotForm = r.PostForm
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"iid": 3, "web_url": "https://gitlab.example.com/group/repo/-/merge_requests/3"}`))
+	}))
+	defer server.Close()
+	t.Setenv("GITLAB_API_URL", server.URL+"/api/v4")
+	t.Setenv("GITLAB_TOKEN", "secret")
+
+	req := changeRequest{Title: "Update", Body: "Body", Source: "automated/deps", Target: "main"}
+	got, err := createGitLabMR(dir, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "https://gitlab.example.com/group/repo/-/merge_requests/3" {
+		t.Errorf("createGitLabMR() = %q", got)
+	}
+	if gotPath != "/api/v4/projects/group%2Frepo/merge_requests" {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotForm["source_branch"][0] != "automated/deps" || gotForm["target_branch"][0] != "main" || gotForm["title"][0] != "Update" {
+		t.Errorf("form = %v", gotForm)
+	}
+
+	// Without glab and a token the request is skipped
+	t.Setenv("GITLAB_TOKEN", "")
+	if got, err := createGitLabMR(dir, req); err != nil || got != "" {
+		t.Errorf("createGitLabMR() without token = %q, %v; want skipped", got, err)
+	}
+}
+
+func TestLastURL(t *testing.T) {
+	output := "Creating merge request for x into main\n\nhttps://gitlab.com/g/r/-/merge_requests/1\n"
+	if got := lastURL(output); got != "https://gitlab.com/g/r/-/merge_requests/1" {
+		t.Errorf("lastURL() = %q", got)
+	}
+	if got := lastURL(" no url "); !strings.Contains(got, "no url") {
+		t.Errorf("lastURL() = %q, want the output", got)
+	}
+}
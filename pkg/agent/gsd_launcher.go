@@ -7,19 +7,22 @@ import (
 	"strings"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/ui"
 )
 
-// GSDWorkflow represents a GSD workflow configuration
+// GSDWorkflow represents one claude invocation of a GSD workflow
 type GSDWorkflow struct {
-	Milestone   string
-	Phase       string
-	AutoExecute bool
-	YoloMode    bool
+	Milestone    string
+	NewMilestone bool   // Start the milestone with /gsd:new-milestone (first phase of a run)
+	Phase        string // Prompt for /gsd:plan-phase
+	AutoExecute  bool
+	YoloMode     bool
+	Env          []string // Environment for claude (nil inherits the caller's)
 }
 
-// LaunchGSDWorkflow starts a GSD workflow with task content
+// LaunchGSDWorkflow runs claude with the GSD command sequence of one phase
 func LaunchGSDWorkflow(cfg *config.Config, workflow GSDWorkflow) error {
-	fmt.Printf("🔄 Launching GSD Workflow\n")
+	ui.Printf("🔄 Launching GSD Workflow\n")
 	fmt.Printf("   Milestone: %s\n", workflow.Milestone)
 	if workflow.AutoExecute {
 		fmt.Printf("   Auto-execute: enabled\n")
@@ -40,8 +43,10 @@ func LaunchGSDWorkflow(cfg *config.Config, workflow GSDWorkflow) error {
 	// Build GSD command sequence
 	var commands []string
 
-	// Start with new milestone
-	commands = append(commands, fmt.Sprintf("/gsd:new-milestone \"%s\"", workflow.Milestone))
+	// Start the milestone before its first phase
+	if workflow.NewMilestone {
+		commands = append(commands, fmt.Sprintf("/gsd:new-milestone \"%s\"", workflow.Milestone))
+	}
 
 	// Add plan-phase command with task content
 	if workflow.Phase != "" {
@@ -70,6 +75,9 @@ func LaunchGSDWorkflow(cfg *config.Config, workflow GSDWorkflow) error {
 
 	// Set environment for YOLO mode
 	env := os.Environ()
+	if workflow.Env != nil {
+		env = append([]string{}, workflow.Env...)
+	}
 	if workflow.YoloMode {
 		env = append(env, "CLAUDE_DANGEROUSLY_SKIP_PERMISSIONS=1")
 	}
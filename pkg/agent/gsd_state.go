@@ -0,0 +1,227 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// GSD phase statuses
+const (
+	GSDPhasePending   = "pending"
+	GSDPhaseRunning   = "running"
+	GSDPhaseCompleted = "completed"
+	GSDPhaseFailed    = "failed"
+)
+
+// GSDStateDir is where milestone progress is kept, relative to the worktrees directory
+const GSDStateDir = ".gsd"
+
+// GSDState is the persisted progress of an agent's GSD milestone. A run
+// resumes at the first phase that has not completed.
+type GSDState struct {
+	Agent            string          `json:"agent"`
+	Worktree         string          `json:"worktree,omitempty"` // Feature the run was queued for (empty for direct runs)
+	Milestone        string          `json:"milestone"`
+	MilestoneStarted bool            `json:"milestone_started"` // /gsd:new-milestone has run
+	Phases           []GSDPhaseState `json:"phases"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+
+	path string
+}
+
+// GSDPhaseState is the progress of one phase
+type GSDPhaseState struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	FailedGates []string   `json:"failed_gates,omitempty"`
+}
+
+// GSDStatePath returns the state file of an agent's milestone:
+// worktrees/.gsd/<agent>.json, or <agent>@<worktree>.json for queued runs
+func GSDStatePath(worktreeDir, agentName, worktree string) string {
+	name := agentName
+	if worktree != "" {
+		name += "@" + worktree
+	}
+	return filepath.Join(worktreeDir, GSDStateDir, name+".json")
+}
+
+// NewGSDState creates the state of a milestone that has not started yet
+func NewGSDState(path, agentName, worktree, milestone string, phases []string) *GSDState {
+	s := &GSDState{
+		Agent:     agentName,
+		Worktree:  worktree,
+		Milestone: milestone,
+		path:      path,
+	}
+	for _, name := range phases {
+		s.Phases = append(s.Phases, GSDPhaseState{Name: name, Status: GSDPhasePending})
+	}
+	return s
+}
+
+// LoadGSDState reads a state file; it returns nil if there is none
+func LoadGSDState(path string) (*GSDState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read GSD state: %w", err)
+	}
+
+	var s GSDState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse GSD state %s: %w", path, err)
+	}
+	s.path = path
+	return &s, nil
+}
+
+// ListGSDStates loads every state file, sorted by agent and worktree
+func ListGSDStates(worktreeDir string) ([]*GSDState, error) {
+	paths, err := filepath.Glob(filepath.Join(worktreeDir, GSDStateDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*GSDState
+	for _, path := range paths {
+		s, err := LoadGSDState(path)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			states = append(states, s)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Agent != states[j].Agent {
+			return states[i].Agent < states[j].Agent
+		}
+		return states[i].Worktree < states[j].Worktree
+	})
+	return states, nil
+}
+
+// Path returns the state file
+func (s *GSDState) Path() string {
+	return s.path
+}
+
+// Save persists the state atomically
+func (s *GSDState) Save() error {
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal GSD state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create GSD state directory: %w", err)
+	}
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write temp GSD state file: %w", err)
+	}
+	if err := os.Rename(tempPath, s.path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to rename GSD state file: %w", err)
+	}
+	return nil
+}
+
+// Matches reports whether the state belongs to the milestone and phase list,
+// so a changed configuration starts over instead of resuming
+func (s *GSDState) Matches(milestone string, phases []string) bool {
+	if s.Milestone != milestone || len(s.Phases) != len(phases) {
+		return false
+	}
+	for i, name := range phases {
+		if s.Phases[i].Name != name {
+			return false
+		}
+	}
+	return true
+}
+
+// NextPhase returns the index of the first phase that has not completed,
+// or -1 when the milestone is done
+func (s *GSDState) NextPhase() int {
+	for i, phase := range s.Phases {
+		if phase.Status != GSDPhaseCompleted {
+			return i
+		}
+	}
+	return -1
+}
+
+// Completed returns the number of completed phases
+func (s *GSDState) Completed() int {
+	n := 0
+	for _, phase := range s.Phases {
+		if phase.Status == GSDPhaseCompleted {
+			n++
+		}
+	}
+	return n
+}
+
+// Done reports whether every phase has completed
+func (s *GSDState) Done() bool {
+	return s.NextPhase() == -1
+}
+
+// Status summarizes the milestone: "completed", "failed", "running",
+// "in progress" or "pending"
+func (s *GSDState) Status() string {
+	if s.Done() {
+		return GSDPhaseCompleted
+	}
+	for _, phase := range s.Phases {
+		if phase.Status == GSDPhaseFailed || phase.Status == GSDPhaseRunning {
+			return phase.Status
+		}
+	}
+	if s.Completed() > 0 {
+		return "in progress"
+	}
+	return GSDPhasePending
+}
+
+// StartPhase marks a phase as running
+func (s *GSDState) StartPhase(i int) {
+	now := time.Now()
+	s.Phases[i] = GSDPhaseState{Name: s.Phases[i].Name, Status: GSDPhaseRunning, StartedAt: &now}
+}
+
+// CompletePhase marks a phase as completed
+func (s *GSDState) CompletePhase(i int) {
+	now := time.Now()
+	s.Phases[i].Status = GSDPhaseCompleted
+	s.Phases[i].CompletedAt = &now
+}
+
+// FailPhase marks a phase as failed with the error and the gates that failed
+func (s *GSDState) FailPhase(i int, err error, failedGates []string) {
+	now := time.Now()
+	s.Phases[i].Status = GSDPhaseFailed
+	s.Phases[i].CompletedAt = &now
+	s.Phases[i].Error = err.Error()
+	s.Phases[i].FailedGates = failedGates
+}
+
+// Label names the run the state belongs to: the agent, and the worktree for queued runs
+func (s *GSDState) Label() string {
+	if s.Worktree == "" {
+		return s.Agent
+	}
+	return s.Agent + " @ " + s.Worktree
+}
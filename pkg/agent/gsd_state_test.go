@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestGSDStatePath(t *testing.T) {
+	dir := "/tmp/worktrees"
+	if got := GSDStatePath(dir, "planner", ""); got != filepath.Join(dir, ".gsd", "planner.json") {
+		t.Errorf("GSDStatePath = %q", got)
+	}
+	if got := GSDStatePath(dir, "planner", "feature-auth"); got != filepath.Join(dir, ".gsd", "planner@feature-auth.json") {
+		t.Errorf("GSDStatePath with worktree = %q", got)
+	}
+}
+
+func TestGSDStateProgress(t *testing.T) {
+	dir := t.TempDir()
+	phases := []string{"research", "implement", "verify"}
+	state := NewGSDState(GSDStatePath(dir, "planner", ""), "planner", "", "v1", phases)
+
+	if state.NextPhase() != 0 || state.Status() != GSDPhasePending {
+		t.Fatalf("new state: next %d, status %q", state.NextPhase(), state.Status())
+	}
+
+	state.StartPhase(0)
+	state.CompletePhase(0)
+	state.MilestoneStarted = true
+	state.StartPhase(1)
+	state.FailPhase(1, errors.New("gates failed"), []string{"tests"})
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadGSDState(state.Path())
+	if err != nil || loaded == nil {
+		t.Fatalf("LoadGSDState = %v, %v", loaded, err)
+	}
+	if !loaded.Matches("v1", phases) {
+		t.Error("loaded state should match its milestone and phases")
+	}
+	if loaded.Matches("v2", phases) || loaded.Matches("v1", phases[:2]) || loaded.Matches("v1", []string{"research", "build", "verify"}) {
+		t.Error("state should not match a changed milestone or phase list")
+	}
+	if loaded.NextPhase() != 1 || loaded.Completed() != 1 || loaded.Done() {
+		t.Errorf("next %d, completed %d, done %v; want resume at phase 1", loaded.NextPhase(), loaded.Completed(), loaded.Done())
+	}
+	if loaded.Status() != GSDPhaseFailed || !loaded.MilestoneStarted {
+		t.Errorf("status %q, milestone started %v", loaded.Status(), loaded.MilestoneStarted)
+	}
+	if p := loaded.Phases[1]; p.Error != "gates failed" || len(p.FailedGates) != 1 || p.CompletedAt == nil {
+		t.Errorf("failed phase = %+v", p)
+	}
+
+	// Retrying a phase clears its previous failure
+	loaded.StartPhase(1)
+	if p := loaded.Phases[1]; p.Status != GSDPhaseRunning || p.Error != "" || p.CompletedAt != nil {
+		t.Errorf("restarted phase = %+v", p)
+	}
+	loaded.CompletePhase(1)
+	loaded.StartPhase(2)
+	loaded.CompletePhase(2)
+	if !loaded.Done() || loaded.Status() != GSDPhaseCompleted {
+		t.Errorf("done %v, status %q", loaded.Done(), loaded.Status())
+	}
+}
+
+func TestListGSDStates(t *testing.T) {
+	dir := t.TempDir()
+
+	states, err := ListGSDStates(dir)
+	if err != nil || len(states) != 0 {
+		t.Fatalf("ListGSDStates without states = %v, %v", states, err)
+	}
+	missing, err := LoadGSDState(GSDStatePath(dir, "planner", ""))
+	if err != nil || missing != nil {
+		t.Fatalf("LoadGSDState of a missing file = %v, %v", missing, err)
+	}
+
+	for _, s := range []*GSDState{
+		NewGSDState(GSDStatePath(dir, "reviewer", ""), "reviewer", "", "m", []string{"a"}),
+		NewGSDState(GSDStatePath(dir, "planner", "feature-b"), "planner", "feature-b", "m", []string{"a"}),
+		NewGSDState(GSDStatePath(dir, "planner", ""), "planner", "", "m", []string{"a"}),
+	} {
+		if err := s.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	states, err = ListGSDStates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, s := range states {
+		labels = append(labels, s.Label())
+	}
+	want := []string{"planner", "planner @ feature-b", "reviewer"}
+	if len(labels) != len(want) {
+		t.Fatalf("labels = %v, want %v", labels, want)
+	}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("labels = %v, want %v", labels, want)
+		}
+	}
+}
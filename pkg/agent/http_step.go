@@ -0,0 +1,203 @@
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+// HTTPStepTimeout bounds each http step request unless the step sets a timeout
+const HTTPStepTimeout = 60 * time.Second
+
+// httpStepMaxBody caps how much of a response is read and captured
+const httpStepMaxBody = 1 << 20
+
+// httpStepMethods lists the request methods http steps accept
+var httpStepMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// HTTPStepMethod returns the step's request method, GET by default
+func HTTPStepMethod(step config.AgentStep) string {
+	if step.Method == "" {
+		return http.MethodGet
+	}
+	return strings.ToUpper(step.Method)
+}
+
+// ValidateHTTPStep checks an http step's URL, method, expected status and capture
+func ValidateHTTPStep(step config.AgentStep) error {
+	switch {
+	case step.URL == "":
+		return fmt.Errorf("url is empty")
+	case !slices.Contains(httpStepMethods, HTTPStepMethod(step)):
+		return fmt.Errorf("invalid method '%s' (must be one of %s)", step.Method, strings.Join(httpStepMethods, ", "))
+	case step.ExpectStatus != 0 && (step.ExpectStatus < 100 || step.ExpectStatus > 599):
+		return fmt.Errorf("invalid expect_status %d", step.ExpectStatus)
+	case step.Capture != "" && !config.ValidEnvName(step.Capture):
+		return fmt.Errorf("invalid capture variable name '%s'", step.Capture)
+	case step.CaptureJSON != "" && step.Capture == "":
+		return fmt.Errorf("capture_json needs a capture variable")
+	}
+	return nil
+}
+
+// NewHTTPStepRequest builds the step's request, expanding $VAR and ${VAR} in
+// the URL, headers and body from the step env
+func NewHTTPStepRequest(step config.AgentStep, env []string) (*http.Request, error) {
+	expand := func(s string) string {
+		return os.Expand(s, func(key string) string { return lookupEnv(env, key) })
+	}
+
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(expand(step.Body))
+	}
+	req, err := http.NewRequest(HTTPStepMethod(step), expand(step.URL), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "worktree-agent")
+	for key, value := range step.Headers {
+		req.Header.Set(key, expand(value))
+	}
+	return req, nil
+}
+
+// executeHTTPStep sends the step's request, checks the response status and
+// captures the response (or one of its JSON fields) into a variable that
+// later steps and the safety gates see
+func (e *Executor) executeHTTPStep(step config.AgentStep, env []string) error {
+	if err := ValidateHTTPStep(step); err != nil {
+		return err
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	req, err := NewHTTPStepRequest(step, env)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("      Request: %s %s\n", req.Method, step.URL)
+
+	timeout := step.GetTimeout()
+	if timeout == 0 {
+		timeout = HTTPStepTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, httpStepMaxBody))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	fmt.Printf("      Response: %s\n", resp.Status)
+
+	if !httpStatusOK(step, resp.StatusCode) {
+		want := "a 2xx status"
+		if step.ExpectStatus != 0 {
+			want = strconv.Itoa(step.ExpectStatus)
+		}
+		return fmt.Errorf("unexpected status %s (want %s): %s", resp.Status, want, responseSnippet(body))
+	}
+
+	if step.Capture == "" {
+		return nil
+	}
+	value := strings.TrimSpace(string(body))
+	if step.CaptureJSON != "" {
+		if value, err = CaptureJSONField(body, step.CaptureJSON); err != nil {
+			return err
+		}
+	}
+	e.setVar(step.Capture, value)
+	fmt.Printf("      Captured: %s\n", step.Capture)
+	return nil
+}
+
+// httpStatusOK reports whether a response status is the one the step expects
+func httpStatusOK(step config.AgentStep, status int) bool {
+	if step.ExpectStatus != 0 {
+		return status == step.ExpectStatus
+	}
+	return status >= 200 && status < 300
+}
+
+// responseSnippet returns the start of a response body for error messages
+func responseSnippet(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return s
+}
+
+// CaptureJSONField returns the field of a JSON document at a dotted path
+// (array elements by index, e.g. "data.items.0.id"). Strings are returned as
+// is, other values as JSON.
+func CaptureJSONField(body []byte, path string) (string, error) {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			field, ok := v[key]
+			if !ok {
+				return "", fmt.Errorf("field '%s' not found in response", path)
+			}
+			value = field
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("field '%s' not found in response", path)
+			}
+			value = v[i]
+		default:
+			return "", fmt.Errorf("field '%s' not found in response", path)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// setVar adds a variable to the environment of the remaining steps and the
+// safety gates
+func (e *Executor) setVar(key, value string) {
+	base := e.env
+	if base == nil {
+		base = os.Environ()
+	}
+	e.env = append(append([]string{}, base...), key+"="+value)
+}
+
+// lookupEnv returns the value of a key in a KEY=VALUE list; later entries win
+func lookupEnv(env []string, key string) string {
+	for i := len(env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
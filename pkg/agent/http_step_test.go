@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestValidateHTTPStep(t *testing.T) {
+	valid := config.AgentStep{Type: "http", URL: "https://deploy.internal/api", Method: "post", Capture: "DEPLOY_ID", CaptureJSON: "id"}
+	if err := ValidateHTTPStep(valid); err != nil {
+		t.Errorf("valid step: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*config.AgentStep)
+		wantErr string
+	}{
+		{"no url", func(s *config.AgentStep) { s.URL = "" }, "url is empty"},
+		{"bad method", func(s *config.AgentStep) { s.Method = "FETCH" }, "invalid method 'FETCH'"},
+		{"bad status", func(s *config.AgentStep) { s.ExpectStatus = 42 }, "invalid expect_status 42"},
+		{"bad capture", func(s *config.AgentStep) { s.Capture = "deploy-id" }, "invalid capture variable name"},
+		{"json without capture", func(s *config.AgentStep) { s.Capture = "" }, "capture_json needs a capture variable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := valid
+			tt.modify(&step)
+			err := ValidateHTTPStep(step)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateHTTPStep = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewHTTPStepRequest(t *testing.T) {
+	step := config.AgentStep{
+		Method:  "post",
+		URL:     "https://api.internal/deploys/${ENV_NAME}",
+		Headers: map[string]string{"Authorization": "Bearer $TOKEN"},
+		Body:    `{"ref":"${REF}"}`,
+	}
+	env := []string{"ENV_NAME=old", "TOKEN=s3cret", "ENV_NAME=staging", "REF=main"}
+
+	req, err := NewHTTPStepRequest(step, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "POST" || req.URL.String() != "https://api.internal/deploys/staging" {
+		t.Errorf("request = %s %s", req.Method, req.URL)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", got)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"ref":"main"}` {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestCaptureJSONField(t *testing.T) {
+	body := []byte(`{"id":"d-42","build":{"number":7,"tags":["a","b"]},"ok":true}`)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"id", "d-42"},
+		{"build.number", "7"},
+		{"build.tags.1", "b"},
+		{"build.tags", `["a","b"]`},
+		{"ok", "true"},
+	}
+	for _, tt := range tests {
+		got, err := CaptureJSONField(body, tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("CaptureJSONField(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+
+	for _, path := range []string{"missing", "build.tags.5", "id.x"} {
+		if _, err := CaptureJSONField(body, path); err == nil {
+			t.Errorf("CaptureJSONField(%q) should fail", path)
+		}
+	}
+	if _, err := CaptureJSONField([]byte("not json"), "id"); err == nil {
+		t.Error("CaptureJSONField of a non-JSON body should fail")
+	}
+}
+
+func TestExecuteHTTPStep(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/deploys":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"deploy":{"id":"d-42"}}`))
+		default:
+			http.Error(w, "no such deploy", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	e := &Executor{env: []string{"BASE=" + server.URL}}
+	step := config.AgentStep{Method: "POST", URL: "${BASE}/deploys", ExpectStatus: http.StatusAccepted, Capture: "DEPLOY_ID", CaptureJSON: "deploy.id"}
+	if err := e.executeHTTPStep(step, e.env); err != nil {
+		t.Fatal(err)
+	}
+	if got := lookupEnv(e.env, "DEPLOY_ID"); got != "d-42" {
+		t.Errorf("captured DEPLOY_ID = %q", got)
+	}
+
+	step = config.AgentStep{URL: "${BASE}/deploys/${DEPLOY_ID}"}
+	err := e.executeHTTPStep(step, e.env)
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "no such deploy") {
+		t.Errorf("executeHTTPStep = %v, want 404 error with the response", err)
+	}
+}
@@ -0,0 +1,243 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/process"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// LockDir holds the lock files of running agent runs, relative to the
+// worktrees directory
+const LockDir = ".agent-locks"
+
+// projectRootLock is the lock runs outside an isolated worktree hold, since
+// they check out branches and commit in the project root
+const projectRootLock = "project-root"
+
+// lockPollInterval is how often a run waiting for a lock checks again
+const lockPollInterval = 2 * time.Second
+
+// takeoverDir is created in the lock directory while a run removes a stale
+// lock file, so that only one run at a time takes a slot over
+const takeoverDir = ".takeover"
+
+// takeoverStaleAfter is when a takeoverDir is considered left behind by a run
+// that died during a takeover, which takes milliseconds
+const takeoverStaleAfter = 10 * time.Second
+
+// RunLock is the content of a lock file: the run holding it
+type RunLock struct {
+	Agent   string    `json:"agent"`
+	PID     int       `json:"pid"`
+	Started time.Time `json:"started"`
+	Trigger string    `json:"trigger"`
+}
+
+// String describes the run for "already running" errors
+func (l RunLock) String() string {
+	return fmt.Sprintf("'%s' running since %s (pid %d, %s)", l.Agent, l.Started.Format("2006-01-02 15:04:05"), l.PID, l.Trigger)
+}
+
+// LockError is returned when a run cannot start because other runs hold the
+// locks it needs
+type LockError struct {
+	Reason  string    // Which limit was reached
+	Holders []RunLock // Runs holding the locks
+}
+
+func (e *LockError) Error() string {
+	holders := make([]string, len(e.Holders))
+	for i, h := range e.Holders {
+		holders[i] = h.String()
+	}
+	return fmt.Sprintf("%s: %s", e.Reason, strings.Join(holders, ", "))
+}
+
+// lockSlots is a set of lock files of which a run needs one: name-1.lock up
+// to name-<max>.lock
+type lockSlots struct {
+	name   string
+	max    int
+	reason string // Reported when every slot is taken
+}
+
+// runSlots returns the lock slots a run of the task needs: one of the task's
+// max_concurrent, one of max_concurrent_agents when set, and the project
+// root unless the run is isolated
+func (e *Executor) runSlots() []lockSlots {
+	max := e.task.GetMaxConcurrent()
+	reason := fmt.Sprintf("agent '%s' is already running", e.agentName)
+	if max > 1 {
+		reason = fmt.Sprintf("agent '%s' already has %d runs (max_concurrent)", e.agentName, max)
+	}
+	slots := []lockSlots{{name: "agent-" + e.agentName, max: max, reason: reason}}
+
+	if e.workCfg.MaxConcurrentAgents > 0 {
+		slots = append(slots, lockSlots{
+			name:   "global",
+			max:    e.workCfg.MaxConcurrentAgents,
+			reason: fmt.Sprintf("%d agent runs are already in progress (max_concurrent_agents)", e.workCfg.MaxConcurrentAgents),
+		})
+	}
+	if !e.task.Context.IsolatedWorktree {
+		slots = append(slots, lockSlots{
+			name:   projectRootLock,
+			max:    1,
+			reason: "another agent run is using the project root (set context.isolated_worktree to run agents side by side)",
+		})
+	}
+	return slots
+}
+
+// acquireLocks takes the locks of the run, waiting for them to be released
+// when wait is set, and returns the function releasing them
+func (e *Executor) acquireLocks(wait bool) (func(), error) {
+	dir := filepath.Join(e.cfg.WorktreeDir, LockDir)
+	lock := RunLock{Agent: e.agentName, PID: os.Getpid(), Started: time.Now(), Trigger: e.trigger}
+
+	waiting := false
+	for {
+		paths, err := acquireSlots(dir, e.runSlots(), lock)
+		var lockErr *LockError
+		if err == nil || !wait || !errors.As(err, &lockErr) {
+			return func() { releaseLocks(paths) }, err
+		}
+		if !waiting {
+			ui.Printf("⏳ Waiting: %v\n", err)
+			waiting = true
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// acquireSlots takes a lock in each set of slots, or none at all
+func acquireSlots(dir string, slots []lockSlots, lock RunLock) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	var paths []string
+	for _, s := range slots {
+		path, holders, err := acquireSlot(dir, s, lock)
+		if err == nil && path == "" {
+			err = &LockError{Reason: s.reason, Holders: holders}
+		}
+		if err != nil {
+			releaseLocks(paths)
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// acquireSlot creates the first free lock file of the slots and returns its
+// path, or no path and the runs holding them when all are taken. Lock files
+// of processes that are gone are taken over.
+func acquireSlot(dir string, s lockSlots, lock RunLock) (string, []RunLock, error) {
+	// Lock files are hard links to a complete file, so nobody reads one half written
+	tmp, err := os.CreateTemp(dir, ".lock-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	err = json.NewEncoder(tmp).Encode(lock)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to write lock file: %w", err)
+	}
+
+	var holders []RunLock
+	for i := 1; i <= s.max; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("%s-%d.lock", s.name, i))
+		for attempt := 0; attempt < 2; attempt++ {
+			err := os.Link(tmp.Name(), path)
+			if err == nil {
+				return path, nil, nil
+			}
+			if !os.IsExist(err) {
+				return "", nil, fmt.Errorf("failed to create lock file: %w", err)
+			}
+
+			holder, ok := readLock(path)
+			if ok {
+				holders = append(holders, holder)
+				break
+			}
+			// Stale lock of a run that ended without releasing it
+			if err := takeOver(dir, path); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	return "", holders, nil
+}
+
+// takeOver removes the lock file at path if it still belongs to no running
+// process. Every removal of another run's lock file happens under the
+// directory's takeover lock, so between checking and removing it the stale
+// file cannot be replaced by the live lock of a run that took the slot over.
+func takeOver(dir, path string) error {
+	unlock, err := lockTakeover(dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := readLock(path); ok {
+		return nil // Taken over by another run meanwhile
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stale lock file: %w", err)
+	}
+	return nil
+}
+
+// lockTakeover creates the takeover lock of the lock directory, waiting while
+// another run holds it, and returns the function releasing it
+func lockTakeover(dir string) (func(), error) {
+	path := filepath.Join(dir, takeoverDir)
+	for {
+		err := os.Mkdir(path, 0755)
+		if err == nil {
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", dir, err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > takeoverStaleAfter {
+			os.Remove(path) // Left by a run that died during a takeover
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readLock reads a lock file, reporting false when it does not belong to a
+// running process
+func readLock(path string) (RunLock, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RunLock{}, false
+	}
+	var lock RunLock
+	if err := json.Unmarshal(data, &lock); err != nil || lock.PID <= 0 {
+		return RunLock{}, false
+	}
+	return lock, process.Alive(lock.PID)
+}
+
+// releaseLocks removes lock files
+func releaseLocks(paths []string) {
+	for _, path := range paths {
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+)
+
+// newLockTestExecutor creates an executor for a task of agentName in root
+func newLockTestExecutor(root, agentName string, workCfg *config.WorktreeConfig, task *config.AgentTask) *Executor {
+	cfg := &config.Config{ProjectRoot: root, WorktreeDir: filepath.Join(root, "worktrees")}
+	return NewExecutor(cfg, workCfg, task, agentName)
+}
+
+func TestAcquireLocks(t *testing.T) {
+	root := t.TempDir()
+	workCfg := &config.WorktreeConfig{}
+	isolated := &config.AgentTask{Context: config.AgentContext{IsolatedWorktree: true}}
+	inRoot := &config.AgentTask{}
+
+	// A second run of the same agent fails while the first holds its lock
+	release, err := newLockTestExecutor(root, "deps", workCfg, isolated).acquireLocks(false)
+	if err != nil {
+		t.Fatalf("acquireLocks() error = %v", err)
+	}
+	_, err = newLockTestExecutor(root, "deps", workCfg, isolated).acquireLocks(false)
+	var lockErr *LockError
+	if !errors.As(err, &lockErr) || !strings.Contains(err.Error(), "agent 'deps' is already running: 'deps' running since") {
+		t.Fatalf("acquireLocks() error = %v, want already running", err)
+	}
+
+	// Isolated runs of other agents run side by side, runs in the project root do not
+	other, err := newLockTestExecutor(root, "audit", workCfg, isolated).acquireLocks(false)
+	if err != nil {
+		t.Fatalf("acquireLocks() of another isolated agent error = %v", err)
+	}
+	first, err := newLockTestExecutor(root, "lint", workCfg, inRoot).acquireLocks(false)
+	if err != nil {
+		t.Fatalf("acquireLocks() in the project root error = %v", err)
+	}
+	if _, err := newLockTestExecutor(root, "fmt", workCfg, inRoot).acquireLocks(false); err == nil || !strings.Contains(err.Error(), "using the project root") {
+		t.Errorf("acquireLocks() of a second run in the project root error = %v", err)
+	}
+	first()
+	other()
+
+	// max_concurrent allows several runs of a task
+	release()
+	task := &config.AgentTask{MaxConcurrent: 2, Context: config.AgentContext{IsolatedWorktree: true}}
+	for i := 0; i < 2; i++ {
+		if _, err := newLockTestExecutor(root, "deps", workCfg, task).acquireLocks(false); err != nil {
+			t.Fatalf("run %d: acquireLocks() error = %v", i+1, err)
+		}
+	}
+	if _, err := newLockTestExecutor(root, "deps", workCfg, task).acquireLocks(false); err == nil || !strings.Contains(err.Error(), "already has 2 runs (max_concurrent)") {
+		t.Errorf("acquireLocks() of a third run error = %v", err)
+	}
+}
+
+func TestAcquireLocksGlobalLimit(t *testing.T) {
+	root := t.TempDir()
+	workCfg := &config.WorktreeConfig{MaxConcurrentAgents: 1}
+	task := &config.AgentTask{Context: config.AgentContext{IsolatedWorktree: true}}
+
+	release, err := newLockTestExecutor(root, "deps", workCfg, task).acquireLocks(false)
+	if err != nil {
+		t.Fatalf("acquireLocks() error = %v", err)
+	}
+	_, err = newLockTestExecutor(root, "audit", workCfg, task).acquireLocks(false)
+	if err == nil || !strings.Contains(err.Error(), "(max_concurrent_agents): 'deps' running since") {
+		t.Fatalf("acquireLocks() error = %v, want max_concurrent_agents reached", err)
+	}
+	// The failed run released the agent lock it had taken
+	if _, err := os.Stat(filepath.Join(root, "worktrees", LockDir, "agent-audit-1.lock")); !os.IsNotExist(err) {
+		t.Errorf("agent lock of the failed run left behind: %v", err)
+	}
+
+	// --wait takes the locks once the other run is done
+	first := release
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		first()
+	}()
+	waited, err := newLockTestExecutor(root, "audit", workCfg, task).acquireLocks(true)
+	if err != nil {
+		t.Fatalf("acquireLocks(wait) error = %v", err)
+	}
+	waited()
+}
+
+func TestAcquireLocksTakesOverStaleLock(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "worktrees", LockDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A run killed without releasing its lock: no process has this PID
+	data, _ := json.Marshal(RunLock{Agent: "deps", PID: 1 << 30, Started: time.Now()})
+	if err := os.WriteFile(filepath.Join(dir, "agent-deps-1.lock"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &config.AgentTask{Context: config.AgentContext{IsolatedWorktree: true}}
+	if _, err := newLockTestExecutor(root, "deps", &config.WorktreeConfig{}, task).acquireLocks(false); err != nil {
+		t.Errorf("acquireLocks() error = %v, want the stale lock taken over", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, takeoverDir)); !os.IsNotExist(err) {
+		t.Errorf("takeover lock left behind: %v", err)
+	}
+}
+
+func TestAcquireSlotConcurrentTakeover(t *testing.T) {
+	dir := t.TempDir()
+	stale, _ := json.Marshal(RunLock{Agent: "deps", PID: 1 << 30, Started: time.Now()})
+	path := filepath.Join(dir, "agent-deps-1.lock")
+
+	for round := 0; round < 20; round++ {
+		if err := os.WriteFile(path, stale, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Runs taking the stale slot over at once never both get it
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var acquired []string
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, _, err := acquireSlot(dir, lockSlots{name: "agent-deps", max: 1}, RunLock{Agent: "deps", PID: os.Getpid()})
+				if err != nil {
+					t.Errorf("acquireSlot() error = %v", err)
+				}
+				if got != "" {
+					mu.Lock()
+					acquired = append(acquired, got)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		if len(acquired) != 1 {
+			t.Fatalf("round %d: %d runs acquired the slot, want 1", round, len(acquired))
+		}
+		os.Remove(path)
+	}
+}
+
+func TestTakeOverKeepsLiveLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "agent-deps-1.lock")
+	live, _ := json.Marshal(RunLock{Agent: "deps", PID: os.Getpid(), Started: time.Now()})
+	if err := os.WriteFile(path, live, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := takeOver(dir, path); err != nil {
+		t.Fatalf("takeOver() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("live lock removed: %v", err)
+	}
+}
+
+func TestRunRecordsSkippedRun(t *testing.T) {
+	root := t.TempDir()
+	task := &config.AgentTask{Name: "Deps"}
+	release, err := newLockTestExecutor(root, "deps", &config.WorktreeConfig{}, task).acquireLocks(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if err := newLockTestExecutor(root, "deps", &config.WorktreeConfig{}, task).Run(); err == nil {
+		t.Fatal("Run() succeeded while another run held the lock")
+	}
+	h, err := history.Load(filepath.Join(root, "worktrees"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Records) != 1 || h.Records[0].Status != history.RunSkipped || h.Records[0].Trigger != history.TriggerManual {
+		t.Errorf("history records = %+v, want one skipped manual run", h.Records)
+	}
+}
@@ -0,0 +1,453 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OS scheduler backends supported by "worktree agent schedule"
+const (
+	ScheduleBackendCron    = "cron"
+	ScheduleBackendLaunchd = "launchd"
+)
+
+// ScheduleResult describes what installing or removing an entry did (or would do)
+type ScheduleResult string
+
+const (
+	ScheduleCreated   ScheduleResult = "created"
+	ScheduleUpdated   ScheduleResult = "updated"
+	ScheduleUnchanged ScheduleResult = "unchanged"
+	ScheduleRemoved   ScheduleResult = "removed"
+	ScheduleNotFound  ScheduleResult = "not found"
+)
+
+// maxLaunchdIntervals caps how many StartCalendarInterval entries a cron expression may expand to
+const maxLaunchdIntervals = 500
+
+// ScheduleEntry is the generated OS scheduler entry for one agent task
+type ScheduleEntry struct {
+	Backend  string // cron or launchd
+	TaskName string
+	Label    string // Unique per project and task: com.worktree.<project>.<task>
+	Path     string // Plist path for launchd, empty for cron
+	Content  string // Plist XML (launchd) or crontab block including BEGIN/END markers (cron)
+}
+
+// DefaultScheduleBackend returns launchd on macOS and cron everywhere else
+func DefaultScheduleBackend() string {
+	if runtime.GOOS == "darwin" {
+		return ScheduleBackendLaunchd
+	}
+	return ScheduleBackendCron
+}
+
+// ScheduleLabel returns the identifier used for a task's scheduler entry
+func ScheduleLabel(projectName, taskName string) string {
+	unsafe := regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+	return fmt.Sprintf("com.worktree.%s.%s",
+		unsafe.ReplaceAllString(projectName, "-"), unsafe.ReplaceAllString(taskName, "-"))
+}
+
+// BuildScheduleEntry generates the scheduler entry that runs
+// "<binary> agent run <task>" from projectRoot on the task's cron schedule
+func BuildScheduleEntry(backend, projectName, projectRoot, binary, taskName string, task *config.AgentTask) (*ScheduleEntry, error) {
+	if task.Schedule == "" {
+		return nil, fmt.Errorf("task '%s' has no schedule", taskName)
+	}
+	if _, err := cron.ParseStandard(task.Schedule); err != nil {
+		return nil, fmt.Errorf("task '%s': invalid schedule '%s': %w", taskName, task.Schedule, err)
+	}
+
+	entry := &ScheduleEntry{
+		Backend:  backend,
+		TaskName: taskName,
+		Label:    ScheduleLabel(projectName, taskName),
+	}
+	logPath := filepath.Join(os.Getenv("HOME"), "logs", fmt.Sprintf("worktree-agent-%s.log", taskName))
+
+	switch backend {
+	case ScheduleBackendCron:
+		command := fmt.Sprintf("cd %s && PATH=%s %s agent run %s >> %s 2>&1",
+			shellQuote(projectRoot), shellQuote(os.Getenv("PATH")), shellQuote(binary),
+			shellQuote(taskName), shellQuote(logPath))
+		entry.Content = fmt.Sprintf("%s\n%s %s\n%s\n",
+			cronBeginMarker(entry.Label), task.Schedule, command, cronEndMarker(entry.Label))
+
+	case ScheduleBackendLaunchd:
+		intervals, err := launchdIntervals(task.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': %w", taskName, err)
+		}
+		entry.Path = filepath.Join(os.Getenv("HOME"), "Library", "LaunchAgents", entry.Label+".plist")
+		entry.Content = buildPlist(entry.Label, projectRoot, binary, taskName, logPath, intervals)
+
+	default:
+		return nil, fmt.Errorf("unknown schedule backend '%s' (expected cron or launchd)", backend)
+	}
+
+	return entry, nil
+}
+
+// ApplyScheduleEntry installs entry, replacing an existing entry for the same task
+// in place. Re-running with an unchanged entry is a no-op. With dryRun nothing is
+// written; the result reports what would happen.
+func ApplyScheduleEntry(entry *ScheduleEntry, dryRun bool) (ScheduleResult, error) {
+	switch entry.Backend {
+	case ScheduleBackendCron:
+		current, err := readCrontab()
+		if err != nil {
+			return "", err
+		}
+		updated, existed := upsertCronBlock(current, entry.Label, entry.Content)
+		if updated == current {
+			return ScheduleUnchanged, nil
+		}
+		result := ScheduleCreated
+		if existed {
+			result = ScheduleUpdated
+		}
+		if dryRun {
+			return result, nil
+		}
+		if err := ensureLogDir(); err != nil {
+			return "", err
+		}
+		return result, writeCrontab(updated)
+
+	case ScheduleBackendLaunchd:
+		result := ScheduleCreated
+		if existing, err := os.ReadFile(entry.Path); err == nil {
+			if string(existing) == entry.Content {
+				return ScheduleUnchanged, nil
+			}
+			result = ScheduleUpdated
+		}
+		if dryRun {
+			return result, nil
+		}
+		if err := ensureLogDir(); err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(filepath.Dir(entry.Path), 0755); err != nil {
+			return "", fmt.Errorf("failed to create LaunchAgents directory: %w", err)
+		}
+		if result == ScheduleUpdated {
+			// Unload the old definition so launchd picks up the new schedule
+			exec.Command("launchctl", "unload", entry.Path).Run()
+		}
+		if err := os.WriteFile(entry.Path, []byte(entry.Content), 0644); err != nil {
+			return "", fmt.Errorf("failed to write plist: %w", err)
+		}
+		if output, err := exec.Command("launchctl", "load", entry.Path).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("launchctl load failed: %w\n%s", err, output)
+		}
+		return result, nil
+	}
+
+	return "", fmt.Errorf("unknown schedule backend '%s'", entry.Backend)
+}
+
+// RemoveScheduleEntry deletes the scheduler entry generated for a task.
+// It works from the label alone, so tasks already deleted from .worktree.yml can be cleaned up.
+func RemoveScheduleEntry(backend, projectName, taskName string, dryRun bool) (ScheduleResult, error) {
+	label := ScheduleLabel(projectName, taskName)
+
+	switch backend {
+	case ScheduleBackendCron:
+		current, err := readCrontab()
+		if err != nil {
+			return "", err
+		}
+		updated, existed := upsertCronBlock(current, label, "")
+		if !existed {
+			return ScheduleNotFound, nil
+		}
+		if dryRun {
+			return ScheduleRemoved, nil
+		}
+		return ScheduleRemoved, writeCrontab(updated)
+
+	case ScheduleBackendLaunchd:
+		path := filepath.Join(os.Getenv("HOME"), "Library", "LaunchAgents", label+".plist")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return ScheduleNotFound, nil
+		}
+		if dryRun {
+			return ScheduleRemoved, nil
+		}
+		exec.Command("launchctl", "unload", path).Run()
+		if err := os.Remove(path); err != nil {
+			return "", fmt.Errorf("failed to remove plist: %w", err)
+		}
+		return ScheduleRemoved, nil
+	}
+
+	return "", fmt.Errorf("unknown schedule backend '%s' (expected cron or launchd)", backend)
+}
+
+func cronBeginMarker(label string) string { return "# BEGIN " + label }
+func cronEndMarker(label string) string   { return "# END " + label }
+
+// upsertCronBlock replaces the BEGIN/END block for label with block (an empty block
+// removes it), or appends block when no block exists. Returns the new crontab and
+// whether a block for label was present.
+func upsertCronBlock(crontab, label, block string) (string, bool) {
+	begin, end := cronBeginMarker(label), cronEndMarker(label)
+
+	var out []string
+	existed, inBlock := false, false
+	for _, line := range strings.Split(strings.TrimRight(crontab, "\n"), "\n") {
+		switch {
+		case line == begin:
+			inBlock = true
+			if !existed && block != "" {
+				out = append(out, strings.TrimRight(block, "\n"))
+			}
+			existed = true
+		case line == end && inBlock:
+			inBlock = false
+		case !inBlock:
+			out = append(out, line)
+		}
+	}
+
+	if !existed && block != "" {
+		out = append(out, strings.TrimRight(block, "\n"))
+	}
+
+	result := strings.TrimLeft(strings.Join(out, "\n"), "\n")
+	if result != "" {
+		result += "\n"
+	}
+	return result, existed
+}
+
+// readCrontab returns the current user's crontab; a missing crontab is empty
+func readCrontab() (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("crontab", "-l")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if strings.Contains(strings.ToLower(stderr.String()), "no crontab") {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read crontab: %w\n%s", err, stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// writeCrontab replaces the current user's crontab
+func writeCrontab(content string) error {
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(content)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write crontab: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// ensureLogDir creates ~/logs, where scheduled runs append their output
+func ensureLogDir() error {
+	if err := os.MkdirAll(filepath.Join(os.Getenv("HOME"), "logs"), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes s for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// cronField describes one field of a standard 5-field cron expression and its launchd key
+type cronField struct {
+	key   string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var cronFields = []cronField{
+	{key: "Minute", min: 0, max: 59},
+	{key: "Hour", min: 0, max: 23},
+	{key: "Day", min: 1, max: 31},
+	{key: "Month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}},
+	{key: "Weekday", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}},
+}
+
+// launchdIntervals expands a cron expression into StartCalendarInterval dictionaries.
+// Wildcard fields are omitted; lists, ranges and steps become one dictionary per combination.
+func launchdIntervals(schedule string) ([]map[string]int, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("launchd needs a 5-field cron expression, got '%s'", schedule)
+	}
+
+	intervals := []map[string]int{{}}
+	restricted := make(map[string]bool)
+	for i, field := range cronFields {
+		values, err := expandCronField(fields[i], field)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			continue
+		}
+		restricted[field.key] = true
+
+		var next []map[string]int
+		for _, interval := range intervals {
+			for _, v := range values {
+				combined := make(map[string]int, len(interval)+1)
+				for k, val := range interval {
+					combined[k] = val
+				}
+				combined[field.key] = v
+				next = append(next, combined)
+			}
+		}
+		intervals = next
+		if len(intervals) > maxLaunchdIntervals {
+			return nil, fmt.Errorf("schedule '%s' expands to too many launchd intervals; use 'worktree agent daemon' instead", schedule)
+		}
+	}
+
+	// cron runs when EITHER day-of-month or weekday matches; launchd requires both
+	if restricted["Day"] && restricted["Weekday"] {
+		return nil, fmt.Errorf("schedule '%s' restricts both day of month and weekday, which launchd cannot express; use 'worktree agent daemon' instead", schedule)
+	}
+
+	return intervals, nil
+}
+
+// expandCronField returns the values matched by a cron field, or nil for "*"
+func expandCronField(expr string, field cronField) ([]int, error) {
+	if expr == "*" || expr == "?" {
+		return nil, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(expr, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			s, err := strconv.Atoi(stepPart)
+			if err != nil || s < 1 {
+				return nil, fmt.Errorf("invalid step '%s' in cron field '%s'", stepPart, expr)
+			}
+			step = s
+		}
+
+		lo, hi := field.min, field.max
+		if rangePart != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = cronValue(loStr, field); err != nil {
+				return nil, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = cronValue(hiStr, field); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				hi = field.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			if field.key == "Weekday" && v == 7 {
+				v = 0 // Sunday may be written as 7
+				seen[v] = true
+				break
+			}
+			seen[v] = true
+		}
+	}
+
+	values := make([]int, 0, len(seen))
+	for v := range seen {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// cronValue parses a single cron value (number or month/weekday name)
+func cronValue(s string, field cronField) (int, error) {
+	if v, ok := field.names[strings.ToUpper(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < field.min || v > field.max {
+		return 0, fmt.Errorf("invalid %s value '%s'", strings.ToLower(field.key), s)
+	}
+	return v, nil
+}
+
+// buildPlist renders a launchd agent definition
+func buildPlist(label, projectRoot, binary, taskName, logPath string, intervals []map[string]int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	writePlistString(&b, "Label", label)
+	b.WriteString("  <key>ProgramArguments</key>\n  <array>\n")
+	for _, arg := range []string{binary, "agent", "run", taskName} {
+		fmt.Fprintf(&b, "    <string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("  </array>\n")
+	writePlistString(&b, "WorkingDirectory", projectRoot)
+	b.WriteString("  <key>EnvironmentVariables</key>\n  <dict>\n")
+	fmt.Fprintf(&b, "    <key>PATH</key>\n    <string>%s</string>\n", xmlEscape(os.Getenv("PATH")))
+	b.WriteString("  </dict>\n")
+
+	b.WriteString("  <key>StartCalendarInterval</key>\n  <array>\n")
+	for _, interval := range intervals {
+		b.WriteString("    <dict>\n")
+		for _, field := range cronFields {
+			if v, ok := interval[field.key]; ok {
+				fmt.Fprintf(&b, "      <key>%s</key>\n      <integer>%d</integer>\n", field.key, v)
+			}
+		}
+		b.WriteString("    </dict>\n")
+	}
+	b.WriteString("  </array>\n")
+
+	writePlistString(&b, "StandardOutPath", logPath)
+	writePlistString(&b, "StandardErrorPath", logPath)
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+func writePlistString(b *strings.Builder, key, value string) {
+	fmt.Fprintf(b, "  <key>%s</key>\n  <string>%s</string>\n", key, xmlEscape(value))
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/process"
+)
+
+// PipelineDir is the shared library of pipeline step scripts, relative to the
+// project root. Each <name>.sh in it is a step usable as "pipeline: <name>".
+const PipelineDir = ".worktree/steps"
+
+// PipelineScript returns the script of a pipeline step, or an error naming the
+// available steps when it does not exist
+func PipelineScript(projectRoot, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("pipeline is empty")
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid pipeline '%s': use the script name without directory or .sh", name)
+	}
+
+	path := filepath.Join(projectRoot, PipelineDir, strings.TrimSuffix(name, ".sh")+".sh")
+	info, err := os.Stat(path)
+	if err == nil && info.Mode().IsRegular() {
+		return path, nil
+	}
+
+	msg := fmt.Sprintf("pipeline '%s' not found (expected %s/%s.sh)", name, PipelineDir, strings.TrimSuffix(name, ".sh"))
+	if available := PipelineSteps(projectRoot); len(available) > 0 {
+		msg += fmt.Sprintf("; available: %s", strings.Join(available, ", "))
+	}
+	return "", fmt.Errorf("%s", msg)
+}
+
+// PipelineSteps returns the names of the scripts in the pipeline library, sorted
+func PipelineSteps(projectRoot string) []string {
+	matches, _ := filepath.Glob(filepath.Join(projectRoot, PipelineDir, "*.sh"))
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(match), ".sh"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// executePipelineStep runs a script from the pipeline library with bash and
+// the step's arguments, in the project root (the run worktree of an isolated
+// run) unless working_dir is set
+func (e *Executor) executePipelineStep(step config.AgentStep, env []string) error {
+	script, err := PipelineScript(e.cfg.ProjectRoot, step.Pipeline)
+	if err != nil {
+		return err
+	}
+
+	rel, _ := filepath.Rel(e.cfg.ProjectRoot, script)
+	fmt.Printf("      Pipeline: %s %s\n", rel, strings.Join(step.PipelineArgs, " "))
+
+	cmd := exec.Command("bash", append([]string{script}, step.PipelineArgs...)...)
+	cmd.Dir = e.stepDir(step, e.workDir())
+	e.connectOutput(cmd)
+	cmd.Env = env
+
+	return process.RunCommand(cmd, step.GetTimeout())
+}
@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPipelineScript(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, PipelineDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"lint.sh", "go-upgrade.sh", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("echo ok\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := PipelineSteps(root); strings.Join(got, ",") != "go-upgrade,lint" {
+		t.Errorf("PipelineSteps = %v, want [go-upgrade lint]", got)
+	}
+
+	for _, name := range []string{"lint", "lint.sh"} {
+		path, err := PipelineScript(root, name)
+		if err != nil || path != filepath.Join(dir, "lint.sh") {
+			t.Errorf("PipelineScript(%q) = %q, %v", name, path, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		wantErr string
+	}{
+		{"", "pipeline is empty"},
+		{"../lint", "invalid pipeline"},
+		{"notes", "available: go-upgrade, lint"},
+	}
+	for _, tt := range tests {
+		if _, err := PipelineScript(root, tt.name); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("PipelineScript(%q) error = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
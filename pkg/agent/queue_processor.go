@@ -5,7 +5,9 @@ import (
 	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
 	"github.com/braunmar/worktree/pkg/queue"
+	"github.com/braunmar/worktree/pkg/ui"
 )
 
 // ProcessQueue runs the next pending task from the queue
@@ -20,7 +22,7 @@ func ProcessQueue(cfg *config.Config, workCfg *config.WorktreeConfig, q *queue.Q
 		return fmt.Errorf("no pending tasks in queue")
 	}
 
-	fmt.Printf("📋 Processing queued task\n")
+	ui.Printf("📋 Processing queued task\n")
 	fmt.Printf("   ID: %s\n", task.ID)
 	fmt.Printf("   Agent: %s\n", task.AgentName)
 	fmt.Printf("   Worktree: %s\n", task.Worktree)
@@ -31,20 +33,28 @@ func ProcessQueue(cfg *config.Config, workCfg *config.WorktreeConfig, q *queue.Q
 		return fmt.Errorf("failed to update task status: %w", err)
 	}
 
-	// Get agent configuration
-	agentTask, exists := workCfg.ScheduledAgents[task.AgentName]
-	if !exists {
-		updateErr := q.UpdateStatus(task.ID, queue.StatusFailed, fmt.Errorf("agent not found: %s", task.AgentName))
+	// Get agent configuration (reruns use the definition they were queued with)
+	agentTask, err := queuedAgentTask(cfg, workCfg, task)
+	if err != nil {
+		updateErr := q.UpdateStatus(task.ID, queue.StatusFailed, err)
 		if updateErr != nil {
-			fmt.Printf("⚠️  Failed to update task status: %v\n", updateErr)
+			ui.Printf("⚠️  Failed to update task status: %v\n", updateErr)
 		}
-		return fmt.Errorf("agent not found in configuration: %s", task.AgentName)
+		return err
 	}
 
 	// Create executor
 	executor := NewExecutor(cfg, workCfg, agentTask, task.AgentName)
-
-	// Run task and track duration
+	executor.worktree = task.Worktree
+	executor.trigger = history.TriggerQueue
+	// Queued tasks run one after the other: wait for runs started elsewhere
+	executor.waitForLock = true
+
+	// Run task and track duration, keeping its output for "agent queue show"
+	stopLog, logErr := teeOutput(queue.LogPath(cfg.WorktreeDir, task.ID))
+	if logErr != nil {
+		ui.Printf("⚠️  Failed to create run log: %v\n", logErr)
+	}
 	start := time.Now()
 	execErr := executor.Run()
 	duration := time.Since(start)
@@ -53,10 +63,14 @@ func ProcessQueue(cfg *config.Config, workCfg *config.WorktreeConfig, q *queue.Q
 	var finalStatus queue.TaskStatus
 	if execErr != nil {
 		finalStatus = queue.StatusFailed
-		fmt.Printf("\n❌ Task failed after %s: %v\n", duration, execErr)
+		ui.Printf("\n❌ Task failed after %s: %v\n", duration, execErr)
 	} else {
 		finalStatus = queue.StatusCompleted
-		fmt.Printf("\n✅ Task completed successfully in %s\n", duration)
+		ui.Printf("\n✅ Task completed successfully in %s\n", duration)
+	}
+	if logErr == nil {
+		// Before the final status, so a follower has read the whole log when it sees it
+		stopLog()
 	}
 
 	// Update queue with final status
@@ -67,6 +81,24 @@ func ProcessQueue(cfg *config.Config, workCfg *config.WorktreeConfig, q *queue.Q
 	return execErr
 }
 
+// queuedAgentTask returns the definition a queued task runs: its history
+// snapshot when it has one, otherwise the current one from .worktree.yml
+func queuedAgentTask(cfg *config.Config, workCfg *config.WorktreeConfig, task *queue.QueuedTask) (*config.AgentTask, error) {
+	if task.TaskHash != "" {
+		h, err := history.Load(cfg.WorktreeDir, workCfg.Storage)
+		if err != nil {
+			return nil, err
+		}
+		return SnapshotTask(h, task.TaskHash)
+	}
+
+	agentTask, exists := workCfg.ScheduledAgents[task.AgentName]
+	if !exists {
+		return nil, fmt.Errorf("agent not found in configuration: %s", task.AgentName)
+	}
+	return agentTask, nil
+}
+
 // ProcessQueueContinuous processes all pending tasks in sequence
 func ProcessQueueContinuous(cfg *config.Config, workCfg *config.WorktreeConfig, q *queue.Queue) error {
 	processedCount := 0
@@ -79,15 +111,17 @@ func ProcessQueueContinuous(cfg *config.Config, workCfg *config.WorktreeConfig,
 			break
 		}
 
-		fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-		fmt.Printf("📊 Queue Status: %d pending, %d processed, %d failed\n", pendingCount, processedCount, failedCount)
-		fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		fmt.Println()
+		ui.Separator(53)
+		ui.Printf("📊 Queue Status: %d pending, %d processed, %d failed\n", pendingCount, processedCount, failedCount)
+		ui.Separator(53)
+		fmt.Println()
 
 		// Process next task
 		err := ProcessQueue(cfg, workCfg, q)
 		if err != nil {
 			failedCount++
-			fmt.Printf("⚠️  Continuing to next task after failure\n")
+			ui.Printf("⚠️  Continuing to next task after failure\n")
 		} else {
 			processedCount++
 		}
@@ -96,12 +130,13 @@ func ProcessQueueContinuous(cfg *config.Config, workCfg *config.WorktreeConfig,
 		time.Sleep(2 * time.Second)
 	}
 
-	fmt.Printf("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
-	fmt.Printf("🏁 Queue Processing Complete\n")
+	fmt.Println()
+	ui.Separator(53)
+	ui.Printf("🏁 Queue Processing Complete\n")
 	fmt.Printf("   Total processed: %d\n", processedCount)
 	fmt.Printf("   Failed: %d\n", failedCount)
 	fmt.Printf("   Success rate: %.1f%%\n", float64(processedCount-failedCount)/float64(processedCount)*100)
-	fmt.Printf("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	ui.Separator(53)
 
 	if failedCount > 0 {
 		return fmt.Errorf("%d task(s) failed", failedCount)
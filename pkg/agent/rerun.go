@@ -0,0 +1,43 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SnapshotTask returns the task definition stored in the history under hash,
+// as executed by the records referring to it
+func SnapshotTask(h *history.History, hash string) (*config.AgentTask, error) {
+	definition, err := h.TaskSnapshot(hash)
+	if err != nil {
+		return nil, err
+	}
+	var task config.AgentTask
+	if err := yaml.Unmarshal(definition, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse task snapshot: %w", err)
+	}
+	return &task, nil
+}
+
+// RerunTask returns the definition to rerun a past execution with: its
+// snapshot, or the current one from .worktree.yml when current is set or the
+// record has no snapshot. The returned hash is empty for the current definition.
+func RerunTask(h *history.History, workCfg *config.WorktreeConfig, record *history.ExecutionRecord, current bool) (*config.AgentTask, string, error) {
+	if !current && record.TaskHash != "" {
+		task, err := SnapshotTask(h, record.TaskHash)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w (use --current to rerun with the definition in .worktree.yml)", err)
+		}
+		return task, record.TaskHash, nil
+	}
+
+	task, exists := workCfg.ScheduledAgents[record.AgentName]
+	if !exists {
+		return nil, "", fmt.Errorf("agent task '%s' no longer exists in .worktree.yml", record.AgentName)
+	}
+	return task, "", nil
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+)
+
+func TestRerunTask(t *testing.T) {
+	h, err := history.Load(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	executed := &config.AgentTask{Name: "Audit", Steps: []config.AgentStep{{Name: "old", Command: "echo old"}}}
+	hash, err := snapshotTask(h, executed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	workCfg := &config.WorktreeConfig{ScheduledAgents: config.ScheduledAgents{
+		"audit": {Name: "Audit", Steps: []config.AgentStep{{Name: "new", Command: "echo new"}}},
+	}}
+	record := &history.ExecutionRecord{AgentName: "audit", TaskHash: hash}
+
+	task, gotHash, err := RerunTask(h, workCfg, record, false)
+	if err != nil {
+		t.Fatalf("RerunTask() error = %v", err)
+	}
+	if gotHash != hash || len(task.Steps) != 1 || task.Steps[0].Command != "echo old" {
+		t.Errorf("RerunTask() = %+v, %q; want the executed snapshot", task, gotHash)
+	}
+
+	task, gotHash, err = RerunTask(h, workCfg, record, true)
+	if err != nil || gotHash != "" || task.Steps[0].Command != "echo new" {
+		t.Errorf("RerunTask(current) = %+v, %q, %v; want the current definition", task, gotHash, err)
+	}
+
+	legacy := &history.ExecutionRecord{AgentName: "audit"}
+	if task, _, err := RerunTask(h, workCfg, legacy, false); err != nil || task.Steps[0].Command != "echo new" {
+		t.Errorf("RerunTask(no snapshot) = %+v, %v; want the current definition", task, err)
+	}
+
+	removed := &history.ExecutionRecord{AgentName: "gone"}
+	if _, _, err := RerunTask(h, workCfg, removed, false); err == nil {
+		t.Error("expected an error for a task that no longer exists")
+	}
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// teeDrainTimeout bounds how long stopping a tee waits for output still in
+// flight, e.g. from a background process a step left running
+const teeDrainTimeout = 5 * time.Second
+
+// teeOutput copies everything written to stdout and stderr, including the
+// output of step commands, to the file at path as well, until the returned
+// function is called
+func teeOutput(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	log := &syncWriter{w: f}
+
+	stdout, stderr := os.Stdout, os.Stderr
+	var readers []*os.File
+	var writers []*os.File
+	var wg sync.WaitGroup
+	for _, target := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			os.Stdout, os.Stderr = stdout, stderr
+			f.Close()
+			return nil, err
+		}
+		readers = append(readers, r)
+		writers = append(writers, w)
+
+		terminal := *target
+		*target = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = io.Copy(io.MultiWriter(terminal, log), r)
+		}()
+	}
+
+	return func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		for _, w := range writers {
+			w.Close()
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(teeDrainTimeout):
+		}
+		for _, r := range readers {
+			r.Close()
+		}
+		f.Close()
+	}, nil
+}
+
+// syncWriter serializes writes from several goroutines
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
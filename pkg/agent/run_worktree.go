@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// RunWorktreeDir is where isolated agent runs create their worktrees,
+// relative to the worktrees directory
+const RunWorktreeDir = ".agent-runs"
+
+// runWorktree is the ephemeral worktree of an isolated agent run: a git
+// worktree of each preset project, checked out on the run's branch
+type runWorktree struct {
+	Dir      string       // worktrees/.agent-runs/<agent>-<timestamp>
+	Branch   string       // Branch the worktrees are checked out on
+	Projects []runProject // Worktree of each preset project
+}
+
+// runProject is one project's git worktree in a run worktree
+type runProject struct {
+	Name          string
+	Repo          string // The project's checkout in the project root
+	Dir           string // The worktree
+	CreatedBranch bool   // The branch was created for the run
+	Pushed        bool   // The branch was pushed
+}
+
+// RunBranch returns the branch an isolated run works on: safety.git.branch
+// with {date} replaced, or agent/<agent>-<stamp> when none is configured
+func RunBranch(task *config.AgentTask, agentName, stamp string) string {
+	if task.Safety.Git.Branch != "" {
+		return strings.ReplaceAll(task.Safety.Git.Branch, "{date}", time.Now().Format("2006-01-02"))
+	}
+	return "agent/" + agentName + "-" + stamp
+}
+
+// runBase returns the ref a run branch is created from: the local base
+// branch, or its origin counterpart when only that exists
+func runBase(repo, base string) string {
+	if base == "" || git.RefExists(repo, base) || !git.RefExists(repo, "origin/"+base) {
+		return base
+	}
+	return "origin/" + base
+}
+
+// createWorktree creates the run's worktree: a git worktree of each preset
+// project branched from context.branch, with freshly allocated ports and the
+// project's generated files. The env vars of the ports are added to the
+// environment of steps and gates.
+func (e *Executor) createWorktree() error {
+	ui.Println("🔨 Creating agent worktree...")
+
+	preset, ok := e.workCfg.Presets[e.task.Context.Preset]
+	if !ok {
+		return fmt.Errorf("preset '%s' not found in .worktree.yml", e.task.Context.Preset)
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	e.runWT = &runWorktree{
+		Dir:    filepath.Join(e.cfg.WorktreeDir, RunWorktreeDir, e.agentName+"-"+stamp),
+		Branch: RunBranch(e.task, e.agentName, stamp),
+	}
+	fmt.Printf("   Directory: %s\n", e.runWT.Dir)
+	fmt.Printf("   Branch: %s\n", e.runWT.Branch)
+
+	for _, name := range preset.Projects {
+		project := e.workCfg.Projects[name]
+		p := runProject{
+			Name: name,
+			Repo: filepath.Join(e.cfg.ProjectRoot, project.Dir),
+			Dir:  filepath.Join(e.runWT.Dir, project.Dir),
+		}
+		p.CreatedBranch = !git.BranchExists(p.Repo, e.runWT.Branch)
+		if err := git.CreateWorktree(p.Repo, p.Dir, e.runWT.Branch, runBase(p.Repo, e.task.Context.Branch)); err != nil {
+			return fmt.Errorf("failed to create %s worktree: %w", name, err)
+		}
+		e.runWT.Projects = append(e.runWT.Projects, p)
+		ui.Printf("   ✅ %s worktree created\n", name)
+	}
+
+	vars, instance, err := e.runVars()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   Instance: %d\n", instance)
+	for _, name := range preset.Projects {
+		if err := e.workCfg.GenerateFiles(name, e.runWT.Dir, vars); err != nil {
+			ui.Printf("   ⚠️  Failed to generate files for %s: %v\n", name, err)
+		}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.setVar(key, vars[key])
+	}
+	fmt.Println()
+	return nil
+}
+
+// runVars allocates ports for the run the way a new feature gets them and
+// returns the env vars computed from them, with the run worktree as
+// FEATURE_DIR. The ports are not registered: the run frees them when it ends.
+func (e *Executor) runVars() (map[string]string, int, error) {
+	instance := e.task.Context.Instance
+	ports := make(map[string]int)
+	if services := e.workCfg.GetPortServiceNames(); len(services) > 0 {
+		reg, err := registry.Load(e.cfg.WorktreeDir, e.workCfg)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load registry: %w", err)
+		}
+		if ports, err = reg.AllocatePorts(services); err != nil {
+			return nil, 0, fmt.Errorf("failed to allocate ports: %w", err)
+		}
+		if name, err := e.workCfg.GetInstancePortName(); err == nil {
+			if base, err := config.ExtractBasePort(e.workCfg.EnvVariables[name].Port); err == nil {
+				instance = ports[name] - base
+			}
+		}
+	}
+
+	vars := e.workCfg.ExportEnvVars(instance)
+	for service, port := range ports {
+		vars[e.workCfg.EnvVariables[service].Env] = strconv.Itoa(port)
+	}
+	vars[config.PathVarProjectRoot] = e.cfg.ProjectRoot
+	config.AddPathVars(vars, e.runWT.Dir)
+	e.workCfg.ResolveValueVars(instance, vars)
+	return vars, instance, nil
+}
+
+// workDir returns the directory steps and gates run in by default: the run
+// worktree in isolated runs, else the project root
+func (e *Executor) workDir() string {
+	if e.runWT != nil {
+		return e.runWT.Dir
+	}
+	return e.cfg.ProjectRoot
+}
+
+// stepDir returns the directory a step runs in: its working_dir, resolved
+// against the run worktree in isolated runs, or dir when it has none
+func (e *Executor) stepDir(step config.AgentStep, dir string) string {
+	switch {
+	case step.WorkingDir == "":
+		return dir
+	case e.runWT != nil && !filepath.IsAbs(step.WorkingDir):
+		return filepath.Join(e.runWT.Dir, step.WorkingDir)
+	default:
+		return step.WorkingDir
+	}
+}
+
+// removeWorktree removes the run's worktrees and directory. A branch created
+// for the run is deleted too, unless it was pushed.
+func (e *Executor) removeWorktree() {
+	fmt.Println()
+	ui.Println("🧹 Removing agent worktree...")
+
+	for _, p := range e.runWT.Projects {
+		if output, err := git.Command(p.Repo, "worktree", "remove", "--force", p.Dir).CombinedOutput(); err != nil {
+			ui.Printf("  ⚠️  Failed to remove %s worktree: %s\n", p.Name, strings.TrimSpace(string(output)))
+		}
+		if p.CreatedBranch && !p.Pushed {
+			if output, err := git.Command(p.Repo, "branch", "-D", e.runWT.Branch).CombinedOutput(); err != nil {
+				ui.Printf("  ⚠️  Failed to delete %s branch %s: %s\n", p.Name, e.runWT.Branch, strings.TrimSpace(string(output)))
+			}
+		}
+	}
+	if err := os.RemoveAll(e.runWT.Dir); err != nil {
+		ui.Printf("  ⚠️  Failed to remove %s: %v\n", e.runWT.Dir, err)
+	}
+	for _, p := range e.runWT.Projects {
+		_ = git.PruneWorktrees(p.Repo)
+	}
+
+	ui.Printf("  ✅ Agent worktree removed\n")
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestRunBranch(t *testing.T) {
+	task := &config.AgentTask{}
+	if got := RunBranch(task, "deps", "20260101-090000"); got != "agent/deps-20260101-090000" {
+		t.Errorf("RunBranch() = %q, want agent/deps-20260101-090000", got)
+	}
+
+	task.Safety.Git.Branch = "automated/deps-{date}"
+	want := "automated/deps-" + time.Now().Format("2006-01-02")
+	if got := RunBranch(task, "deps", "20260101-090000"); got != want {
+		t.Errorf("RunBranch() = %q, want %q", got, want)
+	}
+}
+
+func TestStepDir(t *testing.T) {
+	root := t.TempDir()
+	e := &Executor{cfg: &config.Config{ProjectRoot: root}}
+	abs := filepath.Join(root, "abs")
+
+	tests := []struct {
+		name       string
+		isolated   bool
+		workingDir string
+		want       string
+	}{
+		{"default", false, "", root},
+		{"relative", false, "backend", "backend"},
+		{"isolated default", true, "", filepath.Join(root, "run")},
+		{"isolated relative", true, "backend", filepath.Join(root, "run", "backend")},
+		{"isolated absolute", true, abs, abs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e.runWT = nil
+			if tt.isolated {
+				e.runWT = &runWorktree{Dir: filepath.Join(root, "run")}
+			}
+			got := e.stepDir(config.AgentStep{WorkingDir: tt.workingDir}, e.workDir())
+			if got != tt.want {
+				t.Errorf("stepDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
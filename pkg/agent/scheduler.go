@@ -7,12 +7,16 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/queue"
 
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 )
 
@@ -23,8 +27,25 @@ type Scheduler struct {
 	cron     *cron.Cron
 	logFile  *os.File
 	mu       sync.Mutex
-	running  map[string]bool // Track running tasks to prevent overlaps
+	running  map[string]bool         // Track running tasks to prevent overlaps
+	entries  map[string]cron.EntryID // Cron entry of each scheduled task
 	stopChan chan struct{}
+
+	useQueue bool           // Enqueue scheduled runs and work the queue off one task at a time
+	wake     chan struct{}  // Tells the queue worker that a task was enqueued
+	worker   sync.WaitGroup // The queue worker, waited for on Stop
+
+	run func(taskName string, task *config.AgentTask) error // Runs a task (replaced in tests)
+}
+
+// NextRun returns the first time after after that a cron schedule (standard
+// 5-field format or a descriptor like @daily) fires
+func NextRun(schedule string, after time.Time) (time.Time, error) {
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid cron expression '%s': %w", schedule, err)
+	}
+	return sched.Next(after), nil
 }
 
 // NewScheduler creates a new agent scheduler
@@ -44,14 +65,25 @@ func NewScheduler(cfg *config.Config, workCfg *config.WorktreeConfig) (*Schedule
 	// Create cron scheduler (standard 5-field cron format: minute hour day month weekday)
 	cronScheduler := cron.New()
 
-	return &Scheduler{
+	s := &Scheduler{
 		cfg:      cfg,
 		workCfg:  workCfg,
 		cron:     cronScheduler,
 		logFile:  logFile,
 		running:  make(map[string]bool),
+		entries:  make(map[string]cron.EntryID),
 		stopChan: make(chan struct{}),
-	}, nil
+		wake:     make(chan struct{}, 1),
+	}
+	s.run = s.execute
+	return s, nil
+}
+
+// SetQueue makes scheduled runs go through the task queue: they are enqueued
+// and the daemon works the queue off one task at a time, including tasks
+// added with "agent queue add"
+func (s *Scheduler) SetQueue(enabled bool) {
+	s.useQueue = enabled
 }
 
 // Start starts the scheduler daemon
@@ -72,23 +104,35 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	log.Printf("Loading %d agent tasks...\n", len(s.workCfg.ScheduledAgents))
 
 	// Add each agent to the scheduler
-	for taskName, task := range s.workCfg.ScheduledAgents {
+	for _, taskName := range sortedTaskNames(s.workCfg.ScheduledAgents) {
+		task := s.workCfg.ScheduledAgents[taskName]
 		if err := s.addTask(taskName, task); err != nil {
 			log.Printf("ERROR: Failed to schedule task '%s': %v\n", taskName, err)
 			continue
 		}
 		log.Printf("✓ Scheduled: %s (%s) - cron: %s\n", task.Name, taskName, task.Schedule)
 	}
+	if len(s.entries) == 0 {
+		return fmt.Errorf("none of the scheduled agents has a valid schedule")
+	}
 
 	// Start the cron scheduler
 	s.cron.Start()
+	if s.useQueue {
+		s.worker.Add(1)
+		go s.processQueue()
+		log.Println("Queue mode: scheduled runs are enqueued and run one at a time")
+	}
 	log.Println("Scheduler started successfully")
-	log.Printf("Scheduled tasks: %d\n", len(s.cron.Entries()))
+	log.Printf("Scheduled tasks: %d\n", len(s.entries))
 	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 	// Print next run times
-	for _, entry := range s.cron.Entries() {
-		log.Printf("Next run: %s\n", entry.Next.Format("2006-01-02 15:04:05"))
+	nextRuns := s.GetNextRuns()
+	for _, taskName := range sortedTaskNames(s.workCfg.ScheduledAgents) {
+		if next, ok := nextRuns[taskName]; ok {
+			log.Printf("Next run of %s: %s\n", taskName, next.Format("2006-01-02 15:04:05"))
+		}
 	}
 
 	log.Println()
@@ -119,10 +163,17 @@ func (s *Scheduler) Stop() error {
 
 	// Stop accepting new jobs
 	ctx := s.cron.Stop()
+	close(s.stopChan)
 
-	// Wait for running jobs to complete (with timeout)
+	// Wait for running jobs and the queued task in progress to complete (with timeout)
+	done := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		s.worker.Wait()
+		close(done)
+	}()
 	select {
-	case <-ctx.Done():
+	case <-done:
 		log.Println("All jobs completed")
 	case <-time.After(30 * time.Second):
 		log.Println("Timeout waiting for jobs to complete")
@@ -137,7 +188,6 @@ func (s *Scheduler) Stop() error {
 	log.Println("Scheduler stopped")
 	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	close(s.stopChan)
 	return nil
 }
 
@@ -147,33 +197,138 @@ func (s *Scheduler) addTask(taskName string, task *config.AgentTask) error {
 	if task.Schedule == "" {
 		return fmt.Errorf("schedule is empty")
 	}
+	sched, err := cron.ParseStandard(task.Schedule)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression '%s': %w", task.Schedule, err)
+	}
 
-	// Create a job wrapper that prevents overlapping runs
-	job := func() {
-		s.mu.Lock()
-		if s.running[taskName] {
-			log.Printf("⚠️  Skipping '%s' - previous run still in progress\n", taskName)
-			s.mu.Unlock()
-			return
-		}
-		s.running[taskName] = true
-		s.mu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[taskName] = s.cron.Schedule(sched, cron.FuncJob(func() {
+		s.trigger(taskName, task)
+	}))
+	return nil
+}
 
-		// Run the task
-		s.runTask(taskName, task)
+// trigger starts a scheduled run of a task: it is enqueued in queue mode and
+// run right away otherwise. While the previous run of the task is still
+// pending or in progress, the run is skipped and recorded as skipped in the
+// history instead, so runs of a task never overlap.
+func (s *Scheduler) trigger(taskName string, task *config.AgentTask) {
+	if s.useQueue {
+		s.enqueue(taskName)
+		return
+	}
 
-		s.mu.Lock()
-		s.running[taskName] = false
+	s.mu.Lock()
+	if s.running[taskName] {
 		s.mu.Unlock()
+		s.skip(taskName, "previous run still in progress")
+		return
+	}
+	s.running[taskName] = true
+	s.mu.Unlock()
+
+	// Run the task
+	s.runTask(taskName, task)
+
+	s.mu.Lock()
+	s.running[taskName] = false
+	s.mu.Unlock()
+}
+
+// enqueue adds a scheduled run of a task to the queue and wakes the queue worker
+func (s *Scheduler) enqueue(taskName string) {
+	q, err := queue.Load(s.cfg.WorktreeDir, s.workCfg.Storage)
+	if err != nil {
+		log.Printf("ERROR: Failed to enqueue '%s': %v\n", taskName, err)
+		return
+	}
+	if len(q.Select(taskName, "", queue.StatusPending)) > 0 || len(q.Select(taskName, "", queue.StatusRunning)) > 0 {
+		s.skip(taskName, "previous run still queued or in progress")
+		return
 	}
 
-	// Add to cron scheduler
-	_, err := s.cron.AddFunc(task.Schedule, job)
+	queued, err := q.Add(taskName, "")
 	if err != nil {
-		return fmt.Errorf("invalid cron expression '%s': %w", task.Schedule, err)
+		log.Printf("ERROR: Failed to enqueue '%s': %v\n", taskName, err)
+		return
 	}
+	log.Printf("📋 Enqueued: %s (task %s)\n", taskName, queue.ShortID(queued.ID))
 
-	return nil
+	select {
+	case s.wake <- struct{}{}:
+	default: // The worker is already due to look at the queue
+	}
+}
+
+// processQueue works off the pending tasks of the queue one at a time until
+// the scheduler stops. Tasks already pending when the daemon starts are run
+// right away.
+func (s *Scheduler) processQueue() {
+	defer s.worker.Done()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	for {
+		select {
+		case <-s.wake:
+		case <-s.stopChan:
+			return
+		}
+
+		last := ""
+		for {
+			select {
+			case <-s.stopChan:
+				return
+			default:
+			}
+
+			q, err := queue.Load(s.cfg.WorktreeDir, s.workCfg.Storage)
+			if err != nil {
+				log.Printf("ERROR: Failed to load queue: %v\n", err)
+				break
+			}
+			next, _ := q.Next()
+			if next == nil || next.ID == last {
+				// A task still pending after its turn could not be started; retry on the next wake-up
+				break
+			}
+			last = next.ID
+
+			log.Printf("🤖 Running queued task %s (%s)\n", queue.ShortID(next.ID), next.AgentName)
+			if err := ProcessQueue(s.cfg, s.workCfg, q); err != nil {
+				log.Printf("❌ Queued task %s failed: %v\n", queue.ShortID(next.ID), err)
+			} else {
+				log.Printf("✅ Queued task %s completed\n", queue.ShortID(next.ID))
+			}
+		}
+	}
+}
+
+// skip records a scheduled run that did not start in the history
+func (s *Scheduler) skip(taskName, reason string) {
+	log.Printf("⚠️  Skipping '%s' - %s\n", taskName, reason)
+
+	h, err := loadHistory(s.cfg, s.workCfg)
+	if err == nil {
+		now := time.Now()
+		err = h.Record(history.ExecutionRecord{
+			ID:        uuid.New().String(),
+			AgentName: taskName,
+			Status:    history.RunSkipped,
+			StartTime: now,
+			EndTime:   now,
+			Error:     reason,
+			Trigger:   history.TriggerSchedule,
+		})
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to record skipped run of '%s': %v\n", taskName, err)
+	}
 }
 
 // runTask executes an agent task
@@ -186,11 +341,8 @@ func (s *Scheduler) runTask(taskName string, task *config.AgentTask) {
 	log.Printf("   Started: %s\n", startTime.Format("2006-01-02 15:04:05"))
 	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-	// Create executor
-	executor := NewExecutor(s.cfg, s.workCfg, task, taskName)
-
 	// Run the task
-	err := executor.Run()
+	err := s.run(taskName, task)
 
 	// Calculate duration
 	duration := time.Since(startTime)
@@ -206,8 +358,14 @@ func (s *Scheduler) runTask(taskName string, task *config.AgentTask) {
 	}
 	log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	log.Println()
+}
 
-	// TODO: Send notifications based on err != nil
+// execute runs a task with an executor, which records the run in the
+// history and sends the task's notifications
+func (s *Scheduler) execute(taskName string, task *config.AgentTask) error {
+	executor := NewExecutor(s.cfg, s.workCfg, task, taskName)
+	executor.SetTrigger(history.TriggerSchedule)
+	return executor.Run()
 }
 
 // GetNextRuns returns the next scheduled run times for all tasks
@@ -216,14 +374,8 @@ func (s *Scheduler) GetNextRuns() map[string]time.Time {
 	defer s.mu.Unlock()
 
 	nextRuns := make(map[string]time.Time)
-	entries := s.cron.Entries()
-
-	i := 0
-	for taskName := range s.workCfg.ScheduledAgents {
-		if i < len(entries) {
-			nextRuns[taskName] = entries[i].Next
-			i++
-		}
+	for taskName, id := range s.entries {
+		nextRuns[taskName] = s.cron.Entry(id).Next
 	}
 
 	return nextRuns
@@ -235,3 +387,13 @@ func (s *Scheduler) IsRunning(taskName string) bool {
 	defer s.mu.Unlock()
 	return s.running[taskName]
 }
+
+// sortedTaskNames returns the keys of the scheduled agents in sorted order
+func sortedTaskNames(tasks config.ScheduledAgents) []string {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
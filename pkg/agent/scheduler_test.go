@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/history"
+	"github.com/braunmar/worktree/pkg/queue"
+)
+
+// newTestScheduler creates a scheduler for two tasks whose runs are handled by run
+func newTestScheduler(t *testing.T, run func(string, *config.AgentTask) error) *Scheduler {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	cfg := &config.Config{ProjectRoot: root, WorktreeDir: filepath.Join(root, "worktrees")}
+	if err := os.MkdirAll(cfg.WorktreeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	workCfg := &config.WorktreeConfig{ScheduledAgents: config.ScheduledAgents{
+		"nightly": {Name: "Nightly", Schedule: "0 2 * * *"},
+		"weekly":  {Name: "Weekly", Schedule: "0 9 * * MON"},
+	}}
+
+	s, err := NewScheduler(cfg, workCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.logFile.Close() })
+	if run != nil {
+		s.run = run
+	}
+	return s
+}
+
+func TestNextRun(t *testing.T) {
+	sunday := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"0 9 * * MON", time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local)},
+		{"*/15 * * * *", time.Date(2026, 3, 1, 12, 15, 0, 0, time.Local)},
+		{"@daily", time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := NextRun(tt.schedule, sunday)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("NextRun(%q) = %v, %v; want %v", tt.schedule, got, err, tt.want)
+		}
+	}
+
+	if _, err := NextRun("0 25 * * *", sunday); err == nil {
+		t.Error("NextRun() accepted an invalid hour")
+	}
+}
+
+func TestSchedulerGetNextRuns(t *testing.T) {
+	s := newTestScheduler(t, nil)
+	for name, task := range s.workCfg.ScheduledAgents {
+		if err := s.addTask(name, task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.addTask("broken", &config.AgentTask{Schedule: "not cron"}); err == nil {
+		t.Error("addTask() accepted an invalid schedule")
+	}
+
+	s.cron.Start()
+	defer s.cron.Stop()
+	now := time.Now()
+	nextRuns := s.GetNextRuns()
+	if len(nextRuns) != 2 {
+		t.Fatalf("GetNextRuns() = %v, want the two valid tasks", nextRuns)
+	}
+	for name, task := range s.workCfg.ScheduledAgents {
+		want, _ := NextRun(task.Schedule, now)
+		if got := nextRuns[name]; !got.Equal(want) {
+			t.Errorf("next run of %s = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSchedulerSkipsOverlappingRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := newTestScheduler(t, func(string, *config.AgentTask) error {
+		close(started)
+		<-release
+		return nil
+	})
+	task := s.workCfg.ScheduledAgents["nightly"]
+
+	done := make(chan struct{})
+	go func() {
+		s.trigger("nightly", task)
+		close(done)
+	}()
+	<-started
+
+	// The second run returns right away without running the task
+	s.trigger("nightly", task)
+	close(release)
+	<-done
+
+	h, err := history.Load(s.cfg.WorktreeDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := h.Query("nightly", history.RunSkipped, 0)
+	if len(records) != 1 || records[0].Trigger != history.TriggerSchedule || records[0].Error != "previous run still in progress" {
+		t.Errorf("skipped runs = %+v, want one skipped scheduled run", records)
+	}
+	if s.IsRunning("nightly") {
+		t.Error("task still marked as running after its run")
+	}
+}
+
+func TestSchedulerQueueMode(t *testing.T) {
+	s := newTestScheduler(t, func(string, *config.AgentTask) error {
+		t.Error("queue mode ran a task directly")
+		return nil
+	})
+	s.SetQueue(true)
+
+	s.trigger("nightly", s.workCfg.ScheduledAgents["nightly"])
+	s.trigger("nightly", s.workCfg.ScheduledAgents["nightly"])
+	s.trigger("weekly", s.workCfg.ScheduledAgents["weekly"])
+
+	q, err := queue.Load(s.cfg.WorktreeDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(q.Select("nightly", "", queue.StatusPending)); n != 1 {
+		t.Errorf("pending nightly runs = %d, want 1", n)
+	}
+	if n := len(q.Select("weekly", "", queue.StatusPending)); n != 1 {
+		t.Errorf("pending weekly runs = %d, want 1", n)
+	}
+
+	h, err := history.Load(s.cfg.WorktreeDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records := h.Query("nightly", history.RunSkipped, 0); len(records) != 1 {
+		t.Errorf("skipped nightly runs = %d, want 1", len(records))
+	}
+}
@@ -6,10 +6,11 @@ import (
 	"os/exec"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/process"
 )
 
 // executeSkillStep executes a Claude Code skill command
-func (e *Executor) executeSkillStep(step config.AgentStep) error {
+func (e *Executor) executeSkillStep(step config.AgentStep, stepEnv []string) error {
 	// Skills are invoked via claude CLI with -c flag
 	// Example: claude -c "/backend 'Run npm audit fix'"
 
@@ -31,11 +32,9 @@ func (e *Executor) executeSkillStep(step config.AgentStep) error {
 	cmd := exec.Command("claude", args...)
 
 	// Set working directory
+	cmd.Dir = e.stepDir(step, e.workDir())
 	if step.WorkingDir != "" {
-		cmd.Dir = step.WorkingDir
-		fmt.Printf("      Working directory: %s\n", step.WorkingDir)
-	} else {
-		cmd.Dir = e.cfg.ProjectRoot
+		fmt.Printf("      Working directory: %s\n", cmd.Dir)
 	}
 
 	// Connect stdout and stderr for visibility
@@ -44,7 +43,7 @@ func (e *Executor) executeSkillStep(step config.AgentStep) error {
 	cmd.Stdin = os.Stdin // Important for interactive skills
 
 	// Set environment variables for YOLO mode
-	env := os.Environ()
+	env := append([]string{}, stepEnv...)
 	if e.task.Context.Yolo {
 		env = append(env, "CLAUDE_DANGEROUSLY_SKIP_PERMISSIONS=1")
 	}
@@ -52,6 +51,6 @@ func (e *Executor) executeSkillStep(step config.AgentStep) error {
 
 	fmt.Println()
 
-	// Run the skill
-	return cmd.Run()
+	// Run the skill, killing it after the step's timeout
+	return process.RunCommand(cmd, step.GetTimeout())
 }
@@ -0,0 +1,231 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// FormatVersion is the archive layout version written by Create
+const FormatVersion = 1
+
+// Archive layout: manifest.json, files/<feature-relative path>, volumes/<name>.tar
+const (
+	manifestName = "manifest.json"
+	filesDir     = "files"
+	volumesDir   = "volumes"
+)
+
+// Volume is a container volume stored in the archive
+type Volume struct {
+	Name           string `json:"name"`
+	ComposeProject string `json:"compose_project"`
+	ComposeVolume  string `json:"compose_volume,omitempty"`
+}
+
+// Manifest describes an archived feature environment
+type Manifest struct {
+	Version     int                `json:"version"`
+	ProjectName string             `json:"project_name"`
+	ArchivedAt  time.Time          `json:"archived_at"`
+	Worktree    *registry.Worktree `json:"worktree"`
+	Commits     map[string]string  `json:"commits,omitempty"` // Project -> HEAD commit at archive time
+	Files       []string           `json:"files"`             // Paths relative to the feature directory
+	Volumes     []Volume           `json:"volumes,omitempty"`
+}
+
+// VolumeDumpName is the file name of a volume's dump inside the volume directory
+// passed to Create
+func VolumeDumpName(name string) string {
+	return name + ".tar"
+}
+
+// ExtractedFile returns where Extract put an archived feature file
+func ExtractedFile(destDir, rel string) string {
+	return filepath.Join(destDir, filesDir, filepath.FromSlash(rel))
+}
+
+// ExtractedVolume returns where Extract put a volume dump
+func ExtractedVolume(destDir, name string) string {
+	return filepath.Join(destDir, volumesDir, VolumeDumpName(name))
+}
+
+// Create writes a gzip-compressed tarball to path holding the manifest, every
+// file in manifest.Files (read from featureDir) and a dump of every volume in
+// manifest.Volumes (read from volumeDir). The archive is written to a temporary
+// file first so a failed run never leaves a truncated archive behind.
+func Create(path string, manifest *Manifest, featureDir, volumeDir string) error {
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmpPath)
+
+	if err := writeArchive(f, manifest, featureDir, volumeDir); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+func writeArchive(w io.Writer, manifest *Manifest, featureDir, volumeDir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := writeEntry(tw, manifestName, data); err != nil {
+		return err
+	}
+
+	for _, rel := range manifest.Files {
+		if err := addFile(tw, path.Join(filesDir, filepath.ToSlash(rel)), filepath.Join(featureDir, rel)); err != nil {
+			return err
+		}
+	}
+	for _, vol := range manifest.Volumes {
+		name := VolumeDumpName(vol.Name)
+		if err := addFile(tw, path.Join(volumesDir, name), filepath.Join(volumeDir, name)); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: time.Now()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func addFile(tw *tar.Writer, name, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	header := &tar.Header{Name: name, Mode: int64(info.Mode().Perm()), Size: info.Size(), ModTime: info.ModTime()}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Extract unpacks an archive written by Create into destDir and returns its
+// manifest. Entries outside destDir and anything but regular files are rejected.
+func Extract(archivePath, destDir string) (*Manifest, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest *Manifest
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %s in archive", header.Name)
+		}
+		name := path.Clean(header.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("archive entry %s escapes the archive", header.Name)
+		}
+
+		if name == manifestName {
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("failed to parse manifest: %w", err)
+			}
+			continue
+		}
+
+		if err := extractFile(tr, filepath.Join(destDir, filepath.FromSlash(name)), os.FileMode(header.Mode).Perm()); err != nil {
+			return nil, err
+		}
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%s is not a feature archive (no %s)", archivePath, manifestName)
+	}
+	if manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is newer than supported (%d)", manifest.Version, FormatVersion)
+	}
+	if manifest.Worktree == nil {
+		return nil, fmt.Errorf("archive manifest has no feature entry")
+	}
+
+	return manifest, nil
+}
+
+func extractFile(r io.Reader, dest string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dest, err)
+	}
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", dest, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to extract %s: %w", dest, err)
+	}
+	return f.Close()
+}
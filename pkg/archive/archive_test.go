@@ -0,0 +1,135 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+func TestCreateExtract(t *testing.T) {
+	featureDir := t.TempDir()
+	volumeDir := t.TempDir()
+	writeFile(t, filepath.Join(featureDir, ".worktree-env"), "APP_PORT=9091\n")
+	writeFile(t, filepath.Join(featureDir, "backend", ".env.local"), "API_PORT=9091\n")
+	writeFile(t, filepath.Join(volumeDir, VolumeDumpName("proj-feat-backend_db")), "volume data")
+
+	manifest := &Manifest{
+		Version:     FormatVersion,
+		ProjectName: "proj",
+		ArchivedAt:  time.Now(),
+		Worktree:    &registry.Worktree{Branch: "feature/x", Normalized: "feature-x", Ports: map[string]int{"APP_PORT": 9091}},
+		Commits:     map[string]string{"backend": "abc123"},
+		Files:       []string{".worktree-env", "backend/.env.local"},
+		Volumes:     []Volume{{Name: "proj-feat-backend_db", ComposeProject: "proj-feat-backend", ComposeVolume: "db"}},
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "feature-x.tar.gz")
+	if err := Create(archivePath, manifest, featureDir, volumeDir); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := os.Stat(archivePath + ".tmp"); !os.IsNotExist(err) {
+		t.Error("temporary archive file left behind")
+	}
+
+	dest := t.TempDir()
+	got, err := Extract(archivePath, dest)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	if got.Worktree.Normalized != "feature-x" || got.Worktree.Ports["APP_PORT"] != 9091 {
+		t.Errorf("worktree = %+v", got.Worktree)
+	}
+	if got.Commits["backend"] != "abc123" || len(got.Volumes) != 1 || got.Volumes[0].ComposeVolume != "db" {
+		t.Errorf("manifest = %+v", got)
+	}
+	assertFile(t, ExtractedFile(dest, "backend/.env.local"), "API_PORT=9091\n")
+	assertFile(t, ExtractedFile(dest, ".worktree-env"), "APP_PORT=9091\n")
+	assertFile(t, ExtractedVolume(dest, "proj-feat-backend_db"), "volume data")
+}
+
+func TestCreateMissingFile(t *testing.T) {
+	manifest := &Manifest{Version: FormatVersion, Worktree: &registry.Worktree{}, Files: []string{"missing"}}
+	archivePath := filepath.Join(t.TempDir(), "a.tar.gz")
+
+	if err := Create(archivePath, manifest, t.TempDir(), t.TempDir()); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+		t.Error("archive written despite error")
+	}
+}
+
+func TestExtractRejects(t *testing.T) {
+	validManifest := `{"version":1,"worktree":{"normalized":"f"}}`
+
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantErr string
+	}{
+		{"path traversal", map[string]string{"manifest.json": validManifest, "../evil": "x"}, "escapes"},
+		{"no manifest", map[string]string{"files/a": "x"}, "not a feature archive"},
+		{"newer version", map[string]string{"manifest.json": `{"version":99,"worktree":{}}`}, "newer than supported"},
+		{"no feature", map[string]string{"manifest.json": `{"version":1}`}, "no feature entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			archivePath := filepath.Join(t.TempDir(), "a.tar.gz")
+			writeTarball(t, archivePath, tt.entries)
+
+			_, err := Extract(archivePath, t.TempDir())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Extract() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", path, data, want)
+	}
+}
+
+func writeTarball(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+}
@@ -5,32 +5,73 @@ type ScheduledAgents map[string]*AgentTask
 
 // AgentTask represents a scheduled agent maintenance task
 type AgentTask struct {
-	Name          string       `yaml:"name"`
-	Description   string       `yaml:"description"`
-	Schedule      string       `yaml:"schedule"`
-	Context       AgentContext `yaml:"context"`
-	Steps         []AgentStep  `yaml:"steps,omitempty"`
-	Safety        SafetyConfig `yaml:"safety"`
-	Notifications NotifyConfig `yaml:"notifications"`
-	GSD           *GSDConfig   `yaml:"gsd,omitempty"` // GSD framework integration
+	Name          string            `yaml:"name"`
+	Description   string            `yaml:"description"`
+	Schedule      string            `yaml:"schedule"`
+	Context       AgentContext      `yaml:"context"`
+	Steps         []AgentStep       `yaml:"steps,omitempty"`
+	Safety        SafetyConfig      `yaml:"safety"`
+	Notifications NotifyConfig      `yaml:"notifications"`
+	GSD           *GSDConfig        `yaml:"gsd,omitempty"`            // GSD framework integration
+	Environment   AgentEnvConfig    `yaml:"environment,omitempty"`    // Environment passed to steps and gates
+	Env           map[string]string `yaml:"env,omitempty"`            // Extra env vars for steps, gates and GSD (value templates or secret references)
+	CatchUp       bool              `yaml:"catch_up,omitempty"`       // Queue a catch-up run when "agent audit --enqueue" finds missed runs
+	MaxConcurrent int               `yaml:"max_concurrent,omitempty"` // Runs of this task at once, across processes (default: 1)
+}
+
+// GetMaxConcurrent returns how many runs of the task may be in progress at once
+func (t *AgentTask) GetMaxConcurrent() int {
+	if t.MaxConcurrent <= 0 {
+		return 1
+	}
+	return t.MaxConcurrent
 }
 
 // AgentContext defines the execution environment for an agent task
 type AgentContext struct {
-	Preset   string `yaml:"preset"`   // Which preset to use (frontend, backend, fullstack)
-	Branch   string `yaml:"branch"`   // Base branch to work from
-	Instance int    `yaml:"instance"` // Instance number for port allocation
-	Yolo     bool   `yaml:"yolo"`     // Enable YOLO mode for autonomous execution
+	Preset           string `yaml:"preset"`                      // Which preset to use (frontend, backend, fullstack)
+	Branch           string `yaml:"branch"`                      // Base branch to work from
+	Instance         int    `yaml:"instance"`                    // Instance number for port allocation
+	Yolo             bool   `yaml:"yolo"`                        // Enable YOLO mode for autonomous execution
+	IsolatedWorktree bool   `yaml:"isolated_worktree,omitempty"` // Run steps, gates and git operations in an ephemeral worktree instead of the project root
+}
+
+// AgentEnvConfig controls the environment that agent steps and safety gates run with
+type AgentEnvConfig struct {
+	Isolated    bool     `yaml:"isolated"`               // Start from a minimal environment instead of inheriting the caller's
+	PassThrough []string `yaml:"pass_through,omitempty"` // Extra host vars kept in isolated mode (trailing * matches a prefix)
+	CacheDir    string   `yaml:"cache_dir,omitempty"`    // Dedicated cache root (XDG_CACHE_HOME, GOCACHE, GOMODCACHE)
 }
 
 // AgentStep represents a single step in an agent task
 type AgentStep struct {
-	Name       string `yaml:"name"`
-	Type       string `yaml:"type"`                  // "shell" or "skill"
-	Command    string `yaml:"command,omitempty"`     // For shell steps
-	Skill      string `yaml:"skill,omitempty"`       // For skill steps
-	Args       string `yaml:"args,omitempty"`        // Arguments for skill steps
-	WorkingDir string `yaml:"working_dir,omitempty"` // Working directory for execution
+	Name         string            `yaml:"name"`
+	Type         string            `yaml:"type"`                    // "shell", "skill", "pipeline", "docker" or "http"
+	Command      string            `yaml:"command,omitempty"`       // For shell steps, and docker steps (run with sh -c in the container)
+	Skill        string            `yaml:"skill,omitempty"`         // For skill steps
+	Args         string            `yaml:"args,omitempty"`          // Arguments for skill steps
+	Pipeline     string            `yaml:"pipeline,omitempty"`      // For pipeline steps: script name in .worktree/steps (without .sh)
+	PipelineArgs []string          `yaml:"pipeline_args,omitempty"` // Arguments passed to the pipeline script
+	Project      string            `yaml:"project,omitempty"`       // For docker steps: project whose compose project the service runs in
+	Service      string            `yaml:"service,omitempty"`       // For docker steps: compose service to exec into
+	WorkingDir   string            `yaml:"working_dir,omitempty"`   // Working directory for execution (inside the container for docker steps)
+	Env          map[string]string `yaml:"env,omitempty"`           // Extra env vars for this step, on top of the task's env
+	Method       string            `yaml:"method,omitempty"`        // For http steps: request method (default GET)
+	URL          string            `yaml:"url,omitempty"`           // For http steps: request URL ($VAR and ${VAR} are expanded from the step env)
+	Headers      map[string]string `yaml:"headers,omitempty"`       // For http steps: request headers (expanded like url)
+	Body         string            `yaml:"body,omitempty"`          // For http steps: request body (expanded like url)
+	ExpectStatus int               `yaml:"expect_status,omitempty"` // For http steps: required response status (default: any 2xx)
+	Capture      string            `yaml:"capture,omitempty"`       // For http steps: env var that receives the response for later steps and gates
+	CaptureJSON  string            `yaml:"capture_json,omitempty"`  // For http steps: dotted path of the JSON field to capture instead of the whole body
+	Use          string            `yaml:"use,omitempty"`           // Expands to the steps of this step_templates entry
+	With         map[string]string `yaml:"with,omitempty"`          // Parameters for use: {name} in the template's steps is replaced by the value
+
+	Timeout         string `yaml:"timeout,omitempty"`           // Kill the step after this long, e.g. "30m" (default: no limit; http steps 60s)
+	Retries         int    `yaml:"retries,omitempty"`           // Extra attempts after a failure (default: 0)
+	RetryDelay      string `yaml:"retry_delay,omitempty"`       // Wait before the first retry, doubled before each further one (default: 10s)
+	ContinueOnError bool   `yaml:"continue_on_error,omitempty"` // Record a failure and go on with the next step instead of stopping the task
+
+	Template string `yaml:"-"` // step_templates entry the step was expanded from
 }
 
 // SafetyConfig defines safety mechanisms for agent tasks
@@ -52,15 +93,18 @@ type GitConfig struct {
 	Branch        string     `yaml:"branch"`
 	CommitMessage string     `yaml:"commit_message"`
 	Push          PushConfig `yaml:"push"`
+	Forge         string     `yaml:"forge,omitempty"` // "github", "gitlab" or "auto": where create_pr opens a pull/merge request (default: auto, from the origin remote)
 }
 
 // PushConfig defines push and PR creation settings
 type PushConfig struct {
-	Enabled   bool   `yaml:"enabled"`
-	CreatePR  bool   `yaml:"create_pr"`
-	PRTitle   string `yaml:"pr_title"`
-	PRBody    string `yaml:"pr_body"`
-	AutoMerge bool   `yaml:"auto_merge"`
+	Enabled      bool   `yaml:"enabled"`
+	CreatePR     bool   `yaml:"create_pr"`
+	PRTitle      string `yaml:"pr_title"`
+	PRBody       string `yaml:"pr_body"`
+	AutoMerge    bool   `yaml:"auto_merge"`
+	CommitStatus string `yaml:"commit_status,omitempty"` // "github" or "gitlab": post gate results as a commit status on the pushed commit
+	TargetBranch string `yaml:"target_branch,omitempty"` // Branch the pull/merge request merges into (default: context.branch)
 }
 
 // RollbackConfig defines rollback behavior on failure
@@ -87,8 +131,16 @@ type Notification struct {
 
 // GSDConfig defines GSD framework integration settings
 type GSDConfig struct {
-	Enabled      bool   `yaml:"enabled"`                  // Enable GSD workflow
-	Milestone    string `yaml:"milestone"`                // GSD milestone name
-	ReadTaskFile bool   `yaml:"read_task_file,omitempty"` // Read .task.md from worktree
-	AutoExecute  bool   `yaml:"auto_execute,omitempty"`   // Auto-execute after planning
+	Enabled      bool       `yaml:"enabled"`                  // Enable GSD workflow
+	Milestone    string     `yaml:"milestone"`                // GSD milestone name
+	ReadTaskFile bool       `yaml:"read_task_file,omitempty"` // Read .task.md from worktree
+	AutoExecute  bool       `yaml:"auto_execute,omitempty"`   // Auto-execute after planning
+	Phases       []GSDPhase `yaml:"phases,omitempty"`         // Phases run in order; default: one phase planned from .task.md
+}
+
+// GSDPhase is one phase of a GSD milestone
+type GSDPhase struct {
+	Name   string       `yaml:"name"`
+	Prompt string       `yaml:"prompt,omitempty"` // Passed to /gsd:plan-phase ({task} is replaced with .task.md)
+	Gates  []SafetyGate `yaml:"gates,omitempty"`  // Must pass before the next phase starts
 }
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IsSecretRef reports whether a value is a secret reference (op://, vault:, file:)
+func IsSecretRef(value string) bool {
+	for _, scheme := range []string{SecretSchemeOnePassword, SecretSchemeVault, SecretSchemeFile} {
+		if strings.HasPrefix(value, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveAgentEnv resolves the env map of an agent task or step into sorted
+// KEY=VALUE entries. A value is either a secret reference (op://, vault:,
+// file:) read from its backend, or a value template whose placeholders
+// ({instance}, {host}, {PORT_VAR}, {PORT_VAR+N}, {env:VAR}, path placeholders)
+// resolve against vars, the task instance's computed env vars.
+func (c *WorktreeConfig) ResolveAgentEnv(projectRoot string, instance int, values, vars map[string]string) ([]string, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value := values[key]
+		if IsSecretRef(value) {
+			secret, err := resolveSecret(projectRoot, value)
+			if err != nil {
+				return nil, fmt.Errorf("env %s: %w", key, err)
+			}
+			value = secret
+		} else if value != "" {
+			value = c.resolvePathRefs(value, vars)
+			value = c.withHostRefs(EnvVarConfig{Value: value}).GetValue(instance, vars, c.Hostname)
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, nil
+}
+
+// ValidateAgentEnv checks the names and secret references of an agent task or step env map
+func ValidateAgentEnv(where string, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !envNameRe.MatchString(key) {
+			return fmt.Errorf("%s: '%s' is not a valid environment variable name", where, key)
+		}
+		if IsSecretRef(values[key]) {
+			if err := validateSecret(fmt.Sprintf("%s.%s", where, key), EnvVarConfig{Env: key, Secret: values[key]}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveAgentEnv(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "token"), []byte("s3cret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AGENT_ENV_TEST_FLAG", "on")
+
+	cfg := &WorktreeConfig{Hostname: "dev.test"}
+	values := map[string]string{
+		"API_TOKEN": "file:token",
+		"API_URL":   "http://{host}:{BE_PORT}/v{instance}",
+		"FLAG":      "{env:AGENT_ENV_TEST_FLAG}",
+		"EMPTY":     "",
+	}
+	env, err := cfg.ResolveAgentEnv(root, 3, values, map[string]string{"BE_PORT": "8083"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"API_TOKEN=s3cret", "API_URL=http://dev.test:8083/v3", "EMPTY=", "FLAG=on"}
+	if !slices.Equal(env, want) {
+		t.Errorf("ResolveAgentEnv() = %v, want %v", env, want)
+	}
+
+	_, err = cfg.ResolveAgentEnv(root, 0, map[string]string{"MISSING": "file:nope"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "env MISSING") {
+		t.Errorf("missing secret file: err = %v", err)
+	}
+}
+
+func TestValidateAgentEnv(t *testing.T) {
+	if err := ValidateAgentEnv("env", map[string]string{"TOKEN": "op://vault/item/field", "URL": "http://x"}); err != nil {
+		t.Errorf("valid env: %v", err)
+	}
+	if err := ValidateAgentEnv("env", map[string]string{"BAD-NAME": "x"}); err == nil || !strings.Contains(err.Error(), "not a valid environment variable name") {
+		t.Errorf("invalid name: err = %v", err)
+	}
+	if err := ValidateAgentEnv("env", map[string]string{"TOKEN": "vault:"}); err == nil || !strings.Contains(err.Error(), "env.TOKEN") {
+		t.Errorf("empty secret reference: err = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultStepRetryDelay is the wait before a failed agent step's first retry
+const DefaultStepRetryDelay = 10 * time.Second
+
+// GetTimeout returns how long the step may run, or 0 for no limit
+func (s AgentStep) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(s.Timeout); err == nil {
+		return d
+	}
+	return 0
+}
+
+// GetRetryDelay returns the wait before the step's first retry
+func (s AgentStep) GetRetryDelay() time.Duration {
+	if d, err := time.ParseDuration(s.RetryDelay); err == nil {
+		return d
+	}
+	return DefaultStepRetryDelay
+}
+
+// Attempts returns how often the step is run before its failure is final
+func (s AgentStep) Attempts() int {
+	if s.Retries > 0 {
+		return 1 + s.Retries
+	}
+	return 1
+}
+
+// ValidateStepPolicy checks a step's timeout, retries and retry_delay
+func ValidateStepPolicy(step AgentStep) error {
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout '%s' (expected a positive duration like 30m)", step.Timeout)
+		}
+	}
+	if step.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	if step.RetryDelay != "" {
+		if d, err := time.ParseDuration(step.RetryDelay); err != nil || d < 0 {
+			return fmt.Errorf("invalid retry_delay '%s' (expected a duration like 30s)", step.RetryDelay)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAgentStepPolicy(t *testing.T) {
+	var step AgentStep
+	if step.GetTimeout() != 0 || step.GetRetryDelay() != DefaultStepRetryDelay || step.Attempts() != 1 {
+		t.Errorf("defaults: timeout %s, retry delay %s, attempts %d", step.GetTimeout(), step.GetRetryDelay(), step.Attempts())
+	}
+
+	step = AgentStep{Timeout: "30m", Retries: 2, RetryDelay: "1s"}
+	if step.GetTimeout() != 30*time.Minute || step.GetRetryDelay() != time.Second || step.Attempts() != 3 {
+		t.Errorf("configured: timeout %s, retry delay %s, attempts %d", step.GetTimeout(), step.GetRetryDelay(), step.Attempts())
+	}
+	if err := ValidateStepPolicy(step); err != nil {
+		t.Errorf("ValidateStepPolicy() = %v", err)
+	}
+}
+
+func TestValidateStepPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    AgentStep
+		wantErr string
+	}{
+		{"bad timeout", AgentStep{Timeout: "soon"}, "invalid timeout 'soon'"},
+		{"zero timeout", AgentStep{Timeout: "0s"}, "invalid timeout '0s'"},
+		{"negative retries", AgentStep{Retries: -1}, "retries must not be negative"},
+		{"bad retry delay", AgentStep{RetryDelay: "-1s"}, "invalid retry_delay '-1s'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStepPolicy(tt.step)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateStepPolicy() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	backupDirName      = ".backup"
+	backupManifestFile = "manifest.yml"
+	backupTimeFormat   = "20060102-150405.000000"
+
+	// backupsKept is how many snapshots a feature keeps; older ones are pruned
+	backupsKept = 10
+)
+
+// backupManifest records what a snapshot holds. Saved paths have their
+// previous version in the snapshot; Created paths did not exist before and
+// are removed on restore. Paths are relative to the feature dir.
+type backupManifest struct {
+	Saved   []string `yaml:"saved,omitempty"`
+	Created []string `yaml:"created,omitempty"`
+}
+
+// Backup is a snapshot of the files an operation is about to replace in a
+// feature worktree, stored under worktrees/<feature>/.backup/<timestamp>/.
+// The snapshot directory is only created once something is recorded.
+type Backup struct {
+	featureDir string
+	dir        string
+	manifest   backupManifest
+}
+
+// NewBackup starts a snapshot for featureDir
+func NewBackup(featureDir string) *Backup {
+	return &Backup{
+		featureDir: featureDir,
+		dir:        filepath.Join(featureDir, backupDirName, time.Now().Format(backupTimeFormat)),
+	}
+}
+
+// Dir returns the snapshot directory relative to the feature dir, or "" when
+// nothing was recorded
+func (b *Backup) Dir() string {
+	if b.Empty() {
+		return ""
+	}
+	return filepath.Join(backupDirName, filepath.Base(b.dir))
+}
+
+// Empty reports whether nothing was recorded
+func (b *Backup) Empty() bool {
+	return len(b.manifest.Saved) == 0 && len(b.manifest.Created) == 0
+}
+
+// Preserve copies the current version of rel into the snapshot before it is
+// overwritten, or records it as created when it does not exist yet
+func (b *Backup) Preserve(rel string) error {
+	if _, err := os.Lstat(filepath.Join(b.featureDir, rel)); os.IsNotExist(err) {
+		if err := b.start(); err != nil {
+			return err
+		}
+		b.manifest.Created = append(b.manifest.Created, rel)
+		return b.writeManifest()
+	}
+
+	dest, err := b.prepare(rel)
+	if err != nil {
+		return err
+	}
+	if err := copyPath(filepath.Join(b.featureDir, rel), dest); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", rel, err)
+	}
+	b.manifest.Saved = append(b.manifest.Saved, rel)
+	return b.writeManifest()
+}
+
+// Move moves rel into the snapshot, freeing its path
+func (b *Backup) Move(rel string) error {
+	dest, err := b.prepare(rel)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(b.featureDir, rel), dest); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", rel, err)
+	}
+	b.manifest.Saved = append(b.manifest.Saved, rel)
+	return b.writeManifest()
+}
+
+// start creates the snapshot directory before the first entry is recorded,
+// pruning old snapshots
+func (b *Backup) start() error {
+	if !b.Empty() {
+		return nil
+	}
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create backup dir: %w", err)
+	}
+	pruneBackups(b.featureDir)
+	return nil
+}
+
+// prepare starts the snapshot and creates the parent directory of rel in it,
+// returning rel's path in the snapshot
+func (b *Backup) prepare(rel string) (string, error) {
+	if err := b.start(); err != nil {
+		return "", err
+	}
+	dest := filepath.Join(b.dir, rel)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup dir: %w", err)
+	}
+	return dest, nil
+}
+
+func (b *Backup) writeManifest() error {
+	data, err := yaml.Marshal(b.manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal backup manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(b.dir, backupManifestFile), data, 0644)
+}
+
+// Backups lists the snapshot directories of a feature, oldest first
+func Backups(featureDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(featureDir, backupDirName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backups: %w", err)
+	}
+
+	var snapshots []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			snapshots = append(snapshots, filepath.Join(featureDir, backupDirName, entry.Name()))
+		}
+	}
+	sort.Strings(snapshots)
+	return snapshots, nil
+}
+
+// RestoreBackup puts the files of a snapshot back in place, removes the files
+// the snapshotted operation created, and deletes the snapshot. It returns the
+// restored and removed paths.
+func RestoreBackup(featureDir, snapshotDir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(snapshotDir, backupManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+	var manifest backupManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %w", err)
+	}
+
+	var restored []string
+	for _, rel := range manifest.Created {
+		target, err := backupTarget(featureDir, rel)
+		if err != nil {
+			return restored, err
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return restored, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		restored = append(restored, rel)
+	}
+	for _, rel := range manifest.Saved {
+		target, err := backupTarget(featureDir, rel)
+		if err != nil {
+			return restored, err
+		}
+		if err := os.RemoveAll(target); err != nil {
+			return restored, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+		if err := os.Rename(filepath.Join(snapshotDir, rel), target); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+		restored = append(restored, rel)
+	}
+
+	if err := os.RemoveAll(snapshotDir); err != nil {
+		return restored, fmt.Errorf("failed to remove backup: %w", err)
+	}
+	return restored, nil
+}
+
+// backupTarget resolves a manifest path, refusing paths outside the feature dir
+func backupTarget(featureDir, rel string) (string, error) {
+	rel = filepath.Clean(rel)
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid backup path: %s", rel)
+	}
+	return filepath.Join(featureDir, rel), nil
+}
+
+// pruneBackups removes all but the newest backupsKept snapshots
+func pruneBackups(featureDir string) {
+	snapshots, err := Backups(featureDir)
+	if err != nil || len(snapshots) <= backupsKept {
+		return
+	}
+	for _, snapshot := range snapshots[:len(snapshots)-backupsKept] {
+		os.RemoveAll(snapshot)
+	}
+}
+
+// copyPath copies a file, symlink or directory tree from src to dest
+func copyPath(src, dest string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dest)
+	case info.IsDir():
+		return os.CopyFS(dest, os.DirFS(src))
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncFeatureFiles_BackupAndRestore(t *testing.T) {
+	root := t.TempDir()
+	featureDir := filepath.Join(root, "worktrees", "feature-x")
+	if err := os.MkdirAll(filepath.Join(featureDir, "backend"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &WorktreeConfig{
+		Symlinks: []FileLink{{Source: "shared", Target: "shared"}},
+		Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}},
+		GeneratedFiles: map[string][]GeneratedFile{
+			"backend": {{Path: ".env", Template: "PORT={APP_PORT}\n"}},
+		},
+	}
+	readEnv := func() string {
+		data, _ := os.ReadFile(filepath.Join(featureDir, "backend", ".env"))
+		return string(data)
+	}
+
+	first := cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, map[string]string{"APP_PORT": "8081"})
+	if first.Backup == "" {
+		t.Fatal("first sync recorded no snapshot")
+	}
+	if result := cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, map[string]string{"APP_PORT": "8081"}); result.Backup != "" {
+		t.Errorf("no-op sync recorded snapshot %s", result.Backup)
+	}
+	cfg.Symlinks[0].Source = "other"
+	second := cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, map[string]string{"APP_PORT": "8082"})
+	if second.Backup == "" || second.Backup == first.Backup {
+		t.Fatalf("second sync snapshot = %q (first %q)", second.Backup, first.Backup)
+	}
+
+	snapshots, err := Backups(featureDir)
+	if err != nil || len(snapshots) != 2 {
+		t.Fatalf("Backups() = %v, %v; want 2 snapshots", snapshots, err)
+	}
+
+	// Undo the second sync: previous file content and link target come back
+	if _, err := RestoreBackup(featureDir, snapshots[1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := readEnv(); got != "PORT=8081\n" {
+		t.Errorf(".env after first undo = %q", got)
+	}
+	if link, _ := os.Readlink(filepath.Join(featureDir, "shared")); link != "../../shared" {
+		t.Errorf("symlink after first undo = %q, want ../../shared", link)
+	}
+
+	// Undo the first sync: files it created are removed
+	if _, err := RestoreBackup(featureDir, snapshots[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(featureDir, "backend", ".env")); !os.IsNotExist(err) {
+		t.Errorf(".env still exists after undoing its creation: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(featureDir, "shared")); !os.IsNotExist(err) {
+		t.Errorf("symlink still exists after undoing its creation: %v", err)
+	}
+	if snapshots, _ := Backups(featureDir); len(snapshots) != 0 {
+		t.Errorf("snapshots left after restoring all: %v", snapshots)
+	}
+}
+
+func TestBackupMove(t *testing.T) {
+	featureDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(featureDir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(featureDir, "data", "seed.sql"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := NewBackup(featureDir)
+	if !backup.Empty() || backup.Dir() != "" {
+		t.Fatal("new backup is not empty")
+	}
+	if err := backup.Move("data"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(featureDir, "data")); !os.IsNotExist(err) {
+		t.Fatalf("data still in place after Move: %v", err)
+	}
+	moved := filepath.Join(featureDir, backup.Dir(), "data", "seed.sql")
+	if data, err := os.ReadFile(moved); err != nil || string(data) != "mine" {
+		t.Fatalf("snapshot copy = %q, %v", data, err)
+	}
+
+	if _, err := RestoreBackup(featureDir, filepath.Join(featureDir, backup.Dir())); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(featureDir, "data", "seed.sql")); err != nil || string(data) != "mine" {
+		t.Errorf("restored file = %q, %v", data, err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	featureDir := t.TempDir()
+	for i := 0; i < backupsKept+3; i++ {
+		name := filepath.Join(featureDir, backupDirName, "20260101-0000"+string(rune('a'+i)))
+		if err := os.MkdirAll(name, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruneBackups(featureDir)
+
+	snapshots, _ := Backups(featureDir)
+	if len(snapshots) != backupsKept {
+		t.Fatalf("kept %d snapshots, want %d", len(snapshots), backupsKept)
+	}
+	if filepath.Base(snapshots[0]) != "20260101-0000d" {
+		t.Errorf("oldest kept = %s, want the oldest three pruned", filepath.Base(snapshots[0]))
+	}
+}
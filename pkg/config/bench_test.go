@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchEnvVars is the size of the "large" config the benchmarks use
+const benchEnvVars = 200
+
+// largeConfigYAML returns a .worktree.yml with n env variables: a ranged port,
+// a calculated port and two value templates referencing ports, repeated
+func largeConfigYAML(n int) string {
+	var b strings.Builder
+	b.WriteString(`project_name: "bench"
+projects:
+  backend:
+    dir: backend
+    main_branch: main
+    start_command: "docker compose up -d"
+  frontend:
+    dir: frontend
+    executor: process
+    start_command: "npm start"
+presets:
+  default:
+    projects: [backend, frontend]
+default_preset: default
+env_variables:
+`)
+	for i := 0; i < n; i++ {
+		switch i % 4 {
+		case 0:
+			fmt.Fprintf(&b, "  PORT_%d:\n    port: \"%d\"\n    env: PORT_%d\n    range: [%d, %d]\n", i, 10000+i*100, i, 10000+i*100, 10000+i*100+99)
+		case 1:
+			fmt.Fprintf(&b, "  CALC_%d:\n    port: \"%d + {instance} * 2\"\n    env: CALC_%d\n", i, 40000+i*10, i)
+		case 2:
+			fmt.Fprintf(&b, "  URL_%d:\n    value: \"http://{host}:{PORT_%d}/api\"\n    env: URL_%d\n", i, i-2, i)
+		default:
+			fmt.Fprintf(&b, "  DSN_%d:\n    value: \"postgres://user@localhost:{PORT_%d}/db_{instance}?fallback={env:BENCH_UNSET:-none}\"\n    env: DSN_%d\n", i, i-3, i)
+		}
+	}
+	return b.String()
+}
+
+// writeBenchConfig writes the large config to a temporary project root
+func writeBenchConfig(b *testing.B) string {
+	b.Helper()
+	dir := b.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".worktree.yml"), []byte(largeConfigYAML(benchEnvVars)), 0644); err != nil {
+		b.Fatal(err)
+	}
+	return dir
+}
+
+func benchConfig(b *testing.B) *WorktreeConfig {
+	b.Helper()
+	cfg, err := LoadWorktreeConfig(writeBenchConfig(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return cfg
+}
+
+func BenchmarkLoadWorktreeConfig(b *testing.B) {
+	dir := writeBenchConfig(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := LoadWorktreeConfig(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExportEnvVars(b *testing.B) {
+	cfg := benchConfig(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		cfg.ExportEnvVars(3)
+	}
+}
+
+func BenchmarkResolveValueVars(b *testing.B) {
+	cfg := benchConfig(b)
+	envVars := cfg.ExportEnvVars(3)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		cfg.ResolveValueVars(3, envVars)
+	}
+}
+
+// TestPerformanceBudget fails when a hot path exceeds its time budget. The
+// budgets are generous upper bounds meant to catch regressions of an order of
+// magnitude, not noise. Timing depends on the machine, so it only runs with
+// WORKTREE_BENCH_BUDGET=1 (make bench-budget).
+func TestPerformanceBudget(t *testing.T) {
+	if os.Getenv("WORKTREE_BENCH_BUDGET") == "" {
+		t.Skip("set WORKTREE_BENCH_BUDGET=1 to check performance budgets")
+	}
+
+	budgets := []struct {
+		name   string
+		bench  func(*testing.B)
+		budget time.Duration
+	}{
+		{"LoadWorktreeConfig", BenchmarkLoadWorktreeConfig, 50 * time.Millisecond},
+		{"ExportEnvVars", BenchmarkExportEnvVars, 25 * time.Millisecond},
+		{"ResolveValueVars", BenchmarkResolveValueVars, 30 * time.Millisecond},
+	}
+
+	for _, tt := range budgets {
+		t.Run(tt.name, func(t *testing.T) {
+			result := testing.Benchmark(tt.bench)
+			perOp := time.Duration(result.NsPerOp())
+			t.Logf("%s: %v/op, %d allocs/op", tt.name, perOp, result.AllocsPerOp())
+			if perOp > tt.budget {
+				t.Errorf("%s took %v/op, budget is %v", tt.name, perOp, tt.budget)
+			}
+		})
+	}
+}
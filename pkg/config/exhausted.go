@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// on_exhausted actions: what allocating a port does once every port of the
+// variable's range (or pool) is taken
+const (
+	OnExhaustedFail   = "fail"          // Abort (default)
+	OnExhaustedExpand = "expand-range"  // Allocate from the ports right above the range
+	OnExhaustedSteal  = "steal-stopped" // Reclaim the port of a feature whose services are stopped, after confirmation
+)
+
+// expandRangeRe matches expand-range(+N)
+var expandRangeRe = regexp.MustCompile(`^expand-range\(\+?(\d+)\)$`)
+
+// ExhaustedPolicy is a parsed on_exhausted value
+type ExhaustedPolicy struct {
+	Action string // One of the OnExhausted* actions
+	Expand int    // Ports above the range expand-range may use
+}
+
+// ParseOnExhausted parses an on_exhausted value: fail, expand-range(+N) or
+// steal-stopped. Empty means fail.
+func ParseOnExhausted(value string) (ExhaustedPolicy, error) {
+	switch value {
+	case "", OnExhaustedFail:
+		return ExhaustedPolicy{Action: OnExhaustedFail}, nil
+	case OnExhaustedSteal:
+		return ExhaustedPolicy{Action: OnExhaustedSteal}, nil
+	}
+	if m := expandRangeRe.FindStringSubmatch(value); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err == nil && n > 0 {
+			return ExhaustedPolicy{Action: OnExhaustedExpand, Expand: n}, nil
+		}
+	}
+	return ExhaustedPolicy{}, fmt.Errorf("invalid on_exhausted '%s' (expected fail, expand-range(+N) or steal-stopped)", value)
+}
+
+// GetOnExhausted returns the on_exhausted policy of a port variable
+func (c *WorktreeConfig) GetOnExhausted(name string) ExhaustedPolicy {
+	policy, _ := ParseOnExhausted(c.EnvVariables[name].OnExhausted) // Validated on load
+	return policy
+}
+
+// validateOnExhausted checks the on_exhausted of each port variable
+func (c *WorktreeConfig) validateOnExhausted() error {
+	for name, portCfg := range c.EnvVariables {
+		if portCfg.OnExhausted == "" {
+			continue
+		}
+		policy, err := ParseOnExhausted(portCfg.OnExhausted)
+		if err != nil {
+			return fmt.Errorf("port %s: %w", name, err)
+		}
+		portRange := c.GetPortRangeFor(name)
+		if portRange == nil || portCfg.Env == "" {
+			return fmt.Errorf("port %s: on_exhausted requires a port allocated from a range or pool", name)
+		}
+		if policy.Action == OnExhaustedExpand && portRange[1]+policy.Expand > 65535 {
+			return fmt.Errorf("port %s: on_exhausted %s goes past port 65535", name, portCfg.OnExhausted)
+		}
+	}
+	return nil
+}
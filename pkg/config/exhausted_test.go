@@ -0,0 +1,49 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOnExhausted(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ExhaustedPolicy
+		wantErr bool
+	}{
+		{value: "", want: ExhaustedPolicy{Action: OnExhaustedFail}},
+		{value: "fail", want: ExhaustedPolicy{Action: OnExhaustedFail}},
+		{value: "steal-stopped", want: ExhaustedPolicy{Action: OnExhaustedSteal}},
+		{value: "expand-range(+50)", want: ExhaustedPolicy{Action: OnExhaustedExpand, Expand: 50}},
+		{value: "expand-range(10)", want: ExhaustedPolicy{Action: OnExhaustedExpand, Expand: 10}},
+		{value: "expand-range(+0)", wantErr: true},
+		{value: "expand-range", wantErr: true},
+		{value: "steal", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseOnExhausted(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseOnExhausted(%q) = %+v, %v, want %+v (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateOnExhausted(t *testing.T) {
+	portRange := [2]int{65500, 65530}
+	cfg := &WorktreeConfig{EnvVariables: map[string]EnvVarConfig{
+		"APP_PORT": {Env: "APP_PORT", Range: &portRange, OnExhausted: "expand-range(+5)"},
+	}}
+	if err := cfg.validateOnExhausted(); err != nil {
+		t.Errorf("validateOnExhausted() error = %v", err)
+	}
+
+	cfg.EnvVariables["APP_PORT"] = EnvVarConfig{Env: "APP_PORT", Range: &portRange, OnExhausted: "expand-range(+10)"}
+	if err := cfg.validateOnExhausted(); err == nil || !strings.Contains(err.Error(), "past port 65535") {
+		t.Errorf("validateOnExhausted() error = %v, want past port 65535", err)
+	}
+
+	cfg.EnvVariables["APP_PORT"] = EnvVarConfig{Value: "3000", OnExhausted: "steal-stopped"}
+	if err := cfg.validateOnExhausted(); err == nil || !strings.Contains(err.Error(), "requires a port allocated") {
+		t.Errorf("validateOnExhausted() error = %v, want requires a port allocated", err)
+	}
+}
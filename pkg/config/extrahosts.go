@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ComposeOverrideFile is the compose override rendered into a project
+// worktree from its extra_hosts section
+const ComposeOverrideFile = ".worktree-compose.yml"
+
+// defaultComposeFiles are the files docker compose looks for when COMPOSE_FILE
+// is not set, in its order of preference
+var defaultComposeFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ExtraHostsConfig adds hosts entries to a project's compose services, so
+// containers resolve per-feature hostnames, e.g.
+//
+//	extra_hosts:
+//	  services: ["api"]
+//	  hosts:
+//	    "api.{feature}.local": "host-gateway"
+type ExtraHostsConfig struct {
+	Services     []string          `yaml:"services"`      // Compose services that get the entries
+	Hosts        map[string]string `yaml:"hosts"`         // Hostname -> IP or host-gateway; both may use {feature}, {feature_host}, {KEY} and {host:KEY}
+	ComposeFiles []string          `yaml:"compose_files"` // Compose files the override extends (default: the file compose picks, plus its .override file)
+}
+
+// Enabled reports whether an override is rendered for the project
+func (e *ExtraHostsConfig) Enabled() bool {
+	return len(e.Hosts) > 0
+}
+
+// validateExtraHosts checks a project's extra_hosts section
+func (c *WorktreeConfig) validateExtraHosts(where string, project ProjectConfig) error {
+	e := project.ExtraHosts
+	if !e.Enabled() {
+		if len(e.Services) > 0 || len(e.ComposeFiles) > 0 {
+			return fmt.Errorf("%s: hosts is required", where)
+		}
+		return nil
+	}
+	if project.GetExecutor() != "docker" {
+		return fmt.Errorf("%s: requires the docker executor", where)
+	}
+	if len(e.Services) == 0 {
+		return fmt.Errorf("%s: services is required (the compose services that get the entries)", where)
+	}
+	if slices.Contains(e.Services, "") {
+		return fmt.Errorf("%s: services contains an empty service name", where)
+	}
+	for host, address := range e.Hosts {
+		if host == "" || strings.ContainsAny(host, ": \t") {
+			return fmt.Errorf("%s: invalid hostname '%s'", where, host)
+		}
+		if address == "" {
+			return fmt.Errorf("%s: hosts.%s: address is required (an IP or host-gateway)", where, host)
+		}
+		if err := c.validateHostRefs(fmt.Sprintf("%s: hosts.%s", where, host), host+address); err != nil {
+			return err
+		}
+	}
+	for _, file := range e.ComposeFiles {
+		if file == "" || filepath.IsAbs(file) || strings.HasPrefix(filepath.Clean(file), "..") {
+			return fmt.Errorf("%s: compose_files entry '%s' must be relative to the project directory", where, file)
+		}
+	}
+	return nil
+}
+
+// ResolveExtraHosts returns a project's extra_hosts entries for a feature,
+// with placeholders in hostnames and addresses resolved
+func (c *WorktreeConfig) ResolveExtraHosts(projectName, featureName string, envVars map[string]string) map[string]string {
+	resolve := func(s string) string {
+		s = strings.ReplaceAll(s, "{feature_host}", c.FeatureHost(featureName))
+		s = strings.ReplaceAll(s, "{feature}", featureName)
+		s = c.resolveHostRefs(s)
+		return substituteVars(s, envVars)
+	}
+
+	hosts := make(map[string]string)
+	for host, address := range c.Projects[projectName].ExtraHosts.Hosts {
+		hosts[resolve(host)] = resolve(address)
+	}
+	return hosts
+}
+
+// ComposeOverridePath returns the path of a project's rendered compose override
+func (c *WorktreeConfig) ComposeOverridePath(featureDir, projectName string) string {
+	return filepath.Join(featureDir, c.Projects[projectName].Dir, ComposeOverrideFile)
+}
+
+// WriteComposeOverride renders a project's extra_hosts into its compose
+// override and returns the path, or "" when extra_hosts is not configured
+// (a leftover override is then removed)
+func (c *WorktreeConfig) WriteComposeOverride(featureDir, projectName, featureName string, envVars map[string]string) (string, error) {
+	path := c.ComposeOverridePath(featureDir, projectName)
+	project := c.Projects[projectName]
+	if !project.ExtraHosts.Enabled() {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to remove compose override: %w", err)
+		}
+		return "", nil
+	}
+
+	hosts := c.ResolveExtraHosts(projectName, featureName, envVars)
+	services := make(map[string]map[string]map[string]string, len(project.ExtraHosts.Services))
+	for _, service := range project.ExtraHosts.Services {
+		services[service] = map[string]map[string]string{"extra_hosts": hosts}
+	}
+	data, err := yaml.Marshal(map[string]any{"services": services})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal compose override: %w", err)
+	}
+
+	header := "# Generated by worktree from extra_hosts - do not edit\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return "", fmt.Errorf("failed to write compose override: %w", err)
+	}
+	return path, nil
+}
+
+// ComposeFiles returns the files a project's compose commands load when
+// extra_hosts is configured: its compose files followed by the rendered
+// override. Returns nil when there is no override.
+func (c *WorktreeConfig) ComposeFiles(featureDir, projectName string) ([]string, error) {
+	project := c.Projects[projectName]
+	override := c.ComposeOverridePath(featureDir, projectName)
+	if !project.ExtraHosts.Enabled() || !fileExists(override) {
+		return nil, nil
+	}
+
+	projectDir := filepath.Join(featureDir, project.Dir)
+	var files []string
+	if len(project.ExtraHosts.ComposeFiles) > 0 {
+		for _, file := range project.ExtraHosts.ComposeFiles {
+			files = append(files, filepath.Join(projectDir, file))
+		}
+	} else {
+		// Setting COMPOSE_FILE turns off compose's own lookup, including its override file
+		for _, name := range defaultComposeFiles {
+			base := filepath.Join(projectDir, name)
+			if !fileExists(base) {
+				continue
+			}
+			files = append(files, base)
+			ext := filepath.Ext(name)
+			if extra := strings.TrimSuffix(base, ext) + ".override" + ext; fileExists(extra) {
+				files = append(files, extra)
+			}
+			break
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("project '%s': no compose file found in %s (set extra_hosts.compose_files)", projectName, projectDir)
+		}
+	}
+	return append(files, override), nil
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateExtraHosts(t *testing.T) {
+	c := &WorktreeConfig{ProjectName: "myapp"}
+	tests := []struct {
+		name    string
+		project ProjectConfig
+		wantErr string
+	}{
+		{name: "not configured"},
+		{name: "valid", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api"},
+			Hosts:    map[string]string{"api.{feature}.local": "host-gateway"},
+		}}},
+		{name: "services without hosts", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{Services: []string{"api"}}}, wantErr: "hosts is required"},
+		{name: "no services", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{Hosts: map[string]string{"a.local": "10.0.0.1"}}}, wantErr: "services is required"},
+		{name: "process executor", project: ProjectConfig{Executor: "process", ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api"}, Hosts: map[string]string{"a.local": "10.0.0.1"},
+		}}, wantErr: "requires the docker executor"},
+		{name: "hostname with colon", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api"}, Hosts: map[string]string{"a.local:80": "10.0.0.1"},
+		}}, wantErr: "invalid hostname"},
+		{name: "empty address", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api"}, Hosts: map[string]string{"a.local": ""},
+		}}, wantErr: "address is required"},
+		{name: "compose file outside project", project: ProjectConfig{ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api"}, Hosts: map[string]string{"a.local": "10.0.0.1"}, ComposeFiles: []string{"../compose.yml"},
+		}}, wantErr: "must be relative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := c.validateExtraHosts("project 'backend': extra_hosts", tt.project)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteComposeOverride(t *testing.T) {
+	featureDir := t.TempDir()
+	projectDir := filepath.Join(featureDir, "backend")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	c := &WorktreeConfig{ProjectName: "myapp", Projects: map[string]ProjectConfig{
+		"backend": {Dir: "backend", ExtraHosts: ExtraHostsConfig{
+			Services: []string{"api", "worker"},
+			Hosts: map[string]string{
+				"api.{feature}.local": "host-gateway",
+				"{feature_host}":      "{GATEWAY_IP}",
+			},
+		}},
+		"frontend": {Dir: "frontend"},
+	}}
+
+	path, err := c.WriteComposeOverride(featureDir, "backend", "feature-x", map[string]string{"GATEWAY_IP": "172.17.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{
+		"services:\n    api:\n        extra_hosts:\n",
+		"api.feature-x.local: host-gateway",
+		"myapp-feature-x.local: 172.17.0.1",
+		"    worker:\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("override missing %q:\n%s", want, content)
+		}
+	}
+
+	// No compose file yet: the override cannot be layered on anything
+	if _, err := c.ComposeFiles(featureDir, "backend"); err == nil || !strings.Contains(err.Error(), "no compose file found") {
+		t.Errorf("expected missing compose file error, got %v", err)
+	}
+
+	for _, name := range []string{"docker-compose.yml", "docker-compose.override.yml"} {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte("services: {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := c.ComposeFiles(featureDir, "backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(projectDir, "docker-compose.yml"),
+		filepath.Join(projectDir, "docker-compose.override.yml"),
+		path,
+	}
+	if !slices.Equal(files, want) {
+		t.Errorf("ComposeFiles() = %v, want %v", files, want)
+	}
+
+	if files, err := c.ComposeFiles(featureDir, "frontend"); err != nil || files != nil {
+		t.Errorf("expected no compose files without extra_hosts, got %v, %v", files, err)
+	}
+
+	// Dropping extra_hosts removes the override
+	c.Projects["backend"] = ProjectConfig{Dir: "backend"}
+	if _, err := c.WriteComposeOverride(featureDir, "backend", "feature-x", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected override to be removed, stat err = %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Feature flags file formats for feature_flags.format
+const (
+	FlagsFormatJSON = "json"
+	FlagsFormatYAML = "yaml"
+)
+
+const flagsFile = ".worktree-flags.yml"
+
+// FeatureFlagsConfig renders a flags file into project worktrees that services
+// read at runtime, so flags can change without env vars and restarts
+type FeatureFlagsConfig struct {
+	Path          string            `yaml:"path"`           // File written into each project worktree, relative to the project dir
+	Format        string            `yaml:"format"`         // "json" or "yaml" (default: from the path's extension, else json)
+	Projects      []string          `yaml:"projects"`       // Projects that get the file (default: every project of the feature)
+	ReloadCommand string            `yaml:"reload_command"` // Runs in each project worktree after 'worktree flags set' (e.g. send SIGHUP)
+	Flags         map[string]string `yaml:"flags"`          // Default values; {KEY} and {host:KEY} placeholders are resolved
+}
+
+// Enabled reports whether a flags file is rendered
+func (f *FeatureFlagsConfig) Enabled() bool {
+	return f.Path != ""
+}
+
+// GetFormat returns the flags file format
+func (f *FeatureFlagsConfig) GetFormat() string {
+	if f.Format != "" {
+		return f.Format
+	}
+	switch filepath.Ext(f.Path) {
+	case ".yml", ".yaml":
+		return FlagsFormatYAML
+	default:
+		return FlagsFormatJSON
+	}
+}
+
+// FlagProjects returns the feature's projects that get the flags file
+func (f *FeatureFlagsConfig) FlagProjects(featureProjects []string) []string {
+	if len(f.Projects) == 0 {
+		return featureProjects
+	}
+	var projects []string
+	for _, project := range featureProjects {
+		for _, name := range f.Projects {
+			if project == name {
+				projects = append(projects, project)
+				break
+			}
+		}
+	}
+	return projects
+}
+
+// validateFeatureFlags checks the feature_flags section
+func (c *WorktreeConfig) validateFeatureFlags() error {
+	f := c.FeatureFlags
+	if !f.Enabled() {
+		if len(f.Flags) > 0 || f.ReloadCommand != "" {
+			return fmt.Errorf("feature_flags: path is required")
+		}
+		return nil
+	}
+	if filepath.IsAbs(f.Path) || strings.HasPrefix(filepath.Clean(f.Path), "..") {
+		return fmt.Errorf("feature_flags: path '%s' must be relative to the project directory", f.Path)
+	}
+	switch f.Format {
+	case "", FlagsFormatJSON, FlagsFormatYAML:
+	default:
+		return fmt.Errorf("feature_flags: format must be '%s' or '%s', got '%s'", FlagsFormatJSON, FlagsFormatYAML, f.Format)
+	}
+	for _, project := range f.Projects {
+		if _, ok := c.Projects[project]; !ok {
+			return fmt.Errorf("feature_flags.projects: unknown project '%s'", project)
+		}
+	}
+	for key, value := range f.Flags {
+		if err := validateFlagKey(key); err != nil {
+			return fmt.Errorf("feature_flags.flags: %w", err)
+		}
+		if err := c.validateHostRefs(fmt.Sprintf("feature_flags.flags.%s", key), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateFlagKey checks that a flag name can be set as KEY=VALUE
+func validateFlagKey(key string) error {
+	if key == "" || strings.ContainsAny(key, "= \t\n") {
+		return fmt.Errorf("invalid flag name '%s'", key)
+	}
+	return nil
+}
+
+// FlagsPath returns the path of the per-feature runtime flag values
+func FlagsPath(featureDir string) string {
+	return filepath.Join(featureDir, flagsFile)
+}
+
+// ReadFlags reads the flag values set with 'worktree flags set'.
+// A missing file is not an error and yields an empty map.
+func ReadFlags(featureDir string) (map[string]string, error) {
+	flags := make(map[string]string)
+
+	data, err := os.ReadFile(FlagsPath(featureDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return flags, nil
+		}
+		return nil, fmt.Errorf("failed to read flags file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("failed to parse flags file: %w", err)
+	}
+	if flags == nil {
+		flags = make(map[string]string)
+	}
+	return flags, nil
+}
+
+// WriteFlags writes the feature's runtime flag values. An empty map removes the file.
+func WriteFlags(featureDir string, flags map[string]string) error {
+	path := FlagsPath(featureDir)
+
+	if len(flags) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove flags file: %w", err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(flags)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flags: %w", err)
+	}
+
+	header := "# Per-feature flag values (managed by 'worktree flags')\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write flags file: %w", err)
+	}
+	return nil
+}
+
+// ResolveFlags merges the configured defaults with the feature's runtime
+// values and resolves placeholders. Values that parse as booleans or numbers
+// are typed, so services read `false` rather than "false".
+func (c *WorktreeConfig) ResolveFlags(featureDir string, envVars map[string]string) (map[string]any, error) {
+	runtime, err := ReadFlags(featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]any, len(c.FeatureFlags.Flags)+len(runtime))
+	for key, value := range c.FeatureFlags.Flags {
+		flags[key] = typedFlag(c.resolveHostRefs(substituteVars(value, envVars)))
+	}
+	for key, value := range runtime {
+		flags[key] = typedFlag(value)
+	}
+	return flags, nil
+}
+
+// flagNumberRe matches decimal numbers (ParseFloat also accepts Inf, NaN and hex)
+var flagNumberRe = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// typedFlag converts a flag value to a bool or number when it is one
+func typedFlag(value string) any {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && flagNumberRe.MatchString(value) {
+		return f
+	}
+	return value
+}
+
+// WriteFlagsFiles renders the flags file into the feature's project worktrees
+// and returns the written paths, relative to the feature dir. It does nothing
+// when feature_flags is not configured.
+func (c *WorktreeConfig) WriteFlagsFiles(featureDir string, projects []string, envVars map[string]string) ([]string, error) {
+	if !c.FeatureFlags.Enabled() {
+		return nil, nil
+	}
+
+	flags, err := c.ResolveFlags(featureDir, envVars)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if c.FeatureFlags.GetFormat() == FlagsFormatYAML {
+		data, err = yaml.Marshal(flags)
+	} else {
+		data, err = json.MarshalIndent(flags, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render feature flags: %w", err)
+	}
+
+	var written []string
+	for _, projectName := range c.FeatureFlags.FlagProjects(projects) {
+		project, ok := c.Projects[projectName]
+		if !ok {
+			continue
+		}
+		target := filepath.Join(project.Dir, c.FeatureFlags.Path)
+		path := filepath.Join(featureDir, target)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		written = append(written, target)
+	}
+	return written, nil
+}
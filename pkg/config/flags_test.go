@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteFlagsFiles(t *testing.T) {
+	featureDir := t.TempDir()
+	for _, dir := range []string{"backend", "frontend"} {
+		if err := os.Mkdir(filepath.Join(featureDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &WorktreeConfig{
+		Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}, "frontend": {Dir: "frontend"}},
+		FeatureFlags: FeatureFlagsConfig{
+			Path:     "config/flags.json",
+			Projects: []string{"backend"},
+			Flags: map[string]string{
+				"new_checkout": "false",
+				"rate_limit":   "100",
+				"api_url":      "http://localhost:{BE_PORT}",
+				"version":      "1.10",
+				"zip":          "0x10",
+			},
+		},
+	}
+	if err := WriteFlags(featureDir, map[string]string{"new_checkout": "true"}); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := cfg.WriteFlagsFiles(featureDir, []string{"backend", "frontend"}, map[string]string{"BE_PORT": "8081"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 1 || written[0] != "backend/config/flags.json" {
+		t.Fatalf("written = %v, want only the backend file", written)
+	}
+
+	data, err := os.ReadFile(filepath.Join(featureDir, "backend", "config", "flags.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flags map[string]any
+	if err := json.Unmarshal(data, &flags); err != nil {
+		t.Fatalf("invalid JSON %q: %v", data, err)
+	}
+	want := map[string]any{"new_checkout": true, "rate_limit": 100.0, "api_url": "http://localhost:8081", "version": 1.1, "zip": "0x10"}
+	for key, value := range want {
+		if flags[key] != value {
+			t.Errorf("%s = %#v, want %#v", key, flags[key], value)
+		}
+	}
+
+	// Clearing the runtime values removes the file and restores the defaults
+	if err := WriteFlags(featureDir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(FlagsPath(featureDir)); !os.IsNotExist(err) {
+		t.Errorf("flags file not removed: %v", err)
+	}
+	resolved, err := cfg.ResolveFlags(featureDir, nil)
+	if err != nil || resolved["new_checkout"] != false {
+		t.Errorf("new_checkout = %v (%v), want default false", resolved["new_checkout"], err)
+	}
+}
+
+func TestValidateFeatureFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   FeatureFlagsConfig
+		wantErr string
+	}{
+		{name: "disabled", flags: FeatureFlagsConfig{}},
+		{name: "valid", flags: FeatureFlagsConfig{Path: "flags.yml", Projects: []string{"backend"}, Flags: map[string]string{"a": "1"}}},
+		{name: "flags without path", flags: FeatureFlagsConfig{Flags: map[string]string{"a": "1"}}, wantErr: "path is required"},
+		{name: "absolute path", flags: FeatureFlagsConfig{Path: "/etc/flags.json"}, wantErr: "must be relative"},
+		{name: "escaping path", flags: FeatureFlagsConfig{Path: "../flags.json"}, wantErr: "must be relative"},
+		{name: "unknown format", flags: FeatureFlagsConfig{Path: "flags", Format: "toml"}, wantErr: "format must be"},
+		{name: "unknown project", flags: FeatureFlagsConfig{Path: "flags.json", Projects: []string{"api"}}, wantErr: "unknown project 'api'"},
+		{name: "bad key", flags: FeatureFlagsConfig{Path: "flags.json", Flags: map[string]string{"a b": "1"}}, wantErr: "invalid flag name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &WorktreeConfig{Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}}, FeatureFlags: tt.flags}
+			err := cfg.validateFeatureFlags()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := (&FeatureFlagsConfig{Path: "flags.yaml"}).GetFormat(); got != FlagsFormatYAML {
+		t.Errorf("GetFormat() = %s, want yaml", got)
+	}
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenRuns is how often generation is repeated; map iteration order differs
+// between runs, so order-dependent output shows up as a mismatch
+const goldenRuns = 20
+
+// assertGolden compares got with testdata/golden/<name>, or rewrites the file with -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+// TestGoldenGeneration checks that env vars and generated files are
+// byte-identical for identical inputs, also when value vars reference each other.
+func TestGoldenGeneration(t *testing.T) {
+	cfg, err := LoadWorktreeConfig(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for run := 0; run < goldenRuns; run++ {
+		envVars := cfg.ExportEnvVars(2)
+		// Allocated port from the registry, applied the way new-feature does
+		envVars["BE_PORT"] = "8082"
+		cfg.ResolveValueVars(2, envVars)
+
+		var env strings.Builder
+		for _, key := range sortedEnvKeys(envVars) {
+			fmt.Fprintf(&env, "%s=%s\n", key, envVars[key])
+		}
+
+		featureDir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(featureDir, "backend"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := cfg.GenerateFiles("backend", featureDir, envVars); err != nil {
+			t.Fatal(err)
+		}
+		generated, err := os.ReadFile(filepath.Join(featureDir, "backend", ".env.local"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertGolden(t, "env.golden", []byte(env.String()))
+		assertGolden(t, "backend.env.local.golden", generated)
+		if t.Failed() {
+			t.Fatalf("output differs from golden files on run %d", run+1)
+		}
+	}
+}
+
+func sortedEnvKeys(envVars map[string]string) []string {
+	keys := make([]string, 0, len(envVars))
+	for key := range envVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
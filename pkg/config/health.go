@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Defaults for health_checks
+const (
+	DefaultHealthTimeout  = 60 * time.Second
+	DefaultHealthInterval = 2 * time.Second
+)
+
+// HealthCheck is a readiness probe for a project's services. Exactly one of
+// url, tcp and command is set; url and tcp may use {KEY}, {host} and
+// {host:KEY} placeholders.
+type HealthCheck struct {
+	Name     string `yaml:"name"`
+	URL      string `yaml:"url"`      // Healthy when a GET returns a 2xx or 3xx status
+	TCP      string `yaml:"tcp"`      // host:port that accepts connections
+	Command  string `yaml:"command"`  // Healthy when it exits 0; runs in the project worktree with the feature's env
+	Timeout  string `yaml:"timeout"`  // How long to wait for the service to become healthy (default: 60s)
+	Interval string `yaml:"interval"` // Delay between attempts (default: 2s)
+}
+
+// Label returns the check's name, or what it probes when unnamed
+func (h HealthCheck) Label() string {
+	switch {
+	case h.Name != "":
+		return h.Name
+	case h.URL != "":
+		return h.URL
+	case h.TCP != "":
+		return "tcp " + h.TCP
+	default:
+		return h.Command
+	}
+}
+
+// GetTimeout returns how long to wait for the check to pass
+func (h HealthCheck) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil {
+		return d
+	}
+	return DefaultHealthTimeout
+}
+
+// GetInterval returns the delay between attempts
+func (h HealthCheck) GetInterval() time.Duration {
+	if d, err := time.ParseDuration(h.Interval); err == nil {
+		return d
+	}
+	return DefaultHealthInterval
+}
+
+// ResolveHealthCheck substitutes a feature's env vars and hosts into the
+// check's url and tcp target
+func (c *WorktreeConfig) ResolveHealthCheck(h HealthCheck, envVars map[string]string) HealthCheck {
+	resolve := func(s string) string {
+		s = c.resolveHostRefs(s)
+		s = strings.ReplaceAll(s, "{host}", c.Hostname)
+		return substituteVars(s, envVars)
+	}
+	h.URL = resolve(h.URL)
+	h.TCP = resolve(h.TCP)
+	return h
+}
+
+// validateHealthCheck checks that a health check probes exactly one thing and
+// has valid durations
+func (c *WorktreeConfig) validateHealthCheck(where string, h HealthCheck) error {
+	set := 0
+	for _, target := range []string{h.URL, h.TCP, h.Command} {
+		if target != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%s: exactly one of url, tcp or command is required", where)
+	}
+	if h.URL != "" && !strings.HasPrefix(h.URL, "http://") && !strings.HasPrefix(h.URL, "https://") {
+		return fmt.Errorf("%s: url '%s' must start with http:// or https://", where, h.URL)
+	}
+	for field, value := range map[string]string{"timeout": h.Timeout, "interval": h.Interval} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("%s: invalid %s '%s' (expected a positive duration like 30s)", where, field, value)
+		}
+	}
+	return c.validateHostRefs(where, h.URL+h.TCP)
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateHealthCheck(t *testing.T) {
+	cfg := &WorktreeConfig{EnvVariables: map[string]EnvVarConfig{"BE_PORT": {Port: "8080", Env: "BE_PORT"}}}
+	tests := []struct {
+		name    string
+		check   HealthCheck
+		wantErr string
+	}{
+		{name: "url", check: HealthCheck{URL: "http://{host}:{BE_PORT}/health", Timeout: "30s"}},
+		{name: "tcp", check: HealthCheck{TCP: "{host:BE_PORT}:{BE_PORT}"}},
+		{name: "command", check: HealthCheck{Command: "pg_isready", Interval: "500ms"}},
+		{name: "none", check: HealthCheck{Name: "x"}, wantErr: "exactly one"},
+		{name: "two", check: HealthCheck{URL: "http://localhost", TCP: "localhost:1"}, wantErr: "exactly one"},
+		{name: "bad scheme", check: HealthCheck{URL: "localhost:8080"}, wantErr: "must start with http"},
+		{name: "bad timeout", check: HealthCheck{Command: "true", Timeout: "soon"}, wantErr: "invalid timeout"},
+		{name: "unknown host ref", check: HealthCheck{TCP: "{host:NOPE}:1"}, wantErr: "undefined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.validateHealthCheck("health_checks[0]", tt.check)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveHealthCheck(t *testing.T) {
+	cfg := &WorktreeConfig{
+		Hostname:     "dev.local",
+		EnvVariables: map[string]EnvVarConfig{"DB_PORT": {Port: "5432", Env: "DB_PORT", Host: "db.internal"}},
+	}
+	check := cfg.ResolveHealthCheck(HealthCheck{URL: "http://{host}:{APP_PORT}/health", TCP: "{host:DB_PORT}:{DB_PORT}"},
+		map[string]string{"APP_PORT": "8081", "DB_PORT": "5433"})
+	if check.URL != "http://dev.local:8081/health" || check.TCP != "db.internal:5433" {
+		t.Errorf("resolved = %q, %q", check.URL, check.TCP)
+	}
+
+	if got := (HealthCheck{}).GetTimeout(); got != DefaultHealthTimeout {
+		t.Errorf("default timeout = %s", got)
+	}
+	if got := (HealthCheck{Interval: "250ms"}).GetInterval(); got != 250*time.Millisecond {
+		t.Errorf("interval = %s", got)
+	}
+}
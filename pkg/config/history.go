@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHistoryMaxRecords is how many agent executions the history keeps
+// when history.max_records is not set
+const DefaultHistoryMaxRecords = 1000
+
+// HistoryConfig is the retention policy of the agent execution history
+// (worktrees/.history.json, or the database with storage: sqlite), enforced
+// whenever a run is recorded
+type HistoryConfig struct {
+	MaxRecords int    `yaml:"max_records"` // Executions kept, oldest dropped first (default: 1000)
+	MaxAge     string `yaml:"max_age"`     // Executions older than this are dropped, e.g. "90d" (default: no limit)
+}
+
+// GetMaxRecords returns how many executions the history keeps
+func (h *HistoryConfig) GetMaxRecords() int {
+	if h.MaxRecords <= 0 {
+		return DefaultHistoryMaxRecords
+	}
+	return h.MaxRecords
+}
+
+// GetMaxAge returns the age after which executions are dropped; 0 keeps them
+func (h *HistoryConfig) GetMaxAge() time.Duration {
+	age, _ := ParseAge(h.MaxAge) // Validated on load
+	return age
+}
+
+// validate checks the history: section
+func (h *HistoryConfig) validate() error {
+	if h.MaxRecords < 0 {
+		return fmt.Errorf("history.max_records: invalid value %d (expected a number of executions)", h.MaxRecords)
+	}
+	if h.MaxAge != "" {
+		if _, err := ParseAge(h.MaxAge); err != nil {
+			return fmt.Errorf("history.max_age: %w", err)
+		}
+	}
+	return nil
+}
+
+// ParseAge parses durations with day and week units (14d, 2w) in addition to
+// everything time.ParseDuration accepts (36h)
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age '%s'", s)
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age '%s' (use e.g. 14d, 2w or 36h)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Failure policies for hooks.<hook>.on_failure
+const (
+	HookOnFailureWarn  = "warn"  // Print a warning and carry on (default)
+	HookOnFailureAbort = "abort" // Stop the command with an error
+	HookOnFailureRetry = "retry" // Run the hook again, then warn if it still fails
+)
+
+// DefaultHookRetries is how often a retry hook is run again after failing
+const DefaultHookRetries = 2
+
+// HookNames are the keys of a project's hooks section, one per lifecycle
+// command (start_pre is start_pre_command, and so on)
+var HookNames = []string{"start_pre", "start_post", "stop_pre", "stop_post", "restart_pre", "restart_post"}
+
+// HookPolicy limits how long a lifecycle hook may run and what happens when it
+// fails or times out
+type HookPolicy struct {
+	Timeout   string `yaml:"timeout"`    // Kill the hook after this long (default: no limit)
+	OnFailure string `yaml:"on_failure"` // "warn" (default), "abort" or "retry"
+	Retries   int    `yaml:"retries"`    // Extra attempts for on_failure: retry (default: 2)
+}
+
+// Hook returns the policy of a lifecycle hook, e.g. Hook("start_post")
+func (p *ProjectConfig) Hook(name string) HookPolicy {
+	return p.Hooks[name]
+}
+
+// GetTimeout returns how long the hook may run, or 0 for no limit
+func (h HookPolicy) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil {
+		return d
+	}
+	return 0
+}
+
+// GetOnFailure returns the failure policy, defaulting to warn
+func (h HookPolicy) GetOnFailure() string {
+	if h.OnFailure == "" {
+		return HookOnFailureWarn
+	}
+	return h.OnFailure
+}
+
+// Attempts returns how often the hook is run before its failure is final
+func (h HookPolicy) Attempts() int {
+	if h.GetOnFailure() != HookOnFailureRetry {
+		return 1
+	}
+	if h.Retries > 0 {
+		return 1 + h.Retries
+	}
+	return 1 + DefaultHookRetries
+}
+
+// validateHooks checks a project's hooks section
+func validateHooks(where string, hooks map[string]HookPolicy) error {
+	for name, h := range hooks {
+		if !slices.Contains(HookNames, name) {
+			return fmt.Errorf("%s: unknown hook '%s' (expected one of %s)", where, name, strings.Join(HookNames, ", "))
+		}
+		if h.Timeout != "" {
+			if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("%s.%s: invalid timeout '%s' (expected a positive duration like 5m)", where, name, h.Timeout)
+			}
+		}
+		switch h.OnFailure {
+		case "", HookOnFailureWarn, HookOnFailureAbort, HookOnFailureRetry:
+		default:
+			return fmt.Errorf("%s.%s: unknown on_failure '%s' (expected warn, abort or retry)", where, name, h.OnFailure)
+		}
+		if h.Retries < 0 {
+			return fmt.Errorf("%s.%s: retries cannot be negative", where, name)
+		}
+		if h.Retries > 0 && h.OnFailure != HookOnFailureRetry {
+			return fmt.Errorf("%s.%s: retries requires on_failure: retry", where, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHookPolicy(t *testing.T) {
+	var none HookPolicy
+	if none.GetTimeout() != 0 || none.GetOnFailure() != HookOnFailureWarn || none.Attempts() != 1 {
+		t.Errorf("zero policy = timeout %v, on_failure %q, %d attempts", none.GetTimeout(), none.GetOnFailure(), none.Attempts())
+	}
+
+	retry := HookPolicy{Timeout: "2m", OnFailure: HookOnFailureRetry}
+	if retry.GetTimeout() != 2*time.Minute {
+		t.Errorf("GetTimeout() = %v, want 2m", retry.GetTimeout())
+	}
+	if retry.Attempts() != 1+DefaultHookRetries {
+		t.Errorf("Attempts() = %d, want %d", retry.Attempts(), 1+DefaultHookRetries)
+	}
+	retry.Retries = 4
+	if retry.Attempts() != 5 {
+		t.Errorf("Attempts() = %d, want 5", retry.Attempts())
+	}
+}
+
+func TestValidateHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   map[string]HookPolicy
+		wantErr string
+	}{
+		{name: "valid", hooks: map[string]HookPolicy{
+			"start_post": {Timeout: "5m", OnFailure: "retry", Retries: 3},
+			"stop_pre":   {OnFailure: "abort"},
+		}},
+		{name: "unknown hook", hooks: map[string]HookPolicy{"start": {}}, wantErr: "unknown hook 'start'"},
+		{name: "bad timeout", hooks: map[string]HookPolicy{"start_pre": {Timeout: "soon"}}, wantErr: "invalid timeout"},
+		{name: "bad policy", hooks: map[string]HookPolicy{"start_pre": {OnFailure: "ignore"}}, wantErr: "unknown on_failure"},
+		{name: "retries without retry", hooks: map[string]HookPolicy{"start_pre": {Retries: 1}}, wantErr: "requires on_failure: retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHooks("project 'backend': hooks", tt.hooks)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Defaults for the hosts: section
+const (
+	DefaultHostsFile    = "/etc/hosts"
+	DefaultHostsDomain  = "local"
+	DefaultHostsAddress = "127.0.0.1"
+)
+
+// hostsMarker prefixes the comment lines around a feature's hosts block
+const hostsMarker = "worktree"
+
+// HostsConfig registers a hostname per feature in a hosts file, so URLs
+// (e.g. OAuth redirect URIs) can use a stable name instead of an instance port
+type HostsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	File    string `yaml:"file"`    // Hosts file to manage (default: /etc/hosts)
+	Domain  string `yaml:"domain"`  // Domain of feature hostnames (default: local)
+	Address string `yaml:"address"` // Address the hostnames resolve to (default: 127.0.0.1)
+}
+
+// GetFile returns the managed hosts file
+func (h *HostsConfig) GetFile() string {
+	if h.File == "" {
+		return DefaultHostsFile
+	}
+	return h.File
+}
+
+// GetAddress returns the address feature hostnames resolve to
+func (h *HostsConfig) GetAddress() string {
+	if h.Address == "" {
+		return DefaultHostsAddress
+	}
+	return h.Address
+}
+
+// FeatureHost returns the stable hostname of a feature, e.g.
+// myproject-feature-user-auth.local; value templates use it as {feature_host}
+func (c *WorktreeConfig) FeatureHost(featureName string) string {
+	domain := c.Hosts.Domain
+	if domain == "" {
+		domain = DefaultHostsDomain
+	}
+	return fmt.Sprintf("%s-%s.%s", c.ProjectName, featureName, domain)
+}
+
+// FeatureHostnames returns the hostnames registered for a feature: its
+// feature host, plus its proxy hostnames unless they are under .localhost,
+// which resolves without a hosts entry
+func (c *WorktreeConfig) FeatureHostnames(featureName string) []string {
+	hostnames := []string{c.FeatureHost(featureName)}
+	if c.Proxy.Enabled() {
+		for _, route := range c.Proxy.Routes {
+			host := c.proxyHost(featureName, route)
+			if host != "localhost" && !strings.HasSuffix(host, ".localhost") {
+				hostnames = append(hostnames, host)
+			}
+		}
+	}
+	return hostnames
+}
+
+// UpdateHostsFile rewrites the marked blocks of this project in a hosts file.
+// Every feature in features gets a block with its hostnames (no block for nil),
+// in place of its previous block or appended; blocks of features not listed
+// are kept, or dropped when prune is set. Lines outside the blocks are never
+// changed. It reports whether the file changed.
+func (c *WorktreeConfig) UpdateHostsFile(features map[string][]string, prune bool) (bool, error) {
+	path := c.Hosts.GetFile()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	original := string(data)
+
+	block := func(feature string) []string {
+		return []string{
+			fmt.Sprintf("# BEGIN %s %s/%s", hostsMarker, c.ProjectName, feature),
+			fmt.Sprintf("%s %s", c.Hosts.GetAddress(), strings.Join(features[feature], " ")),
+			fmt.Sprintf("# END %s %s/%s", hostsMarker, c.ProjectName, feature),
+		}
+	}
+
+	prefix := fmt.Sprintf("# BEGIN %s %s/", hostsMarker, c.ProjectName)
+	written := make(map[string]bool)
+	var kept []string
+	var skipUntil string
+	for _, line := range strings.Split(strings.TrimRight(original, "\n"), "\n") {
+		if skipUntil != "" {
+			if line == skipUntil {
+				skipUntil = ""
+			}
+			continue
+		}
+		if feature, ok := strings.CutPrefix(line, prefix); ok {
+			if _, replaced := features[feature]; replaced || prune {
+				skipUntil = fmt.Sprintf("# END %s %s/%s", hostsMarker, c.ProjectName, feature)
+				if len(features[feature]) > 0 && !written[feature] {
+					kept = append(kept, block(feature)...)
+					written[feature] = true
+				} else if len(kept) > 0 && kept[len(kept)-1] == "" {
+					kept = kept[:len(kept)-1] // Drop the blank line separating the block
+				}
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	if skipUntil != "" {
+		// Never drop the rest of the file because an end marker was removed
+		return false, fmt.Errorf("%s: missing '%s'", path, skipUntil)
+	}
+	for len(kept) > 0 && kept[len(kept)-1] == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	names := make([]string, 0, len(features))
+	for feature, hostnames := range features {
+		if len(hostnames) > 0 && !written[feature] {
+			names = append(names, feature)
+		}
+	}
+	sort.Strings(names)
+	for _, feature := range names {
+		kept = append(kept, "")
+		kept = append(kept, block(feature)...)
+	}
+
+	content := strings.Join(kept, "\n") + "\n"
+	if strings.TrimSpace(strings.Join(kept, "\n")) == "" {
+		content = ""
+	}
+	if content == original {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateHostsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	original := "127.0.0.1 localhost\n::1 localhost\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &WorktreeConfig{ProjectName: "myproject", Hosts: HostsConfig{Enabled: true, File: path}}
+	read := func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	changed, err := cfg.UpdateHostsFile(map[string][]string{
+		"feature-a": cfg.FeatureHostnames("feature-a"),
+		"feature-b": cfg.FeatureHostnames("feature-b"),
+	}, false)
+	if err != nil || !changed {
+		t.Fatalf("UpdateHostsFile() = %v, %v", changed, err)
+	}
+	want := original + `
+# BEGIN worktree myproject/feature-a
+127.0.0.1 myproject-feature-a.local
+# END worktree myproject/feature-a
+
+# BEGIN worktree myproject/feature-b
+127.0.0.1 myproject-feature-b.local
+# END worktree myproject/feature-b
+`
+	if got := read(); got != want {
+		t.Errorf("hosts file =\n%s\nwant\n%s", got, want)
+	}
+
+	// Rewriting the same blocks is a no-op
+	if changed, err := cfg.UpdateHostsFile(map[string][]string{"feature-a": {"myproject-feature-a.local"}}, false); err != nil || changed {
+		t.Errorf("unchanged block rewritten: %v, %v", changed, err)
+	}
+
+	// Blocks of other projects survive pruning
+	other := &WorktreeConfig{ProjectName: "other", Hosts: HostsConfig{File: path}}
+	if _, err := other.UpdateHostsFile(map[string][]string{"feature-a": {"other-feature-a.local"}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.UpdateHostsFile(map[string][]string{"feature-b": cfg.FeatureHostnames("feature-b")}, true); err != nil {
+		t.Fatal(err)
+	}
+	got := read()
+	if strings.Contains(got, "myproject-feature-a.local") || !strings.Contains(got, "myproject-feature-b.local") || !strings.Contains(got, "other-feature-a.local") {
+		t.Errorf("unexpected hosts file after prune:\n%s", got)
+	}
+
+	// nil hostnames remove a block
+	if _, err := cfg.UpdateHostsFile(map[string][]string{"feature-b": nil}, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.UpdateHostsFile(nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != original {
+		t.Errorf("hosts file not restored:\n%s", got)
+	}
+
+	broken := original + "# BEGIN worktree myproject/feature-a\n127.0.0.1 myproject-feature-a.local\n"
+	if err := os.WriteFile(path, []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.UpdateHostsFile(nil, true); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected missing end marker error, got %v", err)
+	}
+	if got := read(); got != broken {
+		t.Error("hosts file changed despite a missing end marker")
+	}
+}
+
+func TestFeatureHostnames(t *testing.T) {
+	cfg := &WorktreeConfig{
+		ProjectName: "myproject",
+		Hosts:       HostsConfig{Domain: "test"},
+		EnvVariables: map[string]EnvVarConfig{
+			"FE_PORT":      {Port: "3000", Env: "FE_PORT"},
+			"REDIRECT_URI": {Value: "http://{feature_host}/callback", Env: "REDIRECT_URI"},
+		},
+		Proxy: ProxyConfig{Provider: ProxyProviderTraefik, Dir: "proxy", Domain: "dev.test", Routes: []ProxyRoute{{Port: "FE_PORT"}}},
+	}
+
+	got := cfg.FeatureHostnames("feature-x")
+	if strings.Join(got, " ") != "myproject-feature-x.test feature-x.dev.test" {
+		t.Errorf("FeatureHostnames() = %v", got)
+	}
+	cfg.Proxy.Domain = ""
+	if got := cfg.FeatureHostnames("feature-x"); len(got) != 1 {
+		t.Errorf(".localhost proxy hosts should not be listed: %v", got)
+	}
+
+	envVars := cfg.ExportEnvVars(1)
+	envVars["FEATURE_NAME"] = "feature-x"
+	cfg.ResolveValueVars(1, envVars)
+	if envVars["REDIRECT_URI"] != "http://myproject-feature-x.test/callback" {
+		t.Errorf("REDIRECT_URI = %q", envVars["REDIRECT_URI"])
+	}
+}
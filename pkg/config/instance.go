@@ -13,6 +13,12 @@ const envFile = ".worktree-env.json"
 
 const instanceMarkerFile = ".worktree-instance"
 
+// FeatureStateFiles lists the files worktree keeps in a feature directory
+// (instance marker, resolved env vars, per-feature overrides)
+func FeatureStateFiles() []string {
+	return []string{instanceMarkerFile, envFile, overridesFile}
+}
+
 // InstanceContext represents the worktree instance metadata
 type InstanceContext struct {
 	Feature      string         `json:"feature"`
@@ -22,6 +28,7 @@ type InstanceContext struct {
 	Projects     []string       `json:"projects"`
 	Ports        map[string]int `json:"ports"`
 	YoloMode     bool           `json:"yolo_mode"`
+	InstanceEnv  []string       `json:"instance_env,omitempty"` // Env var names carrying the instance number (empty means INSTANCE)
 	CreatedAt    string         `json:"created_at"`
 }
 
@@ -88,16 +95,7 @@ func WriteInstanceMarker(featureDir string, feature string, instance int, projec
 		CreatedAt:    time.Now().Format(time.RFC3339),
 	}
 
-	data, err := json.MarshalIndent(ctx, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal instance context: %w", err)
-	}
-
-	if err := os.WriteFile(markerPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write instance marker: %w", err)
-	}
-
-	return nil
+	return saveInstanceMarker(markerPath, &ctx)
 }
 
 // RemoveInstanceMarker deletes the .worktree-instance file from the feature directory
@@ -111,10 +109,30 @@ func RemoveInstanceMarker(featureDir string) error {
 	if err := os.Remove(markerPath); err != nil {
 		return fmt.Errorf("failed to remove instance marker: %w", err)
 	}
+	removeChecksumFiles(markerPath)
+
+	return nil
+}
+
+// saveInstanceMarker writes an instance marker with its checksum
+func saveInstanceMarker(markerPath string, ctx *InstanceContext) error {
+	data, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal instance context: %w", err)
+	}
+
+	if err := WriteFileChecksummed(markerPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write instance marker: %w", err)
+	}
 
 	return nil
 }
 
+// InstanceMarkerPath returns the path of a feature's .worktree-instance file
+func InstanceMarkerPath(featureDir string) string {
+	return filepath.Join(featureDir, instanceMarkerFile)
+}
+
 // UpdateInstanceYoloMode updates the yolo_mode field in the .worktree-instance file
 func UpdateInstanceYoloMode(featureDir string, yoloMode bool) error {
 	markerPath := filepath.Join(featureDir, instanceMarkerFile)
@@ -126,21 +144,49 @@ func UpdateInstanceYoloMode(featureDir string, yoloMode bool) error {
 
 	ctx.YoloMode = yoloMode
 
-	data, err := json.MarshalIndent(ctx, "", "  ")
+	return saveInstanceMarker(markerPath, ctx)
+}
+
+// UpdateInstanceEnv records the env var names that carry the instance number in the .worktree-instance file
+func UpdateInstanceEnv(featureDir string, names []string) error {
+	markerPath := filepath.Join(featureDir, instanceMarkerFile)
+
+	ctx, err := loadInstanceMarker(markerPath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal instance context: %w", err)
+		return err
 	}
 
-	if err := os.WriteFile(markerPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write instance marker: %w", err)
+	ctx.InstanceEnv = names
+
+	return saveInstanceMarker(markerPath, ctx)
+}
+
+// RenameInstanceProject renames a project in the .worktree-instance marker's project list
+func RenameInstanceProject(featureDir, oldName, newName string) error {
+	markerPath := filepath.Join(featureDir, instanceMarkerFile)
+
+	ctx, err := loadInstanceMarker(markerPath)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	for i, name := range ctx.Projects {
+		if name == oldName {
+			ctx.Projects[i] = newName
+		}
+	}
+
+	return saveInstanceMarker(markerPath, ctx)
+}
+
+// EnvFilePath returns the path of a feature's .worktree-env.json
+func EnvFilePath(featureDir string) string {
+	return filepath.Join(featureDir, envFile)
 }
 
 // WriteEnvFile writes all computed vars to .worktree-env.json in the feature directory.
 func WriteEnvFile(featureDir string, computedVars map[string]string) error {
-	envPath := filepath.Join(featureDir, envFile)
+	envPath := EnvFilePath(featureDir)
 
 	data, err := json.MarshalIndent(computedVars, "", "  ")
 	if err != nil {
@@ -272,6 +272,53 @@ func TestUpdateInstanceYoloModeMissingMarker(t *testing.T) {
 	}
 }
 
+func TestUpdateInstanceEnv(t *testing.T) {
+	featureDir := t.TempDir()
+	if err := WriteInstanceMarker(featureDir, "feature-env", 2, "/tmp/project", []string{"backend"}, nil, false); err != nil {
+		t.Fatalf("WriteInstanceMarker failed: %v", err)
+	}
+
+	if err := UpdateInstanceEnv(featureDir, []string{"INSTANCE", "WORKER_ID"}); err != nil {
+		t.Fatalf("UpdateInstanceEnv failed: %v", err)
+	}
+
+	ctx, err := loadInstanceMarker(filepath.Join(featureDir, instanceMarkerFile))
+	if err != nil {
+		t.Fatalf("loadInstanceMarker failed: %v", err)
+	}
+	if len(ctx.InstanceEnv) != 2 || ctx.InstanceEnv[1] != "WORKER_ID" {
+		t.Errorf("InstanceEnv = %v, want [INSTANCE WORKER_ID]", ctx.InstanceEnv)
+	}
+	if ctx.Feature != "feature-env" || ctx.Instance != 2 {
+		t.Error("other marker fields should be preserved")
+	}
+}
+
+func TestUpdateInstanceEnvMissingMarker(t *testing.T) {
+	if err := UpdateInstanceEnv("/tmp/worktree-test-nonexistent", []string{"WORKER_ID"}); err == nil {
+		t.Error("expected error when marker file does not exist")
+	}
+}
+
+func TestRenameInstanceProject(t *testing.T) {
+	featureDir := t.TempDir()
+	if err := WriteInstanceMarker(featureDir, "feature-rename", 1, "/tmp/project", []string{"api", "frontend"}, nil, false); err != nil {
+		t.Fatalf("WriteInstanceMarker failed: %v", err)
+	}
+
+	if err := RenameInstanceProject(featureDir, "api", "backend"); err != nil {
+		t.Fatalf("RenameInstanceProject failed: %v", err)
+	}
+
+	ctx, err := loadInstanceMarker(filepath.Join(featureDir, instanceMarkerFile))
+	if err != nil {
+		t.Fatalf("loadInstanceMarker failed: %v", err)
+	}
+	if len(ctx.Projects) != 2 || ctx.Projects[0] != "backend" || ctx.Projects[1] != "frontend" {
+		t.Errorf("Projects = %v, want [backend frontend]", ctx.Projects)
+	}
+}
+
 func TestDetectInstanceFromDirDeepNesting(t *testing.T) {
 	tmpDir := t.TempDir()
 	featureDir := filepath.Join(tmpDir, "worktrees", "feature-deep")
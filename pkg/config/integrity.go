@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Sidecar files of checksummed state files (.registry.json, .worktree-instance)
+const (
+	checksumSuffix = ".sha256" // Hex SHA-256 of the file's content
+	backupSuffix   = ".bak"    // Last content that matched its checksum
+	lockSuffix     = ".lock"   // Held while a writer replaces the file and its checksum
+)
+
+// checksumLockStaleAfter is how old a lock file must be before it is taken to
+// be left behind by a crashed writer; a write holds it for milliseconds
+const checksumLockStaleAfter = 10 * time.Second
+
+// ErrChecksumMismatch means a state file was edited by hand or only partially written
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// checksumOf returns the hex SHA-256 of data
+func checksumOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChecksum checks data against the checksum stored next to path. Files
+// written before checksums existed have none and always pass. While a write
+// is in progress the checksum file lists the new and the previous content's
+// checksums, so a crash between replacing the file and its checksum leaves
+// content that still passes.
+func VerifyChecksum(path string, data []byte) error {
+	want, err := os.ReadFile(path + checksumSuffix)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read checksum of %s: %w", path, err)
+	}
+	sum := checksumOf(data)
+	for _, line := range strings.Fields(string(want)) {
+		if line == sum {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w (edited by hand or partially written)", path, ErrChecksumMismatch)
+}
+
+// VerifyFile reads path and checks it against its checksum. A missing file passes.
+func VerifyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return VerifyChecksum(path, data)
+}
+
+// HasChecksumBackup reports whether path has a backup that matches its checksum
+func HasChecksumBackup(path string) bool {
+	data, err := os.ReadFile(path + backupSuffix)
+	return err == nil && VerifyChecksum(path+backupSuffix, data) == nil
+}
+
+// WriteFileChecksummed atomically replaces path with data and records its
+// checksum. The previous content is kept as a backup when it still matches
+// its own checksum, so a corrupted file can be restored with RepairChecksummed.
+// Writers of the same path (CLI, agent daemon, serve-status) take turns.
+func WriteFileChecksummed(path string, data []byte, perm os.FileMode) error {
+	unlock, err := lockChecksummed(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	sum := checksumOf(data)
+	pending := sum + "\n"
+	if old, err := os.ReadFile(path); err == nil && VerifyChecksum(path, old) == nil {
+		if !bytes.Equal(old, data) {
+			if err := writeAtomic(path+backupSuffix, old, perm); err != nil {
+				return err
+			}
+			if err := writeAtomic(path+backupSuffix+checksumSuffix, []byte(checksumOf(old)+"\n"), 0644); err != nil {
+				return err
+			}
+		}
+		// The old content keeps passing until the new content is in place
+		pending += checksumOf(old) + "\n"
+	}
+
+	if err := writeAtomic(path+checksumSuffix, []byte(pending), 0644); err != nil {
+		return err
+	}
+	if err := writeAtomic(path, data, perm); err != nil {
+		return err
+	}
+	return writeAtomic(path+checksumSuffix, []byte(sum+"\n"), 0644)
+}
+
+// RepairChecksummed fixes a file that fails its checksum: it accepts the
+// current content when it is valid JSON, so a complete write whose checksum
+// was not updated is kept, and otherwise restores the backup when one
+// matches. It returns what was done.
+func RepairChecksummed(path string) (string, error) {
+	unlock, err := lockChecksummed(path)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err == nil && json.Valid(data) {
+		if err := writeAtomic(path+checksumSuffix, []byte(checksumOf(data)+"\n"), 0644); err != nil {
+			return "", err
+		}
+		return "accepted current content", nil
+	}
+
+	if HasChecksumBackup(path) {
+		backup, err := os.ReadFile(path + backupSuffix)
+		if err != nil {
+			return "", fmt.Errorf("failed to read backup: %w", err)
+		}
+		if err := writeAtomic(path, backup, 0644); err != nil {
+			return "", err
+		}
+		if err := writeAtomic(path+checksumSuffix, []byte(checksumOf(backup)+"\n"), 0644); err != nil {
+			return "", err
+		}
+		return "restored from backup", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return "", fmt.Errorf("%s is not valid JSON and has no backup", path)
+}
+
+// ChecksummedContentParses reports whether path holds valid JSON, which
+// RepairChecksummed accepts instead of restoring the backup
+func ChecksummedContentParses(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && json.Valid(data)
+}
+
+// lockChecksummed takes the lock file of path, waiting while another writer
+// holds it. A lock older than checksumLockStaleAfter was left behind by a
+// crashed writer and is taken over. Returns the func that releases it.
+func lockChecksummed(path string) (func(), error) {
+	lockPath := path + lockSuffix
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > checksumLockStaleAfter {
+			os.Remove(lockPath)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeAtomic writes data to a temp file with a unique name next to path and
+// renames it over path, so concurrent writers never share a temp file
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	tempPath := temp.Name()
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, perm)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// removeChecksumFiles deletes the checksum and backup files of path
+func removeChecksumFiles(path string) {
+	for _, suffix := range []string{checksumSuffix, backupSuffix, backupSuffix + checksumSuffix} {
+		os.Remove(path + suffix)
+	}
+}
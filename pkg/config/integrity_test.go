@@ -0,0 +1,184 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteFileChecksummed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".registry.json")
+
+	for _, content := range []string{`{"v": 1}`, `{"v": 2}`} {
+		if err := WriteFileChecksummed(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := VerifyFile(path); err != nil {
+		t.Fatalf("VerifyFile() after write = %v", err)
+	}
+	if !HasChecksumBackup(path) {
+		t.Fatal("previous version not backed up")
+	}
+
+	// A truncated file fails the check and is repaired from the backup
+	if err := os.WriteFile(path, []byte(`{"v": 3`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(path); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("VerifyFile() after edit = %v, want ErrChecksumMismatch", err)
+	}
+	action, err := RepairChecksummed(path)
+	if err != nil || action != "restored from backup" {
+		t.Fatalf("RepairChecksummed() = %q, %v", action, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"v": 1}` {
+		t.Errorf("restored content = %s, want the backup", data)
+	}
+
+	// Content that still parses, e.g. a complete write whose checksum was not
+	// updated, is kept rather than replaced by the older backup
+	if err := os.WriteFile(path, []byte(`{"v": 3}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	action, err = RepairChecksummed(path)
+	if err != nil || action != "accepted current content" {
+		t.Fatalf("RepairChecksummed() = %q, %v", action, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"v": 3}` {
+		t.Errorf("repaired content = %s, want the current content", data)
+	}
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("VerifyFile() after accepting = %v", err)
+	}
+
+	// A corrupted write never replaces the last good backup
+	if err := os.WriteFile(path, []byte(`{"v": 4`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileChecksummed(path, []byte(`{"v": 5}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path + backupSuffix); string(data) != `{"v": 1}` {
+		t.Errorf("backup = %s, want the last verified content", data)
+	}
+}
+
+func TestRepairChecksummedWithoutBackup(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.json")
+	if err := WriteFileChecksummed(valid, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(valid, []byte(`{"edited": true}`), 0644)
+	if action, err := RepairChecksummed(valid); err != nil || action != "accepted current content" {
+		t.Errorf("RepairChecksummed() = %q, %v", action, err)
+	}
+	if err := VerifyFile(valid); err != nil {
+		t.Errorf("VerifyFile() after accepting = %v", err)
+	}
+
+	broken := filepath.Join(dir, "broken.json")
+	if err := WriteFileChecksummed(broken, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(broken, []byte(`{"trunc`), 0644)
+	if _, err := RepairChecksummed(broken); err == nil || !strings.Contains(err.Error(), "no backup") {
+		t.Errorf("RepairChecksummed() error = %v, want no backup", err)
+	}
+
+	// Files written before checksums existed always pass
+	legacy := filepath.Join(dir, "legacy.json")
+	os.WriteFile(legacy, []byte(`{}`), 0644)
+	if err := VerifyFile(legacy); err != nil {
+		t.Errorf("VerifyFile() without checksum = %v", err)
+	}
+}
+
+func TestWriteFileChecksummedInterrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".registry.json")
+	if err := WriteFileChecksummed(path, []byte(`{"v": 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A writer that crashed after recording both checksums leaves either the
+	// old or the new content, and both pass
+	pending := checksumOf([]byte(`{"v": 2}`)) + "\n" + checksumOf([]byte(`{"v": 1}`)) + "\n"
+	if err := os.WriteFile(path+checksumSuffix, []byte(pending), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("VerifyFile() with the old content = %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"v": 2}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("VerifyFile() with the new content = %v", err)
+	}
+
+	// The next write records a single checksum again
+	if err := WriteFileChecksummed(path, []byte(`{"v": 3}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path + checksumSuffix); strings.Count(string(data), "\n") != 1 {
+		t.Errorf("checksum file = %q, want one checksum", data)
+	}
+
+	// A lock left behind by a crashed writer is taken over once stale
+	lockPath := path + lockSuffix
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * checksumLockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFileChecksummed(path, []byte(`{"v": 4}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock file left after write: %v", err)
+	}
+}
+
+func TestWriteFileChecksummedConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".registry.json")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 10 {
+				if err := WriteFileChecksummed(path, []byte(fmt.Sprintf(`{"writer": %d, "n": %d}`, i, j)), 0644); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := VerifyFile(path); err != nil {
+		t.Errorf("VerifyFile() after concurrent writes = %v", err)
+	}
+	if !HasChecksumBackup(path) {
+		t.Error("backup does not match its checksum after concurrent writes")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") || strings.HasSuffix(entry.Name(), lockSuffix) {
+			t.Errorf("leftover file %s", entry.Name())
+		}
+	}
+}
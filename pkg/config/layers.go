@@ -0,0 +1,311 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LocalConfigFileName is the optional untracked overlay merged over the base config
+const LocalConfigFileName = ".worktree.local.yml"
+
+// loadConfigLayers reads .worktree.yml, the files listed in its include: key and
+// .worktree.local.yml, deep-merging them in that order (later layers win).
+// Returns the merged document as YAML and the list of files that were loaded.
+func loadConfigLayers(projectRoot string) ([]byte, []string, error) {
+	merged, layers, _, err := mergeConfigLayers(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to merge config files: %w", err)
+	}
+	return data, layers, nil
+}
+
+// mergeConfigLayers merges the config layers like loadConfigLayers and also
+// returns which layer each top-level key, and each key of a top-level map,
+// got its value from ("env_variables.APP_PORT" -> layer)
+func mergeConfigLayers(projectRoot string) (map[string]interface{}, []string, map[string]string, error) {
+	basePath := filepath.Join(projectRoot, ConfigFileName)
+	base, err := readConfigLayer(basePath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	includes, err := includeEntries(base, ConfigFileName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	delete(base, "include")
+
+	origins := make(map[string]string)
+	recordOrigins(origins, base, basePath)
+	merged := base
+	layers := []string{basePath}
+	add := func(path string, layer map[string]interface{}) error {
+		if _, nested := layer["include"]; nested {
+			return fmt.Errorf("%s: nested include is not supported", path)
+		}
+		recordOrigins(origins, layer, path)
+		merged = deepMerge(merged, layer)
+		layers = append(layers, path)
+		return nil
+	}
+
+	for _, include := range includes {
+		if include.URL != "" {
+			layer, err := readRemoteLayer(include)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if err := add(include.URL, layer); err != nil {
+				return nil, nil, nil, err
+			}
+			continue
+		}
+
+		pattern := include.Path
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(projectRoot, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid include pattern '%s': %w", pattern, err)
+		}
+		if len(matches) == 0 && !hasGlobMeta(pattern) {
+			return nil, nil, nil, fmt.Errorf("included config file not found: %s", pattern)
+		}
+		sort.Strings(matches)
+
+		for _, path := range matches {
+			if include.SHA256 != "" {
+				if err := verifyLayerChecksum(path, include.SHA256); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			layer, err := readConfigLayer(path)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if err := add(path, layer); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+
+	localPath := filepath.Join(projectRoot, LocalConfigFileName)
+	if _, err := os.Stat(localPath); err == nil {
+		local, err := readConfigLayer(localPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if _, nested := local["include"]; nested {
+			return nil, nil, nil, fmt.Errorf("%s: include is only allowed in %s", LocalConfigFileName, ConfigFileName)
+		}
+		recordOrigins(origins, local, localPath)
+		merged = deepMerge(merged, local)
+		layers = append(layers, localPath)
+	}
+
+	return merged, layers, origins, nil
+}
+
+// recordOrigins notes the layer as the origin of its top-level keys and of
+// the keys of its top-level maps
+func recordOrigins(origins map[string]string, layer map[string]interface{}, path string) {
+	for key, value := range layer {
+		origins[key] = path
+		if m, ok := value.(map[string]interface{}); ok {
+			for sub := range m {
+				origins[key+"."+sub] = path
+			}
+		}
+	}
+}
+
+// readConfigLayer parses a single YAML config file into a generic map
+func readConfigLayer(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parseConfigLayer(data, filepath.Base(path))
+}
+
+// parseConfigLayer parses a YAML config document into a generic map
+func parseConfigLayer(data []byte, name string) (map[string]interface{}, error) {
+	layer := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &layer); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", name, err)
+	}
+	if layer == nil {
+		layer = make(map[string]interface{})
+	}
+	return layer, nil
+}
+
+// includeEntry is one entry of the include: list: a local path or glob, or a
+// URL, optionally pinned to the SHA-256 checksum of its content
+type includeEntry struct {
+	Path   string `yaml:"path"`
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// includeEntries extracts the include: list. Entries are file paths (a single
+// string or a list) or maps with path or url and an optional sha256; URLs must
+// be pinned with sha256.
+func includeEntries(layer map[string]interface{}, source string) ([]includeEntry, error) {
+	raw, ok := layer["include"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	var items []interface{}
+	switch v := raw.(type) {
+	case string:
+		items = []interface{}{v}
+	case []interface{}:
+		items = v
+	default:
+		return nil, fmt.Errorf("%s: include must be a file path or a list of includes", source)
+	}
+
+	entries := make([]includeEntry, 0, len(items))
+	for i, item := range items {
+		var entry includeEntry
+		switch v := item.(type) {
+		case string:
+			entry.Path = v
+			if isIncludeURL(v) {
+				return nil, fmt.Errorf("%s: include[%d]: URL includes need a checksum, use {url: %s, sha256: <checksum>}", source, i, v)
+			}
+		case map[string]interface{}:
+			if sum, ok := v["sha256"]; ok {
+				if _, isString := sum.(string); !isString {
+					return nil, fmt.Errorf("%s: include[%d]: sha256 must be a string, quote it", source, i)
+				}
+			}
+			data, err := yaml.Marshal(v)
+			if err == nil {
+				err = yaml.Unmarshal(data, &entry)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: include[%d]: %w", source, i, err)
+			}
+		default:
+			return nil, fmt.Errorf("%s: include entries must be file paths or {path|url, sha256} maps", source)
+		}
+		if err := entry.validate(); err != nil {
+			return nil, fmt.Errorf("%s: include[%d]: %w", source, i, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// validate checks that an include names exactly one of path and url, that
+// URLs are pinned, and that a checksum is a hex SHA-256
+func (e includeEntry) validate() error {
+	switch {
+	case (e.Path == "") == (e.URL == ""):
+		return fmt.Errorf("set either path or url")
+	case e.URL != "" && !isIncludeURL(e.URL):
+		return fmt.Errorf("url '%s' must start with https:// or http://", e.URL)
+	case e.URL != "" && e.SHA256 == "":
+		return fmt.Errorf("url '%s' needs a sha256 checksum", e.URL)
+	case e.SHA256 != "" && !sha256Re.MatchString(e.SHA256):
+		return fmt.Errorf("sha256 '%s' is not a hex SHA-256 checksum", e.SHA256)
+	case e.SHA256 != "" && hasGlobMeta(e.Path):
+		return fmt.Errorf("a sha256 checksum pins a single file, not the glob '%s'", e.Path)
+	}
+	return nil
+}
+
+// deepMerge merges src over dst. Nested maps are merged key by key;
+// lists and scalar values in src replace those in dst.
+func deepMerge(dst, src map[string]interface{}) map[string]interface{} {
+	for key, srcVal := range src {
+		srcMap, srcIsMap := srcVal.(map[string]interface{})
+		dstMap, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			dst[key] = deepMerge(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcVal
+	}
+	return dst
+}
+
+// hasGlobMeta reports whether pattern contains glob metacharacters
+func hasGlobMeta(pattern string) bool {
+	for _, c := range pattern {
+		switch c {
+		case '*', '?', '[':
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveConfig returns the merged config layers as YAML, before defaults
+// are applied. Each key is annotated with the layer its value came from
+// (paths relative to projectRoot); keys of a top-level map are annotated one
+// by one when they come from different layers.
+func EffectiveConfig(projectRoot string) ([]byte, error) {
+	merged, layers, origins, err := mergeConfigLayers(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	name := func(layer string) string {
+		if rel, err := filepath.Rel(projectRoot, layer); err == nil && !isIncludeURL(layer) {
+			return rel
+		}
+		return layer
+	}
+
+	var doc yaml.Node
+	if err := doc.Encode(merged); err != nil {
+		return nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		from := origins[key.Value]
+		if value.Kind == yaml.MappingNode {
+			mixed := false
+			for j := 0; j+1 < len(value.Content); j += 2 {
+				if origins[key.Value+"."+value.Content[j].Value] != from {
+					mixed = true
+					break
+				}
+			}
+			if mixed {
+				for j := 0; j+1 < len(value.Content); j += 2 {
+					sub := value.Content[j]
+					sub.LineComment = "from " + name(origins[key.Value+"."+sub.Value])
+				}
+				continue
+			}
+		}
+		key.LineComment = "from " + name(from)
+	}
+
+	var header strings.Builder
+	header.WriteString("# Effective configuration, merged from (later layers win):\n")
+	for i, layer := range layers {
+		fmt.Fprintf(&header, "#   %d. %s\n", i+1, name(layer))
+	}
+	data, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+	return append([]byte(header.String()), data...), nil
+}
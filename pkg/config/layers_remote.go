@@ -0,0 +1,102 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// IncludeFetchTimeout bounds the download of a URL include
+const IncludeFetchTimeout = 30 * time.Second
+
+// sha256Re matches a hex SHA-256 checksum
+var sha256Re = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// isIncludeURL reports whether an include refers to a URL rather than a file
+func isIncludeURL(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")
+}
+
+// IncludeCacheDir is where URL includes are kept, named by their checksum, so
+// a pinned include is downloaded once and then read offline
+func IncludeCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "worktree", "includes"), nil
+}
+
+// readRemoteLayer returns a URL include, from the cache when a file with its
+// checksum is there, else downloaded and checked against the checksum
+func readRemoteLayer(include includeEntry) (map[string]interface{}, error) {
+	checksum := strings.ToLower(include.SHA256)
+	cacheDir, err := IncludeCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(cacheDir, checksum+".yml")
+
+	if data, err := os.ReadFile(cachePath); err == nil && sha256Hex(data) == checksum {
+		return parseConfigLayer(data, include.URL)
+	}
+
+	data, err := fetchInclude(include.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch included config %s: %w", include.URL, err)
+	}
+	if got := sha256Hex(data); got != checksum {
+		return nil, fmt.Errorf("included config %s: checksum mismatch (got sha256 %s, pinned %s)", include.URL, got, checksum)
+	}
+
+	// The cache only saves a download next time; failing to write it is not an error
+	if err := os.MkdirAll(cacheDir, 0755); err == nil {
+		tempPath := cachePath + ".tmp"
+		if err := os.WriteFile(tempPath, data, 0644); err == nil {
+			if err := os.Rename(tempPath, cachePath); err != nil {
+				os.Remove(tempPath)
+			}
+		}
+	}
+	return parseConfigLayer(data, include.URL)
+}
+
+// fetchInclude downloads a URL include
+func fetchInclude(url string) ([]byte, error) {
+	client := &http.Client{Timeout: IncludeFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verifyLayerChecksum checks a local include against its pinned checksum
+func verifyLayerChecksum(path, want string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if got := sha256Hex(data); got != strings.ToLower(want) {
+		return fmt.Errorf("included config %s: checksum mismatch (got sha256 %s, pinned %s)", path, got, strings.ToLower(want))
+	}
+	return nil
+}
+
+// sha256Hex returns the hex SHA-256 checksum of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
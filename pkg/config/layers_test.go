@@ -0,0 +1,313 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const layersBaseConfig = `project_name: testproject
+include:
+  - worktree.d/*.yml
+projects:
+  backend:
+    dir: backend
+    main_branch: main
+presets:
+  default:
+    projects: [backend]
+default_preset: default
+env_variables:
+  APP_PORT:
+    name: "Backend"
+    range: [3000, 3100]
+`
+
+func writeLayerFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadWorktreeConfig_Includes(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, layersBaseConfig)
+	writeLayerFile(t, dir, "worktree.d/ports.yml", `env_variables:
+  FE_PORT:
+    name: "Frontend"
+    range: [5000, 5100]
+`)
+	writeLayerFile(t, dir, "worktree.d/projects.yml", `projects:
+  frontend:
+    dir: frontend
+    main_branch: main
+presets:
+  default:
+    projects: [backend, frontend]
+`)
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+
+	if _, ok := cfg.EnvVariables["APP_PORT"]; !ok {
+		t.Error("APP_PORT from base config missing after merge")
+	}
+	if _, ok := cfg.EnvVariables["FE_PORT"]; !ok {
+		t.Error("FE_PORT from included file missing after merge")
+	}
+	if len(cfg.Projects) != 2 {
+		t.Errorf("Projects = %v, want backend and frontend", cfg.Projects)
+	}
+	if got := cfg.Presets["default"].Projects; len(got) != 2 {
+		t.Errorf("default preset projects = %v, want list replaced by include", got)
+	}
+	if len(cfg.Layers) != 3 {
+		t.Errorf("Layers = %v, want 3 files", cfg.Layers)
+	}
+}
+
+func TestLoadWorktreeConfig_LocalOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, layersBaseConfig)
+	writeLayerFile(t, dir, LocalConfigFileName, `hostname: dev.local
+env_variables:
+  APP_PORT:
+    range: [4000, 4100]
+`)
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+
+	if cfg.Hostname != "dev.local" {
+		t.Errorf("Hostname = %q, want %q", cfg.Hostname, "dev.local")
+	}
+	app := cfg.EnvVariables["APP_PORT"]
+	if app.Name != "Backend" {
+		t.Errorf("APP_PORT name = %q, want base value kept by deep merge", app.Name)
+	}
+	if r := app.GetPortRange(); r == nil || r[0] != 4000 {
+		t.Errorf("APP_PORT range = %v, want overlay range [4000 4100]", r)
+	}
+	if last := cfg.Layers[len(cfg.Layers)-1]; filepath.Base(last) != LocalConfigFileName {
+		t.Errorf("last layer = %q, want %s", last, LocalConfigFileName)
+	}
+}
+
+func TestLoadWorktreeConfig_LayerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "missing include file",
+			files: map[string]string{
+				ConfigFileName: strings.Replace(layersBaseConfig, "worktree.d/*.yml", "worktree.d/ports.yml", 1),
+			},
+			wantErr: "included config file not found",
+		},
+		{
+			name: "nested include",
+			files: map[string]string{
+				ConfigFileName:         layersBaseConfig,
+				"worktree.d/ports.yml": "include: [other.yml]\n",
+			},
+			wantErr: "nested include is not supported",
+		},
+		{
+			name: "parse error names the file",
+			files: map[string]string{
+				ConfigFileName:         layersBaseConfig,
+				"worktree.d/ports.yml": "env_variables: [unclosed\n",
+			},
+			wantErr: "ports.yml",
+		},
+		{
+			name: "validation runs on merged config",
+			files: map[string]string{
+				ConfigFileName:      layersBaseConfig,
+				LocalConfigFileName: "default_preset: missing\n",
+			},
+			wantErr: "invalid configuration",
+		},
+		{
+			name: "unpinned URL include",
+			files: map[string]string{
+				ConfigFileName: strings.Replace(layersBaseConfig, "  - worktree.d/*.yml", "  - https://platform.example.com/ports.yml", 1),
+			},
+			wantErr: "URL includes need a checksum",
+		},
+		{
+			name: "checksum pins a glob",
+			files: map[string]string{
+				ConfigFileName: strings.Replace(layersBaseConfig, "  - worktree.d/*.yml", "  - {path: worktree.d/*.yml, sha256: "+strings.Repeat("a", 64)+"}", 1),
+			},
+			wantErr: "not the glob",
+		},
+		{
+			name: "local checksum mismatch",
+			files: map[string]string{
+				ConfigFileName:         strings.Replace(layersBaseConfig, "  - worktree.d/*.yml", "  - {path: worktree.d/ports.yml, sha256: "+strings.Repeat("a", 64)+"}", 1),
+				"worktree.d/ports.yml": "hostname: x\n",
+			},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name: "include not allowed in local overlay",
+			files: map[string]string{
+				ConfigFileName:      layersBaseConfig,
+				LocalConfigFileName: "include: [extra.yml]\n",
+			},
+			wantErr: "include is only allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeLayerFile(t, dir, name, content)
+			}
+
+			_, err := LoadWorktreeConfig(dir)
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeepMerge(t *testing.T) {
+	dst := map[string]interface{}{
+		"a": "base",
+		"m": map[string]interface{}{"x": 1, "y": 2},
+		"l": []interface{}{"one", "two"},
+	}
+	src := map[string]interface{}{
+		"m": map[string]interface{}{"y": 3, "z": 4},
+		"l": []interface{}{"three"},
+		"b": true,
+	}
+
+	got := deepMerge(dst, src)
+
+	if got["a"] != "base" || got["b"] != true {
+		t.Errorf("scalars not merged: %v", got)
+	}
+	m := got["m"].(map[string]interface{})
+	if m["x"] != 1 || m["y"] != 3 || m["z"] != 4 {
+		t.Errorf("nested map = %v, want x=1 y=3 z=4", m)
+	}
+	if l := got["l"].([]interface{}); len(l) != 1 || l[0] != "three" {
+		t.Errorf("list = %v, want replaced by [three]", l)
+	}
+}
+
+func TestLoadWorktreeConfig_URLInclude(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	shared := `env_variables:
+  FE_PORT:
+    name: "Frontend"
+    range: [5000, 5100]
+`
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, shared)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	include := fmt.Sprintf("  - url: %s/ports.yml\n    sha256: %s", server.URL, sha256Hex([]byte(shared)))
+	writeLayerFile(t, dir, ConfigFileName, strings.Replace(layersBaseConfig, "  - worktree.d/*.yml", include, 1))
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+	if _, ok := cfg.EnvVariables["FE_PORT"]; !ok {
+		t.Error("FE_PORT from URL include missing after merge")
+	}
+	if len(cfg.Layers) != 2 || cfg.Layers[1] != server.URL+"/ports.yml" {
+		t.Errorf("Layers = %v, want the URL as second layer", cfg.Layers)
+	}
+
+	// The pinned content is cached, so the next load works offline
+	server.Close()
+	if _, err := LoadWorktreeConfig(dir); err != nil {
+		t.Fatalf("LoadWorktreeConfig() from cache error = %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestLoadWorktreeConfig_URLIncludeChecksumMismatch(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hostname: tampered\n")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	include := fmt.Sprintf("  - url: %s/ports.yml\n    sha256: \"%s\"", server.URL, strings.Repeat("0", 64))
+	writeLayerFile(t, dir, ConfigFileName, strings.Replace(layersBaseConfig, "  - worktree.d/*.yml", include, 1))
+
+	_, err := LoadWorktreeConfig(dir)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("LoadWorktreeConfig() error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestEffectiveConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, layersBaseConfig)
+	writeLayerFile(t, dir, "worktree.d/ports.yml", `env_variables:
+  FE_PORT:
+    name: "Frontend"
+    range: [5000, 5100]
+`)
+	writeLayerFile(t, dir, LocalConfigFileName, "hostname: dev.local\n")
+
+	data, err := EffectiveConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"#   1. .worktree.yml\n",
+		"#   2. worktree.d/ports.yml\n",
+		"#   3. .worktree.local.yml\n",
+		"hostname: dev.local # from .worktree.local.yml",
+		"projects: # from .worktree.yml",
+		"APP_PORT: # from .worktree.yml",
+		"FE_PORT: # from worktree.d/ports.yml",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("effective config missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "include:") {
+		t.Errorf("effective config should not contain the include list:\n%s", out)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Merge drivers for merge_driver
+const (
+	MergeDriverOurs       = "ours"       // Keep the worktree's version of a conflicting generated file
+	MergeDriverRegenerate = "regenerate" // Re-render the generated file for the feature
+)
+
+// MergeDriverName is the git merge driver generated files are attributed to
+const MergeDriverName = "worktree-generated"
+
+// validateMergeDriver checks the merge_driver setting
+func (c *WorktreeConfig) validateMergeDriver() error {
+	switch c.MergeDriver {
+	case "", MergeDriverOurs, MergeDriverRegenerate:
+		return nil
+	default:
+		return fmt.Errorf("merge_driver must be '%s' or '%s', got '%s'", MergeDriverOurs, MergeDriverRegenerate, c.MergeDriver)
+	}
+}
+
+// MergeDriverCommand returns the git merge driver command for a project:
+// 'true' keeps the current version; regenerate runs the hidden merge-driver
+// command of executable, which renders the file over git's %A
+func (c *WorktreeConfig) MergeDriverCommand(executable, projectName string) string {
+	if c.MergeDriver != MergeDriverRegenerate {
+		return "true"
+	}
+	return fmt.Sprintf("'%s' merge-driver %s %%A %%P", strings.ReplaceAll(executable, "'", `'\''`), projectName)
+}
+
+// MergeDriverAttributes returns the gitattributes lines assigning the merge
+// driver to a project's generated files
+func (c *WorktreeConfig) MergeDriverAttributes(projectName string) []string {
+	var lines []string
+	for _, file := range c.GeneratedFiles[projectName] {
+		lines = append(lines, fmt.Sprintf("/%s merge=%s", strings.TrimPrefix(file.Path, "/"), MergeDriverName))
+	}
+	return lines
+}
+
+// RenderGeneratedFile renders the generated file of a project at path
+// (relative to the project directory). The bool reports whether path is a
+// generated file of the project.
+func (c *WorktreeConfig) RenderGeneratedFile(projectName, path string, envVars map[string]string) (string, bool, error) {
+	for _, file := range c.GeneratedFiles[projectName] {
+		if strings.TrimPrefix(file.Path, "/") == strings.TrimPrefix(path, "/") {
+			content, err := c.renderGeneratedFile(file, envVars)
+			return content, true, err
+		}
+	}
+	return "", false, nil
+}
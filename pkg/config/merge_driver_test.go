@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeDriver(t *testing.T) {
+	cfg := &WorktreeConfig{
+		MergeDriver: MergeDriverRegenerate,
+		GeneratedFiles: map[string][]GeneratedFile{
+			"backend": {{Path: ".env.local", Template: "API_PORT={APP_PORT}"}, {Path: "/config/dev.json", Template: "{}"}},
+		},
+	}
+	if err := cfg.validateMergeDriver(); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := cfg.MergeDriverAttributes("backend")
+	want := []string{"/.env.local merge=worktree-generated", "/config/dev.json merge=worktree-generated"}
+	if strings.Join(attrs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("attributes = %v, want %v", attrs, want)
+	}
+
+	if got := cfg.MergeDriverCommand("/opt/bin/worktree", "backend"); got != "'/opt/bin/worktree' merge-driver backend %A %P" {
+		t.Errorf("regenerate command = %q", got)
+	}
+	content, ok, err := cfg.RenderGeneratedFile("backend", ".env.local", map[string]string{"APP_PORT": "8081"})
+	if err != nil || !ok || content != "API_PORT=8081" {
+		t.Errorf("RenderGeneratedFile = %q, %v, %v", content, ok, err)
+	}
+	if _, ok, _ := cfg.RenderGeneratedFile("backend", "README.md", nil); ok {
+		t.Error("README.md reported as generated")
+	}
+
+	cfg.MergeDriver = MergeDriverOurs
+	if got := cfg.MergeDriverCommand("/opt/bin/worktree", "backend"); got != "true" {
+		t.Errorf("ours command = %q", got)
+	}
+
+	cfg.MergeDriver = "theirs"
+	if err := cfg.validateMergeDriver(); err == nil {
+		t.Error("expected error for unknown merge_driver")
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const overridesFile = ".worktree-overrides.yml"
+
+// OverridesPath returns the path of the per-feature overrides file
+func OverridesPath(featureDir string) string {
+	return filepath.Join(featureDir, overridesFile)
+}
+
+// ReadOverrides reads the per-feature env var overrides from .worktree-overrides.yml.
+// A missing file is not an error and yields an empty map.
+func ReadOverrides(featureDir string) (map[string]string, error) {
+	overrides := make(map[string]string)
+
+	data, err := os.ReadFile(OverridesPath(featureDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return overrides, nil
+		}
+		return nil, fmt.Errorf("failed to read overrides file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides file: %w", err)
+	}
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+
+	for key := range overrides {
+		if !envNameRe.MatchString(key) {
+			return nil, fmt.Errorf("overrides: '%s' is not a valid environment variable name", key)
+		}
+	}
+
+	return overrides, nil
+}
+
+// WriteOverrides writes the per-feature env var overrides to .worktree-overrides.yml.
+// An empty map removes the file.
+func WriteOverrides(featureDir string, overrides map[string]string) error {
+	path := OverridesPath(featureDir)
+
+	if len(overrides) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove overrides file: %w", err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(overrides)
+	if err != nil {
+		return fmt.Errorf("failed to marshal overrides: %w", err)
+	}
+
+	header := "# Per-feature env overrides (managed by 'worktree override')\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write overrides file: %w", err)
+	}
+
+	return nil
+}
+
+// ApplyOverrides merges the feature's overrides into envVars, replacing configured
+// values and adding extras. Returns the overrides that were applied.
+func ApplyOverrides(featureDir string, envVars map[string]string) (map[string]string, error) {
+	overrides, err := ReadOverrides(featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range overrides {
+		envVars[key] = value
+	}
+
+	return overrides, nil
+}
+
+// ValidEnvName reports whether name is a valid environment variable name
+func ValidEnvName(name string) bool {
+	return envNameRe.MatchString(name)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOverridesMissingFile(t *testing.T) {
+	overrides, err := ReadOverrides(t.TempDir())
+	if err != nil {
+		t.Fatalf("ReadOverrides() error = %v", err)
+	}
+	if len(overrides) != 0 {
+		t.Errorf("expected empty overrides, got %v", overrides)
+	}
+}
+
+func TestWriteAndReadOverrides(t *testing.T) {
+	featureDir := t.TempDir()
+	want := map[string]string{
+		"API_URL": "https://staging.example.com",
+		"DEBUG":   "1",
+	}
+
+	if err := WriteOverrides(featureDir, want); err != nil {
+		t.Fatalf("WriteOverrides() error = %v", err)
+	}
+
+	got, err := ReadOverrides(featureDir)
+	if err != nil {
+		t.Fatalf("ReadOverrides() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d overrides, want %d", len(got), len(want))
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %q, want %q", key, got[key], value)
+		}
+	}
+}
+
+func TestWriteOverridesEmptyRemovesFile(t *testing.T) {
+	featureDir := t.TempDir()
+	if err := WriteOverrides(featureDir, map[string]string{"A": "1"}); err != nil {
+		t.Fatalf("WriteOverrides() error = %v", err)
+	}
+
+	if err := WriteOverrides(featureDir, map[string]string{}); err != nil {
+		t.Fatalf("WriteOverrides() error = %v", err)
+	}
+	if _, err := os.Stat(OverridesPath(featureDir)); !os.IsNotExist(err) {
+		t.Error("expected overrides file to be removed")
+	}
+
+	// Removing again is a no-op
+	if err := WriteOverrides(featureDir, nil); err != nil {
+		t.Errorf("WriteOverrides() on missing file error = %v", err)
+	}
+}
+
+func TestReadOverridesInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "invalid yaml", content: "not: [valid"},
+		{name: "invalid variable name", content: "1BAD: value\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			featureDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(featureDir, overridesFile), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadOverrides(featureDir); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	featureDir := t.TempDir()
+	if err := WriteOverrides(featureDir, map[string]string{
+		"API_URL": "https://staging.example.com",
+		"EXTRA":   "yes",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	envVars := map[string]string{
+		"API_URL":  "http://localhost:8080",
+		"APP_PORT": "8080",
+	}
+
+	applied, err := ApplyOverrides(featureDir, envVars)
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+	if len(applied) != 2 {
+		t.Errorf("applied %d overrides, want 2", len(applied))
+	}
+	if envVars["API_URL"] != "https://staging.example.com" {
+		t.Errorf("API_URL = %q, want override", envVars["API_URL"])
+	}
+	if envVars["EXTRA"] != "yes" {
+		t.Errorf("EXTRA = %q, want %q", envVars["EXTRA"], "yes")
+	}
+	if envVars["APP_PORT"] != "8080" {
+		t.Errorf("APP_PORT = %q, should be untouched", envVars["APP_PORT"])
+	}
+}
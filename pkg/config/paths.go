@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Path placeholders resolve to native paths of the OS worktree runs on, so a
+// template such as "{FEATURE_DIR}{path_sep}data" or a volume mount of
+// "{WORKTREE_PATH:backend}" works on Linux, macOS and Windows alike:
+//
+//	{PROJECT_ROOT}            the project root
+//	{FEATURE_DIR}             the feature's directory (worktrees/<feature>)
+//	{WORKTREE_PATH:project}   a project's worktree in the feature
+//	{path_sep}                the path separator (/ or \)
+//
+// PROJECT_ROOT and FEATURE_DIR are also exported as env vars.
+const (
+	PathVarProjectRoot = "PROJECT_ROOT"
+	PathVarFeatureDir  = "FEATURE_DIR"
+)
+
+// worktreePathRefRe matches {WORKTREE_PATH:project}
+var worktreePathRefRe = regexp.MustCompile(`\{WORKTREE_PATH:([A-Za-z0-9_.-]+)\}`)
+
+// nativePath cleans a path and converts it to the OS's separators
+func nativePath(path string) string {
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// AddPathVars sets FEATURE_DIR and PROJECT_ROOT in envVars from a feature dir
+// (<project root>/worktrees/<feature>). Vars already set, e.g. by an
+// env_variables entry of the same name, are kept.
+func AddPathVars(envVars map[string]string, featureDir string) {
+	if featureDir == "" {
+		return
+	}
+	featureDir = nativePath(featureDir)
+	if _, ok := envVars[PathVarFeatureDir]; !ok {
+		envVars[PathVarFeatureDir] = featureDir
+	}
+	if _, ok := envVars[PathVarProjectRoot]; !ok {
+		envVars[PathVarProjectRoot] = filepath.Dir(filepath.Dir(featureDir))
+	}
+}
+
+// WorktreePath returns the native path of a project's worktree in a feature dir
+func (c *WorktreeConfig) WorktreePath(featureDir, projectName string) string {
+	return nativePath(filepath.Join(featureDir, c.Projects[projectName].Dir))
+}
+
+// resolvePathRefs replaces {path_sep} and, once envVars has FEATURE_DIR,
+// {WORKTREE_PATH:project}; references to unknown projects are left as-is
+func (c *WorktreeConfig) resolvePathRefs(s string, envVars map[string]string) string {
+	s = strings.ReplaceAll(s, "{path_sep}", string(os.PathSeparator))
+	featureDir, ok := envVars[PathVarFeatureDir]
+	if !ok {
+		return s
+	}
+	return worktreePathRefRe.ReplaceAllStringFunc(s, func(match string) string {
+		projectName := worktreePathRefRe.FindStringSubmatch(match)[1]
+		if _, exists := c.Projects[projectName]; !exists {
+			return match
+		}
+		return c.WorktreePath(featureDir, projectName)
+	})
+}
+
+// validatePathRefs checks that every {WORKTREE_PATH:project} in s names a project
+func (c *WorktreeConfig) validatePathRefs(where, s string) error {
+	for _, match := range worktreePathRefRe.FindAllStringSubmatch(s, -1) {
+		if _, ok := c.Projects[match[1]]; !ok {
+			return fmt.Errorf("%s: {WORKTREE_PATH:%s} references undefined project '%s'", where, match[1], match[1])
+		}
+	}
+	return nil
+}
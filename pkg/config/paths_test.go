@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddPathVars(t *testing.T) {
+	root := t.TempDir()
+	featureDir := filepath.Join(root, "worktrees", "feature-x")
+
+	vars := map[string]string{}
+	AddPathVars(vars, featureDir+"/")
+	if vars[PathVarFeatureDir] != featureDir || vars[PathVarProjectRoot] != root {
+		t.Errorf("path vars = %v, want FEATURE_DIR=%s PROJECT_ROOT=%s", vars, featureDir, root)
+	}
+
+	vars = map[string]string{PathVarProjectRoot: "/custom"}
+	AddPathVars(vars, featureDir)
+	if vars[PathVarProjectRoot] != "/custom" {
+		t.Errorf("PROJECT_ROOT = %q, want the existing value kept", vars[PathVarProjectRoot])
+	}
+}
+
+func TestPathPlaceholders(t *testing.T) {
+	root := t.TempDir()
+	featureDir := filepath.Join(root, "worktrees", "feature-x")
+	backendDir := filepath.Join(featureDir, "backend")
+	if err := os.MkdirAll(backendDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sep := string(os.PathSeparator)
+
+	cfg := &WorktreeConfig{
+		Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}, "frontend": {Dir: "apps/web"}},
+		EnvVariables: map[string]EnvVarConfig{
+			"WEB_ROOT": {Env: "WEB_ROOT", Value: "{WORKTREE_PATH:frontend}{path_sep}dist"},
+		},
+		GeneratedFiles: map[string][]GeneratedFile{
+			"backend": {
+				{Path: "mounts.env", Template: "DATA={FEATURE_DIR}{path_sep}data\nWEB={WORKTREE_PATH:frontend}\n"},
+				{Path: "mounts.yml", Engine: TemplateEngineGo, Template: `root: {{ .PROJECT_ROOT }}{{ path_sep }}x
+web: {{ worktree_path "frontend" }}
+`},
+			},
+		},
+	}
+
+	envVars := map[string]string{}
+	AddPathVars(envVars, featureDir)
+	cfg.ResolveValueVars(0, envVars)
+	webDir := filepath.Join(featureDir, "apps", "web")
+	if got := envVars["WEB_ROOT"]; got != webDir+sep+"dist" {
+		t.Errorf("WEB_ROOT = %q, want %q", got, webDir+sep+"dist")
+	}
+
+	if err := cfg.GenerateFiles("backend", featureDir, map[string]string{}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(filepath.Join(backendDir, "mounts.env"))
+	want := "DATA=" + featureDir + sep + "data\nWEB=" + webDir + "\n"
+	if string(data) != want {
+		t.Errorf("mounts.env = %q, want %q", data, want)
+	}
+	data, _ = os.ReadFile(filepath.Join(backendDir, "mounts.yml"))
+	want = "root: " + root + sep + "x\nweb: " + webDir + "\n"
+	if string(data) != want {
+		t.Errorf("mounts.yml = %q, want %q", data, want)
+	}
+}
+
+func TestValidatePathRefs(t *testing.T) {
+	cfg := &WorktreeConfig{Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}}}
+	if err := cfg.validatePathRefs("test", "{WORKTREE_PATH:backend}"); err != nil {
+		t.Errorf("known project: %v", err)
+	}
+	err := cfg.validatePathRefs("test", "{WORKTREE_PATH:backend}:{WORKTREE_PATH:api}")
+	if err == nil || !strings.Contains(err.Error(), "undefined project 'api'") {
+		t.Errorf("unknown project error = %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// validatePortPools checks pool ranges and makes sure no two pools, and no pool
+// and per-variable range, hand out the same ports
+func (c *WorktreeConfig) validatePortPools() error {
+	type namedRange struct {
+		label  string
+		r      [2]int
+		isPool bool
+	}
+
+	var ranges []namedRange
+	for name, pool := range c.PortPools {
+		if pool.Range[0] < 1 || pool.Range[1] > 65535 {
+			return fmt.Errorf("port pool %s: range [%d, %d] outside valid range 1-65535",
+				name, pool.Range[0], pool.Range[1])
+		}
+		if pool.Range[0] >= pool.Range[1] {
+			return fmt.Errorf("port pool %s: invalid range [%d, %d] - min must be < max",
+				name, pool.Range[0], pool.Range[1])
+		}
+		ranges = append(ranges, namedRange{label: "pool " + name, r: pool.Range, isPool: true})
+	}
+
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	for name, portCfg := range c.EnvVariables {
+		if portCfg.Range != nil {
+			ranges = append(ranges, namedRange{label: "port " + name, r: *portCfg.Range})
+		}
+	}
+
+	// Sort for deterministic error messages
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].label < ranges[j].label
+	})
+
+	for i := range ranges {
+		for j := i + 1; j < len(ranges); j++ {
+			a, b := ranges[i], ranges[j]
+			// Per-variable ranges may overlap each other as before; only pools are strict
+			if !a.isPool && !b.isPool {
+				continue
+			}
+			if a.r[0] <= b.r[1] && b.r[0] <= a.r[1] {
+				return fmt.Errorf("%s [%d, %d] overlaps %s [%d, %d]",
+					a.label, a.r[0], a.r[1], b.label, b.r[0], b.r[1])
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetPortRangeFor returns the allocation range of an env variable: its pool's
+// range when it uses a pool, otherwise its own range (see GetPortRange)
+func (c *WorktreeConfig) GetPortRangeFor(name string) *[2]int {
+	portCfg, ok := c.EnvVariables[name]
+	if !ok {
+		return nil
+	}
+	if portCfg.Pool != "" {
+		if pool, ok := c.PortPools[portCfg.Pool]; ok {
+			r := pool.Range
+			return &r
+		}
+		return nil
+	}
+	return portCfg.GetPortRange()
+}
+
+// GetPoolServices returns the env variables allocating from the named pool, sorted
+func (c *WorktreeConfig) GetPoolServices(pool string) []string {
+	var services []string
+	for name, portCfg := range c.EnvVariables {
+		if portCfg.Pool == pool {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_PortPools(t *testing.T) {
+	appRange := [2]int{3000, 3100}
+
+	tests := []struct {
+		name    string
+		pools   map[string]PortPoolConfig
+		vars    map[string]EnvVarConfig
+		wantErr string
+	}{
+		{
+			name:  "valid pool",
+			pools: map[string]PortPoolConfig{"shared": {Range: [2]int{20000, 21000}}},
+			vars: map[string]EnvVarConfig{
+				"APP_PORT":   {Env: "APP_PORT", Port: "3000 + {instance}", Range: &appRange},
+				"DEBUG_PORT": {Env: "DEBUG_PORT", Pool: "shared"},
+			},
+		},
+		{
+			name:    "undefined pool",
+			vars:    map[string]EnvVarConfig{"DEBUG_PORT": {Env: "DEBUG_PORT", Pool: "missing"}},
+			wantErr: "not defined in port_pools",
+		},
+		{
+			name:  "range and pool together",
+			pools: map[string]PortPoolConfig{"shared": {Range: [2]int{20000, 21000}}},
+			vars: map[string]EnvVarConfig{
+				"DEBUG_PORT": {Env: "DEBUG_PORT", Pool: "shared", Range: &appRange},
+			},
+			wantErr: "mutually exclusive",
+		},
+		{
+			name:    "invalid pool range",
+			pools:   map[string]PortPoolConfig{"shared": {Range: [2]int{21000, 20000}}},
+			wantErr: "min must be < max",
+		},
+		{
+			name: "overlapping pools",
+			pools: map[string]PortPoolConfig{
+				"a": {Range: [2]int{20000, 21000}},
+				"b": {Range: [2]int{20500, 22000}},
+			},
+			wantErr: "pool a [20000, 21000] overlaps pool b",
+		},
+		{
+			name:  "pool overlapping a variable range",
+			pools: map[string]PortPoolConfig{"shared": {Range: [2]int{3050, 4000}}},
+			vars: map[string]EnvVarConfig{
+				"APP_PORT": {Env: "APP_PORT", Port: "3000 + {instance}", Range: &appRange},
+			},
+			wantErr: "pool shared [3050, 4000] overlaps port APP_PORT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &WorktreeConfig{
+				Projects:     map[string]ProjectConfig{"backend": {Dir: "backend"}},
+				Presets:      map[string]PresetConfig{"default": {Projects: []string{"backend"}}},
+				PortPools:    tt.pools,
+				EnvVariables: tt.vars,
+			}
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetPortRangeFor(t *testing.T) {
+	appRange := [2]int{3000, 3100}
+	cfg := &WorktreeConfig{
+		PortPools: map[string]PortPoolConfig{"shared": {Range: [2]int{20000, 21000}}},
+		EnvVariables: map[string]EnvVarConfig{
+			"APP_PORT":   {Range: &appRange},
+			"DEBUG_PORT": {Pool: "shared"},
+			"NAME":       {Value: "x"},
+		},
+	}
+
+	if got := cfg.GetPortRangeFor("APP_PORT"); got == nil || *got != appRange {
+		t.Errorf("GetPortRangeFor(APP_PORT) = %v, want %v", got, appRange)
+	}
+	if got := cfg.GetPortRangeFor("DEBUG_PORT"); got == nil || *got != [2]int{20000, 21000} {
+		t.Errorf("GetPortRangeFor(DEBUG_PORT) = %v, want pool range", got)
+	}
+	if got := cfg.GetPortRangeFor("NAME"); got != nil {
+		t.Errorf("GetPortRangeFor(NAME) = %v, want nil", got)
+	}
+	if got := cfg.GetPoolServices("shared"); len(got) != 1 || got[0] != "DEBUG_PORT" {
+		t.Errorf("GetPoolServices(shared) = %v, want [DEBUG_PORT]", got)
+	}
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectDefaults holds project fields inherited by every project that does not
+// set them itself. A project opts out of a default by setting the field
+// explicitly, e.g. start_command: "".
+type ProjectDefaults struct {
+	Executor           string `yaml:"executor"`
+	MainBranch         string `yaml:"main_branch"`
+	StartPreCommand    string `yaml:"start_pre_command"`
+	StartCommand       string `yaml:"start_command"`
+	StartPostCommand   string `yaml:"start_post_command"`
+	StopPreCommand     string `yaml:"stop_pre_command"`
+	StopPostCommand    string `yaml:"stop_post_command"`
+	RestartPreCommand  string `yaml:"restart_pre_command"`
+	RestartPostCommand string `yaml:"restart_post_command"`
+}
+
+// applyProjectDefaults copies project_defaults into every project that does not
+// set the field. data is the merged config document, used to tell a field that
+// is absent from one explicitly set to "".
+func (c *WorktreeConfig) applyProjectDefaults(data []byte) error {
+	if c.ProjectDefaults == (ProjectDefaults{}) {
+		return nil
+	}
+
+	var doc struct {
+		Projects map[string]map[string]yaml.Node `yaml:"projects"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to parse projects: %w", err)
+	}
+
+	d := c.ProjectDefaults
+	for name, project := range c.Projects {
+		keys := doc.Projects[name]
+		inherit := func(key string, field *string, value string) {
+			if _, set := keys[key]; !set && value != "" {
+				*field = value
+			}
+		}
+
+		inherit("executor", &project.Executor, d.Executor)
+		inherit("main_branch", &project.MainBranch, d.MainBranch)
+		inherit("start_pre_command", &project.StartPreCommand, d.StartPreCommand)
+		inherit("start_command", &project.StartCommand, d.StartCommand)
+		inherit("start_post_command", &project.StartPostCommand, d.StartPostCommand)
+		inherit("stop_pre_command", &project.StopPreCommand, d.StopPreCommand)
+		inherit("stop_post_command", &project.StopPostCommand, d.StopPostCommand)
+		inherit("restart_pre_command", &project.RestartPreCommand, d.RestartPreCommand)
+		inherit("restart_post_command", &project.RestartPostCommand, d.RestartPostCommand)
+
+		c.Projects[name] = project
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const projectDefaultsConfig = `project_name: testproject
+project_defaults:
+  main_branch: develop
+  start_command: "docker compose up -d"
+  stop_post_command: "make clean"
+projects:
+  api:
+    dir: api
+  web:
+    dir: web
+    main_branch: main
+    start_command: "npm start"
+    executor: process
+  docs:
+    dir: docs
+    start_command: ""
+presets:
+  default:
+    projects: [api, web, docs]
+default_preset: default
+`
+
+func TestProjectDefaults(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, projectDefaultsConfig)
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		project, field, got, want string
+	}{
+		{"api", "main_branch", cfg.Projects["api"].MainBranch, "develop"},
+		{"api", "start_command", cfg.Projects["api"].StartCommand, "docker compose up -d"},
+		{"api", "stop_post_command", cfg.Projects["api"].StopPostCommand, "make clean"},
+		{"api", "executor", cfg.Projects["api"].Executor, ""},
+		{"web", "main_branch", cfg.Projects["web"].MainBranch, "main"},
+		{"web", "start_command", cfg.Projects["web"].StartCommand, "npm start"},
+		{"web", "stop_post_command", cfg.Projects["web"].StopPostCommand, "make clean"},
+		{"docs", "start_command", cfg.Projects["docs"].StartCommand, ""},
+		{"docs", "main_branch", cfg.Projects["docs"].MainBranch, "develop"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s.%s = %q, want %q", tt.project, tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestProjectDefaults_LocalOverlay(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, projectDefaultsConfig)
+	writeLayerFile(t, dir, LocalConfigFileName, `project_defaults:
+  start_command: "podman compose up -d"
+`)
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+	if got := cfg.Projects["api"].StartCommand; got != "podman compose up -d" {
+		t.Errorf("api.start_command = %q, want overlay default", got)
+	}
+	if got := cfg.Projects["api"].StopPostCommand; got != "make clean" {
+		t.Errorf("api.stop_post_command = %q, want base default", got)
+	}
+}
+
+func TestProjectDefaults_ValidatedAfterMerge(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, strings.Replace(projectDefaultsConfig,
+		"project_defaults:\n", "project_defaults:\n  executor: dockr\n", 1))
+
+	_, err := LoadWorktreeConfig(dir)
+	if err == nil || !strings.Contains(err.Error(), "unknown executor 'dockr'") {
+		t.Fatalf("expected executor validation error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "'web'") {
+		t.Errorf("web sets its own executor and should not be reported: %v", err)
+	}
+}
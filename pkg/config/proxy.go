@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Reverse proxy providers for proxy.provider
+const (
+	ProxyProviderTraefik = "traefik" // Traefik file provider dynamic config
+	ProxyProviderNginx   = "nginx"   // nginx server blocks, included from the main config
+)
+
+// ProxyConfig generates reverse-proxy rules that give every feature stable
+// hostnames (e.g. feature-user-auth.localhost) instead of changing ports
+type ProxyConfig struct {
+	Provider string       `yaml:"provider"` // "traefik" or "nginx"
+	Dir      string       `yaml:"dir"`      // Directory the proxy watches or includes; relative to the project root, ~ allowed
+	Domain   string       `yaml:"domain"`   // Domain features are served under (default: localhost)
+	Upstream string       `yaml:"upstream"` // Host the proxy reaches services at (default: localhost); may be {container_host}
+	Listen   int          `yaml:"listen"`   // nginx listen port (default: 80)
+	Routes   []ProxyRoute `yaml:"routes"`
+}
+
+// ProxyRoute maps a feature hostname to one of its ports
+type ProxyRoute struct {
+	Subdomain string `yaml:"subdomain"` // Optional prefix: "api" → api.<feature>.<domain>
+	Port      string `yaml:"port"`      // env_variables key of the port to route to
+}
+
+// ProxyRule is a resolved route of a feature
+type ProxyRule struct {
+	Name     string // Unique router name, e.g. myproject-feature-x-fe-port
+	Host     string // e.g. feature-x.localhost
+	Upstream string // e.g. http://localhost:3001
+}
+
+// Enabled reports whether reverse-proxy rules are generated
+func (p *ProxyConfig) Enabled() bool {
+	return p.Provider != ""
+}
+
+// validateProxy checks the proxy section against env_variables
+func (c *WorktreeConfig) validateProxy() error {
+	p := c.Proxy
+	if !p.Enabled() {
+		return nil
+	}
+	switch p.Provider {
+	case ProxyProviderTraefik, ProxyProviderNginx:
+	default:
+		return fmt.Errorf("proxy: unknown provider '%s' (expected %s or %s)", p.Provider, ProxyProviderTraefik, ProxyProviderNginx)
+	}
+	if p.Dir == "" {
+		return fmt.Errorf("proxy: dir is required")
+	}
+	if p.Listen < 0 || p.Listen > 65535 {
+		return fmt.Errorf("proxy: listen %d outside valid range 1-65535", p.Listen)
+	}
+	if len(p.Routes) == 0 {
+		return fmt.Errorf("proxy: at least one route is required")
+	}
+	seen := make(map[string]bool)
+	for i, route := range p.Routes {
+		envCfg, ok := c.EnvVariables[route.Port]
+		if !ok || envCfg.Port == "" {
+			return fmt.Errorf("proxy.routes[%d]: port '%s' is not a port in env_variables", i, route.Port)
+		}
+		if seen[route.Subdomain] {
+			return fmt.Errorf("proxy.routes[%d]: duplicate subdomain '%s'", i, route.Subdomain)
+		}
+		seen[route.Subdomain] = true
+	}
+	return nil
+}
+
+// ProxyRules resolves the configured routes for a feature. envVars are the
+// feature's resolved env vars; routes whose port is not set are skipped.
+func (c *WorktreeConfig) ProxyRules(featureName string, envVars map[string]string) []ProxyRule {
+	p := c.Proxy
+	upstream := p.Upstream
+	if upstream == "" {
+		upstream = "localhost"
+	}
+	upstream = c.resolveContainerHost(upstream)
+
+	var rules []ProxyRule
+	for _, route := range p.Routes {
+		port, err := strconv.Atoi(envVars[c.EnvVariables[route.Port].Env])
+		if err != nil {
+			continue
+		}
+		rules = append(rules, ProxyRule{
+			Name:     strings.ToLower(strings.ReplaceAll(fmt.Sprintf("%s-%s-%s", c.ProjectName, featureName, route.Port), "_", "-")),
+			Host:     c.proxyHost(featureName, route),
+			Upstream: fmt.Sprintf("http://%s:%d", upstream, port),
+		})
+	}
+	return rules
+}
+
+// proxyHost returns the hostname a route serves for a feature
+func (c *WorktreeConfig) proxyHost(featureName string, route ProxyRoute) string {
+	domain := c.Proxy.Domain
+	if domain == "" {
+		domain = "localhost"
+	}
+	host := featureName + "." + domain
+	if route.Subdomain != "" {
+		host = route.Subdomain + "." + host
+	}
+	return host
+}
+
+// ProxyFilePath returns the proxy config file of a feature
+func (c *WorktreeConfig) ProxyFilePath(projectRoot, featureName string) (string, error) {
+	dir := c.Proxy.Dir
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve proxy dir: %w", err)
+		}
+		dir = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(dir) {
+		dir = filepath.Join(projectRoot, dir)
+	}
+
+	ext := ".yml"
+	if c.Proxy.Provider == ProxyProviderNginx {
+		ext = ".conf"
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", c.ProjectName, featureName, ext)), nil
+}
+
+// WriteProxyConfig writes the feature's reverse-proxy rules and returns the
+// file and its rules. It does nothing when no proxy is configured.
+func (c *WorktreeConfig) WriteProxyConfig(projectRoot, featureName string, envVars map[string]string) (string, []ProxyRule, error) {
+	if !c.Proxy.Enabled() {
+		return "", nil, nil
+	}
+	path, err := c.ProxyFilePath(projectRoot, featureName)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rules := c.ProxyRules(featureName, envVars)
+	var content string
+	if c.Proxy.Provider == ProxyProviderNginx {
+		content = renderNginxProxy(rules, c.Proxy.Listen)
+	} else {
+		content = renderTraefikProxy(rules)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create proxy dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", nil, fmt.Errorf("failed to write proxy config: %w", err)
+	}
+	return path, rules, nil
+}
+
+// RemoveProxyConfig deletes the feature's reverse-proxy rules, if any
+func (c *WorktreeConfig) RemoveProxyConfig(projectRoot, featureName string) error {
+	if !c.Proxy.Enabled() {
+		return nil
+	}
+	path, err := c.ProxyFilePath(projectRoot, featureName)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove proxy config: %w", err)
+	}
+	return nil
+}
+
+// renderTraefikProxy renders rules as Traefik file provider dynamic config
+func renderTraefikProxy(rules []ProxyRule) string {
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	var b strings.Builder
+	b.WriteString("# Generated by worktree - do not edit\n")
+	b.WriteString("http:\n  routers:\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "    %s:\n      rule: \"Host(`%s`)\"\n      service: %s\n", rule.Name, rule.Host, rule.Name)
+	}
+	b.WriteString("  services:\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "    %s:\n      loadBalancer:\n        servers:\n          - url: \"%s\"\n", rule.Name, rule.Upstream)
+	}
+	return b.String()
+}
+
+// renderNginxProxy renders rules as nginx server blocks
+func renderNginxProxy(rules []ProxyRule, listen int) string {
+	if listen == 0 {
+		listen = 80
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Name < rules[j].Name })
+
+	var b strings.Builder
+	b.WriteString("# Generated by worktree - do not edit\n")
+	for _, rule := range rules {
+		fmt.Fprintf(&b, `
+server {
+    listen %d;
+    server_name %s;
+
+    location / {
+        proxy_pass %s;
+        proxy_http_version 1.1;
+        proxy_set_header Host $host;
+        proxy_set_header Upgrade $http_upgrade;
+        proxy_set_header Connection "upgrade";
+    }
+}
+`, listen, rule.Host, rule.Upstream)
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func proxyTestConfig(provider string) *WorktreeConfig {
+	return &WorktreeConfig{
+		ProjectName: "myproject",
+		EnvVariables: map[string]EnvVarConfig{
+			"FE_PORT":   {Port: "3000", Env: "FE_PORT"},
+			"BE_PORT":   {Port: "8080", Env: "BE_PORT"},
+			"API_URL":   {Value: "http://localhost:{BE_PORT}", Env: "API_URL"},
+			"MAIL_PORT": {Port: "8025", Env: "MAIL_PORT"},
+		},
+		Proxy: ProxyConfig{
+			Provider: provider,
+			Dir:      "proxy",
+			Routes: []ProxyRoute{
+				{Port: "FE_PORT"},
+				{Subdomain: "api", Port: "BE_PORT"},
+			},
+		},
+	}
+}
+
+func TestProxyRules(t *testing.T) {
+	cfg := proxyTestConfig(ProxyProviderTraefik)
+	rules := cfg.ProxyRules("feature-x", map[string]string{"FE_PORT": "3001", "BE_PORT": "8081"})
+	want := []ProxyRule{
+		{Name: "myproject-feature-x-fe-port", Host: "feature-x.localhost", Upstream: "http://localhost:3001"},
+		{Name: "myproject-feature-x-be-port", Host: "api.feature-x.localhost", Upstream: "http://localhost:8081"},
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got %d rules, want %d: %v", len(rules), len(want), rules)
+	}
+	for i := range want {
+		if rules[i] != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, rules[i], want[i])
+		}
+	}
+
+	cfg.Proxy.Domain = "dev.test"
+	cfg.Proxy.Upstream = "{container_host}"
+	rules = cfg.ProxyRules("feature-x", map[string]string{"FE_PORT": "3001"})
+	if len(rules) != 1 || rules[0].Host != "feature-x.dev.test" || rules[0].Upstream != "http://host.docker.internal:3001" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+}
+
+func TestWriteProxyConfig(t *testing.T) {
+	envVars := map[string]string{"FE_PORT": "3001", "BE_PORT": "8081"}
+	tests := []struct {
+		provider string
+		file     string
+		want     []string
+	}{
+		{ProxyProviderTraefik, "myproject-feature-x.yml", []string{"rule: \"Host(`api.feature-x.localhost`)\"", "url: \"http://localhost:3001\""}},
+		{ProxyProviderNginx, "myproject-feature-x.conf", []string{"listen 80;", "server_name api.feature-x.localhost;", "proxy_pass http://localhost:3001;"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			root := t.TempDir()
+			cfg := proxyTestConfig(tt.provider)
+			path, rules, err := cfg.WriteProxyConfig(root, "feature-x", envVars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != filepath.Join(root, "proxy", tt.file) || len(rules) != 2 {
+				t.Errorf("path = %s, rules = %v", path, rules)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("missing %q in:\n%s", want, data)
+				}
+			}
+
+			if err := cfg.RemoveProxyConfig(root, "feature-x"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Error("proxy config not removed")
+			}
+			if err := cfg.RemoveProxyConfig(root, "feature-x"); err != nil {
+				t.Errorf("removing a missing proxy config: %v", err)
+			}
+		})
+	}
+
+	disabled := &WorktreeConfig{}
+	if path, _, err := disabled.WriteProxyConfig(t.TempDir(), "feature-x", envVars); path != "" || err != nil {
+		t.Errorf("disabled proxy wrote %q, %v", path, err)
+	}
+}
+
+func TestValidateProxy(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *ProxyConfig)
+		wantErr string
+	}{
+		{name: "valid", modify: func(p *ProxyConfig) {}},
+		{name: "unknown provider", modify: func(p *ProxyConfig) { p.Provider = "caddy" }, wantErr: "unknown provider"},
+		{name: "no dir", modify: func(p *ProxyConfig) { p.Dir = "" }, wantErr: "dir is required"},
+		{name: "no routes", modify: func(p *ProxyConfig) { p.Routes = nil }, wantErr: "at least one route"},
+		{name: "not a port", modify: func(p *ProxyConfig) { p.Routes[0].Port = "API_URL" }, wantErr: "is not a port"},
+		{name: "duplicate subdomain", modify: func(p *ProxyConfig) { p.Routes[1].Subdomain = "" }, wantErr: "duplicate subdomain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := proxyTestConfig(ProxyProviderNginx)
+			tt.modify(&cfg.Proxy)
+			err := cfg.validateProxy()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RenameProjectInConfig renames a project key and rewrites every reference to
+// it (preset project lists and generated_files keys) in each config layer
+// that mentions it: .worktree.yml, include: files and .worktree.local.yml.
+// The files are edited as YAML node trees so comments and key order are
+// preserved. A URL include that mentions the project cannot be rewritten, so
+// the rename is refused before any file changes. Returns the files changed.
+func RenameProjectInConfig(projectRoot, oldName, newName string) ([]string, error) {
+	merged, layers, _, err := mergeConfigLayers(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+	projects, _ := merged["projects"].(map[string]interface{})
+	if _, ok := projects[oldName]; !ok {
+		return nil, fmt.Errorf("project '%s' not found in the config", oldName)
+	}
+	if _, ok := projects[newName]; ok {
+		return nil, fmt.Errorf("project '%s' already exists in the config", newName)
+	}
+
+	base, err := readConfigLayer(filepath.Join(projectRoot, ConfigFileName))
+	if err != nil {
+		return nil, err
+	}
+	includes, err := includeEntries(base, ConfigFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	type rewrite struct {
+		path string
+		doc  *yaml.Node
+	}
+	var rewrites []rewrite
+	for _, layer := range layers {
+		if isIncludeURL(layer) {
+			mentioned, err := remoteLayerMentionsProject(includes, layer, oldName, newName)
+			if err != nil {
+				return nil, err
+			}
+			if mentioned {
+				return nil, fmt.Errorf("project '%s' is referenced in the included config %s, which cannot be rewritten; rename it there first", oldName, layer)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", layer, err)
+		}
+		if len(doc.Content) == 0 {
+			continue // Empty layer
+		}
+		if doc.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("config file %s is not a YAML mapping", layer)
+		}
+		if renameProjectInLayer(doc.Content[0], oldName, newName) {
+			rewrites = append(rewrites, rewrite{path: layer, doc: &doc})
+		}
+	}
+
+	var changed []string
+	for _, rw := range rewrites {
+		if err := writeConfigNode(rw.path, rw.doc); err != nil {
+			return changed, err
+		}
+		changed = append(changed, rw.path)
+	}
+	return changed, nil
+}
+
+// renameProjectInLayer renames the project in one config layer's mapping
+// node: its projects key, preset project lists and generated_files key.
+// Reports whether anything changed.
+func renameProjectInLayer(root *yaml.Node, oldName, newName string) bool {
+	changed := renameMappingKey(mappingValue(root, "projects"), oldName, newName)
+
+	// Preset project lists
+	if presets := mappingValue(root, "presets"); presets != nil && presets.Kind == yaml.MappingNode {
+		for i := 1; i < len(presets.Content); i += 2 {
+			list := mappingValue(presets.Content[i], "projects")
+			if list == nil || list.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, item := range list.Content {
+				if item.Kind == yaml.ScalarNode && item.Value == oldName {
+					item.Value = newName
+					changed = true
+				}
+			}
+		}
+	}
+
+	// generated_files is keyed by project name
+	if renameMappingKey(mappingValue(root, "generated_files"), oldName, newName) {
+		changed = true
+	}
+	return changed
+}
+
+// remoteLayerMentionsProject reports whether the URL include would need
+// rewriting to rename the project
+func remoteLayerMentionsProject(includes []includeEntry, url, oldName, newName string) (bool, error) {
+	for _, include := range includes {
+		if include.URL != url {
+			continue
+		}
+		layer, err := readRemoteLayer(include)
+		if err != nil {
+			return false, err
+		}
+		var doc yaml.Node
+		if err := doc.Encode(layer); err != nil {
+			return false, fmt.Errorf("included config %s: %w", url, err)
+		}
+		return renameProjectInLayer(&doc, oldName, newName), nil
+	}
+	return false, nil
+}
+
+// writeConfigNode encodes a config file's node tree and writes it atomically,
+// so a failed write never leaves a half-written config
+func writeConfigNode(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+	return nil
+}
+
+// mappingValue returns the value node for key in a YAML mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// renameMappingKey renames key oldKey to newKey in a YAML mapping node
+func renameMappingKey(node *yaml.Node, oldKey, newKey string) bool {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == oldKey {
+			node.Content[i].Value = newKey
+			return true
+		}
+	}
+	return false
+}
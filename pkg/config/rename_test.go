@@ -0,0 +1,210 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const renameTestConfig = `project_name: testproject
+
+# Projects managed by the tool
+projects:
+  api: # the backend service
+    dir: backend
+    main_branch: main
+  frontend:
+    dir: frontend
+    main_branch: main
+
+presets:
+  default:
+    projects: [api, frontend]
+  backend-only:
+    projects:
+      - api
+
+default_preset: default
+
+generated_files:
+  api:
+    - path: .env.local
+      template: "PORT={APP_PORT}"
+`
+
+func TestRenameProjectInConfig(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(renameTestConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := RenameProjectInConfig(dir, "api", "backend")
+	if err != nil {
+		t.Fatalf("RenameProjectInConfig() error = %v", err)
+	}
+	if len(changed) != 1 || changed[0] != filepath.Join(dir, ConfigFileName) {
+		t.Errorf("changed = %v, want only %s", changed, ConfigFileName)
+	}
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("renamed config does not load: %v", err)
+	}
+
+	if _, exists := cfg.Projects["api"]; exists {
+		t.Error("old project key should be gone")
+	}
+	if cfg.Projects["backend"].Dir != "backend" {
+		t.Errorf("backend project = %+v, want dir preserved", cfg.Projects["backend"])
+	}
+	if got := strings.Join(cfg.Presets["default"].Projects, ","); got != "backend,frontend" {
+		t.Errorf("default preset = %s, want backend,frontend", got)
+	}
+	if got := strings.Join(cfg.Presets["backend-only"].Projects, ","); got != "backend" {
+		t.Errorf("backend-only preset = %s, want backend", got)
+	}
+	if _, exists := cfg.GeneratedFiles["backend"]; !exists {
+		t.Error("generated_files key should be renamed")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ConfigFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, comment := range []string{"# Projects managed by the tool", "# the backend service"} {
+		if !strings.Contains(string(data), comment) {
+			t.Errorf("comment %q should be preserved", comment)
+		}
+	}
+}
+
+func TestRenameProjectInConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(renameTestConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := RenameProjectInConfig(dir, "missing", "other"); err == nil {
+		t.Error("expected error for unknown project")
+	}
+	if _, err := RenameProjectInConfig(dir, "api", "frontend"); err == nil {
+		t.Error("expected error when new name already exists")
+	}
+	if _, err := RenameProjectInConfig(t.TempDir(), "api", "backend"); err == nil {
+		t.Error("expected error for missing config file")
+	}
+}
+
+func TestRenameProjectInConfigLayers(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, `project_name: testproject
+include:
+  - worktree.d/*.yml
+projects:
+  api:
+    dir: backend
+    main_branch: main
+  frontend:
+    dir: frontend
+    main_branch: main
+default_preset: default
+`)
+	writeLayerFile(t, dir, "worktree.d/presets.yml", `# Team presets
+presets:
+  default:
+    projects: [api, frontend]
+generated_files:
+  api:
+    - path: .env.local
+      template: "PORT={APP_PORT}"
+`)
+	writeLayerFile(t, dir, "worktree.d/ports.yml", `env_variables:
+  APP_PORT:
+    range: [3000, 3100]
+`)
+	writeLayerFile(t, dir, LocalConfigFileName, `projects:
+  api:
+    main_branch: develop
+`)
+
+	changed, err := RenameProjectInConfig(dir, "api", "backend")
+	if err != nil {
+		t.Fatalf("RenameProjectInConfig() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, ConfigFileName),
+		filepath.Join(dir, "worktree.d/presets.yml"),
+		filepath.Join(dir, LocalConfigFileName),
+	}
+	if strings.Join(changed, ",") != strings.Join(want, ",") {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("renamed config does not load: %v", err)
+	}
+	if got := strings.Join(cfg.Presets["default"].Projects, ","); got != "backend,frontend" {
+		t.Errorf("default preset = %s, want backend,frontend", got)
+	}
+	if _, exists := cfg.GeneratedFiles["backend"]; !exists {
+		t.Error("generated_files key in the include file should be renamed")
+	}
+	if got := cfg.Projects["backend"]; got.Dir != "backend" || got.MainBranch != "develop" {
+		t.Errorf("backend project = %+v, want dir from the base and main_branch from the local overlay", got)
+	}
+	if _, exists := cfg.Projects["api"]; exists {
+		t.Error("old project key should be gone from every layer")
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "worktree.d/presets.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "# Team presets") {
+		t.Error("comment in the include file should be preserved")
+	}
+}
+
+func TestRenameProjectInConfigURLInclude(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	shared := `presets:
+  default:
+    projects: [api]
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, shared)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	base := fmt.Sprintf(`project_name: testproject
+include:
+  - url: %s/presets.yml
+    sha256: %s
+projects:
+  api:
+    dir: backend
+    main_branch: main
+default_preset: default
+`, server.URL, sha256Hex([]byte(shared)))
+	writeLayerFile(t, dir, ConfigFileName, base)
+
+	_, err := RenameProjectInConfig(dir, "api", "backend")
+	if err == nil || !strings.Contains(err.Error(), server.URL+"/presets.yml") {
+		t.Fatalf("RenameProjectInConfig() error = %v, want a refusal naming the URL include", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ConfigFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != base {
+		t.Error("no file should change when the rename is refused")
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Secret reference schemes for env_variables.secret
+const (
+	SecretSchemeOnePassword = "op://"  // op://vault/item/field, read with the 1Password CLI
+	SecretSchemeVault       = "vault:" // vault:secret/path#field, read with the Vault CLI (field defaults to "value")
+	SecretSchemeFile        = "file:"  // file:path, relative to the project root; trailing newlines are trimmed
+)
+
+// HasSecrets reports whether any env variable is read from a secrets backend
+func (c *WorktreeConfig) HasSecrets() bool {
+	for _, envCfg := range c.EnvVariables {
+		if envCfg.Secret != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveSecrets reads every secret env variable from its backend and returns
+// them by env name. Secrets are resolved only when services start; callers
+// must not persist the result (registry, instance marker, .worktree-env).
+func (c *WorktreeConfig) ResolveSecrets(projectRoot string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, name := range sortedEnvNames(c.EnvVariables) {
+		envCfg := c.EnvVariables[name]
+		if envCfg.Secret == "" {
+			continue
+		}
+		value, err := resolveSecret(projectRoot, envCfg.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("env_variables.%s: %w", name, err)
+		}
+		secrets[envCfg.Env] = value
+	}
+	return secrets, nil
+}
+
+// validateSecret checks that a secret reference uses a supported scheme
+func validateSecret(where string, envCfg EnvVarConfig) error {
+	if envCfg.Env == "" {
+		return fmt.Errorf("%s: secret requires env", where)
+	}
+	if envCfg.Port != "" || envCfg.Value != "" {
+		return fmt.Errorf("%s: secret cannot be combined with port or value", where)
+	}
+	for _, scheme := range []string{SecretSchemeOnePassword, SecretSchemeVault, SecretSchemeFile} {
+		if strings.HasPrefix(envCfg.Secret, scheme) && len(envCfg.Secret) > len(scheme) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: unsupported secret reference '%s' (expected %s..., %s... or %s...)",
+		where, envCfg.Secret, SecretSchemeOnePassword, SecretSchemeVault, SecretSchemeFile)
+}
+
+// resolveSecret reads a single secret reference
+func resolveSecret(projectRoot, ref string) (string, error) {
+	switch {
+	case strings.HasPrefix(ref, SecretSchemeOnePassword):
+		return secretCommand("op", "read", ref)
+	case strings.HasPrefix(ref, SecretSchemeVault):
+		path, field, found := strings.Cut(strings.TrimPrefix(ref, SecretSchemeVault), "#")
+		if !found {
+			field = "value"
+		}
+		return secretCommand("vault", "kv", "get", "-field="+field, path)
+	case strings.HasPrefix(ref, SecretSchemeFile):
+		path := strings.TrimPrefix(ref, SecretSchemeFile)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectRoot, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default:
+		return "", fmt.Errorf("unsupported secret reference '%s'", ref)
+	}
+}
+
+// secretCommand runs a secrets CLI and returns its output without the
+// trailing newline. The output is never included in errors.
+func secretCommand(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
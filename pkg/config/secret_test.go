@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSecretCLI installs a fake secrets CLI that prints its arguments
+func writeSecretCLI(t *testing.T, dir, name string) {
+	t.Helper()
+	script := "#!/bin/sh\necho \"" + name + ":$*\"\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "db-password"), []byte("hunter2\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	writeSecretCLI(t, bin, "op")
+	writeSecretCLI(t, bin, "vault")
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &WorktreeConfig{EnvVariables: map[string]EnvVarConfig{
+		"APP_PORT":    {Env: "APP_PORT", Port: "8080"},
+		"DB_PASSWORD": {Env: "DB_PASSWORD", Secret: "file:db-password"},
+		"API_KEY":     {Env: "API_KEY", Secret: "op://dev/api/key"},
+		"TOKEN":       {Env: "TOKEN", Secret: "vault:secret/app#token"},
+		"SIGNING_KEY": {Env: "SIGNING_KEY", Secret: "vault:secret/signing"},
+	}}
+	if !cfg.HasSecrets() {
+		t.Fatal("HasSecrets() = false")
+	}
+
+	secrets, err := cfg.ResolveSecrets(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"DB_PASSWORD": "hunter2",
+		"API_KEY":     "op:read op://dev/api/key",
+		"TOKEN":       "vault:kv get -field=token secret/app",
+		"SIGNING_KEY": "vault:kv get -field=value secret/signing",
+	}
+	if len(secrets) != len(want) {
+		t.Errorf("got %d secrets, want %d: %v", len(secrets), len(want), secrets)
+	}
+	for key, value := range want {
+		if secrets[key] != value {
+			t.Errorf("%s = %q, want %q", key, secrets[key], value)
+		}
+	}
+
+	// Port and value vars never carry secrets
+	if envVars := cfg.ExportEnvVars(1); envVars["DB_PASSWORD"] != "" {
+		t.Errorf("ExportEnvVars exported secret: %v", envVars)
+	}
+
+	cfg.EnvVariables["MISSING"] = EnvVarConfig{Env: "MISSING", Secret: "file:nope"}
+	if _, err := cfg.ResolveSecrets(root); err == nil || !strings.Contains(err.Error(), "env_variables.MISSING") {
+		t.Errorf("expected error naming MISSING, got %v", err)
+	}
+}
+
+func TestValidateSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		envCfg  EnvVarConfig
+		wantErr string
+	}{
+		{name: "1password", envCfg: EnvVarConfig{Env: "KEY", Secret: "op://vault/item/field"}},
+		{name: "vault", envCfg: EnvVarConfig{Env: "KEY", Secret: "vault:secret/app#key"}},
+		{name: "file", envCfg: EnvVarConfig{Env: "KEY", Secret: "file:.secrets/key"}},
+		{name: "unknown scheme", envCfg: EnvVarConfig{Env: "KEY", Secret: "aws:key"}, wantErr: "unsupported secret reference"},
+		{name: "empty reference", envCfg: EnvVarConfig{Env: "KEY", Secret: "file:"}, wantErr: "unsupported secret reference"},
+		{name: "no env", envCfg: EnvVarConfig{Secret: "file:key"}, wantErr: "requires env"},
+		{name: "with value", envCfg: EnvVarConfig{Env: "KEY", Value: "x", Secret: "file:key"}, wantErr: "cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSecret("env_variables.KEY", tt.envCfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const startLogFile = ".worktree-start.log"
+
+// StartLogPath returns the log file holding the full output of a feature's
+// start commands from its last new-feature run
+func StartLogPath(featureDir string) string {
+	return filepath.Join(featureDir, startLogFile)
+}
+
+// CreateStartLog truncates the feature's start log and opens it for writing
+func CreateStartLog(featureDir string) (*os.File, error) {
+	file, err := os.Create(StartLogPath(featureDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create start log: %w", err)
+	}
+	return file, nil
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const statusCacheFile = ".worktree-status.json"
+
+// StatusCache is the last known running state of a feature, written by
+// start/stop/restart/status so cheap readers (the shell prompt) never query docker
+type StatusCache struct {
+	Running      bool          `json:"running"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	RunningSince *time.Time    `json:"running_since,omitempty"` // Start of the current running interval
+	RunningTime  time.Duration `json:"running_time,omitempty"`  // Sum of all finished running intervals
+}
+
+// TotalRunningTime returns the cumulative running time, including the
+// current interval when the feature is running
+func (c *StatusCache) TotalRunningTime(now time.Time) time.Duration {
+	total := c.RunningTime
+	if c.Running && c.RunningSince != nil {
+		total += now.Sub(*c.RunningSince)
+	}
+	return total
+}
+
+// StatusCachePath returns the path of a feature's status cache
+func StatusCachePath(featureDir string) string {
+	return filepath.Join(featureDir, statusCacheFile)
+}
+
+// WriteStatusCache records whether the feature's services are running. A
+// running→stopped transition adds the finished interval to the running time.
+func WriteStatusCache(featureDir string, running bool) error {
+	now := time.Now()
+	cache := StatusCache{Running: running, UpdatedAt: now}
+	if previous, err := ReadStatusCache(featureDir); err == nil && previous != nil {
+		cache.RunningTime = previous.RunningTime
+		if previous.Running && previous.RunningSince != nil {
+			if running {
+				cache.RunningSince = previous.RunningSince
+			} else {
+				cache.RunningTime += now.Sub(*previous.RunningSince)
+			}
+		}
+	}
+	if running && cache.RunningSince == nil {
+		cache.RunningSince = &now
+	}
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status cache: %w", err)
+	}
+
+	if err := os.WriteFile(StatusCachePath(featureDir), data, 0644); err != nil {
+		return fmt.Errorf("failed to write status cache: %w", err)
+	}
+
+	return nil
+}
+
+// ReadStatusCache reads the cached running state. Returns nil without error when
+// no status has been recorded yet.
+func ReadStatusCache(featureDir string) (*StatusCache, error) {
+	data, err := os.ReadFile(filepath.Join(featureDir, statusCacheFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read status cache: %w", err)
+	}
+
+	var cache StatusCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse status cache: %w", err)
+	}
+
+	return &cache, nil
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteStatusCache_AccumulatesRunningTime(t *testing.T) {
+	featureDir := t.TempDir()
+
+	// Running for an hour, with 30m from earlier runs
+	since := time.Now().Add(-time.Hour)
+	data, _ := json.Marshal(StatusCache{Running: true, RunningSince: &since, RunningTime: 30 * time.Minute})
+	if err := os.WriteFile(filepath.Join(featureDir, statusCacheFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Still running: the interval start is kept
+	if err := WriteStatusCache(featureDir, true); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := ReadStatusCache(featureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.RunningSince == nil || !cache.RunningSince.Equal(since) {
+		t.Errorf("RunningSince = %v, want %v", cache.RunningSince, since)
+	}
+	if total := cache.TotalRunningTime(time.Now()); total < 90*time.Minute || total > 91*time.Minute {
+		t.Errorf("TotalRunningTime while running = %s, want ~1h30m", total)
+	}
+
+	// Stopped: the interval is added to the running time
+	if err := WriteStatusCache(featureDir, false); err != nil {
+		t.Fatal(err)
+	}
+	cache, _ = ReadStatusCache(featureDir)
+	if cache.RunningSince != nil {
+		t.Errorf("RunningSince = %v after stop, want nil", cache.RunningSince)
+	}
+	if cache.RunningTime < 90*time.Minute || cache.RunningTime > 91*time.Minute {
+		t.Errorf("RunningTime after stop = %s, want ~1h30m", cache.RunningTime)
+	}
+
+	// Stopping again does not count anything twice
+	stopped := cache.RunningTime
+	if err := WriteStatusCache(featureDir, false); err != nil {
+		t.Fatal(err)
+	}
+	cache, _ = ReadStatusCache(featureDir)
+	if cache.RunningTime != stopped || cache.TotalRunningTime(time.Now()) != stopped {
+		t.Errorf("RunningTime = %s after second stop, want %s", cache.RunningTime, stopped)
+	}
+
+	// Starting again opens a new interval
+	if err := WriteStatusCache(featureDir, true); err != nil {
+		t.Fatal(err)
+	}
+	cache, _ = ReadStatusCache(featureDir)
+	if cache.RunningSince == nil || time.Since(*cache.RunningSince) > time.Minute {
+		t.Errorf("RunningSince = %v after restart, want now", cache.RunningSince)
+	}
+}
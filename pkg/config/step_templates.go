@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// expandStepTemplates replaces every "use: <template>" step of the agent tasks
+// with the template's steps, substituting the step's with: parameters
+func (c *WorktreeConfig) expandStepTemplates() error {
+	for name, steps := range c.StepTemplates {
+		if len(steps) == 0 {
+			return fmt.Errorf("step_templates '%s': no steps", name)
+		}
+		for i, step := range steps {
+			if step.Use != "" {
+				return fmt.Errorf("step_templates '%s': steps[%d]: templates cannot use other templates", name, i)
+			}
+		}
+	}
+
+	for taskName, task := range c.ScheduledAgents {
+		if task == nil {
+			continue
+		}
+		steps, err := c.ExpandSteps(task.Steps)
+		if err != nil {
+			return fmt.Errorf("scheduled_agents '%s': %w", taskName, err)
+		}
+		task.Steps = steps
+	}
+	return nil
+}
+
+// ExpandSteps returns the steps with every "use:" step replaced by the steps
+// of its template
+func (c *WorktreeConfig) ExpandSteps(steps []AgentStep) ([]AgentStep, error) {
+	var expanded []AgentStep
+	for i, step := range steps {
+		if step.Use == "" {
+			expanded = append(expanded, step)
+			continue
+		}
+		templateSteps, err := c.expandTemplate(step)
+		if err != nil {
+			return nil, fmt.Errorf("steps[%d]: %w", i, err)
+		}
+		expanded = append(expanded, templateSteps...)
+	}
+	return expanded, nil
+}
+
+// expandTemplate returns the steps of a "use:" step's template with its
+// parameters substituted
+func (c *WorktreeConfig) expandTemplate(use AgentStep) ([]AgentStep, error) {
+	template, ok := c.StepTemplates[use.Use]
+	if !ok {
+		return nil, fmt.Errorf("step template '%s' not found in step_templates", use.Use)
+	}
+
+	rest := use
+	rest.Use, rest.With = "", nil
+	if !reflect.DeepEqual(rest, AgentStep{}) {
+		return nil, fmt.Errorf("use: '%s' steps only take with: parameters", use.Use)
+	}
+
+	used := make(map[string]bool)
+	replace := func(s string) string {
+		for key, value := range use.With {
+			placeholder := "{" + key + "}"
+			if strings.Contains(s, placeholder) {
+				used[key] = true
+				s = strings.ReplaceAll(s, placeholder, value)
+			}
+		}
+		return s
+	}
+
+	steps := make([]AgentStep, 0, len(template))
+	for _, step := range template {
+		step = substituteStep(step, replace)
+		step.Template = use.Use
+		steps = append(steps, step)
+	}
+
+	var unused []string
+	for key := range use.With {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return nil, fmt.Errorf("step template '%s' does not use parameter(s) %s", use.Use, strings.Join(unused, ", "))
+	}
+	return steps, nil
+}
+
+// substituteStep returns a copy of the step with replace applied to each of
+// its string fields, list entries and map values
+func substituteStep(step AgentStep, replace func(string) string) AgentStep {
+	for _, field := range []*string{
+		&step.Name, &step.Command, &step.Skill, &step.Args, &step.Pipeline,
+		&step.Project, &step.Service, &step.WorkingDir, &step.Method, &step.URL,
+		&step.Body, &step.Capture, &step.CaptureJSON, &step.Timeout, &step.RetryDelay,
+	} {
+		*field = replace(*field)
+	}
+
+	if step.PipelineArgs != nil {
+		args := make([]string, len(step.PipelineArgs))
+		for i, arg := range step.PipelineArgs {
+			args[i] = replace(arg)
+		}
+		step.PipelineArgs = args
+	}
+	step.Env = substituteMap(step.Env, replace)
+	step.Headers = substituteMap(step.Headers, replace)
+	return step
+}
+
+// substituteMap returns a copy of the map with replace applied to its values
+func substituteMap(m map[string]string, replace func(string) string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	result := make(map[string]string, len(m))
+	for key, value := range m {
+		result[key] = replace(value)
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+const stepTemplatesConfig = `project_name: testproject
+projects:
+  api:
+    dir: api
+presets:
+  default:
+    projects: [api]
+default_preset: default
+step_templates:
+  run-tests:
+    - name: "Tests in {dir}"
+      type: shell
+      command: "cd {dir} && make test"
+      env:
+        GOFLAGS: "{flags}"
+    - name: "Notify"
+      type: http
+      url: "https://ci.internal/{dir}/done"
+scheduled_agents:
+  deps:
+    name: "Deps"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Update"
+        type: shell
+        command: "go get -u ./..."
+      - use: run-tests
+        with:
+          dir: api
+          flags: "-mod=mod"
+  audit:
+    name: "Audit"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - use: run-tests
+        with:
+          dir: web
+          flags: ""
+`
+
+func TestStepTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, stepTemplatesConfig)
+
+	cfg, err := LoadWorktreeConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadWorktreeConfig() error = %v", err)
+	}
+
+	deps := cfg.ScheduledAgents["deps"].Steps
+	if len(deps) != 3 {
+		t.Fatalf("deps steps = %+v, want 3", deps)
+	}
+	if deps[0].Template != "" || deps[1].Template != "run-tests" {
+		t.Errorf("templates = %q, %q", deps[0].Template, deps[1].Template)
+	}
+	if deps[1].Name != "Tests in api" || deps[1].Command != "cd api && make test" || deps[1].Env["GOFLAGS"] != "-mod=mod" {
+		t.Errorf("expanded step = %+v", deps[1])
+	}
+	if deps[2].URL != "https://ci.internal/api/done" {
+		t.Errorf("expanded url = %q", deps[2].URL)
+	}
+
+	audit := cfg.ScheduledAgents["audit"].Steps
+	if len(audit) != 2 || audit[0].Command != "cd web && make test" || audit[0].Env["GOFLAGS"] != "" {
+		t.Errorf("audit steps = %+v", audit)
+	}
+	// Each use gets its own copy of the template's maps
+	if cfg.StepTemplates["run-tests"][0].Env["GOFLAGS"] != "{flags}" {
+		t.Error("expanding a template must not modify it")
+	}
+}
+
+func TestStepTemplatesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   string
+		wantErr string
+	}{
+		{"unknown template", `
+      - use: nope`, "step template 'nope' not found"},
+		{"unused parameter", `
+      - use: run-tests
+        with:
+          dir: api
+          flags: ""
+          typo: x`, "does not use parameter(s) typo"},
+		{"extra fields", `
+      - use: run-tests
+        type: shell`, "steps only take with: parameters"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			config := strings.Replace(stepTemplatesConfig, `
+      - use: run-tests
+        with:
+          dir: web
+          flags: ""`, tt.steps, 1)
+			writeLayerFile(t, dir, ConfigFileName, config)
+
+			_, err := LoadWorktreeConfig(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "scheduled_agents 'audit': steps[0]") {
+				t.Errorf("LoadWorktreeConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeLayerFile(t, dir, ConfigFileName, strings.Replace(stepTemplatesConfig, `    - name: "Notify"`, `    - use: other
+    - name: "Notify"`, 1))
+	if _, err := LoadWorktreeConfig(dir); err == nil || !strings.Contains(err.Error(), "templates cannot use other templates") {
+		t.Errorf("nested use: error = %v", err)
+	}
+}
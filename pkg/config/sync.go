@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SyncResult lists what SyncFeatureFiles changed in a feature worktree
+type SyncResult struct {
+	Updated  []string // Paths relative to the feature dir that were written or relinked
+	Warnings []string // Problems that did not stop the sync
+	Backup   string   // Snapshot of the replaced versions, relative to the feature dir ("" if nothing changed)
+}
+
+// SyncFeatureFiles brings generated files, symlinks and file copies in an existing
+// feature worktree up to date with the configuration. Unlike new-feature it only
+// touches files whose content or link target differs, and never replaces a regular
+// file with a symlink. The previous versions of everything it changes are
+// snapshotted (see Backup), so the sync can be undone with RestoreBackup.
+func (c *WorktreeConfig) SyncFeatureFiles(projectRoot, featureDir string, projects []string, envVars map[string]string) *SyncResult {
+	result := &SyncResult{}
+	backup := NewBackup(featureDir)
+
+	for _, link := range c.Symlinks {
+		c.syncSymlink(result, backup, CalculateRelativePath(2), link)
+	}
+	for _, cp := range c.Copies {
+		syncCopy(result, backup, projectRoot, cp)
+	}
+
+	for _, projectName := range projects {
+		project, ok := c.Projects[projectName]
+		if !ok {
+			continue
+		}
+		projectDir := filepath.Join(featureDir, project.Dir)
+		if _, err := os.Stat(projectDir); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: worktree directory not found", projectName))
+			continue
+		}
+
+		for _, link := range project.Symlinks {
+			c.syncSymlink(result, backup, CalculateRelativePath(3), FileLink{
+				Source: link.Source,
+				Target: filepath.Join(project.Dir, link.Target),
+			})
+		}
+		for _, cp := range project.Copies {
+			syncCopy(result, backup, projectRoot, FileLink{
+				Source: cp.Source,
+				Target: filepath.Join(project.Dir, cp.Target),
+			})
+		}
+		for _, file := range c.GeneratedFiles[projectName] {
+			target := filepath.Join(project.Dir, file.Path)
+			content, err := c.renderGeneratedFile(file, envVars)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to generate %s: %v", target, err))
+				continue
+			}
+			changed, err := writeIfChanged(backup, target, []byte(content))
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to generate %s: %v", target, err))
+			} else if changed {
+				result.Updated = append(result.Updated, target)
+			}
+		}
+	}
+
+	result.Backup = backup.Dir()
+	return result
+}
+
+// FeatureSymlinks returns the symlinks SyncFeatureFiles maintains for the
+// given projects: Target relative to the feature dir, Source the exact link value
+func (c *WorktreeConfig) FeatureSymlinks(projects []string) []FileLink {
+	var links []FileLink
+	for _, link := range c.Symlinks {
+		links = append(links, FileLink{Source: CalculateRelativePath(2) + "/" + link.Source, Target: link.Target})
+	}
+	for _, projectName := range projects {
+		project, ok := c.Projects[projectName]
+		if !ok {
+			continue
+		}
+		for _, link := range project.Symlinks {
+			links = append(links, FileLink{
+				Source: CalculateRelativePath(3) + "/" + link.Source,
+				Target: filepath.Join(project.Dir, link.Target),
+			})
+		}
+	}
+	return links
+}
+
+// WatchPaths returns the files whose changes should trigger SyncFeatureFiles:
+// every config file layer (not URL includes), the feature's overrides file and
+// all copy sources.
+func (c *WorktreeConfig) WatchPaths(projectRoot, featureDir string, projects []string) []string {
+	var paths []string
+	for _, layer := range c.Layers {
+		if !isIncludeURL(layer) {
+			paths = append(paths, layer)
+		}
+	}
+	paths = append(paths, OverridesPath(featureDir))
+	for _, cp := range c.Copies {
+		paths = append(paths, filepath.Join(projectRoot, cp.Source))
+	}
+	for _, projectName := range projects {
+		for _, cp := range c.Projects[projectName].Copies {
+			paths = append(paths, filepath.Join(projectRoot, cp.Source))
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// syncSymlink (re)creates link.Target when it is missing or points elsewhere.
+// relPathToRoot leads from the link's directory back to the project root.
+func (c *WorktreeConfig) syncSymlink(result *SyncResult, backup *Backup, relPathToRoot string, link FileLink) {
+	want := relPathToRoot + "/" + link.Source
+	targetPath := filepath.Join(backup.featureDir, link.Target)
+
+	if info, err := os.Lstat(targetPath); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s exists and is not a symlink; leaving it alone", link.Target))
+			return
+		}
+		if current, err := os.Readlink(targetPath); err == nil && current == want {
+			return
+		}
+		if err := backup.Preserve(link.Target); err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+			return
+		}
+		if err := os.Remove(targetPath); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("failed to remove stale symlink %s: %v", link.Target, err))
+			return
+		}
+	} else if err := backup.Preserve(link.Target); err != nil {
+		result.Warnings = append(result.Warnings, err.Error())
+		return
+	}
+
+	if err := os.Symlink(want, targetPath); err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to symlink %s: %v", link.Target, err))
+		return
+	}
+	result.Updated = append(result.Updated, link.Target)
+}
+
+// syncCopy copies a file from the project root when the copy differs from the
+// source. Directory copies are only made when the target does not exist yet.
+func syncCopy(result *SyncResult, backup *Backup, projectRoot string, cp FileLink) {
+	sourcePath := filepath.Join(projectRoot, cp.Source)
+	targetPath := filepath.Join(backup.featureDir, cp.Target)
+
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("source not found: %s", cp.Source))
+		return
+	}
+	if info.IsDir() {
+		if _, err := os.Stat(targetPath); os.IsNotExist(err) {
+			if err := backup.Preserve(cp.Target); err != nil {
+				result.Warnings = append(result.Warnings, err.Error())
+				return
+			}
+			if err := os.CopyFS(targetPath, os.DirFS(sourcePath)); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to copy directory %s: %v", cp.Source, err))
+				return
+			}
+			result.Updated = append(result.Updated, cp.Target)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to read %s: %v", cp.Source, err))
+		return
+	}
+	changed, err := writeIfChanged(backup, cp.Target, data)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("failed to copy %s: %v", cp.Target, err))
+	} else if changed {
+		result.Updated = append(result.Updated, cp.Target)
+	}
+}
+
+// writeIfChanged writes content to rel in the feature dir unless the file
+// already holds it, snapshotting the previous version first
+func writeIfChanged(backup *Backup, rel string, content []byte) (bool, error) {
+	path := filepath.Join(backup.featureDir, rel)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, content) {
+		return false, nil
+	}
+	if err := backup.Preserve(rel); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
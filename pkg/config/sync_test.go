@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSyncFeatureFiles(t *testing.T) {
+	root := t.TempDir()
+	featureDir := filepath.Join(root, "worktrees", "feature-x")
+	if err := os.MkdirAll(filepath.Join(featureDir, "backend"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "seed.sql"), []byte("v1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A regular file where a symlink is configured must not be replaced
+	if err := os.WriteFile(filepath.Join(featureDir, "kept"), []byte("mine"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &WorktreeConfig{
+		Symlinks: []FileLink{{Source: "shared", Target: "shared"}, {Source: "kept", Target: "kept"}},
+		Projects: map[string]ProjectConfig{
+			"backend": {Dir: "backend", Copies: []FileLink{{Source: "seed.sql", Target: "seed.sql"}}},
+		},
+		GeneratedFiles: map[string][]GeneratedFile{
+			"backend": {{Path: ".env", Template: "PORT={APP_PORT}\n"}},
+		},
+	}
+	env := map[string]string{"APP_PORT": "8081"}
+
+	result := cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, env)
+	got := strings.Join(result.Updated, ",")
+	if got != "shared,backend/seed.sql,backend/.env" {
+		t.Errorf("first sync updated %q", got)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "kept exists and is not a symlink") {
+		t.Errorf("warnings = %v", result.Warnings)
+	}
+	if link, _ := os.Readlink(filepath.Join(featureDir, "shared")); link != "../../shared" {
+		t.Errorf("symlink = %q, want ../../shared", link)
+	}
+
+	// Nothing changed: nothing written
+	if result := cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, env); len(result.Updated) != 0 {
+		t.Errorf("second sync updated %v, want nothing", result.Updated)
+	}
+
+	// Template input and copy source change
+	env["APP_PORT"] = "8082"
+	if err := os.WriteFile(filepath.Join(root, "seed.sql"), []byte("v2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result = cfg.SyncFeatureFiles(root, featureDir, []string{"backend"}, env)
+	if got := strings.Join(result.Updated, ","); got != "backend/seed.sql,backend/.env" {
+		t.Errorf("third sync updated %q", got)
+	}
+	data, _ := os.ReadFile(filepath.Join(featureDir, "backend", ".env"))
+	if string(data) != "PORT=8082\n" {
+		t.Errorf(".env = %q", data)
+	}
+}
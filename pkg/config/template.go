@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Template engines for generated_files
+const (
+	TemplateEnginePlaceholders = "placeholders" // {KEY} substitution (default)
+	TemplateEngineGo           = "go"           // Go text/template: {{ .KEY }}, if, range, functions
+)
+
+// renderGeneratedFile renders a generated file's template with envVars using
+// the file's engine
+func (c *WorktreeConfig) renderGeneratedFile(file GeneratedFile, envVars map[string]string) (string, error) {
+	if file.Engine != TemplateEngineGo {
+		return c.resolveHostRefs(substituteVars(c.resolvePathRefs(file.Template, envVars), envVars)), nil
+	}
+
+	tmpl, err := c.parseGoTemplate(file)
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(template.FuncMap{
+		"worktree_path": func(projectName string) (string, error) {
+			if _, ok := c.Projects[projectName]; !ok {
+				return "", fmt.Errorf("worktree_path: unknown project '%s'", projectName)
+			}
+			return c.WorktreePath(envVars[PathVarFeatureDir], projectName), nil
+		},
+	})
+	var out strings.Builder
+	if err := tmpl.Execute(&out, c.templateData(envVars)); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// validateGeneratedFile checks a generated file's engine and template syntax
+func (c *WorktreeConfig) validateGeneratedFile(where string, file GeneratedFile) error {
+	switch file.Engine {
+	case "", TemplateEnginePlaceholders:
+		if err := c.validatePathRefs(where, file.Template); err != nil {
+			return err
+		}
+		return c.validateHostRefs(where, file.Template)
+	case TemplateEngineGo:
+		if _, err := c.parseGoTemplate(file); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: engine must be '%s' or '%s', got '%s'", where, TemplateEnginePlaceholders, TemplateEngineGo, file.Engine)
+	}
+}
+
+// parseGoTemplate parses a Go template. Referencing a variable that is not
+// set is an error, so typos do not silently render as empty.
+func (c *WorktreeConfig) parseGoTemplate(file GeneratedFile) (*template.Template, error) {
+	return template.New(file.Path).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"default":        templateDefault,
+			"host":           c.HostFor,
+			"container_host": c.GetContainerHost,
+			"env":            os.Getenv,
+			"add":            templateAdd,
+			"path_sep":       func() string { return string(os.PathSeparator) },
+			"worktree_path":  func(string) (string, error) { return "", nil }, // Bound to the feature when rendering
+		}).
+		Parse(file.Template)
+}
+
+// templateData exposes envVars to Go templates. "true"/"false" values become
+// booleans so {{ if .YOLO }} works; .Ports maps the port env vars to numbers
+// for {{ range $name, $port := .Ports }}.
+func (c *WorktreeConfig) templateData(envVars map[string]string) map[string]any {
+	data := make(map[string]any, len(envVars)+1)
+	for key, value := range envVars {
+		if value == "true" || value == "false" {
+			data[key] = value == "true"
+		} else {
+			data[key] = value
+		}
+	}
+
+	ports := make(map[string]int)
+	for _, envCfg := range c.EnvVariables {
+		if envCfg.Env == "" || envCfg.Port == "" {
+			continue
+		}
+		if port, err := strconv.Atoi(envVars[envCfg.Env]); err == nil {
+			ports[envCfg.Env] = port
+		}
+	}
+	data["Ports"] = ports
+	return data
+}
+
+// templateDefault returns value, or fallback when value is empty:
+// {{ default "info" .LOG_LEVEL }}
+func templateDefault(fallback, value any) any {
+	if value == nil || value == "" {
+		return fallback
+	}
+	return value
+}
+
+// templateAdd adds integers given as numbers or numeric strings:
+// {{ add .FE_PORT 100 }}
+func templateAdd(values ...any) (int, error) {
+	sum := 0
+	for _, value := range values {
+		switch v := value.(type) {
+		case int:
+			sum += v
+		case string:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("add: %q is not a number", v)
+			}
+			sum += n
+		default:
+			return 0, fmt.Errorf("add: unsupported value %v", value)
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderGeneratedFile(t *testing.T) {
+	cfg := &WorktreeConfig{
+		Hostname: "localhost",
+		EnvVariables: map[string]EnvVarConfig{
+			"BE_PORT": {Env: "BE_PORT", Port: "8080"},
+			"FE_PORT": {Env: "FE_PORT", Port: "3000"},
+			"DB":      {Env: "DB", Port: "5432", Host: "db.local"},
+			"API_URL": {Env: "API_URL", Value: "http://{host}:{BE_PORT}"},
+		},
+	}
+	envVars := map[string]string{
+		"BE_PORT":      "8081",
+		"FE_PORT":      "3001",
+		"DB":           "5433",
+		"API_URL":      "http://localhost:8081",
+		"FEATURE_NAME": "feature-x",
+		"YOLO":         "true",
+		"LOG_LEVEL":    "",
+	}
+
+	tests := []struct {
+		name    string
+		file    GeneratedFile
+		want    string
+		wantErr string
+	}{
+		{
+			name: "placeholders by default",
+			file: GeneratedFile{Template: "PORT={BE_PORT} DB={host:DB} KEEP={{.BE_PORT}}"},
+			want: "PORT=8081 DB=db.local KEEP={{.BE_PORT}}",
+		},
+		{
+			name: "go variables",
+			file: GeneratedFile{Engine: "go", Template: "PORT={{ .FE_PORT }} FEATURE={{ .FEATURE_NAME }}"},
+			want: "PORT=3001 FEATURE=feature-x",
+		},
+		{
+			name: "go conditional on boolean",
+			file: GeneratedFile{Engine: "go", Template: "{{ if .YOLO }}yolo{{ else }}careful{{ end }}"},
+			want: "yolo",
+		},
+		{
+			name: "go range over ports",
+			file: GeneratedFile{Engine: "go", Template: "{{ range $name, $port := .Ports }}{{ $name }}={{ $port }};{{ end }}"},
+			want: "BE_PORT=8081;DB=5433;FE_PORT=3001;",
+		},
+		{
+			name: "go functions",
+			file: GeneratedFile{Engine: "go", Template: `{{ default "info" .LOG_LEVEL }} {{ host "DB" }} {{ add .FE_PORT 100 }}`},
+			want: "info db.local 3101",
+		},
+		{
+			name: "go brace placeholders are literal",
+			file: GeneratedFile{Engine: "go", Template: "{BE_PORT}"},
+			want: "{BE_PORT}",
+		},
+		{
+			name:    "go unknown variable",
+			file:    GeneratedFile{Engine: "go", Template: "{{ .NOPE }}"},
+			wantErr: "NOPE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := cfg.renderGeneratedFile(tt.file, envVars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGeneratedFile(t *testing.T) {
+	cfg := &WorktreeConfig{EnvVariables: map[string]EnvVarConfig{"BE_PORT": {Env: "BE_PORT"}}}
+
+	tests := []struct {
+		name    string
+		file    GeneratedFile
+		wantErr string
+	}{
+		{name: "placeholders", file: GeneratedFile{Template: "{host:BE_PORT}"}},
+		{name: "explicit placeholders", file: GeneratedFile{Engine: "placeholders", Template: "{BE_PORT}"}},
+		{name: "unknown host ref", file: GeneratedFile{Template: "{host:NOPE}"}, wantErr: "undefined env_variables entry"},
+		{name: "go", file: GeneratedFile{Engine: "go", Template: "{{ if .YOLO }}x{{ end }}"}},
+		{name: "go syntax error", file: GeneratedFile{Engine: "go", Template: "{{ if .YOLO }}x"}, wantErr: "unexpected EOF"},
+		{name: "unknown engine", file: GeneratedFile{Engine: "jinja", Template: "x"}, wantErr: "engine must be"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cfg.validateGeneratedFile("generated_files.backend (.env)", tt.file)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Feature lifecycle events sent to webhooks
+const (
+	WebhookEventCreated = "created"
+	WebhookEventStarted = "started"
+	WebhookEventStopped = "stopped"
+	WebhookEventRemoved = "removed"
+)
+
+// WebhookEvents lists every feature lifecycle event, in lifecycle order
+var WebhookEvents = []string{WebhookEventCreated, WebhookEventStarted, WebhookEventStopped, WebhookEventRemoved}
+
+// WebhookConfig is an endpoint that receives signed JSON feature lifecycle events
+type WebhookConfig struct {
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"` // HMAC-SHA256 signing key: a literal or a secret reference (op://, vault:, file:)
+	Events []string `yaml:"events"` // Events to send (default: all)
+}
+
+// Wants reports whether the webhook subscribes to an event
+func (w WebhookConfig) Wants(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// ResolveSecret returns the signing key, reading secret references from their
+// backend; an empty secret leaves deliveries unsigned
+func (w WebhookConfig) ResolveSecret(projectRoot string) (string, error) {
+	if IsSecretRef(w.Secret) {
+		return resolveSecret(projectRoot, w.Secret)
+	}
+	return w.Secret, nil
+}
+
+// validateWebhooks checks webhook URLs and event names
+func (c *WorktreeConfig) validateWebhooks() error {
+	for i, hook := range c.Webhooks {
+		where := fmt.Sprintf("webhooks[%d]", i)
+		u, err := url.Parse(hook.URL)
+		if hook.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s: url '%s' must be an http(s) URL", where, hook.URL)
+		}
+		for _, event := range hook.Events {
+			if !slices.Contains(WebhookEvents, event) {
+				return fmt.Errorf("%s: unknown event '%s' (expected %s)", where, event, strings.Join(WebhookEvents, ", "))
+			}
+		}
+	}
+	return nil
+}
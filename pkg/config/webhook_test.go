@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateWebhooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    WebhookConfig
+		wantErr string
+	}{
+		{"valid", WebhookConfig{URL: "https://dash.internal/events", Events: []string{"created", "removed"}}, ""},
+		{"missing url", WebhookConfig{}, "must be an http(s) URL"},
+		{"not http", WebhookConfig{URL: "ftp://dash.internal"}, "must be an http(s) URL"},
+		{"unknown event", WebhookConfig{URL: "http://localhost:8080", Events: []string{"deleted"}}, "unknown event 'deleted'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &WorktreeConfig{Webhooks: []WebhookConfig{tt.hook}}
+			err := cfg.validateWebhooks()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookWantsAndSecret(t *testing.T) {
+	all := WebhookConfig{URL: "http://localhost"}
+	removed := WebhookConfig{URL: "http://localhost", Events: []string{WebhookEventRemoved}}
+	if !all.Wants(WebhookEventStarted) || removed.Wants(WebhookEventStarted) || !removed.Wants(WebhookEventRemoved) {
+		t.Error("Wants should default to every event and honor events")
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "hook.key"), []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for secret, want := range map[string]string{"": "", "literal": "literal", "file:hook.key": "from-file"} {
+		got, err := WebhookConfig{Secret: secret}.ResolveSecret(root)
+		if err != nil || got != want {
+			t.Errorf("ResolveSecret(%q) = %q, %v; want %q", secret, got, err, want)
+		}
+	}
+}
@@ -3,11 +3,15 @@ package config
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,45 +24,124 @@ type FileLink struct {
 
 // WorktreeConfig represents the .worktree.yml configuration
 type WorktreeConfig struct {
-	ProjectName     string                     `yaml:"project_name"`
-	Hostname        string                     `yaml:"hostname"`
-	Projects        map[string]ProjectConfig   `yaml:"projects"`
-	Presets         map[string]PresetConfig    `yaml:"presets"`
-	DefaultPreset   string                     `yaml:"default_preset"`
-	MaxInstances    int                        `yaml:"max_instances"`
-	AutoFixtures    bool                       `yaml:"auto_fixtures"`
-	Symlinks        []FileLink                 `yaml:"symlinks"`
-	Copies          []FileLink                 `yaml:"copies"`
-	EnvVariables    map[string]EnvVarConfig    `yaml:"env_variables"`
-	GeneratedFiles  map[string][]GeneratedFile `yaml:"generated_files"`
-	ScheduledAgents ScheduledAgents            `yaml:"scheduled_agents"` // NEW: Scheduled agent tasks
+	ProjectName         string                     `yaml:"project_name"`
+	Hostname            string                     `yaml:"hostname"`
+	ContainerHost       string                     `yaml:"container_host"`    // Host containers reach the host machine at, for {container_host} (default: per container_runtime)
+	InstanceEnv         EnvNameList                `yaml:"instance_env"`      // Env var name(s) carrying the instance number (default: INSTANCE)
+	ContainerRuntime    string                     `yaml:"container_runtime"` // "docker", "podman" or "auto" (default: auto-detect)
+	UpdateStrategy      string                     `yaml:"update_strategy"`   // "rebase" or "merge", default for 'worktree update' (default: rebase)
+	FetchTTL            string                     `yaml:"fetch_ttl"`         // Skip 'git fetch' for repositories fetched within this duration, e.g. "10m" (default: always fetch)
+	MinFreeDiskMB       int                        `yaml:"min_free_disk_mb"`  // Free disk space new-feature and start require before running (default: 1024, -1 disables)
+	ProjectDefaults     ProjectDefaults            `yaml:"project_defaults"`  // Fields inherited by projects that do not set them
+	Projects            map[string]ProjectConfig   `yaml:"projects"`
+	Presets             map[string]PresetConfig    `yaml:"presets"`
+	DefaultPreset       string                     `yaml:"default_preset"`
+	NameSuffix          string                     `yaml:"normalized_name_suffix"` // "hash" appends a short hash of the branch to feature names (default: none)
+	GitParallelism      int                        `yaml:"git_parallelism"`        // Projects rebase, push, diff and update work on at once (default: 4, 1 is one at a time)
+	MaxInstances        int                        `yaml:"max_instances"`
+	AutoFixtures        bool                       `yaml:"auto_fixtures"`
+	Symlinks            []FileLink                 `yaml:"symlinks"`
+	Copies              []FileLink                 `yaml:"copies"`
+	EnvVariables        map[string]EnvVarConfig    `yaml:"env_variables"`
+	PortPools           map[string]PortPoolConfig  `yaml:"port_pools"` // Named port ranges shared by several env_variables
+	GeneratedFiles      map[string][]GeneratedFile `yaml:"generated_files"`
+	MergeDriver         string                     `yaml:"merge_driver"`          // "ours" or "regenerate": git merge driver registered for generated_files (default: none)
+	ScheduledAgents     ScheduledAgents            `yaml:"scheduled_agents"`      // NEW: Scheduled agent tasks
+	MaxConcurrentAgents int                        `yaml:"max_concurrent_agents"` // Agent runs in progress at once, across all tasks and processes (default: no limit)
+	History             HistoryConfig              `yaml:"history"`               // Retention of the agent execution history (default: the last 1000 executions)
+	Storage             string                     `yaml:"storage"`               // "json" or "sqlite": where the agent history and queue are kept (default: json)
+	StepTemplates       map[string][]AgentStep     `yaml:"step_templates"`        // Named step sequences agent tasks include with "use: <name>"
+	Proxy               ProxyConfig                `yaml:"proxy"`                 // Optional reverse-proxy rules giving features stable hostnames
+	Hosts               HostsConfig                `yaml:"hosts"`                 // Optional hosts file entries per feature ({feature_host})
+	FeatureFlags        FeatureFlagsConfig         `yaml:"feature_flags"`         // Optional runtime flags file rendered into project worktrees
+	Workspace           WorkspaceConfig            `yaml:"workspace"`             // Optional IDE configuration (VS Code workspace, JetBrains run configurations) per feature
+	Webhooks            []WebhookConfig            `yaml:"webhooks"`              // Optional endpoints receiving signed feature lifecycle events
+
+	// Layers lists the files merged into this config, in order
+	// (.worktree.yml, include: files, .worktree.local.yml)
+	Layers []string `yaml:"-"`
+}
+
+// NameSuffixHash is the normalized_name_suffix value that makes feature names
+// unique per branch, e.g. feature-x-1a2b3c
+const NameSuffixHash = "hash"
+
+// DefaultInstanceEnv is the environment variable that carries the instance number
+// when instance_env is not configured
+const DefaultInstanceEnv = "INSTANCE"
+
+// envNameRe matches valid environment variable names
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// EnvNameList is a list of environment variable names that accepts either a
+// single YAML scalar ("WORKER_ID") or a sequence ([INSTANCE, WORKER_ID])
+type EnvNameList []string
+
+// UnmarshalYAML implements yaml.Unmarshaler for EnvNameList
+func (l *EnvNameList) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.ScalarNode:
+		if value.Value == "" {
+			*l = nil
+			return nil
+		}
+		*l = EnvNameList{value.Value}
+		return nil
+	case yaml.SequenceNode:
+		var names []string
+		if err := value.Decode(&names); err != nil {
+			return err
+		}
+		*l = EnvNameList(names)
+		return nil
+	default:
+		return fmt.Errorf("expected a variable name or a list of variable names")
+	}
 }
 
 // EnvVarConfig represents an environment variable configuration entry (port, string template, or display-only)
 type EnvVarConfig struct {
-	Name  string  `yaml:"name"`
-	URL   string  `yaml:"url"`
-	Port  string  `yaml:"port"`  // Expression like "3000 + {instance}" or null for non-port configs
-	Value string  `yaml:"value"` // String template for non-port configs like COMPOSE_PROJECT_NAME
-	Env   string  `yaml:"env"`   // Environment variable name to export
-	Range *[2]int `yaml:"range"` // Optional explicit range [min, max] for port allocation
+	Name        string  `yaml:"name"`
+	URL         string  `yaml:"url"`
+	Port        string  `yaml:"port"`         // Expression like "3000 + {instance}" or null for non-port configs
+	Value       string  `yaml:"value"`        // String template for non-port configs like COMPOSE_PROJECT_NAME
+	Env         string  `yaml:"env"`          // Environment variable name to export
+	Range       *[2]int `yaml:"range"`        // Optional explicit range [min, max] for port allocation
+	Pool        string  `yaml:"pool"`         // Optional port_pools entry to allocate from instead of a range
+	Host        string  `yaml:"host"`         // Optional hostname for this service, overriding the global hostname
+	Secret      string  `yaml:"secret"`       // Optional secrets backend reference (op://, vault:, file:), resolved at start and never persisted
+	OnExhausted string  `yaml:"on_exhausted"` // Optional: fail (default), expand-range(+N) or steal-stopped once the range or pool is full
+}
+
+// PortPoolConfig is a named port range that several env variables allocate from.
+// Ports are unique across every variable sharing the pool.
+type PortPoolConfig struct {
+	Range       [2]int `yaml:"range"`
+	Description string `yaml:"description"`
 }
 
 // ProjectConfig represents a single project configuration
 type ProjectConfig struct {
-	Executor           string     `yaml:"executor"` // "docker" (default) or "process"
-	Dir                string     `yaml:"dir"`
-	MainBranch         string     `yaml:"main_branch"`
-	StartPreCommand    string     `yaml:"start_pre_command"` // Runs before start_command
-	StartCommand       string     `yaml:"start_command"`
-	StartPostCommand   string     `yaml:"start_post_command"`   // Runs after start_command (fixtures, seed, etc.)
-	StopPreCommand     string     `yaml:"stop_pre_command"`     // Runs before stopping services
-	StopPostCommand    string     `yaml:"stop_post_command"`    // Runs after stopping services
-	RestartPreCommand  string     `yaml:"restart_pre_command"`  // Runs before the full restart cycle
-	RestartPostCommand string     `yaml:"restart_post_command"` // Runs after the full restart cycle
-	ClaudeWorkingDir   bool       `yaml:"claude_working_dir"`
-	Symlinks           []FileLink `yaml:"symlinks"` // Symlinks created inside this project's worktree dir
-	Copies             []FileLink `yaml:"copies"`   // Files copied into this project's worktree dir
+	Executor           string                `yaml:"executor"` // "docker" (default) or "process"
+	Dir                string                `yaml:"dir"`
+	MainBranch         string                `yaml:"main_branch"`
+	BranchTemplate     string                `yaml:"branch_template"`   // Feature branch for this project, e.g. "{branch}-api" (default: the feature branch)
+	StartPreCommand    string                `yaml:"start_pre_command"` // Runs before start_command
+	StartCommand       string                `yaml:"start_command"`
+	StartPostCommand   string                `yaml:"start_post_command"`   // Runs after start_command (fixtures, seed, etc.)
+	StopPreCommand     string                `yaml:"stop_pre_command"`     // Runs before stopping services
+	StopPostCommand    string                `yaml:"stop_post_command"`    // Runs after stopping services
+	RestartPreCommand  string                `yaml:"restart_pre_command"`  // Runs before the full restart cycle
+	RestartPostCommand string                `yaml:"restart_post_command"` // Runs after the full restart cycle
+	TestCommand        string                `yaml:"test_command"`         // Runs the project's tests; listed in .worktree-context.json
+	ClaudeWorkingDir   bool                  `yaml:"claude_working_dir"`
+	Symlinks           []FileLink            `yaml:"symlinks"`         // Symlinks created inside this project's worktree dir
+	Copies             []FileLink            `yaml:"copies"`           // Files copied into this project's worktree dir
+	Submodules         bool                  `yaml:"submodules"`       // Run git submodule update --init --recursive in new worktrees
+	OneshotServices    []string              `yaml:"oneshot_services"` // Compose services that run once and exit (migrations); a clean exit is not a failure
+	HealthChecks       []HealthCheck         `yaml:"health_checks"`    // Readiness probes run after start_command
+	Hooks              map[string]HookPolicy `yaml:"hooks"`            // Timeout and failure policy per lifecycle hook, e.g. start_post
+	ExtraHosts         ExtraHostsConfig      `yaml:"extra_hosts"`      // Hosts entries rendered into a compose override (.worktree-compose.yml)
 }
 
 // GetExecutor returns the executor type, defaulting to "docker" if not set.
@@ -69,6 +152,24 @@ func (p *ProjectConfig) GetExecutor() string {
 	return p.Executor
 }
 
+// IsOneshotService reports whether any project lists service in oneshot_services
+func (c *WorktreeConfig) IsOneshotService(service string) bool {
+	for _, project := range c.Projects {
+		if slices.Contains(project.OneshotServices, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureBranch returns the branch this project uses for a feature created from branch
+func (p *ProjectConfig) FeatureBranch(branch string) string {
+	if p.BranchTemplate == "" {
+		return branch
+	}
+	return strings.ReplaceAll(p.BranchTemplate, "{branch}", branch)
+}
+
 // PresetConfig represents a preset configuration
 type PresetConfig struct {
 	Projects    []string `yaml:"projects"`
@@ -79,6 +180,7 @@ type PresetConfig struct {
 type GeneratedFile struct {
 	Path     string `yaml:"path"`     // File path relative to project directory
 	Template string `yaml:"template"` // Template content with {PLACEHOLDER} substitution
+	Engine   string `yaml:"engine"`   // "placeholders" (default) or "go" for Go text/template
 }
 
 // validateProjectName validates that project name only contains alphanumeric characters and hyphens
@@ -105,7 +207,8 @@ func validateProjectName(name string) error {
 	return nil
 }
 
-// LoadWorktreeConfig loads the .worktree.yml configuration file
+// LoadWorktreeConfig loads the .worktree.yml configuration file, merged with any
+// include: files and the local .worktree.local.yml overlay
 func LoadWorktreeConfig(projectRoot string) (*WorktreeConfig, error) {
 	configPath := filepath.Join(projectRoot, ".worktree.yml")
 
@@ -114,10 +217,10 @@ func LoadWorktreeConfig(projectRoot string) (*WorktreeConfig, error) {
 		return nil, fmt.Errorf("configuration file not found: %s\nRun 'worktree init-config' to create a default configuration", configPath)
 	}
 
-	// Read config file
-	data, err := os.ReadFile(configPath)
+	// Read config file plus include: files and the .worktree.local.yml overlay
+	data, layers, err := loadConfigLayers(projectRoot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	// Parse YAML
@@ -125,6 +228,17 @@ func LoadWorktreeConfig(projectRoot string) (*WorktreeConfig, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	config.Layers = layers
+
+	// Fill in project_defaults before validating, so inherited values are checked too
+	if err := config.applyProjectDefaults(data); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	// Expand "use:" steps before validating, so agent tasks see their full step list
+	if err := config.expandStepTemplates(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
 
 	// Validate config
 	if err := config.Validate(); err != nil {
@@ -168,6 +282,32 @@ func (c *WorktreeConfig) Validate() error {
 		}
 	}
 
+	// Validate project executors and branch templates
+	for projectName, project := range c.Projects {
+		switch project.Executor {
+		case "", "docker", "process":
+		default:
+			return fmt.Errorf("project '%s': unknown executor '%s' (expected docker or process)", projectName, project.Executor)
+		}
+		if project.BranchTemplate != "" && !strings.Contains(project.BranchTemplate, "{branch}") {
+			return fmt.Errorf("project '%s': branch_template '%s' must contain {branch}", projectName, project.BranchTemplate)
+		}
+		if slices.Contains(project.OneshotServices, "") {
+			return fmt.Errorf("project '%s': oneshot_services contains an empty service name", projectName)
+		}
+		for i, check := range project.HealthChecks {
+			if err := c.validateHealthCheck(fmt.Sprintf("project '%s': health_checks[%d]", projectName, i), check); err != nil {
+				return err
+			}
+		}
+		if err := validateHooks(fmt.Sprintf("project '%s': hooks", projectName), project.Hooks); err != nil {
+			return err
+		}
+		if err := c.validateExtraHosts(fmt.Sprintf("project '%s': extra_hosts", projectName), project); err != nil {
+			return err
+		}
+	}
+
 	// Validate port ranges
 	for name, portCfg := range c.EnvVariables {
 		if portCfg.Range != nil {
@@ -181,8 +321,17 @@ func (c *WorktreeConfig) Validate() error {
 			}
 		}
 
+		if portCfg.Pool != "" {
+			if portCfg.Range != nil {
+				return fmt.Errorf("port %s: range and pool are mutually exclusive", name)
+			}
+			if _, exists := c.PortPools[portCfg.Pool]; !exists {
+				return fmt.Errorf("port %s: pool '%s' is not defined in port_pools", name, portCfg.Pool)
+			}
+		}
+
 		// Validate port expressions are parseable
-		if portCfg.Port != "" && portCfg.Range != nil {
+		if portCfg.Port != "" && (portCfg.Range != nil || portCfg.Pool != "") {
 			// Try to parse expression to catch syntax errors early
 			_, err := CalculatePort(portCfg.Port, 0)
 			if err != nil {
@@ -192,6 +341,124 @@ func (c *WorktreeConfig) Validate() error {
 		}
 	}
 
+	if err := c.validatePortPools(); err != nil {
+		return err
+	}
+	if err := c.validateOnExhausted(); err != nil {
+		return err
+	}
+
+	if err := c.validateProxy(); err != nil {
+		return err
+	}
+	if err := c.validateFeatureFlags(); err != nil {
+		return err
+	}
+	if err := c.validateWorkspace(); err != nil {
+		return err
+	}
+	if err := c.validateWebhooks(); err != nil {
+		return err
+	}
+
+	// Validate {host:SERVICE} references
+	for name, envCfg := range c.EnvVariables {
+		if err := c.validateHostRefs(fmt.Sprintf("env_variables.%s", name), envCfg.Value+envCfg.URL); err != nil {
+			return err
+		}
+		if err := c.validatePathRefs(fmt.Sprintf("env_variables.%s", name), envCfg.Value); err != nil {
+			return err
+		}
+		if envCfg.Secret != "" {
+			if err := validateSecret(fmt.Sprintf("env_variables.%s", name), envCfg); err != nil {
+				return err
+			}
+		}
+	}
+	// Validate generated file templates
+	for projectName, files := range c.GeneratedFiles {
+		for _, file := range files {
+			if err := c.validateGeneratedFile(fmt.Sprintf("generated_files.%s (%s)", projectName, file.Path), file); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := c.validateMergeDriver(); err != nil {
+		return err
+	}
+
+	// Validate instance_env names
+	for _, name := range c.InstanceEnv {
+		if !envNameRe.MatchString(name) {
+			return fmt.Errorf("instance_env: '%s' is not a valid environment variable name", name)
+		}
+		for key, envCfg := range c.EnvVariables {
+			if envCfg.Env == name {
+				return fmt.Errorf("instance_env: '%s' is already exported by env_variables entry '%s'", name, key)
+			}
+		}
+	}
+
+	// Validate container_runtime
+	switch c.ContainerRuntime {
+	case "", "auto", "docker", "podman":
+	default:
+		return fmt.Errorf("container_runtime: unknown runtime '%s' (expected docker, podman or auto)", c.ContainerRuntime)
+	}
+
+	// Validate update_strategy
+	switch c.UpdateStrategy {
+	case "", "rebase", "merge":
+	default:
+		return fmt.Errorf("update_strategy: unknown strategy '%s' (expected rebase or merge)", c.UpdateStrategy)
+	}
+
+	// Validate storage
+	switch c.Storage {
+	case "", "json", "sqlite":
+	default:
+		return fmt.Errorf("storage: unknown backend '%s' (expected json or sqlite)", c.Storage)
+	}
+
+	// Validate fetch_ttl
+	if c.FetchTTL != "" {
+		if ttl, err := time.ParseDuration(c.FetchTTL); err != nil || ttl < 0 {
+			return fmt.Errorf("fetch_ttl: invalid duration '%s' (expected e.g. 10m)", c.FetchTTL)
+		}
+	}
+
+	// Validate normalized_name_suffix
+	switch c.NameSuffix {
+	case "", "none", NameSuffixHash:
+	default:
+		return fmt.Errorf("normalized_name_suffix: unknown suffix '%s' (expected hash or none)", c.NameSuffix)
+	}
+
+	// Validate min_free_disk_mb
+	if c.MinFreeDiskMB < -1 {
+		return fmt.Errorf("min_free_disk_mb: invalid value %d (expected a size in MB, or -1 to disable the check)", c.MinFreeDiskMB)
+	}
+
+	// Validate git_parallelism
+	if c.GitParallelism < 0 {
+		return fmt.Errorf("git_parallelism: invalid value %d (expected a number of projects, 1 for one at a time)", c.GitParallelism)
+	}
+
+	// Validate max_concurrent_agents
+	if c.MaxConcurrentAgents < 0 {
+		return fmt.Errorf("max_concurrent_agents: invalid value %d (expected a number of runs, 0 for no limit)", c.MaxConcurrentAgents)
+	}
+	for name, task := range c.ScheduledAgents {
+		if task != nil && task.MaxConcurrent < 0 {
+			return fmt.Errorf("scheduled_agents.%s.max_concurrent: invalid value %d (expected a number of runs)", name, task.MaxConcurrent)
+		}
+	}
+
+	if err := c.History.validate(); err != nil {
+		return err
+	}
+
 	// Validate default_preset exists
 	if c.DefaultPreset != "" {
 		if _, exists := c.Presets[c.DefaultPreset]; !exists {
@@ -365,13 +632,29 @@ func CalculatePort(expression string, instance int) (int, error) {
 	return result, nil
 }
 
-// GetPortURL generates the URL for a port configuration
+// GetPortURL generates the URL for a port configuration.
+// hostname is used unless the service sets its own host.
 func (pc *EnvVarConfig) GetURL(hostname string, port int) string {
-	url := strings.ReplaceAll(pc.URL, "{host}", hostname)
+	url := strings.ReplaceAll(pc.URL, "{host}", pc.hostOr(hostname))
 	url = strings.ReplaceAll(url, "{port}", fmt.Sprintf("%d", port))
 	return url
 }
 
+// varRefRe matches {KEY} placeholders that may name an env var
+var varRefRe = regexp.MustCompile(`\{([A-Za-z0-9_.-]+)\}`)
+
+// substituteVars replaces {KEY} placeholders with their values from envVars in
+// a single left-to-right pass. Inserted values are not scanned again, so the
+// result does not depend on map iteration order. Unknown keys are left as-is.
+func substituteVars(s string, envVars map[string]string) string {
+	return varRefRe.ReplaceAllStringFunc(s, func(match string) string {
+		if value, ok := envVars[match[1:len(match)-1]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
 // arithExprRe matches {VAR_NAME+N} or {VAR_NAME-N} where VAR_NAME is an env var key
 var arithExprRe = regexp.MustCompile(`\{([A-Z_][A-Z0-9_]*)([+-]\d+)\}`)
 
@@ -400,8 +683,33 @@ func resolveArithmeticPlaceholders(s string, envVars map[string]string) string {
 	})
 }
 
+// hostEnvRe matches {env:VAR_NAME} or {env:VAR_NAME:-fallback}
+var hostEnvRe = regexp.MustCompile(`\{env:([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// resolveHostEnvPlaceholders replaces {env:VAR} with the value of VAR from the host
+// environment. {env:VAR:-fallback} uses fallback when VAR is unset or empty;
+// without a fallback an unset variable resolves to an empty string.
+func resolveHostEnvPlaceholders(s string) string {
+	return hostEnvRe.ReplaceAllStringFunc(s, func(match string) string {
+		sub := hostEnvRe.FindStringSubmatch(match)
+		if value := os.Getenv(sub[1]); value != "" {
+			return value
+		}
+		return sub[2]
+	})
+}
+
+// hostOr returns the service's own host (host env lookups resolved), or hostname if unset
+func (pc *EnvVarConfig) hostOr(hostname string) string {
+	if pc.Host == "" {
+		return hostname
+	}
+	return resolveHostEnvPlaceholders(pc.Host)
+}
+
 // GetValue calculates the value for this port config (either port or string template).
-// hostname is used to resolve the {host} placeholder in value templates.
+// hostname is used to resolve the {host} placeholder in value templates unless the
+// service sets its own host.
 // Returns empty string if calculation fails.
 func (pc *EnvVarConfig) GetValue(instance int, envVars map[string]string, hostname string) string {
 	if pc.Port != "" {
@@ -418,34 +726,123 @@ func (pc *EnvVarConfig) GetValue(instance int, envVars map[string]string, hostna
 		result := pc.Value
 
 		// Substitute {host} with the configured hostname
-		result = strings.ReplaceAll(result, "{host}", hostname)
+		result = strings.ReplaceAll(result, "{host}", pc.hostOr(hostname))
 
 		// Substitute {instance}
 		result = strings.ReplaceAll(result, "{instance}", fmt.Sprintf("%d", instance))
 
 		// Substitute port variables like {BE_PORT}, {FE_PORT}, etc.
-		for key, value := range envVars {
-			placeholder := fmt.Sprintf("{%s}", key)
-			result = strings.ReplaceAll(result, placeholder, value)
-		}
+		result = substituteVars(result, envVars)
 
 		// Resolve arithmetic expressions like {FE_PORT+100} or {BE_PORT-50}
 		result = resolveArithmeticPlaceholders(result, envVars)
 
+		// Resolve host environment lookups like {env:OAUTH_CLIENT_ID:-dev-client} last,
+		// so host values are inserted literally
+		result = resolveHostEnvPlaceholders(result)
+
 		return result
 	}
 	return ""
 }
 
+// GetInstanceEnvNames returns the env var names the instance number is exported as.
+// Defaults to INSTANCE; list INSTANCE explicitly in instance_env to keep it alongside a custom name.
+func (c *WorktreeConfig) GetInstanceEnvNames() []string {
+	if len(c.InstanceEnv) == 0 {
+		return []string{DefaultInstanceEnv}
+	}
+	return c.InstanceEnv
+}
+
+// GetContainerHost returns the hostname containers use to reach services on
+// the host machine: container_host if set, otherwise the default of the
+// runtime in use, explicit or auto-detected
+func (c *WorktreeConfig) GetContainerHost() string {
+	if c.ContainerHost != "" {
+		return c.ContainerHost
+	}
+	if c.GetContainerRuntime() == "podman" {
+		return "host.containers.internal"
+	}
+	return "host.docker.internal"
+}
+
+// lookPath finds container runtime binaries; swapped in tests
+var lookPath = exec.LookPath
+
+var (
+	detectRuntimeOnce sync.Once
+	detectedRuntime   string
+)
+
+// GetContainerRuntime returns the container runtime in use: container_runtime
+// if set, otherwise the one auto-detected the way pkg/docker does (docker if
+// on PATH, else podman if on PATH, else docker). PATH is searched once per
+// process; later calls return the cached result.
+func (c *WorktreeConfig) GetContainerRuntime() string {
+	switch c.ContainerRuntime {
+	case "docker", "podman":
+		return c.ContainerRuntime
+	}
+	detectRuntimeOnce.Do(func() {
+		detectedRuntime = "docker"
+		if _, err := lookPath("docker"); err == nil {
+			return
+		}
+		if _, err := lookPath("podman"); err == nil {
+			detectedRuntime = "podman"
+		}
+	})
+	return detectedRuntime
+}
+
+// GetFetchTTL returns how long a fetch stays fresh; 0 (the default) always fetches
+func (c *WorktreeConfig) GetFetchTTL() time.Duration {
+	ttl, _ := time.ParseDuration(c.FetchTTL) // Validated on load
+	return ttl
+}
+
+// DefaultGitParallelism is how many projects git operations work on at once
+const DefaultGitParallelism = 4
+
+// GetGitParallelism returns how many projects multi-project git operations
+// (rebase, push, diff, update) work on at once
+func (c *WorktreeConfig) GetGitParallelism() int {
+	if c.GitParallelism == 0 {
+		return DefaultGitParallelism
+	}
+	return c.GitParallelism
+}
+
+// DefaultMinFreeDiskMB is the free disk space new-feature and start require
+// when min_free_disk_mb is not configured
+const DefaultMinFreeDiskMB = 1024
+
+// GetMinFreeDiskMB returns the free disk space required before starting
+// services; 0 means the check is disabled
+func (c *WorktreeConfig) GetMinFreeDiskMB() int {
+	switch {
+	case c.MinFreeDiskMB < 0:
+		return 0
+	case c.MinFreeDiskMB == 0:
+		return DefaultMinFreeDiskMB
+	}
+	return c.MinFreeDiskMB
+}
+
 // ExportEnvVars exports all configured environment variables for the given instance
 func (c *WorktreeConfig) ExportEnvVars(instance int) map[string]string {
 	envVars := make(map[string]string)
 
-	// Always export INSTANCE first
-	envVars["INSTANCE"] = fmt.Sprintf("%d", instance)
+	// Always export the instance number first (INSTANCE unless renamed via instance_env)
+	for _, name := range c.GetInstanceEnvNames() {
+		envVars[name] = fmt.Sprintf("%d", instance)
+	}
 
 	// First pass: Export all port values (both allocated and calculated ports)
-	for _, portCfg := range c.EnvVariables {
+	for _, name := range sortedEnvNames(c.EnvVariables) {
+		portCfg := c.EnvVariables[name]
 		if portCfg.Env != "" && portCfg.Port != "" {
 			value := portCfg.GetValue(instance, envVars, c.Hostname)
 			if value != "" {
@@ -455,18 +852,21 @@ func (c *WorktreeConfig) ExportEnvVars(instance int) map[string]string {
 	}
 
 	// Second pass: Export string templates that depend on ports
-	for _, portCfg := range c.EnvVariables {
-		if portCfg.Env != "" && portCfg.Value != "" {
-			value := portCfg.GetValue(instance, envVars, c.Hostname)
-			if value != "" {
-				envVars[portCfg.Env] = value
-			}
-		}
-	}
+	c.ResolveValueVars(instance, envVars)
 
 	return envVars
 }
 
+// sortedEnvNames returns the keys of env_variables in sorted order
+func sortedEnvNames(vars map[string]EnvVarConfig) []string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // GenerateFiles creates configured files for a project with templated content
 // Uses the same placeholder substitution as environment variables
 func (c *WorktreeConfig) GenerateFiles(projectName, featureDir string, envVars map[string]string) error {
@@ -482,12 +882,19 @@ func (c *WorktreeConfig) GenerateFiles(projectName, featureDir string, envVars m
 
 	projectPath := filepath.Join(featureDir, projectConfig.Dir)
 
+	// Path placeholders resolve against this feature even when the caller did not add them
+	vars := make(map[string]string, len(envVars)+2)
+	for key, value := range envVars {
+		vars[key] = value
+	}
+	AddPathVars(vars, featureDir)
+	envVars = vars
+
 	for _, file := range files {
 		// Substitute placeholders in template
-		content := file.Template
-		for key, value := range envVars {
-			placeholder := fmt.Sprintf("{%s}", key)
-			content = strings.ReplaceAll(content, placeholder, value)
+		content, err := c.renderGeneratedFile(file, envVars)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.Path, err)
 		}
 
 		// Write file
@@ -500,6 +907,53 @@ func (c *WorktreeConfig) GenerateFiles(projectName, featureDir string, envVars m
 	return nil
 }
 
+// hostRefRe matches {host:SERVICE}, where SERVICE is an env_variables key
+var hostRefRe = regexp.MustCompile(`\{host:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// HostFor returns the hostname a service is reached at: its own host when set,
+// the global hostname otherwise (also for unknown services)
+func (c *WorktreeConfig) HostFor(service string) string {
+	if envCfg, ok := c.EnvVariables[service]; ok {
+		return c.resolveContainerHost(envCfg.hostOr(c.Hostname))
+	}
+	return c.Hostname
+}
+
+// resolveContainerHost replaces {container_host} with GetContainerHost()
+func (c *WorktreeConfig) resolveContainerHost(s string) string {
+	if !strings.Contains(s, "{container_host}") {
+		return s
+	}
+	return strings.ReplaceAll(s, "{container_host}", c.GetContainerHost())
+}
+
+// resolveHostRefs replaces {host:SERVICE} placeholders with HostFor(SERVICE)
+// and {container_host} with the container host
+func (c *WorktreeConfig) resolveHostRefs(s string) string {
+	return hostRefRe.ReplaceAllStringFunc(c.resolveContainerHost(s), func(match string) string {
+		return c.HostFor(hostRefRe.FindStringSubmatch(match)[1])
+	})
+}
+
+// withHostRefs returns a copy of envCfg with {host:SERVICE} and {container_host}
+// resolved in its value and URL, and {container_host} resolved in its host
+func (c *WorktreeConfig) withHostRefs(envCfg EnvVarConfig) *EnvVarConfig {
+	envCfg.Value = c.resolveHostRefs(envCfg.Value)
+	envCfg.URL = c.resolveHostRefs(envCfg.URL)
+	envCfg.Host = c.resolveContainerHost(envCfg.Host)
+	return &envCfg
+}
+
+// validateHostRefs checks that every {host:SERVICE} in s names an env_variables entry
+func (c *WorktreeConfig) validateHostRefs(where, s string) error {
+	for _, match := range hostRefRe.FindAllStringSubmatch(s, -1) {
+		if _, ok := c.EnvVariables[match[1]]; !ok {
+			return fmt.Errorf("%s: {host:%s} references undefined env_variables entry '%s'", where, match[1], match[1])
+		}
+	}
+	return nil
+}
+
 // GetClaudeWorkingProject returns the project configured as Claude's working directory
 func (c *WorktreeConfig) GetClaudeWorkingProject() string {
 	for name, project := range c.Projects {
@@ -565,7 +1019,7 @@ func (c *WorktreeConfig) GetServiceURL(portEnvName string, ports map[string]int)
 		return ""
 	}
 
-	return portCfg.GetURL(c.Hostname, port)
+	return c.withHostRefs(portCfg).GetURL(c.Hostname, port)
 }
 
 // GetDisplayableServices returns a list of services that should be displayed
@@ -584,8 +1038,7 @@ func (c *WorktreeConfig) GetDisplayableServices(ports map[string]int) map[string
 			continue
 		}
 
-		url := portCfg.GetURL(c.Hostname, port)
-		services[portCfg.Name] = url
+		services[portCfg.Name] = c.withHostRefs(portCfg).GetURL(c.Hostname, port)
 	}
 
 	return services
@@ -607,21 +1060,96 @@ func CalculateRelativePath(worktreeDepth int) string {
 // ResolveValueVars recomputes value-template env vars (e.g., GOOGLE_OAUTH_REDIRECT_URI)
 // using the provided envVars map. Call this AFTER overriding ports from the registry
 // so that placeholder substitution uses the actual allocated port values, not base values.
+//
+// Vars are resolved in name order, and the pass is repeated until nothing changes, so a
+// value referencing another value var resolves the same way on every run. Cyclic
+// references stop after one pass per var.
 func (c *WorktreeConfig) ResolveValueVars(instance int, envVars map[string]string) {
-	for _, portCfg := range c.EnvVariables {
-		if portCfg.Env != "" && portCfg.Value != "" {
-			value := portCfg.GetValue(instance, envVars, c.Hostname)
-			if value != "" {
+	c.resolveValueVars(instance, envVars, nil)
+}
+
+// ResolveHostEnvVars adds the host environment vars (see HostEnvVarNames)
+// missing from envVars, read from the current environment. Use it on vars
+// loaded from the registry or .worktree-env, which never hold them.
+func (c *WorktreeConfig) ResolveHostEnvVars(instance int, envVars map[string]string) {
+	missing := make(map[string]bool)
+	for name := range c.HostEnvVarNames() {
+		if _, ok := envVars[name]; !ok {
+			missing[name] = true
+		}
+	}
+	if len(missing) > 0 {
+		c.resolveValueVars(instance, envVars, missing)
+	}
+}
+
+// resolveValueVars implements ResolveValueVars, limited to the env names in
+// only unless it is nil
+func (c *WorktreeConfig) resolveValueVars(instance int, envVars map[string]string, only map[string]bool) {
+	names := sortedEnvNames(c.EnvVariables)
+	for pass := 0; pass <= len(names); pass++ {
+		changed := false
+		for _, name := range names {
+			portCfg := c.EnvVariables[name]
+			if portCfg.Env == "" || portCfg.Value == "" || (only != nil && !only[portCfg.Env]) {
+				continue
+			}
+			// {feature_host} resolves once FEATURE_NAME is known
+			if featureName, ok := envVars["FEATURE_NAME"]; ok {
+				portCfg.Value = strings.ReplaceAll(portCfg.Value, "{feature_host}", c.FeatureHost(featureName))
+			}
+			// {WORKTREE_PATH:project} resolves once FEATURE_DIR is known
+			portCfg.Value = c.resolvePathRefs(portCfg.Value, envVars)
+			value := c.withHostRefs(portCfg).GetValue(instance, envVars, c.Hostname)
+			if value != "" && envVars[portCfg.Env] != value {
 				envVars[portCfg.Env] = value
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
+// HostEnvVarNames returns the env names of value vars read from the host
+// environment: values using {env:VAR}, and values built from such a var.
+// They may carry secrets, so they are resolved whenever vars are exported
+// and never stored (see GetComputedVars).
+func (c *WorktreeConfig) HostEnvVarNames() map[string]bool {
+	names := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, envCfg := range c.EnvVariables {
+			if envCfg.Env == "" || envCfg.Value == "" || names[envCfg.Env] {
+				continue
+			}
+			if hostEnvRe.MatchString(envCfg.Value) || referencesVar(envCfg.Value, names) {
+				names[envCfg.Env] = true
+				changed = true
 			}
 		}
 	}
+	return names
 }
 
-// GetComputedVars returns all env vars that are fully resolved in the provided envVars map.
+// referencesVar reports whether a value template uses one of names as
+// {NAME} or in arithmetic like {NAME+1}
+func referencesVar(value string, names map[string]bool) bool {
+	for name := range names {
+		for _, ref := range []string{"{" + name + "}", "{" + name + "+", "{" + name + "-"} {
+			if strings.Contains(value, ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetExportedVars returns all env vars that are fully resolved in the provided envVars map.
 // Entries with unresolved {placeholder} tokens (e.g. COMPOSE_PROJECT_NAME={project}-{feature}-{service})
 // are excluded — they are substituted separately and cannot be stored as-is.
-func (c *WorktreeConfig) GetComputedVars(envVars map[string]string) map[string]string {
+func (c *WorktreeConfig) GetExportedVars(envVars map[string]string) map[string]string {
 	result := make(map[string]string)
 	for _, portCfg := range c.EnvVariables {
 		if portCfg.Env == "" {
@@ -640,17 +1168,30 @@ func (c *WorktreeConfig) GetComputedVars(envVars map[string]string) map[string]s
 	return result
 }
 
+// GetComputedVars returns the vars of GetExportedVars that are stored in the
+// registry and .worktree-env: all but those read from the host environment
+// (HostEnvVarNames), which stay out of files as they may be secrets.
+func (c *WorktreeConfig) GetComputedVars(envVars map[string]string) map[string]string {
+	result := c.GetExportedVars(envVars)
+	for name := range c.HostEnvVarNames() {
+		delete(result, name)
+	}
+	return result
+}
+
 // GetPortServiceNames returns list of all service names that need port allocation
 // Services must have both 'env' and 'range' fields to be included
 // This excludes template-only services like COMPOSE_PROJECT_NAME
 func (c *WorktreeConfig) GetPortServiceNames() []string {
 	var services []string
 	for name, portCfg := range c.EnvVariables {
-		// Only include services that need port allocation (have both env and range)
-		if portCfg.Env != "" && portCfg.Range != nil {
+		// Only include services that need port allocation (have env and a range or pool)
+		if portCfg.Env != "" && (portCfg.Range != nil || portCfg.Pool != "") {
 			services = append(services, name)
 		}
 	}
+	// Sorted so services sharing a pool get ports in a stable order
+	sort.Strings(services)
 	return services
 }
 
@@ -691,6 +1232,23 @@ func (c *WorktreeConfig) GetInstancePortName() (string, error) {
 	return names[0], nil
 }
 
+// InstanceFromPorts returns the instance number of a feature from its
+// allocated ports, or 0 when it cannot be calculated
+func (c *WorktreeConfig) InstanceFromPorts(ports map[string]int) int {
+	instancePortName, err := c.GetInstancePortName()
+	if err != nil {
+		return 0
+	}
+	basePort, err := ExtractBasePort(c.EnvVariables[instancePortName].Port)
+	if err != nil {
+		return 0
+	}
+	if allocatedPort, ok := ports[instancePortName]; ok {
+		return allocatedPort - basePort
+	}
+	return 0
+}
+
 // GetFirstProjectDir returns the Dir field of the first configured project (alphabetically).
 // Used as a representative git directory for health checks.
 func (c *WorktreeConfig) GetFirstProjectDir() string {
@@ -0,0 +1,287 @@
+package config
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+)
+
+// jetbrainsRunDir is the directory JetBrains IDEs load shared run configurations from
+const jetbrainsRunDir = ".run"
+
+// WorkspaceConfig generates per-feature IDE configuration, e.g.
+//
+//	workspace:
+//	  vscode: true
+//	  jetbrains: true
+//	  launch:
+//	    - name: "API"
+//	      project: backend
+//	      type: go
+//	      program: "cmd/server"
+//	      command: "go run ./cmd/server"
+type WorkspaceConfig struct {
+	VSCode    bool              `yaml:"vscode"`    // Write <feature>.code-workspace with every project folder into the feature dir
+	JetBrains bool              `yaml:"jetbrains"` // Write a .run/<name>.run.xml shell run configuration per launch entry with a command
+	Launch    []WorkspaceLaunch `yaml:"launch"`    // Launch/run configurations; they get the feature's env vars
+}
+
+// WorkspaceLaunch is one launch configuration of a feature workspace
+type WorkspaceLaunch struct {
+	Name    string            `yaml:"name"`
+	Project string            `yaml:"project"` // Project folder it runs in
+	Type    string            `yaml:"type"`    // VS Code debugger type, e.g. go, node, debugpy (VS Code only)
+	Request string            `yaml:"request"` // VS Code request (default: launch)
+	Program string            `yaml:"program"` // VS Code program, relative to the project folder
+	Args    []string          `yaml:"args"`    // VS Code program arguments
+	Command string            `yaml:"command"` // Shell command of the JetBrains run configuration (JetBrains only)
+	Env     map[string]string `yaml:"env"`     // Extra env vars on top of the feature's; {KEY} placeholders are resolved
+}
+
+// Enabled reports whether any IDE configuration is generated
+func (w *WorkspaceConfig) Enabled() bool {
+	return w.VSCode || w.JetBrains
+}
+
+// validateWorkspace checks the workspace section
+func (c *WorktreeConfig) validateWorkspace() error {
+	w := c.Workspace
+	if !w.Enabled() {
+		if len(w.Launch) > 0 {
+			return fmt.Errorf("workspace: launch requires vscode or jetbrains")
+		}
+		return nil
+	}
+	names := make(map[string]bool)
+	for i, launch := range w.Launch {
+		where := fmt.Sprintf("workspace.launch[%d]", i)
+		if launch.Name == "" {
+			return fmt.Errorf("%s: name is required", where)
+		}
+		if names[launch.Name] {
+			return fmt.Errorf("%s: duplicate name '%s'", where, launch.Name)
+		}
+		names[launch.Name] = true
+		if _, ok := c.Projects[launch.Project]; !ok {
+			return fmt.Errorf("%s: unknown project '%s'", where, launch.Project)
+		}
+		if launch.Type == "" && launch.Command == "" {
+			return fmt.Errorf("%s: type (VS Code) or command (JetBrains) is required", where)
+		}
+		if launch.Program != "" && filepath.IsAbs(launch.Program) {
+			return fmt.Errorf("%s: program '%s' must be relative to the project directory", where, launch.Program)
+		}
+	}
+	return nil
+}
+
+// launchEnv returns the env of a launch configuration: the feature's env vars
+// plus its own, with placeholders resolved
+func launchEnv(launch WorkspaceLaunch, envVars map[string]string) map[string]string {
+	env := make(map[string]string, len(envVars)+len(launch.Env))
+	for key, value := range envVars {
+		env[key] = value
+	}
+	for key, value := range launch.Env {
+		env[key] = substituteVars(value, envVars)
+	}
+	return env
+}
+
+// VSCodeWorkspacePath returns the path of a feature's VS Code workspace file
+func VSCodeWorkspacePath(featureDir, featureName string) string {
+	return filepath.Join(featureDir, featureName+".code-workspace")
+}
+
+type vscodeWorkspace struct {
+	Folders  []vscodeFolder    `json:"folders"`
+	Settings map[string]any    `json:"settings"`
+	Launch   *vscodeLaunchList `json:"launch,omitempty"`
+}
+
+type vscodeFolder struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+type vscodeLaunchList struct {
+	Version        string         `json:"version"`
+	Configurations []vscodeLaunch `json:"configurations"`
+}
+
+type vscodeLaunch struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Request string            `json:"request"`
+	Program string            `json:"program,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Cwd     string            `json:"cwd"`
+	Env     map[string]string `json:"env"`
+}
+
+// renderVSCodeWorkspace returns a multi-root workspace with a folder per
+// project; integrated terminals and launch configurations get envVars
+func (c *WorktreeConfig) renderVSCodeWorkspace(projects []string, envVars map[string]string) ([]byte, error) {
+	ws := vscodeWorkspace{Settings: map[string]any{
+		"terminal.integrated.env.linux":   envVars,
+		"terminal.integrated.env.osx":     envVars,
+		"terminal.integrated.env.windows": envVars,
+	}}
+	for _, projectName := range projects {
+		ws.Folders = append(ws.Folders, vscodeFolder{Name: projectName, Path: c.Projects[projectName].Dir})
+	}
+
+	for _, launch := range c.Workspace.Launch {
+		if launch.Type == "" || !slices.Contains(projects, launch.Project) {
+			continue
+		}
+		if ws.Launch == nil {
+			ws.Launch = &vscodeLaunchList{Version: "0.2.0"}
+		}
+		folder := "${workspaceFolder:" + launch.Project + "}"
+		entry := vscodeLaunch{
+			Name:    launch.Name,
+			Type:    launch.Type,
+			Request: launch.Request,
+			Args:    launch.Args,
+			Cwd:     folder,
+			Env:     launchEnv(launch, envVars),
+		}
+		if entry.Request == "" {
+			entry.Request = "launch"
+		}
+		if launch.Program != "" {
+			entry.Program = folder + "/" + filepath.ToSlash(substituteVars(launch.Program, envVars))
+		}
+		ws.Launch.Configurations = append(ws.Launch.Configurations, entry)
+	}
+
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type jetbrainsComponent struct {
+	XMLName       xml.Name         `xml:"component"`
+	Name          string           `xml:"name,attr"`
+	Configuration jetbrainsRunConf `xml:"configuration"`
+}
+
+type jetbrainsRunConf struct {
+	Default bool              `xml:"default,attr"`
+	Name    string            `xml:"name,attr"`
+	Type    string            `xml:"type,attr"`
+	Options []jetbrainsOption `xml:"option"`
+	Envs    []jetbrainsOption `xml:"envs>env"`
+	Method  struct {
+		V string `xml:"v,attr"`
+	} `xml:"method"`
+}
+
+type jetbrainsOption struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// renderJetBrainsRunConfig returns a shell script run configuration that runs
+// the launch command in the project directory with the launch env
+func renderJetBrainsRunConfig(launch WorkspaceLaunch, featureName string, envVars map[string]string) ([]byte, error) {
+	conf := jetbrainsRunConf{
+		Name: fmt.Sprintf("%s (%s)", launch.Name, featureName),
+		Type: "ShConfigurationType",
+		Options: []jetbrainsOption{
+			{Name: "SCRIPT_TEXT", Value: substituteVars(launch.Command, envVars)},
+			{Name: "INDEPENDENT_SCRIPT_PATH", Value: "true"},
+			{Name: "SCRIPT_PATH", Value: ""},
+			{Name: "SCRIPT_OPTIONS", Value: ""},
+			{Name: "INDEPENDENT_SCRIPT_WORKING_DIRECTORY", Value: "true"},
+			{Name: "SCRIPT_WORKING_DIRECTORY", Value: "$PROJECT_DIR$"},
+			{Name: "INDEPENDENT_INTERPRETER_PATH", Value: "true"},
+			{Name: "INTERPRETER_PATH", Value: "/bin/bash"},
+			{Name: "INTERPRETER_OPTIONS", Value: ""},
+			{Name: "EXECUTE_IN_TERMINAL", Value: "true"},
+			{Name: "EXECUTE_SCRIPT_FILE", Value: "false"},
+		},
+	}
+	conf.Method.V = "2"
+
+	env := launchEnv(launch, envVars)
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		conf.Envs = append(conf.Envs, jetbrainsOption{Name: key, Value: env[key]})
+	}
+
+	data, err := xml.MarshalIndent(jetbrainsComponent{Name: "ProjectRunConfigurationManager", Configuration: conf}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// runConfigNameRe matches the characters replaced in run configuration file names
+var runConfigNameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// JetBrainsRunConfigPath returns the path of a launch entry's run configuration
+func (c *WorktreeConfig) JetBrainsRunConfigPath(featureDir string, launch WorkspaceLaunch) string {
+	name := runConfigNameRe.ReplaceAllString(launch.Name, "_")
+	return filepath.Join(featureDir, c.Projects[launch.Project].Dir, jetbrainsRunDir, name+".run.xml")
+}
+
+// WriteWorkspace generates the feature's IDE configuration from the workspace
+// section and returns the written paths, relative to the feature dir. envVars
+// are the feature's env vars (as in .worktree-env). It does nothing when no
+// IDE is enabled.
+func (c *WorktreeConfig) WriteWorkspace(featureDir, featureName string, projects []string, envVars map[string]string) ([]string, error) {
+	var written []string
+	write := func(path string, data []byte) error {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		rel, err := filepath.Rel(featureDir, path)
+		if err != nil {
+			rel = path
+		}
+		written = append(written, rel)
+		return nil
+	}
+
+	if c.Workspace.VSCode {
+		data, err := c.renderVSCodeWorkspace(projects, envVars)
+		if err != nil {
+			return written, fmt.Errorf("failed to render VS Code workspace: %w", err)
+		}
+		if err := write(VSCodeWorkspacePath(featureDir, featureName), data); err != nil {
+			return written, err
+		}
+	}
+
+	if c.Workspace.JetBrains {
+		for _, launch := range c.Workspace.Launch {
+			if launch.Command == "" || !slices.Contains(projects, launch.Project) {
+				continue
+			}
+			data, err := renderJetBrainsRunConfig(launch, featureName, envVars)
+			if err != nil {
+				return written, fmt.Errorf("failed to render run configuration '%s': %w", launch.Name, err)
+			}
+			if err := write(c.JetBrainsRunConfigPath(featureDir, launch), data); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
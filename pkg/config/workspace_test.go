@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteWorkspace(t *testing.T) {
+	featureDir := t.TempDir()
+	cfg := &WorktreeConfig{
+		Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}, "frontend": {Dir: "web"}},
+		Workspace: WorkspaceConfig{
+			VSCode:    true,
+			JetBrains: true,
+			Launch: []WorkspaceLaunch{
+				{Name: "API server", Project: "backend", Type: "go", Program: "cmd/server", Command: "go run ./cmd/server", Env: map[string]string{"API_URL": "http://localhost:{APP_PORT}"}},
+				{Name: "Web", Project: "frontend", Command: "npm run dev"},
+			},
+		},
+	}
+	env := map[string]string{"APP_PORT": "8081"}
+
+	written, err := cfg.WriteWorkspace(featureDir, "feature-x", []string{"backend"}, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"feature-x.code-workspace", filepath.Join("backend", ".run", "API_server.run.xml")}
+	if strings.Join(written, ",") != strings.Join(want, ",") {
+		t.Fatalf("written = %v, want %v (the frontend is not part of the feature)", written, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(featureDir, "feature-x.code-workspace"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ws vscodeWorkspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		t.Fatalf("invalid JSON %q: %v", data, err)
+	}
+	if len(ws.Folders) != 1 || ws.Folders[0] != (vscodeFolder{Name: "backend", Path: "backend"}) {
+		t.Errorf("folders = %+v", ws.Folders)
+	}
+	if ws.Launch == nil || len(ws.Launch.Configurations) != 1 {
+		t.Fatalf("launch = %+v, want the API server configuration", ws.Launch)
+	}
+	launch := ws.Launch.Configurations[0]
+	if launch.Program != "${workspaceFolder:backend}/cmd/server" || launch.Request != "launch" {
+		t.Errorf("launch = %+v", launch)
+	}
+	if launch.Env["APP_PORT"] != "8081" || launch.Env["API_URL"] != "http://localhost:8081" {
+		t.Errorf("launch env = %v", launch.Env)
+	}
+
+	data, err = os.ReadFile(filepath.Join(featureDir, "backend", ".run", "API_server.run.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`name="API server (feature-x)" type="ShConfigurationType"`,
+		`<option name="SCRIPT_TEXT" value="go run ./cmd/server"></option>`,
+		`<env name="API_URL" value="http://localhost:8081"></env>`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("run configuration does not contain %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace WorkspaceConfig
+		wantErr   string
+	}{
+		{name: "disabled", workspace: WorkspaceConfig{}},
+		{name: "valid", workspace: WorkspaceConfig{VSCode: true, Launch: []WorkspaceLaunch{{Name: "api", Project: "backend", Type: "go"}}}},
+		{name: "launch without IDE", workspace: WorkspaceConfig{Launch: []WorkspaceLaunch{{Name: "api", Project: "backend", Type: "go"}}}, wantErr: "requires vscode or jetbrains"},
+		{name: "missing name", workspace: WorkspaceConfig{VSCode: true, Launch: []WorkspaceLaunch{{Project: "backend", Type: "go"}}}, wantErr: "name is required"},
+		{name: "duplicate name", workspace: WorkspaceConfig{VSCode: true, Launch: []WorkspaceLaunch{{Name: "a", Project: "backend", Type: "go"}, {Name: "a", Project: "backend", Type: "go"}}}, wantErr: "duplicate name"},
+		{name: "unknown project", workspace: WorkspaceConfig{JetBrains: true, Launch: []WorkspaceLaunch{{Name: "a", Project: "api", Command: "make"}}}, wantErr: "unknown project 'api'"},
+		{name: "nothing to launch", workspace: WorkspaceConfig{VSCode: true, Launch: []WorkspaceLaunch{{Name: "a", Project: "backend"}}}, wantErr: "type (VS Code) or command (JetBrains) is required"},
+		{name: "absolute program", workspace: WorkspaceConfig{VSCode: true, Launch: []WorkspaceLaunch{{Name: "a", Project: "backend", Type: "go", Program: "/usr/bin/x"}}}, wantErr: "must be relative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &WorktreeConfig{Projects: map[string]ProjectConfig{"backend": {Dir: "backend"}}, Workspace: tt.workspace}
+			err := cfg.validateWorkspace()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package crash writes crash dumps of panics: the command line, version,
+// stack and last output lines, anonymized and stored on the local machine
+// only. Nothing is ever sent anywhere.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir holds crash dumps, relative to the worktrees directory
+const Dir = ".crash"
+
+// IssuesURL is where bugs are filed
+const IssuesURL = "https://github.com/braunmar/worktree/issues"
+
+// Report is what a crash dump records
+type Report struct {
+	Time    time.Time
+	Version string
+	Args    []string    // Command line, without the program name
+	Panic   interface{} // Value the program panicked with
+	Stack   []byte      // Stack trace of the panicking goroutine
+	Recent  []string    // Last lines printed before the panic
+}
+
+// Anonymizer replaces paths that identify the user in a dump: each key is
+// replaced with its value, longest first
+type Anonymizer map[string]string
+
+// DefaultAnonymizer replaces the project root with <project> and the home
+// directory with ~
+func DefaultAnonymizer(projectRoot string) Anonymizer {
+	a := Anonymizer{}
+	if projectRoot != "" {
+		a[projectRoot] = "<project>"
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		a[home] = "~"
+	}
+	return a
+}
+
+// Apply returns text with the anonymized paths replaced
+func (a Anonymizer) Apply(text string) string {
+	keys := make([]string, 0, len(a))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	// Longest first, so the project root wins over the home directory holding it
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	for _, key := range keys {
+		text = strings.ReplaceAll(text, key, a[key])
+	}
+	return text
+}
+
+// Format renders the report as the text of a dump
+func (r Report) Format(a Anonymizer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "worktree crash report\n\n")
+	fmt.Fprintf(&b, "Time:    %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", r.Version)
+	fmt.Fprintf(&b, "Go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Command: worktree %s\n", strings.Join(r.Args, " "))
+	fmt.Fprintf(&b, "Panic:   %v\n", r.Panic)
+
+	fmt.Fprintf(&b, "\nRecent output:\n")
+	if len(r.Recent) == 0 {
+		fmt.Fprintf(&b, "  (none)\n")
+	}
+	for _, line := range r.Recent {
+		fmt.Fprintf(&b, "  %s\n", line)
+	}
+
+	fmt.Fprintf(&b, "\nStack:\n%s", r.Stack)
+	return a.Apply(b.String())
+}
+
+// Write stores the report as a new dump in dir and returns its path
+func Write(dir string, r Report, a Anonymizer) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "crash-"+r.Time.Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, []byte(r.Format(a)), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,60 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnonymizerApply(t *testing.T) {
+	a := Anonymizer{
+		"/home/dev":         "~",
+		"/home/dev/project": "<project>",
+	}
+	got := a.Apply("cd /home/dev/project/backend && ls /home/dev/.config")
+	want := "cd <project>/backend && ls ~/.config"
+	if got != want {
+		t.Errorf("Apply() = %q, want %q", got, want)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), Dir)
+	r := Report{
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Version: "v1.2.3 (abc1234)",
+		Args:    []string{"new-feature", "feature/login"},
+		Panic:   "boom",
+		Stack:   []byte("goroutine 1 [running]:\nmain.main()\n\t/home/dev/project/main.go:10\n"),
+		Recent:  []string{"Creating worktree in /home/dev/project/worktrees/feature-login"},
+	}
+
+	path, err := Write(dir, r, Anonymizer{"/home/dev/project": "<project>"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := filepath.Join(dir, "crash-20260102-030405.txt"); path != want {
+		t.Errorf("Write() path = %q, want %q", path, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := string(data)
+	for _, want := range []string{
+		"Version: v1.2.3 (abc1234)",
+		"Command: worktree new-feature feature/login",
+		"Panic:   boom",
+		"  Creating worktree in <project>/worktrees/feature-login",
+		"\t<project>/main.go:10",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("dump missing %q:\n%s", want, dump)
+		}
+	}
+	if strings.Contains(dump, "/home/dev") {
+		t.Errorf("dump not anonymized:\n%s", dump)
+	}
+}
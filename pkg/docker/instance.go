@@ -3,7 +3,8 @@ package docker
 import (
 	"bytes"
 	"fmt"
-	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -12,7 +13,7 @@ func IsFeatureRunning(projectName, featureName string) bool {
 	// Container name format: {project-name}-{feature-name}-app-1
 	prefix := fmt.Sprintf("%s-%s-", projectName, featureName)
 
-	cmd := exec.Command("docker", "ps", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}")
+	cmd := current.Command("ps", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -27,12 +28,12 @@ func IsFeatureRunning(projectName, featureName string) bool {
 // GetRunningFeatures returns a list of running feature names
 func GetRunningFeatures(projectName string) ([]string, error) {
 	prefix := projectName + "-"
-	cmd := exec.Command("docker", "ps", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}")
+	cmd := current.Command("ps", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("failed to list docker containers: %w", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
 
 	featuresMap := make(map[string]bool)
@@ -74,7 +75,7 @@ func GetRunningFeatures(projectName string) ([]string, error) {
 func StopFeature(projectName, featureName string, worktreePath string, projectInfo map[string]string) error {
 	defaultComposeProject := fmt.Sprintf("%s-%s", projectName, featureName)
 
-	// Tier 1: Try docker compose down in each project directory with correct compose project name
+	// Tier 1: Try compose down in each project directory with correct compose project name
 	allStopped := true
 	for projectDir, composeName := range projectInfo {
 		fullPath := worktreePath + "/" + projectDir
@@ -86,7 +87,7 @@ func StopFeature(projectName, featureName string, worktreePath string, projectIn
 		return nil
 	}
 
-	// Tier 2: Try docker compose with explicit project names (no directory needed)
+	// Tier 2: Try compose with explicit project names (no directory needed)
 	allStopped = true
 	for _, composeName := range projectInfo {
 		if err := stopViaComposeProject(composeName); err != nil {
@@ -110,12 +111,18 @@ func StopFeature(projectName, featureName string, worktreePath string, projectIn
 	}
 
 	// If all methods fail, return error but allow removal to continue
-	return fmt.Errorf("unable to stop services (docker may not be available)")
+	return fmt.Errorf("unable to stop services (%s may not be available)", current.Name)
 }
 
-// stopViaCompose runs docker compose down in the specified directory
+// RemoveFeatureContainers stops and removes every container of a feature by name,
+// without needing its worktree or compose files (used for orphaned containers)
+func RemoveFeatureContainers(projectName, featureName string) error {
+	return stopContainersByName(fmt.Sprintf("%s-%s", projectName, featureName))
+}
+
+// stopViaCompose runs compose down in the specified directory
 func stopViaCompose(dir string, composeProject string) error {
-	cmd := exec.Command("docker", "compose", "-p", composeProject, "down", "--remove-orphans")
+	cmd := current.ComposeCommand("-p", composeProject, "down", "--remove-orphans")
 	cmd.Dir = dir
 
 	var stderr bytes.Buffer
@@ -127,9 +134,9 @@ func stopViaCompose(dir string, composeProject string) error {
 	return nil
 }
 
-// stopViaComposeProject runs docker compose down with explicit project name
+// stopViaComposeProject runs compose down with explicit project name
 func stopViaComposeProject(composeProject string) error {
-	cmd := exec.Command("docker", "compose", "-p", composeProject, "down", "--remove-orphans")
+	cmd := current.ComposeCommand("-p", composeProject, "down", "--remove-orphans")
 
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -144,7 +151,7 @@ func stopViaComposeProject(composeProject string) error {
 func stopContainersByName(composeProject string) error {
 	// Find running containers
 	prefix := composeProject + "-"
-	cmd := exec.Command("docker", "ps", "-q", "--filter", fmt.Sprintf("name=%s", prefix))
+	cmd := current.Command("ps", "-q", "--filter", fmt.Sprintf("name=%s", prefix))
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -160,14 +167,14 @@ func stopContainersByName(composeProject string) error {
 
 	// Stop containers
 	args := append([]string{"stop"}, containerIDs...)
-	stopCmd := exec.Command("docker", args...)
+	stopCmd := current.Command(args...)
 	if err := stopCmd.Run(); err != nil {
 		return fmt.Errorf("failed to stop containers: %w", err)
 	}
 
 	// Remove containers
 	args = append([]string{"rm"}, containerIDs...)
-	rmCmd := exec.Command("docker", args...)
+	rmCmd := current.Command(args...)
 	if err := rmCmd.Run(); err != nil {
 		// Warn but don't fail - containers are stopped
 		return nil
@@ -180,7 +187,7 @@ func stopContainersByName(composeProject string) error {
 func GetFeatureContainerStatus(projectName, featureName string) (map[string]string, error) {
 	prefix := fmt.Sprintf("%s-%s-", projectName, featureName)
 
-	cmd := exec.Command("docker", "ps", "-a", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}:{{.Status}}")
+	cmd := current.Command("ps", "-a", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Names}}:{{.Status}}")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -215,3 +222,44 @@ func GetFeatureContainerStatus(projectName, featureName string) (map[string]stri
 
 	return status, nil
 }
+
+// publishedPortRe matches host ports in docker ps output like "0.0.0.0:8080->8080/tcp"
+var publishedPortRe = regexp.MustCompile(`:(\d+)->`)
+
+// GetFeaturePublishedPorts returns the host ports published by a feature's containers
+func GetFeaturePublishedPorts(projectName, featureName string) (map[int]bool, error) {
+	prefix := fmt.Sprintf("%s-%s-", projectName, featureName)
+
+	cmd := current.Command("ps", "--filter", fmt.Sprintf("name=%s", prefix), "--format", "{{.Ports}}")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to get published ports: %w", err)
+	}
+
+	ports := make(map[int]bool)
+	for _, match := range publishedPortRe.FindAllStringSubmatch(stdout.String(), -1) {
+		if port, err := strconv.Atoi(match[1]); err == nil {
+			ports[port] = true
+		}
+	}
+	return ports, nil
+}
+
+// exitedStatusRe matches container statuses like "Exited (0) 5 seconds ago"
+var exitedStatusRe = regexp.MustCompile(`(?i)^exited \((-?\d+)\)`)
+
+// ExitCode returns the exit code of a container status as reported by
+// GetFeatureContainerStatus, and false if the container has not exited
+func ExitCode(status string) (int, bool) {
+	match := exitedStatusRe.FindStringSubmatch(strings.TrimSpace(status))
+	if match == nil {
+		return 0, false
+	}
+	code, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
@@ -0,0 +1,24 @@
+package docker
+
+import "testing"
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantCode   int
+		wantExited bool
+	}{
+		{status: "Up 5 seconds", wantExited: false},
+		{status: "Exited (0) 3 seconds ago", wantCode: 0, wantExited: true},
+		{status: "Exited (137) About a minute ago", wantCode: 137, wantExited: true},
+		{status: "exited (1) 2 seconds ago", wantCode: 1, wantExited: true},
+		{status: "Created", wantExited: false},
+	}
+
+	for _, tt := range tests {
+		code, exited := ExitCode(tt.status)
+		if code != tt.wantCode || exited != tt.wantExited {
+			t.Errorf("ExitCode(%q) = %d, %v, want %d, %v", tt.status, code, exited, tt.wantCode, tt.wantExited)
+		}
+	}
+}
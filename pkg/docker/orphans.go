@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// composeServiceLabel is the compose label naming a container's service
+const composeServiceLabel = "com.docker.compose.service"
+
+// ComposeContainer is a container created by compose for a compose project
+type ComposeContainer struct {
+	Name    string
+	Service string
+	State   string // e.g. running, exited
+}
+
+// Running reports whether the container is running
+func (c ComposeContainer) Running() bool {
+	return c.State == "running"
+}
+
+// ComposeContainers lists the containers of a compose project, running or
+// not, by compose's project label rather than by name
+func ComposeContainers(composeProject string) ([]ComposeContainer, error) {
+	cmd := current.Command("ps", "-a",
+		"--filter", fmt.Sprintf("label=%s=%s", composeProjectLabel, composeProject),
+		"--format", fmt.Sprintf(`{{.Names}}\t{{.Label "%s"}}\t{{.State}}`, composeServiceLabel))
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list containers of %s: %w", composeProject, err)
+	}
+	return parseComposeContainers(stdout.String()), nil
+}
+
+// parseComposeContainers parses "name\tservice\tstate" lines
+func parseComposeContainers(output string) []ComposeContainer {
+	var containers []ComposeContainer
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+		containers = append(containers, ComposeContainer{Name: fields[0], Service: fields[1], State: strings.ToLower(fields[2])})
+	}
+	return containers
+}
+
+// ComposeServices returns the services of the compose configuration in dir.
+// env is the environment compose runs with (COMPOSE_FILE, interpolated vars).
+func ComposeServices(dir string, env []string) ([]string, error) {
+	cmd := current.ComposeCommand("config", "--services")
+	cmd.Dir = dir
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to read compose services in %s: %s", dir, strings.TrimSpace(stderr.String()))
+	}
+	return strings.Fields(stdout.String()), nil
+}
+
+// Orphans returns the containers whose service is not among services: left
+// behind by services removed or renamed in the compose file since they started
+func Orphans(containers []ComposeContainer, services []string) []ComposeContainer {
+	var orphans []ComposeContainer
+	for _, c := range containers {
+		if !slices.Contains(services, c.Service) {
+			orphans = append(orphans, c)
+		}
+	}
+	return orphans
+}
+
+// StopContainers stops the named containers
+func StopContainers(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if out, err := current.Command(append([]string{"stop"}, names...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to stop containers: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RemoveContainers stops and removes the named containers
+func RemoveContainers(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if out, err := current.Command(append([]string{"rm", "-f"}, names...)...).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to remove containers: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package docker
+
+import "testing"
+
+func TestOrphans(t *testing.T) {
+	containers := parseComposeContainers("proj-app-1\tapp\trunning\nproj-old-1\told\tExited\n\nbroken-line\n")
+	if len(containers) != 2 {
+		t.Fatalf("parsed %+v, want 2 containers", containers)
+	}
+	if !containers[0].Running() || containers[1].Running() {
+		t.Errorf("running = %v, %v; want true, false", containers[0].Running(), containers[1].Running())
+	}
+
+	orphans := Orphans(containers, []string{"app", "db"})
+	if len(orphans) != 1 || orphans[0].Name != "proj-old-1" || orphans[0].Service != "old" {
+		t.Errorf("orphans = %+v, want proj-old-1", orphans)
+	}
+	if orphans := Orphans(containers, []string{"app", "old"}); len(orphans) != 0 {
+		t.Errorf("orphans = %+v, want none", orphans)
+	}
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Supported container runtime names (container_runtime in .worktree.yml)
+const (
+	RuntimeAuto   = "auto"
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// Runtime describes the container CLI used to inspect and stop feature containers
+type Runtime struct {
+	Name    string   // "docker" or "podman"
+	Binary  string   // CLI binary for container commands (ps, stop, rm)
+	Compose []string // Compose invocation, e.g. ["docker", "compose"] or ["podman-compose"]
+}
+
+// lookPath is exec.LookPath, swappable in tests
+var lookPath = exec.LookPath
+
+// current is the runtime used by all package functions (docker until configured)
+var current = dockerRuntime()
+
+func dockerRuntime() Runtime {
+	return Runtime{Name: RuntimeDocker, Binary: "docker", Compose: []string{"docker", "compose"}}
+}
+
+// podmanRuntime prefers the standalone podman-compose and falls back to the
+// "podman compose" wrapper shipped with podman 4+
+func podmanRuntime() Runtime {
+	compose := []string{"podman", "compose"}
+	if _, err := lookPath("podman-compose"); err == nil {
+		compose = []string{"podman-compose"}
+	}
+	return Runtime{Name: RuntimePodman, Binary: "podman", Compose: compose}
+}
+
+// Detect picks docker when it is on PATH, otherwise podman, otherwise docker
+func Detect() Runtime {
+	if _, err := lookPath("docker"); err == nil {
+		return dockerRuntime()
+	}
+	if _, err := lookPath("podman"); err == nil {
+		return podmanRuntime()
+	}
+	return dockerRuntime()
+}
+
+// Resolve returns the runtime for a container_runtime config value.
+// An empty value or "auto" auto-detects.
+func Resolve(name string) (Runtime, error) {
+	switch name {
+	case "", RuntimeAuto:
+		return Detect(), nil
+	case RuntimeDocker:
+		return dockerRuntime(), nil
+	case RuntimePodman:
+		return podmanRuntime(), nil
+	default:
+		return Runtime{}, fmt.Errorf("unknown container runtime '%s' (expected docker, podman or auto)", name)
+	}
+}
+
+// Configure selects the runtime used by this package for the rest of the process
+func Configure(name string) error {
+	rt, err := Resolve(name)
+	if err != nil {
+		return err
+	}
+	current = rt
+	return nil
+}
+
+// Current returns the configured container runtime
+func Current() Runtime {
+	return current
+}
+
+// Command builds a runtime CLI command (e.g. "podman ps ..."), shown in verbose mode
+func (r Runtime) Command(args ...string) *exec.Cmd {
+	cmd := exec.Command(r.Binary, args...)
+	ui.Trace(ui.ScopeDocker, cmd)
+	return cmd
+}
+
+// ComposeCommand builds a compose command (e.g. "podman-compose -p x down"),
+// shown in verbose mode
+func (r Runtime) ComposeCommand(args ...string) *exec.Cmd {
+	full := append(append([]string{}, r.Compose[1:]...), args...)
+	cmd := exec.Command(r.Compose[0], full...)
+	ui.Trace(ui.ScopeDocker, cmd)
+	return cmd
+}
+
+// ComposeString returns the compose invocation as a shell string (e.g. "docker compose")
+func (r Runtime) ComposeString() string {
+	s := r.Compose[0]
+	for _, part := range r.Compose[1:] {
+		s += " " + part
+	}
+	return s
+}
@@ -0,0 +1,96 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+)
+
+// stubLookPath makes only the given binaries resolvable for the duration of a test
+func stubLookPath(t *testing.T, available ...string) {
+	t.Helper()
+	orig := lookPath
+	t.Cleanup(func() { lookPath = orig })
+
+	lookPath = func(file string) (string, error) {
+		for _, name := range available {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name        string
+		runtime     string
+		available   []string
+		wantName    string
+		wantCompose string
+		wantErr     bool
+	}{
+		{name: "explicit docker", runtime: "docker", wantName: "docker", wantCompose: "docker compose"},
+		{name: "explicit podman with podman-compose", runtime: "podman", available: []string{"podman", "podman-compose"}, wantName: "podman", wantCompose: "podman-compose"},
+		{name: "explicit podman without podman-compose", runtime: "podman", available: []string{"podman"}, wantName: "podman", wantCompose: "podman compose"},
+		{name: "auto prefers docker", runtime: "auto", available: []string{"docker", "podman"}, wantName: "docker", wantCompose: "docker compose"},
+		{name: "auto falls back to podman", runtime: "", available: []string{"podman"}, wantName: "podman", wantCompose: "podman compose"},
+		{name: "auto with nothing installed", runtime: "", wantName: "docker", wantCompose: "docker compose"},
+		{name: "unknown runtime", runtime: "containerd", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubLookPath(t, tt.available...)
+
+			rt, err := Resolve(tt.runtime)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if rt.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", rt.Name, tt.wantName)
+			}
+			if rt.ComposeString() != tt.wantCompose {
+				t.Errorf("Compose = %q, want %q", rt.ComposeString(), tt.wantCompose)
+			}
+		})
+	}
+}
+
+func TestComposeCommandArgs(t *testing.T) {
+	rt := Runtime{Name: "podman", Binary: "podman", Compose: []string{"podman", "compose"}}
+	cmd := rt.ComposeCommand("-p", "proj", "down")
+
+	want := []string{"podman", "compose", "-p", "proj", "down"}
+	if len(cmd.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", cmd.Args, want)
+	}
+	for i := range want {
+		if cmd.Args[i] != want[i] {
+			t.Errorf("Args[%d] = %q, want %q", i, cmd.Args[i], want[i])
+		}
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	stubLookPath(t, "podman")
+	orig := current
+	t.Cleanup(func() { current = orig })
+
+	if err := Configure("podman"); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if Current().Binary != "podman" {
+		t.Errorf("Current().Binary = %q, want podman", Current().Binary)
+	}
+
+	if err := Configure("bogus"); err == nil {
+		t.Error("expected error for unknown runtime")
+	}
+	if Current().Binary != "podman" {
+		t.Error("failed Configure should keep the previous runtime")
+	}
+}
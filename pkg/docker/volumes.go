@@ -0,0 +1,104 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// volumeHelperImage is the image used to run tar against a volume's contents
+const volumeHelperImage = "alpine:3"
+
+// Compose labels identifying which project and service volume a volume belongs to
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	composeVolumeLabel  = "com.docker.compose.volume"
+)
+
+// Volume is a named volume created by compose
+type Volume struct {
+	Name          string
+	ComposeVolume string // Volume name as declared in the compose file
+}
+
+// ListComposeVolumes returns the volumes compose created for a compose project
+func ListComposeVolumes(composeProject string) ([]Volume, error) {
+	cmd := current.Command("volume", "ls",
+		"--filter", fmt.Sprintf("label=%s=%s", composeProjectLabel, composeProject),
+		"--format", fmt.Sprintf(`{{.Name}}\t{{.Label "%s"}}`, composeVolumeLabel))
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list volumes: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	var volumes []Volume
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		name, composeVolume, _ := strings.Cut(line, "\t")
+		volumes = append(volumes, Volume{Name: name, ComposeVolume: composeVolume})
+	}
+
+	return volumes, nil
+}
+
+// VolumeExists reports whether a volume with the given name exists
+func VolumeExists(name string) bool {
+	return current.Command("volume", "inspect", name).Run() == nil
+}
+
+// ExportVolume writes the contents of a volume to destFile as an uncompressed tar
+func ExportVolume(name, destFile string) error {
+	dir, err := filepath.Abs(filepath.Dir(destFile))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", destFile, err)
+	}
+
+	return runVolumeHelper(name, dir, "tar", "cf", "/backup/"+filepath.Base(destFile), "-C", "/volume", ".")
+}
+
+// ImportVolume creates a volume labelled for compose and fills it from a tar
+// written by ExportVolume
+func ImportVolume(vol Volume, composeProject, srcFile string) error {
+	dir, err := filepath.Abs(filepath.Dir(srcFile))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", srcFile, err)
+	}
+
+	args := []string{"volume", "create",
+		"--label", fmt.Sprintf("%s=%s", composeProjectLabel, composeProject)}
+	if vol.ComposeVolume != "" {
+		args = append(args, "--label", fmt.Sprintf("%s=%s", composeVolumeLabel, vol.ComposeVolume))
+	}
+	cmd := current.Command(append(args, vol.Name)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create volume %s: %s", vol.Name, strings.TrimSpace(stderr.String()))
+	}
+
+	return runVolumeHelper(vol.Name, dir, "tar", "xf", "/backup/"+filepath.Base(srcFile), "-C", "/volume")
+}
+
+// runVolumeHelper runs a command in a throwaway container with the volume mounted
+// at /volume and hostDir mounted at /backup
+func runVolumeHelper(volume, hostDir string, command ...string) error {
+	args := append([]string{"run", "--rm",
+		"-v", volume + ":/volume",
+		"-v", hostDir + ":/backup",
+		volumeHelperImage}, command...)
+	cmd := current.Command(args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s %s failed for volume %s: %s", current.Name, command[0], volume, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -10,16 +10,28 @@ import (
 
 // RunHealthCheck runs all diagnostic checks and returns a comprehensive report
 func RunHealthCheck(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, opts Options) *Report {
-	report := &Report{}
+	report := &Report{Feature: opts.FeatureFilter}
 
 	// 1. Check Docker health
 	report.Docker = CheckDocker()
+	report.Git = CheckGitVersion()
+
+	// Check integrity of the registry and instance markers
+	report.Integrity = CheckIntegrity(cfg, opts.FeatureFilter)
 
 	// 2. Check consistency (registry vs directories vs containers)
-	report.Consistency = CheckConsistency(cfg, reg, workCfg.ProjectName)
+	report.Consistency = CheckConsistency(cfg, reg, workCfg.ProjectName, opts.FeatureFilter)
 
 	// 3. Get worktrees to check
 	worktrees := filterWorktrees(reg.List(), opts.FeatureFilter)
+	for _, wt := range worktrees {
+		report.Symlinks = append(report.Symlinks, CheckSymlinks(cfg, workCfg, wt)...)
+	}
+
+	// Containers left behind by services removed from compose files
+	if report.Docker.Running {
+		report.Consistency.ServiceOrphans = CheckServiceOrphans(cfg, workCfg, worktrees)
+	}
 
 	// Determine the first project directory for git/staleness checks
 	firstProjectDir := workCfg.GetFirstProjectDir()
@@ -29,6 +41,7 @@ func RunHealthCheck(cfg *config.Config, workCfg *config.WorktreeConfig, reg *reg
 		projectPath := filepath.Join(cfg.WorktreeFeaturePath(wt.Normalized), firstProjectDir)
 		gitReport := CheckGitStatus(cfg, wt, projectPath, !opts.NoFetch)
 		report.GitStatus = append(report.GitStatus, gitReport)
+		report.Submodules = append(report.Submodules, CheckSubmodules(cfg, workCfg, wt)...)
 	}
 
 	// 5. Check staleness for each worktree
@@ -41,28 +54,33 @@ func RunHealthCheck(cfg *config.Config, workCfg *config.WorktreeConfig, reg *reg
 		}
 	}
 
-	// 6. Check port allocations
+	// 6. Check port allocations (range statistics always cover all features)
 	report.Ports = CheckPorts(reg, workCfg)
+	if opts.FeatureFilter != "" {
+		var outOfRange []PortOutOfRange
+		for _, p := range report.Ports.OutOfRange {
+			if p.Feature == opts.FeatureFilter {
+				outOfRange = append(outOfRange, p)
+			}
+		}
+		report.Ports.OutOfRange = outOfRange
+	}
+	CheckPortBindings(cfg, worktrees, workCfg, &report.Ports)
 
 	// 7. Build summary
-	report.Summary = buildSummary(report, reg, workCfg.ProjectName)
-
-	// 8. Auto-fix if requested
-	if opts.AutoFix {
-		applyFixes(cfg, reg, report)
-	}
+	report.Summary = buildSummary(report, worktrees, workCfg.ProjectName)
 
 	return report
 }
 
 // buildSummary calculates overall health metrics
-func buildSummary(report *Report, reg *registry.Registry, projectName string) Summary {
+func buildSummary(report *Report, worktrees []*registry.Worktree, projectName string) Summary {
 	summary := Summary{
-		TotalWorktrees: len(reg.List()),
+		TotalWorktrees: len(worktrees),
 	}
 
 	// Count running worktrees
-	for _, wt := range reg.List() {
+	for _, wt := range worktrees {
 		if docker.IsFeatureRunning(projectName, wt.Normalized) {
 			summary.RunningWorktrees++
 		}
@@ -76,7 +94,11 @@ func buildSummary(report *Report, reg *registry.Registry, projectName string) Su
 	}
 
 	// Count errors and warnings
-	// Errors: orphaned registry entries, missing directories, ports out of range, behind main
+	// Errors: missing or too old git, checksum mismatches, orphaned registry entries, missing directories, ports out of range, behind main
+	if report.Git.Error != "" || !report.Git.Supported {
+		summary.ErrorsCount++
+	}
+	summary.ErrorsCount += len(report.Integrity)
 	summary.ErrorsCount += len(report.Consistency.OrphanedRegistryEntries)
 	summary.ErrorsCount += len(report.Ports.OutOfRange)
 
@@ -88,16 +110,21 @@ func buildSummary(report *Report, reg *registry.Registry, projectName string) Su
 		}
 	}
 
-	// Warnings: orphaned directories/containers, uncommitted changes, high staleness
+	// Warnings: git features needing a newer git, orphaned directories/containers, broken symlinks, uncommitted changes, uninitialized submodules, high staleness
+	summary.WarningsCount += len(report.Git.Unavailable)
 	summary.WarningsCount += len(report.Consistency.OrphanedDirectories)
 	summary.WarningsCount += len(report.Consistency.OrphanedContainers)
+	summary.WarningsCount += len(report.Consistency.ServiceOrphans)
 	summary.WarningsCount += len(report.Ports.Conflicts)
+	summary.WarningsCount += len(report.Ports.Unbound)
 
 	for _, gs := range report.GitStatus {
 		if gs.UncommittedCount > 0 {
 			summary.WarningsCount++
 		}
 	}
+	summary.WarningsCount += len(report.Submodules)
+	summary.WarningsCount += len(report.Symlinks)
 
 	for _, s := range report.Staleness {
 		if s.Score >= 2 {
@@ -116,16 +143,3 @@ func buildSummary(report *Report, reg *registry.Registry, projectName string) Su
 
 	return summary
 }
-
-// applyFixes attempts to fix safe issues automatically
-func applyFixes(cfg *config.Config, reg *registry.Registry, report *Report) {
-	// Fix: Remove orphaned registry entries
-	for _, orphan := range report.Consistency.OrphanedRegistryEntries {
-		reg.Remove(orphan)
-	}
-
-	// Save registry if any fixes were applied
-	if len(report.Consistency.OrphanedRegistryEntries) > 0 {
-		reg.Save()
-	}
-}
@@ -7,16 +7,21 @@ import (
 	"os"
 )
 
-// CheckConsistency checks for mismatches between registry, directories, and containers
-func CheckConsistency(cfg *config.Config, reg *registry.Registry, projectName string) ConsistencyReport {
+// CheckConsistency checks for mismatches between registry, directories, and
+// containers. With a feature, only that feature's registry entry is checked:
+// orphaned directories and containers belong to other features by definition.
+func CheckConsistency(cfg *config.Config, reg *registry.Registry, projectName, feature string) ConsistencyReport {
 	report := ConsistencyReport{}
 
 	// Check registry entries have directories
-	for _, wt := range reg.List() {
+	for _, wt := range filterWorktrees(reg.List(), feature) {
 		if !cfg.WorktreeExists(wt.Normalized) {
 			report.OrphanedRegistryEntries = append(report.OrphanedRegistryEntries, wt.Normalized)
 		}
 	}
+	if feature != "" {
+		return report
+	}
 
 	// Check directories have registry entries
 	worktreeDir := cfg.WorktreeDir
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package doctor
+
+import "syscall"
+
+// diskFreeMB returns the space available to unprivileged users on the
+// filesystem holding dir, in MB
+func diskFreeMB(dir string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize) / (1024 * 1024), nil
+}
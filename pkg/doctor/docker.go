@@ -2,21 +2,23 @@ package doctor
 
 import (
 	"bytes"
-	"os/exec"
 	"strings"
+
+	"github.com/braunmar/worktree/pkg/docker"
 )
 
-// CheckDocker checks Docker installation and availability
+// CheckDocker checks container runtime (docker or podman) installation and availability
 func CheckDocker() DockerHealth {
-	health := DockerHealth{}
+	rt := docker.Current()
+	health := DockerHealth{Runtime: rt.Name}
 
-	// Check if docker command exists
-	versionCmd := exec.Command("docker", "--version")
+	// Check if runtime command exists
+	versionCmd := rt.Command("--version")
 	var versionOut bytes.Buffer
 	versionCmd.Stdout = &versionOut
 
 	if err := versionCmd.Run(); err != nil {
-		health.Error = "Docker not installed or not in PATH"
+		health.Error = rt.Binary + " not installed or not in PATH"
 		return health
 	}
 
@@ -24,16 +26,16 @@ func CheckDocker() DockerHealth {
 	health.Version = strings.TrimSpace(versionOut.String())
 
 	// Check if daemon is running
-	psCmd := exec.Command("docker", "ps")
+	psCmd := rt.Command("ps")
 	if err := psCmd.Run(); err != nil {
-		health.Error = "Docker daemon not running"
+		health.Error = rt.Binary + " daemon not running"
 		return health
 	}
 
 	health.Running = true
 
-	// Check docker compose
-	composeCmd := exec.Command("docker", "compose", "version")
+	// Check compose
+	composeCmd := rt.ComposeCommand("version")
 	health.ComposeAvailable = composeCmd.Run() == nil
 
 	return health
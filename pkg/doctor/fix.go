@@ -0,0 +1,212 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Fix kinds, in the order they are applied
+const (
+	FixIntegrity     = "integrity"      // Restore a state file failing its checksum from backup, or accept it
+	FixContainers    = "containers"     // Stop and remove containers of a feature not in the registry
+	FixOrphans       = "orphans"        // Remove containers of services no longer in a compose file
+	FixRegistryEntry = "registry-entry" // Drop a registry entry whose directory is gone
+	FixDirectory     = "directory"      // Delete a worktrees/ directory not in the registry
+	FixGitMetadata   = "git-metadata"   // Prune stale git worktree metadata in a project repo
+)
+
+// Fix statuses
+const (
+	FixPlanned = "planned"
+	FixApplied = "applied"
+	FixSkipped = "skipped"
+	FixFailed  = "failed"
+)
+
+// FixAction is one remediation step proposed or performed by doctor --fix
+type FixAction struct {
+	Kind         string
+	Target       string // Feature, directory or project name
+	Description  string
+	NeedsConfirm bool     // Destructive: only applied after confirmation
+	Containers   []string `json:",omitempty"` // Containers removed by an orphans fix
+	Status       string
+	Error        string `json:",omitempty"`
+}
+
+// PlanFixes turns the consistency issues in report into fix actions. Directory
+// deletions need confirmation; everything else only touches worktree-managed state.
+func PlanFixes(cfg *config.Config, workCfg *config.WorktreeConfig, report *Report) []FixAction {
+	var actions []FixAction
+
+	for _, feature := range sortedCopy(report.Consistency.OrphanedContainers) {
+		actions = append(actions, FixAction{
+			Kind:        FixContainers,
+			Target:      feature,
+			Description: fmt.Sprintf("stop and remove containers %s-%s-*", workCfg.ProjectName, feature),
+		})
+	}
+
+	for _, orphan := range report.Consistency.ServiceOrphans {
+		actions = append(actions, FixAction{
+			Kind:        FixOrphans,
+			Target:      orphan.Feature + "/" + orphan.Project,
+			Description: fmt.Sprintf("remove orphaned containers %s", strings.Join(orphan.Containers, ", ")),
+			Containers:  orphan.Containers,
+		})
+	}
+
+	for _, feature := range sortedCopy(report.Consistency.OrphanedRegistryEntries) {
+		actions = append(actions, FixAction{
+			Kind:        FixRegistryEntry,
+			Target:      feature,
+			Description: fmt.Sprintf("remove registry entry '%s' (directory missing)", feature),
+		})
+	}
+
+	for _, dir := range sortedCopy(report.Consistency.OrphanedDirectories) {
+		actions = append(actions, FixAction{
+			Kind:         FixDirectory,
+			Target:       dir,
+			Description:  fmt.Sprintf("delete directory worktrees/%s (not in registry)", dir),
+			NeedsConfirm: true,
+		})
+	}
+
+	// Deleting a directory leaves git metadata behind, so prune every project then
+	deletesDirs := len(report.Consistency.OrphanedDirectories) > 0
+	projectNames := make([]string, 0, len(workCfg.Projects))
+	for name := range workCfg.Projects {
+		projectNames = append(projectNames, name)
+	}
+	sort.Strings(projectNames)
+	for _, name := range projectNames {
+		repoPath := filepath.Join(cfg.ProjectRoot, workCfg.Projects[name].Dir)
+		prunable, err := git.PrunableWorktrees(repoPath)
+		if err != nil || (len(prunable) == 0 && !deletesDirs) {
+			continue
+		}
+		description := fmt.Sprintf("prune git worktree metadata in %s", workCfg.Projects[name].Dir)
+		if len(prunable) > 0 {
+			description += fmt.Sprintf(" (%d stale)", len(prunable))
+		}
+		actions = append(actions, FixAction{
+			Kind:        FixGitMetadata,
+			Target:      name,
+			Description: description,
+		})
+	}
+
+	for i := range actions {
+		actions[i].Status = FixPlanned
+	}
+	return actions
+}
+
+// ApplyFixes performs the planned actions in order. confirm is asked before every
+// action with NeedsConfirm; declined actions are marked skipped. The registry is
+// saved once if any entry was removed.
+func ApplyFixes(cfg *config.Config, workCfg *config.WorktreeConfig, reg *registry.Registry, actions []FixAction, confirm func(FixAction) bool) {
+	registryChanged := false
+
+	for i := range actions {
+		action := &actions[i]
+		if action.NeedsConfirm && !confirm(*action) {
+			action.Status = FixSkipped
+			continue
+		}
+
+		var err error
+		switch action.Kind {
+		case FixIntegrity:
+			_, err = config.RepairChecksummed(filepath.Join(cfg.ProjectRoot, action.Target))
+		case FixContainers:
+			err = docker.RemoveFeatureContainers(workCfg.ProjectName, action.Target)
+		case FixOrphans:
+			err = docker.RemoveContainers(action.Containers)
+		case FixRegistryEntry:
+			reg.Remove(action.Target)
+			registryChanged = true
+		case FixDirectory:
+			err = os.RemoveAll(cfg.WorktreeFeaturePath(action.Target))
+		case FixGitMetadata:
+			err = git.PruneWorktrees(filepath.Join(cfg.ProjectRoot, workCfg.Projects[action.Target].Dir))
+		default:
+			err = fmt.Errorf("unknown fix kind '%s'", action.Kind)
+		}
+
+		if err != nil {
+			action.Status = FixFailed
+			action.Error = err.Error()
+		} else {
+			action.Status = FixApplied
+		}
+	}
+
+	if registryChanged {
+		if err := reg.Save(); err != nil {
+			for i := range actions {
+				if actions[i].Kind == FixRegistryEntry && actions[i].Status == FixApplied {
+					actions[i].Status = FixFailed
+					actions[i].Error = fmt.Sprintf("failed to save registry: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// PrintFixes outputs planned (dryRun) or applied fix actions
+func PrintFixes(actions []FixAction, dryRun bool) {
+	ui.Section("🔧 FIXES")
+
+	if len(actions) == 0 {
+		ui.Success("Nothing to fix")
+		return
+	}
+
+	for _, action := range actions {
+		switch {
+		case dryRun:
+			suffix := ""
+			if action.NeedsConfirm {
+				suffix = " (asks for confirmation)"
+			}
+			fmt.Printf("  Would %s%s\n", action.Description, suffix)
+		case action.Status == FixApplied:
+			ui.CheckMark(capitalize(action.Description))
+		case action.Status == FixSkipped:
+			ui.Info(fmt.Sprintf("Skipped: %s", action.Description))
+		default:
+			ui.Error(fmt.Sprintf("Failed to %s: %s", action.Description, action.Error))
+		}
+	}
+
+	if dryRun {
+		ui.NewLine()
+		ui.Info("💡 Run without --dry-run to apply")
+	}
+}
+
+// sortedCopy returns a sorted copy of names
+func sortedCopy(names []string) []string {
+	sorted := append([]string{}, names...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// capitalize upper-cases the first letter of an ASCII description
+func capitalize(s string) string {
+	if s == "" || s[0] < 'a' || s[0] > 'z' {
+		return s
+	}
+	return string(s[0]-'a'+'A') + s[1:]
+}
@@ -6,11 +6,26 @@ import (
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/git"
 	"github.com/braunmar/worktree/pkg/registry"
-	"os/exec"
 	"strconv"
 	"strings"
 )
 
+// CheckGitVersion reports the installed git version and the capabilities it lacks
+func CheckGitVersion() GitHealth {
+	v, err := git.DetectVersion()
+	if err != nil {
+		return GitHealth{Error: err.Error()}
+	}
+
+	health := GitHealth{Version: v.String(), Supported: v.AtLeast(git.MinVersion)}
+	for _, c := range git.Capabilities {
+		if !v.AtLeast(c.Since) {
+			health.Unavailable = append(health.Unavailable, fmt.Sprintf("%s (git %d.%d+)", c.Name, c.Since.Major, c.Since.Minor))
+		}
+	}
+	return health
+}
+
 // CheckGitStatus checks git status for a worktree using the given project path as the git directory.
 func CheckGitStatus(cfg *config.Config, wt *registry.Worktree, projectPath string, fetch bool) GitStatusReport {
 	report := GitStatusReport{
@@ -42,12 +57,12 @@ func CheckGitStatus(cfg *config.Config, wt *registry.Worktree, projectPath strin
 
 	// Fetch if requested
 	if fetch {
-		fetchCmd := exec.Command("git", "-C", projectPath, "fetch", "origin")
+		fetchCmd := git.Command(projectPath, "fetch", "origin")
 		fetchCmd.Run() // Ignore errors (might be offline)
 	}
 
 	// Check how far behind origin/main
-	behindCmd := exec.Command("git", "-C", projectPath, "rev-list", "--count", fmt.Sprintf("%s..origin/main", branch))
+	behindCmd := git.Command(projectPath, "rev-list", "--count", fmt.Sprintf("%s..origin/main", branch))
 	var behindOut bytes.Buffer
 	behindCmd.Stdout = &behindOut
 	if err := behindCmd.Run(); err == nil {
@@ -57,7 +72,7 @@ func CheckGitStatus(cfg *config.Config, wt *registry.Worktree, projectPath strin
 	}
 
 	// Check how far ahead of origin
-	aheadCmd := exec.Command("git", "-C", projectPath, "rev-list", "--count", fmt.Sprintf("origin/%s..%s", branch, branch))
+	aheadCmd := git.Command(projectPath, "rev-list", "--count", fmt.Sprintf("origin/%s..%s", branch, branch))
 	var aheadOut bytes.Buffer
 	aheadCmd.Stdout = &aheadOut
 	if err := aheadCmd.Run(); err == nil {
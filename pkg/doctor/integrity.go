@@ -0,0 +1,78 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// CheckIntegrity verifies the checksums of the registry and of every feature's
+// instance marker (only the given feature's marker when one is set). It reads
+// the files directly, so it also works when the registry no longer loads.
+func CheckIntegrity(cfg *config.Config, feature string) []IntegrityReport {
+	paths := []string{registry.FilePath(cfg.WorktreeDir)}
+	if feature != "" {
+		paths = append(paths, config.InstanceMarkerPath(cfg.WorktreeFeaturePath(feature)))
+	} else if entries, err := os.ReadDir(cfg.WorktreeDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name()[0] != '.' {
+				paths = append(paths, config.InstanceMarkerPath(filepath.Join(cfg.WorktreeDir, entry.Name())))
+			}
+		}
+	}
+	sort.Strings(paths[1:])
+
+	var reports []IntegrityReport
+	for _, path := range paths {
+		err := config.VerifyFile(path)
+		if err == nil {
+			continue
+		}
+		problem := err.Error()
+		if errors.Is(err, config.ErrChecksumMismatch) {
+			problem = "checksum mismatch (edited by hand or partially written)"
+		}
+		rel, relErr := filepath.Rel(cfg.ProjectRoot, path)
+		if relErr != nil {
+			rel = path
+		}
+		reports = append(reports, IntegrityReport{
+			Path:      rel,
+			Problem:   problem,
+			HasBackup: config.HasChecksumBackup(path),
+			Parses:    config.ChecksummedContentParses(path),
+		})
+	}
+	return reports
+}
+
+// PlanIntegrityFixes proposes accepting the content of each file that failed
+// its checksum when it still parses (a complete write whose checksum was not
+// updated), or else restoring it from its backup. Both replace what is on
+// disk, so they need confirmation.
+func PlanIntegrityFixes(reports []IntegrityReport) []FixAction {
+	var actions []FixAction
+	for _, report := range reports {
+		var description string
+		switch {
+		case report.Parses:
+			description = fmt.Sprintf("accept the current content of %s (still valid JSON)", report.Path)
+		case report.HasBackup:
+			description = fmt.Sprintf("restore %s from its last good backup", report.Path)
+		default:
+			description = fmt.Sprintf("accept the current content of %s (no backup)", report.Path)
+		}
+		actions = append(actions, FixAction{
+			Kind:         FixIntegrity,
+			Target:       report.Path,
+			Description:  description,
+			NeedsConfirm: true,
+		})
+	}
+	return actions
+}
@@ -0,0 +1,192 @@
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// portListener is the process bound to a TCP port. PID is 0 when the owner
+// cannot be determined (e.g. a socket of another user's process).
+type portListener struct {
+	PID     int
+	PGID    int
+	Process string
+}
+
+// runtimeProcesses are the processes that publish container ports on the host
+var runtimeProcesses = map[string]bool{
+	"docker-proxy":       true,
+	"com.docker.backend": true,
+	"com.docker.vpnkit":  true,
+	"vpnkit":             true,
+	"vpnkit-bridge":      true,
+	"rootlessport":       true,
+	"rootlesskit":        true,
+	"slirp4netns":        true,
+	"pasta":              true,
+	"gvproxy":            true,
+	"conmon":             true,
+	"podman":             true,
+}
+
+// listeningPorts returns the TCP ports in LISTEN state on the host with their
+// owning process, read from /proc on Linux and from lsof elsewhere
+func listeningPorts() (map[int]portListener, error) {
+	if _, err := os.Stat("/proc/net/tcp"); err == nil {
+		return procListeningPorts("/proc")
+	}
+	if _, err := exec.LookPath("lsof"); err == nil {
+		return lsofListeningPorts()
+	}
+	return nil, fmt.Errorf("neither /proc nor lsof is available")
+}
+
+// procListeningPorts reads listening sockets from <procRoot>/net/tcp{,6} and
+// resolves socket inodes to processes via <procRoot>/<pid>/fd
+func procListeningPorts(procRoot string) (map[int]portListener, error) {
+	inodes := make(map[string]int) // socket inode -> port
+	for _, name := range []string{"tcp", "tcp6"} {
+		data, err := os.ReadFile(filepath.Join(procRoot, "net", name))
+		if err != nil {
+			if name == "tcp" {
+				return nil, fmt.Errorf("failed to read /proc/net/tcp: %w", err)
+			}
+			continue
+		}
+		for inode, port := range parseProcNetTCP(data) {
+			inodes[inode] = port
+		}
+	}
+
+	listeners := make(map[int]portListener, len(inodes))
+	for _, port := range inodes {
+		listeners[port] = portListener{}
+	}
+
+	pidDirs, _ := filepath.Glob(filepath.Join(procRoot, "[0-9]*"))
+	for _, pidDir := range pidDirs {
+		pid, err := strconv.Atoi(filepath.Base(pidDir))
+		if err != nil {
+			continue
+		}
+		fds, err := os.ReadDir(filepath.Join(pidDir, "fd"))
+		if err != nil {
+			continue // Not our process
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(pidDir, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			port, ok := inodes[strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")]
+			if !ok || listeners[port].PID != 0 {
+				continue
+			}
+			listeners[port] = procListener(pidDir, pid)
+		}
+	}
+
+	return listeners, nil
+}
+
+// parseProcNetTCP returns socket inode -> local port for LISTEN entries of a
+// /proc/net/tcp or tcp6 table
+func parseProcNetTCP(data []byte) map[string]int {
+	result := make(map[string]int)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sl local_address rem_address st tx:rx tr:when retrnsmt uid timeout inode
+		if len(fields) < 10 || fields[3] != "0A" {
+			continue
+		}
+		colon := strings.LastIndex(fields[1], ":")
+		if colon < 0 {
+			continue
+		}
+		port, err := strconv.ParseInt(fields[1][colon+1:], 16, 32)
+		if err != nil || fields[9] == "0" {
+			continue
+		}
+		result[fields[9]] = int(port)
+	}
+	return result
+}
+
+// procListener reads the command name and process group of pid
+func procListener(pidDir string, pid int) portListener {
+	listener := portListener{PID: pid}
+	if comm, err := os.ReadFile(filepath.Join(pidDir, "comm")); err == nil {
+		listener.Process = strings.TrimSpace(string(comm))
+	}
+	if stat, err := os.ReadFile(filepath.Join(pidDir, "stat")); err == nil {
+		// pid (comm) state ppid pgrp ...; comm may contain spaces, so split after ')'
+		if end := bytes.LastIndexByte(stat, ')'); end >= 0 {
+			if fields := strings.Fields(string(stat[end+1:])); len(fields) >= 3 {
+				listener.PGID, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return listener
+}
+
+// lsofListeningPorts lists listening TCP sockets with lsof (macOS, BSD)
+func lsofListeningPorts() (map[int]portListener, error) {
+	out, err := exec.Command("lsof", "+c", "0", "-nP", "-iTCP", "-sTCP:LISTEN", "-F", "pcn").Output()
+	if err != nil && len(out) == 0 {
+		// lsof exits 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("lsof failed: %w", err)
+		}
+	}
+	listeners := parseLsof(out)
+
+	// lsof does not report process groups; fill them in from ps
+	if psOut, err := exec.Command("ps", "-A", "-o", "pid=,pgid=").Output(); err == nil {
+		pgids := make(map[int]int)
+		for _, line := range strings.Split(string(psOut), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 {
+				pid, _ := strconv.Atoi(fields[0])
+				pgids[pid], _ = strconv.Atoi(fields[1])
+			}
+		}
+		for port, listener := range listeners {
+			listener.PGID = pgids[listener.PID]
+			listeners[port] = listener
+		}
+	}
+
+	return listeners, nil
+}
+
+// parseLsof parses 'lsof -F pcn' output: p<pid>, c<command>, n<address:port> lines
+func parseLsof(out []byte) map[int]portListener {
+	listeners := make(map[int]portListener)
+	var current portListener
+	for _, line := range strings.Split(string(out), "\n") {
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			current = portListener{}
+			current.PID, _ = strconv.Atoi(line[1:])
+		case 'c':
+			current.Process = line[1:]
+		case 'n':
+			colon := strings.LastIndex(line, ":")
+			if port, err := strconv.Atoi(line[colon+1:]); colon >= 0 && err == nil {
+				if _, seen := listeners[port]; !seen {
+					listeners[port] = current
+				}
+			}
+		}
+	}
+	return listeners
+}
@@ -0,0 +1,78 @@
+package doctor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseProcNetTCP(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41234 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0CEA 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 41235 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:9C40 0100007F:1F90 01 00000000:00000000 00:00000000 00000000  1000        0 41236 1 0000000000000000 20 4 30 10 -1
+   3: 00000000:1F91 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 0 1 0000000000000000 100 0 0 10 0
+`
+	got := parseProcNetTCP([]byte(data))
+	want := map[string]int{"41234": 8080, "41235": 3306}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for inode, port := range want {
+		if got[inode] != port {
+			t.Errorf("inode %s = %d, want %d", inode, got[inode], port)
+		}
+	}
+}
+
+func TestParseLsof(t *testing.T) {
+	out := "p501\ncnode\nn*:3000\nn[::1]:3000\np88\nccom.docker.backend\nn127.0.0.1:8080\n"
+	got := parseLsof([]byte(out))
+
+	if l := got[3000]; l.PID != 501 || l.Process != "node" {
+		t.Errorf("port 3000 = %+v, want node (pid 501)", l)
+	}
+	if l := got[8080]; l.PID != 88 || l.Process != "com.docker.backend" {
+		t.Errorf("port 8080 = %+v, want com.docker.backend (pid 88)", l)
+	}
+}
+
+func TestClassifyBindings(t *testing.T) {
+	owners := []portOwner{
+		{Feature: "running", Service: "API", Port: 8081, Running: true},
+		{Feature: "running", Service: "WEB", Port: 3001, Running: true},
+		{Feature: "running", Service: "DB", Port: 5433, Running: true},
+		{Feature: "stopped", Service: "API", Port: 8082},
+		{Feature: "stopped", Service: "WEB", Port: 3002},
+		{Feature: "stopped", Service: "DB", Port: 5434},
+		{Feature: "native", Service: "WEB", Port: 3003, Running: true, Leaders: []int{700}},
+		{Feature: "native", Service: "API", Port: 8083, Running: true, Leaders: []int{700}},
+	}
+	listeners := map[int]portListener{
+		8081: {PID: 10, Process: "docker-proxy"}, // own containers
+		3001: {PID: 20, Process: "node"},         // unrelated process
+		8082: {PID: 11, Process: "docker-proxy"}, // someone else's containers
+		5434: {},                                 // unknown owner
+		3003: {PID: 701, PGID: 700, Process: "node"},
+	}
+
+	conflicts, unbound := classifyBindings(owners, listeners)
+
+	var got []string
+	for _, c := range conflicts {
+		got = append(got, c.Feature+"/"+c.Service)
+	}
+	if strings.Join(got, ",") != "running/WEB,stopped/API,stopped/DB" {
+		t.Errorf("conflicts = %v", got)
+	}
+	if conflicts[0].Reason != "held by node (pid 20)" {
+		t.Errorf("reason = %q", conflicts[0].Reason)
+	}
+
+	got = nil
+	for _, u := range unbound {
+		got = append(got, u.Feature+"/"+u.Service)
+	}
+	if strings.Join(got, ",") != "running/DB,native/API" {
+		t.Errorf("unbound = %v", got)
+	}
+}
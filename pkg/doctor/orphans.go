@@ -0,0 +1,109 @@
+package doctor
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// CheckServiceOrphans finds containers of the features' compose projects whose
+// service is no longer in the projects' compose files (compose "orphans")
+func CheckServiceOrphans(cfg *config.Config, workCfg *config.WorktreeConfig, worktrees []*registry.Worktree) []ServiceOrphanReport {
+	var reports []ServiceOrphanReport
+	for _, wt := range worktrees {
+		featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+		checked := make(map[string]bool)
+		for _, projectName := range wt.Projects {
+			project, ok := workCfg.Projects[projectName]
+			if !ok || project.GetExecutor() != "docker" {
+				continue
+			}
+			composeProject := ComposeProjectName(workCfg, wt, projectName)
+			if checked[composeProject] {
+				continue
+			}
+			checked[composeProject] = true
+
+			projects := ComposeSiblings(workCfg, wt, composeProject)
+			orphans, err := ServiceOrphans(workCfg, wt, featureDir, projects, composeProject)
+			if err != nil || len(orphans) == 0 {
+				continue
+			}
+
+			report := ServiceOrphanReport{Feature: wt.Normalized, Project: strings.Join(projects, ", "), ComposeProject: composeProject}
+			for _, c := range orphans {
+				report.Containers = append(report.Containers, c.Name)
+				report.Services = append(report.Services, c.Service)
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// ServiceOrphans returns the containers of a compose project whose service is
+// in none of the current compose configurations of the projects sharing it
+func ServiceOrphans(workCfg *config.WorktreeConfig, wt *registry.Worktree, featureDir string, projects []string, composeProject string) ([]docker.ComposeContainer, error) {
+	containers, err := docker.ComposeContainers(composeProject)
+	if err != nil || len(containers) == 0 {
+		return nil, err
+	}
+
+	// Vars read from the host environment are not stored; resolve them for compose
+	vars := maps.Clone(wt.ComputedVars)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	workCfg.ResolveHostEnvVars(workCfg.InstanceFromPorts(wt.Ports), vars)
+
+	var services []string
+	for _, projectName := range projects {
+		projectDir := filepath.Join(featureDir, workCfg.Projects[projectName].Dir)
+		env := os.Environ()
+		for key, value := range vars {
+			env = append(env, fmt.Sprintf("%s=%s", key, value))
+		}
+		env = append(env, "COMPOSE_PROJECT_NAME="+composeProject)
+		if files, err := workCfg.ComposeFiles(featureDir, projectName); err == nil && len(files) > 0 {
+			env = append(env, "COMPOSE_FILE="+strings.Join(files, string(os.PathListSeparator)))
+		}
+
+		projectServices, err := docker.ComposeServices(projectDir, env)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, projectServices...)
+	}
+	return docker.Orphans(containers, services), nil
+}
+
+// ComposeSiblings returns the feature's docker projects that share the
+// compose project, in feature order
+func ComposeSiblings(workCfg *config.WorktreeConfig, wt *registry.Worktree, composeProject string) []string {
+	var projects []string
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok || project.GetExecutor() != "docker" {
+			continue
+		}
+		if ComposeProjectName(workCfg, wt, projectName) == composeProject {
+			projects = append(projects, projectName)
+		}
+	}
+	return projects
+}
+
+// ComposeProjectName returns the compose project of a feature's project,
+// falling back to the default <project_name>-<feature>-<project>
+func ComposeProjectName(workCfg *config.WorktreeConfig, wt *registry.Worktree, projectName string) string {
+	if composeProject := wt.GetComposeProject(projectName); composeProject != "" {
+		return composeProject
+	}
+	return fmt.Sprintf("%s-%s-%s", workCfg.ProjectName, wt.Normalized, projectName)
+}
@@ -3,14 +3,19 @@ package doctor
 import (
 	"fmt"
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/process"
 	"github.com/braunmar/worktree/pkg/registry"
 	"net"
+	"path/filepath"
+	"sort"
 )
 
 // CheckPorts checks port allocations for conflicts and range violations
 func CheckPorts(reg *registry.Registry, workCfg *config.WorktreeConfig) PortReport {
 	report := PortReport{
 		PortRanges: make(map[string]PortRangeInfo),
+		PortPools:  make(map[string]PortRangeInfo),
 	}
 
 	// Build map of allocated ports
@@ -34,25 +39,20 @@ func CheckPorts(reg *registry.Registry, workCfg *config.WorktreeConfig) PortRepo
 			continue // Service not in config (might be custom)
 		}
 
-		// Check if port is in range
-		if portCfg.Range != nil {
-			min, max := (*portCfg.Range)[0], (*portCfg.Range)[1]
-			if port < min || port > max {
-				report.OutOfRange = append(report.OutOfRange, PortOutOfRange{
-					Service: alloc.Service,
-					Port:    port,
-					Feature: alloc.Feature,
-					Range:   *portCfg.Range,
-				})
+		// Check if port is in range (its pool's range for pool members)
+		if portCfg.Range != nil || portCfg.Pool != "" {
+			if portRange := workCfg.GetPortRangeFor(alloc.Service); portRange != nil {
+				min, max := portRange[0], portRange[1]
+				if port < min || port > max {
+					report.OutOfRange = append(report.OutOfRange, PortOutOfRange{
+						Service: alloc.Service,
+						Port:    port,
+						Feature: alloc.Feature,
+						Range:   *portRange,
+					})
+				}
 			}
 		}
-
-		// Check if port is actually available (might be in use by another process)
-		if !isPortAvailable(port) {
-			// Port is in use - check if it might be by Docker
-			// We'll skip this check for now as it could be the worktree's own containers
-			// report.Conflicts = append(report.Conflicts, PortConflict{...})
-		}
 	}
 
 	// Calculate port range statistics
@@ -84,9 +84,137 @@ func CheckPorts(reg *registry.Registry, workCfg *config.WorktreeConfig) PortRepo
 		report.TotalAvailable += (rangeSize - allocated)
 	}
 
+	// Pools are shared by several services, so count them once per pool
+	for poolName, pool := range workCfg.PortPools {
+		min, max := pool.Range[0], pool.Range[1]
+		rangeSize := max - min + 1
+
+		allocated := 0
+		for port := range reg.PoolAllocations(poolName) {
+			if port >= min && port <= max {
+				allocated++
+			}
+		}
+
+		report.PortPools[poolName] = PortRangeInfo{
+			Min:       min,
+			Max:       max,
+			Allocated: allocated,
+			Available: rangeSize - allocated,
+		}
+
+		report.TotalAvailable += (rangeSize - allocated)
+	}
+
 	return report
 }
 
+// CheckPortBindings compares the registered ports of worktrees with what is
+// bound on the host. Ports held by a process that does not belong to the feature
+// (its container runtime or its process-executor process group) are reported as
+// conflicts; ports that are free although the feature is running are reported as unbound.
+func CheckPortBindings(cfg *config.Config, worktrees []*registry.Worktree, workCfg *config.WorktreeConfig, report *PortReport) {
+	var owners []portOwner
+	for _, wt := range worktrees {
+		featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+		running := docker.IsFeatureRunning(workCfg.ProjectName, wt.Normalized)
+
+		var leaders []int
+		for _, projectName := range wt.Projects {
+			if project, ok := workCfg.Projects[projectName]; ok && project.GetExecutor() == "process" {
+				pidFile := filepath.Join(featureDir, projectName+".pid")
+				if pid, err := process.ReadPID(pidFile); err == nil && process.IsRunning(pidFile) {
+					leaders = append(leaders, pid)
+					running = true
+				}
+			}
+		}
+
+		for service, port := range wt.Ports {
+			owners = append(owners, portOwner{
+				Feature: wt.Normalized,
+				Service: service,
+				Port:    port,
+				Running: running,
+				Leaders: leaders,
+			})
+		}
+	}
+	sort.Slice(owners, func(i, j int) bool { return owners[i].Port < owners[j].Port })
+
+	listeners, err := listeningPorts()
+	if err != nil {
+		// Fall back to a bind probe: tells whether a port is taken, not by whom
+		listeners = make(map[int]portListener)
+		for _, owner := range owners {
+			if !isPortAvailable(owner.Port) {
+				listeners[owner.Port] = portListener{}
+			}
+		}
+	}
+
+	report.Conflicts, report.Unbound = classifyBindings(owners, listeners)
+}
+
+// portOwner is a registered port together with the state of its feature
+type portOwner struct {
+	Feature string
+	Service string
+	Port    int
+	Running bool
+	Leaders []int // Process group leaders started by the process executor
+}
+
+// classifyBindings decides, per registered port, whether its current binding
+// (or lack of one) is consistent with the feature's state
+func classifyBindings(owners []portOwner, listeners map[int]portListener) (conflicts, unbound []PortConflict) {
+	for _, owner := range owners {
+		entry := PortConflict{Service: owner.Service, Port: owner.Port, Feature: owner.Feature}
+
+		listener, bound := listeners[owner.Port]
+		if !bound {
+			if owner.Running {
+				entry.Reason = "not bound although the feature is running"
+				unbound = append(unbound, entry)
+			}
+			continue
+		}
+
+		entry.PID = listener.PID
+		entry.Process = listener.Process
+
+		switch {
+		case ownedBy(listener, owner.Leaders):
+			continue
+		case listener.PID == 0:
+			// Owner unknown (other user's process or bind probe): only suspicious when we are not running
+			if owner.Running {
+				continue
+			}
+			entry.Reason = "in use by another process while the feature is stopped"
+		case runtimeProcesses[listener.Process]:
+			if owner.Running {
+				continue
+			}
+			entry.Reason = fmt.Sprintf("published by %s while the feature is stopped (another container?)", listener.Process)
+		default:
+			entry.Reason = fmt.Sprintf("held by %s (pid %d)", listener.Process, listener.PID)
+		}
+		conflicts = append(conflicts, entry)
+	}
+	return conflicts, unbound
+}
+
+// ownedBy reports whether listener runs in one of the given process groups
+func ownedBy(listener portListener, leaders []int) bool {
+	for _, leader := range leaders {
+		if listener.PID == leader || listener.PGID == leader {
+			return true
+		}
+	}
+	return false
+}
+
 // PortAllocation tracks which feature/service uses a port
 type PortAllocation struct {
 	Feature string
@@ -0,0 +1,99 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// PreflightProblem is a pre-flight check that failed, with the fix for it
+type PreflightProblem struct {
+	Problem string
+	Hint    string
+}
+
+// PreflightOptions selects the checks Preflight runs
+type PreflightOptions struct {
+	Dir           string // Directory whose filesystem needs free space
+	Docker        bool   // Whether services start through the container runtime
+	MinFreeDiskMB int    // Free space required in Dir; 0 skips the check
+}
+
+// freeDiskMB is diskFreeMB, swappable in tests
+var freeDiskMB = diskFreeMB
+
+// Preflight runs the fast checks new-feature and start need to pass before
+// changing anything: the container runtime's daemon and compose (the same
+// probes as doctor) and the free disk space. Returns nil when all pass.
+func Preflight(opts PreflightOptions) []PreflightProblem {
+	var problems []PreflightProblem
+	if opts.Docker {
+		if problem := dockerProblem(CheckDocker()); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	if opts.MinFreeDiskMB > 0 {
+		if problem := diskProblem(opts.Dir, opts.MinFreeDiskMB); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	return problems
+}
+
+// dockerProblem reports the first failing runtime probe of health
+func dockerProblem(health DockerHealth) *PreflightProblem {
+	name := runtimeDisplayName(health.Runtime)
+	switch {
+	case !health.Installed:
+		return &PreflightProblem{
+			Problem: fmt.Sprintf("%s is not installed or not in PATH", name),
+			Hint:    "Install it, or set container_runtime in .worktree.yml to the runtime you use",
+		}
+	case !health.Running:
+		return &PreflightProblem{
+			Problem: fmt.Sprintf("%s daemon is not running", name),
+			Hint:    daemonHint(health.Runtime),
+		}
+	case !health.ComposeAvailable:
+		hint := "Install the Docker Compose plugin ('docker compose version' must work)"
+		if health.Runtime == "podman" {
+			hint = "Install podman-compose, or podman 4+ for 'podman compose'"
+		}
+		return &PreflightProblem{
+			Problem: fmt.Sprintf("%s Compose is not available", name),
+			Hint:    hint,
+		}
+	}
+	return nil
+}
+
+// diskProblem reports when the filesystem holding dir has less than minMB free.
+// Platforms without a free-space probe pass.
+func diskProblem(dir string, minMB int) *PreflightProblem {
+	free, err := freeDiskMB(dir)
+	if err != nil {
+		return nil
+	}
+	if free >= uint64(minMB) {
+		return nil
+	}
+	return &PreflightProblem{
+		Problem: fmt.Sprintf("Only %d MB of disk space free in %s (need %d MB)", free, filepath.Clean(dir), minMB),
+		Hint:    "Free up space ('docker system prune' removes unused images and containers) or lower min_free_disk_mb in .worktree.yml",
+	}
+}
+
+// runtimeDisplayName returns "Podman" for podman and "Docker" otherwise
+func runtimeDisplayName(runtime string) string {
+	if runtime == "podman" {
+		return "Podman"
+	}
+	return "Docker"
+}
+
+// daemonHint tells how to start the runtime's daemon
+func daemonHint(runtime string) string {
+	if runtime == "podman" {
+		return "Start the podman socket (systemctl --user start podman.socket) and try again"
+	}
+	return "Start Docker Desktop and try again"
+}
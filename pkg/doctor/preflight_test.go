@@ -0,0 +1,60 @@
+package doctor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDockerProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		health DockerHealth
+		want   string
+	}{
+		{"healthy", DockerHealth{Runtime: "docker", Installed: true, Running: true, ComposeAvailable: true}, ""},
+		{"not installed", DockerHealth{Runtime: "docker"}, "Docker is not installed"},
+		{"daemon down", DockerHealth{Runtime: "podman", Installed: true}, "Podman daemon is not running"},
+		{"no compose", DockerHealth{Runtime: "docker", Installed: true, Running: true}, "Docker Compose is not available"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := dockerProblem(tt.health)
+			if tt.want == "" {
+				if problem != nil {
+					t.Fatalf("dockerProblem() = %+v, want nil", problem)
+				}
+				return
+			}
+			if problem == nil || !strings.Contains(problem.Problem, tt.want) {
+				t.Fatalf("dockerProblem() = %+v, want %q", problem, tt.want)
+			}
+			if problem.Hint == "" {
+				t.Error("problem has no hint")
+			}
+		})
+	}
+}
+
+func TestPreflightDiskSpace(t *testing.T) {
+	orig := freeDiskMB
+	t.Cleanup(func() { freeDiskMB = orig })
+
+	freeDiskMB = func(string) (uint64, error) { return 500, nil }
+	problems := Preflight(PreflightOptions{Dir: "/work", MinFreeDiskMB: 1024})
+	if len(problems) != 1 || !strings.Contains(problems[0].Problem, "Only 500 MB") {
+		t.Fatalf("Preflight() = %+v, want a disk space problem", problems)
+	}
+
+	if problems := Preflight(PreflightOptions{Dir: "/work", MinFreeDiskMB: 500}); problems != nil {
+		t.Errorf("Preflight() at the threshold = %+v, want nil", problems)
+	}
+	if problems := Preflight(PreflightOptions{Dir: "/work"}); problems != nil {
+		t.Errorf("Preflight() with the check disabled = %+v, want nil", problems)
+	}
+
+	freeDiskMB = func(string) (uint64, error) { return 0, errors.New("unsupported") }
+	if problems := Preflight(PreflightOptions{Dir: "/work", MinFreeDiskMB: 1024}); problems != nil {
+		t.Errorf("Preflight() without a free-space probe = %+v, want nil", problems)
+	}
+}
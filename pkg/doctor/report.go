@@ -3,18 +3,26 @@ package doctor
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/braunmar/worktree/pkg/ui"
 	"strings"
+
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/ui"
 )
 
 // Print outputs the report in human-readable format
 func (r *Report) Print() {
 	ui.PrintHeader("🏥 Worktree Doctor - Health Check Report")
+	if r.Feature != "" {
+		ui.Info(fmt.Sprintf("Scoped to feature: %s", r.Feature))
+	}
 	ui.NewLine()
 
 	printSeparator()
 	r.printDockerHealth()
 
+	printSeparator()
+	r.printGitHealth()
+
 	printSeparator()
 	r.printConsistency()
 
@@ -51,35 +59,56 @@ func (r *Report) ExitCode() int {
 }
 
 func printSeparator() {
-	fmt.Println(strings.Repeat("━", 70))
+	ui.Separator(70)
 	ui.NewLine()
 }
 
 func (r *Report) printDockerHealth() {
 	ui.Section("🐳 DOCKER HEALTH")
 
+	name := runtimeDisplayName(r.Docker.Runtime)
+
 	if !r.Docker.Installed {
-		ui.Error("Docker not installed or not in PATH")
+		ui.Error(fmt.Sprintf("%s not installed or not in PATH", name))
 		if r.Docker.Error != "" {
 			fmt.Printf("  %s\n", r.Docker.Error)
 		}
 		return
 	}
 
-	ui.Success(fmt.Sprintf("Docker installed (%s)", r.Docker.Version))
+	ui.Success(fmt.Sprintf("%s installed (%s)", name, r.Docker.Version))
 
 	if !r.Docker.Running {
-		ui.Error("Docker daemon not running")
-		ui.Info("💡 Start Docker Desktop and try again")
+		ui.Error(fmt.Sprintf("%s daemon not running", name))
+		ui.Info("💡 " + daemonHint(r.Docker.Runtime))
 		return
 	}
 
-	ui.Success("Docker daemon running")
+	ui.Success(fmt.Sprintf("%s daemon running", name))
 
 	if r.Docker.ComposeAvailable {
-		ui.Success("Docker Compose available")
+		ui.Success(fmt.Sprintf("%s Compose available", name))
 	} else {
-		ui.Warning("Docker Compose not available")
+		ui.Warning(fmt.Sprintf("%s Compose not available", name))
+	}
+}
+
+func (r *Report) printGitHealth() {
+	ui.Section("🔧 GIT")
+
+	if r.Git.Error != "" {
+		ui.Error(r.Git.Error)
+		return
+	}
+
+	if !r.Git.Supported {
+		ui.Error(fmt.Sprintf("git %s is too old (worktree needs %d.%d or newer)", r.Git.Version, git.MinVersion.Major, git.MinVersion.Minor))
+		return
+	}
+
+	ui.Success(fmt.Sprintf("git %s", r.Git.Version))
+	for _, name := range r.Git.Unavailable {
+		ui.Warning(fmt.Sprintf("Not available: %s", name))
 	}
 }
 
@@ -88,6 +117,21 @@ func (r *Report) printConsistency() {
 
 	allGood := true
 
+	// State files failing their checksum
+	if len(r.Integrity) > 0 {
+		allGood = false
+		ui.Error(fmt.Sprintf("%d state files failed their integrity check:", len(r.Integrity)))
+		for _, report := range r.Integrity {
+			backup := "no backup"
+			if report.HasBackup {
+				backup = "backup available"
+			}
+			fmt.Printf("    - %s: %s (%s)\n", report.Path, report.Problem, backup)
+		}
+		ui.Info("💡 Fix: Run 'worktree doctor --fix' to keep content that still parses and restore the rest from backup (asks first)")
+		ui.NewLine()
+	}
+
 	// Orphaned registry entries
 	if len(r.Consistency.OrphanedRegistryEntries) > 0 {
 		allGood = false
@@ -108,7 +152,7 @@ func (r *Report) printConsistency() {
 		for _, dir := range r.Consistency.OrphanedDirectories {
 			fmt.Printf("    - %s\n", dir)
 		}
-		ui.Info("💡 Add to registry with 'worktree new-feature' or remove manually")
+		ui.Info("💡 Fix: Run 'worktree doctor --fix' to delete them (asks first), or remove manually")
 		ui.NewLine()
 	}
 
@@ -120,12 +164,35 @@ func (r *Report) printConsistency() {
 		for _, container := range r.Consistency.OrphanedContainers {
 			fmt.Printf("    - %s\n", container)
 		}
-		ui.Info("💡 Fix: Run 'docker container prune' to clean up")
+		ui.Info("💡 Fix: Run 'worktree doctor --fix' to stop and remove them")
+		ui.NewLine()
+	}
+
+	// Containers of services no longer in the compose file
+	if len(r.Consistency.ServiceOrphans) > 0 {
+		allGood = false
+		ui.Warning(fmt.Sprintf("%d compose projects with orphaned service containers (service no longer in the compose file):",
+			len(r.Consistency.ServiceOrphans)))
+		for _, orphan := range r.Consistency.ServiceOrphans {
+			fmt.Printf("    - %s (%s): %s\n", orphan.ComposeProject, orphan.Project, strings.Join(orphan.Containers, ", "))
+		}
+		ui.Info("💡 Fix: Run 'worktree doctor --fix' or 'worktree stop <feature> --remove-orphans' to remove them")
+		ui.NewLine()
+	}
+
+	// Broken symlinks
+	if len(r.Symlinks) > 0 {
+		allGood = false
+		ui.Warning(fmt.Sprintf("%d symlinks not in place:", len(r.Symlinks)))
+		for _, link := range r.Symlinks {
+			fmt.Printf("    - %s/%s: %s\n", link.Feature, link.Target, link.Problem)
+		}
+		ui.Info("💡 Fix: Run 'worktree regen <feature>' to recreate them")
 		ui.NewLine()
 	}
 
 	if allGood {
-		ui.Success("All registry entries, directories, and containers are consistent")
+		ui.Success("All state files, registry entries, directories, containers, and symlinks are consistent")
 	}
 }
 
@@ -171,6 +238,15 @@ func (r *Report) printGitStatus() {
 			ui.Info("    🚀 YOLO mode enabled")
 		}
 	}
+
+	for _, sm := range r.Submodules {
+		ui.NewLine()
+		ui.Warning(fmt.Sprintf("  %s/%s: %d uninitialized submodules (%s)", sm.Feature, sm.Project, len(sm.Uninitialized), strings.Join(sm.Uninitialized, ", ")))
+		ui.Info(fmt.Sprintf("    💡 Run: git -C %s submodule update --init --recursive", sm.Path))
+		if !sm.Configured {
+			ui.Info(fmt.Sprintf("    💡 Set 'submodules: true' on project %s to initialize them in new worktrees", sm.Project))
+		}
+	}
 }
 
 func (r *Report) printStaleness() {
@@ -224,10 +300,21 @@ func (r *Report) printPorts() {
 
 	// Port conflicts
 	if len(r.Ports.Conflicts) > 0 {
-		ui.Warning(fmt.Sprintf("%d port conflicts detected:", len(r.Ports.Conflicts)))
+		ui.Warning(fmt.Sprintf("%d registered ports used by other processes:", len(r.Ports.Conflicts)))
 		for _, c := range r.Ports.Conflicts {
-			fmt.Printf("    %s: port %d (%s)\n", c.Service, c.Port, c.Feature)
+			fmt.Printf("    %s: port %d (%s) - %s\n", c.Service, c.Port, c.Feature, c.Reason)
+		}
+		ui.Info("💡 Stop the other process, or remove and recreate the feature to get new ports")
+		ui.NewLine()
+	}
+
+	// Registered but unbound ports of running features
+	if len(r.Ports.Unbound) > 0 {
+		ui.Warning(fmt.Sprintf("%d registered ports not listening although the feature is running:", len(r.Ports.Unbound)))
+		for _, u := range r.Ports.Unbound {
+			fmt.Printf("    %s: port %d (%s)\n", u.Service, u.Port, u.Feature)
 		}
+		ui.Info("💡 Check the service logs, or whether the service is configured with this port")
 		ui.NewLine()
 	}
 
@@ -241,8 +328,8 @@ func (r *Report) printPorts() {
 		ui.NewLine()
 	}
 
-	if len(r.Ports.Conflicts) == 0 && len(r.Ports.OutOfRange) == 0 {
-		ui.Success("All allocated ports within configured ranges")
+	if len(r.Ports.Conflicts) == 0 && len(r.Ports.Unbound) == 0 && len(r.Ports.OutOfRange) == 0 {
+		ui.Success("All allocated ports within configured ranges and free of other processes")
 	}
 
 	// Show available ports
@@ -254,6 +341,15 @@ func (r *Report) printPorts() {
 				service+":", info.Min, info.Max, info.Allocated, info.Available)
 		}
 	}
+
+	if len(r.Ports.PortPools) > 0 {
+		ui.NewLine()
+		ui.Info("Port pools:")
+		for pool, info := range r.Ports.PortPools {
+			fmt.Printf("    %-18s %d-%d (%d allocated, %d available)\n",
+				pool+":", info.Min, info.Max, info.Allocated, info.Available)
+		}
+	}
 }
 
 func (r *Report) printSummary() {
@@ -0,0 +1,408 @@
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Shells the completion doctor knows how to check
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Shell check statuses
+const (
+	ShellOK      = "ok"
+	ShellWarning = "warning"
+	ShellError   = "error"
+	ShellInfo    = "info" // Optional setup that is missing
+)
+
+// ShellOptions describes the environment the shell integration is checked in
+type ShellOptions struct {
+	Shell      string                       // bash, zsh or fish; empty detects it from $SHELL
+	Program    string                       // Command the completion scripts are registered for
+	Executable string                       // Path of the running binary
+	Home       string                       // Home directory holding the shell's rc files
+	Root       string                       // Prefix of system directories, "" for the real filesystem
+	Getenv     func(string) string          // Environment lookup ($SHELL, $ZDOTDIR, $XDG_*, $FPATH)
+	LookPath   func(string) (string, error) // Resolves Program on PATH
+	Complete   func(path string) error      // Sends a completion request to the binary on PATH (nil skips it)
+	Scripts    map[string][][]byte          // Completion scripts the running binary generates, by shell (with and without descriptions)
+}
+
+// ShellCheck is one finding of CheckShell
+type ShellCheck struct {
+	Name   string
+	Status string
+	Detail string
+	Fix    []string `json:",omitempty"` // Commands to run or lines to add, in order
+}
+
+// ShellReport is the result of CheckShell
+type ShellReport struct {
+	Shell  string
+	Checks []ShellCheck
+}
+
+// CheckShell verifies that the binary, its completion script and the prompt
+// helper are installed so the user's shell picks them up
+func CheckShell(opts ShellOptions) *ShellReport {
+	r := &ShellReport{Shell: opts.Shell}
+	if r.Shell == "" {
+		r.Shell = filepath.Base(opts.Getenv("SHELL"))
+	}
+
+	supported := false
+	for _, shell := range Shells {
+		supported = supported || r.Shell == shell
+	}
+	if !supported {
+		detail := fmt.Sprintf("'%s' is not supported (expected %s)", r.Shell, strings.Join(Shells, ", "))
+		if r.Shell == "." || r.Shell == "" {
+			detail = "could not detect the shell, $SHELL is not set"
+		}
+		r.add(ShellCheck{Name: "Shell", Status: ShellError, Detail: detail,
+			Fix: []string{opts.Program + " completion doctor --shell bash|zsh|fish"}})
+		r.checkBinary(opts)
+		return r
+	}
+	r.add(ShellCheck{Name: "Shell", Status: ShellOK, Detail: r.Shell})
+
+	path := r.checkBinary(opts)
+
+	if path != "" && opts.Complete != nil {
+		if err := opts.Complete(path); err != nil {
+			r.add(ShellCheck{Name: "Completion requests", Status: ShellError,
+				Detail: fmt.Sprintf("%s does not answer them: %v", path, err),
+				Fix:    []string{"go install github.com/braunmar/worktree@latest"}})
+		} else {
+			r.add(ShellCheck{Name: "Completion requests", Status: ShellOK, Detail: path + " answers them"})
+		}
+	}
+
+	r.checkCompletion(opts)
+	if r.Shell == "bash" {
+		r.checkBashCompletion(opts)
+	}
+	if r.Shell == "zsh" {
+		r.checkCompinit(opts)
+	}
+	r.checkPrompt(opts)
+	return r
+}
+
+// checkBinary checks that Program on PATH is the running binary and returns its path
+func (r *ShellReport) checkBinary(opts ShellOptions) string {
+	dir := filepath.Dir(opts.Executable)
+	path, err := opts.LookPath(opts.Program)
+	if err != nil {
+		fix := fmt.Sprintf(`echo 'export PATH="%s:$PATH"' >> %s`, dir, r.rcFile(opts))
+		if r.Shell == "fish" {
+			fix = "fish_add_path " + dir
+		}
+		r.add(ShellCheck{Name: "Binary on PATH", Status: ShellError,
+			Detail: fmt.Sprintf("'%s' is not on PATH, completion scripts call it by name", opts.Program),
+			Fix:    []string{fix}})
+		return ""
+	}
+
+	if !samePath(path, opts.Executable) {
+		r.add(ShellCheck{Name: "Binary on PATH", Status: ShellWarning,
+			Detail: fmt.Sprintf("%s is found first, not this binary (%s); completions come from it", path, opts.Executable),
+			Fix:    []string{"rm " + path + "   # or put " + dir + " before " + filepath.Dir(path) + " in PATH"}})
+		return path
+	}
+	r.add(ShellCheck{Name: "Binary on PATH", Status: ShellOK, Detail: path})
+	return path
+}
+
+// checkCompletion looks for the completion script: sourced from an rc file
+// or installed where the shell loads completions from
+func (r *ShellReport) checkCompletion(opts ShellOptions) {
+	command := opts.Program + " completion " + r.Shell
+	if rc := r.rcContaining(opts, command); rc != "" {
+		r.add(ShellCheck{Name: "Completion", Status: ShellOK, Detail: "generated on shell start by " + tildePath(opts.Home, rc)})
+		return
+	}
+
+	installed := r.completionFiles(opts)
+	for _, file := range installed {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		if !r.isCurrentScript(opts, data) {
+			r.add(ShellCheck{Name: "Completion", Status: ShellWarning,
+				Detail: tildePath(opts.Home, file) + " is out of date (generated by another version)",
+				Fix:    []string{command + " > " + tildePath(opts.Home, file)}})
+			return
+		}
+		r.add(ShellCheck{Name: "Completion", Status: ShellOK, Detail: "installed in " + tildePath(opts.Home, file)})
+		return
+	}
+
+	var fix []string
+	switch r.Shell {
+	case "bash":
+		dir := tildePath(opts.Home, filepath.Join(xdgDir(opts, "XDG_DATA_HOME", ".local/share"), "bash-completion", "completions"))
+		fix = []string{"mkdir -p " + dir, command + " > " + filepath.Join(dir, opts.Program)}
+	case "zsh":
+		fix = []string{fmt.Sprintf("echo 'source <(%s)' >> %s", command, r.rcFile(opts))}
+	case "fish":
+		dir := tildePath(opts.Home, filepath.Join(xdgDir(opts, "XDG_CONFIG_HOME", ".config"), "fish", "completions"))
+		fix = []string{"mkdir -p " + dir, command + " > " + filepath.Join(dir, opts.Program+".fish")}
+	}
+	fix = append(fix, "# then open a new shell")
+	r.add(ShellCheck{Name: "Completion", Status: ShellWarning, Detail: "not installed", Fix: fix})
+}
+
+// checkBashCompletion checks for the bash-completion package the bash script relies on
+func (r *ShellReport) checkBashCompletion(opts ShellOptions) {
+	for _, path := range []string{
+		"/usr/share/bash-completion/bash_completion",
+		"/etc/bash_completion",
+		"/usr/local/share/bash-completion/bash_completion",
+		"/usr/local/etc/profile.d/bash_completion.sh",
+		"/opt/homebrew/share/bash-completion/bash_completion",
+		"/opt/homebrew/etc/profile.d/bash_completion.sh",
+	} {
+		if fileExists(filepath.Join(opts.Root, path)) {
+			r.add(ShellCheck{Name: "bash-completion", Status: ShellOK, Detail: path})
+			return
+		}
+	}
+	r.add(ShellCheck{Name: "bash-completion", Status: ShellWarning,
+		Detail: "package not found, bash completion scripts need it",
+		Fix:    []string{"sudo apt install bash-completion   # or: brew install bash-completion@2"}})
+}
+
+// checkCompinit checks that .zshrc initializes the completion system
+func (r *ShellReport) checkCompinit(opts ShellOptions) {
+	for _, marker := range []string{"compinit", "oh-my-zsh.sh", "zinit", "zimfw", "prezto"} {
+		if rc := r.rcContaining(opts, marker); rc != "" {
+			r.add(ShellCheck{Name: "compinit", Status: ShellOK, Detail: "run by " + tildePath(opts.Home, rc)})
+			return
+		}
+	}
+	r.add(ShellCheck{Name: "compinit", Status: ShellWarning,
+		Detail: "the zsh completion system is not initialized",
+		Fix:    []string{"# add to " + r.rcFile(opts) + ", before the completion line:", "autoload -U compinit; compinit"}})
+}
+
+// checkPrompt checks whether the prompt shows the worktree segment (optional)
+func (r *ShellReport) checkPrompt(opts ShellOptions) {
+	command := opts.Program + " prompt"
+	if rc := r.rcContaining(opts, command); rc != "" {
+		r.add(ShellCheck{Name: "Prompt", Status: ShellOK, Detail: "segment set in " + tildePath(opts.Home, rc)})
+		return
+	}
+	starship := filepath.Join(xdgDir(opts, "XDG_CONFIG_HOME", ".config"), "starship.toml")
+	if fileContains(starship, command) {
+		r.add(ShellCheck{Name: "Prompt", Status: ShellOK, Detail: "segment set in " + tildePath(opts.Home, starship)})
+		return
+	}
+
+	fix := []string{"# add to " + r.rcFile(opts) + ":"}
+	switch r.Shell {
+	case "bash":
+		fix = append(fix, fmt.Sprintf(`PS1='$(%s --no-color) '"$PS1"`, command))
+	case "zsh":
+		fix = append(fix, "setopt PROMPT_SUBST", fmt.Sprintf(`RPROMPT='$(%s --no-color)'`, command))
+	case "fish":
+		fix = append(fix, fmt.Sprintf("function fish_right_prompt; %s; end", command))
+	}
+	r.add(ShellCheck{Name: "Prompt", Status: ShellInfo, Detail: "no worktree segment (optional)", Fix: fix})
+}
+
+// isCurrentScript reports whether data is a completion script the running binary generates
+func (r *ShellReport) isCurrentScript(opts ShellOptions, data []byte) bool {
+	for _, script := range opts.Scripts[r.Shell] {
+		if bytes.Equal(data, script) {
+			return true
+		}
+	}
+	return false
+}
+
+// completionFiles returns the installed completion scripts, in the order the
+// shell prefers them
+func (r *ShellReport) completionFiles(opts ShellOptions) []string {
+	var candidates []string
+	switch r.Shell {
+	case "bash":
+		candidates = append(candidates,
+			filepath.Join(xdgDir(opts, "XDG_DATA_HOME", ".local/share"), "bash-completion", "completions", opts.Program),
+			filepath.Join(opts.Root, "/usr/local/share/bash-completion/completions", opts.Program),
+			filepath.Join(opts.Root, "/usr/share/bash-completion/completions", opts.Program),
+			filepath.Join(opts.Root, "/etc/bash_completion.d", opts.Program),
+			filepath.Join(opts.Root, "/usr/local/etc/bash_completion.d", opts.Program),
+			filepath.Join(opts.Root, "/opt/homebrew/etc/bash_completion.d", opts.Program))
+	case "zsh":
+		dirs := filepath.SplitList(opts.Getenv("FPATH"))
+		dirs = append(dirs,
+			filepath.Join(opts.Home, ".zfunc"),
+			filepath.Join(opts.Home, ".oh-my-zsh", "completions"),
+			filepath.Join(opts.Root, "/usr/local/share/zsh/site-functions"),
+			filepath.Join(opts.Root, "/usr/share/zsh/site-functions"),
+			filepath.Join(opts.Root, "/usr/share/zsh/vendor-completions"),
+			filepath.Join(opts.Root, "/opt/homebrew/share/zsh/site-functions"))
+		for _, dir := range dirs {
+			candidates = append(candidates, filepath.Join(dir, "_"+opts.Program))
+		}
+	case "fish":
+		name := opts.Program + ".fish"
+		candidates = append(candidates,
+			filepath.Join(xdgDir(opts, "XDG_CONFIG_HOME", ".config"), "fish", "completions", name),
+			filepath.Join(xdgDir(opts, "XDG_DATA_HOME", ".local/share"), "fish", "vendor_completions.d", name),
+			filepath.Join(opts.Root, "/usr/local/share/fish/vendor_completions.d", name),
+			filepath.Join(opts.Root, "/usr/share/fish/vendor_completions.d", name),
+			filepath.Join(opts.Root, "/opt/homebrew/share/fish/vendor_completions.d", name))
+	}
+
+	var found []string
+	for _, path := range candidates {
+		if fileExists(path) {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// rcFiles returns the startup files of the shell
+func (r *ShellReport) rcFiles(opts ShellOptions) []string {
+	switch r.Shell {
+	case "bash":
+		return []string{
+			filepath.Join(opts.Home, ".bashrc"),
+			filepath.Join(opts.Home, ".bash_profile"),
+			filepath.Join(opts.Home, ".bash_login"),
+			filepath.Join(opts.Home, ".profile"),
+		}
+	case "zsh":
+		dir := opts.Getenv("ZDOTDIR")
+		if dir == "" {
+			dir = opts.Home
+		}
+		return []string{filepath.Join(dir, ".zshrc"), filepath.Join(dir, ".zprofile"), filepath.Join(dir, ".zshenv")}
+	case "fish":
+		dir := filepath.Join(xdgDir(opts, "XDG_CONFIG_HOME", ".config"), "fish")
+		files, _ := filepath.Glob(filepath.Join(dir, "conf.d", "*.fish"))
+		return append([]string{filepath.Join(dir, "config.fish")}, files...)
+	}
+	return nil
+}
+
+// rcFile returns the startup file remediation steps append to
+func (r *ShellReport) rcFile(opts ShellOptions) string {
+	if files := r.rcFiles(opts); len(files) > 0 {
+		return tildePath(opts.Home, files[0])
+	}
+	return "~/.profile"
+}
+
+// rcContaining returns the first startup file with an uncommented line
+// containing text, or ""
+func (r *ShellReport) rcContaining(opts ShellOptions, text string) string {
+	for _, path := range r.rcFiles(opts) {
+		if fileContains(path, text) {
+			return path
+		}
+	}
+	return ""
+}
+
+func (r *ShellReport) add(check ShellCheck) {
+	r.Checks = append(r.Checks, check)
+}
+
+// ExitCode returns 0 when healthy, 1 with warnings and 2 with errors, like doctor
+func (r *ShellReport) ExitCode() int {
+	code := 0
+	for _, check := range r.Checks {
+		switch check.Status {
+		case ShellError:
+			return 2
+		case ShellWarning:
+			code = 1
+		}
+	}
+	return code
+}
+
+// Print shows each check with its remediation steps
+func (r *ShellReport) Print() {
+	ui.PrintHeader("🐚 Worktree Completion Doctor")
+	ui.NewLine()
+
+	for _, check := range r.Checks {
+		line := check.Name + ": " + check.Detail
+		switch check.Status {
+		case ShellOK:
+			ui.Success(line)
+		case ShellWarning:
+			ui.Warning(line)
+		case ShellError:
+			ui.Error(line)
+		default:
+			ui.Info(line)
+		}
+		if len(check.Fix) > 0 {
+			fmt.Println("    Fix:")
+			for _, step := range check.Fix {
+				fmt.Println("      " + step)
+			}
+		}
+	}
+	ui.NewLine()
+}
+
+// xdgDir returns the XDG base directory in env, or its default under the home directory
+func xdgDir(opts ShellOptions, env, fallback string) string {
+	if dir := opts.Getenv(env); dir != "" {
+		return dir
+	}
+	return filepath.Join(opts.Home, fallback)
+}
+
+// fileContains reports whether path has an uncommented line containing text
+func fileContains(path, text string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") && strings.Contains(line, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// samePath reports whether two paths name the same file, following symlinks
+func samePath(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return a == b
+}
+
+// tildePath shortens paths under the home directory to ~/...
+func tildePath(home, path string) string {
+	if rel, err := filepath.Rel(home, path); err == nil && home != "" && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
@@ -0,0 +1,173 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// shellTestOptions returns options for a fake home and filesystem root, with
+// the running binary found on PATH and a current zsh/bash/fish script "v2"
+func shellTestOptions(t *testing.T, shell string, env map[string]string) ShellOptions {
+	t.Helper()
+	home := t.TempDir()
+	executable := filepath.Join(home, "bin", "worktree")
+	writeTestFile(t, executable, "binary")
+	return ShellOptions{
+		Shell:      shell,
+		Program:    "worktree",
+		Executable: executable,
+		Home:       home,
+		Root:       t.TempDir(),
+		Getenv:     func(key string) string { return env[key] },
+		LookPath:   func(string) (string, error) { return executable, nil },
+		Complete:   func(string) error { return nil },
+		Scripts: map[string][][]byte{
+			"bash": {[]byte("bash v2")},
+			"zsh":  {[]byte("zsh v2"), []byte("zsh v2 nodesc")},
+			"fish": {[]byte("fish v2")},
+		},
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// findCheck returns the check with the given name
+func findCheck(t *testing.T, r *ShellReport, name string) ShellCheck {
+	t.Helper()
+	for _, check := range r.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %s check in %+v", name, r.Checks)
+	return ShellCheck{}
+}
+
+func TestCheckShell(t *testing.T) {
+	t.Run("zsh sourced from zshrc", func(t *testing.T) {
+		opts := shellTestOptions(t, "", map[string]string{"SHELL": "/usr/bin/zsh"})
+		writeTestFile(t, filepath.Join(opts.Home, ".zshrc"),
+			"autoload -U compinit; compinit\nsource <(worktree completion zsh)\nRPROMPT='$(worktree prompt --no-color)'\n")
+
+		r := CheckShell(opts)
+		if r.Shell != "zsh" {
+			t.Errorf("Shell = %q, want zsh", r.Shell)
+		}
+		for _, check := range r.Checks {
+			if check.Status != ShellOK {
+				t.Errorf("%s = %s (%s), want ok", check.Name, check.Status, check.Detail)
+			}
+		}
+		if r.ExitCode() != 0 {
+			t.Errorf("ExitCode() = %d, want 0", r.ExitCode())
+		}
+	})
+
+	t.Run("zsh without compinit and completion", func(t *testing.T) {
+		opts := shellTestOptions(t, "zsh", nil)
+		writeTestFile(t, filepath.Join(opts.Home, ".zshrc"), "# source <(worktree completion zsh)\n")
+
+		r := CheckShell(opts)
+		completion := findCheck(t, r, "Completion")
+		if completion.Status != ShellWarning || len(completion.Fix) == 0 || completion.Fix[0] != "echo 'source <(worktree completion zsh)' >> ~/.zshrc" {
+			t.Errorf("Completion = %+v, want a warning with the source line to add", completion)
+		}
+		if check := findCheck(t, r, "compinit"); check.Status != ShellWarning {
+			t.Errorf("compinit = %+v, want a warning", check)
+		}
+		if check := findCheck(t, r, "Prompt"); check.Status != ShellInfo {
+			t.Errorf("Prompt = %+v, want info", check)
+		}
+		if r.ExitCode() != 1 {
+			t.Errorf("ExitCode() = %d, want 1", r.ExitCode())
+		}
+	})
+
+	t.Run("zsh script in FPATH without descriptions", func(t *testing.T) {
+		fpath := t.TempDir()
+		opts := shellTestOptions(t, "zsh", map[string]string{"FPATH": fpath})
+		writeTestFile(t, filepath.Join(fpath, "_worktree"), "zsh v2 nodesc")
+
+		check := findCheck(t, CheckShell(opts), "Completion")
+		if check.Status != ShellOK || !strings.Contains(check.Detail, fpath) {
+			t.Errorf("Completion = %+v, want installed in %s", check, fpath)
+		}
+	})
+
+	t.Run("bash script out of date", func(t *testing.T) {
+		opts := shellTestOptions(t, "bash", nil)
+		writeTestFile(t, filepath.Join(opts.Root, "etc/bash_completion.d/worktree"), "bash v1")
+		writeTestFile(t, filepath.Join(opts.Root, "usr/share/bash-completion/bash_completion"), "")
+
+		r := CheckShell(opts)
+		completion := findCheck(t, r, "Completion")
+		want := "worktree completion bash > " + filepath.Join(opts.Root, "etc/bash_completion.d/worktree")
+		if completion.Status != ShellWarning || len(completion.Fix) != 1 || completion.Fix[0] != want {
+			t.Errorf("Completion = %+v, want a warning to run %q", completion, want)
+		}
+		if check := findCheck(t, r, "bash-completion"); check.Status != ShellOK {
+			t.Errorf("bash-completion = %+v, want ok", check)
+		}
+	})
+
+	t.Run("fish with XDG config", func(t *testing.T) {
+		config := t.TempDir()
+		opts := shellTestOptions(t, "fish", map[string]string{"XDG_CONFIG_HOME": config})
+		writeTestFile(t, filepath.Join(config, "fish/completions/worktree.fish"), "fish v2")
+		writeTestFile(t, filepath.Join(config, "fish/conf.d/prompt.fish"), "function fish_right_prompt; worktree prompt; end\n")
+
+		r := CheckShell(opts)
+		if check := findCheck(t, r, "Completion"); check.Status != ShellOK {
+			t.Errorf("Completion = %+v, want ok", check)
+		}
+		if check := findCheck(t, r, "Prompt"); check.Status != ShellOK {
+			t.Errorf("Prompt = %+v, want ok", check)
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		opts := shellTestOptions(t, "bash", nil)
+		opts.LookPath = func(string) (string, error) { return "", errors.New("not found") }
+		r := CheckShell(opts)
+		check := findCheck(t, r, "Binary on PATH")
+		if check.Status != ShellError || !strings.Contains(check.Fix[0], filepath.Dir(opts.Executable)+":$PATH") {
+			t.Errorf("Binary on PATH = %+v, want an error adding the binary's directory to PATH", check)
+		}
+		if r.ExitCode() != 2 {
+			t.Errorf("ExitCode() = %d, want 2", r.ExitCode())
+		}
+
+		other := filepath.Join(t.TempDir(), "worktree")
+		writeTestFile(t, other, "old binary")
+		opts.LookPath = func(string) (string, error) { return other, nil }
+		opts.Complete = func(string) error { return errors.New("unknown command") }
+		r = CheckShell(opts)
+		if check := findCheck(t, r, "Binary on PATH"); check.Status != ShellWarning || !strings.Contains(check.Detail, other) {
+			t.Errorf("Binary on PATH = %+v, want a warning naming %s", check, other)
+		}
+		if check := findCheck(t, r, "Completion requests"); check.Status != ShellError {
+			t.Errorf("Completion requests = %+v, want an error", check)
+		}
+	})
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		r := CheckShell(shellTestOptions(t, "", map[string]string{"SHELL": "/bin/tcsh"}))
+		if check := findCheck(t, r, "Shell"); check.Status != ShellError || !strings.Contains(check.Detail, "'tcsh' is not supported") {
+			t.Errorf("Shell = %+v, want tcsh unsupported", check)
+		}
+		if r.ExitCode() != 2 {
+			t.Errorf("ExitCode() = %d, want 2", r.ExitCode())
+		}
+	})
+}
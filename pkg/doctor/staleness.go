@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"github.com/braunmar/worktree/pkg/config"
 	"github.com/braunmar/worktree/pkg/docker"
+	"github.com/braunmar/worktree/pkg/git"
 	"github.com/braunmar/worktree/pkg/registry"
 	"os"
-	"os/exec"
 	"strings"
 	"time"
 )
@@ -33,20 +33,26 @@ func CheckStaleness(cfg *config.Config, wt *registry.Worktree, projectName strin
 		}
 	}
 
+	// The project may check out its own branch (branch_template / --branch-map)
+	branch := wt.Branch
+	if current, err := git.GetWorktreeBranch(projectPath); err == nil {
+		branch = current
+	}
+
 	// Check if branch merged to main
-	mergeCheckCmd := exec.Command("git", "-C", projectPath, "branch", "--merged", "origin/main", "--format=%(refname:short)")
+	mergeCheckCmd := git.Command(projectPath, "branch", "--merged", "origin/main", "--format=%(refname:short)")
 	var mergeOut bytes.Buffer
 	mergeCheckCmd.Stdout = &mergeOut
 	if err := mergeCheckCmd.Run(); err == nil {
 		branches := strings.Split(strings.TrimSpace(mergeOut.String()), "\n")
 		for _, b := range branches {
 			// Check if this branch or its corresponding branch name matches
-			if strings.Contains(b, wt.Branch) || b == wt.Branch {
+			if strings.Contains(b, branch) || b == branch {
 				report.BranchMerged = true
 				report.Score++
 
 				// Get merge date (when was the last commit)
-				mergeDate := exec.Command("git", "-C", projectPath, "log", "-1", "--format=%ar", wt.Branch)
+				mergeDate := git.Command(projectPath, "log", "-1", "--format=%ar", branch)
 				var dateOut bytes.Buffer
 				mergeDate.Stdout = &dateOut
 				if err := mergeDate.Run(); err == nil {
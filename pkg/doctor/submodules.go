@@ -0,0 +1,40 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/git"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// CheckSubmodules reports the project worktrees of a feature that have
+// submodules which are registered but not checked out
+func CheckSubmodules(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) []SubmoduleReport {
+	var reports []SubmoduleReport
+	for _, projectName := range wt.Projects {
+		project, ok := workCfg.Projects[projectName]
+		if !ok {
+			continue
+		}
+
+		worktreePath := filepath.Join(cfg.WorktreeFeaturePath(wt.Normalized), project.Dir)
+		if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err != nil {
+			continue
+		}
+
+		paths, err := git.UninitializedSubmodules(worktreePath)
+		if err != nil || len(paths) == 0 {
+			continue
+		}
+		reports = append(reports, SubmoduleReport{
+			Feature:       wt.Normalized,
+			Project:       projectName,
+			Path:          filepath.Join("worktrees", wt.Normalized, project.Dir),
+			Uninitialized: paths,
+			Configured:    project.Submodules,
+		})
+	}
+	return reports
+}
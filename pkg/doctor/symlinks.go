@@ -0,0 +1,50 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/registry"
+)
+
+// CheckSymlinks reports configured symlinks of a feature that are missing,
+// point elsewhere or dangle
+func CheckSymlinks(cfg *config.Config, workCfg *config.WorktreeConfig, wt *registry.Worktree) []SymlinkReport {
+	featureDir := cfg.WorktreeFeaturePath(wt.Normalized)
+	if _, err := os.Stat(featureDir); err != nil {
+		return nil // Reported as an orphaned registry entry
+	}
+
+	var reports []SymlinkReport
+	for _, link := range workCfg.FeatureSymlinks(wt.Projects) {
+		problem := symlinkProblem(filepath.Join(featureDir, link.Target), link.Source)
+		if problem != "" {
+			reports = append(reports, SymlinkReport{Feature: wt.Normalized, Target: link.Target, Problem: problem})
+		}
+	}
+	return reports
+}
+
+// symlinkProblem describes what is wrong with the link at path, or returns ""
+func symlinkProblem(path, want string) string {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "missing"
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return "not a symlink"
+	}
+	current, err := os.Readlink(path)
+	if err != nil {
+		return fmt.Sprintf("unreadable: %v", err)
+	}
+	if current != want {
+		return fmt.Sprintf("points to %s instead of %s", current, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "source does not exist"
+	}
+	return ""
+}
@@ -9,21 +9,27 @@ import (
 type Options struct {
 	FeatureFilter string
 	NoFetch       bool
-	AutoFix       bool
 }
 
 // Report contains all diagnostic results
 type Report struct {
+	Feature     string `json:",omitempty"` // Set when the checks were scoped to one feature
 	Docker      DockerHealth
+	Git         GitHealth
+	Integrity   []IntegrityReport `json:",omitempty"` // State files failing their checksum
 	Consistency ConsistencyReport
+	Symlinks    []SymlinkReport `json:",omitempty"`
 	GitStatus   []GitStatusReport
+	Submodules  []SubmoduleReport `json:",omitempty"`
 	Staleness   []StalenessReport
 	Ports       PortReport
 	Summary     Summary
+	Fixes       []FixAction `json:",omitempty"` // Set by doctor --fix
 }
 
-// DockerHealth contains Docker availability status
+// DockerHealth contains container runtime (docker or podman) availability status
 type DockerHealth struct {
+	Runtime          string // "docker" or "podman"
 	Installed        bool
 	Running          bool
 	ComposeAvailable bool
@@ -31,12 +37,39 @@ type DockerHealth struct {
 	Error            string
 }
 
+// GitHealth contains the installed git version and the worktree features it lacks
+type GitHealth struct {
+	Version     string
+	Supported   bool     // At least git.MinVersion
+	Unavailable []string `json:",omitempty"` // Capabilities needing a newer git
+	Error       string   `json:",omitempty"`
+}
+
 // ConsistencyReport contains registry/directory/container consistency issues
 type ConsistencyReport struct {
-	OrphanedRegistryEntries []string // In registry but no directory
-	OrphanedDirectories     []string // Directory exists but not in registry
-	OrphanedContainers      []string // Containers running but not in registry
-	InvalidWorktrees        []string // Directory exists but not valid git worktree
+	OrphanedRegistryEntries []string              // In registry but no directory
+	OrphanedDirectories     []string              // Directory exists but not in registry
+	OrphanedContainers      []string              // Containers running but not in registry
+	InvalidWorktrees        []string              // Directory exists but not valid git worktree
+	ServiceOrphans          []ServiceOrphanReport `json:",omitempty"` // Containers of services no longer in a compose file
+}
+
+// ServiceOrphanReport lists the containers of a feature project whose compose
+// service was removed or renamed since they were started
+type ServiceOrphanReport struct {
+	Feature        string
+	Project        string
+	ComposeProject string
+	Containers     []string
+	Services       []string // Service of each container, in the same order
+}
+
+// IntegrityReport is a state file (registry or instance marker) that fails its checksum
+type IntegrityReport struct {
+	Path      string // Relative to the project root
+	Problem   string
+	HasBackup bool // A backup matching its checksum can be restored
+	Parses    bool // The content is valid JSON, so --fix keeps it instead of restoring the backup
 }
 
 // GitStatusReport contains git status for a single worktree
@@ -51,6 +84,22 @@ type GitStatusReport struct {
 	Error            string
 }
 
+// SubmoduleReport lists the uninitialized submodules of a project worktree
+type SubmoduleReport struct {
+	Feature       string
+	Project       string
+	Path          string   // Worktree path relative to the project root
+	Uninitialized []string // Submodule paths that are not checked out
+	Configured    bool     // Whether the project sets submodules: true
+}
+
+// SymlinkReport is a configured symlink of a feature that is not in place
+type SymlinkReport struct {
+	Feature string
+	Target  string // Link path relative to the feature dir
+	Problem string
+}
+
 // StalenessReport contains staleness metrics for a worktree
 type StalenessReport struct {
 	Feature           string
@@ -65,18 +114,23 @@ type StalenessReport struct {
 
 // PortReport contains port allocation status
 type PortReport struct {
-	Conflicts      []PortConflict
+	Conflicts      []PortConflict // Registered ports held by an unrelated process
+	Unbound        []PortConflict // Registered ports nobody listens on although the feature runs
 	OutOfRange     []PortOutOfRange
 	TotalAllocated int
 	TotalAvailable int
 	PortRanges     map[string]PortRangeInfo
+	PortPools      map[string]PortRangeInfo // Shared port_pools, keyed by pool name
 }
 
-// PortConflict represents a port that's allocated but in use
+// PortConflict represents a registered port whose host binding does not match the feature's state
 type PortConflict struct {
 	Service string
 	Port    int
 	Feature string
+	PID     int    `json:",omitempty"` // Process holding the port, if known
+	Process string `json:",omitempty"`
+	Reason  string
 }
 
 // PortOutOfRange represents a port allocation outside configured ranges
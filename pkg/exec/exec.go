@@ -0,0 +1,142 @@
+// Package exec runs a feature's shell commands (start commands and lifecycle
+// hooks), streaming their output live with a prefix and capturing it per
+// command in the feature's .logs directory.
+package exec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/process"
+)
+
+// LogDirName is the directory inside a feature that holds captured output
+const LogDirName = ".logs"
+
+// LogDir returns the captured-output directory of a feature
+func LogDir(featureDir string) string {
+	return filepath.Join(featureDir, LogDirName)
+}
+
+// Result records one command run
+type Result struct {
+	Name       string    `json:"name"`    // e.g. backend.start_post
+	Command    string    `json:"command"` // Shell command as configured
+	ExitCode   int       `json:"exit_code"`
+	Error      string    `json:"error,omitempty"` // Why it failed, e.g. "timed out after 5m0s"
+	Started    time.Time `json:"started"`
+	DurationMS int64     `json:"duration_ms"`
+	Log        string    `json:"log,omitempty"`      // Captured output, relative to the feature directory
+	Attempts   int       `json:"attempts,omitempty"` // Set when the command was retried
+}
+
+// Duration returns how long the command ran
+func (r Result) Duration() time.Duration {
+	return time.Duration(r.DurationMS) * time.Millisecond
+}
+
+// Failed reports whether the command did not exit 0
+func (r Result) Failed() bool {
+	return r.ExitCode != 0
+}
+
+// Runner runs commands of one feature
+type Runner struct {
+	FeatureDir string
+	Output     io.Writer // Live output (default: os.Stdout)
+}
+
+// New returns a runner that captures output in featureDir's .logs directory
+func New(featureDir string) *Runner {
+	return &Runner{FeatureDir: featureDir, Output: os.Stdout}
+}
+
+// Run runs a shell command in dir with env. Every output line is written to
+// Output prefixed with "[name] ", and as is to .logs/<name>.log, which is
+// replaced on every run. A timeout of 0 means no limit. The result is
+// returned even when the command fails; a capture that cannot be written
+// only loses the log file.
+func (r *Runner) Run(name, command, dir string, env []string, timeout time.Duration) (Result, error) {
+	result := Result{Name: name, Command: command, Started: time.Now()}
+
+	live := &prefixWriter{out: r.output(), prefix: "[" + name + "] "}
+	var out io.Writer = live
+	logPath := filepath.Join(LogDir(r.FeatureDir), name+".log")
+	var logFile *os.File
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err == nil {
+		if logFile, err = os.Create(logPath); err == nil {
+			fmt.Fprintf(logFile, "$ %s\n", command)
+			out = io.MultiWriter(live, logFile)
+			result.Log = filepath.Join(LogDirName, name+".log")
+		}
+	}
+
+	err := process.RunWithTimeout(command, dir, env, out, timeout)
+	if logFile != nil && len(live.pending) > 0 {
+		fmt.Fprintln(logFile) // Keep the footer on its own line
+	}
+	live.Flush()
+	result.DurationMS = time.Since(result.Started).Milliseconds()
+	if err != nil {
+		result.ExitCode = -1
+		var exitErr *osexec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		result.Error = err.Error()
+	}
+
+	if logFile != nil {
+		if err != nil {
+			fmt.Fprintf(logFile, "# failed after %s: %v\n", result.Duration(), err)
+		} else {
+			fmt.Fprintf(logFile, "# completed in %s\n", result.Duration())
+		}
+		logFile.Close()
+	}
+	return result, err
+}
+
+func (r *Runner) output() io.Writer {
+	if r.Output == nil {
+		return os.Stdout
+	}
+	return r.Output
+}
+
+// prefixWriter writes complete lines to out, each starting with prefix
+type prefixWriter struct {
+	out     io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		if _, err := io.WriteString(w.out, w.prefix+string(w.pending[:i+1])); err != nil {
+			return len(p), err
+		}
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes a trailing line that did not end in a newline
+func (w *prefixWriter) Flush() {
+	if len(w.pending) > 0 {
+		io.WriteString(w.out, w.prefix+strings.TrimRight(string(w.pending), "\r")+"\n")
+		w.pending = nil
+	}
+}
@@ -0,0 +1,57 @@
+package exec
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/process"
+)
+
+func TestRunCapturesOutput(t *testing.T) {
+	featureDir := t.TempDir()
+	var live bytes.Buffer
+	r := &Runner{FeatureDir: featureDir, Output: &live}
+
+	result, err := r.Run("backend.start_post", "echo one; echo two >&2; printf three", featureDir, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 || result.Failed() || result.Log != filepath.Join(".logs", "backend.start_post.log") {
+		t.Errorf("result = %+v", result)
+	}
+
+	want := "[backend.start_post] one\n[backend.start_post] two\n[backend.start_post] three\n"
+	if live.String() != want {
+		t.Errorf("live output = %q, want %q", live.String(), want)
+	}
+	data, err := os.ReadFile(filepath.Join(featureDir, result.Log))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "$ echo one") || !strings.Contains(string(data), "one\ntwo\nthree\n# completed in") {
+		t.Errorf("captured log = %q", data)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	featureDir := t.TempDir()
+	r := &Runner{FeatureDir: featureDir, Output: &bytes.Buffer{}}
+
+	result, err := r.Run("backend.stop_pre", "exit 3", featureDir, nil, 0)
+	if err == nil || result.ExitCode != 3 || !result.Failed() || result.Error == "" {
+		t.Errorf("result = %+v, err = %v", result, err)
+	}
+
+	result, err = r.Run("backend.start_pre", "sleep 5", featureDir, nil, 100*time.Millisecond)
+	if !errors.Is(err, process.ErrTimeout) || result.ExitCode != -1 {
+		t.Errorf("result = %+v, err = %v", result, err)
+	}
+	if result.Duration() >= 5*time.Second {
+		t.Errorf("timeout did not stop the command: ran %s", result.Duration())
+	}
+}
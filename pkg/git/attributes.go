@@ -0,0 +1,86 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SetMergeDriver defines a custom merge driver in the repository's config,
+// shared by all of its worktrees
+func SetMergeDriver(repoPath, name, description, driver string) error {
+	if err := runGit(repoPath, "config", "merge."+name+".name", description); err != nil {
+		return err
+	}
+	return runGit(repoPath, "config", "merge."+name+".driver", driver)
+}
+
+// SetAttributes replaces the block of lines marked with marker in the
+// repository's info/attributes, which applies to all of its worktrees without
+// touching the tracked .gitattributes. No lines removes the block.
+func SetAttributes(repoPath, marker string, lines []string) error {
+	return setInfoBlock(repoPath, "attributes", marker, lines)
+}
+
+// SetExcludes replaces the block of patterns marked with marker in the
+// repository's info/exclude, which ignores files in all of its worktrees
+// without touching the tracked .gitignore. No patterns removes the block.
+func SetExcludes(repoPath, marker string, patterns []string) error {
+	return setInfoBlock(repoPath, "exclude", marker, patterns)
+}
+
+// setInfoBlock replaces the block of lines marked with marker in a file of
+// the repository's shared info directory
+func setInfoBlock(repoPath, file, marker string, lines []string) error {
+	gitDir, err := commonGitDir(repoPath)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(gitDir, "info", file)
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	begin, end := "# BEGIN "+marker, "# END "+marker
+	var kept []string
+	inBlock := false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		switch {
+		case line == begin:
+			inBlock = true
+		case line == end:
+			inBlock = false
+		case !inBlock:
+			kept = append(kept, line)
+		}
+	}
+	if inBlock {
+		return fmt.Errorf("%s: missing '%s'", path, end)
+	}
+	for len(kept) > 0 && kept[len(kept)-1] == "" {
+		kept = kept[:len(kept)-1]
+	}
+	if len(lines) > 0 {
+		kept = append(kept, begin)
+		kept = append(kept, lines...)
+		kept = append(kept, end)
+	}
+
+	content := ""
+	if len(kept) > 0 {
+		content = strings.Join(kept, "\n") + "\n"
+	}
+	if content == string(data) {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package git
+
+import (
+	"os/exec"
+
+	"github.com/braunmar/worktree/pkg/ui"
+)
+
+// Command builds a git command that runs in dir (git -C dir args...).
+// In verbose mode the command is shown before it runs.
+func Command(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	ui.Trace(ui.ScopeGit, cmd)
+	return cmd
+}
@@ -0,0 +1,100 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fetchStampDir holds one file per remote inside the git common dir; its
+// modification time records the last fetch made through a FetchCoordinator
+const fetchStampDir = "worktree-fetch"
+
+// FetchCoordinator fetches each remote of a repository at most once per
+// command invocation. Worktrees of the same repository share their objects
+// and remote-tracking branches, so they are fetched once. With a TTL, a
+// remote fetched within the TTL by an earlier invocation is not fetched again.
+// It is safe for concurrent use; concurrent calls for the same repository
+// wait for the one fetch.
+type FetchCoordinator struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	done map[string]*fetchOnce // "<git common dir>\x00<remote>" → the fetch
+}
+
+// fetchOnce is one repository remote fetched by a FetchCoordinator
+type fetchOnce struct {
+	once sync.Once
+	err  error
+}
+
+// NewFetchCoordinator creates a coordinator; ttl 0 always fetches once
+func NewFetchCoordinator(ttl time.Duration) *FetchCoordinator {
+	return &FetchCoordinator{ttl: ttl, done: make(map[string]*fetchOnce)}
+}
+
+// Fetch fetches remote for the repository or worktree at repoPath unless it
+// was already fetched by this coordinator or within the TTL. It reports
+// whether a fetch actually ran.
+func (f *FetchCoordinator) Fetch(repoPath, remote string) (bool, error) {
+	commonDir, err := commonGitDir(repoPath)
+	if err != nil {
+		return false, err
+	}
+
+	key := commonDir + "\x00" + remote
+	f.mu.Lock()
+	entry, ok := f.done[key]
+	if !ok {
+		entry = &fetchOnce{}
+		f.done[key] = entry
+	}
+	f.mu.Unlock()
+
+	fetched := false
+	entry.once.Do(func() {
+		fetched, entry.err = f.fetch(repoPath, remote, filepath.Join(commonDir, fetchStampDir, remote))
+	})
+	return fetched, entry.err
+}
+
+// fetch runs the fetch unless the stamp is within the TTL
+func (f *FetchCoordinator) fetch(repoPath, remote, stamp string) (bool, error) {
+	if f.ttl > 0 {
+		if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < f.ttl {
+			return false, nil
+		}
+	}
+
+	if err := Fetch(repoPath, remote); err != nil {
+		return true, err
+	}
+
+	// The stamp only saves time later; failing to write it is harmless
+	if os.MkdirAll(filepath.Dir(stamp), 0755) == nil {
+		_ = os.WriteFile(stamp, []byte(time.Now().Format(time.RFC3339)+"\n"), 0644)
+	}
+	return true, nil
+}
+
+// commonGitDir returns the absolute git directory shared by all worktrees of
+// the repository at repoPath
+func commonGitDir(repoPath string) (string, error) {
+	if err := Require(CapPathFormat); err != nil {
+		return "", err
+	}
+
+	absRepoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for repository: %w", err)
+	}
+
+	output, err := Command(absRepoPath, "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find git directory of %s: %w", repoPath, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
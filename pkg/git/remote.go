@@ -0,0 +1,82 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HasRemote reports whether the repository has the named remote configured
+func HasRemote(repoPath, remote string) bool {
+	return Command(repoPath, "remote", "get-url", remote).Run() == nil
+}
+
+// Fetch fetches from remote, updating its remote-tracking branches
+func Fetch(worktreePath, remote string) error {
+	return runGit(worktreePath, "fetch", remote)
+}
+
+// AheadBehind counts the commits on HEAD that are not on ref (ahead) and the
+// commits on ref that are not on HEAD (behind)
+func AheadBehind(worktreePath, ref string) (ahead, behind int, err error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "rev-list", "--left-right", "--count", "HEAD..."+ref)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return 0, 0, fmt.Errorf("failed to compare HEAD with %s: %s", ref, strings.TrimSpace(stderr.String()))
+	}
+
+	fields := strings.Fields(stdout.String())
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rev-list output: %q", stdout.String())
+	}
+	if ahead, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse ahead count: %w", err)
+	}
+	if behind, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, fmt.Errorf("failed to parse behind count: %w", err)
+	}
+	return ahead, behind, nil
+}
+
+// FastForward moves the worktree's branch to ref, failing unless it is a fast-forward
+func FastForward(worktreePath, ref string) error {
+	return runGit(worktreePath, "merge", "--ff-only", ref)
+}
+
+// Merge merges ref into the worktree's branch, creating a merge commit with
+// the default message when needed
+func Merge(worktreePath, ref string) error {
+	return runGit(worktreePath, "merge", "--no-edit", ref)
+}
+
+// Push pushes branch to remote and sets it as the branch's upstream
+func Push(worktreePath, remote, branch string) error {
+	return runGit(worktreePath, "push", "--set-upstream", remote, branch)
+}
+
+// runGit runs a git command in dir, returning its stderr as the error
+func runGit(dir string, args ...string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	cmd := Command(absDir, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
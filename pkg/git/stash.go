@@ -0,0 +1,58 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Stash stashes the worktree's uncommitted changes, including untracked
+// files, under message. It returns false when there was nothing to stash.
+func Stash(worktreePath, message string) (bool, error) {
+	changes, err := HasUncommittedChanges(worktreePath)
+	if err != nil {
+		return false, err
+	}
+	if !changes {
+		return false, nil
+	}
+	if err := runGit(worktreePath, "stash", "push", "--include-untracked", "--message", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FindStash returns the ref (e.g. stash@{2}) of the most recent stash entry
+// created with message, or "" when there is none. Worktrees of a repository
+// share one stash list, so entries are told apart by their message.
+func FindStash(worktreePath, message string) (string, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "stash", "list", "--format=%gd %gs")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to list stashes: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// Subjects look like "On <branch>: <message>"
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		ref, subject, ok := strings.Cut(line, " ")
+		if ok && strings.HasSuffix(subject, ": "+message) {
+			return ref, nil
+		}
+	}
+	return "", nil
+}
+
+// StashPop applies the stash entry ref to the worktree and drops it. On
+// conflicts the entry is kept so nothing is lost.
+func StashPop(worktreePath, ref string) error {
+	return runGit(worktreePath, "stash", "pop", ref)
+}
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateSubmodules initializes and checks out all submodules of a worktree,
+// recursively. Git's progress output is written to progress.
+func UpdateSubmodules(worktreePath string, progress io.Writer) error {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "submodule", "update", "--init", "--recursive", "--progress")
+	cmd.Stdout = progress
+	cmd.Stderr = progress
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git submodule update failed: %w", err)
+	}
+	return nil
+}
+
+// UninitializedSubmodules lists the paths of submodules that are registered in
+// the worktree but not checked out
+func UninitializedSubmodules(worktreePath string) ([]string, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "submodule", "status", "--recursive")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to get submodule status: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// Lines look like "-<sha> <path>" for submodules that are not initialized
+	var paths []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if !strings.HasPrefix(line, "-") {
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) >= 2 {
+			paths = append(paths, fields[1])
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// Version is a parsed git version (git version 2.39.3 → 2.39.3)
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// String returns the version as major.minor.patch
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is the same as or newer than other
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// Capability is a git feature used by worktree together with the git version
+// that introduced it
+type Capability struct {
+	Name  string
+	Since Version
+}
+
+var (
+	// CapWorktreeRemove is the newest git feature every command relies on, so
+	// its version is the minimum git version worktree supports
+	CapWorktreeRemove = Capability{Name: "git worktree remove", Since: Version{Major: 2, Minor: 17}}
+
+	// CapPathFormat resolves the shared git directory of a worktree, used to
+	// remember fetch times for fetch_ttl
+	CapPathFormat = Capability{Name: "git rev-parse --path-format", Since: Version{Major: 2, Minor: 31}}
+)
+
+// MinVersion is the oldest git version worktree works with
+var MinVersion = CapWorktreeRemove.Since
+
+// Capabilities lists the git features worktree checks for, oldest first
+var Capabilities = []Capability{CapWorktreeRemove, CapPathFormat}
+
+var versionPattern = regexp.MustCompile(`^git version (\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion parses the output of git --version, including vendor suffixes
+// such as "2.39.3 (Apple Git-146)" or "2.45.1.windows.1"
+func ParseVersion(output string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return Version{}, fmt.Errorf("unrecognized git version %q", output)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+var (
+	detectOnce      sync.Once
+	detectedVersion Version
+	detectErr       error
+)
+
+// DetectVersion returns the version of the git on PATH. git is asked once per
+// process; later calls return the cached result.
+func DetectVersion() (Version, error) {
+	detectOnce.Do(func() {
+		output, err := exec.Command("git", "--version").Output()
+		if err != nil {
+			detectErr = fmt.Errorf("git not found or not runnable: %w", err)
+			return
+		}
+		detectedVersion, detectErr = ParseVersion(string(output))
+	})
+	return detectedVersion, detectErr
+}
+
+// Require returns an error explaining the upgrade needed when the installed
+// git is older than the capability. An undetectable git version is not an
+// error here; the git command itself reports that.
+func Require(c Capability) error {
+	v, err := DetectVersion()
+	if err != nil {
+		return nil
+	}
+	return requireVersion(v, c)
+}
+
+func requireVersion(v Version, c Capability) error {
+	if v.AtLeast(c.Since) {
+		return nil
+	}
+	return fmt.Errorf("%s needs git %d.%d or newer, but git %s is installed; please upgrade git",
+		c.Name, c.Since.Major, c.Since.Minor, v)
+}
+
+// CheckMinVersion returns an error when the installed git is older than
+// MinVersion
+func CheckMinVersion() error {
+	v, err := DetectVersion()
+	if err != nil {
+		return nil
+	}
+	if v.AtLeast(MinVersion) {
+		return nil
+	}
+	return fmt.Errorf("worktree needs git %d.%d or newer, but git %s is installed; please upgrade git",
+		MinVersion.Major, MinVersion.Minor, v)
+}
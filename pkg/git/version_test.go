@@ -0,0 +1,67 @@
+package git
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    Version
+		wantErr bool
+	}{
+		{output: "git version 2.39.5\n", want: Version{2, 39, 5}},
+		{output: "git version 2.39.3 (Apple Git-146)", want: Version{2, 39, 3}},
+		{output: "git version 2.45.1.windows.1", want: Version{2, 45, 1}},
+		{output: "git version 3.0", want: Version{3, 0, 0}},
+		{output: "hub version 2.14.2", wantErr: true},
+		{output: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.output)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVersion(%q) error = %v, wantErr %v", tt.output, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		v, other Version
+		want     bool
+	}{
+		{Version{2, 31, 0}, Version{2, 31, 0}, true},
+		{Version{2, 31, 1}, Version{2, 31, 0}, true},
+		{Version{2, 30, 9}, Version{2, 31, 0}, false},
+		{Version{3, 0, 0}, Version{2, 31, 0}, true},
+		{Version{1, 99, 0}, Version{2, 0, 0}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.v.AtLeast(tt.other); got != tt.want {
+			t.Errorf("%v.AtLeast(%v) = %v, want %v", tt.v, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestRequireVersion(t *testing.T) {
+	if err := requireVersion(Version{2, 31, 0}, CapPathFormat); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err := requireVersion(Version{2, 25, 1}, CapPathFormat)
+	if err == nil {
+		t.Fatal("expected an error for git 2.25.1")
+	}
+	for _, want := range []string{"needs git 2.31", "2.25.1 is installed"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
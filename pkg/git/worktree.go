@@ -6,7 +6,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // WorktreeInfo holds information about a worktree
@@ -16,8 +18,9 @@ type WorktreeInfo struct {
 	Clean  bool
 }
 
-// CreateWorktree creates a new git worktree
-func CreateWorktree(repoPath, worktreePath, branch string) error {
+// CreateWorktree creates a new git worktree checking out branch. A missing
+// branch is created from base, or from the repository's HEAD when base is empty.
+func CreateWorktree(repoPath, worktreePath, branch, base string) error {
 	// Convert to absolute paths
 	absRepoPath, err := filepath.Abs(repoPath)
 	if err != nil {
@@ -30,16 +33,22 @@ func CreateWorktree(repoPath, worktreePath, branch string) error {
 	}
 
 	// Check if branch already exists
-	checkCmd := exec.Command("git", "-C", absRepoPath, "rev-parse", "--verify", branch)
+	checkCmd := Command(absRepoPath, "rev-parse", "--verify", branch)
 	branchExists := checkCmd.Run() == nil
 
 	var cmd *exec.Cmd
 	if branchExists {
 		// Check out existing branch
-		cmd = exec.Command("git", "-C", absRepoPath, "worktree", "add", absWorktreePath, branch)
+		cmd = Command(absRepoPath, "worktree", "add", absWorktreePath, branch)
+	} else if base != "" {
+		// Create new branch from base
+		if !RefExists(absRepoPath, base) {
+			return fmt.Errorf("cannot create branch %s: base ref %s not found", branch, base)
+		}
+		cmd = Command(absRepoPath, "worktree", "add", "-b", branch, absWorktreePath, base)
 	} else {
-		// Create new branch
-		cmd = exec.Command("git", "-C", absRepoPath, "worktree", "add", "-b", branch, absWorktreePath)
+		// Create new branch from HEAD
+		cmd = Command(absRepoPath, "worktree", "add", "-b", branch, absWorktreePath)
 	}
 
 	var stderr bytes.Buffer
@@ -78,7 +87,7 @@ func RemoveWorktree(repoPath, worktreePath string) error {
 	}
 
 	// Remove the worktree
-	cmd := exec.Command("git", "-C", absRepoPath, "worktree", "remove", absWorktreePath)
+	cmd := Command(absRepoPath, "worktree", "remove", absWorktreePath)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -96,7 +105,7 @@ func PruneWorktrees(repoPath string) error {
 		return fmt.Errorf("failed to get absolute path for repo: %w", err)
 	}
 
-	cmd := exec.Command("git", "-C", absRepoPath, "worktree", "prune")
+	cmd := Command(absRepoPath, "worktree", "prune")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to prune worktrees: %w", err)
 	}
@@ -104,6 +113,32 @@ func PruneWorktrees(repoPath string) error {
 	return nil
 }
 
+// PrunableWorktrees lists the stale worktree metadata entries that
+// 'git worktree prune' would remove, one line per entry
+func PrunableWorktrees(repoPath string) ([]string, error) {
+	absRepoPath, err := filepath.Abs(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for repo: %w", err)
+	}
+
+	cmd := Command(absRepoPath, "worktree", "prune", "--dry-run", "--verbose")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to check prunable worktrees: %s", strings.TrimSpace(out.String()))
+	}
+
+	var entries []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
 // ListWorktrees lists all worktrees for a repository
 func ListWorktrees(repoPath string) ([]WorktreeInfo, error) {
 	absRepoPath, err := filepath.Abs(repoPath)
@@ -111,7 +146,7 @@ func ListWorktrees(repoPath string) ([]WorktreeInfo, error) {
 		return nil, fmt.Errorf("failed to get absolute path for repo: %w", err)
 	}
 
-	cmd := exec.Command("git", "-C", absRepoPath, "worktree", "list", "--porcelain")
+	cmd := Command(absRepoPath, "worktree", "list", "--porcelain")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -152,7 +187,7 @@ func GetWorktreeBranch(worktreePath string) (string, error) {
 		return "", fmt.Errorf("failed to get absolute path for worktree: %w", err)
 	}
 
-	cmd := exec.Command("git", "-C", absWorktreePath, "rev-parse", "--abbrev-ref", "HEAD")
+	cmd := Command(absWorktreePath, "rev-parse", "--abbrev-ref", "HEAD")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -170,7 +205,7 @@ func HasUncommittedChanges(worktreePath string) (bool, error) {
 		return false, fmt.Errorf("failed to get absolute path for worktree: %w", err)
 	}
 
-	cmd := exec.Command("git", "-C", absWorktreePath, "status", "--porcelain")
+	cmd := Command(absWorktreePath, "status", "--porcelain")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -181,6 +216,52 @@ func HasUncommittedChanges(worktreePath string) (bool, error) {
 	return strings.TrimSpace(stdout.String()) != "", nil
 }
 
+// RebaseInProgress reports whether a rebase is stopped (e.g. on conflicts) in a worktree
+func RebaseInProgress(worktreePath string) bool {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return false
+	}
+
+	for _, stateDir := range []string{"rebase-merge", "rebase-apply"} {
+		out, err := Command(absWorktreePath, "rev-parse", "--git-path", stateDir).Output()
+		if err != nil {
+			continue
+		}
+		path := strings.TrimSpace(string(out))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(absWorktreePath, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeInProgress reports whether a merge is stopped (e.g. on conflicts) in a worktree
+func MergeInProgress(worktreePath string) bool {
+	return Command(worktreePath, "rev-parse", "--verify", "--quiet", "MERGE_HEAD").Run() == nil
+}
+
+// ConflictedFiles lists the files with unresolved merge conflicts in a worktree
+func ConflictedFiles(worktreePath string) ([]string, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "diff", "--name-only", "--diff-filter=U")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	return strings.Fields(stdout.String()), nil
+}
+
 // GetUncommittedChangesCount returns the number of uncommitted changes
 func GetUncommittedChangesCount(worktreePath string) (int, error) {
 	absWorktreePath, err := filepath.Abs(worktreePath)
@@ -188,7 +269,7 @@ func GetUncommittedChangesCount(worktreePath string) (int, error) {
 		return 0, fmt.Errorf("failed to get absolute path for worktree: %w", err)
 	}
 
-	cmd := exec.Command("git", "-C", absWorktreePath, "status", "--porcelain")
+	cmd := Command(absWorktreePath, "status", "--porcelain")
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
@@ -203,3 +284,129 @@ func GetUncommittedChangesCount(worktreePath string) (int, error) {
 
 	return len(lines), nil
 }
+
+// LastCommitTime returns the committer date of HEAD in a worktree
+func LastCommitTime(worktreePath string) (time.Time, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "log", "-1", "--format=%ct", "HEAD")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last commit: %w", err)
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(stdout.String()), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time: %w", err)
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+// MainRef returns the ref to compare a worktree against: origin/<mainBranch> when
+// it exists, the local <mainBranch> otherwise
+func MainRef(worktreePath, mainBranch string) string {
+	remote := "origin/" + mainBranch
+	if Command(worktreePath, "rev-parse", "--verify", "--quiet", remote).Run() == nil {
+		return remote
+	}
+	return mainBranch
+}
+
+// IsHeadMergedInto reports whether the worktree's HEAD commit is reachable from ref
+func IsHeadMergedInto(worktreePath, ref string) (bool, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "merge-base", "--is-ancestor", "HEAD", ref)
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to compare HEAD with %s: %w", ref, err)
+	}
+
+	return true, nil
+}
+
+// UnmergedCommits counts the commits on the worktree's HEAD that are not reachable from ref
+func UnmergedCommits(worktreePath, ref string) (int, error) {
+	return countCommits(worktreePath, ref+"..HEAD")
+}
+
+// UnpushedCommits counts the commits on the worktree's HEAD that are on no
+// remote-tracking branch and not reachable from mainRef, i.e. the commits that
+// exist only in this local branch
+func UnpushedCommits(worktreePath, mainRef string) (int, error) {
+	return countCommits(worktreePath, "HEAD", "--not", "--remotes", mainRef)
+}
+
+func countCommits(worktreePath string, revs ...string) (int, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, append([]string{"rev-list", "--count"}, revs...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("failed to count commits: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(stdout.String()))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse commit count: %w", err)
+	}
+	return count, nil
+}
+
+// HeadCommit returns the full commit hash of HEAD in a worktree
+func HeadCommit(worktreePath string) (string, error) {
+	absWorktreePath, err := filepath.Abs(worktreePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path for worktree: %w", err)
+	}
+
+	cmd := Command(absWorktreePath, "rev-parse", "HEAD")
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to read HEAD commit: %w", err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// BranchExists reports whether a local branch exists in the repository
+func BranchExists(repoPath, branch string) bool {
+	return Command(repoPath, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+}
+
+// RefExists reports whether ref (branch, tag, remote branch or commit) resolves to a commit
+func RefExists(repoPath, ref string) bool {
+	return Command(repoPath, "rev-parse", "--verify", "--quiet", ref+"^{commit}").Run() == nil
+}
+
+// CreateBranch creates a local branch pointing at commit
+func CreateBranch(repoPath, branch, commit string) error {
+	cmd := Command(repoPath, "branch", branch, commit)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to create branch %s at %s: %s", branch, commit, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package health probes whether a feature's services are ready: an HTTP URL
+// answering, a TCP port accepting connections or a command exiting 0.
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+// attemptTimeout bounds a single probe so a hanging service cannot stall retries
+const attemptTimeout = 5 * time.Second
+
+// Probe runs a resolved health check once. Commands run in dir with env.
+func Probe(check config.HealthCheck, dir string, env []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), attemptTimeout)
+	defer cancel()
+
+	switch {
+	case check.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
+		if err != nil {
+			return err
+		}
+		// Redirects count as healthy, so do not follow them to e.g. a login page
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("%s returned %s", check.URL, resp.Status)
+		}
+		return nil
+	case check.TCP != "":
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", check.TCP)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	default:
+		cmd := exec.CommandContext(ctx, "sh", "-c", check.Command)
+		cmd.Dir = dir
+		cmd.Env = env
+		var output bytes.Buffer
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Run(); err != nil {
+			if out := strings.TrimSpace(output.String()); out != "" {
+				return fmt.Errorf("%v: %s", err, lastLine(out))
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// Wait probes a resolved health check until it passes or its timeout is
+// reached, and returns the last failure
+func Wait(check config.HealthCheck, dir string, env []string) error {
+	deadline := time.Now().Add(check.GetTimeout())
+	for {
+		err := Probe(check, dir, env)
+		if err == nil {
+			return nil
+		}
+		if time.Now().Add(check.GetInterval()).After(deadline) {
+			return fmt.Errorf("not healthy after %s: %w", check.GetTimeout(), err)
+		}
+		time.Sleep(check.GetInterval())
+	}
+}
+
+// lastLine returns the last line of s
+func lastLine(s string) string {
+	return s[strings.LastIndex(s, "\n")+1:]
+}
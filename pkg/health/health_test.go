@@ -0,0 +1,79 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.WriteHeader(http.StatusOK)
+		case "/login":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := ln.Addr().String()
+	ln.Close()
+
+	tests := []struct {
+		name    string
+		check   config.HealthCheck
+		wantErr string
+	}{
+		{name: "http ok", check: config.HealthCheck{URL: server.URL + "/health"}},
+		{name: "http redirect", check: config.HealthCheck{URL: server.URL + "/login"}},
+		{name: "http unavailable", check: config.HealthCheck{URL: server.URL + "/down"}, wantErr: "503"},
+		{name: "tcp open", check: config.HealthCheck{TCP: strings.TrimPrefix(server.URL, "http://")}},
+		{name: "tcp closed", check: config.HealthCheck{TCP: closedAddr}, wantErr: "refused"},
+		{name: "command ok", check: config.HealthCheck{Command: "test \"$READY\" = yes"}},
+		{name: "command fails", check: config.HealthCheck{Command: "echo not ready; exit 1"}, wantErr: "not ready"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Probe(tt.check, t.TempDir(), []string{"READY=yes"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	marker := t.TempDir() + "/ready"
+	// Becomes healthy on the third attempt
+	check := config.HealthCheck{
+		Command:  "n=$(cat " + marker + " 2>/dev/null || echo 0); echo $((n+1)) > " + marker + "; [ $n -ge 2 ]",
+		Timeout:  "5s",
+		Interval: "10ms",
+	}
+	if err := Wait(check, t.TempDir(), nil); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+
+	never := config.HealthCheck{Command: "exit 1", Timeout: "50ms", Interval: "10ms"}
+	if err := Wait(never, t.TempDir(), nil); err == nil || !strings.Contains(err.Error(), "not healthy after 50ms") {
+		t.Errorf("Wait() = %v, want timeout error", err)
+	}
+}
@@ -0,0 +1,114 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogDirName holds one structured JSON log per execution, named by its ID
+const LogDirName = ".agent-logs"
+
+// Log event phases
+const (
+	PhaseRun  = "run"  // The execution as a whole
+	PhaseStep = "step" // A step of the task
+	PhaseGate = "gate" // A safety gate
+	PhaseGit  = "git"  // A git or forge operation: commit, push, pull/merge request, commit status
+)
+
+// ExecutionLog is the structured log of one execution
+type ExecutionLog struct {
+	ID        string     `json:"id"`
+	AgentName string     `json:"agent_name"`
+	Trigger   string     `json:"trigger,omitempty"`
+	Worktree  string     `json:"worktree,omitempty"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	Events    []LogEvent `json:"events"`
+}
+
+// LogEvent is one thing that happened during an execution
+type LogEvent struct {
+	Time       time.Time         `json:"time"`
+	Phase      string            `json:"phase"` // "run", "step", "gate" or "git"
+	Name       string            `json:"name"`
+	Status     string            `json:"status"` // e.g. "started", "completed", "failed", "skipped"
+	DurationMS int64             `json:"duration_ms,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Details    map[string]string `json:"details,omitempty"` // e.g. project, branch, command, url
+}
+
+// SaveLog writes the structured log of an execution and returns its path
+// relative to the worktrees directory, to keep in ExecutionRecord.Log
+func (h *History) SaveLog(log *ExecutionLog) (string, error) {
+	rel := filepath.Join(LogDirName, log.ID+".json")
+	path := filepath.Join(filepath.Dir(h.path), rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal execution log: %w", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write execution log: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to write execution log: %w", err)
+	}
+	return rel, nil
+}
+
+// LoadLog reads the structured log a record links to
+func (h *History) LoadLog(record *ExecutionRecord) (*ExecutionLog, error) {
+	if record.Log == "" {
+		return nil, fmt.Errorf("execution %s has no log", shortHash(record.ID))
+	}
+	data, err := os.ReadFile(h.LogPath(record))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("execution log %s not found", record.Log)
+		}
+		return nil, fmt.Errorf("failed to read execution log: %w", err)
+	}
+	var log ExecutionLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return nil, fmt.Errorf("failed to parse execution log: %w", err)
+	}
+	return &log, nil
+}
+
+// LogPath returns the path of the log a record links to
+func (h *History) LogPath(record *ExecutionRecord) string {
+	return filepath.Join(filepath.Dir(h.path), record.Log)
+}
+
+// pruneLogsUnlocked removes logs no record links to (caller holds the lock)
+func (h *History) pruneLogsUnlocked() {
+	linked := make(map[string]bool)
+	for _, record := range h.Records {
+		if record.Log != "" {
+			linked[filepath.Base(record.Log)] = true
+		}
+	}
+
+	dir := filepath.Join(filepath.Dir(h.path), LogDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") && !linked[entry.Name()] {
+			os.Remove(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExecutionLogs(t *testing.T) {
+	dir := t.TempDir()
+	h, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	rel, err := h.SaveLog(&ExecutionLog{
+		ID:        "run-1",
+		AgentName: "deps",
+		StartTime: start,
+		Status:    "completed",
+		Events: []LogEvent{
+			{Time: start, Phase: PhaseStep, Name: "update", Status: "completed", DurationMS: 1500},
+			{Time: start.Add(2 * time.Second), Phase: PhaseGit, Name: "push", Status: "completed", Details: map[string]string{"branch": "automated/deps"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveLog() error = %v", err)
+	}
+	if rel != filepath.Join(LogDirName, "run-1.json") {
+		t.Errorf("SaveLog() path = %q", rel)
+	}
+
+	record := &ExecutionRecord{ID: "run-1", Log: rel}
+	log, err := h.LoadLog(record)
+	if err != nil {
+		t.Fatalf("LoadLog() error = %v", err)
+	}
+	if len(log.Events) != 2 || log.Events[1].Details["branch"] != "automated/deps" || log.Events[0].DurationMS != 1500 {
+		t.Errorf("LoadLog() events = %+v", log.Events)
+	}
+	if _, err := h.LoadLog(&ExecutionRecord{ID: "run-2"}); err == nil {
+		t.Error("expected error for a record without log")
+	}
+
+	t.Run("trimming prunes unlinked logs", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			h.Records = append(h.Records, ExecutionRecord{ID: "old", Log: rel})
+		}
+		if _, err := h.SaveLog(&ExecutionLog{ID: "orphan"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Record(makeRecord("agent", "completed", 100)); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := h.LoadLog(record); err != nil {
+			t.Errorf("linked log removed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, LogDirName, "orphan.json")); !os.IsNotExist(err) {
+			t.Errorf("unlinked log kept: %v", err)
+		}
+	})
+
+	t.Run("clear removes logs", func(t *testing.T) {
+		if err := h.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, LogDirName)); !os.IsNotExist(err) {
+			t.Errorf("log directory still exists: %v", err)
+		}
+	})
+}
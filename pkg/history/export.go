@@ -0,0 +1,92 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	ExportCSV  = "csv"
+	ExportJSON = "json"
+)
+
+// exportColumns is the CSV header; each row holds one execution
+var exportColumns = []string{
+	"id", "agent_name", "status", "trigger", "worktree", "start_time", "end_time",
+	"duration_ms", "steps_executed", "steps_failed", "error", "commits", "pr_url", "task_hash",
+}
+
+// Since returns the records matching the filters that started at or after
+// since (zero for all), oldest first
+func (h *History) Since(agentName, status string, since time.Time) []ExecutionRecord {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var records []ExecutionRecord
+	for _, record := range h.Records {
+		if agentName != "" && record.AgentName != agentName {
+			continue
+		}
+		if status != "" && record.Status != status {
+			continue
+		}
+		if record.StartTime.Before(since) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Export writes records as CSV (one row per execution, for spreadsheets and
+// dashboards) or as a JSON array of the full records, steps included
+func Export(w io.Writer, format string, records []ExecutionRecord) error {
+	switch format {
+	case ExportJSON:
+		if records == nil {
+			records = []ExecutionRecord{}
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal history: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case ExportCSV:
+		return exportCSV(w, records)
+	default:
+		return fmt.Errorf("unknown export format '%s' (expected csv or json)", format)
+	}
+}
+
+func exportCSV(w io.Writer, records []ExecutionRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, r := range records {
+		failed := 0
+		for _, step := range r.Steps {
+			if step.Status != StepCompleted {
+				failed++
+			}
+		}
+		row := []string{
+			r.ID, r.AgentName, r.Status, r.Trigger, r.Worktree,
+			r.StartTime.Format(time.RFC3339), r.EndTime.Format(time.RFC3339),
+			strconv.FormatInt(r.Duration, 10), strconv.Itoa(r.StepsExecuted), strconv.Itoa(failed),
+			r.Error, strings.Join(r.Commits, " "), r.PRUrl, r.TaskHash,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
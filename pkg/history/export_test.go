@@ -0,0 +1,63 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSince(t *testing.T) {
+	h := &History{}
+	old := makeRecord("deps", "completed", 100)
+	old.StartTime = time.Now().Add(-72 * time.Hour)
+	h.Records = []ExecutionRecord{old, makeRecord("deps", "failed", 100), makeRecord("audit", "completed", 100)}
+
+	records := h.Since("", "", time.Now().Add(-24*time.Hour))
+	if len(records) != 2 || records[0].Status != "failed" || records[1].AgentName != "audit" {
+		t.Errorf("Since(24h) = %+v, want the two recent records oldest first", records)
+	}
+	if records := h.Since("deps", "", time.Time{}); len(records) != 2 {
+		t.Errorf("Since(deps) returned %d records, want 2", len(records))
+	}
+	if records := h.Since("", "completed", time.Time{}); len(records) != 2 {
+		t.Errorf("Since(completed) returned %d records, want 2", len(records))
+	}
+}
+
+func TestExport(t *testing.T) {
+	record := makeRecord("deps", "failed", 1500)
+	record.Error = "step 'Test' failed: exit status 1, retried"
+	record.Commits = []string{"abc123", "def456"}
+	record.Steps = []StepResult{{Name: "Build", Status: StepCompleted}, {Name: "Test", Status: StepFailed}}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, ExportCSV, []ExecutionRecord{record}); err != nil {
+		t.Fatalf("Export(csv) error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,agent_name,status,") {
+		t.Fatalf("Export(csv) = %q, want a header and one row", buf.String())
+	}
+	if !strings.Contains(lines[1], `,1500,0,1,"step 'Test' failed: exit status 1, retried",abc123 def456,`) {
+		t.Errorf("Export(csv) row = %q", lines[1])
+	}
+
+	buf.Reset()
+	if err := Export(&buf, ExportJSON, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Export(json, none) = %q, %v, want []", buf.String(), err)
+	}
+	buf.Reset()
+	if err := Export(&buf, ExportJSON, []ExecutionRecord{record}); err != nil {
+		t.Fatalf("Export(json) error = %v", err)
+	}
+	var decoded []ExecutionRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded) != 1 || len(decoded[0].Steps) != 2 {
+		t.Errorf("Export(json) = %s (%v), want the record with its steps", buf.String(), err)
+	}
+
+	if err := Export(&buf, "xml", nil); err == nil {
+		t.Error("Export(xml) succeeded, want an unknown format error")
+	}
+}
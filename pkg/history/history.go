@@ -1,34 +1,91 @@
 package history
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/braunmar/worktree/pkg/storage"
 )
 
 // ExecutionRecord represents a single agent execution
 type ExecutionRecord struct {
-	ID            string    `json:"id"`
-	AgentName     string    `json:"agent_name"`
-	Worktree      string    `json:"worktree"`
-	Status        string    `json:"status"` // "completed", "failed"
-	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
-	Duration      int64     `json:"duration_ms"`
-	Error         string    `json:"error,omitempty"`
-	StepsExecuted int       `json:"steps_executed,omitempty"`
-	Commits       []string  `json:"commits,omitempty"`
-	PRUrl         string    `json:"pr_url,omitempty"`
+	ID            string       `json:"id"`
+	AgentName     string       `json:"agent_name"`
+	Worktree      string       `json:"worktree"`
+	Status        string       `json:"status"` // "completed", "failed" or "skipped"
+	StartTime     time.Time    `json:"start_time"`
+	EndTime       time.Time    `json:"end_time"`
+	Duration      int64        `json:"duration_ms"`
+	Error         string       `json:"error,omitempty"`
+	StepsExecuted int          `json:"steps_executed,omitempty"`
+	Commits       []string     `json:"commits,omitempty"`
+	PRUrl         string       `json:"pr_url,omitempty"`
+	TaskHash      string       `json:"task_hash,omitempty"` // Task definition as executed (see SaveTaskSnapshot)
+	Steps         []StepResult `json:"steps,omitempty"`     // Outcome of each step that ran, in order
+	Trigger       string       `json:"trigger,omitempty"`   // What started the run: "manual", "queue" or "schedule"
+	Log           string       `json:"log,omitempty"`       // Structured execution log, relative to the worktrees directory (see SaveLog)
+}
+
+// RunSkipped is the status of a run that did not start because the previous
+// run of the task was still in progress or a concurrency limit was reached.
+// Skipped runs do not count as executions in Stats.
+const RunSkipped = "skipped"
+
+// Run triggers
+const (
+	TriggerManual   = "manual"
+	TriggerQueue    = "queue"
+	TriggerSchedule = "schedule"
+)
+
+// Step statuses
+const (
+	StepCompleted = "completed"
+	StepFailed    = "failed"
+	StepTimedOut  = "timed out"
+)
+
+// StepResult is the outcome of one step of an execution
+type StepResult struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Status     string `json:"status"` // "completed", "failed" or "timed out"
+	Attempts   int    `json:"attempts"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+	Continued  bool   `json:"continued,omitempty"` // Failed with continue_on_error, the run went on
+	Output     string `json:"output,omitempty"`    // Tail of the step's command output
+}
+
+// DefaultMaxRecords is how many records Record keeps without a retention policy
+const DefaultMaxRecords = 1000
+
+// Retention bounds the records Record keeps
+type Retention struct {
+	MaxRecords int           // Records kept, oldest dropped first (0: DefaultMaxRecords)
+	MaxAge     time.Duration // Records that started longer ago are dropped (0: no limit)
+}
+
+// maxRecords returns how many records the policy keeps
+func (r Retention) maxRecords() int {
+	if r.MaxRecords <= 0 {
+		return DefaultMaxRecords
+	}
+	return r.MaxRecords
 }
 
 // History manages execution history
 type History struct {
-	Records []ExecutionRecord `json:"records"`
-	mu      sync.RWMutex
-	path    string
+	Records   []ExecutionRecord `json:"records"`
+	mu        sync.RWMutex
+	path      string  // .history.json; its directory also holds snapshots and logs
+	db        *sql.DB // Set with the SQLite backend, which replaces the file
+	retention Retention
 }
 
 // AgentStats contains statistics for a specific agent
@@ -48,8 +105,10 @@ type HistoryStats struct {
 	ByAgent         map[string]AgentStats
 }
 
-// Load loads history from worktrees/.history.json
-func Load(worktreeDir string) (*History, error) {
+// Load loads history from worktrees/.history.json or, when backend is
+// storage.BackendSQLite, from the worktrees database (migrating the file into
+// it on first use). An empty backend means the JSON file.
+func Load(worktreeDir string, backend string) (*History, error) {
 	historyPath := filepath.Join(worktreeDir, ".history.json")
 
 	h := &History{
@@ -57,6 +116,13 @@ func Load(worktreeDir string) (*History, error) {
 		path:    historyPath,
 	}
 
+	if backend == storage.BackendSQLite {
+		if err := h.loadSQLite(worktreeDir); err != nil {
+			return nil, err
+		}
+		return h, nil
+	}
+
 	// If file doesn't exist, return empty history
 	if _, err := os.Stat(historyPath); os.IsNotExist(err) {
 		return h, nil
@@ -89,16 +155,54 @@ func (h *History) Record(record ExecutionRecord) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if h.db != nil {
+		return h.recordSQLite(record)
+	}
+
 	h.Records = append(h.Records, record)
 
-	// Keep only last 1000 records to prevent unbounded growth
-	if len(h.Records) > 1000 {
-		h.Records = h.Records[len(h.Records)-1000:]
+	// Apply the retention policy to prevent unbounded growth
+	if h.applyRetentionUnlocked(time.Now()) {
+		h.pruneSnapshotsUnlocked()
+		h.pruneLogsUnlocked()
 	}
 
 	return h.saveUnlocked()
 }
 
+// SetRetention sets the retention policy Record enforces
+func (h *History) SetRetention(r Retention) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.retention = r
+}
+
+// applyRetentionUnlocked drops records older than MaxAge and all but the
+// newest MaxRecords, reporting whether any were dropped (caller holds the lock)
+func (h *History) applyRetentionUnlocked(now time.Time) bool {
+	kept := h.Records
+	if h.retention.MaxAge > 0 {
+		cutoff := now.Add(-h.retention.MaxAge)
+		kept = kept[:0:0]
+		for _, record := range h.Records {
+			if !record.StartTime.Before(cutoff) {
+				kept = append(kept, record)
+			}
+		}
+	}
+
+	if maxRecords := h.retention.maxRecords(); len(kept) > maxRecords {
+		kept = kept[len(kept)-maxRecords:]
+	}
+
+	if len(kept) == len(h.Records) {
+		return false
+	}
+	h.Records = kept
+	return true
+}
+
 // Query filters records
 func (h *History) Query(agentName string, status string, limit int) []ExecutionRecord {
 	h.mu.RLock()
@@ -143,8 +247,13 @@ func (h *History) Stats() HistoryStats {
 	}
 
 	// Aggregate by agent
+	var executions []ExecutionRecord
 	agentRecords := make(map[string][]ExecutionRecord)
 	for _, record := range h.Records {
+		if record.Status == RunSkipped {
+			continue
+		}
+		executions = append(executions, record)
 		agentRecords[record.AgentName] = append(agentRecords[record.AgentName], record)
 	}
 
@@ -152,7 +261,7 @@ func (h *History) Stats() HistoryStats {
 	var totalDuration int64
 	successCount := 0
 
-	for _, record := range h.Records {
+	for _, record := range executions {
 		stats.TotalExecutions++
 		totalDuration += record.Duration
 
@@ -195,6 +304,10 @@ func (h *History) Stats() HistoryStats {
 
 // saveUnlocked saves without locking (assumes caller has lock)
 func (h *History) saveUnlocked() error {
+	if h.db != nil {
+		return h.replaceSQLite()
+	}
+
 	// Marshal to JSON
 	data, err := json.MarshalIndent(h, "", "  ")
 	if err != nil {
@@ -221,5 +334,15 @@ func (h *History) Clear() error {
 	defer h.mu.Unlock()
 
 	h.Records = []ExecutionRecord{}
-	return h.saveUnlocked()
+	if err := h.saveUnlocked(); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(h.snapshotDir()); err != nil {
+		return fmt.Errorf("failed to remove task snapshots: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(filepath.Dir(h.path), LogDirName)); err != nil {
+		return fmt.Errorf("failed to remove execution logs: %w", err)
+	}
+	return nil
 }
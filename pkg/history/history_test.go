@@ -23,7 +23,7 @@ func makeRecord(agent, status string, durationMs int64) ExecutionRecord {
 
 func TestLoadEmpty(t *testing.T) {
 	dir := t.TempDir()
-	h, err := Load(dir)
+	h, err := Load(dir, "")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
@@ -34,7 +34,7 @@ func TestLoadEmpty(t *testing.T) {
 
 func TestLoadAndSave(t *testing.T) {
 	dir := t.TempDir()
-	h, err := Load(dir)
+	h, err := Load(dir, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -48,7 +48,7 @@ func TestLoadAndSave(t *testing.T) {
 	}
 
 	// Reload from disk
-	h2, err := Load(dir)
+	h2, err := Load(dir, "")
 	if err != nil {
 		t.Fatalf("Load() after save error = %v", err)
 	}
@@ -62,7 +62,7 @@ func TestLoadAndSave(t *testing.T) {
 
 func TestRecord(t *testing.T) {
 	dir := t.TempDir()
-	h, err := Load(dir)
+	h, err := Load(dir, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -91,6 +91,20 @@ func TestRecord(t *testing.T) {
 			t.Errorf("expected 1000 records after limit enforcement, got %d", len(h.Records))
 		}
 	})
+
+	t.Run("enforces the retention policy", func(t *testing.T) {
+		old := makeRecord("old", "completed", 100)
+		old.StartTime = time.Now().Add(-48 * time.Hour)
+		h.Records = []ExecutionRecord{old, makeRecord("a", "completed", 100), makeRecord("b", "completed", 100)}
+		h.SetRetention(Retention{MaxRecords: 2, MaxAge: 24 * time.Hour})
+
+		if err := h.Record(makeRecord("c", "completed", 100)); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+		if len(h.Records) != 2 || h.Records[0].AgentName != "b" || h.Records[1].AgentName != "c" {
+			t.Errorf("records = %+v, want b and c", h.Records)
+		}
+	})
 }
 
 func TestQuery(t *testing.T) {
@@ -188,6 +202,22 @@ func TestStats(t *testing.T) {
 		}
 	})
 
+	t.Run("skipped runs are not executions", func(t *testing.T) {
+		h := &History{
+			Records: []ExecutionRecord{
+				makeRecord("agent-a", "completed", 1000),
+				makeRecord("agent-a", RunSkipped, 0),
+			},
+		}
+		stats := h.Stats()
+		if stats.TotalExecutions != 1 || stats.SuccessRate != 100.0 {
+			t.Errorf("stats = %d executions, %f%% success; want 1, 100%%", stats.TotalExecutions, stats.SuccessRate)
+		}
+		if agent := stats.ByAgent["agent-a"]; agent.TotalExecutions != 1 || agent.FailureCount != 0 {
+			t.Errorf("agent-a stats = %+v, want 1 execution and no failures", agent)
+		}
+	})
+
 	t.Run("all completed gives 100% success rate", func(t *testing.T) {
 		h := &History{
 			Records: []ExecutionRecord{
@@ -298,7 +328,7 @@ func TestClear(t *testing.T) {
 	}
 
 	// Verify persisted
-	h2, err := Load(dir)
+	h2, err := Load(dir, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -314,7 +344,7 @@ func TestLoadInvalidJSON(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, err := Load(dir)
+	_, err := Load(dir, "")
 	if err == nil {
 		t.Error("expected error for invalid JSON history file")
 	}
@@ -0,0 +1,111 @@
+package history
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapshotDirName holds one file per distinct task definition, named by content hash
+const snapshotDirName = ".history-tasks"
+
+// SaveTaskSnapshot stores a task definition (its YAML) and returns the content
+// hash to keep in ExecutionRecord.TaskHash. Identical definitions are stored once.
+func (h *History) SaveTaskSnapshot(definition []byte) (string, error) {
+	sum := sha256.Sum256(definition)
+	hash := hex.EncodeToString(sum[:])
+
+	path := h.snapshotPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, definition, 0644); err != nil {
+		return "", fmt.Errorf("failed to write task snapshot: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("failed to write task snapshot: %w", err)
+	}
+
+	return hash, nil
+}
+
+// TaskSnapshot returns the task definition stored under hash
+func (h *History) TaskSnapshot(hash string) ([]byte, error) {
+	data, err := os.ReadFile(h.snapshotPath(hash))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("task snapshot %s not found", shortHash(hash))
+		}
+		return nil, fmt.Errorf("failed to read task snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Find returns the record whose ID starts with prefix
+func (h *History) Find(prefix string) (*ExecutionRecord, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var found *ExecutionRecord
+	for i := range h.Records {
+		if !strings.HasPrefix(h.Records[i].ID, prefix) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("execution ID '%s' is ambiguous", prefix)
+		}
+		found = &h.Records[i]
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("no execution with ID '%s'", prefix)
+	}
+	record := *found
+	return &record, nil
+}
+
+// pruneSnapshotsUnlocked removes snapshots no record refers to (caller holds the lock)
+func (h *History) pruneSnapshotsUnlocked() {
+	referenced := make(map[string]bool)
+	for _, record := range h.Records {
+		if record.TaskHash != "" {
+			referenced[record.TaskHash] = true
+		}
+	}
+
+	entries, err := os.ReadDir(h.snapshotDir())
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if hash, ok := strings.CutSuffix(entry.Name(), ".yml"); ok && !referenced[hash] {
+			os.Remove(filepath.Join(h.snapshotDir(), entry.Name()))
+		}
+	}
+}
+
+func (h *History) snapshotDir() string {
+	return filepath.Join(filepath.Dir(h.path), snapshotDirName)
+}
+
+func (h *History) snapshotPath(hash string) string {
+	return filepath.Join(h.snapshotDir(), hash+".yml")
+}
+
+// shortHash abbreviates a snapshot hash for display
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,95 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTaskSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	h, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v1, err := h.SaveTaskSnapshot([]byte("steps: [a]\n"))
+	if err != nil {
+		t.Fatalf("SaveTaskSnapshot() error = %v", err)
+	}
+	again, _ := h.SaveTaskSnapshot([]byte("steps: [a]\n"))
+	v2, _ := h.SaveTaskSnapshot([]byte("steps: [a, b]\n"))
+
+	if v1 != again {
+		t.Errorf("identical definitions got different hashes %s and %s", v1, again)
+	}
+	if v1 == v2 {
+		t.Error("different definitions got the same hash")
+	}
+
+	data, err := h.TaskSnapshot(v1)
+	if err != nil || string(data) != "steps: [a]\n" {
+		t.Errorf("TaskSnapshot(v1) = %q, %v", data, err)
+	}
+	if _, err := h.TaskSnapshot("0000"); err == nil {
+		t.Error("expected error for unknown snapshot")
+	}
+
+	t.Run("trimming prunes unreferenced snapshots", func(t *testing.T) {
+		for i := 0; i < 1000; i++ {
+			h.Records = append(h.Records, ExecutionRecord{ID: "old", TaskHash: v1})
+		}
+		rec := makeRecord("agent", "completed", 100)
+		rec.TaskHash = v2
+		if err := h.Record(rec); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := h.TaskSnapshot(v2); err != nil {
+			t.Errorf("referenced snapshot removed: %v", err)
+		}
+		// 999 records still reference v1
+		if _, err := h.TaskSnapshot(v1); err != nil {
+			t.Errorf("referenced snapshot removed: %v", err)
+		}
+	})
+
+	t.Run("clear removes snapshots", func(t *testing.T) {
+		if err := h.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, snapshotDirName)); !os.IsNotExist(err) {
+			t.Errorf("snapshot directory still exists: %v", err)
+		}
+	})
+}
+
+func TestFind(t *testing.T) {
+	h := &History{Records: []ExecutionRecord{
+		{ID: "abc123", AgentName: "a"},
+		{ID: "abd456", AgentName: "b"},
+	}}
+
+	tests := []struct {
+		prefix    string
+		wantAgent string
+		wantErr   bool
+	}{
+		{"abc", "a", false},
+		{"abd456", "b", false},
+		{"ab", "", true},
+		{"zzz", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			record, err := h.Find(tt.prefix)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Find(%q) error = %v, wantErr %v", tt.prefix, err, tt.wantErr)
+			}
+			if err == nil && record.AgentName != tt.wantAgent {
+				t.Errorf("Find(%q) = %s, want %s", tt.prefix, record.AgentName, tt.wantAgent)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package history
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/storage"
+)
+
+// historySchema keeps each record as JSON, next to the columns worth
+// filtering on with the sqlite3 shell
+const historySchema = `
+CREATE TABLE IF NOT EXISTS history (
+	seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+	id         TEXT NOT NULL UNIQUE,
+	agent_name TEXT NOT NULL,
+	worktree   TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	start_time INTEGER NOT NULL, -- Unix nanoseconds
+	record     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS history_agent_status ON history (agent_name, status);
+CREATE INDEX IF NOT EXISTS history_start_time ON history (start_time);
+`
+
+// loadSQLite reads the records from the database of worktreeDir, importing
+// .history.json first if it still exists
+func (h *History) loadSQLite(worktreeDir string) error {
+	db, err := storage.Open(worktreeDir)
+	if err != nil {
+		return err
+	}
+	if err := storage.Init(db, historySchema, h.path, importHistory); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+
+	h.db = db
+	h.Records, err = selectRecords(db)
+	return err
+}
+
+// importHistory inserts the records of a .history.json file
+func importHistory(tx *sql.Tx, data []byte) error {
+	var file History
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse history file: %w", err)
+	}
+	for _, record := range file.Records {
+		if err := insertRecord(tx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectRecords returns all records, oldest first
+func selectRecords(db *sql.DB) ([]ExecutionRecord, error) {
+	rows, err := db.Query(`SELECT record FROM history ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer rows.Close()
+
+	records := []ExecutionRecord{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		var record ExecutionRecord
+		if err := json.Unmarshal([]byte(data), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse history record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return records, nil
+}
+
+// insertRecord adds a record unless one with its ID exists
+func insertRecord(tx *sql.Tx, record ExecutionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+	_, err = tx.Exec(`INSERT OR IGNORE INTO history (id, agent_name, worktree, status, start_time, record) VALUES (?, ?, ?, ?, ?, ?)`,
+		record.ID, record.AgentName, record.Worktree, record.Status, record.StartTime.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to insert history record: %w", err)
+	}
+	return nil
+}
+
+// recordSQLite inserts record and applies the retention policy in the
+// database, so runs other processes recorded since Load count as well
+// (caller holds the lock)
+func (h *History) recordSQLite(record ExecutionRecord) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to record execution: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertRecord(tx, record); err != nil {
+		return err
+	}
+	dropped, err := h.retainSQLite(tx, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record execution: %w", err)
+	}
+
+	if dropped == 0 {
+		h.Records = append(h.Records, record)
+		return nil
+	}
+
+	// Reload before pruning, so snapshots and logs of records other
+	// processes added are kept
+	if h.Records, err = selectRecords(h.db); err != nil {
+		return err
+	}
+	h.pruneSnapshotsUnlocked()
+	h.pruneLogsUnlocked()
+	return nil
+}
+
+// retainSQLite deletes records older than MaxAge and all but the newest
+// MaxRecords, returning how many it deleted
+func (h *History) retainSQLite(tx *sql.Tx, now time.Time) (int64, error) {
+	var dropped int64
+	deleteRecords := func(query string, arg any) error {
+		result, err := tx.Exec(query, arg)
+		if err != nil {
+			return fmt.Errorf("failed to apply history retention: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		dropped += n
+		return nil
+	}
+
+	if h.retention.MaxAge > 0 {
+		if err := deleteRecords(`DELETE FROM history WHERE start_time < ?`, now.Add(-h.retention.MaxAge).UnixNano()); err != nil {
+			return 0, err
+		}
+	}
+	if err := deleteRecords(`DELETE FROM history WHERE seq NOT IN (SELECT seq FROM history ORDER BY seq DESC LIMIT ?)`, h.retention.maxRecords()); err != nil {
+		return 0, err
+	}
+	return dropped, nil
+}
+
+// replaceSQLite overwrites the stored records with h.Records (caller holds the lock)
+func (h *History) replaceSQLite() error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM history`); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	for _, record := range h.Records {
+		if err := insertRecord(tx, record); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/storage"
+)
+
+func TestSQLiteBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	// Records written to the JSON file are migrated on first use
+	h, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := makeRecord("npm-audit", "completed", 1000)
+	first.ID = "first"
+	if err := h.Record(first); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(h.Records) != 1 || h.Records[0].ID != "first" || h.Records[0].AgentName != "npm-audit" {
+		t.Fatalf("migrated records = %+v, want the JSON record", h.Records)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".history.json")); !os.IsNotExist(err) {
+		t.Errorf(".history.json still exists after migration (err = %v)", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".history.json"+storage.MigratedSuffix)); err != nil {
+		t.Errorf("migrated JSON file not kept: %v", err)
+	}
+
+	// Records added by another instance survive, order is kept
+	other, err := Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := makeRecord("go-deps", "failed", 2000)
+	second.ID = "second"
+	if err := other.Record(second); err != nil {
+		t.Fatal(err)
+	}
+	third := makeRecord("npm-audit", "completed", 3000)
+	third.ID = "third"
+	third.Steps = []StepResult{{Name: "audit", Type: "shell", Status: StepCompleted, Output: "ok"}}
+	if err := h.Record(third); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, record := range reloaded.Records {
+		ids = append(ids, record.ID)
+	}
+	if len(ids) != 3 || ids[0] != "first" || ids[1] != "second" || ids[2] != "third" {
+		t.Fatalf("record IDs = %v, want [first second third]", ids)
+	}
+	if steps := reloaded.Records[2].Steps; len(steps) != 1 || steps[0].Output != "ok" {
+		t.Errorf("steps = %+v, want the recorded step", steps)
+	}
+
+	t.Run("retention", func(t *testing.T) {
+		reloaded.SetRetention(Retention{MaxRecords: 2, MaxAge: time.Hour})
+		old := makeRecord("old", "completed", 1000)
+		old.ID = "old"
+		old.StartTime = time.Now().Add(-2 * time.Hour)
+		if err := reloaded.Record(old); err != nil {
+			t.Fatal(err)
+		}
+		newest := makeRecord("newest", "completed", 1000)
+		newest.ID = "newest"
+		if err := reloaded.Record(newest); err != nil {
+			t.Fatal(err)
+		}
+
+		h, err := Load(dir, storage.BackendSQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(h.Records) != 2 || h.Records[0].ID != "third" || h.Records[1].ID != "newest" {
+			t.Errorf("records after retention = %+v, want third and newest", h.Records)
+		}
+		if len(reloaded.Records) != 2 {
+			t.Errorf("in-memory records = %d, want 2", len(reloaded.Records))
+		}
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		if err := reloaded.Clear(); err != nil {
+			t.Fatal(err)
+		}
+		h, err := Load(dir, storage.BackendSQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(h.Records) != 0 {
+			t.Errorf("records after Clear() = %d, want 0", len(h.Records))
+		}
+	})
+}
@@ -0,0 +1,43 @@
+// Package parallel runs independent per-project jobs on a bounded number of
+// goroutines while reporting their results in the original order, so output
+// reads the same as a serial run.
+package parallel
+
+// Run calls job for every index in [0, n) with at most limit jobs running or
+// waiting to be reported at once (1 or less runs them one at a time). report
+// is called on the calling goroutine with each result in index order, as soon
+// as that job and all earlier ones have finished. When report returns false
+// no further jobs are started; jobs already started are still reported.
+func Run[T any](n, limit int, job func(i int) T, report func(i int, result T) bool) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	results := make([]T, n)
+	done := make([]chan struct{}, n)
+	next := 0
+	start := func() {
+		i := next
+		next++
+		done[i] = make(chan struct{})
+		go func() {
+			results[i] = job(i)
+			close(done[i])
+		}()
+	}
+
+	for next < n && next < limit {
+		start()
+	}
+
+	stopped := false
+	for i := 0; i < next; i++ {
+		<-done[i]
+		if !report(i, results[i]) {
+			stopped = true
+		}
+		if !stopped && next < n {
+			start()
+		}
+	}
+}
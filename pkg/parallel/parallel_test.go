@@ -0,0 +1,79 @@
+package parallel
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunReportsInOrder(t *testing.T) {
+	var reported []int
+	Run(5, 3, func(i int) int {
+		// Later jobs finish first
+		time.Sleep(time.Duration(5-i) * time.Millisecond)
+		return i * 10
+	}, func(i int, result int) bool {
+		if result != i*10 {
+			t.Errorf("result %d = %d, want %d", i, result, i*10)
+		}
+		reported = append(reported, i)
+		return true
+	})
+
+	if len(reported) != 5 {
+		t.Fatalf("reported %v, want all 5 jobs", reported)
+	}
+	for i, got := range reported {
+		if got != i {
+			t.Errorf("reported %v, want index order", reported)
+			break
+		}
+	}
+}
+
+func TestRunBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	Run(10, 3, func(i int) struct{} {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		running.Add(-1)
+		return struct{}{}
+	}, func(int, struct{}) bool { return true })
+
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+}
+
+func TestRunStop(t *testing.T) {
+	var ran atomic.Int32
+	var reported []int
+	Run(5, 1, func(i int) int {
+		ran.Add(1)
+		return i
+	}, func(i int, _ int) bool {
+		reported = append(reported, i)
+		return i != 1
+	})
+
+	// One at a time: nothing after the stopping job is started
+	if ran.Load() != 2 || len(reported) != 2 {
+		t.Errorf("ran %d jobs, reported %v; want 2 and [0 1]", ran.Load(), reported)
+	}
+
+	// Jobs already running when report stops are still reported
+	reported = nil
+	Run(5, 3, func(i int) int { return i }, func(i int, _ int) bool {
+		reported = append(reported, i)
+		return false
+	})
+	if len(reported) != 3 {
+		t.Errorf("reported %v, want the 3 started jobs", reported)
+	}
+}
@@ -4,8 +4,10 @@ package process
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strconv"
 	"syscall"
 	"time"
@@ -38,10 +40,65 @@ func StartBackground(name, command, dir string, env []string, pidFile string) er
 	return nil
 }
 
+// RunWithTimeout runs a shell command in the foreground, writing its output to
+// out, and waits for it. With a timeout, the command gets its own process
+// group, which receives SIGTERM (then SIGKILL after 5 seconds) once the timeout
+// passes; interrupts are passed on to it. A timeout of 0 means no limit.
+func RunWithTimeout(command, dir string, env []string, out io.Writer, timeout time.Duration) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.WaitDelay = OutputWaitDelay
+	return RunCommand(cmd, timeout)
+}
+
+// RunCommand runs a prepared command and waits for it, with the timeout
+// handling of RunWithTimeout. A timeout of 0 means no limit.
+func RunCommand(cmd *exec.Cmd, timeout time.Duration) error {
+	if timeout <= 0 {
+		return ignoreWaitDelay(cmd.Run())
+	}
+
+	// Its own group lets the timeout kill everything the command started, but
+	// takes it out of the terminal's foreground group, so forward interrupts
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pgid := -cmd.Process.Pid
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	done := make(chan error, 1)
+	go func() { done <- ignoreWaitDelay(cmd.Wait()) }()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case sig := <-sigChan:
+			_ = syscall.Kill(pgid, sig.(syscall.Signal))
+		case <-timer.C:
+			_ = syscall.Kill(pgid, syscall.SIGTERM)
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				_ = syscall.Kill(pgid, syscall.SIGKILL)
+				<-done
+			}
+			return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+	}
+}
+
 // StopProcess sends SIGTERM to the process group, waits up to 5 seconds,
 // then sends SIGKILL if the process is still running. Removes the PID file.
 func StopProcess(pidFile string) error {
-	pid, err := readPID(pidFile)
+	pid, err := ReadPID(pidFile)
 	if err != nil {
 		return err
 	}
@@ -74,13 +131,18 @@ func StopProcess(pidFile string) error {
 
 // IsRunning reports whether the process recorded in pidFile is still alive.
 func IsRunning(pidFile string) bool {
-	pid, err := readPID(pidFile)
+	pid, err := ReadPID(pidFile)
 	if err != nil {
 		return false
 	}
 	return isAlive(pid)
 }
 
+// Alive reports whether a process with the given PID is running.
+func Alive(pid int) bool {
+	return isAlive(pid)
+}
+
 // isAlive checks whether a process with the given PID is running by sending
 // signal 0 (a no-op that still returns ESRCH if the process doesn't exist).
 func isAlive(pid int) bool {
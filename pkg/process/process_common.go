@@ -1,14 +1,35 @@
 package process
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// readPID reads a PID from a file created by StartBackground.
-func readPID(pidFile string) (int, error) {
+// ErrTimeout is returned by RunWithTimeout when the command was killed for
+// running too long
+var ErrTimeout = errors.New("timed out")
+
+// OutputWaitDelay is how long RunWithTimeout keeps copying output after the
+// command exits, so a background process it left holding the output cannot
+// block it
+const OutputWaitDelay = 2 * time.Second
+
+// ignoreWaitDelay treats a command that exited cleanly but left its output open
+// past OutputWaitDelay as successful
+func ignoreWaitDelay(err error) error {
+	if errors.Is(err, exec.ErrWaitDelay) {
+		return nil
+	}
+	return err
+}
+
+// ReadPID reads the PID of the process group leader from a file created by StartBackground.
+func ReadPID(pidFile string) (int, error) {
 	data, err := os.ReadFile(pidFile)
 	if err != nil {
 		return 0, fmt.Errorf("PID file not found: %w", err)
@@ -4,6 +4,7 @@ package process
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strconv"
@@ -33,9 +34,44 @@ func StartBackground(name, command, dir string, env []string, pidFile string) er
 	return nil
 }
 
+// RunWithTimeout runs a command in the foreground, writing its output to out,
+// and waits for it, killing it once the timeout passes. A timeout of 0 means
+// no limit. Processes the command started may outlive it.
+func RunWithTimeout(command, dir string, env []string, out io.Writer, timeout time.Duration) error {
+	cmd := exec.Command("cmd", "/C", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.WaitDelay = OutputWaitDelay
+	return RunCommand(cmd, timeout)
+}
+
+// RunCommand runs a prepared command and waits for it, killing it once the
+// timeout passes. A timeout of 0 means no limit.
+func RunCommand(cmd *exec.Cmd, timeout time.Duration) error {
+	if timeout <= 0 {
+		return ignoreWaitDelay(cmd.Run())
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- ignoreWaitDelay(cmd.Wait()) }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		_ = cmd.Process.Kill()
+		<-done
+		return fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+}
+
 // StopProcess kills the process recorded in pidFile and removes the file.
 func StopProcess(pidFile string) error {
-	pid, err := readPID(pidFile)
+	pid, err := ReadPID(pidFile)
 	if err != nil {
 		return err
 	}
@@ -58,18 +94,18 @@ func StopProcess(pidFile string) error {
 
 // IsRunning reports whether the process recorded in pidFile is still alive.
 func IsRunning(pidFile string) bool {
-	pid, err := readPID(pidFile)
-	if err != nil {
-		return false
-	}
-	proc, err := os.FindProcess(pid)
+	pid, err := ReadPID(pidFile)
 	if err != nil {
 		return false
 	}
+	return Alive(pid)
+}
+
+// Alive reports whether a process with the given PID is running.
+func Alive(pid int) bool {
 	// On Windows FindProcess always succeeds; use tasklist to verify.
 	out, err := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/NH").Output()
 	if err != nil {
-		_ = proc
 		return false
 	}
 	return len(out) > 0 && string(out) != "INFO: No tasks are running which match the specified criteria.\r\n"
@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogDir holds the output of queued runs, relative to the worktrees directory
+const LogDir = ".queue-logs"
+
+// LogPath returns the file the output of a queued task's run is written to
+func LogPath(worktreeDir, taskID string) string {
+	return filepath.Join(worktreeDir, LogDir, taskID+".log")
+}
+
+// FollowLog copies the log at path to w and keeps copying what is appended to
+// it, checking every interval, until running reports false. The log is read
+// to its end once more after that, so no output is lost. A log that does not
+// exist yet is waited for while the task runs.
+func FollowLog(path string, w io.Writer, interval time.Duration, running func() bool) error {
+	var f *os.File
+	for f == nil {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return err
+			}
+			if !running() {
+				return nil
+			}
+			time.Sleep(interval)
+		}
+	}
+	defer f.Close()
+
+	for {
+		if _, err := io.Copy(w, f); err != nil {
+			return err
+		}
+		if !running() {
+			_, err := io.Copy(w, f)
+			return err
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,54 @@
+package queue
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollowLog(t *testing.T) {
+	path := LogPath(t.TempDir(), "abc")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The log appears and grows while the task runs; the last line is written
+	// just before the task stops running
+	polls := 0
+	running := func() bool {
+		polls++
+		switch polls {
+		case 2:
+			if err := os.WriteFile(path, []byte("first\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		case 4:
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString("last\n")
+			f.Close()
+			return false
+		}
+		return true
+	}
+
+	var out bytes.Buffer
+	if err := FollowLog(path, &out, time.Millisecond, running); err != nil {
+		t.Fatalf("FollowLog() error = %v", err)
+	}
+	if out.String() != "first\nlast\n" {
+		t.Errorf("FollowLog() copied %q, want %q", out.String(), "first\nlast\n")
+	}
+}
+
+func TestFollowLogMissing(t *testing.T) {
+	var out bytes.Buffer
+	err := FollowLog(LogPath(t.TempDir(), "abc"), &out, time.Millisecond, func() bool { return false })
+	if err != nil || out.Len() != 0 {
+		t.Errorf("FollowLog() = %q, %v; want no output and no error", out.String(), err)
+	}
+}
@@ -1,13 +1,17 @@
 package queue
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/braunmar/worktree/pkg/storage"
+
 	"github.com/google/uuid"
 )
 
@@ -31,6 +35,7 @@ type QueuedTask struct {
 	CompletedAt *time.Time `json:"completed_at,omitempty"` // When execution completed
 	Error       string     `json:"error,omitempty"`        // Error message if failed
 	Duration    int64      `json:"duration_ms,omitempty"`  // Duration in milliseconds
+	TaskHash    string     `json:"task_hash,omitempty"`    // History snapshot to run instead of the current definition (reruns)
 }
 
 // Queue manages the task queue
@@ -38,10 +43,13 @@ type Queue struct {
 	Tasks []QueuedTask `json:"tasks"`
 	mu    sync.RWMutex
 	path  string
+	db    *sql.DB // Set with the SQLite backend, which replaces the file
 }
 
-// Load loads queue from worktrees/.queue.json
-func Load(worktreeDir string) (*Queue, error) {
+// Load loads queue from worktrees/.queue.json or, when backend is
+// storage.BackendSQLite, from the worktrees database (migrating the file into
+// it on first use). An empty backend means the JSON file.
+func Load(worktreeDir string, backend string) (*Queue, error) {
 	queuePath := filepath.Join(worktreeDir, ".queue.json")
 
 	q := &Queue{
@@ -49,6 +57,13 @@ func Load(worktreeDir string) (*Queue, error) {
 		path:  queuePath,
 	}
 
+	if backend == storage.BackendSQLite {
+		if err := q.loadSQLite(worktreeDir); err != nil {
+			return nil, err
+		}
+		return q, nil
+	}
+
 	// If file doesn't exist, return empty queue
 	if _, err := os.Stat(queuePath); os.IsNotExist(err) {
 		return q, nil
@@ -65,6 +80,18 @@ func Load(worktreeDir string) (*Queue, error) {
 		return nil, fmt.Errorf("failed to parse queue file: %w", err)
 	}
 
+	// Every command selects tasks by ID, so an edited file must keep them unique
+	seen := make(map[string]bool, len(q.Tasks))
+	for i, task := range q.Tasks {
+		if task.ID == "" {
+			return nil, fmt.Errorf("invalid queue file: task %d has no ID", i+1)
+		}
+		if seen[task.ID] {
+			return nil, fmt.Errorf("invalid queue file: duplicate task ID %s", task.ID)
+		}
+		seen[task.ID] = true
+	}
+
 	return q, nil
 }
 
@@ -73,43 +100,37 @@ func (q *Queue) Save() error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
-	// Marshal to JSON
-	data, err := json.MarshalIndent(q, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal queue: %w", err)
-	}
-
-	// Atomic write: write to temp file, then rename
-	tempPath := q.path + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write temp queue file: %w", err)
-	}
-
-	if err := os.Rename(tempPath, q.path); err != nil {
-		os.Remove(tempPath) // Clean up temp file
-		return fmt.Errorf("failed to rename queue file: %w", err)
-	}
-
-	return nil
+	return q.saveUnlocked()
 }
 
 // Add adds a task to the queue
 func (q *Queue) Add(agentName, worktree string) (*QueuedTask, error) {
+	return q.AddSnapshot(agentName, worktree, "")
+}
+
+// AddSnapshot adds a task that runs the task definition stored in the
+// execution history under taskHash, e.g. to rerun a past execution
+func (q *Queue) AddSnapshot(agentName, worktree, taskHash string) (*QueuedTask, error) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	if err := q.refreshUnlocked(); err != nil {
+		return nil, err
+	}
+
 	task := &QueuedTask{
 		ID:        uuid.New().String(),
 		AgentName: agentName,
 		Worktree:  worktree,
 		Status:    StatusPending,
 		CreatedAt: time.Now(),
+		TaskHash:  taskHash,
 	}
 
 	q.Tasks = append(q.Tasks, *task)
 
 	// Save immediately
-	if err := q.saveUnlocked(); err != nil {
+	if err := q.storeUnlocked([]QueuedTask{*task}, nil); err != nil {
 		return nil, err
 	}
 
@@ -135,6 +156,10 @@ func (q *Queue) UpdateStatus(taskID string, status TaskStatus, taskErr error) er
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	if err := q.refreshUnlocked(); err != nil {
+		return err
+	}
+
 	for i := range q.Tasks {
 		if q.Tasks[i].ID == taskID {
 			q.Tasks[i].Status = status
@@ -155,7 +180,7 @@ func (q *Queue) UpdateStatus(taskID string, status TaskStatus, taskErr error) er
 			}
 
 			// Save immediately
-			return q.saveUnlocked()
+			return q.storeUnlocked([]QueuedTask{q.Tasks[i]}, nil)
 		}
 	}
 
@@ -189,17 +214,81 @@ func (q *Queue) Remove(taskID string) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	if err := q.refreshUnlocked(); err != nil {
+		return err
+	}
+
 	for i := range q.Tasks {
 		if q.Tasks[i].ID == taskID {
 			// Remove task
 			q.Tasks = append(q.Tasks[:i], q.Tasks[i+1:]...)
-			return q.saveUnlocked()
+			return q.storeUnlocked(nil, []string{taskID})
 		}
 	}
 
 	return fmt.Errorf("task not found: %s", taskID)
 }
 
+// ShortIDLength is the number of ID characters shown in task listings
+const ShortIDLength = 8
+
+// ShortID returns the abbreviated task ID shown in listings
+func ShortID(id string) string {
+	if len(id) <= ShortIDLength {
+		return id
+	}
+	return id[:ShortIDLength]
+}
+
+// Find returns the task whose ID is id or starts with id. A prefix matching
+// several tasks is an error, so a short ID never selects the wrong task.
+func (q *Queue) Find(id string) (*QueuedTask, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	id = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(id, "...")))
+	if id == "" {
+		return nil, fmt.Errorf("task ID cannot be empty")
+	}
+
+	var matches []*QueuedTask
+	for i := range q.Tasks {
+		if q.Tasks[i].ID == id {
+			return &q.Tasks[i], nil
+		}
+		if strings.HasPrefix(q.Tasks[i].ID, id) {
+			matches = append(matches, &q.Tasks[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("task not found: %s", id)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, task := range matches {
+			ids[i] = task.ID
+		}
+		return nil, fmt.Errorf("task ID prefix '%s' is ambiguous, it matches: %s", id, strings.Join(ids, ", "))
+	}
+}
+
+// Select returns the tasks of an agent on a worktree, optionally filtered by status
+func (q *Queue) Select(agentName, worktree string, status TaskStatus) []QueuedTask {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	var selected []QueuedTask
+	for _, task := range q.Tasks {
+		if task.AgentName == agentName && task.Worktree == worktree && (status == "" || task.Status == status) {
+			selected = append(selected, task)
+		}
+	}
+	return selected
+}
+
 // Clear removes all completed and failed tasks
 func (q *Queue) Clear() error {
 	q.mu.Lock()
@@ -207,18 +296,64 @@ func (q *Queue) Clear() error {
 
 	// Keep only pending and running tasks
 	var activeTasks []QueuedTask
+	var removed []string
 	for _, task := range q.Tasks {
 		if task.Status == StatusPending || task.Status == StatusRunning {
 			activeTasks = append(activeTasks, task)
+		} else {
+			removed = append(removed, task.ID)
 		}
 	}
 
 	q.Tasks = activeTasks
+	return q.storeUnlocked(nil, removed)
+}
+
+// refreshUnlocked re-reads the tasks from the queue file before a change, so
+// tasks another process added or updated since Load are not overwritten
+// (assumes caller has lock). Without a file the tasks in memory are kept.
+func (q *Queue) refreshUnlocked() error {
+	if q.db != nil {
+		tasks, err := selectTasks(q.db)
+		if err != nil {
+			return err
+		}
+		q.Tasks = tasks
+		return nil
+	}
+
+	data, err := os.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read queue file: %w", err)
+	}
+
+	var current Queue
+	if err := json.Unmarshal(data, &current); err != nil {
+		return fmt.Errorf("failed to parse queue file: %w", err)
+	}
+	q.Tasks = current.Tasks
+	return nil
+}
+
+// storeUnlocked persists a change that upserted the changed tasks and
+// removed the tasks with the removed IDs: the queue file is rewritten, the
+// database only gets those rows written (assumes caller has lock)
+func (q *Queue) storeUnlocked(changed []QueuedTask, removed []string) error {
+	if q.db != nil {
+		return q.writeSQLite(changed, removed)
+	}
 	return q.saveUnlocked()
 }
 
 // saveUnlocked saves without locking (assumes caller has lock)
 func (q *Queue) saveUnlocked() error {
+	if q.db != nil {
+		return q.replaceSQLite()
+	}
+
 	// Marshal to JSON
 	data, err := json.MarshalIndent(q, "", "  ")
 	if err != nil {
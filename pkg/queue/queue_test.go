@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -12,7 +13,7 @@ import (
 func newTestQueue(t *testing.T) *Queue {
 	t.Helper()
 	dir := t.TempDir()
-	q, err := Load(dir)
+	q, err := Load(dir, "")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
@@ -22,7 +23,7 @@ func newTestQueue(t *testing.T) *Queue {
 
 func TestLoadEmpty(t *testing.T) {
 	dir := t.TempDir()
-	q, err := Load(dir)
+	q, err := Load(dir, "")
 	if err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
@@ -33,7 +34,7 @@ func TestLoadEmpty(t *testing.T) {
 
 func TestLoadAndSave(t *testing.T) {
 	dir := t.TempDir()
-	q, err := Load(dir)
+	q, err := Load(dir, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -50,7 +51,7 @@ func TestLoadAndSave(t *testing.T) {
 		t.Fatalf("Save() error = %v", err)
 	}
 
-	q2, err := Load(dir)
+	q2, err := Load(dir, "")
 	if err != nil {
 		t.Fatalf("Load() after save error = %v", err)
 	}
@@ -67,7 +68,7 @@ func TestLoadInvalidJSON(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(dir, ".queue.json"), []byte("{bad json"), 0644); err != nil {
 		t.Fatal(err)
 	}
-	_, err := Load(dir)
+	_, err := Load(dir, "")
 	if err == nil {
 		t.Error("expected error for invalid JSON queue file")
 	}
@@ -99,6 +100,14 @@ func TestAdd(t *testing.T) {
 	if len(q.Tasks) != 1 {
 		t.Errorf("expected 1 task in queue, got %d", len(q.Tasks))
 	}
+
+	rerun, err := q.AddSnapshot("npm-audit", "feature-x", "abc123")
+	if err != nil {
+		t.Fatalf("AddSnapshot() error = %v", err)
+	}
+	if rerun.TaskHash != "abc123" || task.TaskHash != "" {
+		t.Errorf("TaskHash = %q and %q, want abc123 and empty", rerun.TaskHash, task.TaskHash)
+	}
 }
 
 func TestNext(t *testing.T) {
@@ -295,6 +304,71 @@ func TestRemove(t *testing.T) {
 	})
 }
 
+func TestFind(t *testing.T) {
+	q := newTestQueue(t)
+	q.Tasks = []QueuedTask{
+		{ID: "3f2a9c1e-0000-4000-8000-000000000001", AgentName: "npm-audit", Worktree: "feature-x", Status: StatusPending},
+		{ID: "3f2a9c1e-0000-4000-8000-000000000002", AgentName: "npm-audit", Worktree: "feature-x", Status: StatusCompleted},
+		{ID: "7b1d0e44-0000-4000-8000-000000000003", AgentName: "go-deps", Worktree: "feature-y", Status: StatusPending},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		wantID  string
+		wantErr string
+	}{
+		{name: "full ID", id: "3f2a9c1e-0000-4000-8000-000000000002", wantID: "3f2a9c1e-0000-4000-8000-000000000002"},
+		{name: "unique prefix", id: "7b1d", wantID: "7b1d0e44-0000-4000-8000-000000000003"},
+		{name: "listed short ID", id: "7B1D0E44...", wantID: "7b1d0e44-0000-4000-8000-000000000003"},
+		{name: "ambiguous prefix", id: "3f2a9c1e", wantErr: "ambiguous"},
+		{name: "unknown", id: "ffff", wantErr: "task not found"},
+		{name: "empty", id: " ", wantErr: "cannot be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task, err := q.Find(tt.id)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Find(%q) error = %v, want containing %q", tt.id, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Find(%q) error = %v", tt.id, err)
+			}
+			if task.ID != tt.wantID {
+				t.Errorf("Find(%q) = %s, want %s", tt.id, task.ID, tt.wantID)
+			}
+		})
+	}
+
+	if got := q.Select("npm-audit", "feature-x", ""); len(got) != 2 {
+		t.Errorf("Select() returned %d tasks, want 2", len(got))
+	}
+	if got := q.Select("npm-audit", "feature-x", StatusPending); len(got) != 1 || got[0].Status != StatusPending {
+		t.Errorf("Select(pending) = %v", got)
+	}
+	if got := q.Select("npm-audit", "feature-y", ""); len(got) != 0 {
+		t.Errorf("Select() on other worktree = %v", got)
+	}
+
+	if got := ShortID("abc"); got != "abc" {
+		t.Errorf("ShortID(abc) = %s", got)
+	}
+}
+
+func TestLoadDuplicateIDs(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"tasks": [{"id": "a", "status": "pending"}, {"id": "a", "status": "failed"}]}`
+	if err := os.WriteFile(filepath.Join(dir, ".queue.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir, ""); err == nil || !strings.Contains(err.Error(), "duplicate task ID") {
+		t.Errorf("Load() error = %v, want duplicate task ID", err)
+	}
+}
+
 func TestClear(t *testing.T) {
 	q := newTestQueue(t)
 
@@ -349,3 +423,37 @@ func TestCount(t *testing.T) {
 		t.Errorf("Count(failed) = %d, want 0", n)
 	}
 }
+
+func TestConcurrentQueueInstances(t *testing.T) {
+	dir := t.TempDir()
+	first, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes through one instance are not lost by changes through another
+	// that was loaded before them
+	task, err := first.Add("agent-a", "feature-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.Add("agent-b", "feature-y"); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.UpdateStatus(task.ID, StatusRunning, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Count("") != 2 || q.Count(StatusRunning) != 1 || q.Count(StatusPending) != 1 {
+		t.Errorf("queue has %d tasks (%d running, %d pending), want 2 (1 running, 1 pending)",
+			q.Count(""), q.Count(StatusRunning), q.Count(StatusPending))
+	}
+}
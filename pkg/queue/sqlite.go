@@ -0,0 +1,148 @@
+package queue
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/braunmar/worktree/pkg/storage"
+)
+
+// queueSchema keeps each task as JSON, next to the columns worth filtering
+// on with the sqlite3 shell
+const queueSchema = `
+CREATE TABLE IF NOT EXISTS queue (
+	seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+	id         TEXT NOT NULL UNIQUE,
+	agent_name TEXT NOT NULL,
+	worktree   TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	task       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS queue_status ON queue (status);
+`
+
+// loadSQLite reads the tasks from the database of worktreeDir, importing
+// .queue.json first if it still exists
+func (q *Queue) loadSQLite(worktreeDir string) error {
+	db, err := storage.Open(worktreeDir)
+	if err != nil {
+		return err
+	}
+	if err := storage.Init(db, queueSchema, q.path, importQueue); err != nil {
+		return fmt.Errorf("queue: %w", err)
+	}
+
+	q.db = db
+	q.Tasks, err = selectTasks(db)
+	return err
+}
+
+// importQueue inserts the tasks of a .queue.json file
+func importQueue(tx *sql.Tx, data []byte) error {
+	var file Queue
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse queue file: %w", err)
+	}
+	for i, task := range file.Tasks {
+		if task.ID == "" {
+			return fmt.Errorf("invalid queue file: task %d has no ID", i+1)
+		}
+		if err := putTask(tx, task, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectTasks returns all tasks in queue order
+func selectTasks(db *sql.DB) ([]QueuedTask, error) {
+	rows, err := db.Query(`SELECT task FROM queue ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []QueuedTask{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read queue: %w", err)
+		}
+		var task QueuedTask
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return nil, fmt.Errorf("failed to parse queued task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read queue: %w", err)
+	}
+	return tasks, nil
+}
+
+// putTask inserts a task or, with update, overwrites the task with its ID
+// keeping its place in the queue. Without update an existing task is kept.
+func putTask(tx *sql.Tx, task QueuedTask, update bool) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued task: %w", err)
+	}
+	conflict := `DO NOTHING`
+	if update {
+		conflict = `DO UPDATE SET status = excluded.status, task = excluded.task`
+	}
+	_, err = tx.Exec(`INSERT INTO queue (id, agent_name, worktree, status, task) VALUES (?, ?, ?, ?, ?) ON CONFLICT (id) `+conflict,
+		task.ID, task.AgentName, task.Worktree, string(task.Status), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to store queued task: %w", err)
+	}
+	return nil
+}
+
+// writeSQLite upserts the changed tasks and deletes the removed ones in one
+// transaction, leaving the tasks other processes changed alone
+func (q *Queue) writeSQLite(changed []QueuedTask, removed []string) error {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, task := range changed {
+		if err := putTask(tx, task, true); err != nil {
+			return err
+		}
+	}
+	for _, id := range removed {
+		if _, err := tx.Exec(`DELETE FROM queue WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to remove queued task: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	return nil
+}
+
+// replaceSQLite overwrites the stored tasks with q.Tasks (caller holds the lock)
+func (q *Queue) replaceSQLite() error {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM queue`); err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	for _, task := range q.Tasks {
+		if err := putTask(tx, task, true); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save queue: %w", err)
+	}
+	return nil
+}
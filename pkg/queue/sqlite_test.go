@@ -0,0 +1,77 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/storage"
+)
+
+func TestSQLiteBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	// Tasks written to the JSON file are migrated on first use
+	q, err := Load(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrated, err := q.Add("agent-a", "feature-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if first.Count("") != 1 || first.Tasks[0].ID != migrated.ID {
+		t.Fatalf("migrated tasks = %+v, want the JSON task", first.Tasks)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".queue.json")); !os.IsNotExist(err) {
+		t.Errorf(".queue.json still exists after migration (err = %v)", err)
+	}
+
+	// Changes through one instance are not lost by changes through another
+	second, err := Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := second.Add("agent-b", "feature-y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.UpdateStatus(migrated.ID, StatusRunning, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.UpdateStatus(migrated.ID, StatusFailed, errors.New("boom")); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Count("") != 2 || q.Tasks[0].ID != migrated.ID || q.Tasks[1].ID != added.ID {
+		t.Fatalf("tasks = %+v, want the migrated task then the added one", q.Tasks)
+	}
+	if task := q.Tasks[0]; task.Status != StatusFailed || task.Error != "boom" || task.StartedAt == nil {
+		t.Errorf("updated task = %+v, want failed with its start time and error", task)
+	}
+
+	// Clear removes finished tasks only, Remove the given one
+	if err := q.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Remove(added.ID); err != nil {
+		t.Fatal(err)
+	}
+	q, err = Load(dir, storage.BackendSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Count("") != 0 {
+		t.Errorf("tasks after Clear() and Remove() = %+v, want none", q.Tasks)
+	}
+}
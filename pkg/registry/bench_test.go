@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+const (
+	benchServices  = 20   // Ranged services in the benchmark config
+	benchRangeSize = 1000 // Ports per service range
+	benchFeatures  = 500  // Registered features already holding ports
+)
+
+// benchRegistry returns a registry with wide port ranges whose low ends are
+// taken by registered features, so allocation has to skip past them
+func benchRegistry(b *testing.B) (*Registry, []string) {
+	b.Helper()
+
+	envVars := make(map[string]config.EnvVarConfig)
+	var services []string
+	for i := 0; i < benchServices; i++ {
+		service := fmt.Sprintf("PORT_%d", i)
+		start := 20000 + i*benchRangeSize
+		portRange := [2]int{start, start + benchRangeSize - 1}
+		envVars[service] = config.EnvVarConfig{Env: service, Range: &portRange}
+		services = append(services, service)
+	}
+
+	reg, err := Load(b.TempDir(), &config.WorktreeConfig{EnvVariables: envVars})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for f := 0; f < benchFeatures; f++ {
+		ports := make(map[string]int, len(services))
+		for i, service := range services {
+			ports[service] = 20000 + i*benchRangeSize + f
+		}
+		name := fmt.Sprintf("feature-%d", f)
+		if err := reg.Add(&Worktree{Branch: name, Normalized: name, Created: time.Now(), Ports: ports}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return reg, services
+}
+
+func BenchmarkAllocatePorts(b *testing.B) {
+	reg, services := benchRegistry(b)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := reg.AllocatePorts(services); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestPerformanceBudget fails when port allocation exceeds its time budget.
+// See the budget test in pkg/config; it only runs with WORKTREE_BENCH_BUDGET=1.
+func TestPerformanceBudget(t *testing.T) {
+	if os.Getenv("WORKTREE_BENCH_BUDGET") == "" {
+		t.Skip("set WORKTREE_BENCH_BUDGET=1 to check performance budgets")
+	}
+
+	const budget = 20 * time.Millisecond
+	result := testing.Benchmark(BenchmarkAllocatePorts)
+	perOp := time.Duration(result.NsPerOp())
+	t.Logf("AllocatePorts: %v/op, %d allocs/op", perOp, result.AllocsPerOp())
+	if perOp > budget {
+		t.Errorf("AllocatePorts took %v/op, budget is %v", perOp, budget)
+	}
+}
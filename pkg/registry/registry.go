@@ -1,13 +1,18 @@
 package registry
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/exec"
+	"github.com/braunmar/worktree/pkg/ui"
 	"net"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -19,15 +24,20 @@ const (
 
 // Worktree represents a single worktree instance
 type Worktree struct {
-	Branch          string            `json:"branch"`
-	Normalized      string            `json:"normalized"`
-	Created         time.Time         `json:"created"`
-	Projects        []string          `json:"projects"`
-	Ports           map[string]int    `json:"ports"`
-	ComputedVars    map[string]string `json:"computed_vars,omitempty"`    // All env vars fully resolved for this instance (ports, derived URLs, aliases)
-	ComposeProject  string            `json:"compose_project,omitempty"`  // Deprecated: use ComposeProjects
-	ComposeProjects map[string]string `json:"compose_projects,omitempty"` // Per-service compose project names
-	YoloMode        bool              `json:"yolo_mode,omitempty"`        // YOLO mode: Claude works autonomously when solution is clear
+	Branch          string                 `json:"branch"`
+	Branches        map[string]string      `json:"branches,omitempty"` // Per-project branches that differ from Branch
+	Normalized      string                 `json:"normalized"`
+	Created         time.Time              `json:"created"`
+	Projects        []string               `json:"projects"`
+	Ports           map[string]int         `json:"ports"`
+	ComputedVars    map[string]string      `json:"computed_vars,omitempty"`    // All env vars fully resolved for this instance (ports, derived URLs, aliases)
+	ComposeProject  string                 `json:"compose_project,omitempty"`  // Deprecated: use ComposeProjects
+	ComposeProjects map[string]string      `json:"compose_projects,omitempty"` // Per-service compose project names
+	YoloMode        bool                   `json:"yolo_mode,omitempty"`        // YOLO mode: Claude works autonomously when solution is clear
+	Preset          string                 `json:"preset,omitempty"`           // Preset the feature was created with
+	BaseRef         string                 `json:"base_ref,omitempty"`         // --from ref missing branches were created from
+	NoFixtures      bool                   `json:"no_fixtures,omitempty"`      // Created with --no-fixtures
+	Runs            map[string]exec.Result `json:"runs,omitempty"`             // Last run of each start command and hook, by name
 }
 
 // GetComposeProject returns the compose project name for a specific service
@@ -40,12 +50,43 @@ func (w *Worktree) GetComposeProject(service string) string {
 	return w.ComposeProject
 }
 
+// RecordRun keeps a command's result as the last run of its name
+func (w *Worktree) RecordRun(result exec.Result) {
+	if w.Runs == nil {
+		w.Runs = make(map[string]exec.Result)
+	}
+	w.Runs[result.Name] = result
+}
+
+// ListRuns returns the recorded runs, oldest first; with a project, only the
+// runs of its commands
+func (w *Worktree) ListRuns(project string) []exec.Result {
+	var runs []exec.Result
+	for _, run := range w.Runs {
+		if project == "" || strings.HasPrefix(run.Name, project+".") {
+			runs = append(runs, run)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Started.Before(runs[j].Started) })
+	return runs
+}
+
+// BranchFor returns the branch checked out in a project's worktree
+func (w *Worktree) BranchFor(project string) string {
+	if branch := w.Branches[project]; branch != "" {
+		return branch
+	}
+	return w.Branch
+}
+
 // Registry manages all worktree instances and port allocations
 type Registry struct {
-	Worktrees  map[string]*Worktree `json:"worktrees"`
-	PortRanges map[string][2]int    `json:"port_ranges"`
-	mu         sync.RWMutex
-	filePath   string
+	Worktrees    map[string]*Worktree `json:"worktrees"`
+	PortRanges   map[string][2]int    `json:"port_ranges"`
+	ServicePools map[string]string    `json:"service_pools,omitempty"` // Service -> port pool it allocates from
+	Expansions   map[string]int       `json:"-"`                       // Service -> ports above its range it may use (on_exhausted: expand-range)
+	mu           sync.RWMutex
+	filePath     string
 }
 
 // BuildPortRanges constructs port ranges from WorktreeConfig
@@ -57,9 +98,9 @@ func BuildPortRanges(workCfg *config.WorktreeConfig) map[string][2]int {
 		return ranges
 	}
 
-	// Read all configured port ranges
-	for serviceName, portCfg := range workCfg.EnvVariables {
-		if portRange := portCfg.GetPortRange(); portRange != nil {
+	// Read all configured port ranges (pool members get their pool's range)
+	for serviceName := range workCfg.EnvVariables {
+		if portRange := workCfg.GetPortRangeFor(serviceName); portRange != nil {
 			ranges[serviceName] = *portRange
 		}
 	}
@@ -67,18 +108,73 @@ func BuildPortRanges(workCfg *config.WorktreeConfig) map[string][2]int {
 	return ranges
 }
 
+// BuildServicePools maps each service allocating from a port pool to the pool name
+func BuildServicePools(workCfg *config.WorktreeConfig) map[string]string {
+	pools := make(map[string]string)
+
+	if workCfg == nil {
+		return pools
+	}
+
+	for serviceName, portCfg := range workCfg.EnvVariables {
+		if portCfg.Pool != "" {
+			pools[serviceName] = portCfg.Pool
+		}
+	}
+
+	return pools
+}
+
+// BuildRangeExpansions maps each service with on_exhausted: expand-range(+N) to N
+func BuildRangeExpansions(workCfg *config.WorktreeConfig) map[string]int {
+	expansions := make(map[string]int)
+
+	if workCfg == nil {
+		return expansions
+	}
+
+	for serviceName := range workCfg.EnvVariables {
+		if policy := workCfg.GetOnExhausted(serviceName); policy.Action == config.OnExhaustedExpand {
+			expansions[serviceName] = policy.Expand
+		}
+	}
+
+	return expansions
+}
+
+// FilePath returns the path of the registry file in worktreeDir
+func FilePath(worktreeDir string) string {
+	return filepath.Join(worktreeDir, registryFileName)
+}
+
 // Load loads the registry from disk, or creates a new one if it doesn't exist
-// workCfg is optional - if provided, port ranges are loaded from configuration
+// workCfg is optional - if provided, port ranges are loaded from configuration.
+// A registry that fails its checksum is an error (see config.ErrChecksumMismatch),
+// since allocating ports from a corrupted registry can hand out a port twice.
 func Load(worktreeDir string, workCfg *config.WorktreeConfig) (*Registry, error) {
-	registryPath := filepath.Join(worktreeDir, registryFileName)
+	return load(worktreeDir, workCfg, true)
+}
+
+// LoadUnverified loads the registry without checking its checksum, for
+// diagnosing and repairing it
+func LoadUnverified(worktreeDir string, workCfg *config.WorktreeConfig) (*Registry, error) {
+	return load(worktreeDir, workCfg, false)
+}
+
+func load(worktreeDir string, workCfg *config.WorktreeConfig, verify bool) (*Registry, error) {
+	registryPath := FilePath(worktreeDir)
 
 	// Build port ranges from config (with defaults as fallback)
 	portRanges := BuildPortRanges(workCfg)
 
+	servicePools := BuildServicePools(workCfg)
+
 	r := &Registry{
-		Worktrees:  make(map[string]*Worktree),
-		PortRanges: portRanges,
-		filePath:   registryPath,
+		Worktrees:    make(map[string]*Worktree),
+		PortRanges:   portRanges,
+		ServicePools: servicePools,
+		Expansions:   BuildRangeExpansions(workCfg),
+		filePath:     registryPath,
 	}
 
 	// If registry doesn't exist, return empty registry
@@ -92,6 +188,12 @@ func Load(worktreeDir string, workCfg *config.WorktreeConfig) (*Registry, error)
 		return nil, fmt.Errorf("failed to read registry: %w", err)
 	}
 
+	if verify {
+		if err := config.VerifyChecksum(registryPath, data); err != nil {
+			return nil, fmt.Errorf("registry integrity check failed: %w; run 'worktree doctor --fix' to accept it if it still parses, else restore the last good copy", err)
+		}
+	}
+
 	// Unmarshal JSON
 	if err := json.Unmarshal(data, r); err != nil {
 		return nil, fmt.Errorf("failed to parse registry: %w", err)
@@ -99,6 +201,7 @@ func Load(worktreeDir string, workCfg *config.WorktreeConfig) (*Registry, error)
 
 	// Override with configured port ranges (config is source of truth)
 	r.PortRanges = portRanges
+	r.ServicePools = servicePools
 	r.filePath = registryPath
 
 	return r, nil
@@ -121,14 +224,8 @@ func (r *Registry) Save() error {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
 
-	// Write atomically by writing to temp file and renaming
-	tempPath := r.filePath + ".tmp"
-	if err := os.WriteFile(tempPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write registry: %w", err)
-	}
-
-	if err := os.Rename(tempPath, r.filePath); err != nil {
-		os.Remove(tempPath) // Clean up temp file
+	// Write atomically, with a checksum and a backup of the previous version
+	if err := config.WriteFileChecksummed(r.filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to save registry: %w", err)
 	}
 
@@ -170,6 +267,34 @@ func (r *Registry) Get(normalized string) (*Worktree, bool) {
 	return wt, exists
 }
 
+// Find returns the worktree registered under name, also accepting a branch
+// name and the bare name of a feature whose name carries a branch hash suffix
+// (normalized_name_suffix: hash). A bare name shared by several suffixed
+// features matches none of them; those need the full name or their branch.
+func (r *Registry) Find(name string) (*Worktree, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	normalized := NormalizeBranchName(name)
+	for _, candidate := range []string{name, normalized, WithHashSuffix(normalized, name)} {
+		if wt, exists := r.Worktrees[candidate]; exists {
+			return wt, true
+		}
+	}
+
+	var match *Worktree
+	for _, wt := range r.Worktrees {
+		if NormalizeBranchName(wt.Branch) != normalized || wt.Normalized != WithHashSuffix(normalized, wt.Branch) {
+			continue
+		}
+		if match != nil {
+			return nil, false
+		}
+		match = wt
+	}
+	return match, match != nil
+}
+
 // List returns all worktrees sorted by creation time
 func (r *Registry) List() []*Worktree {
 	r.mu.RLock()
@@ -183,8 +308,51 @@ func (r *Registry) List() []*Worktree {
 	return worktrees
 }
 
+// PortHolder is a port allocated to a feature
+type PortHolder struct {
+	Feature string
+	Service string
+	Port    int
+}
+
+// PortExhaustedError is returned when every port a service may use is taken
+type PortExhaustedError struct {
+	Service string
+	Range   [2]int       // Configured range, without an expansion
+	Pool    string       // Port pool the service allocates from, if any
+	Holders []PortHolder // Features holding the ports, sorted by feature
+}
+
+func (e *PortExhaustedError) Error() string {
+	msg := fmt.Sprintf("no available ports in range %d-%d for service %s", e.Range[0], e.Range[1], e.Service)
+	if e.Pool != "" {
+		msg += fmt.Sprintf(" (pool %s)", e.Pool)
+	}
+	if len(e.Holders) > 0 {
+		allocated := make([]string, len(e.Holders))
+		for i, h := range e.Holders {
+			if e.Pool != "" {
+				allocated[i] = fmt.Sprintf("%s (%s): %d", h.Feature, h.Service, h.Port)
+			} else {
+				allocated[i] = fmt.Sprintf("%s: %d", h.Feature, h.Port)
+			}
+		}
+		msg += fmt.Sprintf("\nCurrently allocated:\n  %s", strings.Join(allocated, "\n  "))
+	}
+	return msg
+}
+
 // FindAvailablePort finds an available port for a service
 func (r *Registry) FindAvailablePort(service string) (int, error) {
+	return r.findAvailablePort(service, r.expandedPorts())
+}
+
+// findAvailablePort finds an available port for a service, skipping ports in
+// reserved, which callers seed with expandedPorts.
+// Services sharing a port pool never get a port already used by another pool member.
+// Once the range is full, a service with an expansion continues above it; the
+// error of a service without one is a *PortExhaustedError.
+func (r *Registry) findAvailablePort(service string, reserved map[int]bool) (int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -200,52 +368,196 @@ func (r *Registry) FindAvailablePort(service string) (int, error) {
 
 	minPort, maxPort := portRange[0], portRange[1]
 
-	// Collect used ports for this service from registry
-	usedPorts := make(map[int]bool)
+	// Collect used ports for this service (or every service in its pool) from registry
+	sharing := r.servicesSharingRange(service)
+	usedPorts := make(map[int]bool, len(reserved))
+	for port := range reserved {
+		usedPorts[port] = true
+	}
+	var holders []PortHolder
 	for _, wt := range r.Worktrees {
-		if port, ok := wt.Ports[service]; ok {
-			usedPorts[port] = true
+		for _, svc := range sharing {
+			if port, ok := wt.Ports[svc]; ok {
+				usedPorts[port] = true
+				holders = append(holders, PortHolder{Feature: wt.Normalized, Service: svc, Port: port})
+			}
 		}
 	}
 
-	// Find first available port
+	// Find first available port; the skip messages are only formatted when
+	// shown, as a crowded range skips many ports
+	verbose := ui.IsVerboseFor(ui.ScopeRegistry)
 	for port := minPort; port <= maxPort; port++ {
-		if !usedPorts[port] && isPortAvailable(port) {
-			return port, nil
+		if usedPorts[port] {
+			if verbose {
+				ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("%s: port %d is already allocated, skipping", service, port))
+			}
+			continue
 		}
+		if !isPortAvailable(port) {
+			if verbose {
+				ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("%s: port %d is in use on the host, skipping", service, port))
+			}
+			continue
+		}
+		ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("%s: allocated port %d (range %d-%d)", service, port, minPort, maxPort))
+		return port, nil
 	}
 
-	// Build detailed error message showing what's allocated
-	allocatedInfo := make([]string, 0)
-	for _, wt := range r.Worktrees {
-		if port, ok := wt.Ports[service]; ok {
-			allocatedInfo = append(allocatedInfo, fmt.Sprintf("%s: %d", wt.Normalized, port))
+	// Ports above the range may belong to other ranges, so skip every allocated port there
+	if expand := r.Expansions[service]; expand > 0 {
+		for _, wt := range r.Worktrees {
+			for _, port := range wt.Ports {
+				usedPorts[port] = true
+			}
+		}
+		for port := maxPort + 1; port <= maxPort+expand; port++ {
+			if !usedPorts[port] && isPortAvailable(port) {
+				ui.Verbose(ui.ScopeRegistry, fmt.Sprintf("%s: range %d-%d is full, allocated port %d above it (expand-range)", service, minPort, maxPort, port))
+				return port, nil
+			}
 		}
 	}
 
-	errorMsg := fmt.Sprintf("no available ports in range %d-%d for service %s", minPort, maxPort, service)
-	if len(allocatedInfo) > 0 {
-		errorMsg += fmt.Sprintf("\nCurrently allocated:\n  %s", strings.Join(allocatedInfo, "\n  "))
-	}
+	sort.Slice(holders, func(i, j int) bool {
+		if holders[i].Feature != holders[j].Feature {
+			return holders[i].Feature < holders[j].Feature
+		}
+		return holders[i].Service < holders[j].Service
+	})
+	return 0, &PortExhaustedError{Service: service, Range: portRange, Pool: r.ServicePools[service], Holders: holders}
+}
+
+// expandedPorts returns the allocated ports that lie above their service's
+// range (given out by expand-range); other services must skip them too
+func (r *Registry) expandedPorts() map[int]bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return 0, fmt.Errorf("%s", errorMsg)
+	expanded := make(map[int]bool)
+	for _, wt := range r.Worktrees {
+		for svc, port := range wt.Ports {
+			if portRange, ok := r.PortRanges[svc]; ok && port > portRange[1] {
+				expanded[port] = true
+			}
+		}
+	}
+	return expanded
 }
 
 // AllocatePorts allocates ports for all specified services
 func (r *Registry) AllocatePorts(services []string) (map[string]int, error) {
 	ports := make(map[string]int)
+	// Ports handed out in this call are not in the registry yet; reserve them so
+	// services sharing a pool do not get the same port. Ports given out above
+	// ranges are collected once for the whole call.
+	reserved := r.expandedPorts()
 
 	for _, service := range services {
-		port, err := r.FindAvailablePort(service)
+		port, err := r.findAvailablePort(service, reserved)
 		if err != nil {
 			return nil, err
 		}
 		ports[service] = port
+		reserved[port] = true
 	}
 
 	return ports, nil
 }
 
+// CheckPorts reports whether previously allocated ports can be taken again, e.g.
+// when restoring an archived feature: each port must lie in its service's range,
+// not be allocated to a registered feature and be free on the host.
+func (r *Registry) CheckPorts(ports map[string]int) error {
+	return r.checkPorts(ports, "", nil)
+}
+
+// CheckTakeoverPorts reports whether a feature's ports can be handed to the
+// feature replacing it: like CheckPorts, but the ports may be allocated to
+// feature itself, and may be in use on the host when held lists them (ports
+// published by the containers being replaced).
+func (r *Registry) CheckTakeoverPorts(ports map[string]int, feature string, held map[int]bool) error {
+	return r.checkPorts(ports, feature, held)
+}
+
+// checkPorts implements CheckPorts and CheckTakeoverPorts
+func (r *Registry) checkPorts(ports map[string]int, owner string, held map[int]bool) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, service := range sortedServices(ports) {
+		port := ports[service]
+		portRange, ok := r.PortRanges[service]
+		if !ok {
+			return fmt.Errorf("unknown service: %s", service)
+		}
+		if port < portRange[0] || port > portRange[1]+r.Expansions[service] {
+			return fmt.Errorf("%s port %d is outside range %d-%d", service, port, portRange[0], portRange[1]+r.Expansions[service])
+		}
+
+		for _, wt := range r.Worktrees {
+			if wt.Normalized == owner {
+				continue
+			}
+			for svc, used := range wt.Ports {
+				if used == port {
+					return fmt.Errorf("%s port %d is allocated to %s (%s)", service, port, wt.Normalized, svc)
+				}
+			}
+		}
+
+		if !held[port] && !isPortAvailable(port) {
+			return fmt.Errorf("%s port %d is in use on this host", service, port)
+		}
+	}
+
+	return nil
+}
+
+// sortedServices returns the service names of a port map in sorted order
+func sortedServices(ports map[string]int) []string {
+	services := make([]string, 0, len(ports))
+	for service := range ports {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// servicesSharingRange returns the services whose allocations must not collide with
+// service: every member of its pool, or just the service itself. Caller holds r.mu.
+func (r *Registry) servicesSharingRange(service string) []string {
+	pool := r.ServicePools[service]
+	if pool == "" {
+		return []string{service}
+	}
+
+	var services []string
+	for svc, p := range r.ServicePools {
+		if p == pool {
+			services = append(services, svc)
+		}
+	}
+	sort.Strings(services)
+	return services
+}
+
+// PoolAllocations returns the allocated ports of a pool as port -> "feature/service"
+func (r *Registry) PoolAllocations(pool string) map[int]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	allocations := make(map[int]string)
+	for _, wt := range r.Worktrees {
+		for svc, port := range wt.Ports {
+			if r.ServicePools[svc] == pool {
+				allocations[port] = wt.Normalized + "/" + svc
+			}
+		}
+	}
+	return allocations
+}
+
 // isPortAvailable checks if a port is available by attempting to bind to it
 func isPortAvailable(port int) bool {
 	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
@@ -284,3 +596,59 @@ func NormalizeBranchName(branch string) string {
 
 	return normalized
 }
+
+// branchHashLen is the number of hex digits in a branch hash suffix
+const branchHashLen = 6
+
+// BranchHash returns a short stable hash of a branch name, so branches that
+// normalize to the same name (Feature/X and feature-x) get different suffixes
+func BranchHash(branch string) string {
+	sum := sha256.Sum256([]byte(strings.TrimPrefix(branch, "refs/heads/")))
+	return hex.EncodeToString(sum[:])[:branchHashLen]
+}
+
+// WithHashSuffix appends the branch hash to a normalized feature name
+func WithHashSuffix(normalized, branch string) string {
+	return normalized + "-" + BranchHash(branch)
+}
+
+// FeatureName returns the feature name for a branch: its normalized name,
+// suffixed with the branch hash when normalized_name_suffix is hash
+func FeatureName(workCfg *config.WorktreeConfig, branch string) string {
+	normalized := NormalizeBranchName(branch)
+	if workCfg.NameSuffix == config.NameSuffixHash {
+		return WithHashSuffix(normalized, branch)
+	}
+	return normalized
+}
+
+// RenameProject renames a project in every worktree's Projects list and
+// ComposeProjects map. Compose project names are kept as-is so containers that
+// are already running can still be found and stopped. Returns the names of the
+// worktrees that were updated.
+func (r *Registry) RenameProject(oldName, newName string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var updated []string
+	for name, wt := range r.Worktrees {
+		changed := false
+		for i, project := range wt.Projects {
+			if project == oldName {
+				wt.Projects[i] = newName
+				changed = true
+			}
+		}
+		if composeName, ok := wt.ComposeProjects[oldName]; ok {
+			delete(wt.ComposeProjects, oldName)
+			wt.ComposeProjects[newName] = composeName
+			changed = true
+		}
+		if changed {
+			updated = append(updated, name)
+		}
+	}
+
+	sort.Strings(updated)
+	return updated
+}
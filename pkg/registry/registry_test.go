@@ -1,12 +1,16 @@
 package registry
 
 import (
+	"errors"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/braunmar/worktree/pkg/config"
+	"github.com/braunmar/worktree/pkg/exec"
 )
 
 // testConfig creates a WorktreeConfig with standard port ranges for testing
@@ -57,6 +61,67 @@ func TestNormalizeBranchName(t *testing.T) {
 	}
 }
 
+func TestFeatureName(t *testing.T) {
+	plain := &config.WorktreeConfig{}
+	hashed := &config.WorktreeConfig{NameSuffix: config.NameSuffixHash}
+
+	if got := FeatureName(plain, "feature/x"); got != "feature-x" {
+		t.Errorf("FeatureName without suffix = %q, want feature-x", got)
+	}
+
+	a := FeatureName(hashed, "Feature/X")
+	b := FeatureName(hashed, "feature_x")
+	if a == b {
+		t.Fatalf("branches normalizing alike got the same name %q", a)
+	}
+	if !strings.HasPrefix(a, "feature-x-") || len(a) != len("feature-x-")+6 {
+		t.Errorf("FeatureName = %q, want feature-x-<6 hex digits>", a)
+	}
+	if again := FeatureName(hashed, "refs/heads/Feature/X"); again != a {
+		t.Errorf("FeatureName is not stable: %q != %q", again, a)
+	}
+}
+
+func TestFind(t *testing.T) {
+	hashed := &config.WorktreeConfig{NameSuffix: config.NameSuffixHash}
+	reg := &Registry{Worktrees: map[string]*Worktree{}}
+	add := func(branch, name string) {
+		reg.Worktrees[name] = &Worktree{Branch: branch, Normalized: name}
+	}
+	add("feature/bare", "feature-bare")
+	add("feature/one", FeatureName(hashed, "feature/one"))
+	add("Feature/Two", FeatureName(hashed, "Feature/Two"))
+	add("feature_two", FeatureName(hashed, "feature_two"))
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"feature-bare", "feature-bare"},
+		{"feature-one", FeatureName(hashed, "feature/one")},
+		{FeatureName(hashed, "feature/one"), FeatureName(hashed, "feature/one")},
+		{FeatureName(hashed, "feature_two"), FeatureName(hashed, "feature_two")},
+		{"feature-two", ""}, // ambiguous bare name
+		{"Feature/Two", FeatureName(hashed, "Feature/Two")},
+		{"feature/bare", "feature-bare"},
+		{"feature-missing", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wt, exists := reg.Find(tt.name)
+			if tt.want == "" {
+				if exists {
+					t.Errorf("Find(%q) = %s, want no match", tt.name, wt.Normalized)
+				}
+				return
+			}
+			if !exists || wt.Normalized != tt.want {
+				t.Errorf("Find(%q) = %v, %v; want %s", tt.name, wt, exists, tt.want)
+			}
+		})
+	}
+}
+
 func TestRegistryLoadAndSave(t *testing.T) {
 	// Create temp directory for test
 	tempDir, err := os.MkdirTemp("", "registry-test")
@@ -122,6 +187,38 @@ func TestRegistryLoadAndSave(t *testing.T) {
 	}
 }
 
+func TestRegistryChecksum(t *testing.T) {
+	dir := t.TempDir()
+	reg, err := Load(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.Add(&Worktree{Normalized: "feature-a", Ports: map[string]int{"BE_PORT": 8081}})
+	if err := reg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hand edit that double-allocates a port is refused on load
+	path := FilePath(dir)
+	data, _ := os.ReadFile(path)
+	tampered := strings.Replace(string(data), `"worktrees": {`, `"worktrees": {
+    "feature-b": {"normalized": "feature-b", "ports": {"BE_PORT": 8081}},`, 1)
+	if err := os.WriteFile(path, []byte(tampered), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir, nil); !errors.Is(err, config.ErrChecksumMismatch) {
+		t.Fatalf("Load() error = %v, want ErrChecksumMismatch", err)
+	}
+
+	unverified, err := LoadUnverified(dir, nil)
+	if err != nil {
+		t.Fatalf("LoadUnverified() error = %v", err)
+	}
+	if _, ok := unverified.Get("feature-b"); !ok {
+		t.Error("LoadUnverified() did not load the edited registry")
+	}
+}
+
 func TestRegistryAddRemove(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "registry-test")
 	if err != nil {
@@ -171,6 +268,62 @@ func TestRegistryAddRemove(t *testing.T) {
 	}
 }
 
+func TestRecordRun(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	wt := &Worktree{}
+	wt.RecordRun(exec.Result{Name: "backend.start_post", ExitCode: 1, Started: start.Add(time.Minute)})
+	wt.RecordRun(exec.Result{Name: "frontend.start", Started: start.Add(2 * time.Minute)})
+	wt.RecordRun(exec.Result{Name: "backend.start", Started: start})
+	wt.RecordRun(exec.Result{Name: "backend.start_post", Started: start.Add(3 * time.Minute)})
+
+	var names []string
+	for _, run := range wt.ListRuns("") {
+		names = append(names, run.Name)
+	}
+	if got := strings.Join(names, ","); got != "backend.start,frontend.start,backend.start_post" {
+		t.Errorf("ListRuns() = %s", got)
+	}
+	if runs := wt.ListRuns("backend"); len(runs) != 2 || runs[1].ExitCode != 0 {
+		t.Errorf("ListRuns(backend) = %+v, want the last run of each backend command", runs)
+	}
+}
+
+func TestRenameProject(t *testing.T) {
+	reg, err := Load(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reg.Worktrees["feature-a"] = &Worktree{
+		Normalized:      "feature-a",
+		Projects:        []string{"api", "frontend"},
+		ComposeProjects: map[string]string{"api": "proj-feature-a-api", "frontend": "proj-feature-a-frontend"},
+	}
+	reg.Worktrees["feature-b"] = &Worktree{
+		Normalized: "feature-b",
+		Projects:   []string{"frontend"},
+	}
+
+	updated := reg.RenameProject("api", "backend")
+	if len(updated) != 1 || updated[0] != "feature-a" {
+		t.Fatalf("updated = %v, want [feature-a]", updated)
+	}
+
+	wt := reg.Worktrees["feature-a"]
+	if wt.Projects[0] != "backend" {
+		t.Errorf("Projects = %v, want backend first", wt.Projects)
+	}
+	if _, exists := wt.ComposeProjects["api"]; exists {
+		t.Error("old ComposeProjects key should be removed")
+	}
+	if wt.ComposeProjects["backend"] != "proj-feature-a-api" {
+		t.Errorf("ComposeProjects[backend] = %q, want compose name preserved", wt.ComposeProjects["backend"])
+	}
+	if reg.Worktrees["feature-b"].Projects[0] != "frontend" {
+		t.Error("unrelated worktree should be untouched")
+	}
+}
+
 func TestPortAllocation(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "registry-test")
 	if err != nil {
@@ -258,6 +411,68 @@ func TestFindAvailablePort(t *testing.T) {
 	}
 }
 
+func TestCheckPorts(t *testing.T) {
+	reg, err := Load(t.TempDir(), testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(&Worktree{Normalized: "taken", Ports: map[string]int{"FE_PORT": 3001}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		ports   map[string]int
+		wantErr bool
+	}{
+		{"free ports", map[string]int{"FE_PORT": 3002, "BE_PORT": 8082}, false},
+		{"allocated to another feature", map[string]int{"FE_PORT": 3001}, true},
+		{"outside range", map[string]int{"FE_PORT": 8082}, true},
+		{"unknown service", map[string]int{"NOPE": 3002}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := reg.CheckPorts(tt.ports)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPorts(%v) error = %v, wantErr %v", tt.ports, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTakeoverPorts(t *testing.T) {
+	reg, err := Load(t.TempDir(), testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(&Worktree{Normalized: "old", Ports: map[string]int{"FE_PORT": 3001}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Add(&Worktree{Normalized: "other", Ports: map[string]int{"FE_PORT": 3002}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := reg.CheckTakeoverPorts(map[string]int{"FE_PORT": 3001}, "old", nil); err != nil {
+		t.Errorf("own port rejected: %v", err)
+	}
+	if err := reg.CheckTakeoverPorts(map[string]int{"FE_PORT": 3002}, "old", nil); err == nil {
+		t.Error("port of another feature accepted")
+	}
+
+	ln, err := net.Listen("tcp", ":3003")
+	if err != nil {
+		t.Skipf("port 3003 not available: %v", err)
+	}
+	defer ln.Close()
+	if err := reg.CheckTakeoverPorts(map[string]int{"FE_PORT": 3003}, "old", nil); err == nil {
+		t.Error("port in use accepted")
+	}
+	if err := reg.CheckTakeoverPorts(map[string]int{"FE_PORT": 3003}, "old", map[int]bool{3003: true}); err != nil {
+		t.Errorf("held port rejected: %v", err)
+	}
+}
+
 func TestBuildPortRanges(t *testing.T) {
 	// Test with nil config (should return empty)
 	ranges := BuildPortRanges(nil)
@@ -464,8 +679,49 @@ func TestFindAvailablePort_Exhausted(t *testing.T) {
 
 	// All registry ports are taken; FindAvailablePort should fail
 	_, err = reg.FindAvailablePort("MY_PORT")
-	if err == nil {
-		t.Error("expected error when all ports in range are already allocated in registry")
+	var exhausted *PortExhaustedError
+	if !errors.As(err, &exhausted) {
+		t.Fatalf("expected a PortExhaustedError when all ports in range are already allocated in registry, got %v", err)
+	}
+	if len(exhausted.Holders) != 2 || exhausted.Holders[0].Feature != "feature-one" || exhausted.Holders[1].Port != 19999 {
+		t.Errorf("holders = %+v, want feature-one and feature-two", exhausted.Holders)
+	}
+}
+
+func TestFindAvailablePort_ExpandRange(t *testing.T) {
+	tinyRange := [2]int{19990, 19991}
+	otherRange := [2]int{19992, 19995}
+	workCfg := &config.WorktreeConfig{
+		EnvVariables: map[string]config.EnvVarConfig{
+			"MY_PORT":    {Env: "MY_PORT", Range: &tinyRange, OnExhausted: "expand-range(+2)"},
+			"OTHER_PORT": {Env: "OTHER_PORT", Range: &otherRange},
+		},
+	}
+	reg, err := Load(t.TempDir(), workCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg.Add(&Worktree{Normalized: "feature-one", Ports: map[string]int{"MY_PORT": 19990, "OTHER_PORT": 19992}})
+	reg.Add(&Worktree{Normalized: "feature-two", Ports: map[string]int{"MY_PORT": 19991}})
+
+	// Above the full range, skipping the port another service holds there
+	port, err := reg.FindAvailablePort("MY_PORT")
+	if err != nil || port != 19993 {
+		t.Fatalf("FindAvailablePort() = %d, %v, want 19993 above the range", port, err)
+	}
+	reg.Add(&Worktree{Normalized: "feature-three", Ports: map[string]int{"MY_PORT": port}})
+
+	// The other service does not get the port given out above MY_PORT's range
+	if port, err := reg.FindAvailablePort("OTHER_PORT"); err != nil || port != 19994 {
+		t.Errorf("FindAvailablePort(OTHER_PORT) = %d, %v, want 19994", port, err)
+	}
+	// Expanded ports can be taken again, e.g. on restore
+	if err := reg.CheckPorts(map[string]int{"MY_PORT": 19993}); err == nil || !strings.Contains(err.Error(), "allocated to feature-three") {
+		t.Errorf("CheckPorts() error = %v, want allocated to feature-three", err)
+	}
+
+	if _, err := reg.FindAvailablePort("MY_PORT"); err == nil {
+		t.Error("expected an error once the expansion is used up")
 	}
 }
 
@@ -502,3 +758,82 @@ func TestComputedVars_NilWhenNotSet(t *testing.T) {
 		t.Errorf("expected nil/empty ComputedVars for legacy entry, got %v", loaded.ComputedVars)
 	}
 }
+
+func TestPortPoolAllocation(t *testing.T) {
+	tempDir := t.TempDir()
+
+	workCfg := &config.WorktreeConfig{
+		PortPools: map[string]config.PortPoolConfig{
+			"shared": {Range: [2]int{19900, 19903}},
+		},
+		EnvVariables: map[string]config.EnvVarConfig{
+			"API_PORT":   {Env: "API_PORT", Pool: "shared"},
+			"DEBUG_PORT": {Env: "DEBUG_PORT", Pool: "shared"},
+		},
+	}
+
+	reg, err := Load(tempDir, workCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.PortRanges["API_PORT"]; got != [2]int{19900, 19903} {
+		t.Errorf("API_PORT range = %v, want pool range", got)
+	}
+
+	// A port used by one pool member is unavailable to the others
+	reg.Add(&Worktree{
+		Branch:     "feature/one",
+		Normalized: "feature-one",
+		Ports:      map[string]int{"API_PORT": 19900},
+	})
+
+	ports, err := reg.AllocatePorts([]string{"API_PORT", "DEBUG_PORT"})
+	if err != nil {
+		t.Fatalf("AllocatePorts() error = %v", err)
+	}
+	if ports["API_PORT"] == 19900 || ports["DEBUG_PORT"] == 19900 {
+		t.Errorf("allocated port already used in pool: %v", ports)
+	}
+	if ports["API_PORT"] == ports["DEBUG_PORT"] {
+		t.Errorf("pool members got the same port in one allocation: %v", ports)
+	}
+
+	reg.Add(&Worktree{
+		Branch:     "feature/two",
+		Normalized: "feature-two",
+		Ports:      ports,
+	})
+
+	allocations := reg.PoolAllocations("shared")
+	if len(allocations) != 3 {
+		t.Errorf("PoolAllocations() = %v, want 3 entries", allocations)
+	}
+	if allocations[19900] != "feature-one/API_PORT" {
+		t.Errorf("PoolAllocations()[19900] = %q, want feature-one/API_PORT", allocations[19900])
+	}
+
+	// One port left in the pool: the second service cannot be allocated
+	if _, err := reg.AllocatePorts([]string{"API_PORT", "DEBUG_PORT"}); err == nil {
+		t.Error("expected error when the pool is exhausted")
+	}
+}
+
+func TestBranchFor(t *testing.T) {
+	wt := &Worktree{
+		Branch:   "feature/x",
+		Branches: map[string]string{"frontend": "feature/x-ui"},
+	}
+
+	tests := []struct {
+		project string
+		want    string
+	}{
+		{"frontend", "feature/x-ui"},
+		{"backend", "feature/x"},
+	}
+	for _, tt := range tests {
+		if got := wt.BranchFor(tt.project); got != tt.want {
+			t.Errorf("BranchFor(%q) = %q, want %q", tt.project, got, tt.want)
+		}
+	}
+}
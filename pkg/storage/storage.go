@@ -0,0 +1,101 @@
+// Package storage opens the SQLite database that holds the agent execution
+// history and task queue when .worktree.yml sets storage: sqlite, in place of
+// worktrees/.history.json and worktrees/.queue.json.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	_ "modernc.org/sqlite" // Pure-Go driver, no cgo
+)
+
+// Storage backends
+const (
+	BackendJSON   = "json"   // One JSON file per store, rewritten on every change (default)
+	BackendSQLite = "sqlite" // Rows in DBFile, changed one at a time
+)
+
+// DBFile is the SQLite database, relative to the worktrees directory
+const DBFile = ".worktree.db"
+
+// MigratedSuffix is appended to a JSON file once its content was imported
+const MigratedSuffix = ".migrated"
+
+// busyTimeoutMS is how long a write waits for another process holding the
+// database lock, e.g. the scheduler recording a run while a queue command runs
+const busyTimeoutMS = 5000
+
+var (
+	mu  sync.Mutex
+	dbs = map[string]*sql.DB{}
+)
+
+// Open returns the database of a worktrees directory, creating it if needed.
+// Handles are shared per process, so stores loaded in a loop reuse one pool.
+func Open(worktreeDir string) (*sql.DB, error) {
+	path := filepath.Join(worktreeDir, DBFile)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if db, ok := dbs[path]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(worktreeDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", worktreeDir, err)
+	}
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)", path, busyTimeoutMS)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", DBFile, err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", DBFile, err)
+	}
+
+	dbs[path] = db
+	return db, nil
+}
+
+// Init creates a store's tables with schema and, if the store's JSON file
+// still exists, imports it with insert and renames it to *.migrated, so
+// switching to SQLite keeps the existing history and queue. The import runs
+// in one transaction; insert must ignore rows already present, so an import
+// interrupted before the rename is simply repeated.
+func Init(db *sql.DB, schema, jsonPath string, insert func(tx *sql.Tx, data []byte) error) error {
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("failed to create tables: %w", err)
+	}
+
+	data, err := os.ReadFile(jsonPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filepath.Base(jsonPath), err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", filepath.Base(jsonPath), err)
+	}
+	if err := insert(tx, data); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to migrate %s: %w", filepath.Base(jsonPath), err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", filepath.Base(jsonPath), err)
+	}
+
+	// Another process migrating at the same time may have renamed it already
+	if err := os.Rename(jsonPath, jsonPath+MigratedSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rename migrated %s: %w", filepath.Base(jsonPath), err)
+	}
+	return nil
+}
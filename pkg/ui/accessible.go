@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// accessible enables screen-reader friendly output: no emoji, box-drawing
+// characters or colors, and explicit OK/WARN/ERROR words instead of symbols
+var accessible bool
+
+// SetAccessible turns screen-reader friendly output on or off
+func SetAccessible(enabled bool) {
+	accessible = enabled
+	if enabled {
+		color.NoColor = true
+	}
+}
+
+// IsAccessible reports whether screen-reader friendly output is enabled
+func IsAccessible() bool {
+	return accessible
+}
+
+// marker returns the emoji symbol, its word equivalent in accessible mode, or
+// its ASCII equivalent in plain mode
+func marker(symbol, word string) string {
+	if accessible {
+		return word
+	}
+	if plain {
+		return asciiMarkers[word]
+	}
+	return symbol
+}
+
+// Plain strips emoji and box-drawing characters from text in accessible and
+// plain mode. In normal mode the text is returned unchanged.
+func Plain(text string) string {
+	if !undecorated() {
+		return text
+	}
+
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range text {
+		if isDecorativeRune(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// statusSymbols maps status emoji to the markers used in accessible mode
+var statusSymbols = []struct {
+	re   *regexp.Regexp
+	word string
+}{
+	{regexp.MustCompile(`[✅✓✔]\x{FE0F}?\s*`), "OK:"},
+	{regexp.MustCompile(`[❌✗✘]\x{FE0F}?\s*`), "ERROR:"},
+	{regexp.MustCompile(`⚠\x{FE0F}?\s*`), "WARN:"},
+}
+
+// wordify replaces status emoji with OK/ERROR/WARN (or [ok]/[err]/[warn] in
+// plain mode) and strips the remaining decoration. Used for free-form output
+// that has no marker.
+func wordify(text string) string {
+	if !undecorated() {
+		return text
+	}
+	for _, sym := range statusSymbols {
+		text = sym.re.ReplaceAllString(text, marker("", sym.word)+" ")
+	}
+	return Plain(text)
+}
+
+// isDecorativeRune reports whether r is an emoji, pictograph or box-drawing character
+func isDecorativeRune(r rune) bool {
+	switch {
+	case r == 0x200D, r == 0xFE0E, r == 0xFE0F: // zero-width joiner, variation selectors
+		return true
+	case r == 0x2139: // ℹ
+		return true
+	case r >= 0x2300 && r <= 0x23FF: // misc technical (⏳, ⏱)
+		return true
+	case r >= 0x2500 && r <= 0x259F: // box drawing and block elements (━, │)
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols and dingbats (⚠, ✓, ✗, ✨)
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // misc symbols and arrows (⭐)
+		return true
+	case r >= 0x1F000 && r <= 0x1FAFF: // emoji and pictographs
+		return true
+	}
+	return false
+}
+
+// Printf is fmt.Printf that spells out status emoji in accessible mode
+func Printf(format string, args ...interface{}) {
+	text := wordify(fmt.Sprintf(format, args...))
+	remember(text)
+	fmt.Print(text)
+}
+
+// Println is fmt.Println that spells out status emoji in accessible mode
+func Println(args ...interface{}) {
+	text := wordify(fmt.Sprintln(args...))
+	remember(text)
+	fmt.Print(text)
+}
+
+// Separator prints a horizontal rule of the given width.
+// In accessible mode it prints nothing, since screen readers announce each
+// character, and in plain mode an ASCII rule.
+func Separator(width int) {
+	if accessible {
+		return
+	}
+	if plain {
+		fmt.Println(strings.Repeat("-", width))
+		return
+	}
+	fmt.Println(strings.Repeat("━", width))
+}
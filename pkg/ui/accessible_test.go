@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// withAccessible enables accessible mode for the duration of a test
+func withAccessible(t *testing.T) {
+	t.Helper()
+	SetAccessible(true)
+	t.Cleanup(func() { accessible = false })
+}
+
+func TestPlain(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"✨ Auto-detected from current directory", "Auto-detected from current directory"},
+		{"⚠️  Continuing to next task", "Continuing to next task"},
+		{"🟢 Running", "Running"},
+		{"feature/x → feature-x", "feature/x → feature-x"},
+		{"  ✗ Step 1 (build): command is empty", "  Step 1 (build): command is empty"},
+		{"no symbols here", "no symbols here"},
+	}
+
+	t.Run("normal mode leaves text unchanged", func(t *testing.T) {
+		for _, tt := range tests {
+			if got := Plain(tt.input); got != tt.input {
+				t.Errorf("Plain(%q) = %q, want unchanged", tt.input, got)
+			}
+		}
+	})
+
+	t.Run("accessible mode strips decoration", func(t *testing.T) {
+		withAccessible(t)
+		for _, tt := range tests {
+			if got := Plain(tt.input); got != tt.want {
+				t.Errorf("Plain(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestAccessibleMarkers(t *testing.T) {
+	withAccessible(t)
+
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{"success", func() { Success("done") }, "OK: done"},
+		{"error", func() { Error("❌ broke") }, "ERROR: broke"},
+		{"warning", func() { Warning("careful") }, "WARN: careful"},
+		{"info", func() { Info("✨ note") }, "INFO: note"},
+		{"checkmark", func() { CheckMark("ready") }, "  OK: ready"},
+		{"crossmark", func() { CrossMark("missing") }, "  ERROR: missing"},
+		{"loading", func() { Loading("Starting backend...") }, "WORKING: Starting backend..."},
+		{"rocket", func() { Rocket("Launching") }, "Launching"},
+		{"printf status emoji", func() { Printf("        ✅ Passed\n") }, "        OK: Passed"},
+		{"println warning emoji", func() { Println("⚠️  Continuing") }, "WARN: Continuing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := captureOutput(tt.fn)
+			if strings.TrimRight(output, "\n") != tt.want {
+				t.Errorf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeparatorAccessible(t *testing.T) {
+	if output := captureOutput(func() { Separator(10) }); !strings.Contains(output, "━") {
+		t.Errorf("expected separator line, got %q", output)
+	}
+
+	withAccessible(t)
+	if output := captureOutput(func() { Separator(10) }); output != "" {
+		t.Errorf("expected no separator in accessible mode, got %q", output)
+	}
+}
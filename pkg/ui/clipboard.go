@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ClipboardEnv overrides the clipboard command, e.g. "tmux load-buffer -" on
+// a remote machine; the text is written to its stdin
+const ClipboardEnv = "WORKTREE_CLIPBOARD"
+
+// clipboardCommands lists the clipboard tools tried per OS, in order
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux": {
+		{"wl-copy"}, // Wayland sessions only (see ClipboardCommand)
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"clip.exe"}, // WSL
+	},
+}
+
+// ClipboardCommand returns the command that copies its stdin to the system
+// clipboard: the WORKTREE_CLIPBOARD override, or the first available tool of
+// the OS (pbcopy, clip, wl-copy, xclip, xsel, clip.exe)
+func ClipboardCommand() ([]string, error) {
+	return clipboardCommand(runtime.GOOS, os.Getenv, exec.LookPath)
+}
+
+func clipboardCommand(goos string, getenv func(string) string, lookPath func(string) (string, error)) ([]string, error) {
+	if override := strings.Fields(getenv(ClipboardEnv)); len(override) > 0 {
+		return override, nil
+	}
+
+	candidates, ok := clipboardCommands[goos]
+	if !ok {
+		candidates = clipboardCommands["linux"]
+	}
+	var tried []string
+	for _, candidate := range candidates {
+		if candidate[0] == "wl-copy" && getenv("WAYLAND_DISPLAY") == "" {
+			continue
+		}
+		tried = append(tried, candidate[0])
+		if _, err := lookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard tool found (tried %s); install one or set %s", strings.Join(tried, ", "), ClipboardEnv)
+}
+
+// CopyToClipboard puts text on the system clipboard
+func CopyToClipboard(text string) error {
+	command, err := ClipboardCommand()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", command[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
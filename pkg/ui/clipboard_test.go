@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClipboardCommand(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		env       map[string]string
+		installed []string
+		want      string
+		wantErr   string
+	}{
+		{"macOS", "darwin", nil, []string{"pbcopy"}, "pbcopy", ""},
+		{"windows", "windows", nil, []string{"clip"}, "clip", ""},
+		{"wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"wl-copy", "xclip"}, "wl-copy", ""},
+		{"x11 skips wl-copy", "linux", nil, []string{"wl-copy", "xclip"}, "xclip -selection clipboard", ""},
+		{"xsel fallback", "linux", nil, []string{"xsel"}, "xsel --clipboard --input", ""},
+		{"wsl", "linux", nil, []string{"clip.exe"}, "clip.exe", ""},
+		{"other unix", "freebsd", nil, []string{"xclip"}, "xclip -selection clipboard", ""},
+		{"override", "linux", map[string]string{ClipboardEnv: "tmux load-buffer -"}, nil, "tmux load-buffer -", ""},
+		{"none", "linux", nil, nil, "", "no clipboard tool found (tried xclip, xsel, clip.exe)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			lookPath := func(name string) (string, error) {
+				for _, installed := range tt.installed {
+					if installed == name {
+						return "/usr/bin/" + name, nil
+					}
+				}
+				return "", errors.New("not found")
+			}
+
+			got, err := clipboardCommand(tt.goos, getenv, lookPath)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("clipboardCommand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || strings.Join(got, " ") != tt.want {
+				t.Errorf("clipboardCommand() = %v, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
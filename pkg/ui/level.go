@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Level controls how much the CLI prints besides errors, warnings and the
+// data a command was asked for
+type Level int
+
+const (
+	LevelQuiet   Level = iota // No progress, success or info messages
+	LevelNormal               // Default output
+	LevelVerbose              // Also details and the git/docker commands being run
+)
+
+// Verbose scopes: the subsystems --verbose=<scopes> and WORKTREE_DEBUG trace
+const (
+	ScopeConfig   = "config"   // Configuration loading and feature name resolution
+	ScopeDocker   = "docker"   // Container runtime and compose commands, service start
+	ScopeGit      = "git"      // git commands
+	ScopeRegistry = "registry" // Registry loading and port allocation decisions
+)
+
+// Scopes lists the verbose scopes
+var Scopes = []string{ScopeConfig, ScopeDocker, ScopeGit, ScopeRegistry}
+
+// level is the current output level, set once from --quiet, --verbose,
+// WORKTREE_DEBUG or WORKTREE_LOG_LEVEL
+var level = LevelNormal
+
+// verboseScopes limits verbose output to some scopes; nil traces all of them
+var verboseScopes map[string]bool
+
+// SetLevel sets the output level
+func SetLevel(l Level) {
+	level = l
+}
+
+// IsQuiet reports whether progress and info messages are suppressed
+func IsQuiet() bool {
+	return level == LevelQuiet
+}
+
+// SetVerboseScopes limits verbose output to the given scopes (nil for all)
+func SetVerboseScopes(scopes map[string]bool) {
+	verboseScopes = scopes
+}
+
+// IsVerbose reports whether details and underlying commands are shown
+func IsVerbose() bool {
+	return level == LevelVerbose
+}
+
+// IsVerboseFor reports whether details of a scope are shown
+func IsVerboseFor(scope string) bool {
+	return IsVerbose() && (verboseScopes == nil || verboseScopes[scope])
+}
+
+// ParseScopes parses a comma-separated list of verbose scopes, e.g.
+// "git,registry". An empty value, "all" or "1" selects every scope (nil).
+func ParseScopes(value string) (map[string]bool, error) {
+	scopes := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+		case name == "all" || name == "1" || name == "true":
+			return nil, nil
+		case slices.Contains(Scopes, name):
+			scopes[name] = true
+		default:
+			return nil, fmt.Errorf("unknown verbose scope '%s' (expected %s or all)", name, strings.Join(Scopes, ", "))
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, nil
+	}
+	return scopes, nil
+}
+
+// ParseLevel parses a WORKTREE_LOG_LEVEL value: quiet, normal or verbose
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "quiet":
+		return LevelQuiet, nil
+	case "", "normal":
+		return LevelNormal, nil
+	case "verbose":
+		return LevelVerbose, nil
+	default:
+		return LevelNormal, fmt.Errorf("unknown log level '%s' (expected quiet, normal or verbose)", name)
+	}
+}
+
+// Verbose prints an info message of a scope in verbose mode only, e.g.
+// "[registry] Allocated ports: ..."
+func Verbose(scope, message string) {
+	if IsVerboseFor(scope) {
+		Info(fmt.Sprintf("[%s] %s", scope, message))
+	}
+}
+
+// Trace shows a command of a scope about to run in verbose mode. It goes to
+// stderr so it never mixes with output that scripts parse.
+func Trace(scope string, cmd *exec.Cmd) {
+	if !IsVerboseFor(scope) {
+		return
+	}
+	line := "$ " + strings.Join(cmd.Args, " ")
+	if cmd.Dir != "" {
+		line += fmt.Sprintf("  (in %s)", cmd.Dir)
+	}
+	remember(line)
+	fmt.Fprintln(os.Stderr, magenta(line))
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// captureStderr captures stderr during function execution
+func captureStderr(f func()) string {
+	old := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	f()
+
+	w.Close()
+	os.Stderr = old
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	return buf.String()
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Level
+		wantErr bool
+	}{
+		{"", LevelNormal, false},
+		{"normal", LevelNormal, false},
+		{"quiet", LevelQuiet, false},
+		{"VERBOSE", LevelVerbose, false},
+		{"debug", LevelNormal, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestQuietSuppressesProgress(t *testing.T) {
+	SetLevel(LevelQuiet)
+	defer SetLevel(LevelNormal)
+
+	output := captureOutput(func() {
+		Section("Allocating ports...")
+		Info("info message")
+		Success("success message")
+		CheckMark("check message")
+		Loading("loading message")
+		StartSpinner("spinner message").Stop()
+		Warning("warning message")
+		Error("error message")
+	})
+	for _, hidden := range []string{"Allocating", "info message", "success message", "check message", "loading message", "spinner message"} {
+		if strings.Contains(output, hidden) {
+			t.Errorf("quiet mode printed %q: %s", hidden, output)
+		}
+	}
+	for _, shown := range []string{"warning message", "error message"} {
+		if !strings.Contains(output, shown) {
+			t.Errorf("quiet mode hid %q: %s", shown, output)
+		}
+	}
+}
+
+func TestVerboseAndTrace(t *testing.T) {
+	cmd := exec.Command("git", "status")
+	cmd.Dir = "/tmp/repo"
+
+	output := captureOutput(func() { Verbose(ScopeConfig, "detail") })
+	stderr := captureStderr(func() { Trace(ScopeGit, cmd) })
+	if output != "" || stderr != "" {
+		t.Errorf("expected nothing at normal level, got %q and %q", output, stderr)
+	}
+
+	SetLevel(LevelVerbose)
+	defer SetLevel(LevelNormal)
+	output = captureOutput(func() { Verbose(ScopeConfig, "detail") })
+	stderr = captureStderr(func() { Trace(ScopeGit, cmd) })
+	if !strings.Contains(output, "[config] detail") {
+		t.Errorf("expected verbose detail, got %q", output)
+	}
+	if !strings.Contains(stderr, "$ git status") || !strings.Contains(stderr, "/tmp/repo") {
+		t.Errorf("expected traced command on stderr, got %q", stderr)
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	for _, all := range []string{"", "all", "1", " , "} {
+		if scopes, err := ParseScopes(all); err != nil || scopes != nil {
+			t.Errorf("ParseScopes(%q) = %v, %v; want all scopes", all, scopes, err)
+		}
+	}
+
+	scopes, err := ParseScopes("git, Registry")
+	if err != nil || len(scopes) != 2 || !scopes[ScopeGit] || !scopes[ScopeRegistry] {
+		t.Errorf("ParseScopes() = %v, %v; want git and registry", scopes, err)
+	}
+
+	if _, err := ParseScopes("git,compose"); err == nil || !strings.Contains(err.Error(), "unknown verbose scope 'compose'") {
+		t.Errorf("ParseScopes() error = %v, want unknown scope", err)
+	}
+}
+
+func TestVerboseScopes(t *testing.T) {
+	SetLevel(LevelVerbose)
+	SetVerboseScopes(map[string]bool{ScopeRegistry: true})
+	defer func() {
+		SetLevel(LevelNormal)
+		SetVerboseScopes(nil)
+	}()
+
+	output := captureOutput(func() {
+		Verbose(ScopeRegistry, "allocated port")
+		Verbose(ScopeDocker, "compose noise")
+	})
+	stderr := captureStderr(func() { Trace(ScopeGit, exec.Command("git", "status")) })
+	if !strings.Contains(output, "[registry] allocated port") {
+		t.Errorf("expected registry detail, got %q", output)
+	}
+	if strings.Contains(output, "compose noise") || stderr != "" {
+		t.Errorf("expected nothing from other scopes, got %q and %q", output, stderr)
+	}
+}
@@ -1,6 +1,8 @@
 // Package ui provides colored terminal output utilities for the worktree manager CLI.
 // It includes functions for printing success, error, warning, and informational messages
-// with consistent formatting and emoji indicators.
+// with consistent formatting and emoji indicators, or ASCII markers in plain mode
+// (see SetPlain). In quiet mode (see SetLevel) only
+// errors, warnings and data output are printed.
 package ui
 
 import (
@@ -23,58 +25,95 @@ var (
 
 // Success prints a success message
 func Success(message string) {
-	fmt.Printf("%s %s\n", green("✅"), message)
+	remember(message)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("%s %s\n", green(marker("✅", "OK:")), Plain(message))
 }
 
 // Error prints an error message
 func Error(message string) {
-	fmt.Printf("%s %s\n", red("❌"), message)
+	remember(message)
+	fmt.Printf("%s %s\n", red(marker("❌", "ERROR:")), Plain(message))
 }
 
 // Warning prints a warning message
 func Warning(message string) {
-	fmt.Printf("%s %s\n", yellow("⚠️ "), message)
+	remember(message)
+	fmt.Printf("%s %s\n", yellow(marker("⚠️ ", "WARN:")), Plain(message))
 }
 
 // Info prints an info message
 func Info(message string) {
-	fmt.Printf("%s %s\n", blue("ℹ️ "), message)
+	remember(message)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("%s %s\n", blue(marker("ℹ️ ", "INFO:")), Plain(message))
 }
 
 // Section prints a section header
 func Section(title string) {
+	remember(title)
+	if IsQuiet() {
+		return
+	}
+	if undecorated() {
+		fmt.Printf("\n%s\n\n", Plain(title))
+		return
+	}
 	fmt.Printf("\n%s %s\n\n", cyan("»"), bold(title))
 }
 
 // Rocket prints a message with a rocket emoji
 func Rocket(message string) {
+	if IsQuiet() {
+		return
+	}
+	if undecorated() {
+		fmt.Println(Plain(message))
+		return
+	}
 	fmt.Printf("%s %s\n", "🚀", message)
 }
 
 // Loading prints a loading message
 func Loading(message string) {
-	fmt.Printf("%s %s\n", "⏳", message)
+	remember(message)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("%s %s\n", marker("⏳", "WORKING:"), Plain(message))
 }
 
 // CheckMark prints a check mark with a message
 func CheckMark(message string) {
-	fmt.Printf("  %s %s\n", green("✅"), message)
+	remember(message)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("  %s %s\n", green(marker("✅", "OK:")), Plain(message))
 }
 
 // CrossMark prints a cross mark with a message
 func CrossMark(message string) {
-	fmt.Printf("  %s %s\n", red("❌"), message)
+	remember(message)
+	fmt.Printf("  %s %s\n", red(marker("❌", "ERROR:")), Plain(message))
 }
 
 // ShowPortsFromConfig displays port mapping from configuration
 func ShowPortsFromConfig(hostname string, instance int, ports map[string]int, portConfigs map[string]config.EnvVarConfig) {
+	if IsQuiet() {
+		return
+	}
 	if len(portConfigs) == 0 {
 		// Fallback to showing instance number only
-		fmt.Printf("\n%s Instance %d configured\n\n", "📍", instance)
+		fmt.Printf("\n%s\n\n", Plain(fmt.Sprintf("📍 Instance %d configured", instance)))
 		return
 	}
 
-	fmt.Printf("\n%s Services (Instance %d):\n", "📍", instance)
+	fmt.Printf("\n%s\n", Plain(fmt.Sprintf("📍 Services (Instance %d):", instance)))
 
 	// Display ports in order (if config preserves order, or alphabetically)
 	// Skip entries without a name (used only for env var export)
@@ -104,12 +143,15 @@ func ShowPortsFromConfig(hostname string, instance int, ports map[string]int, po
 
 // PrintHeader prints a header message
 func PrintHeader(message string) {
-	fmt.Printf("\n%s\n", bold(message))
+	fmt.Printf("\n%s\n", bold(Plain(message)))
 }
 
 // PrintStep prints a numbered step
 func PrintStep(number int, message string) {
-	fmt.Printf("   %s %s\n", cyan(fmt.Sprintf("%d.", number)), message)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("   %s %s\n", cyan(fmt.Sprintf("%d.", number)), Plain(message))
 }
 
 // PrintCommand prints a command to run
@@ -119,32 +161,44 @@ func PrintCommand(command string) {
 
 // PrintNextSteps prints next steps section
 func PrintNextSteps() {
+	if IsQuiet() {
+		return
+	}
 	fmt.Printf("\n%s\n", bold("Next steps:"))
 }
 
 // PrintStatusLine prints a status line with label and value
 func PrintStatusLine(label, value string) {
-	fmt.Printf("  %s %s\n", cyan(label+":"), value)
+	fmt.Printf("  %s %s\n", cyan(Plain(label)+":"), Plain(value))
 }
 
 // PrintTable prints a simple table row
 func PrintTable(col1, col2 string) {
-	fmt.Printf("%-20s %s\n", col1, col2)
+	fmt.Printf("%-20s %s\n", Plain(col1), Plain(col2))
 }
 
 // NewLine prints a new line
 func NewLine() {
+	if IsQuiet() {
+		return
+	}
 	fmt.Println()
 }
 
 // Progress prints a progress indicator with current/total counts
 func Progress(current, total int, message string) {
-	fmt.Printf("%s %s... (%d/%d)\n", "⏳", message, current, total)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("%s %s... (%d/%d)\n", marker("⏳", "WORKING:"), Plain(message), current, total)
 }
 
 // ProgressWithName prints a progress indicator for a named item
 func ProgressWithName(current, total int, itemName, action string) {
-	fmt.Printf("%s %s %s... (%d/%d)\n", "⏳", action, itemName, current, total)
+	if IsQuiet() {
+		return
+	}
+	fmt.Printf("%s %s %s... (%d/%d)\n", marker("⏳", "WORKING:"), Plain(action), itemName, current, total)
 }
 
 // Bold returns a bold-formatted string
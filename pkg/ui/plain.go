@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+)
+
+// plain switches output to ASCII for CI logs and pipes: no emoji or colors,
+// and [ok]/[warn]/[err] instead of status symbols. Accessible mode, which
+// spells markers out as words, takes precedence.
+var plain bool
+
+// asciiMarkers maps the accessible-mode marker words to their plain-mode markers
+var asciiMarkers = map[string]string{
+	"OK:":      "[ok]",
+	"ERROR:":   "[err]",
+	"WARN:":    "[warn]",
+	"INFO:":    "[info]",
+	"WORKING:": "[..]",
+}
+
+// SetPlain turns plain ASCII output on or off
+func SetPlain(enabled bool) {
+	plain = enabled
+	if enabled {
+		color.NoColor = true
+	}
+}
+
+// IsPlain reports whether plain ASCII output is enabled
+func IsPlain() bool {
+	return plain
+}
+
+// StdoutIsTerminal reports whether stdout is an interactive terminal
+func StdoutIsTerminal() bool {
+	return stdoutIsTerminal()
+}
+
+// NoColorRequested reports whether the NO_COLOR convention (https://no-color.org)
+// asks for output without colors
+func NoColorRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// undecorated reports whether emoji and box-drawing characters are left out
+func undecorated() bool {
+	return accessible || plain
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// withPlain enables plain mode for the duration of a test
+func withPlain(t *testing.T) {
+	t.Helper()
+	SetPlain(true)
+	t.Cleanup(func() { plain = false })
+}
+
+func TestPlainMarkers(t *testing.T) {
+	withPlain(t)
+
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{"success", func() { Success("done") }, "[ok] done"},
+		{"error", func() { Error("❌ broke") }, "[err] broke"},
+		{"warning", func() { Warning("careful") }, "[warn] careful"},
+		{"info", func() { Info("✨ note") }, "[info] note"},
+		{"checkmark", func() { CheckMark("ready") }, "  [ok] ready"},
+		{"crossmark", func() { CrossMark("missing") }, "  [err] missing"},
+		{"loading", func() { Loading("Starting backend...") }, "[..] Starting backend..."},
+		{"rocket", func() { Rocket("Launching") }, "Launching"},
+		{"section", func() { Section("🐳 DOCKER") }, "\nDOCKER"},
+		{"printf status emoji", func() { Printf("        ✅ Passed\n") }, "        [ok] Passed"},
+		{"println warning emoji", func() { Println("⚠️  Continuing") }, "[warn] Continuing"},
+		{"separator", func() { Separator(5) }, "-----"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := captureOutput(tt.fn)
+			if strings.TrimRight(output, "\n") != tt.want {
+				t.Errorf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessibleTakesPrecedenceOverPlain(t *testing.T) {
+	withPlain(t)
+	withAccessible(t)
+
+	if output := captureOutput(func() { Warning("careful") }); strings.TrimRight(output, "\n") != "WARN: careful" {
+		t.Errorf("output = %q, want accessible marker", output)
+	}
+	if output := captureOutput(func() { Separator(5) }); output != "" {
+		t.Errorf("expected no separator in accessible mode, got %q", output)
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	// assumeYes answers every confirmation with yes (--yes)
+	assumeYes bool
+	// nonInteractive answers every confirmation with its default (--non-interactive)
+	nonInteractive bool
+
+	// promptInput and stdinIsTerminal are replaced in tests
+	promptInput     io.Reader = os.Stdin
+	stdinIsTerminal           = func() bool {
+		info, err := os.Stdin.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0
+	}
+)
+
+// SetAssumeYes makes Confirm answer yes without asking
+func SetAssumeYes(enabled bool) {
+	assumeYes = enabled
+}
+
+// AssumingYes reports whether confirmations are answered with yes without asking
+func AssumingYes() bool {
+	return assumeYes
+}
+
+// SetNonInteractive makes Confirm answer with its default without asking
+func SetNonInteractive(enabled bool) {
+	nonInteractive = enabled
+}
+
+// Confirm asks a yes/no question and returns the answer. An empty answer (or
+// end of input) gives defaultYes; anything but y/yes counts as no.
+//
+// Without a terminal on stdin, or with --non-interactive, the question is
+// printed with defaultYes as the answer instead of waiting for input. --yes
+// answers yes in every case.
+func Confirm(question string, defaultYes bool) bool {
+	hint := "[y/N]"
+	if defaultYes {
+		hint = "[Y/n]"
+	}
+	fmt.Printf("%s %s: ", Plain(question), hint)
+
+	switch {
+	case assumeYes:
+		fmt.Println("y (--yes)")
+		return true
+	case nonInteractive || !stdinIsTerminal():
+		fmt.Printf("%s (non-interactive; use --yes to confirm)\n", answerWord(defaultYes))
+		return defaultYes
+	}
+
+	response, err := bufio.NewReader(promptInput).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "" {
+		if err != nil {
+			fmt.Println()
+		}
+		return defaultYes
+	}
+	return response == "y" || response == "yes"
+}
+
+func answerWord(yes bool) string {
+	if yes {
+		return "y"
+	}
+	return "n"
+}
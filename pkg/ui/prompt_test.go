@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+// withPrompt feeds input to Confirm as if typed on a terminal (or not) and
+// restores the prompt settings afterwards
+func withPrompt(t *testing.T, input string, terminal bool) {
+	t.Helper()
+	oldInput, oldTerminal := promptInput, stdinIsTerminal
+	promptInput = strings.NewReader(input)
+	stdinIsTerminal = func() bool { return terminal }
+	t.Cleanup(func() {
+		promptInput, stdinIsTerminal = oldInput, oldTerminal
+		assumeYes, nonInteractive = false, false
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		terminal       bool
+		defaultYes     bool
+		assumeYes      bool
+		nonInteractive bool
+		want           bool
+		wantOutput     string
+	}{
+		{name: "yes", input: "y\n", terminal: true, want: true, wantOutput: "Proceed? [y/N]: "},
+		{name: "yes word, mixed case", input: "  Yes \n", terminal: true, want: true},
+		{name: "no", input: "n\n", terminal: true, defaultYes: true, want: false, wantOutput: "Proceed? [Y/n]: "},
+		{name: "other answer is no", input: "maybe\n", terminal: true, defaultYes: true, want: false},
+		{name: "empty takes default no", input: "\n", terminal: true, want: false},
+		{name: "empty takes default yes", input: "\n", terminal: true, defaultYes: true, want: true},
+		{name: "end of input takes default", input: "", terminal: true, defaultYes: true, want: true},
+		{name: "not a terminal takes default", input: "y\n", want: false, wantOutput: "n (non-interactive"},
+		{name: "non-interactive takes default", input: "n\n", terminal: true, defaultYes: true, nonInteractive: true, want: true},
+		{name: "assume yes", input: "n\n", terminal: true, assumeYes: true, want: true, wantOutput: "y (--yes)"},
+		{name: "assume yes wins over non-interactive", assumeYes: true, nonInteractive: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withPrompt(t, tt.input, tt.terminal)
+			SetAssumeYes(tt.assumeYes)
+			SetNonInteractive(tt.nonInteractive)
+
+			var got bool
+			output := captureOutput(func() {
+				got = Confirm("Proceed?", tt.defaultYes)
+			})
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(output, tt.wantOutput) {
+				t.Errorf("output = %q, want it to contain %q", output, tt.wantOutput)
+			}
+		})
+	}
+}
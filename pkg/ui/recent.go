@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+)
+
+// recentLimit is how many of the last printed lines are kept for crash dumps
+const recentLimit = 50
+
+var (
+	recentMu sync.Mutex
+	recent   []string
+)
+
+// remember keeps the lines of text among the recently printed ones
+func remember(text string) {
+	text = strings.TrimRight(text, "\n")
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	recent = append(recent, strings.Split(text, "\n")...)
+	if len(recent) > recentLimit {
+		recent = append([]string(nil), recent[len(recent)-recentLimit:]...)
+	}
+}
+
+// RecentLines returns the last lines printed through this package, oldest
+// first, without colors
+func RecentLines() []string {
+	recentMu.Lock()
+	defer recentMu.Unlock()
+	return append([]string(nil), recent...)
+}
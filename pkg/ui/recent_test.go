@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRecentLines(t *testing.T) {
+	recent = nil
+	t.Cleanup(func() { recent = nil })
+
+	captureOutput(func() {
+		Info("first")
+		Printf("two\nlines\n")
+		Println()
+	})
+	got := RecentLines()
+	want := []string{"first", "two", "lines"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("RecentLines() = %q, want %q", got, want)
+	}
+
+	captureOutput(func() {
+		for i := 0; i < recentLimit+10; i++ {
+			Printf("line %d\n", i)
+		}
+	})
+	got = RecentLines()
+	if len(got) != recentLimit {
+		t.Fatalf("len(RecentLines()) = %d, want %d", len(got), recentLimit)
+	}
+	if last := fmt.Sprintf("line %d", recentLimit+9); got[len(got)-1] != last {
+		t.Errorf("last line = %q, want %q", got[len(got)-1], last)
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// stdoutIsTerminal is replaced in tests
+var stdoutIsTerminal = func() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// Spinner animates a loading message while a long command runs. When stdout
+// is not a terminal (or in accessible or plain mode) it prints the message once instead,
+// and in quiet mode nothing.
+type Spinner struct {
+	message string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// StartSpinner prints message with an animated spinner until Stop is called
+func StartSpinner(message string) *Spinner {
+	s := &Spinner{message: message}
+	if IsQuiet() {
+		return s
+	}
+	if undecorated() || !stdoutIsTerminal() {
+		Loading(message)
+		return s
+	}
+
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			fmt.Printf("\r%s %s", cyan(spinnerFrames[frame%len(spinnerFrames)]), message)
+			select {
+			case <-s.stop:
+				fmt.Print("\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// Stop stops the animation and clears its line
+func (s *Spinner) Stop() {
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.stop = nil
+}
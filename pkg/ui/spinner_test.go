@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpinnerWithoutTerminal(t *testing.T) {
+	old := stdoutIsTerminal
+	stdoutIsTerminal = func() bool { return false }
+	t.Cleanup(func() { stdoutIsTerminal = old })
+
+	output := captureOutput(func() {
+		s := StartSpinner("Building images")
+		s.Stop()
+		s.Stop()
+	})
+	if strings.Count(output, "Building images") != 1 || strings.Contains(output, "\r") {
+		t.Errorf("expected a single plain loading line, got %q", output)
+	}
+}
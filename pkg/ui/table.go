@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table renders rows as aligned columns under an upper-case header, e.g.
+//
+//	ID        STATUS   AGENT
+//	3f2a9c1e  pending  npm-audit
+//
+// Cells are printed as-is; the last column is not padded.
+type Table struct {
+	headers []string
+	rows    [][]string
+	maxCell int
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers}
+}
+
+// Truncate limits cells to n characters, ending cut cells with "..." (0 = no limit)
+func (t *Table) Truncate(n int) *Table {
+	t.maxCell = n
+	return t
+}
+
+// AddRow appends a row; missing cells are empty, extra cells are dropped
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.headers))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Print renders the table to stdout
+func (t *Table) Print() {
+	t.Render(os.Stdout)
+}
+
+// Render writes the table to w
+func (t *Table) Render(w io.Writer) {
+	rows := make([][]string, 0, len(t.rows)+1)
+	rows = append(rows, t.headers)
+	for _, row := range t.rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = t.cell(cell)
+		}
+		rows = append(rows, cells)
+	}
+
+	widths := make([]int, len(t.headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for _, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				line.WriteString(cell)
+				break
+			}
+			line.WriteString(cell)
+			line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+		}
+		fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+	}
+}
+
+// cell prepares a cell for a single table line
+func (t *Table) cell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if t.maxCell > 3 && utf8.RuneCountInString(value) > t.maxCell {
+		runes := []rune(value)
+		value = string(runes[:t.maxCell-3]) + "..."
+	}
+	return value
+}
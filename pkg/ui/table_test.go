@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableRender(t *testing.T) {
+	table := NewTable("ID", "STATUS", "ERROR").Truncate(12)
+	table.AddRow("3f2a9c1e", "pending")
+	table.AddRow("b1", "failed", "exit status 1:\ngate lint failed")
+
+	var buf bytes.Buffer
+	table.Render(&buf)
+
+	want := "ID        STATUS   ERROR\n" +
+		"3f2a9c1e  pending\n" +
+		"b1        failed   exit stat...\n"
+	if buf.String() != want {
+		t.Errorf("Render() =\n%s\nwant\n%s", buf.String(), want)
+	}
+	if table.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", table.Len())
+	}
+}
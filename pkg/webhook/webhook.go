@@ -0,0 +1,116 @@
+// Package webhook delivers feature lifecycle events to the webhooks configured
+// in .worktree.yml, so external automation (dashboards, DNS) can react to
+// features being created, started, stopped and removed without polling the
+// registry.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/braunmar/worktree/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+// Request headers of a delivery
+const (
+	HeaderEvent     = "X-Worktree-Event"
+	HeaderDelivery  = "X-Worktree-Delivery"
+	HeaderSignature = "X-Worktree-Signature" // "sha256=" + hex HMAC-SHA256 of the body, keyed with the webhook secret
+)
+
+// Timeout bounds each delivery so an unreachable endpoint cannot stall the CLI
+const Timeout = 5 * time.Second
+
+// Event is the JSON body of a delivery
+type Event struct {
+	ID        string            `json:"id"`
+	Event     string            `json:"event"` // created, started, stopped or removed
+	Timestamp time.Time         `json:"timestamp"`
+	Project   string            `json:"project"` // project_name of .worktree.yml
+	Host      string            `json:"host"`    // Machine the event happened on
+	Feature   string            `json:"feature"`
+	Branch    string            `json:"branch"`
+	Projects  []string          `json:"projects"`
+	Ports     map[string]int    `json:"ports,omitempty"`
+	URLs      map[string]string `json:"urls,omitempty"` // Service URLs by display name
+}
+
+// NewEvent creates an event with a fresh delivery ID, timestamp and host
+func NewEvent(event, project string) Event {
+	host, _ := os.Hostname()
+	return Event{
+		ID:        uuid.New().String(),
+		Event:     event,
+		Timestamp: time.Now().UTC(),
+		Project:   project,
+		Host:      host,
+	}
+}
+
+// Sign returns the signature header value of a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers the event to every webhook subscribed to it and returns one
+// error per failed delivery. Deliveries are not retried.
+func Send(projectRoot string, hooks []config.WebhookConfig, event Event) []error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return []error{fmt.Errorf("failed to marshal webhook event: %w", err)}
+	}
+
+	client := &http.Client{Timeout: Timeout}
+	var errs []error
+	for _, hook := range hooks {
+		if !hook.Wants(event.Event) {
+			continue
+		}
+		if err := deliver(client, projectRoot, hook, event, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", hook.URL, err))
+		}
+	}
+	return errs
+}
+
+// deliver posts one event to one webhook
+func deliver(client *http.Client, projectRoot string, hook config.WebhookConfig, event Event, body []byte) error {
+	secret, err := hook.ResolveSecret(projectRoot)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "worktree-webhook")
+	req.Header.Set(HeaderEvent, event.Event)
+	req.Header.Set(HeaderDelivery, event.ID)
+	if secret != "" {
+		req.Header.Set(HeaderSignature, Sign(secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
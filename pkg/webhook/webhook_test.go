@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/braunmar/worktree/pkg/config"
+)
+
+func TestSign(t *testing.T) {
+	// printf '{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494"
+	if got := Sign("secret", []byte(`{"a":1}`)); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestSend(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	var received []delivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, delivery{r.Header.Clone(), body})
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	hooks := []config.WebhookConfig{
+		{URL: server.URL + "/all", Secret: "s3cret"},
+		{URL: server.URL + "/removed-only", Events: []string{config.WebhookEventRemoved}},
+		{URL: server.URL + "/fail"},
+	}
+	event := NewEvent(config.WebhookEventCreated, "myproject")
+	event.Feature = "feature-x"
+	event.Ports = map[string]int{"APP_PORT": 9090}
+
+	errs := Send(t.TempDir(), hooks, event)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "/fail: endpoint returned 500") {
+		t.Errorf("errors = %v, want one for /fail", errs)
+	}
+	if len(received) != 2 {
+		t.Fatalf("received %d deliveries, want 2 (removed-only is not subscribed)", len(received))
+	}
+
+	signed := received[0]
+	if got := signed.header.Get(HeaderSignature); got != Sign("s3cret", signed.body) {
+		t.Errorf("signature = %q, want %q", got, Sign("s3cret", signed.body))
+	}
+	if signed.header.Get(HeaderEvent) != "created" || signed.header.Get(HeaderDelivery) != event.ID {
+		t.Errorf("headers = %v", signed.header)
+	}
+	var decoded Event
+	if err := json.Unmarshal(signed.body, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Feature != "feature-x" || decoded.Project != "myproject" || decoded.Ports["APP_PORT"] != 9090 {
+		t.Errorf("body = %+v", decoded)
+	}
+	if received[1].header.Get(HeaderSignature) != "" {
+		t.Error("deliveries without a secret should be unsigned")
+	}
+}
@@ -0,0 +1,137 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestArchiveRestore archives a feature with a volume, deletes its branch and
+// restores it, then restores a second archive whose ports were taken meanwhile.
+func TestArchiveRestore(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	// Generated files are git-ignored, as in a real project
+	os.WriteFile(filepath.Join(env.root, "backend", ".gitignore"), []byte(".env.local\n"), 0644)
+	env.gitRun(filepath.Join(env.root, "backend"), "add", ".gitignore")
+	env.gitRun(filepath.Join(env.root, "backend"), "commit", "-m", "ignore generated files")
+	env.writeConfig(worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "API_PORT={APP_PORT}"
+`)
+
+	// Mock docker: one compose volume per feature, volume dumps written to and
+	// read from the /backup mount
+	dockerLog := filepath.Join(env.binDir, "docker.log")
+	env.writeMockBinary("docker",
+		`log="`+dockerLog+`"`,
+		`case "$1 $2" in`,
+		`  "volume ls") printf 'testproject-feature-arch_db\tdb\n' ;;`,
+		`  "volume inspect") exit 1 ;;`,
+		`  "volume create") echo "$*" >> "$log" ;;`,
+		`esac`,
+		`if [ "$1" = "run" ]; then`,
+		`  for a in "$@"; do case "$a" in *:/backup) dir="${a%:/backup}" ;; /backup/*) file="${a#/backup/}" ;; esac; done`,
+		`  case "$*" in`,
+		`    *" cf "*) echo dbdata > "$dir/$file" ;;`,
+		`    *" xf "*) echo "import $file $(cat "$dir/$file")" >> "$log" ;;`,
+		`  esac`,
+		`fi`,
+	)
+
+	out, err := env.run("new-feature", "feature/arch")
+	assertSuccess(t, out, err)
+
+	backendWorktree := filepath.Join(env.root, "worktrees", "feature-arch", "backend")
+	env.gitRun(backendWorktree, "commit", "--allow-empty", "-m", "feature work")
+	archivePath := filepath.Join(env.root, "arch.tar.gz")
+
+	t.Run("uncommitted changes refused", func(t *testing.T) {
+		wip := filepath.Join(backendWorktree, "wip.txt")
+		if err := os.WriteFile(wip, []byte("wip"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(wip)
+
+		out, err := env.run("archive", "feature-arch", "-o", archivePath)
+		assertFailure(t, err)
+		assertContains(t, out, "Uncommitted changes")
+		if _, err := os.Stat(archivePath); !os.IsNotExist(err) {
+			t.Error("archive written despite refusal")
+		}
+	})
+
+	t.Run("archive removes feature", func(t *testing.T) {
+		out, err := env.run("archive", "feature-arch", "--volumes", "-o", archivePath)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Exported volume testproject-feature-arch_db")
+		assertContains(t, out, "1 volumes")
+
+		if _, err := os.Stat(backendWorktree); !os.IsNotExist(err) {
+			t.Error("feature directory still exists")
+		}
+		out, _ = env.run("list")
+		assertNotContains(t, out, "feature-arch")
+	})
+
+	t.Run("restore recreates deleted branch", func(t *testing.T) {
+		env.gitRun(filepath.Join(env.root, "backend"), "branch", "-D", "feature/arch")
+
+		out, err := env.run("restore", archivePath)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "recreated branch feature/arch")
+		assertContains(t, out, "Archived ports reused")
+		assertContains(t, out, "Restored volume testproject-feature-arch_db")
+
+		data, err := os.ReadFile(filepath.Join(backendWorktree, ".env.local"))
+		if err != nil || string(data) != "API_PORT=9090" {
+			t.Errorf(".env.local = %q, %v", data, err)
+		}
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-arch", ".worktree-instance")); err != nil {
+			t.Errorf("instance marker missing: %v", err)
+		}
+
+		log, _ := os.ReadFile(dockerLog)
+		assertContains(t, string(log), "volume create --label com.docker.compose.project=testproject-feature-arch --label com.docker.compose.volume=db testproject-feature-arch_db")
+		assertContains(t, string(log), "import testproject-feature-arch_db.tar dbdata")
+
+		out, err = env.run("list")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-arch")
+	})
+
+	t.Run("restore existing feature fails", func(t *testing.T) {
+		out, err := env.run("restore", archivePath)
+		assertFailure(t, err)
+		assertContains(t, out, "already exists")
+	})
+
+	t.Run("taken ports are reallocated", func(t *testing.T) {
+		out, err := env.run("archive", "feature-arch", "-o", archivePath+".2")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("new-feature", "feature/other")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("restore", archivePath+".2")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Cannot reuse archived ports")
+		assertContains(t, out, "APP_PORT port 9090 is allocated to feature-other")
+
+		data, _ := os.ReadFile(filepath.Join(backendWorktree, ".env.local"))
+		if !strings.HasPrefix(string(data), "API_PORT=909") || string(data) == "API_PORT=9090" {
+			t.Errorf(".env.local not re-rendered for new port: %q", data)
+		}
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		bogus := filepath.Join(env.root, "bogus.tar.gz")
+		os.WriteFile(bogus, []byte("nope"), 0644)
+		_, err := env.run("restore", bogus)
+		assertFailure(t, err)
+	})
+}
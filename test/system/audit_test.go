@@ -0,0 +1,53 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAgentAudit verifies that runs are recorded in history and that agent
+// audit reports missed scheduled runs and queues catch-up runs.
+func TestAgentAudit(t *testing.T) {
+	env := newTestEnv(t)
+	hourly := strings.Replace(validAgentYAML, `schedule: "0 9 * * MON"`, `schedule: "0 * * * *"
+    catch_up: true`, 1)
+	env.writeConfig(minimalConfig(hourly))
+
+	t.Run("missed runs reported", func(t *testing.T) {
+		out, err := env.run("agent", "audit", "--days", "1")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "valid-task: missed")
+		assertContains(t, out, "last run: never")
+		assertContains(t, out, "--enqueue")
+	})
+
+	t.Run("enqueue catch-up run once", func(t *testing.T) {
+		out, err := env.run("agent", "audit", "--days", "1", "--enqueue")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "catch-up run queued")
+
+		out, err = env.run("agent", "audit", "--days", "1", "--enqueue")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "already pending")
+
+		data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".queue.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(string(data), `"agent_name": "valid-task"`); n != 1 {
+			t.Errorf("expected 1 queued catch-up task, found %d:\n%s", n, data)
+		}
+	})
+
+	t.Run("manual run is recorded in history", func(t *testing.T) {
+		out, err := env.run("agent", "run", "valid-task")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("agent", "history", "list", "--agent", "valid-task", "--format", "wide")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "valid-task")
+		assertContains(t, out, "Status: completed")
+	})
+}
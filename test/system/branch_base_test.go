@@ -0,0 +1,75 @@
+package system_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewFeatureBranchBase covers where new-feature creates missing branches:
+// the project's main_branch by default, --from when given.
+func TestNewFeatureBranchBase(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	backend := filepath.Join(env.root, "backend")
+	revParse := func(dir, ref string) string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", dir, "rev-parse", ref).Output()
+		if err != nil {
+			t.Fatalf("git rev-parse %s in %s: %v", ref, dir, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	// The main checkout sits on a branch ahead of main, so HEAD is not main
+	env.gitRun(backend, "checkout", "-b", "release/1.0")
+	env.gitRun(backend, "commit", "--allow-empty", "-m", "release")
+	env.gitRun(backend, "checkout", "-b", "develop")
+	env.gitRun(backend, "commit", "--allow-empty", "-m", "develop")
+	env.gitRun(filepath.Join(env.root, "frontend"), "branch", "release/1.0")
+
+	t.Run("from main_branch by default", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/a")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "new branch from main")
+
+		if got, want := revParse(filepath.Join(env.root, "worktrees", "feature-a", "backend"), "HEAD"), revParse(backend, "main"); got != want {
+			t.Errorf("feature/a starts at %s, want main (%s)", got, want)
+		}
+	})
+
+	t.Run("from ref", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/b", "--from", "release/1.0")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "new branch from release/1.0")
+
+		if got, want := revParse(filepath.Join(env.root, "worktrees", "feature-b", "backend"), "HEAD"), revParse(backend, "release/1.0"); got != want {
+			t.Errorf("feature/b starts at %s, want release/1.0 (%s)", got, want)
+		}
+	})
+
+	t.Run("existing branch ignores from", func(t *testing.T) {
+		env.gitRun(backend, "branch", "feature/c", "develop")
+
+		out, err := env.run("new-feature", "feature/c", "--from", "release/1.0")
+		assertSuccess(t, out, err)
+
+		if got, want := revParse(filepath.Join(env.root, "worktrees", "feature-c", "backend"), "HEAD"), revParse(backend, "develop"); got != want {
+			t.Errorf("feature/c at %s, want its existing commit (%s)", got, want)
+		}
+	})
+
+	t.Run("unknown ref", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/d", "--from", "no-such-ref")
+		assertFailure(t, err)
+		assertContains(t, out, "ref 'no-such-ref' not found")
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-d")); err == nil {
+			t.Error("feature directory created despite unknown --from ref")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewFeatureBranchMap covers per-project branches from --branch-map and
+// branch_template while the feature stays a single registry entry.
+func TestNewFeatureBranchMap(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(strings.Replace(worktreeConfig(),
+		"    dir: \"frontend\"\n", "    dir: \"frontend\"\n    branch_template: \"{branch}-ui\"\n", 1))
+
+	checkedOut := func(feature, project string) string {
+		t.Helper()
+		cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+		cmd.Dir = filepath.Join(env.root, "worktrees", feature, project)
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git rev-parse in %s/%s: %v", feature, project, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	t.Run("map and template", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/x", "--branch-map", "backend=feature/x-api")
+		assertSuccess(t, out, err)
+
+		if got := checkedOut("feature-x", "backend"); got != "feature/x-api" {
+			t.Errorf("backend branch = %q, want feature/x-api", got)
+		}
+		if got := checkedOut("feature-x", "frontend"); got != "feature/x-ui" {
+			t.Errorf("frontend branch = %q, want feature/x-ui", got)
+		}
+
+		data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var reg struct {
+			Worktrees map[string]struct {
+				Branch   string            `json:"branch"`
+				Branches map[string]string `json:"branches"`
+			} `json:"worktrees"`
+		}
+		if err := json.Unmarshal(data, &reg); err != nil {
+			t.Fatal(err)
+		}
+		entry := reg.Worktrees["feature-x"]
+		if entry.Branch != "feature/x" || entry.Branches["backend"] != "feature/x-api" || entry.Branches["frontend"] != "feature/x-ui" {
+			t.Errorf("registry entry = %+v", entry)
+		}
+
+		out, err = env.run("status", "feature-x")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature/x-api")
+	})
+
+	t.Run("unknown project in map", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/y", "--branch-map", "mobile=feature/y-app")
+		assertFailure(t, err)
+		assertContains(t, out, "project 'mobile' is not part of the preset")
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-y")); err == nil {
+			t.Error("feature directory created despite invalid --branch-map")
+		}
+	})
+}
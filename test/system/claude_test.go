@@ -0,0 +1,53 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestClaudeLaunch verifies that worktree claude runs the claude CLI in the
+// Claude working directory with the feature's env, YOLO flag and context.
+func TestClaudeLaunch(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	argsFile := filepath.Join(env.root, "claude-args")
+	env.writeMockBinary("claude",
+		`echo "pwd=$(pwd)"`,
+		`echo "APP_PORT=$APP_PORT FEATURE_NAME=$FEATURE_NAME"`,
+		`printf '%s\n' "$@" > "`+argsFile+`"`,
+		`exit 3`,
+	)
+
+	out, err := env.run("new-feature", "feature/claude", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "worktree claude feature-claude")
+
+	out, err = env.run("claude", "feature-claude", "--yolo", "--prompt", "Fix the login test", "--", "--model", "opus")
+	assertFailure(t, err) // claude's exit code is passed through
+	assertContains(t, out, "pwd="+filepath.Join(env.root, "worktrees", "feature-claude", "backend"))
+	assertContains(t, out, "APP_PORT=9090 FEATURE_NAME=feature-claude")
+	assertContains(t, out, "YOLO Mode")
+
+	data, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := string(data)
+	assertContains(t, args, "--dangerously-skip-permissions\n--append-system-prompt\n")
+	assertContains(t, args, "- frontend: "+filepath.Join(env.root, "worktrees", "feature-claude", "frontend")+" (branch feature/claude)")
+	assertContains(t, args, "- APP_PORT=9090")
+	assertContains(t, args, "--model\nopus\nFix the login test\n")
+
+	t.Run("no context", func(t *testing.T) {
+		out, _ := env.run("claude", "feature-claude", "--no-context")
+		assertNotContains(t, out, "YOLO Mode")
+		data, _ := os.ReadFile(argsFile)
+		if string(data) != "\n" {
+			t.Errorf("claude args = %q, want none", data)
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -113,6 +114,22 @@ func TestAgentValidateValid(t *testing.T) {
 	assertContains(t, out, "valid-task' is valid")
 }
 
+// TestAgentValidateAccessible verifies --accessible replaces emoji with words.
+func TestAgentValidateAccessible(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	out, err := env.run("--accessible", "agent", "validate", "valid-task")
+	t.Logf("output:\n%s", out)
+
+	assertSuccess(t, out, err)
+	assertContains(t, out, "OK: Task name: Valid Task")
+	assertContains(t, out, "WARN: No safety gates configured")
+	for _, symbol := range []string{"✅", "⚠", "»", "❌"} {
+		assertNotContains(t, out, symbol)
+	}
+}
+
 // TestAgentValidateInvalid verifies that a broken task causes non-zero exit
 // and reports specific validation errors.
 func TestAgentValidateInvalid(t *testing.T) {
@@ -123,8 +140,8 @@ func TestAgentValidateInvalid(t *testing.T) {
 	t.Logf("output:\n%s", out)
 
 	assertFailure(t, err)
-	assertContains(t, out, "✗ Branch is empty")
-	assertContains(t, out, "✗ No steps configured")
+	assertContains(t, out, "[err] Branch is empty")
+	assertContains(t, out, "[err] No steps configured")
 	assertContains(t, out, "broken-task' has")
 	assertContains(t, out, "error(s)")
 }
@@ -142,6 +159,38 @@ func TestAgentValidateUnknownTask(t *testing.T) {
 	assertContains(t, out, "does-not-exist")
 }
 
+// TestAgentShow verifies "worktree agent show" prints the resolved task,
+// including defaults that are not in .worktree.yml.
+func TestAgentShow(t *testing.T) {
+	env := newTestEnv(t)
+	isolated := strings.Replace(validAgentYAML, "    safety:\n", `    environment:
+      isolated: true
+      pass_through: ["NPM_TOKEN"]
+    safety:
+      gates:
+        - name: "tests"
+          command: "make test"
+          required: true
+`, 1)
+	env.writeConfig(minimalConfig(isolated))
+
+	out, err := env.run("agent", "show", "valid-task")
+	t.Logf("output:\n%s", out)
+
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Agent Task: Valid Task")
+	assertContains(t, out, "[1/1] Do something (shell)")
+	assertContains(t, out, "Working dir: current directory (default)")
+	assertContains(t, out, "[1/1] tests (required)")
+	assertContains(t, out, "Push: off")
+	assertContains(t, out, "Pass-through: PATH, HOME")
+	assertContains(t, out, "NPM_TOKEN")
+
+	out, err = env.run("agent", "show", "does-not-exist")
+	assertFailure(t, err)
+	assertContains(t, out, "agent task 'does-not-exist' not found")
+}
+
 // ── Group 2: Registry commands ────────────────────────────────────────────────
 
 // TestListEmpty verifies "worktree list" with no worktrees registered.
@@ -178,6 +227,42 @@ func TestNewFeatureDryRun(t *testing.T) {
 	}
 }
 
+// TestNewFeatureNoStart verifies that "worktree new-feature --no-start" sets
+// up the feature without running start commands, and that "worktree start"
+// launches them later.
+func TestNewFeatureNoStart(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(strings.Replace(worktreeConfig(), `    dir: "backend"
+    main_branch: "main"
+`, `    dir: "backend"
+    main_branch: "main"
+    start_command: "echo started > started.txt"
+`, 1))
+
+	out, err := env.run("new-feature", "feature/no-start", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Skipping service startup (--no-start)")
+	assertContains(t, out, "worktree start feature-no-start")
+	assertNotContains(t, out, "Starting services")
+
+	started := filepath.Join(env.root, "worktrees", "feature-no-start", "backend", "started.txt")
+	if _, err := os.Stat(started); !os.IsNotExist(err) {
+		t.Fatalf("start_command ran despite --no-start: %v", err)
+	}
+
+	out, err = env.run("get-env", "feature-no-start", "APP_PORT")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "9090")
+
+	out, err = env.run("start", "feature-no-start")
+	assertSuccess(t, out, err)
+	if _, err := os.Stat(started); err != nil {
+		t.Errorf("start_command did not run on worktree start: %v", err)
+	}
+}
+
 // TestWorktreeLifecycle creates a worktree once and exercises list, ports,
 // yolo, and remove in sequence.  This avoids repeating the expensive
 // new-feature setup in each test.
@@ -218,8 +303,8 @@ scheduled_agents:
 		assertContains(t, out, "Status for Feature: feature-lifecycle-test")
 		assertContains(t, out, "Branch")
 		assertContains(t, out, "YOLO Mode")
-		assertContains(t, out, "✅ Exists")
-		assertContains(t, out, "⚪ Not running")
+		assertContains(t, out, "Worktree: Exists")
+		assertContains(t, out, "Not running")
 	})
 
 	t.Run("list shows feature", func(t *testing.T) {
@@ -240,6 +325,25 @@ scheduled_agents:
 		assertContains(t, out, "Ports for Feature: feature-lifecycle-test")
 	})
 
+	t.Run("copy to clipboard", func(t *testing.T) {
+		clip := filepath.Join(env.root, "clipboard.txt")
+		env.writeMockBinary("xclip", "cat > "+clip)
+
+		out, err := env.run("status", "feature-lifecycle-test", "--copy=backend")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Copied backend path to the clipboard")
+		data, _ := os.ReadFile(clip)
+		if want := filepath.Join("worktrees", "feature-lifecycle-test", "backend"); !strings.HasSuffix(string(data), want) {
+			t.Errorf("clipboard = %q, want a path ending in %s", data, want)
+		}
+
+		// The test config has no service URLs to copy
+		out, err = env.run("ports", "feature-lifecycle-test", "--copy")
+		assertFailure(t, err)
+		assertContains(t, out, "has no services with a URL")
+	})
+
 	t.Run("yolo enable", func(t *testing.T) {
 		out, err := env.run("yolo", "feature-lifecycle-test")
 		t.Logf("output:\n%s", out)
@@ -371,6 +475,40 @@ func TestQueueLifecycle(t *testing.T) {
 		assertContains(t, out, "pending")
 	})
 
+	t.Run("list formats and filters", func(t *testing.T) {
+		out, err := env.run("agent", "queue", "add", "valid-task", "feature-other")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("agent", "queue", "list")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "ID        STATUS   AGENT       WORKTREE")
+		assertContains(t, out, taskID[:8]+"  pending  valid-task  feature-test")
+		assertNotContains(t, out, "Agent: valid-task")
+
+		out, err = env.run("agent", "queue", "list", "--format", "wide")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "pending (2)")
+		assertContains(t, out, "Worktree: feature-other")
+
+		out, err = env.run("agent", "queue", "list", "--limit", "1")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-test")
+		assertNotContains(t, out, "feature-other")
+		assertContains(t, out, "Showing 1 of 2 tasks")
+
+		out, err = env.run("agent", "queue", "list", "--status", "failed")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "No failed tasks in queue")
+		assertContains(t, out, "Pending: 2")
+
+		out, err = env.run("agent", "queue", "list", "--format", "tall")
+		assertFailure(t, err)
+		assertContains(t, out, "unknown format 'tall' (expected compact or wide)")
+
+		out, err = env.run("agent", "queue", "remove", "--agent", "valid-task", "--worktree", "feature-other")
+		assertSuccess(t, out, err)
+	})
+
 	t.Run("remove task", func(t *testing.T) {
 		if taskID == "" {
 			t.Skip("taskID not set (add task failed)")
@@ -513,6 +651,19 @@ func TestHistoryWithRecords(t *testing.T) {
 		assertNotContains(t, out, "failed")
 	})
 
+	t.Run("show record without snapshot", func(t *testing.T) {
+		out, err := env.run("agent", "history", "show", "bbb222")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-history-b")
+		assertContains(t, out, "required gate failed")
+		assertContains(t, out, "No task definition recorded")
+	})
+
+	t.Run("show unknown id", func(t *testing.T) {
+		_, err := env.run("agent", "history", "show", "zzz")
+		assertFailure(t, err)
+	})
+
 	t.Run("stats shows aggregate", func(t *testing.T) {
 		out, err := env.run("agent", "history", "stats")
 		t.Logf("output:\n%s", out)
@@ -535,3 +686,104 @@ func TestHistoryWithRecords(t *testing.T) {
 		assertContains(t, out2, "No execution history found")
 	})
 }
+
+// TestHistoryShowSnapshot verifies that history show renders the task
+// definition as executed after the task is edited in .worktree.yml.
+func TestHistoryShowSnapshot(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	out, err := env.run("agent", "run", "valid-task")
+	assertSuccess(t, out, err)
+
+	data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h struct {
+		Records []struct {
+			ID       string `json:"id"`
+			TaskHash string `json:"task_hash"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(data, &h); err != nil || len(h.Records) != 1 || h.Records[0].TaskHash == "" {
+		t.Fatalf("expected one record with a task hash: %v\n%s", err, data)
+	}
+	id := h.Records[0].ID[:8]
+
+	t.Run("unchanged definition", func(t *testing.T) {
+		out, err := env.run("agent", "history", "show", id)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Task definition (snapshot")
+		assertContains(t, out, "echo 'working'")
+		assertNotContains(t, out, "has changed")
+	})
+
+	t.Run("edited definition shows executed version", func(t *testing.T) {
+		env.writeConfig(minimalConfig(strings.Replace(validAgentYAML, "echo 'working'", "echo 'edited'", 1)))
+
+		out, err := env.run("agent", "history", "show", id)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "echo 'working'")
+		assertNotContains(t, out, "echo 'edited'")
+		assertContains(t, out, "has changed since this execution")
+	})
+
+	t.Run("clear removes snapshots", func(t *testing.T) {
+		out, err := env.run("agent", "history", "clear")
+		assertSuccess(t, out, err)
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", ".history-tasks")); !os.IsNotExist(err) {
+			t.Errorf("snapshot directory still exists: %v", err)
+		}
+	})
+}
+
+// TestHistoryRerun verifies that a recorded execution can be queued or run
+// again with the definition it executed, even after the task was edited.
+func TestHistoryRerun(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	out, err := env.run("agent", "run", "valid-task")
+	assertSuccess(t, out, err)
+
+	out, err = env.run("agent", "history", "list")
+	assertSuccess(t, out, err)
+	id := regexp.MustCompile(`(?m)^([0-9a-f]{8})\s+completed`).FindStringSubmatch(out)
+	if id == nil {
+		t.Fatalf("no execution ID in history list:\n%s", out)
+	}
+
+	env.writeConfig(minimalConfig(strings.Replace(validAgentYAML, "echo 'working'", "echo 'edited'", 1)))
+
+	t.Run("queue with the executed definition", func(t *testing.T) {
+		out, err := env.run("agent", "history", "rerun", id[1])
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Rerun of "+id[1]+" queued")
+		assertContains(t, out, "Definition: snapshot")
+
+		out, err = env.run("agent", "queue", "start")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "working")
+		assertNotContains(t, out, "edited")
+	})
+
+	t.Run("run now with the current definition", func(t *testing.T) {
+		out, err := env.run("agent", "history", "rerun", id[1], "--now", "--current")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "current definition")
+		assertContains(t, out, "edited")
+
+		out, err = env.run("agent", "history", "stats")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Total executions: 3")
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		_, err := env.run("agent", "history", "rerun", "zzz")
+		assertFailure(t, err)
+	})
+}
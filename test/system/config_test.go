@@ -0,0 +1,156 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestConfigRenameProject verifies that renaming a project rewrites the config,
+// the registry and the feature's instance marker so commands keep working.
+func TestConfigRenameProject(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/rename-test")
+	assertSuccess(t, out, err)
+
+	out, err = env.run("config", "rename-project", "backend", "api")
+	t.Logf("rename output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Project 'backend' renamed to 'api'")
+
+	t.Run("config rewritten", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(env.root, ".worktree.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertContains(t, string(data), "api:")
+		assertContains(t, string(data), "dir: \"backend\"")
+		assertNotContains(t, string(data), "\n  backend:")
+	})
+
+	t.Run("registry rewritten", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var reg struct {
+			Worktrees map[string]struct {
+				Projects []string `json:"projects"`
+			} `json:"worktrees"`
+		}
+		if err := json.Unmarshal(data, &reg); err != nil {
+			t.Fatal(err)
+		}
+		got := strings.Join(reg.Worktrees["feature-rename-test"].Projects, ",")
+		if got != "api,frontend" {
+			t.Errorf("registry projects = %s, want api,frontend", got)
+		}
+	})
+
+	t.Run("instance marker rewritten", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(env.root, "worktrees", "feature-rename-test", ".worktree-instance"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertContains(t, string(data), `"api"`)
+		assertNotContains(t, string(data), `"backend"`)
+	})
+
+	t.Run("unknown project rejected", func(t *testing.T) {
+		_, err := env.run("config", "rename-project", "nope", "other")
+		assertFailure(t, err)
+	})
+
+	t.Run("existing name rejected", func(t *testing.T) {
+		_, err := env.run("config", "rename-project", "api", "frontend")
+		assertFailure(t, err)
+	})
+}
+
+// TestConfigRenameProjectInclude verifies that a rename also rewrites the
+// references in an include: file, so the config still loads afterwards.
+func TestConfigRenameProjectInclude(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig("include: worktree.d/presets.yml\n" + worktreeConfig())
+	presets := "presets:\n  default:\n    projects: [backend, frontend]\n"
+	if err := os.MkdirAll(filepath.Join(env.root, "worktree.d"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(env.root, "worktree.d", "presets.yml"), []byte(presets), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := env.run("config", "rename-project", "backend", "api")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Updated worktree.d/presets.yml")
+
+	data, err := os.ReadFile(filepath.Join(env.root, "worktree.d", "presets.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(data), "[api, frontend]")
+
+	out, err = env.run("list")
+	assertSuccess(t, out, err)
+}
+
+// TestConfigShow verifies that config show prints resolved ranges and, with
+// --feature, the feature's computed env vars including overrides.
+func TestConfigShow(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `  API_URL:
+    value: "http://{host}:{APP_PORT}"
+    env: "API_URL"
+`)
+
+	t.Run("without feature", func(t *testing.T) {
+		out, err := env.run("config", "show")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "testproject")
+		assertContains(t, out, "range=9090-9190 (explicit)")
+		assertContains(t, out, "[backend, frontend] (default)")
+		assertNotContains(t, out, "Feature:")
+	})
+
+	out, err := env.run("new-feature", "feature/show-test")
+	assertSuccess(t, out, err)
+	out, err = env.run("override", "feature-show-test", "EXTRA=1")
+	assertSuccess(t, out, err)
+
+	t.Run("with feature", func(t *testing.T) {
+		out, err := env.run("config", "show", "--feature", "feature/show-test")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Feature: feature-show-test")
+		assertContains(t, out, "http://localhost:9090")
+		assertContains(t, out, "(override)")
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		out, err := env.run("config", "show", "--feature", "nope")
+		assertFailure(t, err)
+		assertContains(t, out, "not found")
+	})
+
+	t.Run("effective", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(env.root, ".worktree.local.yml"), []byte("hostname: dev.local\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(filepath.Join(env.root, ".worktree.local.yml"))
+
+		out, err := env.run("config", "show", "--effective")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "#   2. .worktree.local.yml")
+		assertContains(t, out, "hostname: dev.local # from .worktree.local.yml")
+		assertContains(t, out, "project_name: testproject # from .worktree.yml")
+	})
+}
@@ -0,0 +1,81 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestFeatureContextFile verifies that new-feature writes .worktree-context.json
+// into the Claude working directory, kept out of git status, and that
+// worktree context prints and refreshes it.
+func TestFeatureContextFile(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/ctx", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Feature context created")
+
+	backendDir := filepath.Join(env.root, "worktrees", "feature-ctx", "backend")
+	path := filepath.Join(backendDir, ".worktree-context.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected %s to exist: %v", path, err)
+	}
+	var ctx struct {
+		Feature    string         `json:"feature"`
+		Branch     string         `json:"branch"`
+		WorkingDir string         `json:"working_dir"`
+		Ports      map[string]int `json:"ports"`
+		Projects   []struct {
+			Name   string `json:"name"`
+			Path   string `json:"path"`
+			Branch string `json:"branch"`
+		} `json:"projects"`
+		Commands map[string]string `json:"commands"`
+	}
+	if err := json.Unmarshal(data, &ctx); err != nil {
+		t.Fatalf("invalid context JSON: %v\n%s", err, data)
+	}
+	if ctx.Feature != "feature-ctx" || ctx.Branch != "feature/ctx" || ctx.WorkingDir != backendDir {
+		t.Errorf("context = %+v, want feature-ctx in %s", ctx, backendDir)
+	}
+	if ctx.Ports["APP_PORT"] != 9090 {
+		t.Errorf("ports = %v, want APP_PORT=9090", ctx.Ports)
+	}
+	if len(ctx.Projects) != 2 || ctx.Projects[1].Name != "frontend" || ctx.Projects[1].Branch != "feature/ctx" {
+		t.Errorf("projects = %+v, want backend and frontend on feature/ctx", ctx.Projects)
+	}
+	if ctx.Commands["status"] != "worktree status feature-ctx" {
+		t.Errorf("commands = %v", ctx.Commands)
+	}
+
+	status, err := exec.Command("git", "-C", backendDir, "status", "--porcelain").CombinedOutput()
+	if err != nil || len(status) != 0 {
+		t.Errorf("git status = %q (%v), want the context file excluded", status, err)
+	}
+
+	t.Run("print", func(t *testing.T) {
+		out, err := env.run("context", "feature-ctx")
+		assertSuccess(t, out, err)
+		assertContains(t, out, `"feature": "feature-ctx"`)
+		assertContains(t, out, `"APP_PORT": 9090`)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		if err := os.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.run("context", "feature-ctx", "--refresh")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Feature context written")
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("context file not rewritten: %v", err)
+		}
+	})
+}
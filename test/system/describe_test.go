@@ -0,0 +1,81 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDescribe covers the reproduction command and YAML snippet of a feature.
+func TestDescribe(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/x", "--branch-map", "backend=feature/x-api", "--no-fixtures", "--yolo")
+	assertSuccess(t, out, err)
+	out, err = env.run("override", "feature-x", "MSG=hello world", "DEBUG=1")
+	assertSuccess(t, out, err)
+
+	t.Run("as command", func(t *testing.T) {
+		out, err := env.run("describe", "feature/x", "--as-command")
+		assertSuccess(t, out, err)
+		want := "worktree new-feature feature/x default --branch-map backend=feature/x-api --no-fixtures --yolo\n" +
+			"worktree override feature-x DEBUG=1 'MSG=hello world'\n"
+		if out != want {
+			t.Errorf("output = %q, want %q", out, want)
+		}
+	})
+
+	t.Run("as yaml", func(t *testing.T) {
+		out, err := env.run("describe", "feature-x", "--as-yaml")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "branch: feature/x\n")
+		assertContains(t, out, "preset: default\n")
+		assertContains(t, out, "    backend: feature/x-api\n")
+		assertContains(t, out, "no_fixtures: true\n")
+		assertContains(t, out, "MSG: hello world")
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		out, err := env.run("describe", "feature-x")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Recreate with:")
+		assertContains(t, out, "worktree new-feature feature/x default")
+	})
+
+	t.Run("preset inferred for older entries", func(t *testing.T) {
+		regPath := filepath.Join(env.root, "worktrees", ".registry.json")
+		data, err := os.ReadFile(regPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var reg map[string]any
+		if err := json.Unmarshal(data, &reg); err != nil {
+			t.Fatal(err)
+		}
+		delete(reg["worktrees"].(map[string]any)["feature-x"].(map[string]any), "preset")
+		data, _ = json.Marshal(reg)
+		if err := os.WriteFile(regPath, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		os.Remove(regPath + ".sha256") // Keep the hand edit, as a user would
+
+		out, err := env.run("describe", "feature-x", "--as-command")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "worktree new-feature feature/x default ")
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		out, err := env.run("describe", "feature-nope")
+		assertFailure(t, err)
+		assertContains(t, out, "not found")
+	})
+
+	t.Run("exclusive formats", func(t *testing.T) {
+		_, err := env.run("describe", "feature-x", "--as-command", "--as-yaml")
+		assertFailure(t, err)
+	})
+}
@@ -0,0 +1,297 @@
+package system_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestDoctorFix verifies doctor --fix previews and then repairs orphaned
+// registry entries, directories, containers and git worktree metadata.
+func TestDoctorFix(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/vanished")
+	assertSuccess(t, out, err)
+
+	// Registry entry without directory, leaving stale git metadata behind
+	if err := os.RemoveAll(filepath.Join(env.root, "worktrees", "feature-vanished")); err != nil {
+		t.Fatal(err)
+	}
+	// Directory without registry entry
+	strayDir := filepath.Join(env.root, "worktrees", "stray")
+	env.mkdir("worktrees/stray")
+
+	// Containers of a feature that is not in the registry
+	dockerLog := filepath.Join(env.binDir, "docker.log")
+	env.writeMockBinary("docker",
+		`if [ "$1" = "ps" ]; then`,
+		`  filter=""`,
+		`  for a in "$@"; do case "$a" in name=*) filter="${a#name=}";; esac; done`,
+		`  name="testproject-ghost-app-1"`,
+		`  case "$name" in "$filter"*) if [ "$2" = "-q" ]; then echo c0ffee; else echo "$name"; fi;; esac`,
+		`  exit 0`,
+		`fi`,
+		`case "$1" in stop|rm) echo "$@" >> "`+dockerLog+`";; esac`,
+	)
+
+	t.Run("dry run previews without changes", func(t *testing.T) {
+		out, _ := env.run("doctor", "--fix", "--dry-run", "--no-fetch")
+		assertContains(t, out, "Would stop and remove containers testproject-ghost-*")
+		assertContains(t, out, "Would remove registry entry 'feature-vanished'")
+		assertContains(t, out, "Would delete directory worktrees/stray (not in registry) (asks for confirmation)")
+		assertContains(t, out, "Would prune git worktree metadata in backend (1 stale)")
+
+		if _, err := os.Stat(strayDir); err != nil {
+			t.Errorf("dry run deleted the stray directory: %v", err)
+		}
+		if _, err := os.Stat(dockerLog); err == nil {
+			t.Error("dry run ran docker stop/rm")
+		}
+	})
+
+	t.Run("declined directory deletion is skipped", func(t *testing.T) {
+		out, _ := env.run("doctor", "--fix", "--no-fetch")
+		assertContains(t, out, "Skipped: delete directory worktrees/stray")
+		assertContains(t, out, "Remove registry entry 'feature-vanished'")
+		if _, err := os.Stat(strayDir); err != nil {
+			t.Errorf("declined deletion removed the stray directory: %v", err)
+		}
+	})
+
+	t.Run("fix with --yes repairs everything", func(t *testing.T) {
+		out, _ := env.run("doctor", "--fix", "--yes", "--no-fetch")
+		assertContains(t, out, "Delete directory worktrees/stray")
+		assertContains(t, out, "Stop and remove containers testproject-ghost-*")
+
+		if _, err := os.Stat(strayDir); !os.IsNotExist(err) {
+			t.Error("expected stray directory to be deleted")
+		}
+		data, err := os.ReadFile(dockerLog)
+		if err != nil {
+			t.Fatalf("docker stop/rm not called: %v", err)
+		}
+		assertContains(t, string(data), "stop c0ffee")
+		assertContains(t, string(data), "rm c0ffee")
+
+		list, err := exec.Command("git", "-C", filepath.Join(env.root, "backend"), "worktree", "list").CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNotContains(t, string(list), "feature-vanished")
+
+		registry, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertNotContains(t, string(registry), "feature-vanished")
+	})
+}
+
+// TestDoctorPortCollision verifies doctor reports a registered port that is
+// bound by a process unrelated to the feature.
+func TestDoctorPortCollision(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/collide")
+	assertSuccess(t, out, err)
+
+	data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reg struct {
+		Worktrees map[string]struct {
+			Ports map[string]int `json:"ports"`
+		} `json:"worktrees"`
+	}
+	if err := json.Unmarshal(data, &reg); err != nil {
+		t.Fatal(err)
+	}
+	port := reg.Worktrees["feature-collide"].Ports["APP_PORT"]
+
+	// The test process plays the unrelated process holding the port
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		t.Skipf("cannot bind port %d: %v", port, err)
+	}
+	defer ln.Close()
+
+	out, _ = env.run("doctor", "--no-fetch")
+	assertContains(t, out, "registered ports used by other processes")
+	assertContains(t, out, fmt.Sprintf("APP_PORT: port %d (feature-collide)", port))
+	assertContains(t, out, "held by")
+	assertNotContains(t, out, "FE_PORT: port")
+}
+
+// TestDoctorFeatureScope verifies doctor --feature only reports problems of
+// that feature and rejects unknown features.
+func TestDoctorFeatureScope(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.mkdir("shared")
+	env.writeConfig(worktreeConfig() + `
+symlinks:
+  - source: "shared"
+    target: "shared"
+`)
+
+	for _, branch := range []string{"feature/broken", "feature/healthy"} {
+		out, err := env.run("new-feature", branch)
+		assertSuccess(t, out, err)
+	}
+	if err := os.Remove(filepath.Join(env.root, "worktrees", "feature-broken", "shared")); err != nil {
+		t.Fatal(err)
+	}
+	env.mkdir("worktrees/stray")
+
+	doctorJSON := func(t *testing.T, args ...string) map[string]any {
+		t.Helper()
+		out, _ := env.run(append([]string{"doctor", "--no-fetch", "--json"}, args...)...)
+		var report map[string]any
+		if err := json.Unmarshal([]byte(out), &report); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		return report
+	}
+
+	t.Run("full run sees everything", func(t *testing.T) {
+		report := doctorJSON(t)
+		data, _ := json.Marshal(report)
+		assertContains(t, string(data), `"OrphanedDirectories":["stray"]`)
+		assertContains(t, string(data), `"Feature":"feature-broken","Problem":"missing","Target":"shared"`)
+	})
+
+	t.Run("healthy feature ignores the others", func(t *testing.T) {
+		report := doctorJSON(t, "--feature", "feature/healthy")
+		data, _ := json.Marshal(report)
+		assertContains(t, string(data), `"Feature":"feature-healthy"`)
+		assertNotContains(t, string(data), "stray")
+		assertNotContains(t, string(data), "feature-broken")
+		if total := report["Summary"].(map[string]any)["TotalWorktrees"]; total != float64(1) {
+			t.Errorf("TotalWorktrees = %v, want 1", total)
+		}
+	})
+
+	t.Run("broken feature reports its symlink", func(t *testing.T) {
+		out, err := env.run("doctor", "--no-fetch", "--feature", "feature-broken")
+		assertFailure(t, err)
+		assertContains(t, out, "Scoped to feature: feature-broken")
+		assertContains(t, out, "feature-broken/shared: missing")
+		assertNotContains(t, out, "stray")
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		out, err := env.run("doctor", "--feature", "feature-nope")
+		assertFailure(t, err)
+		assertContains(t, out, "Feature worktree 'feature-nope' not found")
+	})
+}
+
+// TestDoctorIntegrity verifies a tampered registry is refused until doctor
+// --fix restores its last good copy, and that --fix keeps a registry that
+// still parses instead of replacing it with the older backup.
+func TestDoctorIntegrity(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	// Two saves, so the first registry is kept as the backup
+	out, err := env.run("new-feature", "feature/one")
+	assertSuccess(t, out, err)
+	out, err = env.run("new-feature", "feature/two")
+	assertSuccess(t, out, err)
+
+	path := filepath.Join(env.root, "worktrees", ".registry.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)/2], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = env.run("list")
+	assertFailure(t, err)
+	assertContains(t, out, "registry integrity check failed")
+
+	out, _ = env.run("doctor", "--fix", "--dry-run", "--no-fetch")
+	assertContains(t, out, "Would restore worktrees/.registry.json from its last good backup")
+
+	out, _ = env.run("doctor", "--fix", "--yes", "--no-fetch")
+	assertContains(t, out, "Restore worktrees/.registry.json from its last good backup")
+
+	out, err = env.run("list")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "feature-one")
+
+	t.Run("content that parses is kept", func(t *testing.T) {
+		// The complete write whose checksum was not updated
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.run("list")
+		assertFailure(t, err)
+
+		out, _ = env.run("doctor", "--fix", "--yes", "--no-fetch")
+		assertContains(t, out, "Accept the current content of worktrees/.registry.json (still valid JSON)")
+
+		out, err = env.run("list")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-two")
+	})
+}
+
+// TestCompletionDoctor verifies completion doctor checks the binary on PATH
+// and the completion setup in the user's rc file, printing remediation steps.
+func TestCompletionDoctor(t *testing.T) {
+	env := newTestEnv(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("ZDOTDIR", "")
+	t.Setenv("SHELL", "/bin/zsh")
+
+	// Not on PATH yet: env.binDir only holds mocks
+	out, err := env.run("completion", "doctor")
+	assertFailure(t, err)
+	assertContains(t, out, "[ok] Shell: zsh")
+	assertContains(t, out, "'worktree' is not on PATH")
+
+	if err := os.Symlink(testBinary, filepath.Join(env.binDir, "worktree")); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.run("completion", "doctor")
+	assertFailure(t, err)
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+		t.Errorf("exit error = %v, want exit code 1 (warnings)", err)
+	}
+	assertContains(t, out, "[ok] Binary on PATH")
+	assertContains(t, out, "answers them")
+	assertContains(t, out, "[warn] Completion: not installed")
+	assertContains(t, out, "echo 'source <(worktree completion zsh)' >> ~/.zshrc")
+
+	rc := "autoload -U compinit; compinit\nsource <(worktree completion zsh)\n"
+	if err := os.WriteFile(filepath.Join(home, ".zshrc"), []byte(rc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.run("completion", "doctor")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "[ok] Completion: generated on shell start by ~/.zshrc")
+	assertContains(t, out, "[info] Prompt: no worktree segment (optional)")
+
+	out, err = env.run("completion", "doctor", "--shell", "tcsh")
+	assertFailure(t, err)
+	assertContains(t, out, "'tcsh' is not supported")
+}
@@ -0,0 +1,92 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLifecycleDryRun verifies start, stop, restart and remove --dry-run print
+// their commands, env vars and files without running or changing anything.
+func TestLifecycleDryRun(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	dockerLog := filepath.Join(env.binDir, "docker.log")
+	env.writeMockBinary("docker", `echo "$@" >> "`+dockerLog+`"`)
+
+	cfg := strings.Replace(worktreeConfig(), "    dir: \"backend\"\n    main_branch: \"main\"\n",
+		"    dir: \"backend\"\n    main_branch: \"main\"\n"+
+			"    start_command: \"touch started\"\n"+
+			"    start_pre_command: \"touch start_pre_ran\"\n"+
+			"    stop_pre_command: \"touch stop_pre_ran\"\n"+
+			"    restart_post_command: \"touch restart_post_ran\"\n", 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/preview", "--no-start")
+	assertSuccess(t, out, err)
+	featureDir := filepath.Join(env.root, "worktrees", "feature-preview")
+	backendDir := filepath.Join(featureDir, "backend")
+	registryBefore, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("start", func(t *testing.T) {
+		out, err := env.run("start", "feature-preview", "--dry-run")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Dry Run - Preview Mode")
+		assertContains(t, out, "APP_PORT=9090")
+		assertContains(t, out, "FEATURE_NAME=feature-preview")
+		assertContains(t, out, "backend (in worktrees/feature-preview/backend, COMPOSE_PROJECT_NAME=testproject-feature-preview)")
+		assertContains(t, out, "start_pre_command: touch start_pre_ran")
+		assertContains(t, out, "start_command: touch started")
+		assertContains(t, out, "worktrees/feature-preview/.worktree-env.json")
+		assertContains(t, out, "no changes were made")
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		out, err := env.run("stop", "feature-preview", "--dry-run")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "stop_pre_command: touch stop_pre_ran")
+		assertContains(t, out, "stop: docker compose -p testproject-feature-preview down --remove-orphans")
+		assertContains(t, out, "worktrees/feature-preview/.worktree-status.json")
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		out, err := env.run("restart", "feature-preview", "--dry-run", "--project", "backend")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "stop: docker compose -p testproject-feature-preview down --remove-orphans")
+		assertContains(t, out, "start_command: touch started")
+		assertContains(t, out, "restart_post_command: touch restart_post_ran")
+		assertNotContains(t, out, "frontend (in")
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		out, err := env.run("remove", "feature-preview", "--dry-run")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "worktree remove "+backendDir)
+		assertContains(t, out, "worktrees/.registry.json (entry 'feature-preview')")
+		assertContains(t, out, "Run without --dry-run to remove the feature")
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		for _, marker := range []string{"started", "start_pre_ran", "stop_pre_ran", "restart_post_ran"} {
+			if _, err := os.Stat(filepath.Join(backendDir, marker)); !os.IsNotExist(err) {
+				t.Errorf("dry run executed a command: %s exists", marker)
+			}
+		}
+		if _, err := os.Stat(dockerLog); !os.IsNotExist(err) {
+			data, _ := os.ReadFile(dockerLog)
+			t.Errorf("dry run called docker: %s", data)
+		}
+		registryAfter, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(registryAfter) != string(registryBefore) {
+			t.Error("dry run changed the registry")
+		}
+	})
+}
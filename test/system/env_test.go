@@ -0,0 +1,64 @@
+package system_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// TestEnvExport verifies that env prints all resolved vars of a feature in
+// the dotenv, shell and json formats.
+func TestEnvExport(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `  API_URL:
+    value: "http://{host}:{APP_PORT}/api?a=1&b=2"
+    env: "API_URL"
+  COMPOSE_PROJECT_NAME:
+    value: "{project}-{feature}-{service}"
+    env: "COMPOSE_PROJECT_NAME"
+  FRONTEND_DIST:
+    value: "{WORKTREE_PATH:frontend}{path_sep}dist"
+    env: "FRONTEND_DIST"
+`)
+
+	out, err := env.run("new-feature", "feature/env-test")
+	assertSuccess(t, out, err)
+
+	t.Run("dotenv", func(t *testing.T) {
+		out, err := env.run("env", "feature/env-test")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "APP_PORT=9090\n")
+		assertContains(t, out, "FEATURE_NAME=feature-env-test\n")
+		assertContains(t, out, "INSTANCE=0\n")
+		assertContains(t, out, `API_URL="http://localhost:9090/api?a=1&b=2"`)
+		assertNotContains(t, out, "COMPOSE_PROJECT_NAME")
+		assertContains(t, out, "FEATURE_DIR="+filepath.Join(env.root, "worktrees", "feature-env-test")+"\n")
+		assertContains(t, out, "FRONTEND_DIST="+filepath.Join(env.root, "worktrees", "feature-env-test", "frontend", "dist")+"\n")
+	})
+
+	t.Run("shell", func(t *testing.T) {
+		out, err := env.runFrom(filepath.Join(env.root, "worktrees", "feature-env-test", "backend"), "env", "--format", "shell")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "export APP_PORT='9090'\n")
+		assertContains(t, out, "export API_URL='http://localhost:9090/api?a=1&b=2'\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := env.run("env", "feature-env-test", "--format", "json")
+		assertSuccess(t, out, err)
+		var vars map[string]string
+		if err := json.Unmarshal([]byte(out), &vars); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if vars["FE_PORT"] != "9200" || vars["FEATURE_NAME"] != "feature-env-test" {
+			t.Errorf("unexpected vars %v", vars)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := env.run("env", "feature-env-test", "--format", "yaml")
+		assertFailure(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFeatureFlags verifies that the flags file is rendered on new-feature and
+// that 'worktree flags set/unset' rewrites it and runs the reload command.
+func TestFeatureFlags(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `
+feature_flags:
+  path: "flags.yml"
+  projects: [backend]
+  reload_command: "echo reloaded-$COMPOSE_PROJECT_NAME"
+  flags:
+    new_checkout: false
+    api_url: "http://localhost:{APP_PORT}"
+`)
+
+	out, err := env.run("new-feature", "feature/flags", "--no-start")
+	assertSuccess(t, out, err)
+
+	flagsFile := filepath.Join(env.root, "worktrees", "feature-flags", "backend", "flags.yml")
+	readFlags := func() string {
+		t.Helper()
+		data, err := os.ReadFile(flagsFile)
+		if err != nil {
+			t.Fatalf("flags file not written: %v", err)
+		}
+		return string(data)
+	}
+	assertContains(t, readFlags(), "new_checkout: false")
+	assertContains(t, readFlags(), "api_url: http://localhost:9090")
+	if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-flags", "frontend", "flags.yml")); !os.IsNotExist(err) {
+		t.Errorf("flags file written for frontend: %v", err)
+	}
+
+	out, err = env.run("flags", "feature-flags", "set", "new_checkout=true", "beta=1")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "'beta' is not in feature_flags.flags")
+	assertContains(t, out, "reloaded-testproject-feature-flags")
+	assertContains(t, readFlags(), "new_checkout: true")
+	assertContains(t, readFlags(), "beta: 1")
+
+	out, err = env.run("flags", "feature-flags")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "new_checkout=true (set)")
+	assertContains(t, out, "api_url=http://localhost:9090")
+
+	t.Run("unset restores the default", func(t *testing.T) {
+		out, err := env.run("flags", "feature-flags", "unset", "new_checkout", "--no-reload")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "reloaded-")
+		assertContains(t, readFlags(), "new_checkout: false")
+	})
+
+	t.Run("invalid assignment", func(t *testing.T) {
+		out, err := env.run("flags", "feature-flags", "set", "oops")
+		assertFailure(t, err)
+		assertContains(t, out, "expected KEY=VALUE")
+	})
+}
@@ -0,0 +1,34 @@
+package system_test
+
+import "testing"
+
+// TestOldGitIsRefused verifies that commands stop with a clear message when
+// the git on PATH is older than worktree supports.
+func TestOldGitIsRefused(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("git", `echo "git version 2.11.0"`)
+
+	out, err := env.run("list")
+	assertFailure(t, err)
+	assertContains(t, out, "worktree needs git 2.17 or newer, but git 2.11.0 is installed")
+
+	// The prompt segment must stay fast and silent
+	out, err = env.run("prompt")
+	assertSuccess(t, out, err)
+	assertNotContains(t, out, "git 2.11.0")
+}
+
+// TestInfoShowsGitVersion verifies that info reports the detected git version
+// and which git features are available.
+func TestInfoShowsGitVersion(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("git", `echo "git version 2.25.1"`)
+
+	out, err := env.run("info")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Version: 2.25.1 (minimum 2.17)")
+	assertContains(t, out, "git worktree remove: yes")
+	assertContains(t, out, "git rev-parse --path-format: no, needs git 2.31")
+}
@@ -0,0 +1,59 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCrossFeatureGuard verifies that destructive commands run from inside one
+// feature's worktree require confirmation before targeting another feature.
+func TestCrossFeatureGuard(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/guard-a")
+	assertSuccess(t, out, err)
+	out, err = env.run("new-feature", "feature/guard-b")
+	assertSuccess(t, out, err)
+
+	featureADir := filepath.Join(env.root, "worktrees", "feature-guard-a", "backend")
+	featureBDir := filepath.Join(env.root, "worktrees", "feature-guard-b")
+
+	t.Run("remove other feature without confirmation is refused", func(t *testing.T) {
+		out, err := env.runFrom(featureADir, "remove", "feature-guard-b")
+		assertFailure(t, err)
+		assertContains(t, out, "inside feature 'feature-guard-a'")
+		if _, statErr := os.Stat(featureBDir); statErr != nil {
+			t.Errorf("feature-guard-b should still exist: %v", statErr)
+		}
+	})
+
+	t.Run("stop other feature without confirmation is refused", func(t *testing.T) {
+		out, err := env.runFrom(featureADir, "stop", "feature-guard-b")
+		assertFailure(t, err)
+		assertContains(t, out, "use --force")
+	})
+
+	t.Run("same feature is not guarded", func(t *testing.T) {
+		out, err := env.runFrom(featureADir, "stop", "feature-guard-a")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "inside feature")
+	})
+
+	t.Run("force skips the guard", func(t *testing.T) {
+		out, err := env.runFrom(featureADir, "remove", "feature-guard-b", "--force")
+		assertSuccess(t, out, err)
+		if _, statErr := os.Stat(featureBDir); !os.IsNotExist(statErr) {
+			t.Error("expected feature-guard-b to be removed")
+		}
+	})
+
+	t.Run("project root is not guarded", func(t *testing.T) {
+		out, err := env.run("stop", "feature-guard-a")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "inside feature")
+	})
+}
@@ -0,0 +1,61 @@
+package system_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNewFeatureHealthChecks verifies that health_checks run after a project
+// starts, that a failing check skips the project's post-command and that start
+// still records the runs when it fails on an unhealthy project.
+func TestNewFeatureHealthChecks(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeMockBinary("docker")
+
+	cfg := strings.Replace(worktreeConfig(), "    dir: \"backend\"\n    main_branch: \"main\"\n",
+		"    dir: \"backend\"\n    main_branch: \"main\"\n"+
+			"    start_command: \"true\"\n"+
+			"    start_post_command: \"echo post-command-ran\"\n"+
+			"    health_checks:\n"+
+			"      - name: api\n"+
+			"        command: \"test -z \\\"$FAIL_HEALTH\\\" && test -n \\\"$APP_PORT\\\"\"\n"+
+			"        timeout: 1s\n"+
+			"        interval: 100ms\n", 1)
+	cfg = strings.Replace(cfg, "default_preset: default\n", "default_preset: default\nauto_fixtures: true\n", 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/healthy", "--verbose")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "api is healthy")
+	assertContains(t, out, "post-command-ran")
+
+	t.Run("unhealthy skips post-command", func(t *testing.T) {
+		t.Setenv("FAIL_HEALTH", "1")
+		out, err := env.run("new-feature", "feature/unhealthy", "--verbose")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "not healthy after 1s")
+		assertContains(t, out, "skipping its post-command")
+		assertNotContains(t, out, "post-command-ran")
+	})
+
+	t.Run("unhealthy start fails and records its runs", func(t *testing.T) {
+		t.Setenv("FAIL_HEALTH", "1")
+		out, err := env.run("start", "feature-unhealthy")
+		assertFailure(t, err)
+		assertContains(t, out, "backend did not become healthy")
+		assertContains(t, out, "Commands:")
+
+		out, err = env.run("logs", "feature-unhealthy", "--runs")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend.start")
+	})
+
+	t.Run("invalid check is rejected", func(t *testing.T) {
+		env.writeConfig(strings.Replace(cfg, "        timeout: 1s\n", "        timeout: 1s\n        url: \"localhost\"\n", 1))
+		out, err := env.run("list")
+		assertFailure(t, err)
+		assertContains(t, out, "exactly one of url, tcp or command")
+	})
+}
@@ -0,0 +1,74 @@
+package system_test
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestHookPolicies verifies hook timeouts, the retry and abort failure
+// policies, the run summary at the end of start and the captured runs shown by
+// logs --runs.
+func TestHookPolicies(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeMockBinary("docker")
+
+	cfg := strings.Replace(worktreeConfig(), "    dir: \"backend\"\n    main_branch: \"main\"\n",
+		"    dir: \"backend\"\n    main_branch: \"main\"\n"+
+			"    start_command: \"true\"\n"+
+			"    start_pre_command: \"sleep 30\"\n"+
+			"    start_post_command: \"test -f tried || { touch tried; exit 1; }\"\n"+
+			"    hooks:\n"+
+			"      start_pre:\n"+
+			"        timeout: 300ms\n"+
+			"      start_post:\n"+
+			"        on_failure: retry\n"+
+			"        retries: 1\n", 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/hooks")
+	assertSuccess(t, out, err)
+
+	t.Run("timeout and retry", func(t *testing.T) {
+		out, err := env.run("start", "feature-hooks")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend: start_pre_command failed: timed out after 300ms")
+		assertContains(t, out, "Retrying backend: start_post_command (attempt 2 of 2)")
+		assertContains(t, out, "Commands:")
+		assertContains(t, out, "2 attempts")
+		assertContains(t, out, "All services started!")
+	})
+
+	t.Run("runs are captured and recorded", func(t *testing.T) {
+		out, err := env.run("logs", "feature-hooks", "--runs")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend.start_pre")
+		assertContains(t, out, ".logs/backend.start_post.log")
+
+		out, err = env.run("logs", "feature-hooks", "--run", "backend.start_post")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "$ test -f tried")
+		assertContains(t, out, "# completed in")
+
+		out, err = env.run("logs", "feature-hooks", "--run", "backend.nope")
+		assertFailure(t, err)
+		assertContains(t, out, "No captured output for 'backend.nope'")
+	})
+
+	t.Run("abort stops the command", func(t *testing.T) {
+		env.writeConfig(strings.Replace(cfg, "        timeout: 300ms\n", "        timeout: 300ms\n        on_failure: abort\n", 1))
+		out, err := env.run("start", "feature-hooks")
+		assertFailure(t, err)
+		assertContains(t, out, "backend: start_pre_command failed: timed out after 300ms")
+		assertContains(t, out, "Commands:")
+		assertNotContains(t, out, "All services started!")
+	})
+
+	t.Run("invalid policy is rejected", func(t *testing.T) {
+		env.writeConfig(strings.Replace(cfg, "        retries: 1\n", "        retries: 1\n      seed:\n        timeout: 1m\n", 1))
+		out, err := env.run("list")
+		assertFailure(t, err)
+		assertContains(t, out, "unknown hook 'seed'")
+	})
+}
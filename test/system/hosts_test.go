@@ -0,0 +1,118 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHostsEntries verifies that new-feature and remove maintain a feature's
+// hosts block and that 'worktree hosts' prunes stale blocks.
+func TestHostsEntries(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	hostsFile := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(hostsFile, []byte("127.0.0.1 localhost\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := strings.Replace(worktreeConfig(), "default_preset: default\n", "default_preset: default\n\nhosts:\n  enabled: true\n  file: \""+hostsFile+"\"\n", 1)
+	env.writeConfig(cfg + `  REDIRECT_URI:
+    value: "http://{feature_host}/callback"
+    env: "REDIRECT_URI"
+`)
+	readHosts := func() string {
+		data, err := os.ReadFile(hostsFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	out, err := env.run("new-feature", "feature/named", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Hosts entries added")
+	assertContains(t, readHosts(), "127.0.0.1 testproject-feature-named.local")
+
+	out, err = env.run("env", "feature-named")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "REDIRECT_URI=http://testproject-feature-named.local/callback")
+
+	out, err = env.run("remove", "feature-named", "--force")
+	assertSuccess(t, out, err)
+	if got := readHosts(); got != "127.0.0.1 localhost\n" {
+		t.Errorf("hosts file after remove:\n%s", got)
+	}
+
+	t.Run("sync prunes stale blocks", func(t *testing.T) {
+		stale := "127.0.0.1 localhost\n\n# BEGIN worktree testproject/feature-gone\n127.0.0.1 testproject-feature-gone.local\n# END worktree testproject/feature-gone\n"
+		if err := os.WriteFile(hostsFile, []byte(stale), 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.run("new-feature", "feature/kept", "--no-start")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("hosts")
+		assertSuccess(t, out, err)
+		hosts := readHosts()
+		assertNotContains(t, hosts, "feature-gone")
+		assertContains(t, hosts, "testproject-feature-kept.local")
+
+		out, err = env.run("hosts", "--print")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "127.0.0.1 testproject-feature-kept.local")
+	})
+}
+
+// TestExtraHostsComposeOverride verifies that extra_hosts is rendered into the
+// project's compose override and handed to start commands via COMPOSE_FILE,
+// and that regen rewrites it after a config change.
+func TestExtraHostsComposeOverride(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeMockBinary("docker")
+
+	extraHosts := `    dir: "backend"
+    main_branch: "main"
+    start_command: "echo \"$COMPOSE_FILE\" > compose-file.txt"
+    extra_hosts:
+      compose_files: ["docker-compose.yml"]
+      services: ["api"]
+      hosts:
+        "api.{feature}.local": "host-gateway"
+`
+	cfg := strings.Replace(worktreeConfig(), "    dir: \"backend\"\n    main_branch: \"main\"\n", extraHosts, 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/dns", "--no-start")
+	assertSuccess(t, out, err)
+
+	backendDir := filepath.Join(env.root, "worktrees", "feature-dns", "backend")
+	overridePath := filepath.Join(backendDir, ".worktree-compose.yml")
+	data, err := os.ReadFile(overridePath)
+	if err != nil {
+		t.Fatalf("compose override not written: %v", err)
+	}
+	assertContains(t, string(data), "api:")
+	assertContains(t, string(data), "api.feature-dns.local: host-gateway")
+
+	out, err = env.run("start", "feature-dns")
+	assertSuccess(t, out, err)
+	data, err = os.ReadFile(filepath.Join(backendDir, "compose-file.txt"))
+	if err != nil {
+		t.Fatalf("start command did not run: %v", err)
+	}
+	want := filepath.Join(backendDir, "docker-compose.yml") + string(os.PathListSeparator) + overridePath
+	if strings.TrimSpace(string(data)) != want {
+		t.Errorf("COMPOSE_FILE = %q, want %q", strings.TrimSpace(string(data)), want)
+	}
+
+	env.writeConfig(strings.Replace(cfg, `"host-gateway"`, `"10.0.0.5"`, 1))
+	out, err = env.run("regen", "feature-dns")
+	assertSuccess(t, out, err)
+	data, _ = os.ReadFile(overridePath)
+	assertContains(t, string(data), "api.feature-dns.local: 10.0.0.5")
+}
@@ -0,0 +1,53 @@
+package system_test
+
+import (
+	"testing"
+)
+
+// TestLogsUsesRegistryComposeProjects verifies "worktree logs" runs compose logs
+// with the per-project compose names stored in the registry.
+func TestLogsUsesRegistryComposeProjects(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/logs-test")
+	assertSuccess(t, out, err)
+
+	// Mock docker: echo the invocation instead of talking to a daemon
+	env.writeMockBinary("docker",
+		`echo "mock-docker: $* (dir=$(basename "$PWD"), compose=$COMPOSE_PROJECT_NAME)"`)
+
+	t.Run("single project with tail", func(t *testing.T) {
+		out, err := env.run("logs", "feature-logs-test", "backend", "--tail", "20")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "mock-docker: compose -p testproject-feature-logs-test logs --tail 20 (dir=backend, compose=testproject-feature-logs-test)")
+		assertNotContains(t, out, "dir=frontend")
+	})
+
+	t.Run("all projects by default", func(t *testing.T) {
+		out, err := env.run("logs", "feature-logs-test")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "-p testproject-feature-logs-test logs (dir=backend")
+		assertContains(t, out, "-p testproject-feature-logs-test logs (dir=frontend")
+	})
+
+	t.Run("start --attach follows new logs", func(t *testing.T) {
+		out, err := env.run("start", "feature-logs-test", "--project", "backend", "--attach")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Attached to logs of feature feature-logs-test")
+		assertContains(t, out, "mock-docker: compose -p testproject-feature-logs-test logs --follow --since ")
+		assertNotContains(t, out, "dir=frontend")
+		assertContains(t, out, "Detached - services are still running")
+	})
+
+	t.Run("unknown project fails", func(t *testing.T) {
+		out, err := env.run("logs", "feature-logs-test", "nope")
+		assertFailure(t, err)
+		assertContains(t, out, "Project 'nope' not found")
+	})
+}
@@ -0,0 +1,66 @@
+package system_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestMergeDriverRegenerate verifies that a rebase conflicting on a tracked
+// generated file is resolved by re-rendering the file for the feature.
+func TestMergeDriverRegenerate(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	backend := filepath.Join(env.root, "backend")
+	writeAndCommit := func(dir, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ".env.local"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(dir, "add", ".env.local")
+		env.gitRun(dir, "commit", "-m", msg)
+	}
+	writeAndCommit(backend, "API_PORT=8080\n", "track env file")
+
+	env.writeConfig(worktreeConfig() + `
+merge_driver: regenerate
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "API_PORT={APP_PORT}\n"
+`)
+
+	out, err := env.run("new-feature", "feature/merge-driver", "--no-start")
+	assertSuccess(t, out, err)
+
+	data, err := os.ReadFile(filepath.Join(backend, ".git", "info", "attributes"))
+	if err != nil {
+		t.Fatalf("attributes not written: %v", err)
+	}
+	assertContains(t, string(data), "/.env.local merge=worktree-generated")
+
+	worktree := filepath.Join(env.root, "worktrees", "feature-merge-driver", "backend")
+	writeAndCommit(worktree, "API_PORT=9090\n", "feature ports")
+	writeAndCommit(backend, "API_PORT=8080\nDEBUG=1\n", "main change")
+
+	if out, err := exec.Command("git", "-C", worktree, "rebase", "main").CombinedOutput(); err != nil {
+		t.Fatalf("rebase stopped: %v\n%s", err, out)
+	}
+	data, err = os.ReadFile(filepath.Join(worktree, ".env.local"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "API_PORT=9090\n" {
+		t.Errorf(".env.local = %q, want the regenerated file", data)
+	}
+
+	t.Run("invalid driver is rejected", func(t *testing.T) {
+		env.writeConfig(worktreeConfig() + "merge_driver: theirs\n")
+		out, err := env.run("list")
+		assertFailure(t, err)
+		assertContains(t, out, "merge_driver must be")
+	})
+}
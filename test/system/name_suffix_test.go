@@ -0,0 +1,53 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// TestNormalizedNameSuffix verifies normalized_name_suffix: hash gives branches
+// that normalize alike their own features, and that commands accept both the
+// bare and the suffixed name.
+func TestNormalizedNameSuffix(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + "normalized_name_suffix: hash\n")
+
+	out, err := env.run("new-feature", "feature/dup", "--no-start")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	name := regexp.MustCompile(`feature-dup-[0-9a-f]{6}`).FindString(out)
+	if name == "" {
+		t.Fatalf("no hash-suffixed feature name in output")
+	}
+	if _, err := os.Stat(filepath.Join(env.root, "worktrees", name)); err != nil {
+		t.Fatalf("feature directory %s not created: %v", name, err)
+	}
+
+	t.Run("bare and suffixed names resolve", func(t *testing.T) {
+		for _, input := range []string{"feature-dup", "feature/dup", name} {
+			out, err := env.run("status", input)
+			assertSuccess(t, out, err)
+			assertContains(t, out, "Status for Feature: "+name)
+		}
+	})
+
+	t.Run("colliding branch gets its own feature", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature_dup", "--no-start")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "already exists")
+
+		// The bare name is now ambiguous; the suffixed names still work
+		out, err = env.run("status", "feature-dup")
+		assertFailure(t, err)
+		out, err = env.run("status", name)
+		assertSuccess(t, out, err)
+		out, err = env.run("status", "feature_dup")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "Status for Feature: "+name)
+	})
+}
@@ -0,0 +1,69 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestServiceOrphans verifies that containers of services no longer in the
+// compose file are listed by status and doctor, kept by stop and removed by
+// stop --remove-orphans.
+func TestServiceOrphans(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	dockerLog := filepath.Join(env.root, "docker.log")
+	env.writeMockBinary("docker",
+		`case "$1" in`,
+		`  ps)`,
+		`    proj=""`,
+		`    for a in "$@"; do case "$a" in label=com.docker.compose.project=*) proj="${a#label=com.docker.compose.project=}";; esac; done`,
+		`    if [ -n "$proj" ]; then printf '%s-app-1\tapp\trunning\n%s-old-1\told\trunning\n' "$proj" "$proj"; else echo testproject-feature-o-app-1; fi ;;`,
+		`  compose) case "$*" in *"config --services"*) echo app;; *) echo "$@" >> "`+dockerLog+`";; esac ;;`,
+		`  stop|rm) echo "$@" >> "`+dockerLog+`" ;;`,
+		`esac`,
+	)
+
+	out, err := env.run("new-feature", "feature/o", "--no-start")
+	assertSuccess(t, out, err)
+
+	t.Run("status lists orphans", func(t *testing.T) {
+		out, err := env.run("status", "feature-o")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend, frontend: testproject-feature-o-old-1 (service 'old' is no longer in the compose file)")
+		assertContains(t, out, "worktree stop feature-o --remove-orphans")
+		assertNotContains(t, out, "app-1 (service")
+	})
+
+	t.Run("doctor reports and plans removal", func(t *testing.T) {
+		out, _ := env.run("doctor", "--no-fetch")
+		assertContains(t, out, "1 compose projects with orphaned service containers")
+		out, _ = env.run("doctor", "--fix", "--dry-run", "--no-fetch")
+		assertContains(t, out, "Would remove orphaned containers testproject-feature-o-old-1")
+	})
+
+	t.Run("stop keeps orphans", func(t *testing.T) {
+		os.Remove(dockerLog)
+		out, err := env.run("stop", "feature-o")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "orphaned containers kept")
+		data, _ := os.ReadFile(dockerLog)
+		assertContains(t, string(data), "stop testproject-feature-o-old-1")
+		assertNotContains(t, string(data), "rm -f")
+	})
+
+	t.Run("stop --remove-orphans removes them", func(t *testing.T) {
+		os.Remove(dockerLog)
+		out, err := env.run("stop", "feature-o", "--remove-orphans")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend: removed 1 orphaned containers")
+		data, _ := os.ReadFile(dockerLog)
+		if n := strings.Count(string(data), "rm -f testproject-feature-o-old-1"); n != 1 {
+			t.Errorf("orphan removed %d times, want once (compose project is shared):\n%s", n, data)
+		}
+	})
+}
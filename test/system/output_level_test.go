@@ -0,0 +1,73 @@
+package system_test
+
+import (
+	"testing"
+)
+
+// TestOutputLevels verifies --quiet drops progress output while keeping the
+// result, and that verbose mode (flag or WORKTREE_LOG_LEVEL) shows the git
+// commands being run, limited to some subsystems with --verbose=<scopes> or
+// WORKTREE_DEBUG.
+func TestOutputLevels(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	t.Run("quiet", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/quiet", "--no-start", "--quiet")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "Allocating ports")
+		assertNotContains(t, out, "✅")
+		assertNotContains(t, out, "$ git")
+
+		out, err = env.run("list", "-q")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-quiet")
+	})
+
+	t.Run("verbose via env", func(t *testing.T) {
+		t.Setenv("WORKTREE_LOG_LEVEL", "verbose")
+		out, err := env.run("remove", "feature-quiet", "--force")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Loaded configuration from")
+		assertContains(t, out, "worktree remove")
+		assertContains(t, out, "$ git -C")
+	})
+
+	t.Run("scoped verbose", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/scoped", "--no-start", "--verbose=registry")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "[registry] ")
+		assertContains(t, out, "allocated port")
+		assertNotContains(t, out, "$ git")
+		assertNotContains(t, out, "[config]")
+
+		t.Setenv("WORKTREE_DEBUG", "git")
+		out, err = env.run("remove", "feature-scoped", "--force")
+		t.Logf("output:\n%s", out)
+		assertSuccess(t, out, err)
+		assertContains(t, out, "$ git -C")
+		assertNotContains(t, out, "Loaded configuration from")
+
+		t.Setenv("WORKTREE_DEBUG", "compose")
+		out, err = env.run("list")
+		assertFailure(t, err)
+		assertContains(t, out, "unknown verbose scope 'compose'")
+	})
+
+	t.Run("invalid level and conflicting flags", func(t *testing.T) {
+		t.Setenv("WORKTREE_LOG_LEVEL", "loud")
+		out, err := env.run("list")
+		assertFailure(t, err)
+		assertContains(t, out, "unknown log level 'loud'")
+
+		t.Setenv("WORKTREE_LOG_LEVEL", "")
+		out, err = env.run("list", "--quiet", "--verbose")
+		assertFailure(t, err)
+		assertContains(t, out, "quiet")
+	})
+}
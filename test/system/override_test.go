@@ -0,0 +1,117 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestOverrideAppliedOnStart verifies that per-feature overrides set with
+// "worktree override" reach start hooks, the registry computed vars and the
+// value templates built from an overridden port.
+func TestOverrideAppliedOnStart(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	env.writeConfig(`project_name: "testproject"
+hostname: localhost
+
+projects:
+  backend:
+    dir: "backend"
+    main_branch: "main"
+    start_command: "echo \"$API_URL\" > api_url.txt"
+  frontend:
+    dir: "frontend"
+    main_branch: "main"
+
+presets:
+  default:
+    projects: ["backend", "frontend"]
+
+default_preset: default
+
+env_variables:
+  APP_PORT:
+    name: "Backend API"
+    port: "9090"
+    env: "APP_PORT"
+    range: [9090, 9190]
+  API_URL:
+    value: "http://{host}:{APP_PORT}"
+    env: "API_URL"
+`)
+
+	out, err := env.run("new-feature", "feature/override-test")
+	assertSuccess(t, out, err)
+
+	t.Run("no overrides listed initially", func(t *testing.T) {
+		out, err := env.run("override", "feature-override-test")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "No overrides set")
+	})
+
+	t.Run("invalid assignment rejected", func(t *testing.T) {
+		_, err := env.run("override", "feature-override-test", "NOEQUALS")
+		assertFailure(t, err)
+	})
+
+	out, err = env.run("override", "feature-override-test", "API_URL=https://staging.example.com", "EXTRA_FLAG=on")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "API_URL=https://staging.example.com")
+
+	featureDir := filepath.Join(env.root, "worktrees", "feature-override-test")
+	if _, err := os.Stat(filepath.Join(featureDir, ".worktree-overrides.yml")); err != nil {
+		t.Fatalf("overrides file not written: %v", err)
+	}
+
+	out, err = env.run("start", "feature-override-test")
+	t.Logf("start output:\n%s", out)
+	assertSuccess(t, out, err)
+
+	t.Run("start command sees override", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(featureDir, "backend", "api_url.txt"))
+		if err != nil {
+			t.Fatalf("start_command did not run: %v", err)
+		}
+		if got := strings.TrimSpace(string(data)); got != "https://staging.example.com" {
+			t.Errorf("API_URL = %q, want override value", got)
+		}
+	})
+
+	t.Run("get-env returns override and extras", func(t *testing.T) {
+		out, err := env.run("get-env", "feature-override-test", "EXTRA_FLAG")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "on")
+	})
+
+	t.Run("overridden port reaches dependent values", func(t *testing.T) {
+		out, err := env.run("override", "feature-override-test", "--unset", "API_URL", "APP_PORT=9999")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "✓ Updated")
+
+		out, err = env.run("start", "feature-override-test")
+		assertSuccess(t, out, err)
+		data, err := os.ReadFile(filepath.Join(featureDir, "backend", "api_url.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(string(data)); got != "http://localhost:9999" {
+			t.Errorf("API_URL = %q, want it built from the overridden APP_PORT", got)
+		}
+
+		out, err = env.run("get-env", "feature-override-test", "API_URL")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "http://localhost:9999")
+	})
+
+	t.Run("unset removes override", func(t *testing.T) {
+		out, err := env.run("override", "feature-override-test", "--unset", "APP_PORT", "--unset", "EXTRA_FLAG")
+		assertSuccess(t, out, err)
+		if _, err := os.Stat(filepath.Join(featureDir, ".worktree-overrides.yml")); !os.IsNotExist(err) {
+			t.Error("expected overrides file to be removed once empty")
+		}
+	})
+}
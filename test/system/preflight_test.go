@@ -0,0 +1,58 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPreflight verifies new-feature and start refuse to run, before changing
+// anything, when the docker daemon is down or the disk is too full.
+func TestPreflight(t *testing.T) {
+	t.Setenv("WORKTREE_SKIP_PREFLIGHT", "")
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(strings.Replace(worktreeConfig(),
+		"    dir: \"backend\"\n    main_branch: \"main\"\n",
+		"    dir: \"backend\"\n    main_branch: \"main\"\n    start_command: \"true\"\n", 1))
+	// docker is installed but "docker ps" cannot reach the daemon
+	env.writeMockBinary("docker", `if [ "$1" = "ps" ]; then echo "Cannot connect to the Docker daemon" >&2; exit 1; fi`)
+
+	t.Run("daemon down blocks new-feature", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/pre")
+		t.Logf("output:\n%s", out)
+		assertFailure(t, err)
+		assertContains(t, out, "Docker daemon is not running")
+		assertContains(t, out, "--skip-preflight")
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-pre")); err == nil {
+			t.Error("feature directory was created despite the failed pre-flight")
+		}
+	})
+
+	t.Run("no-start does not need docker", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/pre", "--no-start")
+		assertSuccess(t, out, err)
+	})
+
+	t.Run("daemon down blocks start", func(t *testing.T) {
+		out, err := env.run("start", "feature-pre")
+		t.Logf("output:\n%s", out)
+		assertFailure(t, err)
+		assertContains(t, out, "Docker daemon is not running")
+
+		out, err = env.run("start", "feature-pre", "--skip-preflight")
+		assertSuccess(t, out, err)
+	})
+
+	t.Run("disk threshold", func(t *testing.T) {
+		env.writeMockBinary("docker")
+		env.writeConfig(worktreeConfig() + "min_free_disk_mb: 1000000000\n")
+		out, err := env.run("start", "feature-pre")
+		t.Logf("output:\n%s", out)
+		assertFailure(t, err)
+		assertContains(t, out, "MB of disk space free")
+		assertContains(t, out, "min_free_disk_mb")
+	})
+}
@@ -0,0 +1,53 @@
+package system_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPrompt verifies the shell prompt segment reads the instance marker and
+// the cached status written by lifecycle commands.
+func TestPrompt(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/prompt-test")
+	assertSuccess(t, out, err)
+
+	featureDir := filepath.Join(env.root, "worktrees", "feature-prompt-test", "backend")
+
+	t.Run("outside a worktree prints nothing", func(t *testing.T) {
+		out, err := env.run("prompt")
+		assertSuccess(t, out, err)
+		if strings.TrimSpace(out) != "" {
+			t.Errorf("expected empty output, got %q", out)
+		}
+	})
+
+	t.Run("stop caches stopped state", func(t *testing.T) {
+		out, err := env.run("stop", "feature-prompt-test")
+		assertSuccess(t, out, err)
+
+		out, err = env.runFrom(featureDir, "prompt", "--no-color")
+		assertSuccess(t, out, err)
+		if !strings.HasPrefix(out, "feature-prompt-test:") || !strings.Contains(out, "○") {
+			t.Errorf("unexpected prompt %q", out)
+		}
+	})
+
+	t.Run("custom format", func(t *testing.T) {
+		out, err := env.runFrom(featureDir, "prompt", "--format", "[{feature} {status}]")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "[feature-prompt-test stopped]")
+	})
+
+	t.Run("accessible mode uses words", func(t *testing.T) {
+		out, err := env.runFrom(featureDir, "prompt", "--accessible")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "stopped")
+		assertNotContains(t, out, "○")
+	})
+}
@@ -0,0 +1,48 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestProxyConfig verifies that new-feature writes the feature's reverse-proxy
+// rules and remove deletes them.
+func TestProxyConfig(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	cfg := strings.Replace(worktreeConfig(), "default_preset: default\n", `default_preset: default
+
+proxy:
+  provider: traefik
+  dir: proxy
+  routes:
+    - port: FE_PORT
+    - subdomain: api
+      port: APP_PORT
+`, 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/proxied", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Proxy config written")
+
+	path := filepath.Join(env.root, "proxy", "testproject-feature-proxied.yml")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("proxy config not written: %v", err)
+	}
+	assertContains(t, string(data), "Host(`feature-proxied.localhost`)")
+	assertContains(t, string(data), "Host(`api.feature-proxied.localhost`)")
+	assertContains(t, string(data), "http://localhost:9200")
+
+	out, err = env.run("remove", "feature-proxied", "--force")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Removed proxy config")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("proxy config still exists after remove")
+	}
+}
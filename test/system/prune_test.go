@@ -0,0 +1,146 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestPrune covers the merged and inactivity criteria, the dry run and the
+// protection of features with uncommitted changes.
+func TestPrune(t *testing.T) {
+	// Date the initial commits in the past so only registry creation time and
+	// the commits below decide a feature's age
+	t.Setenv("GIT_AUTHOR_DATE", "2020-01-01T00:00:00")
+	t.Setenv("GIT_COMMITTER_DATE", "2020-01-01T00:00:00")
+
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("docker", "exit 0")
+
+	for _, branch := range []string{"feature/merged", "feature/aged", "feature/dirty", "feature/active"} {
+		out, err := env.run("new-feature", branch)
+		assertSuccess(t, out, err)
+	}
+
+	worktreePath := func(feature, project string) string {
+		return filepath.Join(env.root, "worktrees", feature, project)
+	}
+	commitAt := func(dir, date string) {
+		t.Helper()
+		cmd := exec.Command("git", "commit", "--allow-empty", "-m", "work")
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git commit in %s: %v\n%s", dir, err, out)
+		}
+	}
+
+	// feature-merged: a commit after creation that landed on main
+	commitAt(worktreePath("feature-merged", "backend"), "2099-01-01T00:00:00")
+	env.gitRun(filepath.Join(env.root, "backend"), "merge", "--ff-only", "feature/merged")
+
+	// feature-aged and feature-dirty: created and last committed long ago
+	commitAt(worktreePath("feature-aged", "backend"), "2020-01-01T00:00:00")
+	commitAt(worktreePath("feature-dirty", "backend"), "2020-01-01T00:00:00")
+	if err := os.WriteFile(filepath.Join(worktreePath("feature-dirty", "backend"), "wip.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	setCreated(t, env, "2020-01-01T00:00:00Z", "feature-aged", "feature-dirty")
+
+	featureExists := func(feature string) bool {
+		_, err := os.Stat(filepath.Join(env.root, "worktrees", feature))
+		return err == nil
+	}
+
+	t.Run("dry run lists without removing", func(t *testing.T) {
+		out, err := env.run("prune", "--dry-run")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "feature-merged (feature/merged): merged into main")
+		assertContains(t, out, "feature-aged (feature/aged): no commits for")
+		assertContains(t, out, "feature-dirty: no commits for")
+		assertContains(t, out, "dirty: backend")
+		assertNotContains(t, out, "feature-active")
+		assertContains(t, out, "nothing removed")
+		if !featureExists("feature-merged") || !featureExists("feature-aged") {
+			t.Error("dry run removed a feature")
+		}
+	})
+
+	t.Run("merged only", func(t *testing.T) {
+		out, err := env.run("prune", "--merged", "--force")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Pruned 1 features")
+		if featureExists("feature-merged") {
+			t.Error("feature-merged should have been removed")
+		}
+		if !featureExists("feature-aged") {
+			t.Error("feature-aged should not be pruned by --merged")
+		}
+	})
+
+	t.Run("older than", func(t *testing.T) {
+		out, err := env.run("prune", "--older-than", "14d", "--force")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Pruned 1 features")
+		if featureExists("feature-aged") {
+			t.Error("feature-aged should have been removed")
+		}
+		if !featureExists("feature-dirty") || !featureExists("feature-active") {
+			t.Error("dirty and active features must be kept")
+		}
+
+		out, err = env.run("list")
+		assertSuccess(t, out, err)
+		assertNotContains(t, out, "feature-aged")
+	})
+
+	t.Run("declined confirmation", func(t *testing.T) {
+		os.Remove(filepath.Join(worktreePath("feature-dirty", "backend"), "wip.txt"))
+
+		out, err := env.run("prune")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Prune cancelled")
+		if !featureExists("feature-dirty") {
+			t.Error("feature removed without confirmation")
+		}
+	})
+
+	t.Run("invalid age", func(t *testing.T) {
+		_, err := env.run("prune", "--older-than", "soon")
+		assertFailure(t, err)
+	})
+}
+
+// setCreated rewrites the creation time of registry entries
+func setCreated(t *testing.T, env *TestEnv, created string, features ...string) {
+	t.Helper()
+	path := filepath.Join(env.root, "worktrees", ".registry.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read registry: %v", err)
+	}
+
+	var reg map[string]any
+	if err := json.Unmarshal(data, &reg); err != nil {
+		t.Fatalf("parse registry: %v", err)
+	}
+	worktrees, _ := reg["worktrees"].(map[string]any)
+	for _, feature := range features {
+		entry, ok := worktrees[feature].(map[string]any)
+		if !ok {
+			t.Fatalf("registry entry %s not found", feature)
+		}
+		entry["created"] = created
+	}
+
+	data, _ = json.MarshalIndent(reg, "", "  ")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("write registry: %v", err)
+	}
+	os.Remove(path + ".sha256") // Keep the hand edit, as a user would
+}
@@ -0,0 +1,203 @@
+package system_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestQueueTaskSelection verifies that queue show/remove accept short task IDs
+// and that remove accepts an agent and worktree instead of an ID.
+func TestQueueTaskSelection(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	queueFile := filepath.Join(env.root, "worktrees", ".queue.json")
+	taskIDs := func() []string {
+		t.Helper()
+		data, err := os.ReadFile(queueFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q struct {
+			Tasks []struct {
+				ID string `json:"id"`
+			} `json:"tasks"`
+		}
+		if err := json.Unmarshal(data, &q); err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, task := range q.Tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	for _, worktree := range []string{"feature-x", "feature-x", "feature-y"} {
+		out, err := env.run("agent", "queue", "add", "valid-task", worktree)
+		assertSuccess(t, out, err)
+	}
+	ids := taskIDs()
+
+	out, err := env.run("agent", "queue", "show", ids[2][:8])
+	assertSuccess(t, out, err)
+	assertContains(t, out, "ID: "+ids[2])
+	assertContains(t, out, "Worktree: feature-y")
+
+	out, err = env.run("agent", "queue", "show", "")
+	assertFailure(t, err)
+	assertContains(t, out, "cannot be empty")
+
+	out, err = env.run("agent", "queue", "remove", ids[2][:8]+"...")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Task removed from queue: "+ids[2])
+
+	t.Run("agent and worktree selector", func(t *testing.T) {
+		out, err := env.run("agent", "queue", "remove", "--agent", "valid-task")
+		assertFailure(t, err)
+		assertContains(t, out, "both --agent and --worktree")
+
+		out, err = env.run("agent", "queue", "remove", "--agent", "valid-task", "--worktree", "feature-x", "--status", "pending")
+		assertSuccess(t, out, err)
+		if remaining := taskIDs(); len(remaining) != 0 {
+			t.Errorf("expected an empty queue, found %v", remaining)
+		}
+	})
+}
+
+// TestQueueShowRunLog verifies that queued runs keep their output in a run
+// log and that queue show follows the log of a running task until it ends.
+func TestQueueShowRunLog(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	out, err := env.run("agent", "queue", "add", "valid-task", "feature-x")
+	assertSuccess(t, out, err)
+	out, err = env.run("agent", "queue", "start")
+	assertSuccess(t, out, err)
+
+	queueFile := filepath.Join(env.root, "worktrees", ".queue.json")
+	data, err := os.ReadFile(queueFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var q struct {
+		Tasks []map[string]interface{} `json:"tasks"`
+	}
+	if err := json.Unmarshal(data, &q); err != nil || len(q.Tasks) != 1 {
+		t.Fatalf("unexpected queue %s (err=%v)", data, err)
+	}
+	id := q.Tasks[0]["id"].(string)
+
+	logPath := filepath.Join(env.root, "worktrees", ".queue-logs", id+".log")
+	logData, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("run log not written: %v", err)
+	}
+	assertContains(t, string(logData), "working")
+	assertContains(t, string(logData), "Task completed successfully")
+
+	out, err = env.run("agent", "queue", "show", id[:8])
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Log: "+logPath)
+	assertNotContains(t, out, "Live output")
+
+	t.Run("follow running task", func(t *testing.T) {
+		q.Tasks[0]["status"] = "running"
+		delete(q.Tasks[0], "completed_at")
+		writeQueue := func() {
+			data, err := json.Marshal(q)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := os.WriteFile(queueFile, data, 0644); err != nil {
+				t.Error(err)
+			}
+		}
+		writeQueue()
+		if err := os.WriteFile(logPath, []byte("early output\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		out, err := env.run("agent", "queue", "show", id[:8], "--no-follow")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Output so far")
+		assertContains(t, out, "early output")
+
+		// The task writes more output and completes while show follows it
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			time.Sleep(time.Second)
+			f, err := os.OpenFile(logPath, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			f.WriteString("late output\n")
+			f.Close()
+			q.Tasks[0]["status"] = "completed"
+			writeQueue()
+		}()
+
+		out, err = env.run("agent", "queue", "show", id[:8])
+		<-done
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Live output")
+		assertContains(t, out, "early output")
+		assertContains(t, out, "late output")
+		assertContains(t, out, "Task completed")
+	})
+
+	out, err = env.run("agent", "queue", "clear")
+	assertSuccess(t, out, err)
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Errorf("run log of a cleared task was kept (err=%v)", err)
+	}
+}
+
+// TestQueueSQLiteStorage verifies that storage: sqlite imports the existing
+// queue file and keeps the queue in worktrees/.worktree.db from then on.
+func TestQueueSQLiteStorage(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	out, err := env.run("agent", "queue", "add", "valid-task", "feature-json")
+	assertSuccess(t, out, err)
+
+	env.writeConfig("storage: sqlite\n" + minimalConfig(validAgentYAML))
+	out, err = env.run("agent", "queue", "add", "valid-task", "feature-sqlite")
+	assertSuccess(t, out, err)
+
+	out, err = env.run("agent", "queue", "list")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "feature-json")
+	assertContains(t, out, "feature-sqlite")
+
+	worktrees := filepath.Join(env.root, "worktrees")
+	if _, err := os.Stat(filepath.Join(worktrees, ".worktree.db")); err != nil {
+		t.Errorf("database not created: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktrees, ".queue.json")); !os.IsNotExist(err) {
+		t.Errorf(".queue.json still exists after migration (err = %v)", err)
+	}
+	if _, err := os.Stat(filepath.Join(worktrees, ".queue.json.migrated")); err != nil {
+		t.Errorf("migrated queue file not kept: %v", err)
+	}
+
+	out, err = env.run("agent", "queue", "remove", "--agent", "valid-task", "--worktree", "feature-json")
+	assertSuccess(t, out, err)
+	out, err = env.run("agent", "queue", "list")
+	assertSuccess(t, out, err)
+	assertNotContains(t, out, "feature-json")
+	assertContains(t, out, "feature-sqlite")
+
+	env.writeConfig("storage: postgres\n" + minimalConfig(validAgentYAML))
+	out, err = env.run("agent", "queue", "list")
+	assertFailure(t, err)
+	assertContains(t, out, "storage: unknown backend 'postgres'")
+}
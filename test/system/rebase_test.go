@@ -0,0 +1,143 @@
+package system_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRebaseContinueAbort covers resuming and aborting a rebase that stopped
+// on conflicts in one project of a feature.
+func TestRebaseContinueAbort(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	out, err := env.run("new-feature", "feature/r")
+	assertSuccess(t, out, err)
+
+	writeAndCommit := func(dir, file, content, msg string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(dir, "add", file)
+		env.gitRun(dir, "commit", "-m", msg)
+	}
+	head := func(dir string) string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("git rev-parse in %s: %v", dir, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	containsMain := func(dir string) bool {
+		return exec.Command("git", "-C", dir, "merge-base", "--is-ancestor", "main", "HEAD").Run() == nil
+	}
+
+	backendWT := filepath.Join(env.root, "worktrees", "feature-r", "backend")
+	frontendWT := filepath.Join(env.root, "worktrees", "feature-r", "frontend")
+	writeAndCommit(backendWT, "shared.txt", "feature\n", "feature change")
+	writeAndCommit(frontendWT, "ui.txt", "feature\n", "feature ui")
+	for _, project := range []string{"backend", "frontend"} {
+		dir := filepath.Join(env.root, project)
+		writeAndCommit(dir, "shared.txt", "main\n", "main change")
+		env.gitRun(dir, "push", "origin", "main")
+	}
+	featureHead := head(backendWT)
+
+	t.Run("conflict stops the rebase", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-r")
+		assertFailure(t, err)
+		assertContains(t, out, "backend rebase failed")
+		assertContains(t, out, "worktree rebase feature-r --continue")
+	})
+
+	t.Run("plain rebase refused while in progress", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-r")
+		assertFailure(t, err)
+		assertContains(t, out, "Rebase already in progress in: backend")
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-r", "--abort")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend rebase aborted")
+		if got := head(backendWT); got != featureHead {
+			t.Errorf("backend HEAD after abort = %s, want %s", got, featureHead)
+		}
+	})
+
+	t.Run("continue after resolving", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-r")
+		assertFailure(t, err)
+
+		if err := os.WriteFile(filepath.Join(backendWT, "shared.txt"), []byte("resolved\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(backendWT, "add", "shared.txt")
+
+		out, err = env.run("rebase", "feature-r", "--continue")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Continuing backend rebase")
+		assertContains(t, out, "frontend rebased successfully")
+		if !containsMain(backendWT) || !containsMain(frontendWT) {
+			t.Error("feature branches do not contain main after --continue")
+		}
+	})
+
+	t.Run("continue without rebase", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-r", "--continue")
+		assertFailure(t, err)
+		assertContains(t, out, "No rebase in progress")
+	})
+}
+
+// TestRebaseGitParallelism covers git_parallelism: projects are reported in
+// order, and with one at a time a conflict leaves later projects untouched.
+func TestRebaseGitParallelism(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + "git_parallelism: 1\n")
+
+	env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	out, err := env.run("new-feature", "feature/p")
+	assertSuccess(t, out, err)
+
+	backendWT := filepath.Join(env.root, "worktrees", "feature-p", "backend")
+	frontendWT := filepath.Join(env.root, "worktrees", "feature-p", "frontend")
+	for _, dir := range []string{backendWT, filepath.Join(env.root, "backend")} {
+		if err := os.WriteFile(filepath.Join(dir, "shared.txt"), []byte(dir+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(dir, "add", "shared.txt")
+		env.gitRun(dir, "commit", "-m", "change shared.txt")
+	}
+	env.gitRun(filepath.Join(env.root, "backend"), "push", "origin", "main")
+	env.gitRun(filepath.Join(env.root, "frontend"), "commit", "--allow-empty", "-m", "main change")
+	env.gitRun(filepath.Join(env.root, "frontend"), "push", "origin", "main")
+
+	out, err = env.run("push", "feature-p")
+	assertSuccess(t, out, err)
+	if b, f := strings.Index(out, "backend pushed"), strings.Index(out, "frontend pushed"); b < 0 || f < b {
+		t.Errorf("push output not in project order\n--- output ---\n%s", out)
+	}
+
+	out, err = env.run("rebase", "feature-p")
+	assertFailure(t, err)
+	assertContains(t, out, "backend rebase failed")
+	assertNotContains(t, out, "Rebasing frontend")
+	if exec.Command("git", "-C", frontendWT, "merge-base", "--is-ancestor", "main", "HEAD").Run() == nil {
+		t.Error("frontend was rebased although backend stopped on conflicts")
+	}
+}
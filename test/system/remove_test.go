@@ -0,0 +1,100 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRemoveUnpushedCommits covers the guard against removing features whose
+// commits exist only on the local branch.
+func TestRemoveUnpushedCommits(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("docker", "exit 0")
+
+	out, err := env.run("new-feature", "feature/work")
+	assertSuccess(t, out, err)
+
+	featureDir := filepath.Join(env.root, "worktrees", "feature-work")
+	env.gitRun(filepath.Join(featureDir, "backend"), "commit", "--allow-empty", "-m", "work")
+
+	t.Run("refused without flag", func(t *testing.T) {
+		out, err := env.run("remove", "feature-work")
+		assertFailure(t, err)
+		assertContains(t, out, "backend: 1 commits not in main")
+		assertContains(t, out, "backend: 1 unpushed commits")
+		assertContains(t, out, "Refusing to remove")
+		assertNotContains(t, out, "frontend: 1")
+		if _, err := os.Stat(featureDir); err != nil {
+			t.Error("feature removed despite unpushed commits")
+		}
+	})
+
+	t.Run("discard-unpushed still confirms", func(t *testing.T) {
+		out, err := env.run("remove", "feature-work", "--discard-unpushed")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Are you sure")
+		assertContains(t, out, "Removal cancelled")
+	})
+
+	t.Run("pushed but unmerged only warns", func(t *testing.T) {
+		remote := filepath.Join(env.root, "backend-remote.git")
+		env.gitRun(env.root, "init", "--bare", remote)
+		backend := filepath.Join(env.root, "backend")
+		env.gitRun(backend, "remote", "add", "origin", remote)
+		env.gitRun(backend, "push", "origin", "main", "feature/work")
+
+		out, err := env.run("remove", "feature-work")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend: 1 commits not in origin/main")
+		assertNotContains(t, out, "unpushed commits")
+		assertContains(t, out, "Removal cancelled")
+	})
+
+	t.Run("force removes", func(t *testing.T) {
+		env.gitRun(filepath.Join(featureDir, "backend"), "commit", "--allow-empty", "-m", "more work")
+
+		out, err := env.run("remove", "feature-work", "--force")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend: 1 unpushed commits")
+		if _, err := os.Stat(featureDir); !os.IsNotExist(err) {
+			t.Error("feature directory still exists after --force")
+		}
+	})
+}
+
+// TestRemoveConfirmation covers the global --yes and --non-interactive answers
+// to the removal prompt.
+func TestRemoveConfirmation(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("docker", "exit 0")
+
+	out, err := env.run("new-feature", "feature/confirm")
+	assertSuccess(t, out, err)
+	featureDir := filepath.Join(env.root, "worktrees", "feature-confirm")
+
+	t.Run("non-interactive declines", func(t *testing.T) {
+		out, err := env.run("remove", "feature-confirm", "--non-interactive")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "[y/N]: n (non-interactive; use --yes to confirm)")
+		assertContains(t, out, "Removal cancelled")
+		if _, err := os.Stat(featureDir); err != nil {
+			t.Error("feature removed without confirmation")
+		}
+	})
+
+	t.Run("yes confirms", func(t *testing.T) {
+		out, err := env.run("remove", "feature-confirm", "-y")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "[y/N]: y (--yes)")
+		if _, err := os.Stat(featureDir); !os.IsNotExist(err) {
+			t.Error("feature directory still exists after --yes")
+		}
+	})
+}
@@ -212,3 +212,68 @@ env_variables:
 func TestRestartAutoDetect(t *testing.T) {
 	t.Skip("Auto-detection has known limitation with config.New() in test environments")
 }
+
+// TestProjectScopedLifecycle verifies that start, stop and restart with
+// --project only run the hooks and start command of the selected project.
+func TestProjectScopedLifecycle(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	config := `project_name: "testproject"
+hostname: localhost
+
+projects:
+  backend:
+    dir: "backend"
+    main_branch: "main"
+    start_command: "echo started-backend"
+    stop_pre_command: "echo stopping-backend"
+    restart_pre_command: "echo restarting-backend"
+  frontend:
+    dir: "frontend"
+    main_branch: "main"
+    start_command: "echo started-frontend"
+    stop_pre_command: "echo stopping-frontend"
+    restart_pre_command: "echo restarting-frontend"
+
+presets:
+  default:
+    projects: ["backend", "frontend"]
+
+default_preset: default
+
+env_variables:
+  APP_PORT:
+    port: "9090"
+    env: "APP_PORT"
+    range: [9090, 9190]
+`
+	env.writeConfig(config)
+
+	out, err := env.run("new-feature", "feature/scoped", "--no-start")
+	assertSuccess(t, out, err)
+
+	out, err = env.run("start", "feature-scoped", "--project", "frontend")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "started-frontend")
+	assertNotContains(t, out, "started-backend")
+	assertContains(t, out, "Started frontend")
+
+	out, err = env.run("stop", "feature-scoped", "--project", "backend")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "stopping-backend")
+	assertNotContains(t, out, "stopping-frontend")
+
+	out, err = env.run("restart", "feature-scoped", "--project", "backend")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "restarting-backend")
+	assertContains(t, out, "started-backend")
+	assertNotContains(t, out, "frontend")
+
+	t.Run("unknown project", func(t *testing.T) {
+		out, err := env.run("stop", "feature-scoped", "--project", "api")
+		assertFailure(t, err)
+		assertContains(t, out, "project 'api' is not part of this feature")
+	})
+}
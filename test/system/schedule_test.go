@@ -0,0 +1,102 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAgentScheduleCron exercises agent schedule against a mock crontab that
+// stores the table in a file: dry-run, idempotent re-runs, in-place updates and removal.
+func TestAgentScheduleCron(t *testing.T) {
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(validAgentYAML))
+
+	crontabFile := filepath.Join(env.binDir, "crontab.txt")
+	env.writeMockBinary("crontab",
+		`file="`+crontabFile+`"`,
+		`if [ "$1" = "-l" ]; then`,
+		`  if [ -f "$file" ]; then cat "$file"; exit 0; fi`,
+		`  echo "no crontab for $USER" >&2; exit 1`,
+		`fi`,
+		`if [ "$1" = "-" ]; then cat > "$file"; fi`,
+	)
+	if err := os.WriteFile(crontabFile, []byte("0 1 * * * /usr/bin/backup\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	readCrontab := func() string {
+		t.Helper()
+		data, err := os.ReadFile(crontabFile)
+		if err != nil {
+			t.Fatalf("read mock crontab: %v", err)
+		}
+		return string(data)
+	}
+
+	t.Run("dry run prints entry without writing", func(t *testing.T) {
+		out, err := env.run("agent", "schedule", "valid-task", "--backend", "cron", "--dry-run")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "would be created")
+		assertContains(t, out, "# BEGIN com.worktree.testproject.valid-task")
+		assertContains(t, out, "0 9 * * MON")
+		assertNotContains(t, readCrontab(), "valid-task")
+	})
+
+	t.Run("schedule creates entry", func(t *testing.T) {
+		out, err := env.run("agent", "schedule", "valid-task", "--backend", "cron")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "created")
+
+		tab := readCrontab()
+		assertContains(t, tab, "/usr/bin/backup")
+		assertContains(t, tab, "0 9 * * MON")
+		assertContains(t, tab, "agent run 'valid-task'")
+	})
+
+	t.Run("re-run is a no-op", func(t *testing.T) {
+		out, err := env.run("agent", "schedule", "--all", "--backend", "cron")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "already up to date")
+		if n := strings.Count(readCrontab(), "# BEGIN"); n != 1 {
+			t.Errorf("expected 1 entry, found %d:\n%s", n, readCrontab())
+		}
+	})
+
+	t.Run("edited schedule updates in place", func(t *testing.T) {
+		env.writeConfig(minimalConfig(strings.Replace(validAgentYAML, "0 9 * * MON", "30 6 * * *", 1)))
+
+		out, err := env.run("agent", "schedule", "valid-task", "--backend", "cron")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "updated")
+
+		tab := readCrontab()
+		assertContains(t, tab, "30 6 * * *")
+		assertNotContains(t, tab, "0 9 * * MON")
+		if n := strings.Count(tab, "# BEGIN"); n != 1 {
+			t.Errorf("expected 1 entry, found %d:\n%s", n, tab)
+		}
+	})
+
+	t.Run("remove deletes only the generated entry", func(t *testing.T) {
+		out, err := env.run("agent", "schedule", "remove", "valid-task", "--backend", "cron")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Removed cron entry")
+
+		tab := readCrontab()
+		assertNotContains(t, tab, "valid-task")
+		assertContains(t, tab, "/usr/bin/backup")
+	})
+
+	t.Run("remove missing entry", func(t *testing.T) {
+		out, err := env.run("agent", "schedule", "remove", "valid-task", "--backend", "cron")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "No cron entry found")
+	})
+
+	t.Run("task name or --all required", func(t *testing.T) {
+		_, err := env.run("agent", "schedule", "--backend", "cron")
+		assertFailure(t, err)
+	})
+}
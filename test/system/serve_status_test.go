@@ -0,0 +1,146 @@
+package system_test
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestServeStatus starts the status page server and exercises the HTML page,
+// the JSON API and the stop action.
+func TestServeStatus(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(strings.Replace(worktreeConfig(), `    port: "9090"`, `    url: "http://{host}:{port}"
+    port: "9090"`, 1))
+
+	out, err := env.run("new-feature", "feature/served")
+	assertSuccess(t, out, err)
+
+	// Reserve a free port for the server
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	server := exec.Command(testBinary, "serve-status", "--listen", addr)
+	server.Dir = env.root
+	server.Env = append(os.Environ(), "PATH="+env.binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		server.Process.Kill()
+		server.Wait()
+	}()
+
+	base := "http://" + addr
+	get := func(path string) string {
+		t.Helper()
+		var lastErr error
+		for i := 0; i < 50; i++ {
+			resp, err := http.Get(base + path)
+			if err == nil {
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				return string(body)
+			}
+			lastErr = err
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("server not reachable: %v", lastErr)
+		return ""
+	}
+
+	t.Run("html page lists features and URLs", func(t *testing.T) {
+		page := get("/")
+		assertContains(t, page, "testproject worktrees")
+		assertContains(t, page, "feature-served")
+		assertContains(t, page, `href="http://localhost:90`)
+		assertContains(t, page, `action="/api/features/feature-served/stop"`)
+	})
+
+	t.Run("json api", func(t *testing.T) {
+		var features []struct {
+			Name     string            `json:"name"`
+			Running  bool              `json:"running"`
+			Services map[string]string `json:"services"`
+		}
+		if err := json.Unmarshal([]byte(get("/api/features")), &features); err != nil {
+			t.Fatal(err)
+		}
+		if len(features) != 1 || features[0].Name != "feature-served" || features[0].Running {
+			t.Fatalf("unexpected features %+v", features)
+		}
+		if !strings.HasPrefix(features[0].Services["Backend API"], "http://localhost:90") {
+			t.Errorf("services = %v", features[0].Services)
+		}
+	})
+
+	t.Run("stop action", func(t *testing.T) {
+		resp, err := http.Post(base+"/api/features/feature-served/stop", "application/json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Success bool   `json:"success"`
+			Output  string `json:"output"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		if !result.Success {
+			t.Fatalf("stop failed: %s", result.Output)
+		}
+		assertContains(t, result.Output, "stopped")
+
+		if _, err := os.Stat(filepath.Join(env.root, "worktrees", "feature-served", ".worktree-status.json")); err != nil {
+			t.Errorf("stop did not record status: %v", err)
+		}
+	})
+
+	t.Run("html form redirects with message", func(t *testing.T) {
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		resp, err := client.PostForm(base+"/api/features/feature-served/stop", url.Values{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusSeeOther || !strings.Contains(resp.Header.Get("Location"), "stop+done") {
+			t.Errorf("got %d %q, want redirect with message", resp.StatusCode, resp.Header.Get("Location"))
+		}
+	})
+
+	t.Run("unknown feature and cross-origin are rejected", func(t *testing.T) {
+		resp, err := http.Post(base+"/api/features/nope/start", "application/json", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("unknown feature: status %d, want 404", resp.StatusCode)
+		}
+
+		req, _ := http.NewRequest(http.MethodPost, base+"/api/features/feature-served/stop", nil)
+		req.Header.Set("Origin", "http://evil.example")
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("cross-origin: status %d, want 403", resp.StatusCode)
+		}
+	})
+
+}
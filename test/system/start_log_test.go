@@ -0,0 +1,48 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewFeatureStartLog verifies that start command output is kept out of the
+// terminal unless --verbose, and always written to the feature's start log.
+func TestNewFeatureStartLog(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeMockBinary("docker")
+
+	cfg := strings.Replace(worktreeConfig(), "    dir: \"backend\"\n    main_branch: \"main\"\n",
+		"    dir: \"backend\"\n    main_branch: \"main\"\n    start_command: \"echo build-step-output; test -z \\\"$FAIL_START\\\"\"\n", 1)
+	env.writeConfig(cfg)
+
+	out, err := env.run("new-feature", "feature/quiet-start")
+	assertSuccess(t, out, err)
+	assertNotContains(t, out, "build-step-output")
+	assertContains(t, out, "Full start output")
+
+	logPath := filepath.Join(env.root, "worktrees", "feature-quiet-start", ".worktree-start.log")
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("start log not written: %v", err)
+	}
+	assertContains(t, string(data), "==> backend:")
+	assertContains(t, string(data), "build-step-output")
+
+	t.Run("verbose streams output", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/loud-start", "--verbose")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "build-step-output")
+	})
+
+	t.Run("failure shows log tail", func(t *testing.T) {
+		t.Setenv("FAIL_START", "1")
+		out, err := env.run("new-feature", "feature/failed-start")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Failed to start backend")
+		assertContains(t, out, "build-step-output")
+	})
+}
@@ -0,0 +1,101 @@
+package system_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStashUnstash covers stashing a feature's changes across projects and
+// rebasing a dirty feature with --autostash.
+func TestStashUnstash(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	out, err := env.run("new-feature", "feature/s")
+	assertSuccess(t, out, err)
+	out, err = env.run("new-feature", "feature/t")
+	assertSuccess(t, out, err)
+
+	backendWT := filepath.Join(env.root, "worktrees", "feature-s", "backend")
+	otherWT := filepath.Join(env.root, "worktrees", "feature-t", "backend")
+	notes := filepath.Join(backendWT, "notes.txt")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	isClean := func(dir string) bool {
+		t.Helper()
+		out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+		if err != nil {
+			t.Fatalf("git status in %s: %v", dir, err)
+		}
+		return strings.TrimSpace(string(out)) == ""
+	}
+
+	t.Run("stash and unstash", func(t *testing.T) {
+		writeFile(notes, "wip\n")
+		writeFile(filepath.Join(otherWT, "other.txt"), "other\n")
+
+		out, err := env.run("stash", "feature-s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend changes stashed")
+		assertNotContains(t, out, "frontend changes stashed")
+		if !isClean(backendWT) {
+			t.Error("backend worktree still dirty after stash")
+		}
+
+		// The other feature shares the stash list; its stash must not be popped
+		out, err = env.run("stash", "feature-t")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("unstash", "feature-s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend changes restored")
+		if _, err := os.Stat(notes); err != nil {
+			t.Errorf("notes.txt not restored: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(backendWT, "other.txt")); err == nil {
+			t.Error("feature-t stash was popped into feature-s")
+		}
+
+		out, err = env.run("unstash", "feature-s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "No stashed changes for feature-s")
+	})
+
+	t.Run("rebase refuses dirty worktree", func(t *testing.T) {
+		out, err := env.run("rebase", "feature-s")
+		assertFailure(t, err)
+		assertContains(t, out, "Cannot rebase with uncommitted changes")
+	})
+
+	t.Run("rebase --autostash", func(t *testing.T) {
+		mainDir := filepath.Join(env.root, "backend")
+		writeFile(filepath.Join(mainDir, "main.txt"), "main\n")
+		env.gitRun(mainDir, "add", "main.txt")
+		env.gitRun(mainDir, "commit", "-m", "main change")
+		env.gitRun(mainDir, "push", "origin", "main")
+
+		out, err := env.run("rebase", "feature-s", "--autostash")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "backend changes stashed")
+		assertContains(t, out, "backend changes restored")
+		if _, err := os.Stat(filepath.Join(backendWT, "main.txt")); err != nil {
+			t.Errorf("main.txt missing after rebase: %v", err)
+		}
+		data, err := os.ReadFile(notes)
+		if err != nil || string(data) != "wip\n" {
+			t.Errorf("notes.txt after autostash = %q, %v; want restored", data, err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSubmodules verifies that projects with submodules: true get their
+// submodules checked out in new worktrees and that doctor reports worktrees
+// with uninitialized submodules.
+func TestSubmodules(t *testing.T) {
+	// Local file:// submodules are disabled by default in recent git
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+
+	env := newTestEnv(t)
+	env.gitInitProject("lib")
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	libDir := filepath.Join(env.root, "lib")
+	if err := os.WriteFile(filepath.Join(libDir, "lib.txt"), []byte("lib\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env.gitRun(libDir, "add", "lib.txt")
+	env.gitRun(libDir, "commit", "-m", "lib")
+
+	for _, project := range []string{"backend", "frontend"} {
+		dir := filepath.Join(env.root, project)
+		env.gitRun(dir, "submodule", "add", libDir, "vendor/lib")
+		env.gitRun(dir, "commit", "-m", "add lib submodule")
+	}
+
+	env.writeConfig(strings.Replace(worktreeConfig(), `    dir: "backend"
+    main_branch: "main"
+`, `    dir: "backend"
+    main_branch: "main"
+    submodules: true
+`, 1))
+
+	out, err := env.run("new-feature", "feature/sub")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Initialized backend submodules")
+	assertNotContains(t, out, "Initialized frontend submodules")
+
+	featureDir := filepath.Join(env.root, "worktrees", "feature-sub")
+	if _, err := os.Stat(filepath.Join(featureDir, "backend", "vendor", "lib", "lib.txt")); err != nil {
+		t.Errorf("backend submodule not checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(featureDir, "frontend", "vendor", "lib", "lib.txt")); err == nil {
+		t.Error("frontend submodule checked out without submodules: true")
+	}
+
+	out, _ = env.run("doctor", "--no-fetch")
+	assertContains(t, out, "feature-sub/frontend: 1 uninitialized submodules (vendor/lib)")
+	assertContains(t, out, "Set 'submodules: true' on project frontend")
+	assertNotContains(t, out, "feature-sub/backend:")
+}
@@ -0,0 +1,119 @@
+package system_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSync covers fetch + fast-forward, ahead/behind reporting and --push
+// across the project worktrees of a feature.
+func TestSync(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	backendRemote := env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	out, err := env.run("new-feature", "feature/s")
+	assertSuccess(t, out, err)
+
+	backendWorktree := filepath.Join(env.root, "worktrees", "feature-s", "backend")
+	env.gitRun(backendWorktree, "push", "origin", "feature/s")
+
+	// A teammate pushes to the backend feature branch
+	clone := filepath.Join(env.root, "clone")
+	env.gitRun(env.root, "clone", "-b", "feature/s", backendRemote, clone)
+	env.gitRun(clone, "-c", "user.email=t@example.com", "-c", "user.name=Teammate", "commit", "--allow-empty", "-m", "teammate")
+	env.gitRun(clone, "push", "origin", "feature/s")
+
+	head := func(dir string) string {
+		t.Helper()
+		out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatalf("git rev-parse in %s: %v", dir, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	t.Run("fast-forward and report", func(t *testing.T) {
+		out, err := env.run("sync", "feature/s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Fast-forwarded 1 commits from origin/feature/s")
+		assertContains(t, out, "feature/s is not on origin yet (push with --push)")
+		if head(backendWorktree) != head(clone) {
+			t.Error("backend worktree not fast-forwarded to the teammate's commit")
+		}
+	})
+
+	t.Run("ahead", func(t *testing.T) {
+		env.gitRun(backendWorktree, "commit", "--allow-empty", "-m", "local")
+
+		out, err := env.run("sync", "feature-s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "1 commits ahead of origin/feature/s (push with --push)")
+	})
+
+	t.Run("push", func(t *testing.T) {
+		out, err := env.run("sync", "feature-s", "--push")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Pushed feature/s to origin")
+
+		out, err = env.run("sync", "feature-s")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Up to date with origin/feature/s")
+		assertNotContains(t, out, "not on origin")
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		env.gitRun(clone, "pull", "--ff-only", "origin", "feature/s")
+		env.gitRun(clone, "-c", "user.email=t@example.com", "-c", "user.name=Teammate", "commit", "--allow-empty", "-m", "teammate 2")
+		env.gitRun(clone, "push", "origin", "feature/s")
+		env.gitRun(backendWorktree, "commit", "--allow-empty", "-m", "local 2")
+
+		out, err := env.run("sync", "feature-s", "--push")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Diverged from origin/feature/s: 1 ahead, 1 behind")
+	})
+}
+
+// TestSyncFetchTTL verifies that fetch_ttl skips fetching a repository that
+// was fetched recently, so new remote commits are only seen after the TTL.
+func TestSyncFetchTTL(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + "fetch_ttl: 1h\n")
+
+	backendRemote := env.gitAddOrigin("backend")
+
+	out, err := env.run("new-feature", "feature/ttl")
+	assertSuccess(t, out, err)
+
+	backendWorktree := filepath.Join(env.root, "worktrees", "feature-ttl", "backend")
+	env.gitRun(backendWorktree, "push", "origin", "feature/ttl")
+
+	out, err = env.run("sync", "feature-ttl")
+	assertSuccess(t, out, err)
+	assertNotContains(t, out, "fetched recently")
+
+	// A teammate pushes after the fetch
+	clone := filepath.Join(env.root, "clone")
+	env.gitRun(env.root, "clone", "-b", "feature/ttl", backendRemote, clone)
+	env.gitRun(clone, "-c", "user.email=t@example.com", "-c", "user.name=Teammate", "commit", "--allow-empty", "-m", "teammate")
+	env.gitRun(clone, "push", "origin", "feature/ttl")
+
+	out, err = env.run("sync", "feature-ttl")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "origin was fetched recently, not fetching again")
+	assertContains(t, out, "Up to date with origin/feature/ttl")
+
+	// Without a TTL every invocation fetches
+	env.writeConfig(worktreeConfig())
+	out, err = env.run("sync", "feature-ttl")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Fast-forwarded 1 commits from origin/feature/ttl")
+}
@@ -0,0 +1,94 @@
+package system_test
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// registeredPorts returns the ports of a feature from the registry file
+func registeredPorts(t *testing.T, env *TestEnv, feature string) map[string]int {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reg struct {
+		Worktrees map[string]struct {
+			Ports map[string]int `json:"ports"`
+		} `json:"worktrees"`
+	}
+	if err := json.Unmarshal(data, &reg); err != nil {
+		t.Fatal(err)
+	}
+	return reg.Worktrees[feature].Ports
+}
+
+// TestNewFeatureTakeover verifies that --takeover recreates a feature on the
+// ports of the feature it replaces.
+func TestNewFeatureTakeover(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+	env.writeMockBinary("docker")
+
+	for _, branch := range []string{"feature/first", "feature/second"} {
+		out, err := env.run("new-feature", branch, "--no-start")
+		assertSuccess(t, out, err)
+	}
+	before := registeredPorts(t, env, "feature-second")
+
+	t.Run("without takeover", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/second", "--no-start")
+		assertFailure(t, err)
+		assertContains(t, out, "--takeover")
+	})
+
+	t.Run("unregistered feature", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/unknown", "--no-start", "--takeover")
+		assertFailure(t, err)
+		assertContains(t, out, "nothing to take over")
+	})
+
+	t.Run("uncommitted changes", func(t *testing.T) {
+		dirty := filepath.Join(env.root, "worktrees", "feature-second", "backend", "dirty.txt")
+		if err := os.WriteFile(dirty, []byte("wip\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := env.run("new-feature", "feature/second", "--no-start", "--takeover")
+		assertFailure(t, err)
+		assertContains(t, out, "uncommitted changes")
+		if err := os.Remove(dirty); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	ln, err := net.Listen("tcp", ":9201")
+	if err != nil {
+		t.Skipf("port 9201 not available: %v", err)
+	}
+	defer ln.Close()
+
+	t.Run("port used by another process", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/second", "--no-start", "--takeover")
+		assertFailure(t, err)
+		assertContains(t, out, "in use on this host")
+	})
+
+	t.Run("port held by the replaced containers", func(t *testing.T) {
+		env.writeMockBinary("docker", `echo "0.0.0.0:9201->3000/tcp"`)
+		out, err := env.run("new-feature", "feature/second", "--no-start", "--takeover")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Ports of the previous feature-second reused")
+		assertContains(t, out, "Removed backend worktree")
+		assertContains(t, out, "Created backend worktree")
+
+		after := registeredPorts(t, env, "feature-second")
+		if after["APP_PORT"] != before["APP_PORT"] || after["FE_PORT"] != before["FE_PORT"] {
+			t.Errorf("ports changed: before %v, after %v", before, after)
+		}
+	})
+}
@@ -39,6 +39,9 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
+	// The sandbox has no container runtime; preflight_test.go re-enables the checks
+	os.Setenv("WORKTREE_SKIP_PREFLIGHT", "1")
+
 	os.Exit(m.Run())
 }
 
@@ -100,6 +103,18 @@ func (e *TestEnv) gitInitProject(relDir string) {
 	e.gitRun(dir, "commit", "--allow-empty", "-m", "initial commit")
 }
 
+// gitAddOrigin creates a bare repository under env.root/remotes, adds it as the
+// "origin" remote of the project in relDir and pushes main to it. Returns the
+// remote's path.
+func (e *TestEnv) gitAddOrigin(relDir string) string {
+	e.t.Helper()
+	remote := filepath.Join(e.root, "remotes", relDir+".git")
+	e.gitRun(e.root, "init", "--bare", "-b", "main", remote)
+	e.gitRun(filepath.Join(e.root, relDir), "remote", "add", "origin", remote)
+	e.gitRun(filepath.Join(e.root, relDir), "push", "origin", "main")
+	return remote
+}
+
 func (e *TestEnv) gitRun(dir string, args ...string) {
 	e.t.Helper()
 	cmd := exec.Command("git", args...)
@@ -140,6 +155,18 @@ func (e *TestEnv) writeMockClaude(extraLines ...string) {
 	}
 }
 
+// writeMockBinary writes an executable shell script named name to env.binDir.
+// The script runs the given lines and exits 0, shadowing the real binary on PATH.
+func (e *TestEnv) writeMockBinary(name string, lines ...string) {
+	e.t.Helper()
+	script := "#!/bin/bash\n" + strings.Join(lines, "\n") + "\nexit 0\n"
+
+	path := filepath.Join(e.binDir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		e.t.Fatalf("write mock %s: %v", name, err)
+	}
+}
+
 // run invokes the worktree binary from env.root with the given arguments.
 // It returns combined stdout+stderr and the command error (nil on exit 0).
 func (e *TestEnv) run(args ...string) (string, error) {
@@ -0,0 +1,92 @@
+package system_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUpdateMerge covers the merge strategy of update: clean merges,
+// per-project conflict reporting and the config default.
+func TestUpdateMerge(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + "update_strategy: merge\n")
+	env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	out, err := env.run("new-feature", "feature/m")
+	assertSuccess(t, out, err)
+
+	writeAndCommit := func(dir, file, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(dir, "add", file)
+		env.gitRun(dir, "commit", "-m", "change "+file)
+	}
+	isMergeCommit := func(dir string) bool {
+		out, err := exec.Command("git", "-C", dir, "rev-list", "--parents", "-n", "1", "HEAD").Output()
+		return err == nil && len(strings.Fields(string(out))) == 3
+	}
+
+	backendWT := filepath.Join(env.root, "worktrees", "feature-m", "backend")
+	frontendWT := filepath.Join(env.root, "worktrees", "feature-m", "frontend")
+	writeAndCommit(backendWT, "shared.txt", "feature\n")
+	writeAndCommit(frontendWT, "ui.txt", "feature\n")
+	for _, project := range []string{"backend", "frontend"} {
+		dir := filepath.Join(env.root, project)
+		writeAndCommit(dir, "shared.txt", "main\n")
+		env.gitRun(dir, "push", "origin", "main")
+	}
+
+	t.Run("conflicts reported per project", func(t *testing.T) {
+		out, err := env.run("update", "feature-m")
+		assertFailure(t, err)
+		assertContains(t, out, "Updating Feature: feature-m (merge)")
+		assertContains(t, out, "frontend merged successfully")
+		assertContains(t, out, "Merge conflicts in: backend")
+		assertContains(t, out, "- shared.txt")
+		if !isMergeCommit(frontendWT) {
+			t.Error("frontend not merged despite the backend conflict")
+		}
+	})
+
+	t.Run("unfinished merge blocks", func(t *testing.T) {
+		out, err := env.run("update", "feature-m")
+		assertFailure(t, err)
+		assertContains(t, out, "backend")
+		assertContains(t, out, "merge in progress")
+	})
+
+	t.Run("clean after resolving", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(backendWT, "shared.txt"), []byte("resolved\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		env.gitRun(backendWT, "add", "shared.txt")
+		env.gitRun(backendWT, "commit", "--no-edit")
+
+		out, err := env.run("update", "feature-m")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Update completed successfully")
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		out, err := env.run("update", "feature-m", "--strategy", "squash")
+		assertFailure(t, err)
+		assertContains(t, out, "unknown strategy 'squash'")
+	})
+
+	t.Run("rebase strategy", func(t *testing.T) {
+		out, err := env.run("new-feature", "feature/n")
+		assertSuccess(t, out, err)
+
+		out, err = env.run("update", "feature-n", "--strategy", "rebase")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Rebasing Feature: feature-n")
+	})
+}
@@ -0,0 +1,171 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWatchOnce verifies that watch --once re-renders generated files after a
+// config edit and leaves unchanged files alone.
+func TestWatchOnce(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/watch-test")
+	assertSuccess(t, out, err)
+
+	envFile := filepath.Join(env.root, "worktrees", "feature-watch-test", "backend", ".env.local")
+	withTemplate := func(template string) string {
+		return worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "` + template + `"
+`
+	}
+
+	t.Run("config edit regenerates file", func(t *testing.T) {
+		env.writeConfig(withTemplate("API_PORT={APP_PORT}"))
+
+		out, err := env.run("watch", "feature-watch-test", "--once")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Updated backend/.env.local")
+
+		data, err := os.ReadFile(envFile)
+		if err != nil {
+			t.Fatalf("generated file missing: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "API_PORT=90") {
+			t.Errorf("unexpected content %q", data)
+		}
+	})
+
+	t.Run("unchanged config writes nothing", func(t *testing.T) {
+		out, err := env.run("watch", "feature-watch-test", "--once")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "is up to date")
+	})
+
+	t.Run("auto-detect from worktree", func(t *testing.T) {
+		env.writeConfig(withTemplate("FEATURE={FEATURE_NAME}"))
+
+		out, err := env.runFrom(filepath.Dir(envFile), "watch", "--once")
+		assertSuccess(t, out, err)
+		assertContains(t, out, "Updated backend/.env.local")
+
+		data, _ := os.ReadFile(envFile)
+		if string(data) != "FEATURE=feature-watch-test" {
+			t.Errorf("unexpected content %q", data)
+		}
+	})
+
+	t.Run("go template engine", func(t *testing.T) {
+		env.writeConfig(worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      engine: go
+      template: "{{ if .YOLO }}yolo{{ else }}safe{{ end }} {{ add .APP_PORT 1 }}"
+`)
+
+		out, err := env.run("watch", "feature-watch-test", "--once")
+		assertSuccess(t, out, err)
+
+		data, _ := os.ReadFile(envFile)
+		if !strings.HasPrefix(string(data), "safe 90") {
+			t.Errorf("unexpected content %q", data)
+		}
+	})
+
+	t.Run("unknown feature", func(t *testing.T) {
+		out, err := env.run("watch", "no-such-feature", "--once")
+		assertFailure(t, err)
+		assertContains(t, out, "not found")
+	})
+}
+
+// TestSyncUndo verifies that sync --undo reverts the files the last
+// regeneration rewrote.
+func TestSyncUndo(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "V=1"
+`)
+
+	out, err := env.run("new-feature", "feature/undo")
+	assertSuccess(t, out, err)
+
+	envFile := filepath.Join(env.root, "worktrees", "feature-undo", "backend", ".env.local")
+	env.writeConfig(worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "V=2"
+`)
+
+	out, err = env.run("watch", "feature-undo", "--once")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Previous versions saved to .backup/")
+	if data, _ := os.ReadFile(envFile); string(data) != "V=2" {
+		t.Fatalf("regenerated content %q", data)
+	}
+
+	out, err = env.run("sync", "feature-undo", "--undo")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Reverted backend/.env.local")
+	if data, _ := os.ReadFile(envFile); string(data) != "V=1" {
+		t.Errorf("content after undo %q, want V=1", data)
+	}
+
+	out, err = env.run("sync", "feature-undo", "--undo")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "nothing to undo")
+}
+
+// TestRegen verifies that regen re-renders generated files of an existing
+// feature with its registry ports after a template edit.
+func TestRegen(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig())
+
+	out, err := env.run("new-feature", "feature/regen-test")
+	assertSuccess(t, out, err)
+
+	env.writeConfig(worktreeConfig() + `
+generated_files:
+  backend:
+    - path: ".env.local"
+      template: "API_PORT={APP_PORT} FEATURE={FEATURE_NAME}"
+`)
+
+	out, err = env.run("regen", "feature/regen-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Updated backend/.env.local")
+
+	data, err := os.ReadFile(filepath.Join(env.root, "worktrees", "feature-regen-test", "backend", ".env.local"))
+	if err != nil {
+		t.Fatalf("generated file missing: %v", err)
+	}
+	if string(data) != "API_PORT=9090 FEATURE=feature-regen-test" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	out, err = env.run("regen", "feature-regen-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "is up to date")
+
+	out, err = env.run("regen", "feature-missing")
+	assertFailure(t, err)
+	assertContains(t, out, "not found")
+}
@@ -0,0 +1,74 @@
+package system_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestWebhookEvents verifies that configured webhooks receive signed JSON
+// events when a feature is created and removed.
+func TestWebhookEvents(t *testing.T) {
+	type delivery struct {
+		event     string
+		signature string
+		body      map[string]interface{}
+		raw       []byte
+	}
+	var mu sync.Mutex
+	var received []delivery
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		_ = json.Unmarshal(raw, &body)
+		mu.Lock()
+		received = append(received, delivery{r.Header.Get("X-Worktree-Event"), r.Header.Get("X-Worktree-Signature"), body, raw})
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `
+webhooks:
+  - url: "` + server.URL + `/events"
+    secret: "hook-secret"
+  - url: "` + server.URL + `/dns"
+    events: [removed]
+`)
+
+	out, err := env.run("new-feature", "feature/hook", "--no-start")
+	assertSuccess(t, out, err)
+	out, err = env.run("remove", "feature-hook", "--force")
+	assertSuccess(t, out, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var events []string
+	for _, d := range received {
+		events = append(events, d.event)
+	}
+	if len(received) != 3 || events[0] != "created" || events[1] != "removed" || events[2] != "removed" {
+		t.Fatalf("events = %v, want [created removed removed]", events)
+	}
+
+	created := received[0]
+	if created.body["feature"] != "feature-hook" || created.body["branch"] != "feature/hook" || created.body["project"] != "testproject" {
+		t.Errorf("created body = %s", created.raw)
+	}
+	mac := hmac.New(sha256.New, []byte("hook-secret"))
+	mac.Write(created.raw)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); created.signature != want {
+		t.Errorf("signature = %q, want %q", created.signature, want)
+	}
+	if received[2].signature != "" {
+		t.Errorf("webhook without a secret got signature %q", received[2].signature)
+	}
+}
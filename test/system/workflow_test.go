@@ -1,8 +1,15 @@
 package system_test
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 )
@@ -122,7 +129,7 @@ func TestAgentRunWithSafetyGates(t *testing.T) {
 
 	assertSuccess(t, out, err)
 	assertContains(t, out, "Always passes")
-	assertContains(t, out, "✅ Passed")
+	assertContains(t, out, "[ok] Passed")
 	assertContains(t, out, "Always fails optional")
 	assertContains(t, out, "Failed (optional")
 	assertContains(t, out, "Safety Gates Summary:")
@@ -162,7 +169,7 @@ func TestAgentRunRequiredGateFails(t *testing.T) {
 	t.Logf("output:\n%s", out)
 
 	assertFailure(t, err)
-	assertContains(t, out, "❌ Failed (required)")
+	assertContains(t, out, "[err] Failed (required)")
 	assertContains(t, out, "Required safety gates failed")
 }
 
@@ -279,3 +286,759 @@ scheduled_agents:
 	assertContains(t, out, "hello from worktree dir")
 	assertContains(t, out, "completed successfully")
 }
+
+// TestAgentRunIsolatedEnvironment validates that environment.isolated drops
+// unlisted host variables, keeps pass-through ones and adds computed vars.
+func TestAgentRunIsolatedEnvironment(t *testing.T) {
+	env := newTestEnv(t)
+	t.Setenv("WT_TEST_LEAK", "leaked")
+	t.Setenv("WT_TEST_KEEP_ME", "kept")
+
+	env.writeConfig(minimalConfig(`  isolated-test:
+    name: "Isolated Env Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      instance: 3
+    environment:
+      isolated: true
+      pass_through: ["WT_TEST_KEEP_*"]
+      cache_dir: ".agent-cache"
+    steps:
+      - name: "Print env"
+        type: shell
+        command: "echo \"leak=${WT_TEST_LEAK:-none} keep=$WT_TEST_KEEP_ME instance=$INSTANCE cache=$(basename $XDG_CACHE_HOME)\""
+    safety:
+      gates:
+        - name: "Gate sees isolated env"
+          command: "test -z \"$WT_TEST_LEAK\""
+          required: true
+      git:
+        push:
+          enabled: false
+`))
+
+	out, err := env.run("agent", "run", "isolated-test")
+	t.Logf("output:\n%s", out)
+
+	assertSuccess(t, out, err)
+	assertContains(t, out, "leak=none keep=kept instance=3 cache=.agent-cache")
+	assertContains(t, out, "completed successfully")
+
+	if _, err := os.Stat(filepath.Join(env.root, ".agent-cache")); err != nil {
+		t.Errorf("cache dir not created: %v", err)
+	}
+}
+
+// TestAgentRunPipelineStep validates that pipeline steps run their script from
+// .worktree/steps with arguments, and that validate checks the script exists.
+func TestAgentRunPipelineStep(t *testing.T) {
+	env := newTestEnv(t)
+
+	stepsDir := filepath.Join(env.root, ".worktree", "steps")
+	if err := os.MkdirAll(stepsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "echo \"greet $1 from $(basename \"$PWD\")\"\n"
+	if err := os.WriteFile(filepath.Join(stepsDir, "greet.sh"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	env.writeConfig(minimalConfig(`  pipeline-test:
+    name: "Pipeline Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+      instance: 4
+    steps:
+      - name: "Greet"
+        type: pipeline
+        pipeline: greet
+        pipeline_args: ["world"]
+    safety:
+      git:
+        branch: "automated/pipeline"
+        commit_message: "chore: pipeline"
+        push:
+          enabled: false
+  missing-pipeline:
+    name: "Missing Pipeline Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Missing"
+        type: pipeline
+        pipeline: nope
+    safety:
+      git:
+        branch: "automated/missing"
+        commit_message: "chore: missing"
+`))
+
+	out, err := env.run("agent", "run", "pipeline-test")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Pipeline: .worktree/steps/greet.sh world")
+	assertContains(t, out, "greet world from "+filepath.Base(env.root))
+
+	out, err = env.run("agent", "validate", "pipeline-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Step 1: Greet (pipeline: greet)")
+
+	out, err = env.run("agent", "show", "pipeline-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, `Runs: bash .worktree/steps/greet.sh "world"`)
+
+	out, err = env.run("agent", "validate", "missing-pipeline")
+	assertFailure(t, err)
+	assertContains(t, out, "pipeline 'nope' not found (expected .worktree/steps/nope.sh); available: greet")
+
+	out, err = env.run("agent", "run", "missing-pipeline")
+	assertFailure(t, err)
+	assertContains(t, out, "pipeline 'nope' not found")
+}
+
+// TestAgentRunGSDPhases validates that a GSD task runs its phases one claude
+// session each, records progress, and resumes at the phase whose gates failed.
+func TestAgentRunGSDPhases(t *testing.T) {
+	env := newTestEnv(t)
+
+	claudeLog := filepath.Join(env.root, "claude.log")
+	env.writeMockBinary("claude", `printf '%s\n' "$@" >> "`+claudeLog+`"`)
+	marker := filepath.Join(env.root, "ready")
+
+	env.writeConfig(minimalConfig(`  gsd-test:
+    name: "GSD Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    gsd:
+      enabled: true
+      milestone: "v2"
+      auto_execute: true
+      phases:
+        - name: research
+          prompt: "Research the API"
+        - name: implement
+          prompt: "Implement the client"
+          gates:
+            - name: "Ready"
+              command: "test -f ` + marker + `"
+              required: true
+        - name: verify
+          prompt: "Verify the client"
+    safety:
+      git:
+        branch: "automated/gsd"
+        commit_message: "chore: gsd"
+`))
+
+	out, err := env.run("agent", "validate", "gsd-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "GSD phases: 3 configured")
+
+	out, err = env.run("agent", "run", "gsd-test")
+	t.Logf("first run:\n%s", out)
+	assertFailure(t, err)
+	assertContains(t, out, "phase 'implement' failed")
+
+	out, err = env.run("agent", "gsd", "status", "gsd-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Status: failed (1/3 phases completed)")
+	assertContains(t, out, "Failed gates: Ready")
+
+	data, err := os.ReadFile(claudeLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "/gsd:new-milestone"); n != 1 {
+		t.Errorf("new-milestone ran %d times, want 1:\n%s", n, data)
+	}
+
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(claudeLog); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.run("agent", "run", "gsd-test")
+	t.Logf("second run:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Resuming GSD milestone 'v2' at phase 2/3 (implement)")
+
+	data, err = os.ReadFile(claudeLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := string(data)
+	assertNotContains(t, log, "/gsd:new-milestone")
+	assertNotContains(t, log, "Research the API")
+	assertContains(t, log, `/gsd:plan-phase "Implement the client"`)
+	assertContains(t, log, `/gsd:plan-phase "Verify the client"`)
+
+	out, err = env.run("agent", "gsd", "status")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Status: completed (3/3 phases completed)")
+
+	out, err = env.run("agent", "gsd", "reset", "gsd-test")
+	assertSuccess(t, out, err)
+	out, err = env.run("agent", "gsd", "status", "gsd-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "No GSD progress recorded for 'gsd-test'")
+}
+
+// TestAgentRunDockerStep validates that docker steps exec into the service
+// container of the run's feature under its compose project.
+func TestAgentRunDockerStep(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+
+	dockerLog := filepath.Join(env.root, "docker.log")
+	env.writeMockBinary("docker",
+		`echo "$@ pwd=$(basename "$PWD") port=$APP_PORT" >> "`+dockerLog+`"`,
+	)
+
+	env.writeConfig(worktreeConfig() + `
+scheduled_agents:
+  db-migrate:
+    name: "Migrate"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Run migrations"
+        type: docker
+        project: backend
+        service: api
+        command: "make migrate"
+        working_dir: /app
+    safety:
+      git:
+        branch: "automated/migrate"
+        commit_message: "chore: migrate"
+  bad-docker:
+    name: "Bad Docker"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "No service"
+        type: docker
+        project: backend
+        command: "true"
+    safety:
+      git:
+        branch: "automated/bad"
+        commit_message: "chore: bad"
+`)
+
+	out, err := env.run("new-feature", "feature/dock", "--no-start")
+	assertSuccess(t, out, err)
+
+	out, err = env.run("agent", "validate", "db-migrate")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Step 1: Run migrations (docker: backend/api)")
+
+	out, err = env.run("agent", "show", "db-migrate")
+	assertSuccess(t, out, err)
+	assertContains(t, out, `Runs: docker compose -p <feature compose project> exec -T --workdir /app api sh -c "make migrate"`)
+
+	out, err = env.run("agent", "run", "db-migrate")
+	assertFailure(t, err)
+	assertContains(t, out, "worktree agent run db-migrate --worktree <feature>")
+
+	os.Remove(dockerLog)
+	out, err = env.run("agent", "run", "db-migrate", "--worktree", "feature/dock")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Container: api (compose project testproject-feature-dock)")
+	data, err := os.ReadFile(dockerLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, string(data), "compose -p testproject-feature-dock exec -T --workdir /app api sh -c make migrate pwd=backend port=9090")
+
+	out, err = env.run("agent", "validate", "bad-docker")
+	assertFailure(t, err)
+	assertContains(t, out, "Step 1 (No service): service is empty")
+}
+
+// TestAgentRunTaskEnv validates that task and step env maps reach shell steps
+// and safety gates, with placeholders and secret references resolved.
+func TestAgentRunTaskEnv(t *testing.T) {
+	env := newTestEnv(t)
+	if err := os.WriteFile(filepath.Join(env.root, "token.txt"), []byte("tok-123\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env.writeConfig(minimalConfig(`  env-test:
+    name: "Env Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+      instance: 5
+    env:
+      API_TOKEN: "file:token.txt"
+      RUN_LABEL: "run-{instance}"
+    steps:
+      - name: "Print env"
+        type: shell
+        command: "echo \"token=$API_TOKEN label=$RUN_LABEL mode=$MODE\""
+        env:
+          MODE: "strict"
+      - name: "Print step env again"
+        type: shell
+        command: "echo \"second mode=[$MODE]\""
+    safety:
+      gates:
+        - name: "Token gate"
+          command: "test -n \"$API_TOKEN\""
+          required: true
+      git:
+        branch: "automated/env"
+        commit_message: "chore: env"
+`))
+
+	out, err := env.run("agent", "run", "env-test")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "token=tok-123 label=run-5 mode=strict")
+	assertContains(t, out, "second mode=[]")
+	assertContains(t, out, "Passed: 1")
+
+	out, err = env.run("agent", "show", "env-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "API_TOKEN=file:token.txt (secret)")
+	assertContains(t, out, "Env of step 'Print env':")
+	assertNotContains(t, out, "tok-123")
+}
+
+// TestAgentRunHTTPStep validates that http steps send their expanded request,
+// check the response status and capture a JSON field for later steps.
+func TestAgentRunHTTPStep(t *testing.T) {
+	var gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/deploys" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"deploy":{"id":"d-42"}}`)
+	}))
+	defer server.Close()
+
+	env := newTestEnv(t)
+	env.writeConfig(minimalConfig(fmt.Sprintf(`  deploy:
+    name: "Deploy Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+      instance: 3
+    env:
+      DEPLOY_TOKEN: "tok-{instance}"
+      DEPLOY_ENV: "staging"
+    steps:
+      - name: "Trigger deploy"
+        type: http
+        method: POST
+        url: "%s/deploys"
+        headers:
+          Authorization: "Bearer ${DEPLOY_TOKEN}"
+        body: '{"env":"${DEPLOY_ENV}"}'
+        expect_status: 201
+        capture: DEPLOY_ID
+        capture_json: deploy.id
+      - name: "Use deploy"
+        type: shell
+        command: "echo \"deploy=$DEPLOY_ID\""
+    safety:
+      git:
+        branch: "automated/deploy"
+        commit_message: "chore: deploy"
+`, server.URL)))
+
+	out, err := env.run("agent", "validate", "deploy")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "(http: POST "+server.URL+"/deploys)")
+
+	out, err = env.run("agent", "run", "deploy")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Response: 201 Created")
+	assertContains(t, out, "Captured: DEPLOY_ID")
+	assertContains(t, out, "deploy=d-42")
+	if gotAuth != "Bearer tok-3" {
+		t.Errorf("Authorization header = %q, want %q", gotAuth, "Bearer tok-3")
+	}
+	if gotBody != `{"env":"staging"}` {
+		t.Errorf("request body = %q", gotBody)
+	}
+
+	env.writeConfig(minimalConfig(fmt.Sprintf(`  deploy:
+    name: "Deploy Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Missing endpoint"
+        type: http
+        url: "%s/missing"
+    safety:
+      git:
+        branch: "automated/deploy"
+        commit_message: "chore: deploy"
+`, server.URL)))
+
+	out, err = env.run("agent", "run", "deploy")
+	assertFailure(t, err)
+	assertContains(t, out, "unexpected status 400 Bad Request")
+}
+
+// TestAgentRunStepTemplates validates that "use:" steps expand to the steps of
+// a step_templates entry with their parameters substituted.
+func TestAgentRunStepTemplates(t *testing.T) {
+	env := newTestEnv(t)
+
+	env.writeConfig(minimalConfig(`  templated:
+    name: "Templated Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Own step"
+        type: shell
+        command: "echo own-step"
+      - use: greet
+        with:
+          who: backend
+      - use: greet
+        with:
+          who: frontend
+    safety:
+      git:
+        branch: "automated/templated"
+        commit_message: "chore: templated"
+
+step_templates:
+  greet:
+    - name: "Greet {who}"
+      type: shell
+      command: "echo hello-{who}"
+`))
+
+	out, err := env.run("agent", "run", "templated")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "[1/3] Own step")
+	assertContains(t, out, "[2/3] Greet backend")
+	assertContains(t, out, "hello-backend")
+	assertContains(t, out, "[3/3] Greet frontend")
+	assertContains(t, out, "hello-frontend")
+
+	out, err = env.run("agent", "show", "templated")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "From template: greet")
+}
+
+// TestAgentRunStepPolicy validates step retries, timeouts and
+// continue_on_error, and that each step's outcome is recorded in history.
+func TestAgentRunStepPolicy(t *testing.T) {
+	env := newTestEnv(t)
+	counter := filepath.Join(env.root, "attempts")
+
+	env.writeConfig(minimalConfig(fmt.Sprintf(`  policy-test:
+    name: "Policy Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+    steps:
+      - name: "Flaky"
+        type: shell
+        command: "echo x >> %s; test $(wc -l < %s) -ge 2"
+        retries: 2
+        retry_delay: 10ms
+      - name: "Slow"
+        type: shell
+        command: "sleep 30"
+        timeout: 200ms
+        continue_on_error: true
+      - name: "After"
+        type: shell
+        command: "echo after-ran"
+    safety:
+      git:
+        branch: "automated/policy"
+        commit_message: "chore: policy"
+`, counter, counter)))
+
+	out, err := env.run("agent", "show", "policy-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Policy: 2 retries (first after 10ms, doubling)")
+	assertContains(t, out, "Policy: timeout 200ms, continue on error")
+
+	out, err = env.run("agent", "run", "policy-test")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Attempt 1 of 3 failed")
+	assertNotContains(t, out, "Attempt 2 of 3 failed")
+	assertContains(t, out, "Step 'Slow' failed, continuing (continue_on_error): timed out after 200ms")
+	assertContains(t, out, "after-ran")
+
+	data, err := os.ReadFile(filepath.Join(env.root, "worktrees", ".history.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h struct {
+		Records []struct {
+			ID     string `json:"id"`
+			Status string `json:"status"`
+			Steps  []struct {
+				Name      string `json:"name"`
+				Status    string `json:"status"`
+				Attempts  int    `json:"attempts"`
+				Continued bool   `json:"continued"`
+				Output    string `json:"output"`
+			} `json:"steps"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Records) != 1 || h.Records[0].Status != "completed" || len(h.Records[0].Steps) != 3 {
+		t.Fatalf("history = %s", data)
+	}
+	steps := h.Records[0].Steps
+	if steps[0].Status != "completed" || steps[0].Attempts != 2 {
+		t.Errorf("flaky step = %+v", steps[0])
+	}
+	if steps[1].Status != "timed out" || !steps[1].Continued {
+		t.Errorf("slow step = %+v", steps[1])
+	}
+	if steps[2].Status != "completed" || steps[2].Attempts != 1 || steps[2].Output != "after-ran\n" {
+		t.Errorf("after step = %+v", steps[2])
+	}
+
+	out, err = env.run("agent", "history", "show", h.Records[0].ID)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "1. Flaky (shell): completed")
+	assertContains(t, out, ", 2 attempts")
+	assertContains(t, out, "2. Slow (shell): timed out")
+	assertContains(t, out, "Error: timed out after 200ms")
+	assertContains(t, out, "      Output:\n        after-ran\n")
+
+	out, err = env.run("agent", "history", "export", "--since", "1d")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "id,agent_name,status,trigger,")
+	assertContains(t, out, h.Records[0].ID+",policy-test,completed,manual,")
+	out, err = env.run("agent", "history", "export", "--format", "json", "--status", "failed")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "[]")
+
+	out, err = env.run("agent", "validate", "policy-test")
+	assertSuccess(t, out, err)
+}
+
+func TestAgentRunIsolatedWorktree(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	backendRemote := env.gitAddOrigin("backend")
+	frontendRemote := env.gitAddOrigin("frontend")
+
+	env.writeConfig(minimalConfig(`  isolated-test:
+    name: "Isolated Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+      isolated_worktree: true
+    steps:
+      - name: "Change backend"
+        type: shell
+        command: "echo update > backend/agent.txt && echo run-dir=$FEATURE_DIR"
+      - name: "Inspect frontend"
+        type: shell
+        working_dir: frontend
+        command: "echo frontend-branch=$(git rev-parse --abbrev-ref HEAD)"
+    safety:
+      gates:
+        - name: "Change present"
+          command: "test -f backend/agent.txt"
+          required: true
+      git:
+        branch: "automated/isolated"
+        commit_message: "chore: isolated update"
+        push:
+          enabled: true
+`))
+
+	out, err := env.run("agent", "show", "isolated-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Isolated worktree: on")
+
+	out, err = env.run("agent", "run", "isolated-test")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "run-dir="+filepath.Join(env.root, "worktrees", ".agent-runs", "isolated-test-"))
+	assertContains(t, out, "frontend-branch=automated/isolated")
+	assertContains(t, out, "Pushed to origin/automated/isolated")
+	assertContains(t, out, "Agent worktree removed")
+
+	// The developer's checkouts are untouched
+	if _, err := os.Stat(filepath.Join(env.root, "backend", "agent.txt")); !os.IsNotExist(err) {
+		t.Errorf("agent.txt was written to the project root checkout (err=%v)", err)
+	}
+	for _, project := range []string{"backend", "frontend"} {
+		branch, err := exec.Command("git", "-C", filepath.Join(env.root, project), "rev-parse", "--abbrev-ref", "HEAD").Output()
+		if err != nil || strings.TrimSpace(string(branch)) != "main" {
+			t.Errorf("%s checkout is on %q (err=%v), want main", project, branch, err)
+		}
+	}
+
+	// Only the backend had changes: its branch is pushed and kept, the
+	// frontend's is deleted with the worktree
+	if err := exec.Command("git", "-C", backendRemote, "rev-parse", "--verify", "automated/isolated").Run(); err != nil {
+		t.Errorf("automated/isolated was not pushed to the backend remote: %v", err)
+	}
+	if err := exec.Command("git", "-C", frontendRemote, "rev-parse", "--verify", "automated/isolated").Run(); err == nil {
+		t.Error("automated/isolated was pushed to the frontend remote without changes")
+	}
+	if err := exec.Command("git", "-C", filepath.Join(env.root, "frontend"), "rev-parse", "--verify", "refs/heads/automated/isolated").Run(); err == nil {
+		t.Error("unpushed run branch was kept in frontend")
+	}
+
+	entries, err := os.ReadDir(filepath.Join(env.root, "worktrees", ".agent-runs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("agent run worktree was not removed: %v", entries)
+	}
+}
+
+// TestAgentRunGitLabMergeRequest verifies that forge: gitlab opens a merge
+// request with glab, with the title templated and the target branch set, and
+// that its URL is recorded in the execution history.
+func TestAgentRunGitLabMergeRequest(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.gitAddOrigin("backend")
+	env.gitAddOrigin("frontend")
+
+	argsFile := filepath.Join(env.root, "glab-args.txt")
+	env.writeMockBinary("glab",
+		`printf '%s\n' "$@" > `+argsFile,
+		`echo "Creating merge request for automated/mr into main in group/backend"`,
+		`echo "https://gitlab.example.com/group/backend/-/merge_requests/7"`,
+	)
+
+	env.writeConfig(minimalConfig(`  mr-test:
+    name: "MR Agent"
+    schedule: "0 9 * * MON"
+    context:
+      preset: default
+      branch: main
+      isolated_worktree: true
+    steps:
+      - name: "Change backend"
+        type: shell
+        command: "echo update > backend/agent.txt"
+    safety:
+      gates:
+        - name: "Change present"
+          command: "test -f backend/agent.txt"
+          required: true
+      git:
+        branch: "automated/mr"
+        commit_message: "chore: mr update"
+        forge: gitlab
+        push:
+          enabled: true
+          create_pr: true
+          pr_title: "Update from {task} into {target}"
+          pr_body: "Gates: {gates}"
+`))
+
+	out, err := env.run("agent", "show", "mr-test")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Forge: gitlab")
+	assertContains(t, out, "Target branch: main")
+
+	out, err = env.run("agent", "run", "mr-test")
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, "Creating merge request (automated/mr → main)")
+	assertContains(t, out, "Merge request created: https://gitlab.example.com/group/backend/-/merge_requests/7")
+
+	args, err := os.ReadFile(argsFile)
+	if err != nil {
+		t.Fatalf("glab was not called: %v", err)
+	}
+	for _, want := range []string{
+		"mr\ncreate\n",
+		"--title\nUpdate from mr-test into main\n",
+		"--description\nGates: All 1 safety gates passed\n",
+		"--source-branch\nautomated/mr\n",
+		"--target-branch\nmain\n",
+	} {
+		assertContains(t, string(args), want)
+	}
+
+	out, err = env.run("agent", "history", "list", "--format", "wide")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "PR/MR: https://gitlab.example.com/group/backend/-/merge_requests/7")
+
+	// The structured log records the steps, gates and git operations
+	id := regexp.MustCompile(`ID: ([0-9a-f]{8})`).FindStringSubmatch(out)
+	if id == nil {
+		t.Fatalf("no execution ID in history list:\n%s", out)
+	}
+	out, err = env.run("agent", "history", "show", id[1])
+	t.Logf("output:\n%s", out)
+	assertSuccess(t, out, err)
+	assertContains(t, out, filepath.Join(env.root, "worktrees", ".agent-logs")+string(filepath.Separator))
+	assertContains(t, out, "Timeline")
+	assertContains(t, out, "gate  Change present: passed")
+	assertContains(t, out, "git   merge request: completed")
+
+	out, err = env.run("agent", "history", "show", id[1], "--json")
+	assertSuccess(t, out, err)
+	var log struct {
+		Status string `json:"status"`
+		Events []struct {
+			Phase   string            `json:"phase"`
+			Name    string            `json:"name"`
+			Status  string            `json:"status"`
+			Details map[string]string `json:"details"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("history show --json is not a log: %v\n%s", err, out)
+	}
+	var phases []string
+	for _, event := range log.Events {
+		phases = append(phases, event.Phase+"/"+event.Name+"/"+event.Status)
+	}
+	got := strings.Join(phases, ", ")
+	want := "run/MR Agent/started, step/Change backend/completed, gate/Change present/passed, " +
+		"git/commit/completed, git/push/completed, git/merge request/completed, git/commit/skipped, run/MR Agent/completed"
+	if got != want {
+		t.Errorf("log events = %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,54 @@
+package system_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWorkspace verifies that new-feature generates the configured IDE files
+// with the feature's ports and that regen rewrites them.
+func TestWorkspace(t *testing.T) {
+	env := newTestEnv(t)
+	env.gitInitProject("backend")
+	env.gitInitProject("frontend")
+	env.writeConfig(worktreeConfig() + `
+workspace:
+  vscode: true
+  jetbrains: true
+  launch:
+    - name: "API"
+      project: backend
+      type: go
+      program: "cmd/server"
+      command: "go run ./cmd/server"
+`)
+
+	out, err := env.run("new-feature", "feature/ide", "--no-start")
+	assertSuccess(t, out, err)
+	assertContains(t, out, "IDE configuration created (feature-ide.code-workspace)")
+
+	featureDir := filepath.Join(env.root, "worktrees", "feature-ide")
+	read := func(rel string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(featureDir, rel))
+		if err != nil {
+			t.Fatalf("%s not written: %v", rel, err)
+		}
+		return string(data)
+	}
+
+	workspace := read("feature-ide.code-workspace")
+	assertContains(t, workspace, `"path": "frontend"`)
+	assertContains(t, workspace, `"program": "${workspaceFolder:backend}/cmd/server"`)
+	assertContains(t, workspace, `"APP_PORT": "9090"`)
+	assertContains(t, read("backend/.run/API.run.xml"), `<env name="FE_PORT" value="9200"></env>`)
+
+	// regen restores a deleted workspace
+	if err := os.Remove(filepath.Join(featureDir, "feature-ide.code-workspace")); err != nil {
+		t.Fatal(err)
+	}
+	out, err = env.run("regen", "feature-ide")
+	assertSuccess(t, out, err)
+	assertContains(t, read("feature-ide.code-workspace"), `"name": "API"`)
+}